@@ -1,12 +1,17 @@
 package main
 
 import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
 	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/security"
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,20 +22,36 @@ func main() {
 	// CLI flags
 	addr := flag.String("addr", "bootstrap:4000", "Address of the Koorde node (entry point)")
 	timeout := flag.Duration("timeout", 5*time.Second, "Request timeout (e.g., 5s)")
+	maxRecv := flag.Int("max-recv-bytes", 0, "Largest response accepted, for Get of large values (0 = gRPC default, 4 MiB)")
+	idleTTL := flag.Duration("conn-idle", 5*time.Minute, "Close the connections to nodes not used for this long (0 = never)")
+	maxWalk := flag.Int("max-walk", client.DefaultMaxRingWalk, "Maximum number of successor hops of a ring walk (ownership, shards, topology)")
+	var sec security.Config
+	flag.StringVar(&sec.Mode, "tls-mode", security.ModeNone, "Transport security: none, tls or mtls")
+	flag.StringVar(&sec.CAFile, "tls-ca", "", "PEM CA bundle used to verify the node (empty = system roots)")
+	flag.StringVar(&sec.CertFile, "tls-cert", "", "PEM client certificate (required by mtls)")
+	flag.StringVar(&sec.KeyFile, "tls-key", "", "PEM private key of the client certificate (required by mtls)")
 	flag.Parse()
 
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 
+	tlsConfig, err := security.ClientTLS(sec)
+	if err != nil {
+		log.Fatalf("Invalid TLS configuration: %v", err)
+	}
+
+	// Connections are pooled: "use" switching back to a node reuses its connection
+	conns := client.NewManager(*idleTTL, client.WithTLS(tlsConfig), client.WithMaxRecvMsgSize(*maxRecv))
+	defer conns.Close()
+
 	// Connect to initial node
-	api, conn, err := client.Connect(*addr)
+	api, err := conns.API(*addr)
 	if err != nil {
 		log.Fatalf("Failed to connect to node at %s: %v", *addr, err)
 	}
-	defer conn.Close()
 
 	currentAddr := *addr
 	fmt.Printf("Koorde interactive client. Connected to %s\n", currentAddr)
-	fmt.Println("Available commands: put/get/delete/getstore/getrt/lookup/use/exit")
+	fmt.Println("Available commands: put/putc/putnx/cas/get/delete/delrange/mput/mget/mdel/getstore/getrt/info/space/getconfig/pause/rebalance/shutdown/lookup/trace/placement/ownership/shards/topology/use/exit")
 
 	// Setup liner shell
 	line := liner.NewLiner()
@@ -54,32 +75,116 @@ func main() {
 		}
 		cmd := args[0]
 
+		// the connection may have been closed after being idle: the manager dials it again
+		if api, err = conns.API(currentAddr); err != nil {
+			fmt.Printf("Failed to connect to %s: %v\n", currentAddr, err)
+			continue
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 
 		switch cmd {
 
 		case "put":
+			args, rawHex := rawKeyFlag(args)
 			if len(args) < 3 {
-				fmt.Println("Usage: put <key> <value>")
+				fmt.Println("Usage: put [--rawkey-hex] <key> <value> [ttlSeconds]")
 				cancel()
 				continue
 			}
 			key, value := args[1], args[2]
-			delay, err := client.Put(ctx, api, key, value)
+			var ttl time.Duration
+			if len(args) > 3 {
+				secs, err := strconv.ParseUint(args[3], 10, 32)
+				if err != nil {
+					fmt.Printf("Invalid TTL %q: must be a number of seconds\n", args[3])
+					cancel()
+					continue
+				}
+				ttl = time.Duration(secs) * time.Second
+			}
+			put := client.PutTTL
+			if rawHex {
+				put = client.PutID
+			}
+			delay, err := put(ctx, api, key, value, ttl)
 			if err != nil {
 				fmt.Printf("Put failed (%v) | latency=%s\n", err, delay)
 			} else {
 				fmt.Printf("Put succeeded (key=%s, value=%s) | latency=%s\n", key, value, delay)
 			}
 
+		case "putc":
+			if len(args) < 2 || len(args) > 3 {
+				fmt.Println("Usage: putc <value> [ttlSeconds]")
+				cancel()
+				continue
+			}
+			value := args[1]
+			var ttl time.Duration
+			if len(args) > 2 {
+				secs, err := strconv.ParseUint(args[2], 10, 32)
+				if err != nil {
+					fmt.Printf("Invalid TTL %q: must be a number of seconds\n", args[2])
+					cancel()
+					continue
+				}
+				ttl = time.Duration(secs) * time.Second
+			}
+			key, delay, err := client.PutContent(ctx, api, value, ttl)
+			if err != nil {
+				fmt.Printf("Put failed (%v) | latency=%s\n", err, delay)
+			} else {
+				fmt.Printf("Put succeeded (key=%s, value=%s) | latency=%s\n", key, value, delay)
+			}
+
+		case "putnx":
+			if len(args) != 3 {
+				fmt.Println("Usage: putnx <key> <value>")
+				cancel()
+				continue
+			}
+			key, value := args[1], args[2]
+			delay, err := client.PutIfAbsent(ctx, api, key, value, 0)
+			switch {
+			case errors.Is(err, client.ErrPreconditionFailed):
+				fmt.Printf("Put not applied: key %s already exists | latency=%s\n", key, delay)
+			case err != nil:
+				fmt.Printf("Put failed (%v) | latency=%s\n", err, delay)
+			default:
+				fmt.Printf("Put succeeded (key=%s, value=%s) | latency=%s\n", key, value, delay)
+			}
+
+		case "cas":
+			if len(args) != 4 {
+				fmt.Println("Usage: cas <key> <old> <new>")
+				cancel()
+				continue
+			}
+			key, old, value := args[1], args[2], args[3]
+			delay, err := client.CompareAndSwap(ctx, api, key, old, value, 0)
+			switch {
+			case errors.Is(err, client.ErrPreconditionFailed):
+				fmt.Printf("Swap not applied: key %s does not hold %s | latency=%s\n", key, old, delay)
+			case err != nil:
+				fmt.Printf("Swap failed (%v) | latency=%s\n", err, delay)
+			default:
+				fmt.Printf("Swap succeeded (key=%s, %s -> %s) | latency=%s\n", key, old, value, delay)
+			}
+
 		case "get":
+			args, rawHex := rawKeyFlag(args)
 			if len(args) < 2 {
-				fmt.Println("Usage: get <key>")
+				fmt.Println("Usage: get [--rawkey-hex] <key>")
 				cancel()
 				continue
 			}
 			key := args[1]
-			val, delay, err := client.Get(ctx, api, key)
+			get := client.Get
+			if rawHex {
+				get = client.GetID
+			}
+			val, delay, err := get(ctx, api, key)
 			switch err {
 			case nil:
 				fmt.Printf("Get succeeded (key=%s, value=%s) | latency=%s\n", key, val, delay)
@@ -90,13 +195,18 @@ func main() {
 			}
 
 		case "delete":
+			args, rawHex := rawKeyFlag(args)
 			if len(args) < 2 {
-				fmt.Println("Usage: delete <key>")
+				fmt.Println("Usage: delete [--rawkey-hex] <key>")
 				cancel()
 				continue
 			}
 			key := args[1]
-			delay, err := client.Delete(ctx, api, key)
+			del := client.Delete
+			if rawHex {
+				del = client.DeleteID
+			}
+			delay, err := del(ctx, api, key)
 			switch err {
 			case nil:
 				fmt.Printf("Delete succeeded (key=%s) | latency=%s\n", key, delay)
@@ -106,8 +216,130 @@ func main() {
 				fmt.Printf("Delete failed: %v | latency=%s\n", err, delay)
 			}
 
+		case "delrange":
+			if len(args) < 3 {
+				fmt.Println("Usage: delrange <fromId> <toId> (keys with ID in (from, to], from == to = all)")
+				cancel()
+				continue
+			}
+			deleted, delay, err := client.DeleteRange(ctx, api, args[1], args[2])
+			if err != nil {
+				fmt.Printf("DeleteRange failed: %v | latency=%s\n", err, delay)
+			} else {
+				fmt.Printf("DeleteRange: %d resources deleted | latency=%s\n", deleted, delay)
+			}
+
+		case "mput":
+			if len(args) < 2 {
+				fmt.Println("Usage: mput <key>=<value> [key=value...]")
+				cancel()
+				continue
+			}
+			items := make([]client.PutItem, 0, len(args)-1)
+			for _, kv := range args[1:] {
+				key, value, ok := strings.Cut(kv, "=")
+				if !ok {
+					fmt.Printf("Invalid pair %q: expected <key>=<value>\n", kv)
+					items = nil
+					break
+				}
+				items = append(items, client.PutItem{Key: key, Value: value})
+			}
+			if items == nil {
+				cancel()
+				continue
+			}
+			stored, failed, delay, err := client.BatchPut(ctx, api, items)
+			if err != nil {
+				fmt.Printf("BatchPut failed: %v | latency=%s\n", err, delay)
+				cancel()
+				continue
+			}
+			for k, ferr := range failed {
+				fmt.Printf("  failed    %s: %v\n", k, ferr)
+			}
+			fmt.Printf("BatchPut: %d stored, %d failed | latency=%s\n", stored, len(failed), delay)
+
+		case "mget":
+			if len(args) < 2 {
+				fmt.Println("Usage: mget <key> [key...]")
+				cancel()
+				continue
+			}
+			results, delay, err := client.BatchGet(ctx, api, args[1:])
+			if err != nil {
+				fmt.Printf("BatchGet failed: %v | latency=%s\n", err, delay)
+				cancel()
+				continue
+			}
+			found := 0
+			for _, k := range args[1:] {
+				r, ok := results[k]
+				if !ok {
+					continue
+				}
+				delete(results, k) // duplicate keys are printed once
+				switch {
+				case r.Err == nil:
+					found++
+					fmt.Printf("  %s = %s\n", k, r.Value)
+				case errors.Is(r.Err, client.ErrNotFound):
+					fmt.Printf("  not found %s\n", k)
+				default:
+					fmt.Printf("  failed    %s: %v\n", k, r.Err)
+				}
+			}
+			fmt.Printf("BatchGet: %d found | latency=%s\n", found, delay)
+
+		case "mdel":
+			if len(args) < 2 {
+				fmt.Println("Usage: mdel <key> [key...]")
+				cancel()
+				continue
+			}
+			results, delay, err := client.BatchDelete(ctx, api, args[1:])
+			deleted, missing := 0, 0
+			for _, r := range results {
+				switch {
+				case r.Err == nil:
+					deleted++
+					fmt.Printf("  deleted   %s\n", r.Key)
+				case errors.Is(r.Err, client.ErrNotFound):
+					missing++
+					fmt.Printf("  not found %s\n", r.Key)
+				default:
+					fmt.Printf("  failed    %s: %v\n", r.Key, r.Err)
+				}
+			}
+			if err != nil {
+				fmt.Printf("BatchDelete failed after %d of %d keys: %v | latency=%s\n", len(results), len(args)-1, err, delay)
+			} else {
+				fmt.Printf("BatchDelete: %d deleted, %d not found, %d failed | latency=%s\n",
+					deleted, missing, len(results)-deleted-missing, delay)
+			}
+
 		case "getstore":
-			resources, delay, err := client.GetStore(ctx, api)
+			// Usage: getstore [--limit n] [--after token] [--prefix p]
+			fs := flag.NewFlagSet("getstore", flag.ContinueOnError)
+			limit := fs.Int("limit", 0, "Resources per page (0 = node default)")
+			after := fs.String("after", "", "Token of the page to start from (the next token printed by the previous page)")
+			prefix := fs.String("prefix", "", "Only keys starting with this prefix")
+			if err := fs.Parse(args[1:]); err != nil {
+				cancel()
+				continue
+			}
+			var (
+				resources []*clientv1.Resource
+				next      string
+				delay     time.Duration
+				err       error
+			)
+			paged := *limit > 0 || *after != "" || *prefix != ""
+			if paged {
+				resources, next, delay, err = client.GetStorePage(ctx, api, *prefix, *after, *limit)
+			} else {
+				resources, delay, err = client.GetStore(ctx, api)
+			}
 			if err != nil {
 				fmt.Printf("GetStore failed: %v | latency=%s\n", err, delay)
 				cancel()
@@ -115,7 +347,19 @@ func main() {
 			}
 			fmt.Printf("Stored resources (count=%d) | latency=%s\n", len(resources), delay)
 			for _, r := range resources {
-				fmt.Printf("  - key=%s | value=%s\n", r.Key, r.Value)
+				line := fmt.Sprintf("  - key=%s | value=%s", r.Key, r.Value)
+				if r.Origin != "" {
+					line += " | origin=" + r.Origin
+				}
+				if len(r.Metadata) > 0 {
+					line += fmt.Sprintf(" | metadata=%v", r.Metadata)
+				}
+				fmt.Println(line)
+			}
+			if next != "" {
+				fmt.Printf("Next page token: %s (pass it to --after)\n", next)
+			} else if paged {
+				fmt.Println("Last page")
 			}
 
 		case "getrt":
@@ -125,23 +369,129 @@ func main() {
 				cancel()
 				continue
 			}
-			fmt.Println("Routing table:")
-			if rt.Self != nil {
-				fmt.Printf("  Self: %s (%s)\n", rt.Self.Id, rt.Self.Addr)
+			printRoutingTable("Routing table:", rt)
+			for i, v := range rt.Vnodes {
+				printRoutingTable(fmt.Sprintf("Virtual node %d:", i+1), v)
+			}
+			fmt.Printf("Latency: %s\n", delay)
+
+		case "info":
+			info, delay, err := client.Info(ctx, api)
+			if err != nil {
+				fmt.Printf("Info failed: %v | latency=%s\n", err, delay)
+				cancel()
+				continue
+			}
+			fmt.Println("Node info:")
+			if info.Self != nil {
+				fmt.Printf("  Self: %s (%s)\n", info.Self.Id, info.Self.Addr)
 			}
-			if rt.Predecessor != nil {
-				fmt.Printf("  Predecessor: %s (%s)\n", rt.Predecessor.Id, rt.Predecessor.Addr)
+			if info.Predecessor != nil {
+				fmt.Printf("  Predecessor: %s (%s)\n", info.Predecessor.Id, info.Predecessor.Addr)
+			} else {
+				fmt.Println("  Predecessor: unknown")
 			}
-			fmt.Println("  Successors:")
-			for i, s := range rt.Successors {
-				fmt.Printf("    [%d] %s (%s)\n", i, s.Id, s.Addr)
+			fmt.Printf("  Successors: %d\n", info.SuccessorCount)
+			fmt.Printf("  DeBruijn: %d/%d\n", info.DeBruijnFilled, info.DeBruijnDegree)
+			if info.MaxKeys > 0 {
+				fmt.Printf("  Stored keys: %d/%d\n", info.StoredKeys, info.MaxKeys)
+			} else {
+				fmt.Printf("  Stored keys: %d (unlimited)\n", info.StoredKeys)
 			}
-			fmt.Println("  DeBruijn List:")
-			for i, d := range rt.DeBruijnList {
-				fmt.Printf("    [%d] %s (%s)\n", i, d.Id, d.Addr)
+			fmt.Printf("  Uptime: %s\n", (time.Duration(info.UptimeMs) * time.Millisecond).String())
+			fmt.Printf("  Pooled connections: %d\n", info.PooledConnections)
+			if info.MaxOutbound > 0 {
+				fmt.Printf("  Outbound RPCs in flight: %d/%d\n", info.OutboundInFlight, info.MaxOutbound)
+			} else {
+				fmt.Printf("  Outbound RPCs in flight: %d (unlimited)\n", info.OutboundInFlight)
 			}
 			fmt.Printf("Latency: %s\n", delay)
 
+		case "space":
+			sp, delay, err := client.GetSpace(ctx, api)
+			if err != nil {
+				fmt.Printf("GetSpace failed: %v | latency=%s\n", err, delay)
+				cancel()
+				continue
+			}
+			fmt.Println("Identifier space:")
+			fmt.Printf("  ID bits: %d\n", sp.Bits)
+			fmt.Printf("  DeBruijn degree: %d\n", sp.GraphGrade)
+			fmt.Printf("  Successor list size: %d\n", sp.SuccListSize)
+			fmt.Printf("  Hash: %s\n", sp.Hash)
+			if sp.Namespace != "" {
+				fmt.Printf("  Namespace: %s\n", sp.Namespace)
+			}
+			fmt.Printf("Latency: %s\n", delay)
+
+		case "getconfig":
+			entries, delay, err := client.GetConfig(ctx, api)
+			if err != nil {
+				fmt.Printf("GetConfig failed: %v | latency=%s\n", err, delay)
+				cancel()
+				continue
+			}
+			fmt.Printf("Configuration (entries=%d) | latency=%s\n", len(entries), delay)
+			for _, e := range entries {
+				fmt.Printf("  %s = %s\n", e.Key, e.Value)
+			}
+
+		case "pause":
+			if len(args) < 2 {
+				fmt.Println("Usage: pause <duration|0> [detect]")
+				cancel()
+				continue
+			}
+			d, err := time.ParseDuration(args[1])
+			if err != nil || d < 0 {
+				fmt.Printf("Invalid duration %q (e.g. 30s, 5m; 0 resumes now)\n", args[1])
+				cancel()
+				continue
+			}
+			detect := len(args) > 2 && args[2] == "detect"
+			until, delay, err := client.PauseStabilization(ctx, api, d, detect)
+			switch {
+			case err != nil:
+				fmt.Printf("PauseStabilization failed: %v | latency=%s\n", err, delay)
+			case until.IsZero():
+				fmt.Printf("Stabilization resumed | latency=%s\n", delay)
+			default:
+				fmt.Printf("Stabilization paused until %s (failure detection=%t) | latency=%s\n",
+					until.Format(time.RFC3339), detect, delay)
+			}
+
+		case "rebalance":
+			transferred, failed, delay, err := client.Rebalance(ctx, api)
+			if err != nil {
+				fmt.Printf("Rebalance failed: %v | latency=%s\n", err, delay)
+			} else {
+				fmt.Printf("Rebalance done (transferred=%d, failed=%d) | latency=%s\n", transferred, failed, delay)
+			}
+
+		case "shutdown":
+			handoff := 30 * time.Second
+			if len(args) > 1 {
+				d, err := time.ParseDuration(args[1])
+				if err != nil || d <= 0 {
+					fmt.Printf("Invalid timeout %q (e.g. 30s, 2m)\n", args[1])
+					cancel()
+					continue
+				}
+				handoff = d
+			}
+			// the reply arrives only after the handoff
+			cancel()
+			ctx, cancel = context.WithTimeout(context.Background(), handoff+*timeout)
+			completed, detail, delay, err := client.Shutdown(ctx, api, handoff)
+			switch {
+			case err != nil:
+				fmt.Printf("Shutdown failed: %v | latency=%s\n", err, delay)
+			case completed:
+				fmt.Printf("Node left the ring and is stopping | latency=%s\n", delay)
+			default:
+				fmt.Printf("Node is stopping, handoff incomplete: %s | latency=%s\n", detail, delay)
+			}
+
 		case "lookup":
 			if len(args) < 2 {
 				fmt.Println("Usage: lookup <id>")
@@ -157,6 +507,162 @@ func main() {
 					node.Id, node.Addr, delay)
 			}
 
+		case "trace":
+			if len(args) < 2 {
+				fmt.Println("Usage: trace <id>")
+				cancel()
+				continue
+			}
+			resp, delay, err := client.LookupTrace(ctx, api, args[1])
+			if err != nil {
+				fmt.Printf("Trace failed: %v | latency=%s\n", err, delay)
+			} else {
+				fmt.Printf("Trace result: successor=%s (%s) | hops=%d | latency=%s\n",
+					resp.Successor.GetId(), resp.Successor.GetAddr(), resp.Hops, delay)
+				for i, nd := range resp.Path {
+					fmt.Printf("  %2d. %s (%s)\n", i, nd.GetId(), nd.GetAddr())
+				}
+			}
+
+		case "placement":
+			if len(args) < 2 {
+				fmt.Println("Usage: placement <id>")
+				cancel()
+				continue
+			}
+			resp, delay, err := client.LookupOwnership(ctx, api, args[1])
+			if err != nil {
+				fmt.Printf("Placement failed: %v | latency=%s\n", err, delay)
+			} else {
+				fmt.Printf("Placement result: successor=%s (%s) | latency=%s\n",
+					resp.Successor.GetId(), resp.Successor.GetAddr(), delay)
+				if resp.Predecessor != nil {
+					fmt.Printf("  owned range: (%s, %s] (predecessor %s)\n",
+						resp.Predecessor.GetId(), resp.Successor.GetId(), resp.Predecessor.GetAddr())
+				} else {
+					fmt.Println("  owned range: unknown (no predecessor)")
+				}
+				if len(resp.Replicas) == 0 {
+					fmt.Println("  replicas: none")
+				}
+				for i, nd := range resp.Replicas {
+					fmt.Printf("  replica %d: %s (%s)\n", i+1, nd.GetId(), nd.GetAddr())
+				}
+			}
+
+		case "ownership":
+			// Usage: ownership [json] [bits]
+			asJSON, bits := false, 0
+			for _, a := range args[1:] {
+				if a == "json" {
+					asJSON = true
+				} else if b, convErr := strconv.Atoi(a); convErr == nil {
+					bits = b
+				}
+			}
+			om, err := client.OwnershipMap(ctx, currentAddr, bits, client.WithMaxRingWalk(*maxWalk))
+			if err != nil {
+				fmt.Printf("Ownership map failed: %v\n", err)
+				cancel()
+				continue
+			}
+			if asJSON {
+				out, _ := json.MarshalIndent(om, "", "  ")
+				fmt.Println(string(out))
+				cancel()
+				continue
+			}
+			fmt.Printf("Keyspace ownership (%d nodes, 2^%d IDs, %d keys):\n", len(om.Intervals), om.Bits, om.TotalKeys)
+			fmt.Printf("  %-24s %-24s %-21s %8s %6s\n", "start (excl)", "end (incl)", "owner", "share", "keys")
+			for _, iv := range om.Intervals {
+				fmt.Printf("  %-24s %-24s %-21s %7.3f%% %6d\n", iv.Start, iv.End, iv.Owner, iv.Share*100, iv.Keys)
+			}
+			fmt.Printf("Share min=%.3f%% max=%.3f%% imbalance=%.2f\n", om.MinShare*100, om.MaxShare*100, om.Imbalance)
+			if om.Truncated {
+				fmt.Printf("Ring walk truncated after %d steps: the map is partial\n", *maxWalk)
+			}
+
+		case "shards":
+			// Usage: shards <count> [json] [bits]
+			if len(args) < 2 {
+				fmt.Println("Usage: shards <count> [json] [bits]")
+				cancel()
+				continue
+			}
+			count, convErr := strconv.Atoi(args[1])
+			if convErr != nil {
+				fmt.Printf("Invalid shard count %q\n", args[1])
+				cancel()
+				continue
+			}
+			asJSON, bits := false, 0
+			for _, a := range args[2:] {
+				if a == "json" {
+					asJSON = true
+				} else if b, convErr := strconv.Atoi(a); convErr == nil {
+					bits = b
+				}
+			}
+			plan, err := client.ShardPlan(ctx, currentAddr, bits, count, client.WithMaxRingWalk(*maxWalk))
+			if err != nil {
+				fmt.Printf("Shard plan failed: %v\n", err)
+				cancel()
+				continue
+			}
+			if asJSON {
+				out, _ := json.MarshalIndent(plan, "", "  ")
+				fmt.Println(string(out))
+				cancel()
+				continue
+			}
+			fmt.Printf("Shard plan (%d shards, 2^%d IDs, version %s):\n", len(plan.Shards), plan.Bits, plan.Version)
+			fmt.Printf("  %6s %-24s %-24s %-21s %s\n", "shard", "start", "end", "owner", "exact")
+			for _, sh := range plan.Shards {
+				fmt.Printf("  %6d %-24s %-24s %-21s %t\n", sh.Index, sh.Start, sh.End, sh.Owner, sh.Exact)
+			}
+
+		case "topology":
+			// Usage: topology [--format csv|json] [--out file]
+			fs := flag.NewFlagSet("topology", flag.ContinueOnError)
+			format := fs.String("format", client.FormatCSV, "Output format: csv or json")
+			out := fs.String("out", "", "File to write the topology to (empty = standard output)")
+			if err := fs.Parse(args[1:]); err != nil {
+				cancel()
+				continue
+			}
+			topo, err := client.RingTopology(ctx, currentAddr, client.WithMaxRingWalk(*maxWalk))
+			if err != nil {
+				fmt.Printf("Topology failed: %v\n", err)
+				cancel()
+				continue
+			}
+			if *out == "" {
+				err = topo.Write(os.Stdout, *format)
+			} else {
+				var f *os.File
+				if f, err = os.Create(*out); err == nil {
+					err = errors.Join(topo.Write(f, *format), f.Close())
+				}
+			}
+			if err != nil {
+				fmt.Printf("Topology export failed: %v\n", err)
+				cancel()
+				continue
+			}
+			if *out != "" {
+				fmt.Printf("Topology of %d nodes written to %s\n", len(topo.Nodes), *out)
+			}
+			for _, nd := range topo.Nodes {
+				for _, p := range nd.Unreachable {
+					fmt.Printf("Ring break: %s (%s) cannot reach its successor %s (%s)\n", nd.ID, nd.Addr, p.ID, p.Addr)
+				}
+			}
+			if topo.Truncated {
+				fmt.Printf("Ring walk truncated after %d steps: the topology is partial\n", *maxWalk)
+			} else if !topo.Complete {
+				fmt.Println("Ring walk did not return to the entry node: the ring is broken")
+			}
+
 		case "use":
 			if len(args) < 2 {
 				fmt.Println("Usage: use <addr>")
@@ -164,15 +670,13 @@ func main() {
 				continue
 			}
 			newAddr := args[1]
-			newClient, newConn, err := client.Connect(newAddr)
+			newClient, err := conns.API(newAddr)
 			if err != nil {
 				fmt.Printf("Failed to connect to %s: %v\n", newAddr, err)
 				cancel()
 				continue
 			}
-			conn.Close()
 			api = newClient
-			conn = newConn
 			currentAddr = newAddr
 			fmt.Printf("Switched connection to %s\n", currentAddr)
 
@@ -188,3 +692,33 @@ func main() {
 		cancel()
 	}
 }
+
+// printRoutingTable prints a routing table returned by GetRoutingTable
+// under the given title.
+func printRoutingTable(title string, rt *clientv1.GetRoutingTableResponse) {
+	fmt.Println(title)
+	if rt.Self != nil {
+		fmt.Printf("  Self: %s (%s)\n", rt.Self.Id, rt.Self.Addr)
+	}
+	if rt.Predecessor != nil {
+		fmt.Printf("  Predecessor: %s (%s)\n", rt.Predecessor.Id, rt.Predecessor.Addr)
+	}
+	fmt.Println("  Successors:")
+	for i, s := range rt.Successors {
+		fmt.Printf("    [%d] %s (%s)\n", i, s.Id, s.Addr)
+	}
+	fmt.Println("  DeBruijn List:")
+	for i, d := range rt.DeBruijnList {
+		fmt.Printf("    [%d] %s (%s)\n", i, d.Id, d.Addr)
+	}
+}
+
+// rawKeyFlag removes the --rawkey-hex flag following the command name, if
+// present: the key is then a pre-hashed ID (hex digest) used as is instead
+// of being hashed by the node.
+func rawKeyFlag(args []string) ([]string, bool) {
+	if len(args) > 1 && args[1] == "--rawkey-hex" {
+		return append([]string{args[0]}, args[2:]...), true
+	}
+	return args, false
+}
@@ -7,13 +7,19 @@ import (
 	zapfactory "KoordeDHT/internal/logger/zap"
 	client2 "KoordeDHT/internal/node/client"
 	"KoordeDHT/internal/node/config"
+	"KoordeDHT/internal/node/identity"
 	logicnode2 "KoordeDHT/internal/node/logicnode"
 	routingtable2 "KoordeDHT/internal/node/routingtable"
 	server2 "KoordeDHT/internal/node/server"
 	"KoordeDHT/internal/node/storage"
 	"KoordeDHT/internal/node/telemetry"
+	"KoordeDHT/internal/node/telemetry/nodemetrics"
+	"KoordeDHT/internal/security"
+	"KoordeDHT/internal/telemetry/metrics"
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
@@ -23,13 +29,14 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 var defaultConfigPath = "config/node/config.yaml"
 
 func main() {
 	// Parse command-line flags
-	configPath := flag.String("config", defaultConfigPath, "path to configuration file")
+	configPath := flag.String("config", defaultConfigPath, "path or URI (file://, http://, https://) of the configuration file")
 	flag.Parse()
 
 	// Load configuration
@@ -43,23 +50,27 @@ func main() {
 		log.Fatalf("invalid configuration: %v", err)
 	}
 
-	// Initialize logger
-	var lgr logger.Logger
-	if cfg.Logger.Active {
-		zapLog, err := zapfactory.New(cfg.Logger)
+	// Initialize logger (and the audit logger used by the access log)
+	var lgr logger.Logger = &logger.NopLogger{} // no-op logger
+	var auditLgr logger.Logger                  // nil = access log disabled
+	if cfg.Logger.Active || cfg.Logger.AccessLog {
+		zapLog, zapAudit, err := zapfactory.NewWithAudit(cfg.Logger)
 		if err != nil {
 			log.Fatalf("failed to initialize logger: %v", err)
 		}
-		defer func() { _ = zapLog.Sync() }()   // flush logger buffers before exit
-		lgr = zapfactory.NewZapAdapter(zapLog) // adapt zap.Logger to logger.Interface
-	} else {
-		lgr = &logger.NopLogger{} // no-op logger
+		defer func() { _ = zapLog.Sync() }() // flush logger buffers before exit
+		if cfg.Logger.Active {
+			lgr = zapfactory.NewZapAdapter(zapLog) // adapt zap.Logger to logger.Interface
+		}
+		if cfg.Logger.AccessLog {
+			auditLgr = zapfactory.NewZapAdapter(zapAudit)
+		}
 	}
 	// Log loaded configuration at DEBUG level
 	cfg.LogConfig(lgr) // log loaded configuration at DEBUG level
 
 	// Initialize listener (to determine server address and port)
-	lis, advertised, err := server2.Listen(cfg.DHT.Mode, cfg.Node.Bind, cfg.Node.Host, cfg.Node.Port)
+	lis, advertised, err := server2.Listen(cfg.DHT.Mode, cfg.Node.IPVersion, cfg.Node.Bind, cfg.Node.Host, cfg.Node.Port)
 	if err != nil {
 		lgr.Error("Fatal: failed to initialize listener", logger.F("err", err))
 		os.Exit(1)
@@ -69,17 +80,45 @@ func main() {
 	lgr.Debug("create listener", logger.F("BindAddr", addr), logger.F("AdvertisedAddr", advertised))
 
 	// Initialize the identifier space
-	space, err := domain.NewSpace(cfg.DHT.IDBits, cfg.DHT.DeBruijn.Degree, cfg.DHT.FaultTolerance.SuccessorListSize)
+	space, err := domain.NewSpace(cfg.DHT.IDBits, cfg.DHT.DeBruijn.Degree, cfg.DHT.FaultTolerance.SuccessorListSize,
+		domain.WithHash(cfg.DHT.Hash))
 	if err != nil {
 		lgr.Error("failed to initialize identifier space", logger.F("err", err))
 		os.Exit(1)
 	}
+	space = space.WithNamespace(cfg.DHT.Namespace)
 	lgr.Debug("identifier space initialized", logger.F("id_bits", space.Bits), logger.F("degree", space.GraphGrade), logger.F("sizeByte", space.ByteLen), logger.F("SuccessorListSize", space.SuccListSize))
 
 	// Initialize the local node
 	var id domain.ID
+	var idDecision identity.Decision
 	if cfg.Node.Id == "" {
-		id = space.NewIdFromString(advertised) // derive ID from address
+		var rec *identity.Record
+		if cfg.Node.IdFile != "" {
+			rec, err = identity.Load(cfg.Node.IdFile) // previously persisted identity (if any)
+			if err != nil {
+				lgr.Error("failed to load persisted node identity", logger.F("err", err))
+				os.Exit(1)
+			}
+		}
+		idDecision, err = identity.Resolve(space, cfg.Node.IdStrategy, rec, advertised)
+		if err != nil {
+			lgr.Error("failed to resolve node ID", logger.F("err", err))
+			os.Exit(1)
+		}
+		id = idDecision.ID // derive ID from address or reuse the persisted one
+		if idDecision.AddrChanged {
+			if idDecision.Rejoin {
+				lgr.Warn("advertised address changed, rejoining under a new ID",
+					logger.FNode("previous", idDecision.Previous),
+					logger.F("newAddr", advertised),
+					logger.F("newId", id.ToHexString(true)))
+			} else {
+				lgr.Warn("advertised address changed, keeping persisted ID",
+					logger.FNode("previous", idDecision.Previous),
+					logger.F("newAddr", advertised))
+			}
+		}
 	} else {
 		id, err = space.FromHexString(cfg.Node.Id) // use configured ID
 		if err != nil {
@@ -92,47 +131,151 @@ func main() {
 		Addr: advertised,
 	}
 	lgr.Debug("generated node ID", logger.F("id", id.ToHexString(true)))
+	baseLgr := lgr // without the node fields, for the other virtual nodes
 	lgr = lgr.Named("node").WithNode(domainNode)
 	lgr.Info("New Node initializing")
 
 	// Initialize Telemetry (if enabled)
 	shutdown := telemetry.InitTracer(cfg.Telemetry, "KoordeDHT-Node", id)
-	defer shutdown(context.Background())
+	defer func() {
+		if err := telemetry.ShutdownWithin(shutdown, cfg.Telemetry.ShutdownTimeout); err != nil {
+			lgr.Warn("tracer did not flush before the shutdown timeout", logger.F("err", err))
+		}
+	}()
+	shutdownMeter := telemetry.InitMeter(cfg.Telemetry, "KoordeDHT-Node", id)
+	defer func() {
+		if err := telemetry.ShutdownWithin(shutdownMeter, cfg.Telemetry.ShutdownTimeout); err != nil {
+			lgr.Warn("meter did not flush before the shutdown timeout", logger.F("err", err))
+		}
+	}()
 
 	// Initialize the routing table
 	rt := routingtable2.New(
 		&domainNode,
 		space,
 		routingtable2.WithLogger(lgr.Named("routingtable")),
+		routingtable2.WithDeBruijnBackups(cfg.DHT.DeBruijn.BackupSize),
 	)
 	lgr.Debug("initialized routing table")
 
-	// Initialize the client pool
+	// Load the TLS configurations (nil in mode none)
+	serverTLS, err := security.ServerTLS(cfg.Security)
+	if err != nil {
+		lgr.Error("failed to load server TLS configuration", logger.F("err", err))
+		os.Exit(1)
+	}
+	clientTLS, err := security.ClientTLS(cfg.Security)
+	if err != nil {
+		lgr.Error("failed to load client TLS configuration", logger.F("err", err))
+		os.Exit(1)
+	}
+
+	// Initialize the client pool (shared by the virtual nodes, which dial
+	// each other: the check on the own address is then disabled)
+	poolAddr := addr
+	if cfg.DHT.VNodes > 1 {
+		poolAddr = ""
+	}
 	cp := client2.New(
 		id,
-		addr,
+		poolAddr,
 		cfg.DHT.FaultTolerance.FailureTimeout,
 		client2.WithLogger(lgr.Named("clientpool")),
+		client2.WithCompression(cfg.DHT.Compression.GRPC),
+		client2.WithTLS(clientTLS),
+		client2.WithMaxRecvMsgSize(server2.MsgSizeFor(cfg.DHT.Storage.MaxValueBytes)),
+		client2.WithKeepalive(cfg.DHT.GRPC.Keepalive.Time, cfg.DHT.GRPC.Keepalive.Timeout, cfg.DHT.GRPC.Keepalive.PermitWithoutStream),
+		client2.WithDialTimeout(cfg.DHT.FaultTolerance.DialTimeout, cfg.DHT.FaultTolerance.DialFailFast),
 	)
 	lgr.Debug("initialized client pool")
 
-	// Initialize the storage
-	store := storage.NewMemoryStorage(
-		lgr.Named("storage"),
-	)
-	lgr.Debug("initialized in-memory storage")
+	// Initialize the storage (in memory or persisted to a bolt file)
+	storeOpts := []storage.Option{
+		storage.WithChecksum(cfg.DHT.Storage.Checksum),
+		storage.WithQuota(cfg.DHT.Storage.QuotaBytes),
+		storage.WithMaxKeys(cfg.DHT.Storage.MaxKeys, cfg.DHT.Storage.Eviction),
+	}
+	var store storage.Store
+	if cfg.DHT.Storage.Backend == "bolt" {
+		store, err = storage.OpenBolt(cfg.DHT.Storage.Path, lgr.Named("storage"), storeOpts...)
+		if err != nil {
+			lgr.Error("failed to open persistent storage", logger.F("err", err))
+			os.Exit(1)
+		}
+	} else {
+		store = storage.NewMemoryStorage(lgr.Named("storage"), storeOpts...)
+	}
+	defer func() { _ = store.Close() }()
+	lgr.Debug("initialized storage", logger.F("backend", cfg.DHT.Storage.Backend))
 
 	// Initialize the node
-	n := logicnode2.New(
-		rt,
-		cp,
-		store,
+	nodeOpts := []logicnode2.Option{
 		logicnode2.WithLogger(lgr),
-	)
+		logicnode2.WithPullOnJoin(cfg.DHT.Storage.PullOnJoin),
+		logicnode2.WithHopBudget(cfg.DHT.Lookup.HopReserve, cfg.DHT.Lookup.MinHopBudget),
+		logicnode2.WithOwnerReserve(cfg.DHT.Lookup.OwnerReserve),
+		logicnode2.WithLookupRetry(cfg.DHT.Lookup.MaxRetries, cfg.DHT.Lookup.RetryBaseDelay),
+		logicnode2.WithOwnerRetries(cfg.DHT.Lookup.OwnerRetries),
+		logicnode2.WithMaxLocalDepth(cfg.DHT.Lookup.MaxLocalDepth),
+		logicnode2.WithMaxHops(cfg.DHT.Lookup.MaxHops),
+		logicnode2.WithLookupParallelism(cfg.DHT.Lookup.Parallelism),
+		logicnode2.WithMaxOutbound(cfg.DHT.Lookup.MaxOutbound),
+		logicnode2.WithLookupCache(cfg.DHT.Lookup.CacheSize, cfg.DHT.Lookup.CacheTTL),
+		logicnode2.WithCatchUp(cfg.DHT.CatchUp.Interval, cfg.DHT.CatchUp.MaxRounds),
+		logicnode2.WithLazyDeBruijnRefresh(cfg.DHT.DeBruijn.MaxRefreshAge),
+		logicnode2.WithObserver(cfg.Node.Role == "observer"),
+		logicnode2.WithDeBruijn(cfg.DHT.Routing.DeBruijn),
+		logicnode2.WithIterativeLookup(cfg.DHT.LookupMode == "iterative"),
+		logicnode2.WithReplicas(cfg.DHT.Storage.Replicas),
+		logicnode2.WithReadRepair(cfg.DHT.Storage.ReadRepair),
+		logicnode2.WithNoPredecessorPolicy(cfg.DHT.Storage.NoPredecessor),
+		logicnode2.WithAntiEntropy(cfg.DHT.Storage.AntiEntropyInterval, cfg.DHT.Storage.AntiEntropyDepth),
+	}
+	var nodeMetrics *nodemetrics.Metrics
+	if cfg.Telemetry.Metrics.Enabled {
+		reg := metrics.NewRegistry()
+		msrv, maddr, err := metrics.Serve(fmt.Sprintf(":%d", cfg.Telemetry.Metrics.Port), reg)
+		if err != nil {
+			lgr.Error("failed to start metrics endpoint", logger.F("err", err))
+			os.Exit(1)
+		}
+		defer func() {
+			if err := telemetry.ShutdownWithin(msrv.Shutdown, cfg.Telemetry.ShutdownTimeout); err != nil {
+				lgr.Warn("metrics endpoint did not stop before the shutdown timeout", logger.F("err", err))
+				_ = msrv.Close()
+			}
+		}()
+		lgr.Info("metrics endpoint listening", logger.F("addr", maddr.String()))
+		nodeMetrics = nodemetrics.New(reg)
+		nodeOpts = append(nodeOpts, logicnode2.WithMetrics(nodeMetrics))
+	}
+	if cfg.DHT.FaultTolerance.AdaptiveSuccessorList {
+		nodeOpts = append(nodeOpts, logicnode2.WithAdaptiveSuccessorList(
+			cfg.DHT.FaultTolerance.MinSuccessorListSize, cfg.DHT.FaultTolerance.MaxSuccessorListSize))
+	}
+	if cfg.DHT.FaultTolerance.MaxInterval > 0 {
+		nodeOpts = append(nodeOpts, logicnode2.WithAdaptiveStabilization(
+			cfg.DHT.FaultTolerance.MinInterval, cfg.DHT.FaultTolerance.MaxInterval))
+	}
+	nodeOpts = append(nodeOpts, logicnode2.WithAsymmetryCheck(
+		cfg.DHT.FaultTolerance.AsymmetryRounds, cfg.DHT.FaultTolerance.AsymmetryRenotify))
+	nodeOpts = append(nodeOpts, logicnode2.WithStabilizationJitter(cfg.DHT.FaultTolerance.Jitter))
+	if cfg.DHT.VNodes > 1 {
+		// the virtual nodes share the client pool and the storage of this node
+		nodeOpts = append(nodeOpts, logicnode2.WithVNodeGroup(logicnode2.NewVNodeGroup()))
+	}
+	vnodeOpts := append([]logicnode2.Option(nil), nodeOpts...) // the routing table of a virtual node is not persisted
+	if cfg.Node.RoutingFile != "" {
+		nodeOpts = append(nodeOpts, logicnode2.WithRoutingPersistence(cfg.Node.RoutingFile, cfg.Node.RoutingSaveInterval))
+	}
+	n := logicnode2.New(rt, cp, store, nodeOpts...)
 	lgr.Debug("initialized new struct node")
 
 	// Initialize the gRPC server
 	var grpcOpts []grpc.ServerOption
+	if serverTLS != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(serverTLS)))
+	}
 	if cfg.Telemetry.Tracing.Enabled {
 		grpcOpts = append(grpcOpts,
 			grpc.StatsHandler(otelgrpc.NewServerHandler(
@@ -142,12 +285,38 @@ func main() {
 		)
 	}
 
-	s, err := server2.New(
-		lis,
-		n,
-		grpcOpts,
+	srvOpts := []server2.Option{
 		server2.WithLogger(lgr.Named("server")),
-	)
+		server2.WithConfig(cfg.Fields()),
+		server2.WithMaxValueBytes(cfg.DHT.Storage.MaxValueBytes),
+		server2.WithKeepalive(cfg.DHT.GRPC.Keepalive.Time, cfg.DHT.GRPC.Keepalive.Timeout, cfg.DHT.GRPC.Keepalive.PermitWithoutStream),
+		server2.WithConcurrencyLimits(server2.Limits{
+			MaxRequests:    cfg.DHT.GRPC.MaxConcurrentRequests,
+			MaxMaintenance: cfg.DHT.GRPC.MaxConcurrentMaintenance,
+			Methods:        cfg.DHT.GRPC.MethodLimits,
+		}),
+	}
+	if auditLgr != nil {
+		srvOpts = append(srvOpts, server2.WithAccessLog(auditLgr))
+	}
+	if nodeMetrics != nil {
+		srvOpts = append(srvOpts, server2.WithUnaryInterceptors(nodeMetrics.ServerInterceptor()))
+	}
+	if cfg.Telemetry.Metrics.Enabled && cfg.Telemetry.Metrics.OTLP {
+		srvOpts = append(srvOpts,
+			server2.WithUnaryInterceptors(telemetry.UnaryServerInterceptor()),
+			server2.WithStreamInterceptors(telemetry.StreamServerInterceptor()))
+	}
+	if cfg.DHT.Bootstrap.GateUntilRegistered {
+		srvOpts = append(srvOpts, server2.WithStartupGate())
+	}
+	// admin RPCs only on the server of the primary node, whose shutdown is
+	// watched below
+	primaryOpts := srvOpts[:len(srvOpts):len(srvOpts)]
+	if cfg.Node.AdminRPC {
+		primaryOpts = append(primaryOpts, server2.WithAdmin())
+	}
+	s, err := server2.New(lis, n, grpcOpts, primaryOpts...)
 	if err != nil {
 		lgr.Error("failed to initialize gRPC server", logger.F("err", err))
 		os.Exit(1)
@@ -155,74 +324,173 @@ func main() {
 	lgr.Debug("initialized gRPC server")
 
 	// Run server in background
-	serveErr := make(chan error, 1)
+	serveErr := make(chan error, cfg.DHT.VNodes)
 	go func() { serveErr <- s.Start() }()
 	lgr.Debug("server started")
 
-	// resolve host and port for bootstrap
-	var register bootstrap.Bootstrap
-	if cfg.DHT.Bootstrap.Mode == "route53" {
-		register, err = bootstrap.NewRoute53Bootstrap(cfg.DHT.Bootstrap.Route53)
-		if err != nil {
-			lgr.Error("failed to initialize Route53 bootstrap", logger.F("err", err))
+	// Check that the advertised address reaches this node before announcing it
+	if cfg.Node.VerifySelfReachable {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := cp.Probe(ctx, advertised)
+		cancel()
+		// a closed startup gate still answers: the address reaches the server
+		if err != nil && !server2.IsNotReady(err) {
+			lgr.Error("advertised address is not reachable, refusing to register",
+				logger.F("advertised", advertised), logger.F("err", err))
 			// cleanup before exit
 			s.Stop()
 			n.Stop()
 			os.Exit(1)
 		}
-	} else if cfg.DHT.Bootstrap.Mode == "static" {
-		register = bootstrap.NewStaticBootstrap(cfg.DHT.Bootstrap.Peers)
-	} else {
-		lgr.Error("unsupported bootstrap mode", logger.F("mode", cfg.DHT.Bootstrap.Mode))
-		// cleanup before exit
-		s.Stop()
-		n.Stop()
-		os.Exit(1)
+		lgr.Debug("advertised address is reachable", logger.F("advertised", advertised))
 	}
 
-	// Join an existing DHT or create a new one
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	peers, err := register.Discover(ctx)
-	cancel()
+	// resolve host and port for bootstrap
+	register, err := bootstrap.New(cfg.DHT.Bootstrap.Mode, bootstrap.Config{
+		BootstrapConfig: cfg.DHT.Bootstrap,
+		SelfAddr:        advertised,
+	})
 	if err != nil {
-		lgr.Error("failed to resolve bootstrap peers", logger.F("err", err))
+		lgr.Error("failed to initialize bootstrap", logger.F("mode", cfg.DHT.Bootstrap.Mode), logger.F("err", err))
 		// cleanup before exit
 		s.Stop()
 		n.Stop()
 		os.Exit(1)
 	}
-	lgr.Info("resolved bootstrap peers", logger.F("peers", peers))
-	if len(peers) != 0 {
-		if err := n.Join(peers); err != nil {
+
+	// Resume from the persisted routing table if its neighbors are still
+	// alive, otherwise join an existing DHT or create a new one
+	restored := false
+	if cfg.Node.RoutingFile != "" && !idDecision.Rejoin {
+		restored, err = n.RestoreRoutingTable(cfg.Node.RoutingFile)
+		if err != nil {
+			lgr.Warn("failed to restore the routing table, joining instead",
+				logger.F("path", cfg.Node.RoutingFile), logger.F("err", err))
+		}
+	}
+	if restored {
+		lgr.Info("routing table restored, join skipped", logger.F("path", cfg.Node.RoutingFile))
+	} else {
+		joined, err := bootstrap.JoinWithRetry(context.Background(), register, n.Join, bootstrap.JoinPolicy{
+			Retries:         cfg.DHT.Bootstrap.JoinRetries,
+			Interval:        cfg.DHT.Bootstrap.JoinRetryInterval,
+			DiscoverTimeout: 10 * time.Second,
+			Permanent:       func(err error) bool { return errors.Is(err, logicnode2.ErrIDCollision) },
+		}, lgr.Named("bootstrap"))
+		if err != nil {
 			lgr.Error("failed to join DHT", logger.F("err", err))
 			// cleanup before exit
 			s.Stop()
 			n.Stop()
 			os.Exit(1)
 		}
-		lgr.Debug("joined DHT")
-	} else {
-		n.CreateNewDHT()
-		lgr.Debug("new DHT created")
+		if joined {
+			lgr.Debug("joined DHT")
+			if idDecision.Rejoin {
+				// copy the keys that no longer belong to the new ID to their
+				// owners; the storage maintenance deletes the local copies
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				n.HandOff(ctx)
+				cancel()
+				lgr.Info("key handoff after rejoin completed", logger.FNode("previous", idDecision.Previous))
+			}
+		} else if n.IsObserver() {
+			lgr.Error("observer node cannot create a new DHT: no bootstrap peers found")
+			s.Stop()
+			n.Stop()
+			os.Exit(1)
+		} else {
+			n.CreateNewDHT()
+			lgr.Debug("new DHT created")
+		}
 	}
+	// The node has a successor: report it healthy (once the gate, if any,
+	// is open)
+	s.SetServing(true)
 
-	// Register node
-	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
-	err = register.Register(ctx, &domainNode)
-	cancel()
+	// Persist node identity
+	if cfg.Node.IdFile != "" && cfg.Node.Id == "" {
+		if err := identity.Save(cfg.Node.IdFile, identity.Record{ID: id.ToHexString(true), Addr: advertised}); err != nil {
+			lgr.Warn("failed to persist node identity", logger.F("path", cfg.Node.IdFile), logger.F("err", err))
+		}
+	}
+
+	// Register node (with retries)
+	retrying := bootstrap.NewRetrying(register, bootstrap.RetryPolicy{
+		Attempts: cfg.DHT.Bootstrap.Retry.Attempts,
+		Timeout:  cfg.DHT.Bootstrap.Retry.Timeout,
+		Backoff:  cfg.DHT.Bootstrap.Retry.Backoff,
+	}, lgr.Named("bootstrap"))
+	err = retrying.Register(context.Background(), &domainNode)
 	if err != nil {
-		lgr.Error("failed to register DHT", logger.F("err", err))
+		if cfg.DHT.Bootstrap.RequireRegistration {
+			lgr.Error("failed to register node, registration is required: exiting", logger.F("err", err))
+			// cleanup before exit
+			_ = n.Leave()
+			s.Stop()
+			n.Stop()
+			os.Exit(1)
+		}
+		lgr.Error("failed to register node, continuing unregistered", logger.F("err", err))
 	} else {
 		lgr.Info("node registered successfully")
 		defer func() {
 			// Deregister node on shutdown
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			err := register.Deregister(ctx, &domainNode)
-			cancel()
-			if err != nil {
-				lgr.Warn("failed to deregister node", logger.F("err", err))
+			if err := retrying.Deregister(context.Background(), &domainNode); err != nil {
+				lgr.Error("failed to deregister node", logger.F("err", err))
 			}
 		}()
+		if cfg.DHT.Bootstrap.Mode == "route53" {
+			// Refresh the registration as a heartbeat, stopped before the deregistration
+			refresher := bootstrap.StartRefresher(retrying, &domainNode, cfg.DHT.Bootstrap.Route53.RefreshInterval, lgr.Named("bootstrap"))
+			defer refresher.Stop()
+		}
+	}
+
+	// The node is in the DHT and (if possible) registered: start serving
+	s.SetReady()
+
+	// Start the other virtual nodes (dht.vnodes > 1) on the following ports:
+	// they join the DHT through this node and are not registered
+	type vnode struct {
+		n *logicnode2.Node
+		s *server2.Server
+	}
+	var vnodes []vnode
+	for i := 1; i < cfg.DHT.VNodes; i++ {
+		port := 0
+		if cfg.Node.Port != 0 {
+			port = cfg.Node.Port + i
+		}
+		vlis, vaddr, err := server2.Listen(cfg.DHT.Mode, cfg.Node.IPVersion, cfg.Node.Bind, cfg.Node.Host, port)
+		if err != nil {
+			lgr.Error("failed to initialize the listener of a virtual node, running fewer",
+				logger.F("vnode", i), logger.F("err", err))
+			break
+		}
+		vself := domain.Node{ID: logicnode2.VNodeID(space, advertised, i), Addr: vaddr}
+		vlgr := baseLgr.Named("node").WithNode(vself)
+		vrt := routingtable2.New(&vself, space, routingtable2.WithLogger(vlgr.Named("routingtable")),
+			routingtable2.WithDeBruijnBackups(cfg.DHT.DeBruijn.BackupSize))
+		vn := logicnode2.New(vrt, cp, store, append(vnodeOpts[:len(vnodeOpts):len(vnodeOpts)], logicnode2.WithLogger(vlgr))...)
+		vs, err := server2.New(vlis, vn, grpcOpts, append(srvOpts[:len(srvOpts):len(srvOpts)], server2.WithLogger(vlgr.Named("server")))...)
+		if err != nil {
+			lgr.Error("failed to initialize the gRPC server of a virtual node, running fewer",
+				logger.F("vnode", i), logger.F("err", err))
+			_ = vlis.Close()
+			break
+		}
+		go func() { serveErr <- vs.Start() }()
+		if err := vn.Join([]string{advertised}); err != nil {
+			lgr.Error("virtual node failed to join DHT, running fewer",
+				logger.F("vnode", i), logger.F("err", err))
+			vs.Stop()
+			break
+		}
+		vs.SetReady()
+		vs.SetServing(true)
+		vnodes = append(vnodes, vnode{n: vn, s: vs})
+		lgr.Info("virtual node joined DHT", logger.F("vnode", i), logger.FNode("self", &vself))
 	}
 
 	// Setup signal handler for graceful shutdown
@@ -230,37 +498,59 @@ func main() {
 
 	// Start periodic stabilization workers (run until ctx is canceled)
 	n.StartStabilizers(ctx, cfg.DHT.FaultTolerance.StabilizationInterval, cfg.DHT.DeBruijn.FixInterval, cfg.DHT.Storage.FixInterval)
+	for _, v := range vnodes {
+		v.n.StartStabilizers(ctx, cfg.DHT.FaultTolerance.StabilizationInterval, cfg.DHT.DeBruijn.FixInterval, cfg.DHT.Storage.FixInterval)
+	}
 	lgr.Debug("Stabilization workers started")
 
+	// Start the sweeper of expired resources
+	store.StartSweeper(ctx, cfg.DHT.Storage.SweepInterval)
+
 	select {
 	case <-ctx.Done():
 		lgr.Info("shutdown signal received, stopping server gracefully...")
-
-		stabilizerStop() // stop stabilization workers
-
-		// Allow some time for graceful stop
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
-		done := make(chan struct{})
-		go func() {
-			s.GracefulStop()
-			close(done)
-		}()
-
-		select {
-		case <-done:
-			lgr.Info("server stopped gracefully")
-		case <-shutdownCtx.Done():
-			lgr.Warn("graceful stop timed out, forcing shutdown")
-		}
-
-		n.Stop() // stop node
-
+	case <-s.ShutdownRequested():
+		// the node has already left the ring and the server is stopping
+		lgr.Info("shutdown requested via RPC, stopping server gracefully...")
 	case err := <-serveErr:
 		lgr.Error("gRPC server terminated unexpectedly", logger.F("err", err))
 		stabilizerStop()
+		for _, v := range vnodes {
+			v.s.Stop()
+		}
 		n.Stop()
 		os.Exit(1)
 	}
+
+	stabilizerStop() // stop stabilization workers
+	s.SetServing(false)
+	for _, v := range vnodes {
+		v.s.SetServing(false)
+	}
+
+	// the other virtual nodes leave first, handing their ranges over
+	// while this node still serves (unless it already left via Shutdown)
+	for _, v := range vnodes {
+		_ = v.n.Leave()
+		v.s.Stop()
+	}
+
+	// Allow some time for graceful stop
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		lgr.Info("server stopped gracefully")
+	case <-shutdownCtx.Done():
+		lgr.Warn("graceful stop timed out, forcing shutdown")
+	}
+
+	n.Stop() // stop node
 }
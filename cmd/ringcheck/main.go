@@ -0,0 +1,52 @@
+package main
+
+import (
+	"KoordeDHT/internal/client"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+func main() {
+	// CLI flags
+	addr := flag.String("addr", "bootstrap:4000", "Address of the Koorde node used as seed")
+	timeout := flag.Duration("timeout", 30*time.Second, "Timeout for the whole ring walk (e.g., 30s)")
+	maxWalk := flag.Int("max-walk", client.DefaultMaxRingWalk, "Maximum number of successor hops of the ring walk")
+	flag.Parse()
+
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	report, err := client.CheckRing(ctx, *addr, client.WithMaxRingWalk(*maxWalk))
+	if err != nil {
+		log.Fatalf("Ring check failed: %v", err)
+	}
+
+	fmt.Printf("Visited %d node(s) starting from %s\n", len(report.Nodes), *addr)
+	for i, rt := range report.Nodes {
+		pred := "<nil>"
+		if rt.Predecessor != nil {
+			pred = rt.Predecessor.Id
+		}
+		fmt.Printf("  %3d  %s  %-21s pred=%s\n", i, rt.Self.Id, rt.Self.Addr, pred)
+	}
+	if report.Truncated {
+		fmt.Printf("Ring walk truncated after %d steps\n", *maxWalk)
+	} else if !report.Complete {
+		fmt.Println("Ring walk did not return to the seed")
+	}
+	if len(report.Violations) == 0 && report.Complete {
+		fmt.Println("Ring is consistent")
+		return
+	}
+	fmt.Printf("%d violation(s):\n", len(report.Violations))
+	for _, v := range report.Violations {
+		fmt.Printf("  %s\n", v)
+	}
+	os.Exit(1)
+}
@@ -4,9 +4,11 @@ import (
 	"KoordeDHT/internal/bootstrap"
 	"KoordeDHT/internal/client/tester"
 	"KoordeDHT/internal/client/tester/writer"
+	"KoordeDHT/internal/configloader"
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
 	zapfactory "KoordeDHT/internal/logger/zap"
+	"KoordeDHT/internal/telemetry/metrics"
 	"context"
 	"flag"
 	"log"
@@ -61,23 +63,24 @@ func main() {
 	}
 	defer w.Close()
 
-	// initialize domain space
-	space, err := domain.NewSpace(cfg.DHT.IDBits, 2, 2)
-	if err != nil {
-		lgr.Error("failed to initialize domain space", logger.F("err", err))
-		return
-	}
-
-	// initialize bootstrap
-	var boot bootstrap.Bootstrap
-	if cfg.Bootstrap.Mode == "route53" {
-		boot, err = bootstrap.NewRoute53Bootstrap(cfg.Bootstrap.Route53)
+	// initialize domain space (discovered from the ring if idBits is 0)
+	space := domain.Space{Hash: cfg.DHT.Hash}
+	if cfg.DHT.IDBits > 0 {
+		space, err = domain.NewSpace(cfg.DHT.IDBits, 2, 2, domain.WithHash(cfg.DHT.Hash))
 		if err != nil {
-			lgr.Error("failed to initialize route53 bootstrap", logger.F("err", err))
+			lgr.Error("failed to initialize domain space", logger.F("err", err))
 			return
 		}
-	} else {
-		boot = tester.NewDockerBootstrap(cfg.Bootstrap.Docker.ContainerSuffix, cfg.Bootstrap.Docker.Port, cfg.Bootstrap.Docker.Network)
+	}
+
+	// initialize bootstrap
+	boot, err := bootstrap.New(cfg.Bootstrap.Mode, bootstrap.Config{
+		BootstrapConfig: configloader.BootstrapConfig{Mode: cfg.Bootstrap.Mode, Route53: cfg.Bootstrap.Route53},
+		Extra:           cfg.Bootstrap.Docker,
+	})
+	if err != nil {
+		lgr.Error("failed to initialize bootstrap", logger.F("mode", cfg.Bootstrap.Mode), logger.F("err", err))
+		return
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -93,8 +96,22 @@ func main() {
 		cancel()
 	}()
 
+	// Expose metrics, if enabled
+	var opts []tester.Option
+	if cfg.Metrics.Enabled {
+		reg := metrics.NewRegistry()
+		srv, addr, err := metrics.Serve(cfg.Metrics.Addr, reg)
+		if err != nil {
+			lgr.Error("failed to start metrics endpoint", logger.F("err", err))
+			return
+		}
+		defer func() { _ = srv.Close() }()
+		lgr.Info("metrics endpoint listening", logger.F("addr", addr.String()))
+		opts = append(opts, tester.WithMetrics(tester.NewMetrics(reg)))
+	}
+
 	// Initialize and run tester
-	runner := tester.New(cfg, lgr.Named("runner"), w, boot, space)
+	runner := tester.New(cfg, lgr.Named("runner"), w, boot, space, opts...)
 	start := time.Now()
 	if err := runner.Run(ctx); err != nil {
 		lgr.Error("tester run failed", logger.F("err", err))
@@ -27,8 +27,11 @@ const (
 // ---------------------------------------------------------------
 type Resource struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`     // Resource key (application-key)
-	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"` // Resource value
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`                                                                                     // Resource key (application-key)
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`                                                                                 // Resource value
+	Origin        string                 `protobuf:"bytes,3,opt,name=origin,proto3" json:"origin,omitempty"`                                                                               // ID of the node that first accepted the Put (read-only, empty = unknown)
+	Id            string                 `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`                                                                                       // Pre-hashed key ID (hex digest, truncated into the ID space); if set, key is not hashed
+	Metadata      map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Application metadata (e.g. content-type), stored and returned with the value (empty = none)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -77,9 +80,31 @@ func (x *Resource) GetValue() string {
 	return ""
 }
 
+func (x *Resource) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *Resource) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Resource) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 type PutRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Resource      *Resource              `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	TtlSeconds    uint32                 `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // Time to live of the resource (0 = never expires)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -121,27 +146,41 @@ func (x *PutRequest) GetResource() *Resource {
 	return nil
 }
 
-type GetRequest struct {
+func (x *PutRequest) GetTtlSeconds() uint32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+// Conditional Put: the resource is stored only if the condition holds for
+// the resource currently stored under its key. Exactly one of if_absent and
+// if_equal must be set.
+type PutIfRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Resource      *Resource              `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	TtlSeconds    uint32                 `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // Time to live of the resource (0 = never expires)
+	IfAbsent      bool                   `protobuf:"varint,3,opt,name=if_absent,json=ifAbsent,proto3" json:"if_absent,omitempty"`       // Only if the key is not stored
+	IfEqual       bool                   `protobuf:"varint,4,opt,name=if_equal,json=ifEqual,proto3" json:"if_equal,omitempty"`          // Only if the current value equals expected (compare-and-swap)
+	Expected      string                 `protobuf:"bytes,5,opt,name=expected,proto3" json:"expected,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetRequest) Reset() {
-	*x = GetRequest{}
+func (x *PutIfRequest) Reset() {
+	*x = PutIfRequest{}
 	mi := &file_client_v1_client_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRequest) String() string {
+func (x *PutIfRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRequest) ProtoMessage() {}
+func (*PutIfRequest) ProtoMessage() {}
 
-func (x *GetRequest) ProtoReflect() protoreflect.Message {
+func (x *PutIfRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_client_v1_client_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -153,39 +192,69 @@ func (x *GetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
-func (*GetRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PutIfRequest.ProtoReflect.Descriptor instead.
+func (*PutIfRequest) Descriptor() ([]byte, []int) {
 	return file_client_v1_client_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetRequest) GetKey() string {
+func (x *PutIfRequest) GetResource() *Resource {
 	if x != nil {
-		return x.Key
+		return x.Resource
+	}
+	return nil
+}
+
+func (x *PutIfRequest) GetTtlSeconds() uint32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *PutIfRequest) GetIfAbsent() bool {
+	if x != nil {
+		return x.IfAbsent
+	}
+	return false
+}
+
+func (x *PutIfRequest) GetIfEqual() bool {
+	if x != nil {
+		return x.IfEqual
+	}
+	return false
+}
+
+func (x *PutIfRequest) GetExpected() string {
+	if x != nil {
+		return x.Expected
 	}
 	return ""
 }
 
-type GetResponse struct {
+// Content-addressable Put: the key is derived from the value itself.
+type PutContentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	TtlSeconds    uint32                 `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // Time to live of the resource (0 = never expires)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetResponse) Reset() {
-	*x = GetResponse{}
+func (x *PutContentRequest) Reset() {
+	*x = PutContentRequest{}
 	mi := &file_client_v1_client_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetResponse) String() string {
+func (x *PutContentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetResponse) ProtoMessage() {}
+func (*PutContentRequest) ProtoMessage() {}
 
-func (x *GetResponse) ProtoReflect() protoreflect.Message {
+func (x *PutContentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_client_v1_client_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -197,39 +266,46 @@ func (x *GetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
-func (*GetResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use PutContentRequest.ProtoReflect.Descriptor instead.
+func (*PutContentRequest) Descriptor() ([]byte, []int) {
 	return file_client_v1_client_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetResponse) GetValue() string {
+func (x *PutContentRequest) GetValue() string {
 	if x != nil {
 		return x.Value
 	}
 	return ""
 }
 
-type DeleteRequest struct {
+func (x *PutContentRequest) GetTtlSeconds() uint32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type PutContentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Key ID derived from the value (hex string), to be passed as GetRequest.id / DeleteRequest.id
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteRequest) Reset() {
-	*x = DeleteRequest{}
+func (x *PutContentResponse) Reset() {
+	*x = PutContentResponse{}
 	mi := &file_client_v1_client_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRequest) String() string {
+func (x *PutContentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRequest) ProtoMessage() {}
+func (*PutContentResponse) ProtoMessage() {}
 
-func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+func (x *PutContentResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_client_v1_client_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -241,40 +317,40 @@ func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PutContentResponse.ProtoReflect.Descriptor instead.
+func (*PutContentResponse) Descriptor() ([]byte, []int) {
 	return file_client_v1_client_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *DeleteRequest) GetKey() string {
+func (x *PutContentResponse) GetId() string {
 	if x != nil {
-		return x.Key
+		return x.Id
 	}
 	return ""
 }
 
-type NodeInfo struct {
+type GetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`     // Unique identifier of the node in the ring (hex string)
-	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"` // Address of the node (host:port)
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"` // Pre-hashed key ID (hex digest, see Resource.id); if set, key is not hashed
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NodeInfo) Reset() {
-	*x = NodeInfo{}
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
 	mi := &file_client_v1_client_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NodeInfo) String() string {
+func (x *GetRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NodeInfo) ProtoMessage() {}
+func (*GetRequest) ProtoMessage() {}
 
-func (x *NodeInfo) ProtoReflect() protoreflect.Message {
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_client_v1_client_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -286,47 +362,48 @@ func (x *NodeInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NodeInfo.ProtoReflect.Descriptor instead.
-func (*NodeInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
 	return file_client_v1_client_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *NodeInfo) GetId() string {
+func (x *GetRequest) GetKey() string {
 	if x != nil {
-		return x.Id
+		return x.Key
 	}
 	return ""
 }
 
-func (x *NodeInfo) GetAddr() string {
+func (x *GetRequest) GetId() string {
 	if x != nil {
-		return x.Addr
+		return x.Id
 	}
 	return ""
 }
 
-type GetStoreResponse struct {
+type GetResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Item          *Resource              `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"` // id of the resource in the dht
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Origin        string                 `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`                                                                               // ID of the node that first accepted the Put (empty = unknown)
+	Metadata      map[string]string      `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Metadata stored by the Put (empty = none)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStoreResponse) Reset() {
-	*x = GetStoreResponse{}
+func (x *GetResponse) Reset() {
+	*x = GetResponse{}
 	mi := &file_client_v1_client_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStoreResponse) String() string {
+func (x *GetResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStoreResponse) ProtoMessage() {}
+func (*GetResponse) ProtoMessage() {}
 
-func (x *GetStoreResponse) ProtoReflect() protoreflect.Message {
+func (x *GetResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_client_v1_client_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -338,49 +415,54 @@ func (x *GetStoreResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStoreResponse.ProtoReflect.Descriptor instead.
-func (*GetStoreResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
+func (*GetResponse) Descriptor() ([]byte, []int) {
 	return file_client_v1_client_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetStoreResponse) GetItem() *Resource {
+func (x *GetResponse) GetValue() string {
 	if x != nil {
-		return x.Item
+		return x.Value
 	}
-	return nil
+	return ""
 }
 
-func (x *GetStoreResponse) GetId() string {
+func (x *GetResponse) GetOrigin() string {
 	if x != nil {
-		return x.Id
+		return x.Origin
 	}
 	return ""
 }
 
-type GetRoutingTableResponse struct {
+func (x *GetResponse) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type DeleteRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Self          *NodeInfo              `protobuf:"bytes,1,opt,name=self,proto3" json:"self,omitempty"`
-	Predecessor   *NodeInfo              `protobuf:"bytes,2,opt,name=predecessor,proto3" json:"predecessor,omitempty"`
-	Successors    []*NodeInfo            `protobuf:"bytes,3,rep,name=successors,proto3" json:"successors,omitempty"`
-	DeBruijnList  []*NodeInfo            `protobuf:"bytes,4,rep,name=de_bruijn_list,json=deBruijnList,proto3" json:"de_bruijn_list,omitempty"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"` // Pre-hashed key ID (hex digest, see Resource.id); if set, key is not hashed
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetRoutingTableResponse) Reset() {
-	*x = GetRoutingTableResponse{}
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
 	mi := &file_client_v1_client_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRoutingTableResponse) String() string {
+func (x *DeleteRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRoutingTableResponse) ProtoMessage() {}
+func (*DeleteRequest) ProtoMessage() {}
 
-func (x *GetRoutingTableResponse) ProtoReflect() protoreflect.Message {
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_client_v1_client_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -392,61 +474,99 @@ func (x *GetRoutingTableResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRoutingTableResponse.ProtoReflect.Descriptor instead.
-func (*GetRoutingTableResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
 	return file_client_v1_client_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetRoutingTableResponse) GetSelf() *NodeInfo {
+func (x *DeleteRequest) GetKey() string {
 	if x != nil {
-		return x.Self
+		return x.Key
 	}
-	return nil
+	return ""
 }
 
-func (x *GetRoutingTableResponse) GetPredecessor() *NodeInfo {
+func (x *DeleteRequest) GetId() string {
 	if x != nil {
-		return x.Predecessor
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-func (x *GetRoutingTableResponse) GetSuccessors() []*NodeInfo {
+type DeleteRangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"` // Exclusive lower bound of the key IDs (hex string)
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`     // Inclusive upper bound of the key IDs (hex string, from == to = the whole ring)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRangeRequest) Reset() {
+	*x = DeleteRangeRequest{}
+	mi := &file_client_v1_client_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRangeRequest) ProtoMessage() {}
+
+func (x *DeleteRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[8]
 	if x != nil {
-		return x.Successors
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *GetRoutingTableResponse) GetDeBruijnList() []*NodeInfo {
+// Deprecated: Use DeleteRangeRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRangeRequest) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteRangeRequest) GetFrom() string {
 	if x != nil {
-		return x.DeBruijnList
+		return x.From
 	}
-	return nil
+	return ""
 }
 
-type LookupRequest struct {
+func (x *DeleteRangeRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type DeleteRangeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Identifier to look up
+	Deleted       uint64                 `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"` // Resources deleted, replica copies excluded
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LookupRequest) Reset() {
-	*x = LookupRequest{}
-	mi := &file_client_v1_client_proto_msgTypes[8]
+func (x *DeleteRangeResponse) Reset() {
+	*x = DeleteRangeResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LookupRequest) String() string {
+func (x *DeleteRangeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LookupRequest) ProtoMessage() {}
+func (*DeleteRangeResponse) ProtoMessage() {}
 
-func (x *LookupRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_client_v1_client_proto_msgTypes[8]
+func (x *DeleteRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -457,40 +577,42 @@ func (x *LookupRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LookupRequest.ProtoReflect.Descriptor instead.
-func (*LookupRequest) Descriptor() ([]byte, []int) {
-	return file_client_v1_client_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use DeleteRangeResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRangeResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *LookupRequest) GetId() string {
+func (x *DeleteRangeResponse) GetDeleted() uint64 {
 	if x != nil {
-		return x.Id
+		return x.Deleted
 	}
-	return ""
+	return 0
 }
 
-type LookupResponse struct {
+type BatchDeleteResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Successor     *NodeInfo              `protobuf:"bytes,1,opt,name=successor,proto3" json:"successor,omitempty"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Deleted       bool                   `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"` // false with an empty error: the key was not found
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`      // set if the key could not be deleted
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LookupResponse) Reset() {
-	*x = LookupResponse{}
-	mi := &file_client_v1_client_proto_msgTypes[9]
+func (x *BatchDeleteResult) Reset() {
+	*x = BatchDeleteResult{}
+	mi := &file_client_v1_client_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LookupResponse) String() string {
+func (x *BatchDeleteResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LookupResponse) ProtoMessage() {}
+func (*BatchDeleteResult) ProtoMessage() {}
 
-func (x *LookupResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_client_v1_client_proto_msgTypes[9]
+func (x *BatchDeleteResult) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -501,60 +623,1543 @@ func (x *LookupResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LookupResponse.ProtoReflect.Descriptor instead.
-func (*LookupResponse) Descriptor() ([]byte, []int) {
-	return file_client_v1_client_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use BatchDeleteResult.ProtoReflect.Descriptor instead.
+func (*BatchDeleteResult) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *LookupResponse) GetSuccessor() *NodeInfo {
+func (x *BatchDeleteResult) GetKey() string {
 	if x != nil {
-		return x.Successor
+		return x.Key
 	}
-	return nil
+	return ""
 }
 
-var File_client_v1_client_proto protoreflect.FileDescriptor
+func (x *BatchDeleteResult) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
 
-const file_client_v1_client_proto_rawDesc = "" +
-	"\n" +
-	"\x16client/v1/client.proto\x12\tclient.v1\x1a\x1bgoogle/protobuf/empty.proto\"2\n" +
-	"\bResource\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\"=\n" +
-	"\n" +
-	"PutRequest\x12/\n" +
-	"\bresource\x18\x01 \x01(\v2\x13.client.v1.ResourceR\bresource\"\x1e\n" +
-	"\n" +
-	"GetRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\"#\n" +
-	"\vGetResponse\x12\x14\n" +
-	"\x05value\x18\x01 \x01(\tR\x05value\"!\n" +
-	"\rDeleteRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\".\n" +
-	"\bNodeInfo\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04addr\x18\x02 \x01(\tR\x04addr\"K\n" +
-	"\x10GetStoreResponse\x12'\n" +
-	"\x04item\x18\x01 \x01(\v2\x13.client.v1.ResourceR\x04item\x12\x0e\n" +
-	"\x02id\x18\x02 \x01(\tR\x02id\"\xe9\x01\n" +
-	"\x17GetRoutingTableResponse\x12'\n" +
-	"\x04self\x18\x01 \x01(\v2\x13.client.v1.NodeInfoR\x04self\x125\n" +
-	"\vpredecessor\x18\x02 \x01(\v2\x13.client.v1.NodeInfoR\vpredecessor\x123\n" +
-	"\n" +
+func (x *BatchDeleteResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchPutFailure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"` // reason the resource could not be stored
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchPutFailure) Reset() {
+	*x = BatchPutFailure{}
+	mi := &file_client_v1_client_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchPutFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchPutFailure) ProtoMessage() {}
+
+func (x *BatchPutFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchPutFailure.ProtoReflect.Descriptor instead.
+func (*BatchPutFailure) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchPutFailure) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *BatchPutFailure) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchPutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stored        uint32                 `protobuf:"varint,1,opt,name=stored,proto3" json:"stored,omitempty"` // number of resources stored
+	Failed        []*BatchPutFailure     `protobuf:"bytes,2,rep,name=failed,proto3" json:"failed,omitempty"`  // resources that could not be stored
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchPutResponse) Reset() {
+	*x = BatchPutResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchPutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchPutResponse) ProtoMessage() {}
+
+func (x *BatchPutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchPutResponse.ProtoReflect.Descriptor instead.
+func (*BatchPutResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchPutResponse) GetStored() uint32 {
+	if x != nil {
+		return x.Stored
+	}
+	return 0
+}
+
+func (x *BatchPutResponse) GetFailed() []*BatchPutFailure {
+	if x != nil {
+		return x.Failed
+	}
+	return nil
+}
+
+type BatchGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetRequest) Reset() {
+	*x = BatchGetRequest{}
+	mi := &file_client_v1_client_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetRequest) ProtoMessage() {}
+
+func (x *BatchGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetRequest) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BatchGetRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type BatchGetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         map[string]string      `protobuf:"bytes,1,rep,name=found,proto3" json:"found,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`   // key -> value of the keys found
+	NotFound      []string               `protobuf:"bytes,2,rep,name=not_found,json=notFound,proto3" json:"not_found,omitempty"`                                                       // keys that do not exist
+	Failed        map[string]string      `protobuf:"bytes,3,rep,name=failed,proto3" json:"failed,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // key -> error of the keys that could not be retrieved
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetResponse) Reset() {
+	*x = BatchGetResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetResponse) ProtoMessage() {}
+
+func (x *BatchGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchGetResponse) GetFound() map[string]string {
+	if x != nil {
+		return x.Found
+	}
+	return nil
+}
+
+func (x *BatchGetResponse) GetNotFound() []string {
+	if x != nil {
+		return x.NotFound
+	}
+	return nil
+}
+
+func (x *BatchGetResponse) GetFailed() map[string]string {
+	if x != nil {
+		return x.Failed
+	}
+	return nil
+}
+
+type NodeInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`     // Unique identifier of the node in the ring (hex string)
+	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"` // Address of the node (host:port)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeInfo) Reset() {
+	*x = NodeInfo{}
+	mi := &file_client_v1_client_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeInfo) ProtoMessage() {}
+
+func (x *NodeInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeInfo.ProtoReflect.Descriptor instead.
+func (*NodeInfo) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *NodeInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NodeInfo) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+type GetStoreResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Item          *Resource              `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"` // id of the resource in the dht
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStoreResponse) Reset() {
+	*x = GetStoreResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStoreResponse) ProtoMessage() {}
+
+func (x *GetStoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStoreResponse.ProtoReflect.Descriptor instead.
+func (*GetStoreResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetStoreResponse) GetItem() *Resource {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *GetStoreResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetStorePageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        string                 `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`                        // Only resources whose key starts with prefix (empty = all)
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page (empty = first page)
+	Limit         uint32                 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                         // Resources per page (0 = server default)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStorePageRequest) Reset() {
+	*x = GetStorePageRequest{}
+	mi := &file_client_v1_client_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStorePageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStorePageRequest) ProtoMessage() {}
+
+func (x *GetStorePageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStorePageRequest.ProtoReflect.Descriptor instead.
+func (*GetStorePageRequest) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetStorePageRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *GetStorePageRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetStorePageRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetStorePageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*GetStoreResponse    `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`                                        // Resources ordered by id
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Token of the next page (empty = last page)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStorePageResponse) Reset() {
+	*x = GetStorePageResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStorePageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStorePageResponse) ProtoMessage() {}
+
+func (x *GetStorePageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStorePageResponse.ProtoReflect.Descriptor instead.
+func (*GetStorePageResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetStorePageResponse) GetItems() []*GetStoreResponse {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *GetStorePageResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetRoutingTableResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Self          *NodeInfo                  `protobuf:"bytes,1,opt,name=self,proto3" json:"self,omitempty"`
+	Predecessor   *NodeInfo                  `protobuf:"bytes,2,opt,name=predecessor,proto3" json:"predecessor,omitempty"`
+	Successors    []*NodeInfo                `protobuf:"bytes,3,rep,name=successors,proto3" json:"successors,omitempty"`
+	DeBruijnList  []*NodeInfo                `protobuf:"bytes,4,rep,name=de_bruijn_list,json=deBruijnList,proto3" json:"de_bruijn_list,omitempty"`
+	Vnodes        []*GetRoutingTableResponse `protobuf:"bytes,5,rep,name=vnodes,proto3" json:"vnodes,omitempty"` // Routing tables of the other virtual nodes of the same physical node (dht.vnodes > 1)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoutingTableResponse) Reset() {
+	*x = GetRoutingTableResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoutingTableResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoutingTableResponse) ProtoMessage() {}
+
+func (x *GetRoutingTableResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoutingTableResponse.ProtoReflect.Descriptor instead.
+func (*GetRoutingTableResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetRoutingTableResponse) GetSelf() *NodeInfo {
+	if x != nil {
+		return x.Self
+	}
+	return nil
+}
+
+func (x *GetRoutingTableResponse) GetPredecessor() *NodeInfo {
+	if x != nil {
+		return x.Predecessor
+	}
+	return nil
+}
+
+func (x *GetRoutingTableResponse) GetSuccessors() []*NodeInfo {
+	if x != nil {
+		return x.Successors
+	}
+	return nil
+}
+
+func (x *GetRoutingTableResponse) GetDeBruijnList() []*NodeInfo {
+	if x != nil {
+		return x.DeBruijnList
+	}
+	return nil
+}
+
+func (x *GetRoutingTableResponse) GetVnodes() []*GetRoutingTableResponse {
+	if x != nil {
+		return x.Vnodes
+	}
+	return nil
+}
+
+type InfoResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Self              *NodeInfo              `protobuf:"bytes,1,opt,name=self,proto3" json:"self,omitempty"`
+	Predecessor       *NodeInfo              `protobuf:"bytes,2,opt,name=predecessor,proto3" json:"predecessor,omitempty"`                                       // Unset if not known yet
+	SuccessorCount    uint32                 `protobuf:"varint,3,opt,name=successor_count,json=successorCount,proto3" json:"successor_count,omitempty"`          // Entries of the successor list
+	DeBruijnFilled    uint32                 `protobuf:"varint,4,opt,name=de_bruijn_filled,json=deBruijnFilled,proto3" json:"de_bruijn_filled,omitempty"`        // Non-empty entries of the de Bruijn list
+	DeBruijnDegree    uint32                 `protobuf:"varint,5,opt,name=de_bruijn_degree,json=deBruijnDegree,proto3" json:"de_bruijn_degree,omitempty"`        // Entries of a full de Bruijn list (degree k)
+	StoredKeys        uint64                 `protobuf:"varint,6,opt,name=stored_keys,json=storedKeys,proto3" json:"stored_keys,omitempty"`                      // Resources stored in the node
+	UptimeMs          int64                  `protobuf:"varint,7,opt,name=uptime_ms,json=uptimeMs,proto3" json:"uptime_ms,omitempty"`                            // Time since the node started
+	PooledConnections uint32                 `protobuf:"varint,8,opt,name=pooled_connections,json=pooledConnections,proto3" json:"pooled_connections,omitempty"` // Open connections of the client pool
+	MaxKeys           uint64                 `protobuf:"varint,9,opt,name=max_keys,json=maxKeys,proto3" json:"max_keys,omitempty"`                               // Maximum number of stored resources (0 = unlimited)
+	OutboundInFlight  uint32                 `protobuf:"varint,10,opt,name=outbound_in_flight,json=outboundInFlight,proto3" json:"outbound_in_flight,omitempty"` // Outbound forward RPCs in progress (lookup hops, Put/Get/Delete sent to the owner)
+	MaxOutbound       uint32                 `protobuf:"varint,11,opt,name=max_outbound,json=maxOutbound,proto3" json:"max_outbound,omitempty"`                  // Maximum concurrent outbound forward RPCs (0 = unlimited)
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *InfoResponse) Reset() {
+	*x = InfoResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InfoResponse) ProtoMessage() {}
+
+func (x *InfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InfoResponse.ProtoReflect.Descriptor instead.
+func (*InfoResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *InfoResponse) GetSelf() *NodeInfo {
+	if x != nil {
+		return x.Self
+	}
+	return nil
+}
+
+func (x *InfoResponse) GetPredecessor() *NodeInfo {
+	if x != nil {
+		return x.Predecessor
+	}
+	return nil
+}
+
+func (x *InfoResponse) GetSuccessorCount() uint32 {
+	if x != nil {
+		return x.SuccessorCount
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetDeBruijnFilled() uint32 {
+	if x != nil {
+		return x.DeBruijnFilled
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetDeBruijnDegree() uint32 {
+	if x != nil {
+		return x.DeBruijnDegree
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetStoredKeys() uint64 {
+	if x != nil {
+		return x.StoredKeys
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetUptimeMs() int64 {
+	if x != nil {
+		return x.UptimeMs
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetPooledConnections() uint32 {
+	if x != nil {
+		return x.PooledConnections
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetMaxKeys() uint64 {
+	if x != nil {
+		return x.MaxKeys
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetOutboundInFlight() uint32 {
+	if x != nil {
+		return x.OutboundInFlight
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetMaxOutbound() uint32 {
+	if x != nil {
+		return x.MaxOutbound
+	}
+	return 0
+}
+
+type GetSpaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IdBits        uint32                 `protobuf:"varint,1,opt,name=id_bits,json=idBits,proto3" json:"id_bits,omitempty"`                     // Bits of the identifiers
+	Degree        uint32                 `protobuf:"varint,2,opt,name=degree,proto3" json:"degree,omitempty"`                                   // Degree k of the de Bruijn graph
+	SuccListSize  uint32                 `protobuf:"varint,3,opt,name=succ_list_size,json=succListSize,proto3" json:"succ_list_size,omitempty"` // Configured length of the successor list
+	Hash          string                 `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`                                        // Hash function deriving identifiers from keys (e.g. sha1)
+	Namespace     string                 `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`                              // Namespace mixed into every derived identifier ("" = none)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSpaceResponse) Reset() {
+	*x = GetSpaceResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSpaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSpaceResponse) ProtoMessage() {}
+
+func (x *GetSpaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSpaceResponse.ProtoReflect.Descriptor instead.
+func (*GetSpaceResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetSpaceResponse) GetIdBits() uint32 {
+	if x != nil {
+		return x.IdBits
+	}
+	return 0
+}
+
+func (x *GetSpaceResponse) GetDegree() uint32 {
+	if x != nil {
+		return x.Degree
+	}
+	return 0
+}
+
+func (x *GetSpaceResponse) GetSuccListSize() uint32 {
+	if x != nil {
+		return x.SuccListSize
+	}
+	return 0
+}
+
+func (x *GetSpaceResponse) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *GetSpaceResponse) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type ConfigEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`     // Configuration field (e.g. dht.deBruijn.degree)
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"` // Effective value, secrets redacted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigEntry) Reset() {
+	*x = ConfigEntry{}
+	mi := &file_client_v1_client_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigEntry) ProtoMessage() {}
+
+func (x *ConfigEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigEntry.ProtoReflect.Descriptor instead.
+func (*ConfigEntry) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ConfigEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ConfigEntry) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type GetConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*ConfigEntry         `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigResponse) Reset() {
+	*x = GetConfigResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigResponse) ProtoMessage() {}
+
+func (x *GetConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigResponse.ProtoReflect.Descriptor instead.
+func (*GetConfigResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetConfigResponse) GetEntries() []*ConfigEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type PauseStabilizationRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	DurationMs           uint64                 `protobuf:"varint,1,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`                                 // Length of the pause (0 = resume immediately)
+	KeepFailureDetection bool                   `protobuf:"varint,2,opt,name=keep_failure_detection,json=keepFailureDetection,proto3" json:"keep_failure_detection,omitempty"` // Keep probing the predecessor while paused
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *PauseStabilizationRequest) Reset() {
+	*x = PauseStabilizationRequest{}
+	mi := &file_client_v1_client_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseStabilizationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseStabilizationRequest) ProtoMessage() {}
+
+func (x *PauseStabilizationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseStabilizationRequest.ProtoReflect.Descriptor instead.
+func (*PauseStabilizationRequest) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PauseStabilizationRequest) GetDurationMs() uint64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *PauseStabilizationRequest) GetKeepFailureDetection() bool {
+	if x != nil {
+		return x.KeepFailureDetection
+	}
+	return false
+}
+
+type PauseStabilizationResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ResumeAtUnixMs int64                  `protobuf:"varint,1,opt,name=resume_at_unix_ms,json=resumeAtUnixMs,proto3" json:"resume_at_unix_ms,omitempty"` // When stabilization resumes (0 = not paused)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PauseStabilizationResponse) Reset() {
+	*x = PauseStabilizationResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseStabilizationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseStabilizationResponse) ProtoMessage() {}
+
+func (x *PauseStabilizationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseStabilizationResponse.ProtoReflect.Descriptor instead.
+func (*PauseStabilizationResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PauseStabilizationResponse) GetResumeAtUnixMs() int64 {
+	if x != nil {
+		return x.ResumeAtUnixMs
+	}
+	return 0
+}
+
+type RebalanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transferred   uint32                 `protobuf:"varint,1,opt,name=transferred,proto3" json:"transferred,omitempty"` // Resources copied to their current owner
+	Failed        uint32                 `protobuf:"varint,2,opt,name=failed,proto3" json:"failed,omitempty"`           // Misplaced resources that could not be transferred
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RebalanceResponse) Reset() {
+	*x = RebalanceResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebalanceResponse) ProtoMessage() {}
+
+func (x *RebalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebalanceResponse.ProtoReflect.Descriptor instead.
+func (*RebalanceResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RebalanceResponse) GetTransferred() uint32 {
+	if x != nil {
+		return x.Transferred
+	}
+	return 0
+}
+
+func (x *RebalanceResponse) GetFailed() uint32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+type LookupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Identifier to look up
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupRequest) Reset() {
+	*x = LookupRequest{}
+	mi := &file_client_v1_client_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupRequest) ProtoMessage() {}
+
+func (x *LookupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupRequest.ProtoReflect.Descriptor instead.
+func (*LookupRequest) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *LookupRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type LookupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Successor     *NodeInfo              `protobuf:"bytes,1,opt,name=successor,proto3" json:"successor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupResponse) Reset() {
+	*x = LookupResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupResponse) ProtoMessage() {}
+
+func (x *LookupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupResponse.ProtoReflect.Descriptor instead.
+func (*LookupResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *LookupResponse) GetSuccessor() *NodeInfo {
+	if x != nil {
+		return x.Successor
+	}
+	return nil
+}
+
+type LookupTraceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Identifier to look up
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupTraceRequest) Reset() {
+	*x = LookupTraceRequest{}
+	mi := &file_client_v1_client_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupTraceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupTraceRequest) ProtoMessage() {}
+
+func (x *LookupTraceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupTraceRequest.ProtoReflect.Descriptor instead.
+func (*LookupTraceRequest) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *LookupTraceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type LookupTraceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Successor     *NodeInfo              `protobuf:"bytes,1,opt,name=successor,proto3" json:"successor,omitempty"` // Node responsible for the id
+	Path          []*NodeInfo            `protobuf:"bytes,2,rep,name=path,proto3" json:"path,omitempty"`           // Nodes that made each routing decision, in order: the contacted node first, the successor last
+	Hops          uint32                 `protobuf:"varint,3,opt,name=hops,proto3" json:"hops,omitempty"`          // Routing steps of the lookup (len(path) - 1)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupTraceResponse) Reset() {
+	*x = LookupTraceResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupTraceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupTraceResponse) ProtoMessage() {}
+
+func (x *LookupTraceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupTraceResponse.ProtoReflect.Descriptor instead.
+func (*LookupTraceResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *LookupTraceResponse) GetSuccessor() *NodeInfo {
+	if x != nil {
+		return x.Successor
+	}
+	return nil
+}
+
+func (x *LookupTraceResponse) GetPath() []*NodeInfo {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *LookupTraceResponse) GetHops() uint32 {
+	if x != nil {
+		return x.Hops
+	}
+	return 0
+}
+
+type LookupOwnershipRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Identifier to look up
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupOwnershipRequest) Reset() {
+	*x = LookupOwnershipRequest{}
+	mi := &file_client_v1_client_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupOwnershipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupOwnershipRequest) ProtoMessage() {}
+
+func (x *LookupOwnershipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupOwnershipRequest.ProtoReflect.Descriptor instead.
+func (*LookupOwnershipRequest) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *LookupOwnershipRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type LookupOwnershipResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Successor     *NodeInfo              `protobuf:"bytes,1,opt,name=successor,proto3" json:"successor,omitempty"`     // Node responsible for the id
+	Predecessor   *NodeInfo              `protobuf:"bytes,2,opt,name=predecessor,proto3" json:"predecessor,omitempty"` // Predecessor of the successor, which owns the ids in (predecessor, successor] (unset if it has none)
+	Replicas      []*NodeInfo            `protobuf:"bytes,3,rep,name=replicas,proto3" json:"replicas,omitempty"`       // Replicas of the successor: its next R-1 successors, in ring order (empty with R = 1)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupOwnershipResponse) Reset() {
+	*x = LookupOwnershipResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupOwnershipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupOwnershipResponse) ProtoMessage() {}
+
+func (x *LookupOwnershipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupOwnershipResponse.ProtoReflect.Descriptor instead.
+func (*LookupOwnershipResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *LookupOwnershipResponse) GetSuccessor() *NodeInfo {
+	if x != nil {
+		return x.Successor
+	}
+	return nil
+}
+
+func (x *LookupOwnershipResponse) GetPredecessor() *NodeInfo {
+	if x != nil {
+		return x.Predecessor
+	}
+	return nil
+}
+
+func (x *LookupOwnershipResponse) GetReplicas() []*NodeInfo {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+// Shutdown of the node (admin RPC, see node.adminRPC).
+type ShutdownRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TimeoutSeconds uint32                 `protobuf:"varint,1,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // Maximum time to wait for the handoff of the resources (0 = 30 seconds)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ShutdownRequest) Reset() {
+	*x = ShutdownRequest{}
+	mi := &file_client_v1_client_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShutdownRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShutdownRequest) ProtoMessage() {}
+
+func (x *ShutdownRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShutdownRequest.ProtoReflect.Descriptor instead.
+func (*ShutdownRequest) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ShutdownRequest) GetTimeoutSeconds() uint32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+type ShutdownResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	HandoffCompleted bool                   `protobuf:"varint,1,opt,name=handoff_completed,json=handoffCompleted,proto3" json:"handoff_completed,omitempty"` // The node left the ring within the timeout
+	Detail           string                 `protobuf:"bytes,2,opt,name=detail,proto3" json:"detail,omitempty"`                                              // Why the handoff is not complete (timeout or leave error)
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ShutdownResponse) Reset() {
+	*x = ShutdownResponse{}
+	mi := &file_client_v1_client_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShutdownResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShutdownResponse) ProtoMessage() {}
+
+func (x *ShutdownResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_client_v1_client_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShutdownResponse.ProtoReflect.Descriptor instead.
+func (*ShutdownResponse) Descriptor() ([]byte, []int) {
+	return file_client_v1_client_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ShutdownResponse) GetHandoffCompleted() bool {
+	if x != nil {
+		return x.HandoffCompleted
+	}
+	return false
+}
+
+func (x *ShutdownResponse) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+var File_client_v1_client_proto protoreflect.FileDescriptor
+
+const file_client_v1_client_proto_rawDesc = "" +
+	"\n" +
+	"\x16client/v1/client.proto\x12\tclient.v1\x1a\x1bgoogle/protobuf/empty.proto\"\xd6\x01\n" +
+	"\bResource\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x16\n" +
+	"\x06origin\x18\x03 \x01(\tR\x06origin\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\tR\x02id\x12=\n" +
+	"\bmetadata\x18\x05 \x03(\v2!.client.v1.Resource.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"^\n" +
+	"\n" +
+	"PutRequest\x12/\n" +
+	"\bresource\x18\x01 \x01(\v2\x13.client.v1.ResourceR\bresource\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\rR\n" +
+	"ttlSeconds\"\xb4\x01\n" +
+	"\fPutIfRequest\x12/\n" +
+	"\bresource\x18\x01 \x01(\v2\x13.client.v1.ResourceR\bresource\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\rR\n" +
+	"ttlSeconds\x12\x1b\n" +
+	"\tif_absent\x18\x03 \x01(\bR\bifAbsent\x12\x19\n" +
+	"\bif_equal\x18\x04 \x01(\bR\aifEqual\x12\x1a\n" +
+	"\bexpected\x18\x05 \x01(\tR\bexpected\"J\n" +
+	"\x11PutContentRequest\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\rR\n" +
+	"ttlSeconds\"$\n" +
+	"\x12PutContentResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\".\n" +
+	"\n" +
+	"GetRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"\xba\x01\n" +
+	"\vGetResponse\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x16\n" +
+	"\x06origin\x18\x02 \x01(\tR\x06origin\x12@\n" +
+	"\bmetadata\x18\x03 \x03(\v2$.client.v1.GetResponse.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"1\n" +
+	"\rDeleteRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"8\n" +
+	"\x12DeleteRangeRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\"/\n" +
+	"\x13DeleteRangeResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\x04R\adeleted\"U\n" +
+	"\x11BatchDeleteResult\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\adeleted\x18\x02 \x01(\bR\adeleted\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"9\n" +
+	"\x0fBatchPutFailure\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"^\n" +
+	"\x10BatchPutResponse\x12\x16\n" +
+	"\x06stored\x18\x01 \x01(\rR\x06stored\x122\n" +
+	"\x06failed\x18\x02 \x03(\v2\x1a.client.v1.BatchPutFailureR\x06failed\"%\n" +
+	"\x0fBatchGetRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\"\xa3\x02\n" +
+	"\x10BatchGetResponse\x12<\n" +
+	"\x05found\x18\x01 \x03(\v2&.client.v1.BatchGetResponse.FoundEntryR\x05found\x12\x1b\n" +
+	"\tnot_found\x18\x02 \x03(\tR\bnotFound\x12?\n" +
+	"\x06failed\x18\x03 \x03(\v2'.client.v1.BatchGetResponse.FailedEntryR\x06failed\x1a8\n" +
+	"\n" +
+	"FoundEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a9\n" +
+	"\vFailedEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\".\n" +
+	"\bNodeInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04addr\x18\x02 \x01(\tR\x04addr\"K\n" +
+	"\x10GetStoreResponse\x12'\n" +
+	"\x04item\x18\x01 \x01(\v2\x13.client.v1.ResourceR\x04item\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"b\n" +
+	"\x13GetStorePageRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit\"q\n" +
+	"\x14GetStorePageResponse\x121\n" +
+	"\x05items\x18\x01 \x03(\v2\x1b.client.v1.GetStoreResponseR\x05items\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xa5\x02\n" +
+	"\x17GetRoutingTableResponse\x12'\n" +
+	"\x04self\x18\x01 \x01(\v2\x13.client.v1.NodeInfoR\x04self\x125\n" +
+	"\vpredecessor\x18\x02 \x01(\v2\x13.client.v1.NodeInfoR\vpredecessor\x123\n" +
+	"\n" +
 	"successors\x18\x03 \x03(\v2\x13.client.v1.NodeInfoR\n" +
 	"successors\x129\n" +
-	"\x0ede_bruijn_list\x18\x04 \x03(\v2\x13.client.v1.NodeInfoR\fdeBruijnList\"\x1f\n" +
+	"\x0ede_bruijn_list\x18\x04 \x03(\v2\x13.client.v1.NodeInfoR\fdeBruijnList\x12:\n" +
+	"\x06vnodes\x18\x05 \x03(\v2\".client.v1.GetRoutingTableResponseR\x06vnodes\"\xc4\x03\n" +
+	"\fInfoResponse\x12'\n" +
+	"\x04self\x18\x01 \x01(\v2\x13.client.v1.NodeInfoR\x04self\x125\n" +
+	"\vpredecessor\x18\x02 \x01(\v2\x13.client.v1.NodeInfoR\vpredecessor\x12'\n" +
+	"\x0fsuccessor_count\x18\x03 \x01(\rR\x0esuccessorCount\x12(\n" +
+	"\x10de_bruijn_filled\x18\x04 \x01(\rR\x0edeBruijnFilled\x12(\n" +
+	"\x10de_bruijn_degree\x18\x05 \x01(\rR\x0edeBruijnDegree\x12\x1f\n" +
+	"\vstored_keys\x18\x06 \x01(\x04R\n" +
+	"storedKeys\x12\x1b\n" +
+	"\tuptime_ms\x18\a \x01(\x03R\buptimeMs\x12-\n" +
+	"\x12pooled_connections\x18\b \x01(\rR\x11pooledConnections\x12\x19\n" +
+	"\bmax_keys\x18\t \x01(\x04R\amaxKeys\x12,\n" +
+	"\x12outbound_in_flight\x18\n" +
+	" \x01(\rR\x10outboundInFlight\x12!\n" +
+	"\fmax_outbound\x18\v \x01(\rR\vmaxOutbound\"\x9b\x01\n" +
+	"\x10GetSpaceResponse\x12\x17\n" +
+	"\aid_bits\x18\x01 \x01(\rR\x06idBits\x12\x16\n" +
+	"\x06degree\x18\x02 \x01(\rR\x06degree\x12$\n" +
+	"\x0esucc_list_size\x18\x03 \x01(\rR\fsuccListSize\x12\x12\n" +
+	"\x04hash\x18\x04 \x01(\tR\x04hash\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\"5\n" +
+	"\vConfigEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"E\n" +
+	"\x11GetConfigResponse\x120\n" +
+	"\aentries\x18\x01 \x03(\v2\x16.client.v1.ConfigEntryR\aentries\"r\n" +
+	"\x19PauseStabilizationRequest\x12\x1f\n" +
+	"\vduration_ms\x18\x01 \x01(\x04R\n" +
+	"durationMs\x124\n" +
+	"\x16keep_failure_detection\x18\x02 \x01(\bR\x14keepFailureDetection\"G\n" +
+	"\x1aPauseStabilizationResponse\x12)\n" +
+	"\x11resume_at_unix_ms\x18\x01 \x01(\x03R\x0eresumeAtUnixMs\"M\n" +
+	"\x11RebalanceResponse\x12 \n" +
+	"\vtransferred\x18\x01 \x01(\rR\vtransferred\x12\x16\n" +
+	"\x06failed\x18\x02 \x01(\rR\x06failed\"\x1f\n" +
 	"\rLookupRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"C\n" +
 	"\x0eLookupResponse\x121\n" +
-	"\tsuccessor\x18\x01 \x01(\v2\x13.client.v1.NodeInfoR\tsuccessor2\x84\x03\n" +
+	"\tsuccessor\x18\x01 \x01(\v2\x13.client.v1.NodeInfoR\tsuccessor\"$\n" +
+	"\x12LookupTraceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x85\x01\n" +
+	"\x13LookupTraceResponse\x121\n" +
+	"\tsuccessor\x18\x01 \x01(\v2\x13.client.v1.NodeInfoR\tsuccessor\x12'\n" +
+	"\x04path\x18\x02 \x03(\v2\x13.client.v1.NodeInfoR\x04path\x12\x12\n" +
+	"\x04hops\x18\x03 \x01(\rR\x04hops\"(\n" +
+	"\x16LookupOwnershipRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xb4\x01\n" +
+	"\x17LookupOwnershipResponse\x121\n" +
+	"\tsuccessor\x18\x01 \x01(\v2\x13.client.v1.NodeInfoR\tsuccessor\x125\n" +
+	"\vpredecessor\x18\x02 \x01(\v2\x13.client.v1.NodeInfoR\vpredecessor\x12/\n" +
+	"\breplicas\x18\x03 \x03(\v2\x13.client.v1.NodeInfoR\breplicas\":\n" +
+	"\x0fShutdownRequest\x12'\n" +
+	"\x0ftimeout_seconds\x18\x01 \x01(\rR\x0etimeoutSeconds\"W\n" +
+	"\x10ShutdownResponse\x12+\n" +
+	"\x11handoff_completed\x18\x01 \x01(\bR\x10handoffCompleted\x12\x16\n" +
+	"\x06detail\x18\x02 \x01(\tR\x06detail2\xca\v\n" +
 	"\tClientAPI\x124\n" +
-	"\x03Put\x12\x15.client.v1.PutRequest\x1a\x16.google.protobuf.Empty\x124\n" +
+	"\x03Put\x12\x15.client.v1.PutRequest\x1a\x16.google.protobuf.Empty\x128\n" +
+	"\x05PutIf\x12\x17.client.v1.PutIfRequest\x1a\x16.google.protobuf.Empty\x12I\n" +
+	"\n" +
+	"PutContent\x12\x1c.client.v1.PutContentRequest\x1a\x1d.client.v1.PutContentResponse\x124\n" +
 	"\x03Get\x12\x15.client.v1.GetRequest\x1a\x16.client.v1.GetResponse\x12:\n" +
-	"\x06Delete\x12\x18.client.v1.DeleteRequest\x1a\x16.google.protobuf.Empty\x12A\n" +
-	"\bGetStore\x12\x16.google.protobuf.Empty\x1a\x1b.client.v1.GetStoreResponse0\x01\x12M\n" +
+	"\x06Delete\x12\x18.client.v1.DeleteRequest\x1a\x16.google.protobuf.Empty\x12@\n" +
+	"\bBatchPut\x12\x15.client.v1.PutRequest\x1a\x1b.client.v1.BatchPutResponse(\x01\x12C\n" +
+	"\bBatchGet\x12\x1a.client.v1.BatchGetRequest\x1a\x1b.client.v1.BatchGetResponse\x12I\n" +
+	"\vBatchDelete\x12\x18.client.v1.DeleteRequest\x1a\x1c.client.v1.BatchDeleteResult(\x010\x01\x12L\n" +
+	"\vDeleteRange\x12\x1d.client.v1.DeleteRangeRequest\x1a\x1e.client.v1.DeleteRangeResponse\x12A\n" +
+	"\bGetStore\x12\x16.google.protobuf.Empty\x1a\x1b.client.v1.GetStoreResponse0\x01\x12O\n" +
+	"\fGetStorePage\x12\x1e.client.v1.GetStorePageRequest\x1a\x1f.client.v1.GetStorePageResponse\x12M\n" +
 	"\x0fGetRoutingTable\x12\x16.google.protobuf.Empty\x1a\".client.v1.GetRoutingTableResponse\x12=\n" +
-	"\x06Lookup\x12\x18.client.v1.LookupRequest\x1a\x19.client.v1.LookupResponseBFZDgithub.com/flaviosimonelli/KoordeDHT/internal/api/client/v1;clientv1b\x06proto3"
+	"\x06Lookup\x12\x18.client.v1.LookupRequest\x1a\x19.client.v1.LookupResponse\x12L\n" +
+	"\vLookupTrace\x12\x1d.client.v1.LookupTraceRequest\x1a\x1e.client.v1.LookupTraceResponse\x12X\n" +
+	"\x0fLookupOwnership\x12!.client.v1.LookupOwnershipRequest\x1a\".client.v1.LookupOwnershipResponse\x127\n" +
+	"\x04Info\x12\x16.google.protobuf.Empty\x1a\x17.client.v1.InfoResponse\x12?\n" +
+	"\bGetSpace\x12\x16.google.protobuf.Empty\x1a\x1b.client.v1.GetSpaceResponse\x12A\n" +
+	"\tGetConfig\x12\x16.google.protobuf.Empty\x1a\x1c.client.v1.GetConfigResponse\x12a\n" +
+	"\x12PauseStabilization\x12$.client.v1.PauseStabilizationRequest\x1a%.client.v1.PauseStabilizationResponse\x12C\n" +
+	"\bShutdown\x12\x1a.client.v1.ShutdownRequest\x1a\x1b.client.v1.ShutdownResponse\x12A\n" +
+	"\tRebalance\x12\x16.google.protobuf.Empty\x1a\x1c.client.v1.RebalanceResponseBFZDgithub.com/flaviosimonelli/KoordeDHT/internal/api/client/v1;clientv1b\x06proto3"
 
 var (
 	file_client_v1_client_proto_rawDescOnce sync.Once
@@ -568,45 +2173,120 @@ func file_client_v1_client_proto_rawDescGZIP() []byte {
 	return file_client_v1_client_proto_rawDescData
 }
 
-var file_client_v1_client_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_client_v1_client_proto_msgTypes = make([]protoimpl.MessageInfo, 39)
 var file_client_v1_client_proto_goTypes = []any{
-	(*Resource)(nil),                // 0: client.v1.Resource
-	(*PutRequest)(nil),              // 1: client.v1.PutRequest
-	(*GetRequest)(nil),              // 2: client.v1.GetRequest
-	(*GetResponse)(nil),             // 3: client.v1.GetResponse
-	(*DeleteRequest)(nil),           // 4: client.v1.DeleteRequest
-	(*NodeInfo)(nil),                // 5: client.v1.NodeInfo
-	(*GetStoreResponse)(nil),        // 6: client.v1.GetStoreResponse
-	(*GetRoutingTableResponse)(nil), // 7: client.v1.GetRoutingTableResponse
-	(*LookupRequest)(nil),           // 8: client.v1.LookupRequest
-	(*LookupResponse)(nil),          // 9: client.v1.LookupResponse
-	(*emptypb.Empty)(nil),           // 10: google.protobuf.Empty
+	(*Resource)(nil),                   // 0: client.v1.Resource
+	(*PutRequest)(nil),                 // 1: client.v1.PutRequest
+	(*PutIfRequest)(nil),               // 2: client.v1.PutIfRequest
+	(*PutContentRequest)(nil),          // 3: client.v1.PutContentRequest
+	(*PutContentResponse)(nil),         // 4: client.v1.PutContentResponse
+	(*GetRequest)(nil),                 // 5: client.v1.GetRequest
+	(*GetResponse)(nil),                // 6: client.v1.GetResponse
+	(*DeleteRequest)(nil),              // 7: client.v1.DeleteRequest
+	(*DeleteRangeRequest)(nil),         // 8: client.v1.DeleteRangeRequest
+	(*DeleteRangeResponse)(nil),        // 9: client.v1.DeleteRangeResponse
+	(*BatchDeleteResult)(nil),          // 10: client.v1.BatchDeleteResult
+	(*BatchPutFailure)(nil),            // 11: client.v1.BatchPutFailure
+	(*BatchPutResponse)(nil),           // 12: client.v1.BatchPutResponse
+	(*BatchGetRequest)(nil),            // 13: client.v1.BatchGetRequest
+	(*BatchGetResponse)(nil),           // 14: client.v1.BatchGetResponse
+	(*NodeInfo)(nil),                   // 15: client.v1.NodeInfo
+	(*GetStoreResponse)(nil),           // 16: client.v1.GetStoreResponse
+	(*GetStorePageRequest)(nil),        // 17: client.v1.GetStorePageRequest
+	(*GetStorePageResponse)(nil),       // 18: client.v1.GetStorePageResponse
+	(*GetRoutingTableResponse)(nil),    // 19: client.v1.GetRoutingTableResponse
+	(*InfoResponse)(nil),               // 20: client.v1.InfoResponse
+	(*GetSpaceResponse)(nil),           // 21: client.v1.GetSpaceResponse
+	(*ConfigEntry)(nil),                // 22: client.v1.ConfigEntry
+	(*GetConfigResponse)(nil),          // 23: client.v1.GetConfigResponse
+	(*PauseStabilizationRequest)(nil),  // 24: client.v1.PauseStabilizationRequest
+	(*PauseStabilizationResponse)(nil), // 25: client.v1.PauseStabilizationResponse
+	(*RebalanceResponse)(nil),          // 26: client.v1.RebalanceResponse
+	(*LookupRequest)(nil),              // 27: client.v1.LookupRequest
+	(*LookupResponse)(nil),             // 28: client.v1.LookupResponse
+	(*LookupTraceRequest)(nil),         // 29: client.v1.LookupTraceRequest
+	(*LookupTraceResponse)(nil),        // 30: client.v1.LookupTraceResponse
+	(*LookupOwnershipRequest)(nil),     // 31: client.v1.LookupOwnershipRequest
+	(*LookupOwnershipResponse)(nil),    // 32: client.v1.LookupOwnershipResponse
+	(*ShutdownRequest)(nil),            // 33: client.v1.ShutdownRequest
+	(*ShutdownResponse)(nil),           // 34: client.v1.ShutdownResponse
+	nil,                                // 35: client.v1.Resource.MetadataEntry
+	nil,                                // 36: client.v1.GetResponse.MetadataEntry
+	nil,                                // 37: client.v1.BatchGetResponse.FoundEntry
+	nil,                                // 38: client.v1.BatchGetResponse.FailedEntry
+	(*emptypb.Empty)(nil),              // 39: google.protobuf.Empty
 }
 var file_client_v1_client_proto_depIdxs = []int32{
-	0,  // 0: client.v1.PutRequest.resource:type_name -> client.v1.Resource
-	0,  // 1: client.v1.GetStoreResponse.item:type_name -> client.v1.Resource
-	5,  // 2: client.v1.GetRoutingTableResponse.self:type_name -> client.v1.NodeInfo
-	5,  // 3: client.v1.GetRoutingTableResponse.predecessor:type_name -> client.v1.NodeInfo
-	5,  // 4: client.v1.GetRoutingTableResponse.successors:type_name -> client.v1.NodeInfo
-	5,  // 5: client.v1.GetRoutingTableResponse.de_bruijn_list:type_name -> client.v1.NodeInfo
-	5,  // 6: client.v1.LookupResponse.successor:type_name -> client.v1.NodeInfo
-	1,  // 7: client.v1.ClientAPI.Put:input_type -> client.v1.PutRequest
-	2,  // 8: client.v1.ClientAPI.Get:input_type -> client.v1.GetRequest
-	4,  // 9: client.v1.ClientAPI.Delete:input_type -> client.v1.DeleteRequest
-	10, // 10: client.v1.ClientAPI.GetStore:input_type -> google.protobuf.Empty
-	10, // 11: client.v1.ClientAPI.GetRoutingTable:input_type -> google.protobuf.Empty
-	8,  // 12: client.v1.ClientAPI.Lookup:input_type -> client.v1.LookupRequest
-	10, // 13: client.v1.ClientAPI.Put:output_type -> google.protobuf.Empty
-	3,  // 14: client.v1.ClientAPI.Get:output_type -> client.v1.GetResponse
-	10, // 15: client.v1.ClientAPI.Delete:output_type -> google.protobuf.Empty
-	6,  // 16: client.v1.ClientAPI.GetStore:output_type -> client.v1.GetStoreResponse
-	7,  // 17: client.v1.ClientAPI.GetRoutingTable:output_type -> client.v1.GetRoutingTableResponse
-	9,  // 18: client.v1.ClientAPI.Lookup:output_type -> client.v1.LookupResponse
-	13, // [13:19] is the sub-list for method output_type
-	7,  // [7:13] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	35, // 0: client.v1.Resource.metadata:type_name -> client.v1.Resource.MetadataEntry
+	0,  // 1: client.v1.PutRequest.resource:type_name -> client.v1.Resource
+	0,  // 2: client.v1.PutIfRequest.resource:type_name -> client.v1.Resource
+	36, // 3: client.v1.GetResponse.metadata:type_name -> client.v1.GetResponse.MetadataEntry
+	11, // 4: client.v1.BatchPutResponse.failed:type_name -> client.v1.BatchPutFailure
+	37, // 5: client.v1.BatchGetResponse.found:type_name -> client.v1.BatchGetResponse.FoundEntry
+	38, // 6: client.v1.BatchGetResponse.failed:type_name -> client.v1.BatchGetResponse.FailedEntry
+	0,  // 7: client.v1.GetStoreResponse.item:type_name -> client.v1.Resource
+	16, // 8: client.v1.GetStorePageResponse.items:type_name -> client.v1.GetStoreResponse
+	15, // 9: client.v1.GetRoutingTableResponse.self:type_name -> client.v1.NodeInfo
+	15, // 10: client.v1.GetRoutingTableResponse.predecessor:type_name -> client.v1.NodeInfo
+	15, // 11: client.v1.GetRoutingTableResponse.successors:type_name -> client.v1.NodeInfo
+	15, // 12: client.v1.GetRoutingTableResponse.de_bruijn_list:type_name -> client.v1.NodeInfo
+	19, // 13: client.v1.GetRoutingTableResponse.vnodes:type_name -> client.v1.GetRoutingTableResponse
+	15, // 14: client.v1.InfoResponse.self:type_name -> client.v1.NodeInfo
+	15, // 15: client.v1.InfoResponse.predecessor:type_name -> client.v1.NodeInfo
+	22, // 16: client.v1.GetConfigResponse.entries:type_name -> client.v1.ConfigEntry
+	15, // 17: client.v1.LookupResponse.successor:type_name -> client.v1.NodeInfo
+	15, // 18: client.v1.LookupTraceResponse.successor:type_name -> client.v1.NodeInfo
+	15, // 19: client.v1.LookupTraceResponse.path:type_name -> client.v1.NodeInfo
+	15, // 20: client.v1.LookupOwnershipResponse.successor:type_name -> client.v1.NodeInfo
+	15, // 21: client.v1.LookupOwnershipResponse.predecessor:type_name -> client.v1.NodeInfo
+	15, // 22: client.v1.LookupOwnershipResponse.replicas:type_name -> client.v1.NodeInfo
+	1,  // 23: client.v1.ClientAPI.Put:input_type -> client.v1.PutRequest
+	2,  // 24: client.v1.ClientAPI.PutIf:input_type -> client.v1.PutIfRequest
+	3,  // 25: client.v1.ClientAPI.PutContent:input_type -> client.v1.PutContentRequest
+	5,  // 26: client.v1.ClientAPI.Get:input_type -> client.v1.GetRequest
+	7,  // 27: client.v1.ClientAPI.Delete:input_type -> client.v1.DeleteRequest
+	1,  // 28: client.v1.ClientAPI.BatchPut:input_type -> client.v1.PutRequest
+	13, // 29: client.v1.ClientAPI.BatchGet:input_type -> client.v1.BatchGetRequest
+	7,  // 30: client.v1.ClientAPI.BatchDelete:input_type -> client.v1.DeleteRequest
+	8,  // 31: client.v1.ClientAPI.DeleteRange:input_type -> client.v1.DeleteRangeRequest
+	39, // 32: client.v1.ClientAPI.GetStore:input_type -> google.protobuf.Empty
+	17, // 33: client.v1.ClientAPI.GetStorePage:input_type -> client.v1.GetStorePageRequest
+	39, // 34: client.v1.ClientAPI.GetRoutingTable:input_type -> google.protobuf.Empty
+	27, // 35: client.v1.ClientAPI.Lookup:input_type -> client.v1.LookupRequest
+	29, // 36: client.v1.ClientAPI.LookupTrace:input_type -> client.v1.LookupTraceRequest
+	31, // 37: client.v1.ClientAPI.LookupOwnership:input_type -> client.v1.LookupOwnershipRequest
+	39, // 38: client.v1.ClientAPI.Info:input_type -> google.protobuf.Empty
+	39, // 39: client.v1.ClientAPI.GetSpace:input_type -> google.protobuf.Empty
+	39, // 40: client.v1.ClientAPI.GetConfig:input_type -> google.protobuf.Empty
+	24, // 41: client.v1.ClientAPI.PauseStabilization:input_type -> client.v1.PauseStabilizationRequest
+	33, // 42: client.v1.ClientAPI.Shutdown:input_type -> client.v1.ShutdownRequest
+	39, // 43: client.v1.ClientAPI.Rebalance:input_type -> google.protobuf.Empty
+	39, // 44: client.v1.ClientAPI.Put:output_type -> google.protobuf.Empty
+	39, // 45: client.v1.ClientAPI.PutIf:output_type -> google.protobuf.Empty
+	4,  // 46: client.v1.ClientAPI.PutContent:output_type -> client.v1.PutContentResponse
+	6,  // 47: client.v1.ClientAPI.Get:output_type -> client.v1.GetResponse
+	39, // 48: client.v1.ClientAPI.Delete:output_type -> google.protobuf.Empty
+	12, // 49: client.v1.ClientAPI.BatchPut:output_type -> client.v1.BatchPutResponse
+	14, // 50: client.v1.ClientAPI.BatchGet:output_type -> client.v1.BatchGetResponse
+	10, // 51: client.v1.ClientAPI.BatchDelete:output_type -> client.v1.BatchDeleteResult
+	9,  // 52: client.v1.ClientAPI.DeleteRange:output_type -> client.v1.DeleteRangeResponse
+	16, // 53: client.v1.ClientAPI.GetStore:output_type -> client.v1.GetStoreResponse
+	18, // 54: client.v1.ClientAPI.GetStorePage:output_type -> client.v1.GetStorePageResponse
+	19, // 55: client.v1.ClientAPI.GetRoutingTable:output_type -> client.v1.GetRoutingTableResponse
+	28, // 56: client.v1.ClientAPI.Lookup:output_type -> client.v1.LookupResponse
+	30, // 57: client.v1.ClientAPI.LookupTrace:output_type -> client.v1.LookupTraceResponse
+	32, // 58: client.v1.ClientAPI.LookupOwnership:output_type -> client.v1.LookupOwnershipResponse
+	20, // 59: client.v1.ClientAPI.Info:output_type -> client.v1.InfoResponse
+	21, // 60: client.v1.ClientAPI.GetSpace:output_type -> client.v1.GetSpaceResponse
+	23, // 61: client.v1.ClientAPI.GetConfig:output_type -> client.v1.GetConfigResponse
+	25, // 62: client.v1.ClientAPI.PauseStabilization:output_type -> client.v1.PauseStabilizationResponse
+	34, // 63: client.v1.ClientAPI.Shutdown:output_type -> client.v1.ShutdownResponse
+	26, // 64: client.v1.ClientAPI.Rebalance:output_type -> client.v1.RebalanceResponse
+	44, // [44:65] is the sub-list for method output_type
+	23, // [23:44] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_client_v1_client_proto_init() }
@@ -620,7 +2300,7 @@ func file_client_v1_client_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_client_v1_client_proto_rawDesc), len(file_client_v1_client_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   39,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -20,12 +20,27 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ClientAPI_Put_FullMethodName             = "/client.v1.ClientAPI/Put"
-	ClientAPI_Get_FullMethodName             = "/client.v1.ClientAPI/Get"
-	ClientAPI_Delete_FullMethodName          = "/client.v1.ClientAPI/Delete"
-	ClientAPI_GetStore_FullMethodName        = "/client.v1.ClientAPI/GetStore"
-	ClientAPI_GetRoutingTable_FullMethodName = "/client.v1.ClientAPI/GetRoutingTable"
-	ClientAPI_Lookup_FullMethodName          = "/client.v1.ClientAPI/Lookup"
+	ClientAPI_Put_FullMethodName                = "/client.v1.ClientAPI/Put"
+	ClientAPI_PutIf_FullMethodName              = "/client.v1.ClientAPI/PutIf"
+	ClientAPI_PutContent_FullMethodName         = "/client.v1.ClientAPI/PutContent"
+	ClientAPI_Get_FullMethodName                = "/client.v1.ClientAPI/Get"
+	ClientAPI_Delete_FullMethodName             = "/client.v1.ClientAPI/Delete"
+	ClientAPI_BatchPut_FullMethodName           = "/client.v1.ClientAPI/BatchPut"
+	ClientAPI_BatchGet_FullMethodName           = "/client.v1.ClientAPI/BatchGet"
+	ClientAPI_BatchDelete_FullMethodName        = "/client.v1.ClientAPI/BatchDelete"
+	ClientAPI_DeleteRange_FullMethodName        = "/client.v1.ClientAPI/DeleteRange"
+	ClientAPI_GetStore_FullMethodName           = "/client.v1.ClientAPI/GetStore"
+	ClientAPI_GetStorePage_FullMethodName       = "/client.v1.ClientAPI/GetStorePage"
+	ClientAPI_GetRoutingTable_FullMethodName    = "/client.v1.ClientAPI/GetRoutingTable"
+	ClientAPI_Lookup_FullMethodName             = "/client.v1.ClientAPI/Lookup"
+	ClientAPI_LookupTrace_FullMethodName        = "/client.v1.ClientAPI/LookupTrace"
+	ClientAPI_LookupOwnership_FullMethodName    = "/client.v1.ClientAPI/LookupOwnership"
+	ClientAPI_Info_FullMethodName               = "/client.v1.ClientAPI/Info"
+	ClientAPI_GetSpace_FullMethodName           = "/client.v1.ClientAPI/GetSpace"
+	ClientAPI_GetConfig_FullMethodName          = "/client.v1.ClientAPI/GetConfig"
+	ClientAPI_PauseStabilization_FullMethodName = "/client.v1.ClientAPI/PauseStabilization"
+	ClientAPI_Shutdown_FullMethodName           = "/client.v1.ClientAPI/Shutdown"
+	ClientAPI_Rebalance_FullMethodName          = "/client.v1.ClientAPI/Rebalance"
 )
 
 // ClientAPIClient is the client API for ClientAPI service.
@@ -34,12 +49,28 @@ const (
 type ClientAPIClient interface {
 	// KV storage
 	Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	PutIf(ctx context.Context, in *PutIfRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	PutContent(ctx context.Context, in *PutContentRequest, opts ...grpc.CallOption) (*PutContentResponse, error)
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	BatchPut(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[PutRequest, BatchPutResponse], error)
+	BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error)
+	BatchDelete(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DeleteRequest, BatchDeleteResult], error)
+	DeleteRange(ctx context.Context, in *DeleteRangeRequest, opts ...grpc.CallOption) (*DeleteRangeResponse, error)
 	// Demonstrative
 	GetStore(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetStoreResponse], error)
+	GetStorePage(ctx context.Context, in *GetStorePageRequest, opts ...grpc.CallOption) (*GetStorePageResponse, error)
 	GetRoutingTable(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetRoutingTableResponse, error)
 	Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupResponse, error)
+	LookupTrace(ctx context.Context, in *LookupTraceRequest, opts ...grpc.CallOption) (*LookupTraceResponse, error)
+	LookupOwnership(ctx context.Context, in *LookupOwnershipRequest, opts ...grpc.CallOption) (*LookupOwnershipResponse, error)
+	Info(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*InfoResponse, error)
+	GetSpace(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetSpaceResponse, error)
+	// Admin
+	GetConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetConfigResponse, error)
+	PauseStabilization(ctx context.Context, in *PauseStabilizationRequest, opts ...grpc.CallOption) (*PauseStabilizationResponse, error)
+	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
+	Rebalance(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RebalanceResponse, error)
 }
 
 type clientAPIClient struct {
@@ -60,6 +91,26 @@ func (c *clientAPIClient) Put(ctx context.Context, in *PutRequest, opts ...grpc.
 	return out, nil
 }
 
+func (c *clientAPIClient) PutIf(ctx context.Context, in *PutIfRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ClientAPI_PutIf_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) PutContent(ctx context.Context, in *PutContentRequest, opts ...grpc.CallOption) (*PutContentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PutContentResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_PutContent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *clientAPIClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetResponse)
@@ -80,9 +131,55 @@ func (c *clientAPIClient) Delete(ctx context.Context, in *DeleteRequest, opts ..
 	return out, nil
 }
 
+func (c *clientAPIClient) BatchPut(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[PutRequest, BatchPutResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ClientAPI_ServiceDesc.Streams[0], ClientAPI_BatchPut_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PutRequest, BatchPutResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ClientAPI_BatchPutClient = grpc.ClientStreamingClient[PutRequest, BatchPutResponse]
+
+func (c *clientAPIClient) BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_BatchGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) BatchDelete(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DeleteRequest, BatchDeleteResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ClientAPI_ServiceDesc.Streams[1], ClientAPI_BatchDelete_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DeleteRequest, BatchDeleteResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ClientAPI_BatchDeleteClient = grpc.BidiStreamingClient[DeleteRequest, BatchDeleteResult]
+
+func (c *clientAPIClient) DeleteRange(ctx context.Context, in *DeleteRangeRequest, opts ...grpc.CallOption) (*DeleteRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRangeResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_DeleteRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *clientAPIClient) GetStore(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetStoreResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &ClientAPI_ServiceDesc.Streams[0], ClientAPI_GetStore_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &ClientAPI_ServiceDesc.Streams[2], ClientAPI_GetStore_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -99,6 +196,16 @@ func (c *clientAPIClient) GetStore(ctx context.Context, in *emptypb.Empty, opts
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ClientAPI_GetStoreClient = grpc.ServerStreamingClient[GetStoreResponse]
 
+func (c *clientAPIClient) GetStorePage(ctx context.Context, in *GetStorePageRequest, opts ...grpc.CallOption) (*GetStorePageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStorePageResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_GetStorePage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *clientAPIClient) GetRoutingTable(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetRoutingTableResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetRoutingTableResponse)
@@ -119,18 +226,114 @@ func (c *clientAPIClient) Lookup(ctx context.Context, in *LookupRequest, opts ..
 	return out, nil
 }
 
+func (c *clientAPIClient) LookupTrace(ctx context.Context, in *LookupTraceRequest, opts ...grpc.CallOption) (*LookupTraceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupTraceResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_LookupTrace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) LookupOwnership(ctx context.Context, in *LookupOwnershipRequest, opts ...grpc.CallOption) (*LookupOwnershipResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupOwnershipResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_LookupOwnership_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) Info(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*InfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InfoResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_Info_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) GetSpace(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetSpaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSpaceResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_GetSpace_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) GetConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetConfigResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_GetConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) PauseStabilization(ctx context.Context, in *PauseStabilizationRequest, opts ...grpc.CallOption) (*PauseStabilizationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseStabilizationResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_PauseStabilization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShutdownResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_Shutdown_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientAPIClient) Rebalance(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*RebalanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RebalanceResponse)
+	err := c.cc.Invoke(ctx, ClientAPI_Rebalance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ClientAPIServer is the server API for ClientAPI service.
 // All implementations must embed UnimplementedClientAPIServer
 // for forward compatibility.
 type ClientAPIServer interface {
 	// KV storage
 	Put(context.Context, *PutRequest) (*emptypb.Empty, error)
+	PutIf(context.Context, *PutIfRequest) (*emptypb.Empty, error)
+	PutContent(context.Context, *PutContentRequest) (*PutContentResponse, error)
 	Get(context.Context, *GetRequest) (*GetResponse, error)
 	Delete(context.Context, *DeleteRequest) (*emptypb.Empty, error)
+	BatchPut(grpc.ClientStreamingServer[PutRequest, BatchPutResponse]) error
+	BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error)
+	BatchDelete(grpc.BidiStreamingServer[DeleteRequest, BatchDeleteResult]) error
+	DeleteRange(context.Context, *DeleteRangeRequest) (*DeleteRangeResponse, error)
 	// Demonstrative
 	GetStore(*emptypb.Empty, grpc.ServerStreamingServer[GetStoreResponse]) error
+	GetStorePage(context.Context, *GetStorePageRequest) (*GetStorePageResponse, error)
 	GetRoutingTable(context.Context, *emptypb.Empty) (*GetRoutingTableResponse, error)
 	Lookup(context.Context, *LookupRequest) (*LookupResponse, error)
+	LookupTrace(context.Context, *LookupTraceRequest) (*LookupTraceResponse, error)
+	LookupOwnership(context.Context, *LookupOwnershipRequest) (*LookupOwnershipResponse, error)
+	Info(context.Context, *emptypb.Empty) (*InfoResponse, error)
+	GetSpace(context.Context, *emptypb.Empty) (*GetSpaceResponse, error)
+	// Admin
+	GetConfig(context.Context, *emptypb.Empty) (*GetConfigResponse, error)
+	PauseStabilization(context.Context, *PauseStabilizationRequest) (*PauseStabilizationResponse, error)
+	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
+	Rebalance(context.Context, *emptypb.Empty) (*RebalanceResponse, error)
 	mustEmbedUnimplementedClientAPIServer()
 }
 
@@ -144,21 +347,66 @@ type UnimplementedClientAPIServer struct{}
 func (UnimplementedClientAPIServer) Put(context.Context, *PutRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Put not implemented")
 }
+func (UnimplementedClientAPIServer) PutIf(context.Context, *PutIfRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutIf not implemented")
+}
+func (UnimplementedClientAPIServer) PutContent(context.Context, *PutContentRequest) (*PutContentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutContent not implemented")
+}
 func (UnimplementedClientAPIServer) Get(context.Context, *GetRequest) (*GetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
 }
 func (UnimplementedClientAPIServer) Delete(context.Context, *DeleteRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedClientAPIServer) BatchPut(grpc.ClientStreamingServer[PutRequest, BatchPutResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BatchPut not implemented")
+}
+func (UnimplementedClientAPIServer) BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGet not implemented")
+}
+func (UnimplementedClientAPIServer) BatchDelete(grpc.BidiStreamingServer[DeleteRequest, BatchDeleteResult]) error {
+	return status.Errorf(codes.Unimplemented, "method BatchDelete not implemented")
+}
+func (UnimplementedClientAPIServer) DeleteRange(context.Context, *DeleteRangeRequest) (*DeleteRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRange not implemented")
+}
 func (UnimplementedClientAPIServer) GetStore(*emptypb.Empty, grpc.ServerStreamingServer[GetStoreResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method GetStore not implemented")
 }
+func (UnimplementedClientAPIServer) GetStorePage(context.Context, *GetStorePageRequest) (*GetStorePageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStorePage not implemented")
+}
 func (UnimplementedClientAPIServer) GetRoutingTable(context.Context, *emptypb.Empty) (*GetRoutingTableResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRoutingTable not implemented")
 }
 func (UnimplementedClientAPIServer) Lookup(context.Context, *LookupRequest) (*LookupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Lookup not implemented")
 }
+func (UnimplementedClientAPIServer) LookupTrace(context.Context, *LookupTraceRequest) (*LookupTraceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupTrace not implemented")
+}
+func (UnimplementedClientAPIServer) LookupOwnership(context.Context, *LookupOwnershipRequest) (*LookupOwnershipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupOwnership not implemented")
+}
+func (UnimplementedClientAPIServer) Info(context.Context, *emptypb.Empty) (*InfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Info not implemented")
+}
+func (UnimplementedClientAPIServer) GetSpace(context.Context, *emptypb.Empty) (*GetSpaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSpace not implemented")
+}
+func (UnimplementedClientAPIServer) GetConfig(context.Context, *emptypb.Empty) (*GetConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
+func (UnimplementedClientAPIServer) PauseStabilization(context.Context, *PauseStabilizationRequest) (*PauseStabilizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseStabilization not implemented")
+}
+func (UnimplementedClientAPIServer) Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Shutdown not implemented")
+}
+func (UnimplementedClientAPIServer) Rebalance(context.Context, *emptypb.Empty) (*RebalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rebalance not implemented")
+}
 func (UnimplementedClientAPIServer) mustEmbedUnimplementedClientAPIServer() {}
 func (UnimplementedClientAPIServer) testEmbeddedByValue()                   {}
 
@@ -198,6 +446,42 @@ func _ClientAPI_Put_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClientAPI_PutIf_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutIfRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).PutIf(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_PutIf_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).PutIf(ctx, req.(*PutIfRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_PutContent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutContentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).PutContent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_PutContent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).PutContent(ctx, req.(*PutContentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ClientAPI_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetRequest)
 	if err := dec(in); err != nil {
@@ -234,6 +518,56 @@ func _ClientAPI_Delete_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClientAPI_BatchPut_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ClientAPIServer).BatchPut(&grpc.GenericServerStream[PutRequest, BatchPutResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ClientAPI_BatchPutServer = grpc.ClientStreamingServer[PutRequest, BatchPutResponse]
+
+func _ClientAPI_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_BatchGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).BatchGet(ctx, req.(*BatchGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_BatchDelete_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ClientAPIServer).BatchDelete(&grpc.GenericServerStream[DeleteRequest, BatchDeleteResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ClientAPI_BatchDeleteServer = grpc.BidiStreamingServer[DeleteRequest, BatchDeleteResult]
+
+func _ClientAPI_DeleteRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).DeleteRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_DeleteRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).DeleteRange(ctx, req.(*DeleteRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ClientAPI_GetStore_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(emptypb.Empty)
 	if err := stream.RecvMsg(m); err != nil {
@@ -245,6 +579,24 @@ func _ClientAPI_GetStore_Handler(srv interface{}, stream grpc.ServerStream) erro
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ClientAPI_GetStoreServer = grpc.ServerStreamingServer[GetStoreResponse]
 
+func _ClientAPI_GetStorePage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStorePageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).GetStorePage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_GetStorePage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).GetStorePage(ctx, req.(*GetStorePageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ClientAPI_GetRoutingTable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -281,6 +633,150 @@ func _ClientAPI_Lookup_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClientAPI_LookupTrace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupTraceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).LookupTrace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_LookupTrace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).LookupTrace(ctx, req.(*LookupTraceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_LookupOwnership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupOwnershipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).LookupOwnership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_LookupOwnership_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).LookupOwnership(ctx, req.(*LookupOwnershipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).Info(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_Info_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).Info(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_GetSpace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).GetSpace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_GetSpace_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).GetSpace(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_GetConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).GetConfig(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_PauseStabilization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseStabilizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).PauseStabilization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_PauseStabilization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).PauseStabilization(ctx, req.(*PauseStabilizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).Shutdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_Shutdown_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).Shutdown(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientAPI_Rebalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientAPIServer).Rebalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientAPI_Rebalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientAPIServer).Rebalance(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ClientAPI_ServiceDesc is the grpc.ServiceDesc for ClientAPI service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -292,6 +788,14 @@ var ClientAPI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Put",
 			Handler:    _ClientAPI_Put_Handler,
 		},
+		{
+			MethodName: "PutIf",
+			Handler:    _ClientAPI_PutIf_Handler,
+		},
+		{
+			MethodName: "PutContent",
+			Handler:    _ClientAPI_PutContent_Handler,
+		},
 		{
 			MethodName: "Get",
 			Handler:    _ClientAPI_Get_Handler,
@@ -300,6 +804,18 @@ var ClientAPI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _ClientAPI_Delete_Handler,
 		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _ClientAPI_BatchGet_Handler,
+		},
+		{
+			MethodName: "DeleteRange",
+			Handler:    _ClientAPI_DeleteRange_Handler,
+		},
+		{
+			MethodName: "GetStorePage",
+			Handler:    _ClientAPI_GetStorePage_Handler,
+		},
 		{
 			MethodName: "GetRoutingTable",
 			Handler:    _ClientAPI_GetRoutingTable_Handler,
@@ -308,8 +824,51 @@ var ClientAPI_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Lookup",
 			Handler:    _ClientAPI_Lookup_Handler,
 		},
+		{
+			MethodName: "LookupTrace",
+			Handler:    _ClientAPI_LookupTrace_Handler,
+		},
+		{
+			MethodName: "LookupOwnership",
+			Handler:    _ClientAPI_LookupOwnership_Handler,
+		},
+		{
+			MethodName: "Info",
+			Handler:    _ClientAPI_Info_Handler,
+		},
+		{
+			MethodName: "GetSpace",
+			Handler:    _ClientAPI_GetSpace_Handler,
+		},
+		{
+			MethodName: "GetConfig",
+			Handler:    _ClientAPI_GetConfig_Handler,
+		},
+		{
+			MethodName: "PauseStabilization",
+			Handler:    _ClientAPI_PauseStabilization_Handler,
+		},
+		{
+			MethodName: "Shutdown",
+			Handler:    _ClientAPI_Shutdown_Handler,
+		},
+		{
+			MethodName: "Rebalance",
+			Handler:    _ClientAPI_Rebalance_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchPut",
+			Handler:       _ClientAPI_BatchPut_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "BatchDelete",
+			Handler:       _ClientAPI_BatchDelete_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "GetStore",
 			Handler:       _ClientAPI_GetStore_Handler,
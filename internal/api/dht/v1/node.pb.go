@@ -296,6 +296,76 @@ func (x *FindSuccessorResponse) GetNode() *Node {
 	return nil
 }
 
+// One step of an iterative lookup: either the lookup ended at this node, or
+// the originator must continue it at one of the candidates with the new state.
+type NextHopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Successor     *Node                  `protobuf:"bytes,1,opt,name=successor,proto3" json:"successor,omitempty"`               // set if the lookup ended: successor responsible for target_id
+	Candidates    []*Node                `protobuf:"bytes,2,rep,name=candidates,proto3" json:"candidates,omitempty"`             // otherwise, the next hops in order of preference
+	CurrentI      []byte                 `protobuf:"bytes,3,opt,name=current_i,json=currentI,proto3" json:"current_i,omitempty"` // imaginary node to send to the next hop
+	KShift        []byte                 `protobuf:"bytes,4,opt,name=k_shift,json=kShift,proto3" json:"k_shift,omitempty"`       // shifted key to send to the next hop
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NextHopResponse) Reset() {
+	*x = NextHopResponse{}
+	mi := &file_dht_v1_node_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NextHopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextHopResponse) ProtoMessage() {}
+
+func (x *NextHopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextHopResponse.ProtoReflect.Descriptor instead.
+func (*NextHopResponse) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *NextHopResponse) GetSuccessor() *Node {
+	if x != nil {
+		return x.Successor
+	}
+	return nil
+}
+
+func (x *NextHopResponse) GetCandidates() []*Node {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *NextHopResponse) GetCurrentI() []byte {
+	if x != nil {
+		return x.CurrentI
+	}
+	return nil
+}
+
+func (x *NextHopResponse) GetKShift() []byte {
+	if x != nil {
+		return x.KShift
+	}
+	return nil
+}
+
 // Successor list
 type SuccessorList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -306,7 +376,7 @@ type SuccessorList struct {
 
 func (x *SuccessorList) Reset() {
 	*x = SuccessorList{}
-	mi := &file_dht_v1_node_proto_msgTypes[5]
+	mi := &file_dht_v1_node_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -318,7 +388,7 @@ func (x *SuccessorList) String() string {
 func (*SuccessorList) ProtoMessage() {}
 
 func (x *SuccessorList) ProtoReflect() protoreflect.Message {
-	mi := &file_dht_v1_node_proto_msgTypes[5]
+	mi := &file_dht_v1_node_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -331,7 +401,7 @@ func (x *SuccessorList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SuccessorList.ProtoReflect.Descriptor instead.
 func (*SuccessorList) Descriptor() ([]byte, []int) {
-	return file_dht_v1_node_proto_rawDescGZIP(), []int{5}
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *SuccessorList) GetSuccessors() []*Node {
@@ -341,19 +411,76 @@ func (x *SuccessorList) GetSuccessors() []*Node {
 	return nil
 }
 
+// Departure of the successor of the callee (PredecessorLeaving).
+type PredecessorLeavingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Leaving       *Node                  `protobuf:"bytes,1,opt,name=leaving,proto3" json:"leaving,omitempty"`       // the leaving node
+	Successors    []*Node                `protobuf:"bytes,2,rep,name=successors,proto3" json:"successors,omitempty"` // its successors, which replace it in the callee's list
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredecessorLeavingRequest) Reset() {
+	*x = PredecessorLeavingRequest{}
+	mi := &file_dht_v1_node_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredecessorLeavingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredecessorLeavingRequest) ProtoMessage() {}
+
+func (x *PredecessorLeavingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredecessorLeavingRequest.ProtoReflect.Descriptor instead.
+func (*PredecessorLeavingRequest) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PredecessorLeavingRequest) GetLeaving() *Node {
+	if x != nil {
+		return x.Leaving
+	}
+	return nil
+}
+
+func (x *PredecessorLeavingRequest) GetSuccessors() []*Node {
+	if x != nil {
+		return x.Successors
+	}
+	return nil
+}
+
 // Resource stored in the DHT.
 type Resource struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           []byte                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	RawKey        string                 `protobuf:"bytes,2,opt,name=raw_key,json=rawKey,proto3" json:"raw_key,omitempty"` // for debugging
 	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                                                       // expiry time, unix milliseconds (0 = never expires)
+	Origin        []byte                 `protobuf:"bytes,5,opt,name=origin,proto3" json:"origin,omitempty"`                                                                               // ID of the node that first accepted the Put (empty = unknown)
+	Version       uint64                 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`                                                                            // version of the write, unix nanoseconds assigned by the owner (0 = new write, or unknown)
+	Metadata      map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // application metadata stored with the value (empty = none)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Resource) Reset() {
 	*x = Resource{}
-	mi := &file_dht_v1_node_proto_msgTypes[6]
+	mi := &file_dht_v1_node_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -365,7 +492,7 @@ func (x *Resource) String() string {
 func (*Resource) ProtoMessage() {}
 
 func (x *Resource) ProtoReflect() protoreflect.Message {
-	mi := &file_dht_v1_node_proto_msgTypes[6]
+	mi := &file_dht_v1_node_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -378,7 +505,7 @@ func (x *Resource) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Resource.ProtoReflect.Descriptor instead.
 func (*Resource) Descriptor() ([]byte, []int) {
-	return file_dht_v1_node_proto_rawDescGZIP(), []int{6}
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Resource) GetKey() []byte {
@@ -402,17 +529,54 @@ func (x *Resource) GetValue() string {
 	return ""
 }
 
+func (x *Resource) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *Resource) GetOrigin() []byte {
+	if x != nil {
+		return x.Origin
+	}
+	return nil
+}
+
+func (x *Resource) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Resource) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 // Store a resource (Put).
+//
+// Large values are split across several frames: the first one (seq = 0)
+// carries the resource with the first chunk of its value, the following
+// ones (seq = 1, 2, ...) only the next chunk in resource.value. The value
+// is complete at the first frame with more = false.
 type StoreRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Resource      *Resource              `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	Replica       bool                   `protobuf:"varint,2,opt,name=replica,proto3" json:"replica,omitempty"`    // replica copy pushed by the owner's side: stored without the ownership check
+	Seq           uint32                 `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`            // index of the value chunk carried by this frame
+	More          bool                   `protobuf:"varint,4,opt,name=more,proto3" json:"more,omitempty"`          // further chunks of the same value follow
+	Condition     *Condition             `protobuf:"bytes,5,opt,name=condition,proto3" json:"condition,omitempty"` // precondition checked atomically by the owner, first frame only (unset = unconditional)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *StoreRequest) Reset() {
 	*x = StoreRequest{}
-	mi := &file_dht_v1_node_proto_msgTypes[7]
+	mi := &file_dht_v1_node_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -424,7 +588,7 @@ func (x *StoreRequest) String() string {
 func (*StoreRequest) ProtoMessage() {}
 
 func (x *StoreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_dht_v1_node_proto_msgTypes[7]
+	mi := &file_dht_v1_node_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -437,7 +601,7 @@ func (x *StoreRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StoreRequest.ProtoReflect.Descriptor instead.
 func (*StoreRequest) Descriptor() ([]byte, []int) {
-	return file_dht_v1_node_proto_rawDescGZIP(), []int{7}
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *StoreRequest) GetResource() *Resource {
@@ -447,6 +611,140 @@ func (x *StoreRequest) GetResource() *Resource {
 	return nil
 }
 
+func (x *StoreRequest) GetReplica() bool {
+	if x != nil {
+		return x.Replica
+	}
+	return false
+}
+
+func (x *StoreRequest) GetSeq() uint32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *StoreRequest) GetMore() bool {
+	if x != nil {
+		return x.More
+	}
+	return false
+}
+
+func (x *StoreRequest) GetCondition() *Condition {
+	if x != nil {
+		return x.Condition
+	}
+	return nil
+}
+
+type StoreResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []uint64               `protobuf:"varint,1,rep,packed,name=versions,proto3" json:"versions,omitempty"` // version stored under the key of each resource, in request order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreResponse) Reset() {
+	*x = StoreResponse{}
+	mi := &file_dht_v1_node_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreResponse) ProtoMessage() {}
+
+func (x *StoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreResponse.ProtoReflect.Descriptor instead.
+func (*StoreResponse) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StoreResponse) GetVersions() []uint64 {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// Precondition of a conditional Store (PutIf): the resource is stored only
+// if it holds for the resource the owner currently stores under its key.
+type Condition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IfAbsent      bool                   `protobuf:"varint,1,opt,name=if_absent,json=ifAbsent,proto3" json:"if_absent,omitempty"` // no resource is stored under the key
+	IfEqual       bool                   `protobuf:"varint,2,opt,name=if_equal,json=ifEqual,proto3" json:"if_equal,omitempty"`    // the stored resource has value expected
+	Expected      string                 `protobuf:"bytes,3,opt,name=expected,proto3" json:"expected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Condition) Reset() {
+	*x = Condition{}
+	mi := &file_dht_v1_node_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Condition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Condition) ProtoMessage() {}
+
+func (x *Condition) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Condition.ProtoReflect.Descriptor instead.
+func (*Condition) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Condition) GetIfAbsent() bool {
+	if x != nil {
+		return x.IfAbsent
+	}
+	return false
+}
+
+func (x *Condition) GetIfEqual() bool {
+	if x != nil {
+		return x.IfEqual
+	}
+	return false
+}
+
+func (x *Condition) GetExpected() string {
+	if x != nil {
+		return x.Expected
+	}
+	return ""
+}
+
 // Retrieve a resource (Get).
 type RetrieveRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -457,7 +755,7 @@ type RetrieveRequest struct {
 
 func (x *RetrieveRequest) Reset() {
 	*x = RetrieveRequest{}
-	mi := &file_dht_v1_node_proto_msgTypes[8]
+	mi := &file_dht_v1_node_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -469,7 +767,7 @@ func (x *RetrieveRequest) String() string {
 func (*RetrieveRequest) ProtoMessage() {}
 
 func (x *RetrieveRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_dht_v1_node_proto_msgTypes[8]
+	mi := &file_dht_v1_node_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -482,7 +780,7 @@ func (x *RetrieveRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RetrieveRequest.ProtoReflect.Descriptor instead.
 func (*RetrieveRequest) Descriptor() ([]byte, []int) {
-	return file_dht_v1_node_proto_rawDescGZIP(), []int{8}
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *RetrieveRequest) GetKey() []byte {
@@ -501,7 +799,7 @@ type RetrieveResponse struct {
 
 func (x *RetrieveResponse) Reset() {
 	*x = RetrieveResponse{}
-	mi := &file_dht_v1_node_proto_msgTypes[9]
+	mi := &file_dht_v1_node_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -513,7 +811,7 @@ func (x *RetrieveResponse) String() string {
 func (*RetrieveResponse) ProtoMessage() {}
 
 func (x *RetrieveResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_dht_v1_node_proto_msgTypes[9]
+	mi := &file_dht_v1_node_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -526,7 +824,7 @@ func (x *RetrieveResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RetrieveResponse.ProtoReflect.Descriptor instead.
 func (*RetrieveResponse) Descriptor() ([]byte, []int) {
-	return file_dht_v1_node_proto_rawDescGZIP(), []int{9}
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *RetrieveResponse) GetResource() *Resource {
@@ -546,7 +844,7 @@ type RemoveRequest struct {
 
 func (x *RemoveRequest) Reset() {
 	*x = RemoveRequest{}
-	mi := &file_dht_v1_node_proto_msgTypes[10]
+	mi := &file_dht_v1_node_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -558,7 +856,7 @@ func (x *RemoveRequest) String() string {
 func (*RemoveRequest) ProtoMessage() {}
 
 func (x *RemoveRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_dht_v1_node_proto_msgTypes[10]
+	mi := &file_dht_v1_node_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -571,7 +869,7 @@ func (x *RemoveRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveRequest.ProtoReflect.Descriptor instead.
 func (*RemoveRequest) Descriptor() ([]byte, []int) {
-	return file_dht_v1_node_proto_rawDescGZIP(), []int{10}
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *RemoveRequest) GetKey() []byte {
@@ -581,6 +879,448 @@ func (x *RemoveRequest) GetKey() []byte {
 	return nil
 }
 
+// Remove a batch of resources (BatchDelete).
+type RemoveBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          [][]byte               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveBatchRequest) Reset() {
+	*x = RemoveBatchRequest{}
+	mi := &file_dht_v1_node_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveBatchRequest) ProtoMessage() {}
+
+func (x *RemoveBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveBatchRequest.ProtoReflect.Descriptor instead.
+func (*RemoveBatchRequest) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RemoveBatchRequest) GetKeys() [][]byte {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type RemoveBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Removed       []bool                 `protobuf:"varint,1,rep,packed,name=removed,proto3" json:"removed,omitempty"` // one per key, in request order (false = key not found)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveBatchResponse) Reset() {
+	*x = RemoveBatchResponse{}
+	mi := &file_dht_v1_node_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveBatchResponse) ProtoMessage() {}
+
+func (x *RemoveBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveBatchResponse.ProtoReflect.Descriptor instead.
+func (*RemoveBatchResponse) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RemoveBatchResponse) GetRemoved() []bool {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+// Remove all resources with key in (from, to] (DeleteRange).
+type RemoveRangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          []byte                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"` // exclusive lower bound (from == to = the whole ring)
+	To            []byte                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`     // inclusive upper bound
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRangeRequest) Reset() {
+	*x = RemoveRangeRequest{}
+	mi := &file_dht_v1_node_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRangeRequest) ProtoMessage() {}
+
+func (x *RemoveRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRangeRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRangeRequest) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RemoveRangeRequest) GetFrom() []byte {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *RemoveRangeRequest) GetTo() []byte {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type RemoveRangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Removed       uint64                 `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"` // number of resources removed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRangeResponse) Reset() {
+	*x = RemoveRangeResponse{}
+	mi := &file_dht_v1_node_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRangeResponse) ProtoMessage() {}
+
+func (x *RemoveRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRangeResponse.ProtoReflect.Descriptor instead.
+func (*RemoveRangeResponse) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RemoveRangeResponse) GetRemoved() uint64 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+// Retrieve all resources with key in (from, to] (range pull).
+type RetrieveRangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          []byte                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"` // exclusive lower bound
+	To            []byte                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`     // inclusive upper bound
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetrieveRangeRequest) Reset() {
+	*x = RetrieveRangeRequest{}
+	mi := &file_dht_v1_node_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetrieveRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrieveRangeRequest) ProtoMessage() {}
+
+func (x *RetrieveRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrieveRangeRequest.ProtoReflect.Descriptor instead.
+func (*RetrieveRangeRequest) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RetrieveRangeRequest) GetFrom() []byte {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *RetrieveRangeRequest) GetTo() []byte {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+// Merkle summary of the resources with key in (from, to] (anti-entropy).
+//
+// The range is split into 2^depth leaves of equal width, hashed into a
+// binary tree (level 0 = root, level depth = leaves). The request asks for
+// the hashes of the nodes at level with the given indices or, if entries is
+// set, for the entries of the leaves with the given indices.
+type SyncDigestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          []byte                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`               // exclusive lower bound
+	To            []byte                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`                   // inclusive upper bound
+	Depth         uint32                 `protobuf:"varint,3,opt,name=depth,proto3" json:"depth,omitempty"`            // depth of the tree
+	Level         uint32                 `protobuf:"varint,4,opt,name=level,proto3" json:"level,omitempty"`            // level of the requested nodes (ignored with entries)
+	Indices       []uint32               `protobuf:"varint,5,rep,packed,name=indices,proto3" json:"indices,omitempty"` // indices of the requested nodes within level
+	Entries       bool                   `protobuf:"varint,6,opt,name=entries,proto3" json:"entries,omitempty"`        // return the entries of the leaves instead of hashes
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncDigestRequest) Reset() {
+	*x = SyncDigestRequest{}
+	mi := &file_dht_v1_node_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncDigestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncDigestRequest) ProtoMessage() {}
+
+func (x *SyncDigestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncDigestRequest.ProtoReflect.Descriptor instead.
+func (*SyncDigestRequest) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SyncDigestRequest) GetFrom() []byte {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *SyncDigestRequest) GetTo() []byte {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *SyncDigestRequest) GetDepth() uint32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *SyncDigestRequest) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *SyncDigestRequest) GetIndices() []uint32 {
+	if x != nil {
+		return x.Indices
+	}
+	return nil
+}
+
+func (x *SyncDigestRequest) GetEntries() bool {
+	if x != nil {
+		return x.Entries
+	}
+	return false
+}
+
+// Entry of a Merkle leaf: a key with the digest of its resource.
+type SyncEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Leaf          uint32                 `protobuf:"varint,1,opt,name=leaf,proto3" json:"leaf,omitempty"` // index of the leaf holding the key
+	Key           []byte                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Digest        []byte                 `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncEntry) Reset() {
+	*x = SyncEntry{}
+	mi := &file_dht_v1_node_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncEntry) ProtoMessage() {}
+
+func (x *SyncEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncEntry.ProtoReflect.Descriptor instead.
+func (*SyncEntry) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SyncEntry) GetLeaf() uint32 {
+	if x != nil {
+		return x.Leaf
+	}
+	return 0
+}
+
+func (x *SyncEntry) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *SyncEntry) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+type SyncDigestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hashes        [][]byte               `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`   // hashes of the requested nodes, in request order (empty = empty subtree)
+	Entries       []*SyncEntry           `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"` // with entries: the entries of the requested leaves
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncDigestResponse) Reset() {
+	*x = SyncDigestResponse{}
+	mi := &file_dht_v1_node_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncDigestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncDigestResponse) ProtoMessage() {}
+
+func (x *SyncDigestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dht_v1_node_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncDigestResponse.ProtoReflect.Descriptor instead.
+func (*SyncDigestResponse) Descriptor() ([]byte, []int) {
+	return file_dht_v1_node_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SyncDigestResponse) GetHashes() [][]byte {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+func (x *SyncDigestResponse) GetEntries() []*SyncEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
 var File_dht_v1_node_proto protoreflect.FileDescriptor
 
 const file_dht_v1_node_proto_rawDesc = "" +
@@ -599,33 +1339,97 @@ const file_dht_v1_node_proto_rawDesc = "" +
 	"\tcurrent_i\x18\x01 \x01(\fR\bcurrentI\x12\x17\n" +
 	"\ak_shift\x18\x02 \x01(\fR\x06kShift\"9\n" +
 	"\x15FindSuccessorResponse\x12 \n" +
-	"\x04node\x18\x01 \x01(\v2\f.dht.v1.NodeR\x04node\"=\n" +
+	"\x04node\x18\x01 \x01(\v2\f.dht.v1.NodeR\x04node\"\xa1\x01\n" +
+	"\x0fNextHopResponse\x12*\n" +
+	"\tsuccessor\x18\x01 \x01(\v2\f.dht.v1.NodeR\tsuccessor\x12,\n" +
+	"\n" +
+	"candidates\x18\x02 \x03(\v2\f.dht.v1.NodeR\n" +
+	"candidates\x12\x1b\n" +
+	"\tcurrent_i\x18\x03 \x01(\fR\bcurrentI\x12\x17\n" +
+	"\ak_shift\x18\x04 \x01(\fR\x06kShift\"=\n" +
 	"\rSuccessorList\x12,\n" +
 	"\n" +
 	"successors\x18\x01 \x03(\v2\f.dht.v1.NodeR\n" +
-	"successors\"K\n" +
+	"successors\"q\n" +
+	"\x19PredecessorLeavingRequest\x12&\n" +
+	"\aleaving\x18\x01 \x01(\v2\f.dht.v1.NodeR\aleaving\x12,\n" +
+	"\n" +
+	"successors\x18\x02 \x03(\v2\f.dht.v1.NodeR\n" +
+	"successors\"\x95\x02\n" +
 	"\bResource\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\fR\x03key\x12\x17\n" +
 	"\araw_key\x18\x02 \x01(\tR\x06rawKey\x12\x14\n" +
-	"\x05value\x18\x03 \x01(\tR\x05value\"<\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\x12\x16\n" +
+	"\x06origin\x18\x05 \x01(\fR\x06origin\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x04R\aversion\x12:\n" +
+	"\bmetadata\x18\a \x03(\v2\x1e.dht.v1.Resource.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xad\x01\n" +
 	"\fStoreRequest\x12,\n" +
-	"\bresource\x18\x01 \x01(\v2\x10.dht.v1.ResourceR\bresource\"#\n" +
+	"\bresource\x18\x01 \x01(\v2\x10.dht.v1.ResourceR\bresource\x12\x18\n" +
+	"\areplica\x18\x02 \x01(\bR\areplica\x12\x10\n" +
+	"\x03seq\x18\x03 \x01(\rR\x03seq\x12\x12\n" +
+	"\x04more\x18\x04 \x01(\bR\x04more\x12/\n" +
+	"\tcondition\x18\x05 \x01(\v2\x11.dht.v1.ConditionR\tcondition\"+\n" +
+	"\rStoreResponse\x12\x1a\n" +
+	"\bversions\x18\x01 \x03(\x04R\bversions\"_\n" +
+	"\tCondition\x12\x1b\n" +
+	"\tif_absent\x18\x01 \x01(\bR\bifAbsent\x12\x19\n" +
+	"\bif_equal\x18\x02 \x01(\bR\aifEqual\x12\x1a\n" +
+	"\bexpected\x18\x03 \x01(\tR\bexpected\"#\n" +
 	"\x0fRetrieveRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\fR\x03key\"@\n" +
 	"\x10RetrieveResponse\x12,\n" +
 	"\bresource\x18\x01 \x01(\v2\x10.dht.v1.ResourceR\bresource\"!\n" +
 	"\rRemoveRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\fR\x03key2\x96\x04\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\"(\n" +
+	"\x12RemoveBatchRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\fR\x04keys\"/\n" +
+	"\x13RemoveBatchResponse\x12\x18\n" +
+	"\aremoved\x18\x01 \x03(\bR\aremoved\"8\n" +
+	"\x12RemoveRangeRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\fR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\fR\x02to\"/\n" +
+	"\x13RemoveRangeResponse\x12\x18\n" +
+	"\aremoved\x18\x01 \x01(\x04R\aremoved\":\n" +
+	"\x14RetrieveRangeRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\fR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\fR\x02to\"\x97\x01\n" +
+	"\x11SyncDigestRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\fR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\fR\x02to\x12\x14\n" +
+	"\x05depth\x18\x03 \x01(\rR\x05depth\x12\x14\n" +
+	"\x05level\x18\x04 \x01(\rR\x05level\x12\x18\n" +
+	"\aindices\x18\x05 \x03(\rR\aindices\x12\x18\n" +
+	"\aentries\x18\x06 \x01(\bR\aentries\"I\n" +
+	"\tSyncEntry\x12\x12\n" +
+	"\x04leaf\x18\x01 \x01(\rR\x04leaf\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\fR\x03key\x12\x16\n" +
+	"\x06digest\x18\x03 \x01(\fR\x06digest\"Y\n" +
+	"\x12SyncDigestResponse\x12\x16\n" +
+	"\x06hashes\x18\x01 \x03(\fR\x06hashes\x12+\n" +
+	"\aentries\x18\x02 \x03(\v2\x11.dht.v1.SyncEntryR\aentries2\xa5\b\n" +
 	"\x03DHT\x12L\n" +
-	"\rFindSuccessor\x12\x1c.dht.v1.FindSuccessorRequest\x1a\x1d.dht.v1.FindSuccessorResponse\x126\n" +
+	"\rFindSuccessor\x12\x1c.dht.v1.FindSuccessorRequest\x1a\x1d.dht.v1.FindSuccessorResponse\x12M\n" +
+	"\x14FindSuccessorNextHop\x12\x1c.dht.v1.FindSuccessorRequest\x1a\x17.dht.v1.NextHopResponse\x12N\n" +
+	"\x0fFindPredecessor\x12\x1c.dht.v1.FindSuccessorRequest\x1a\x1d.dht.v1.FindSuccessorResponse\x126\n" +
 	"\x0eGetPredecessor\x12\x16.google.protobuf.Empty\x1a\f.dht.v1.Node\x12A\n" +
 	"\x10GetSuccessorList\x12\x16.google.protobuf.Empty\x1a\x15.dht.v1.SuccessorList\x12.\n" +
 	"\x06Notify\x12\f.dht.v1.Node\x1a\x16.google.protobuf.Empty\x126\n" +
-	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\x127\n" +
-	"\x05Store\x12\x14.dht.v1.StoreRequest\x1a\x16.google.protobuf.Empty(\x01\x12=\n" +
+	"\x04Ping\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\x126\n" +
+	"\x05Store\x12\x14.dht.v1.StoreRequest\x1a\x15.dht.v1.StoreResponse(\x01\x12=\n" +
 	"\bRetrieve\x12\x17.dht.v1.RetrieveRequest\x1a\x18.dht.v1.RetrieveResponse\x127\n" +
-	"\x06Remove\x12\x15.dht.v1.RemoveRequest\x1a\x16.google.protobuf.Empty\x12-\n" +
-	"\x05Leave\x12\f.dht.v1.Node\x1a\x16.google.protobuf.EmptyB@Z>github.com/flaviosimonelli/KoordeDHT/internal/api/dht/v1;dhtv1b\x06proto3"
+	"\x06Remove\x12\x15.dht.v1.RemoveRequest\x1a\x16.google.protobuf.Empty\x12F\n" +
+	"\vRemoveBatch\x12\x1a.dht.v1.RemoveBatchRequest\x1a\x1b.dht.v1.RemoveBatchResponse\x12F\n" +
+	"\vRemoveRange\x12\x1a.dht.v1.RemoveRangeRequest\x1a\x1b.dht.v1.RemoveRangeResponse\x12I\n" +
+	"\rRetrieveRange\x12\x1c.dht.v1.RetrieveRangeRequest\x1a\x18.dht.v1.RetrieveResponse0\x01\x12-\n" +
+	"\x05Leave\x12\f.dht.v1.Node\x1a\x16.google.protobuf.Empty\x12O\n" +
+	"\x12PredecessorLeaving\x12!.dht.v1.PredecessorLeavingRequest\x1a\x16.google.protobuf.Empty\x12C\n" +
+	"\n" +
+	"SyncDigest\x12\x19.dht.v1.SyncDigestRequest\x1a\x1a.dht.v1.SyncDigestResponseB@Z>github.com/flaviosimonelli/KoordeDHT/internal/api/dht/v1;dhtv1b\x06proto3"
 
 var (
 	file_dht_v1_node_proto_rawDescOnce sync.Once
@@ -639,51 +1443,85 @@ func file_dht_v1_node_proto_rawDescGZIP() []byte {
 	return file_dht_v1_node_proto_rawDescData
 }
 
-var file_dht_v1_node_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_dht_v1_node_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_dht_v1_node_proto_goTypes = []any{
-	(*Node)(nil),                  // 0: dht.v1.Node
-	(*FindSuccessorRequest)(nil),  // 1: dht.v1.FindSuccessorRequest
-	(*Initial)(nil),               // 2: dht.v1.Initial
-	(*Step)(nil),                  // 3: dht.v1.Step
-	(*FindSuccessorResponse)(nil), // 4: dht.v1.FindSuccessorResponse
-	(*SuccessorList)(nil),         // 5: dht.v1.SuccessorList
-	(*Resource)(nil),              // 6: dht.v1.Resource
-	(*StoreRequest)(nil),          // 7: dht.v1.StoreRequest
-	(*RetrieveRequest)(nil),       // 8: dht.v1.RetrieveRequest
-	(*RetrieveResponse)(nil),      // 9: dht.v1.RetrieveResponse
-	(*RemoveRequest)(nil),         // 10: dht.v1.RemoveRequest
-	(*emptypb.Empty)(nil),         // 11: google.protobuf.Empty
+	(*Node)(nil),                      // 0: dht.v1.Node
+	(*FindSuccessorRequest)(nil),      // 1: dht.v1.FindSuccessorRequest
+	(*Initial)(nil),                   // 2: dht.v1.Initial
+	(*Step)(nil),                      // 3: dht.v1.Step
+	(*FindSuccessorResponse)(nil),     // 4: dht.v1.FindSuccessorResponse
+	(*NextHopResponse)(nil),           // 5: dht.v1.NextHopResponse
+	(*SuccessorList)(nil),             // 6: dht.v1.SuccessorList
+	(*PredecessorLeavingRequest)(nil), // 7: dht.v1.PredecessorLeavingRequest
+	(*Resource)(nil),                  // 8: dht.v1.Resource
+	(*StoreRequest)(nil),              // 9: dht.v1.StoreRequest
+	(*StoreResponse)(nil),             // 10: dht.v1.StoreResponse
+	(*Condition)(nil),                 // 11: dht.v1.Condition
+	(*RetrieveRequest)(nil),           // 12: dht.v1.RetrieveRequest
+	(*RetrieveResponse)(nil),          // 13: dht.v1.RetrieveResponse
+	(*RemoveRequest)(nil),             // 14: dht.v1.RemoveRequest
+	(*RemoveBatchRequest)(nil),        // 15: dht.v1.RemoveBatchRequest
+	(*RemoveBatchResponse)(nil),       // 16: dht.v1.RemoveBatchResponse
+	(*RemoveRangeRequest)(nil),        // 17: dht.v1.RemoveRangeRequest
+	(*RemoveRangeResponse)(nil),       // 18: dht.v1.RemoveRangeResponse
+	(*RetrieveRangeRequest)(nil),      // 19: dht.v1.RetrieveRangeRequest
+	(*SyncDigestRequest)(nil),         // 20: dht.v1.SyncDigestRequest
+	(*SyncEntry)(nil),                 // 21: dht.v1.SyncEntry
+	(*SyncDigestResponse)(nil),        // 22: dht.v1.SyncDigestResponse
+	nil,                               // 23: dht.v1.Resource.MetadataEntry
+	(*emptypb.Empty)(nil),             // 24: google.protobuf.Empty
 }
 var file_dht_v1_node_proto_depIdxs = []int32{
 	2,  // 0: dht.v1.FindSuccessorRequest.initial:type_name -> dht.v1.Initial
 	3,  // 1: dht.v1.FindSuccessorRequest.step:type_name -> dht.v1.Step
 	0,  // 2: dht.v1.FindSuccessorResponse.node:type_name -> dht.v1.Node
-	0,  // 3: dht.v1.SuccessorList.successors:type_name -> dht.v1.Node
-	6,  // 4: dht.v1.StoreRequest.resource:type_name -> dht.v1.Resource
-	6,  // 5: dht.v1.RetrieveResponse.resource:type_name -> dht.v1.Resource
-	1,  // 6: dht.v1.DHT.FindSuccessor:input_type -> dht.v1.FindSuccessorRequest
-	11, // 7: dht.v1.DHT.GetPredecessor:input_type -> google.protobuf.Empty
-	11, // 8: dht.v1.DHT.GetSuccessorList:input_type -> google.protobuf.Empty
-	0,  // 9: dht.v1.DHT.Notify:input_type -> dht.v1.Node
-	11, // 10: dht.v1.DHT.Ping:input_type -> google.protobuf.Empty
-	7,  // 11: dht.v1.DHT.Store:input_type -> dht.v1.StoreRequest
-	8,  // 12: dht.v1.DHT.Retrieve:input_type -> dht.v1.RetrieveRequest
-	10, // 13: dht.v1.DHT.Remove:input_type -> dht.v1.RemoveRequest
-	0,  // 14: dht.v1.DHT.Leave:input_type -> dht.v1.Node
-	4,  // 15: dht.v1.DHT.FindSuccessor:output_type -> dht.v1.FindSuccessorResponse
-	0,  // 16: dht.v1.DHT.GetPredecessor:output_type -> dht.v1.Node
-	5,  // 17: dht.v1.DHT.GetSuccessorList:output_type -> dht.v1.SuccessorList
-	11, // 18: dht.v1.DHT.Notify:output_type -> google.protobuf.Empty
-	11, // 19: dht.v1.DHT.Ping:output_type -> google.protobuf.Empty
-	11, // 20: dht.v1.DHT.Store:output_type -> google.protobuf.Empty
-	9,  // 21: dht.v1.DHT.Retrieve:output_type -> dht.v1.RetrieveResponse
-	11, // 22: dht.v1.DHT.Remove:output_type -> google.protobuf.Empty
-	11, // 23: dht.v1.DHT.Leave:output_type -> google.protobuf.Empty
-	15, // [15:24] is the sub-list for method output_type
-	6,  // [6:15] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	0,  // 3: dht.v1.NextHopResponse.successor:type_name -> dht.v1.Node
+	0,  // 4: dht.v1.NextHopResponse.candidates:type_name -> dht.v1.Node
+	0,  // 5: dht.v1.SuccessorList.successors:type_name -> dht.v1.Node
+	0,  // 6: dht.v1.PredecessorLeavingRequest.leaving:type_name -> dht.v1.Node
+	0,  // 7: dht.v1.PredecessorLeavingRequest.successors:type_name -> dht.v1.Node
+	23, // 8: dht.v1.Resource.metadata:type_name -> dht.v1.Resource.MetadataEntry
+	8,  // 9: dht.v1.StoreRequest.resource:type_name -> dht.v1.Resource
+	11, // 10: dht.v1.StoreRequest.condition:type_name -> dht.v1.Condition
+	8,  // 11: dht.v1.RetrieveResponse.resource:type_name -> dht.v1.Resource
+	21, // 12: dht.v1.SyncDigestResponse.entries:type_name -> dht.v1.SyncEntry
+	1,  // 13: dht.v1.DHT.FindSuccessor:input_type -> dht.v1.FindSuccessorRequest
+	1,  // 14: dht.v1.DHT.FindSuccessorNextHop:input_type -> dht.v1.FindSuccessorRequest
+	1,  // 15: dht.v1.DHT.FindPredecessor:input_type -> dht.v1.FindSuccessorRequest
+	24, // 16: dht.v1.DHT.GetPredecessor:input_type -> google.protobuf.Empty
+	24, // 17: dht.v1.DHT.GetSuccessorList:input_type -> google.protobuf.Empty
+	0,  // 18: dht.v1.DHT.Notify:input_type -> dht.v1.Node
+	24, // 19: dht.v1.DHT.Ping:input_type -> google.protobuf.Empty
+	9,  // 20: dht.v1.DHT.Store:input_type -> dht.v1.StoreRequest
+	12, // 21: dht.v1.DHT.Retrieve:input_type -> dht.v1.RetrieveRequest
+	14, // 22: dht.v1.DHT.Remove:input_type -> dht.v1.RemoveRequest
+	15, // 23: dht.v1.DHT.RemoveBatch:input_type -> dht.v1.RemoveBatchRequest
+	17, // 24: dht.v1.DHT.RemoveRange:input_type -> dht.v1.RemoveRangeRequest
+	19, // 25: dht.v1.DHT.RetrieveRange:input_type -> dht.v1.RetrieveRangeRequest
+	0,  // 26: dht.v1.DHT.Leave:input_type -> dht.v1.Node
+	7,  // 27: dht.v1.DHT.PredecessorLeaving:input_type -> dht.v1.PredecessorLeavingRequest
+	20, // 28: dht.v1.DHT.SyncDigest:input_type -> dht.v1.SyncDigestRequest
+	4,  // 29: dht.v1.DHT.FindSuccessor:output_type -> dht.v1.FindSuccessorResponse
+	5,  // 30: dht.v1.DHT.FindSuccessorNextHop:output_type -> dht.v1.NextHopResponse
+	4,  // 31: dht.v1.DHT.FindPredecessor:output_type -> dht.v1.FindSuccessorResponse
+	0,  // 32: dht.v1.DHT.GetPredecessor:output_type -> dht.v1.Node
+	6,  // 33: dht.v1.DHT.GetSuccessorList:output_type -> dht.v1.SuccessorList
+	24, // 34: dht.v1.DHT.Notify:output_type -> google.protobuf.Empty
+	24, // 35: dht.v1.DHT.Ping:output_type -> google.protobuf.Empty
+	10, // 36: dht.v1.DHT.Store:output_type -> dht.v1.StoreResponse
+	13, // 37: dht.v1.DHT.Retrieve:output_type -> dht.v1.RetrieveResponse
+	24, // 38: dht.v1.DHT.Remove:output_type -> google.protobuf.Empty
+	16, // 39: dht.v1.DHT.RemoveBatch:output_type -> dht.v1.RemoveBatchResponse
+	18, // 40: dht.v1.DHT.RemoveRange:output_type -> dht.v1.RemoveRangeResponse
+	13, // 41: dht.v1.DHT.RetrieveRange:output_type -> dht.v1.RetrieveResponse
+	24, // 42: dht.v1.DHT.Leave:output_type -> google.protobuf.Empty
+	24, // 43: dht.v1.DHT.PredecessorLeaving:output_type -> google.protobuf.Empty
+	22, // 44: dht.v1.DHT.SyncDigest:output_type -> dht.v1.SyncDigestResponse
+	29, // [29:45] is the sub-list for method output_type
+	13, // [13:29] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_dht_v1_node_proto_init() }
@@ -701,7 +1539,7 @@ func file_dht_v1_node_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_dht_v1_node_proto_rawDesc), len(file_dht_v1_node_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
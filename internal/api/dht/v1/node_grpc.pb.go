@@ -20,15 +20,22 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DHT_FindSuccessor_FullMethodName    = "/dht.v1.DHT/FindSuccessor"
-	DHT_GetPredecessor_FullMethodName   = "/dht.v1.DHT/GetPredecessor"
-	DHT_GetSuccessorList_FullMethodName = "/dht.v1.DHT/GetSuccessorList"
-	DHT_Notify_FullMethodName           = "/dht.v1.DHT/Notify"
-	DHT_Ping_FullMethodName             = "/dht.v1.DHT/Ping"
-	DHT_Store_FullMethodName            = "/dht.v1.DHT/Store"
-	DHT_Retrieve_FullMethodName         = "/dht.v1.DHT/Retrieve"
-	DHT_Remove_FullMethodName           = "/dht.v1.DHT/Remove"
-	DHT_Leave_FullMethodName            = "/dht.v1.DHT/Leave"
+	DHT_FindSuccessor_FullMethodName        = "/dht.v1.DHT/FindSuccessor"
+	DHT_FindSuccessorNextHop_FullMethodName = "/dht.v1.DHT/FindSuccessorNextHop"
+	DHT_FindPredecessor_FullMethodName      = "/dht.v1.DHT/FindPredecessor"
+	DHT_GetPredecessor_FullMethodName       = "/dht.v1.DHT/GetPredecessor"
+	DHT_GetSuccessorList_FullMethodName     = "/dht.v1.DHT/GetSuccessorList"
+	DHT_Notify_FullMethodName               = "/dht.v1.DHT/Notify"
+	DHT_Ping_FullMethodName                 = "/dht.v1.DHT/Ping"
+	DHT_Store_FullMethodName                = "/dht.v1.DHT/Store"
+	DHT_Retrieve_FullMethodName             = "/dht.v1.DHT/Retrieve"
+	DHT_Remove_FullMethodName               = "/dht.v1.DHT/Remove"
+	DHT_RemoveBatch_FullMethodName          = "/dht.v1.DHT/RemoveBatch"
+	DHT_RemoveRange_FullMethodName          = "/dht.v1.DHT/RemoveRange"
+	DHT_RetrieveRange_FullMethodName        = "/dht.v1.DHT/RetrieveRange"
+	DHT_Leave_FullMethodName                = "/dht.v1.DHT/Leave"
+	DHT_PredecessorLeaving_FullMethodName   = "/dht.v1.DHT/PredecessorLeaving"
+	DHT_SyncDigest_FullMethodName           = "/dht.v1.DHT/SyncDigest"
 )
 
 // DHTClient is the client API for DHT service.
@@ -38,6 +45,13 @@ type DHTClient interface {
 	// Lookup in the Koorde overlay.
 	// Returns the successor responsible for target_id.
 	FindSuccessor(ctx context.Context, in *FindSuccessorRequest, opts ...grpc.CallOption) (*FindSuccessorResponse, error)
+	// Compute only the next step of a lookup, without forwarding it
+	// (iterative lookup: the originator drives the loop).
+	FindSuccessorNextHop(ctx context.Context, in *FindSuccessorRequest, opts ...grpc.CallOption) (*NextHopResponse, error)
+	// Lookup of the predecessor of the node responsible for target_id:
+	// same routing and modes as FindSuccessor, but the node n with
+	// target_id in (n, successor(n)] returns itself instead of its successor.
+	FindPredecessor(ctx context.Context, in *FindSuccessorRequest, opts ...grpc.CallOption) (*FindSuccessorResponse, error)
 	// Returns this node's predecessor.
 	GetPredecessor(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Node, error)
 	// Returns this node's successor list.
@@ -48,16 +62,33 @@ type DHTClient interface {
 	// Ping to check liveness of the node (debug).
 	Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Store a resource (Put). If the key already exists, overwrite it.
-	Store(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StoreRequest, emptypb.Empty], error)
+	// The owner assigns the version of new writes (see Resource.version).
+	Store(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StoreRequest, StoreResponse], error)
 	// Retrieve a resource (Get).
 	// Returns NotFound if the key does not exist.
 	Retrieve(ctx context.Context, in *RetrieveRequest, opts ...grpc.CallOption) (*RetrieveResponse, error)
 	// Remove a resource (Delete).
 	// Returns NotFound if the key does not exist.
 	Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Remove a batch of resources, reporting for each key whether it existed.
+	RemoveBatch(ctx context.Context, in *RemoveBatchRequest, opts ...grpc.CallOption) (*RemoveBatchResponse, error)
+	// Remove every resource stored locally with key in (from, to], owned or
+	// replica copy, and report how many were removed.
+	RemoveRange(ctx context.Context, in *RemoveRangeRequest, opts ...grpc.CallOption) (*RemoveRangeResponse, error)
+	// Stream a copy of every resource stored locally with key in (from, to].
+	// Used by a joining node to pull its range before notifying its successor.
+	RetrieveRange(ctx context.Context, in *RetrieveRangeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RetrieveResponse], error)
 	// Gracefully leave the DHT, notifying the successor that the predecessor leave.
 	// Returns InvalidArgument if the node is not the successor of this node.
 	Leave(ctx context.Context, in *Node, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Sent by a leaving node to its predecessor, so that the predecessor
+	// adopts the leaving node's successors right away instead of waiting
+	// for stabilization. Ignored if the node is not the callee's successor.
+	PredecessorLeaving(ctx context.Context, in *PredecessorLeavingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Return part of the Merkle summary of the resources stored locally with
+	// key in (from, to], so that a neighbor can find and transfer only the
+	// resources on which the two nodes differ (anti-entropy).
+	SyncDigest(ctx context.Context, in *SyncDigestRequest, opts ...grpc.CallOption) (*SyncDigestResponse, error)
 }
 
 type dHTClient struct {
@@ -78,6 +109,26 @@ func (c *dHTClient) FindSuccessor(ctx context.Context, in *FindSuccessorRequest,
 	return out, nil
 }
 
+func (c *dHTClient) FindSuccessorNextHop(ctx context.Context, in *FindSuccessorRequest, opts ...grpc.CallOption) (*NextHopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NextHopResponse)
+	err := c.cc.Invoke(ctx, DHT_FindSuccessorNextHop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dHTClient) FindPredecessor(ctx context.Context, in *FindSuccessorRequest, opts ...grpc.CallOption) (*FindSuccessorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindSuccessorResponse)
+	err := c.cc.Invoke(ctx, DHT_FindPredecessor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *dHTClient) GetPredecessor(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Node, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Node)
@@ -118,18 +169,18 @@ func (c *dHTClient) Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.Ca
 	return out, nil
 }
 
-func (c *dHTClient) Store(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StoreRequest, emptypb.Empty], error) {
+func (c *dHTClient) Store(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StoreRequest, StoreResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &DHT_ServiceDesc.Streams[0], DHT_Store_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[StoreRequest, emptypb.Empty]{ClientStream: stream}
+	x := &grpc.GenericClientStream[StoreRequest, StoreResponse]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type DHT_StoreClient = grpc.ClientStreamingClient[StoreRequest, emptypb.Empty]
+type DHT_StoreClient = grpc.ClientStreamingClient[StoreRequest, StoreResponse]
 
 func (c *dHTClient) Retrieve(ctx context.Context, in *RetrieveRequest, opts ...grpc.CallOption) (*RetrieveResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
@@ -151,6 +202,45 @@ func (c *dHTClient) Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.
 	return out, nil
 }
 
+func (c *dHTClient) RemoveBatch(ctx context.Context, in *RemoveBatchRequest, opts ...grpc.CallOption) (*RemoveBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveBatchResponse)
+	err := c.cc.Invoke(ctx, DHT_RemoveBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dHTClient) RemoveRange(ctx context.Context, in *RemoveRangeRequest, opts ...grpc.CallOption) (*RemoveRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveRangeResponse)
+	err := c.cc.Invoke(ctx, DHT_RemoveRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dHTClient) RetrieveRange(ctx context.Context, in *RetrieveRangeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RetrieveResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DHT_ServiceDesc.Streams[1], DHT_RetrieveRange_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RetrieveRangeRequest, RetrieveResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DHT_RetrieveRangeClient = grpc.ServerStreamingClient[RetrieveResponse]
+
 func (c *dHTClient) Leave(ctx context.Context, in *Node, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -161,6 +251,26 @@ func (c *dHTClient) Leave(ctx context.Context, in *Node, opts ...grpc.CallOption
 	return out, nil
 }
 
+func (c *dHTClient) PredecessorLeaving(ctx context.Context, in *PredecessorLeavingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, DHT_PredecessorLeaving_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dHTClient) SyncDigest(ctx context.Context, in *SyncDigestRequest, opts ...grpc.CallOption) (*SyncDigestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncDigestResponse)
+	err := c.cc.Invoke(ctx, DHT_SyncDigest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DHTServer is the server API for DHT service.
 // All implementations must embed UnimplementedDHTServer
 // for forward compatibility.
@@ -168,6 +278,13 @@ type DHTServer interface {
 	// Lookup in the Koorde overlay.
 	// Returns the successor responsible for target_id.
 	FindSuccessor(context.Context, *FindSuccessorRequest) (*FindSuccessorResponse, error)
+	// Compute only the next step of a lookup, without forwarding it
+	// (iterative lookup: the originator drives the loop).
+	FindSuccessorNextHop(context.Context, *FindSuccessorRequest) (*NextHopResponse, error)
+	// Lookup of the predecessor of the node responsible for target_id:
+	// same routing and modes as FindSuccessor, but the node n with
+	// target_id in (n, successor(n)] returns itself instead of its successor.
+	FindPredecessor(context.Context, *FindSuccessorRequest) (*FindSuccessorResponse, error)
 	// Returns this node's predecessor.
 	GetPredecessor(context.Context, *emptypb.Empty) (*Node, error)
 	// Returns this node's successor list.
@@ -178,16 +295,33 @@ type DHTServer interface {
 	// Ping to check liveness of the node (debug).
 	Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	// Store a resource (Put). If the key already exists, overwrite it.
-	Store(grpc.ClientStreamingServer[StoreRequest, emptypb.Empty]) error
+	// The owner assigns the version of new writes (see Resource.version).
+	Store(grpc.ClientStreamingServer[StoreRequest, StoreResponse]) error
 	// Retrieve a resource (Get).
 	// Returns NotFound if the key does not exist.
 	Retrieve(context.Context, *RetrieveRequest) (*RetrieveResponse, error)
 	// Remove a resource (Delete).
 	// Returns NotFound if the key does not exist.
 	Remove(context.Context, *RemoveRequest) (*emptypb.Empty, error)
+	// Remove a batch of resources, reporting for each key whether it existed.
+	RemoveBatch(context.Context, *RemoveBatchRequest) (*RemoveBatchResponse, error)
+	// Remove every resource stored locally with key in (from, to], owned or
+	// replica copy, and report how many were removed.
+	RemoveRange(context.Context, *RemoveRangeRequest) (*RemoveRangeResponse, error)
+	// Stream a copy of every resource stored locally with key in (from, to].
+	// Used by a joining node to pull its range before notifying its successor.
+	RetrieveRange(*RetrieveRangeRequest, grpc.ServerStreamingServer[RetrieveResponse]) error
 	// Gracefully leave the DHT, notifying the successor that the predecessor leave.
 	// Returns InvalidArgument if the node is not the successor of this node.
 	Leave(context.Context, *Node) (*emptypb.Empty, error)
+	// Sent by a leaving node to its predecessor, so that the predecessor
+	// adopts the leaving node's successors right away instead of waiting
+	// for stabilization. Ignored if the node is not the callee's successor.
+	PredecessorLeaving(context.Context, *PredecessorLeavingRequest) (*emptypb.Empty, error)
+	// Return part of the Merkle summary of the resources stored locally with
+	// key in (from, to], so that a neighbor can find and transfer only the
+	// resources on which the two nodes differ (anti-entropy).
+	SyncDigest(context.Context, *SyncDigestRequest) (*SyncDigestResponse, error)
 	mustEmbedUnimplementedDHTServer()
 }
 
@@ -201,6 +335,12 @@ type UnimplementedDHTServer struct{}
 func (UnimplementedDHTServer) FindSuccessor(context.Context, *FindSuccessorRequest) (*FindSuccessorResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindSuccessor not implemented")
 }
+func (UnimplementedDHTServer) FindSuccessorNextHop(context.Context, *FindSuccessorRequest) (*NextHopResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindSuccessorNextHop not implemented")
+}
+func (UnimplementedDHTServer) FindPredecessor(context.Context, *FindSuccessorRequest) (*FindSuccessorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindPredecessor not implemented")
+}
 func (UnimplementedDHTServer) GetPredecessor(context.Context, *emptypb.Empty) (*Node, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPredecessor not implemented")
 }
@@ -213,7 +353,7 @@ func (UnimplementedDHTServer) Notify(context.Context, *Node) (*emptypb.Empty, er
 func (UnimplementedDHTServer) Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
-func (UnimplementedDHTServer) Store(grpc.ClientStreamingServer[StoreRequest, emptypb.Empty]) error {
+func (UnimplementedDHTServer) Store(grpc.ClientStreamingServer[StoreRequest, StoreResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method Store not implemented")
 }
 func (UnimplementedDHTServer) Retrieve(context.Context, *RetrieveRequest) (*RetrieveResponse, error) {
@@ -222,9 +362,24 @@ func (UnimplementedDHTServer) Retrieve(context.Context, *RetrieveRequest) (*Retr
 func (UnimplementedDHTServer) Remove(context.Context, *RemoveRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Remove not implemented")
 }
+func (UnimplementedDHTServer) RemoveBatch(context.Context, *RemoveBatchRequest) (*RemoveBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveBatch not implemented")
+}
+func (UnimplementedDHTServer) RemoveRange(context.Context, *RemoveRangeRequest) (*RemoveRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRange not implemented")
+}
+func (UnimplementedDHTServer) RetrieveRange(*RetrieveRangeRequest, grpc.ServerStreamingServer[RetrieveResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method RetrieveRange not implemented")
+}
 func (UnimplementedDHTServer) Leave(context.Context, *Node) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Leave not implemented")
 }
+func (UnimplementedDHTServer) PredecessorLeaving(context.Context, *PredecessorLeavingRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PredecessorLeaving not implemented")
+}
+func (UnimplementedDHTServer) SyncDigest(context.Context, *SyncDigestRequest) (*SyncDigestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncDigest not implemented")
+}
 func (UnimplementedDHTServer) mustEmbedUnimplementedDHTServer() {}
 func (UnimplementedDHTServer) testEmbeddedByValue()             {}
 
@@ -264,6 +419,42 @@ func _DHT_FindSuccessor_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DHT_FindSuccessorNextHop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindSuccessorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DHTServer).FindSuccessorNextHop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DHT_FindSuccessorNextHop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DHTServer).FindSuccessorNextHop(ctx, req.(*FindSuccessorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DHT_FindPredecessor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindSuccessorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DHTServer).FindPredecessor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DHT_FindPredecessor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DHTServer).FindPredecessor(ctx, req.(*FindSuccessorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DHT_GetPredecessor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -337,11 +528,11 @@ func _DHT_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{
 }
 
 func _DHT_Store_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(DHTServer).Store(&grpc.GenericServerStream[StoreRequest, emptypb.Empty]{ServerStream: stream})
+	return srv.(DHTServer).Store(&grpc.GenericServerStream[StoreRequest, StoreResponse]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type DHT_StoreServer = grpc.ClientStreamingServer[StoreRequest, emptypb.Empty]
+type DHT_StoreServer = grpc.ClientStreamingServer[StoreRequest, StoreResponse]
 
 func _DHT_Retrieve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RetrieveRequest)
@@ -379,6 +570,53 @@ func _DHT_Remove_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DHT_RemoveBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DHTServer).RemoveBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DHT_RemoveBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DHTServer).RemoveBatch(ctx, req.(*RemoveBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DHT_RemoveRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DHTServer).RemoveRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DHT_RemoveRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DHTServer).RemoveRange(ctx, req.(*RemoveRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DHT_RetrieveRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RetrieveRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DHTServer).RetrieveRange(m, &grpc.GenericServerStream[RetrieveRangeRequest, RetrieveResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DHT_RetrieveRangeServer = grpc.ServerStreamingServer[RetrieveResponse]
+
 func _DHT_Leave_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Node)
 	if err := dec(in); err != nil {
@@ -397,6 +635,42 @@ func _DHT_Leave_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DHT_PredecessorLeaving_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PredecessorLeavingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DHTServer).PredecessorLeaving(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DHT_PredecessorLeaving_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DHTServer).PredecessorLeaving(ctx, req.(*PredecessorLeavingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DHT_SyncDigest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncDigestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DHTServer).SyncDigest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DHT_SyncDigest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DHTServer).SyncDigest(ctx, req.(*SyncDigestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DHT_ServiceDesc is the grpc.ServiceDesc for DHT service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -408,6 +682,14 @@ var DHT_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FindSuccessor",
 			Handler:    _DHT_FindSuccessor_Handler,
 		},
+		{
+			MethodName: "FindSuccessorNextHop",
+			Handler:    _DHT_FindSuccessorNextHop_Handler,
+		},
+		{
+			MethodName: "FindPredecessor",
+			Handler:    _DHT_FindPredecessor_Handler,
+		},
 		{
 			MethodName: "GetPredecessor",
 			Handler:    _DHT_GetPredecessor_Handler,
@@ -432,10 +714,26 @@ var DHT_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Remove",
 			Handler:    _DHT_Remove_Handler,
 		},
+		{
+			MethodName: "RemoveBatch",
+			Handler:    _DHT_RemoveBatch_Handler,
+		},
+		{
+			MethodName: "RemoveRange",
+			Handler:    _DHT_RemoveRange_Handler,
+		},
 		{
 			MethodName: "Leave",
 			Handler:    _DHT_Leave_Handler,
 		},
+		{
+			MethodName: "PredecessorLeaving",
+			Handler:    _DHT_PredecessorLeaving_Handler,
+		},
+		{
+			MethodName: "SyncDigest",
+			Handler:    _DHT_SyncDigest_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -443,6 +741,11 @@ var DHT_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _DHT_Store_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "RetrieveRange",
+			Handler:       _DHT_RetrieveRange_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "dht/v1/node.proto",
 }
@@ -0,0 +1,189 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/configloader"
+	"KoordeDHT/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcdClient is the subset of the etcd client used by EtcdBootstrap.
+type etcdClient interface {
+	Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error)
+	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+	Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error)
+	Revoke(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error)
+	KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error)
+}
+
+// EtcdBootstrap keeps the membership of the DHT in etcd: every registered
+// node owns the key <prefix><id> -> addr, attached to a lease kept alive
+// while the node runs. A node that crashes stops renewing its lease and
+// etcd deletes its key once the lease TTL expires, so Discover never
+// returns it afterwards.
+type EtcdBootstrap struct {
+	cli    etcdClient
+	prefix string // ends with "/"
+	ttl    int64  // lease TTL in seconds
+	self   string // advertised address of this node, filtered out of Discover
+
+	mu    sync.Mutex   // serializes Register and Deregister
+	lease atomic.Int64 // lease of the registered node (0 = not registered)
+	stop  context.CancelFunc
+	done  chan struct{} // closed when the keepalive loop returns
+}
+
+func init() {
+	Register("etcd", func(cfg Config) (Bootstrap, error) {
+		return NewEtcdBootstrap(cfg.Etcd, cfg.SelfAddr)
+	})
+}
+
+// NewEtcdBootstrap connects to the etcd cluster of cfg.Endpoints. The
+// connection is established lazily: an unreachable cluster makes the
+// first Discover or Register fail, not the constructor. selfAddr is the
+// advertised address of this node: Discover never returns it, e.g. from
+// the registration of a previous run whose lease has not expired yet.
+func NewEtcdBootstrap(cfg configloader.EtcdConfig, selfAddr string) (*EtcdBootstrap, error) {
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   cfg.Endpoints,
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("etcd: failed to create client: %w", err)
+	}
+	return newEtcdBootstrap(cli, cfg, selfAddr), nil
+}
+
+func newEtcdBootstrap(cli etcdClient, cfg configloader.EtcdConfig, selfAddr string) *EtcdBootstrap {
+	prefix := cfg.Prefix
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &EtcdBootstrap{
+		cli:    cli,
+		prefix: prefix,
+		ttl:    max(int64(cfg.LeaseTTL/time.Second), 1),
+		self:   selfAddr,
+	}
+}
+
+// Discover returns the addresses stored under the prefix, i.e. the nodes
+// registered and still alive, except this node.
+func (e *EtcdBootstrap) Discover(ctx context.Context) ([]string, error) {
+	resp, err := e.cli.Get(ctx, e.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("etcd: failed to list %s: %w", e.prefix, err)
+	}
+	peers := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		if addr := string(kv.Value); addr != "" && addr != e.self {
+			peers = append(peers, addr)
+		}
+	}
+	return peers, nil
+}
+
+// Register writes the key of node under a new lease and keeps the lease
+// alive until Deregister. If the lease is lost anyway (e.g. etcd was
+// unreachable for longer than its TTL), the node is registered again as
+// soon as etcd answers. A previous registration is replaced.
+func (e *EtcdBootstrap) Register(ctx context.Context, node *domain.Node) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	// a previous lease that cannot be revoked expires on its own
+	_ = e.deregister(ctx)
+	e.lease.Store(0)
+	kaCtx, stop := context.WithCancel(context.Background())
+	ch, err := e.register(ctx, kaCtx, node)
+	if err != nil {
+		stop()
+		return err
+	}
+	e.stop, e.done = stop, make(chan struct{})
+	go e.keepAlive(kaCtx, node, ch, e.done)
+	return nil
+}
+
+// register grants a lease, writes the key of node under it and starts the
+// keepalive of the lease, which runs until kaCtx is done.
+func (e *EtcdBootstrap) register(ctx, kaCtx context.Context, node *domain.Node) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	grant, err := e.cli.Grant(ctx, e.ttl)
+	if err != nil {
+		return nil, fmt.Errorf("etcd: failed to grant lease: %w", err)
+	}
+	key := e.key(node)
+	if _, err := e.cli.Put(ctx, key, node.Addr, clientv3.WithLease(grant.ID)); err != nil {
+		_, _ = e.cli.Revoke(context.WithoutCancel(ctx), grant.ID)
+		return nil, fmt.Errorf("etcd: failed to put %s: %w", key, err)
+	}
+	ch, err := e.cli.KeepAlive(kaCtx, grant.ID)
+	if err != nil {
+		_, _ = e.cli.Revoke(context.WithoutCancel(ctx), grant.ID)
+		return nil, fmt.Errorf("etcd: failed to keep lease alive: %w", err)
+	}
+	e.lease.Store(int64(grant.ID))
+	return ch, nil
+}
+
+// keepAlive drains the keepalive responses of the lease of node until ctx
+// is done. When the channel closes before, the lease is lost and the node
+// is registered again, retrying every third of the TTL.
+func (e *EtcdBootstrap) keepAlive(ctx context.Context, node *domain.Node, ch <-chan *clientv3.LeaseKeepAliveResponse, done chan struct{}) {
+	defer close(done)
+	retry := time.Duration(e.ttl) * time.Second / 3
+	for {
+		if ch != nil {
+			for range ch { // closed when the lease is lost or ctx is done
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		ch, _ = e.register(ctx, ctx, node)
+	}
+}
+
+// Deregister stops the keepalive and revokes the lease, deleting the key
+// of the node at once rather than after the TTL.
+func (e *EtcdBootstrap) Deregister(ctx context.Context, node *domain.Node) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.deregister(ctx); err != nil {
+		return fmt.Errorf("%w (key %s)", err, e.key(node))
+	}
+	return nil
+}
+
+// deregister stops the keepalive loop, if any, and revokes the current
+// lease; a lease already expired counts as revoked. The caller holds e.mu.
+func (e *EtcdBootstrap) deregister(ctx context.Context) error {
+	if e.stop != nil {
+		e.stop()
+		<-e.done
+		e.stop, e.done = nil, nil
+	}
+	lease := clientv3.LeaseID(e.lease.Load())
+	if lease == 0 {
+		return nil
+	}
+	if _, err := e.cli.Revoke(ctx, lease); err != nil && !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		return fmt.Errorf("etcd: failed to revoke lease %x: %w", int64(lease), err)
+	}
+	e.lease.Store(0)
+	return nil
+}
+
+func (e *EtcdBootstrap) key(node *domain.Node) string {
+	return e.prefix + node.ID.ToHexString(true)
+}
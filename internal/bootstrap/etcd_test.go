@@ -0,0 +1,211 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/configloader"
+	"KoordeDHT/internal/domain"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeEtcd simula le chiavi e i lease di etcd: la scadenza di un lease
+// (expire) cancella le sue chiavi e chiude il canale del keepalive.
+type fakeEtcd struct {
+	mu     sync.Mutex
+	next   clientv3.LeaseID
+	kvs    map[string]string
+	owner  map[string]clientv3.LeaseID // lease di ogni chiave
+	leases map[clientv3.LeaseID]chan *clientv3.LeaseKeepAliveResponse
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{
+		kvs:    make(map[string]string),
+		owner:  make(map[string]clientv3.LeaseID),
+		leases: make(map[clientv3.LeaseID]chan *clientv3.LeaseKeepAliveResponse),
+	}
+}
+
+func (f *fakeEtcd) Put(_ context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.kvs[key] = val
+	f.owner[key] = f.next // le Put del bootstrap usano sempre l'ultimo lease concesso
+	return &clientv3.PutResponse{}, nil
+}
+
+func (f *fakeEtcd) Get(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := &clientv3.GetResponse{}
+	for k, v := range f.kvs {
+		if strings.HasPrefix(k, key) {
+			resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(k), Value: []byte(v)})
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeEtcd) Grant(_ context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.next++
+	f.leases[f.next] = make(chan *clientv3.LeaseKeepAliveResponse)
+	return &clientv3.LeaseGrantResponse{ID: f.next, TTL: ttl}, nil
+}
+
+func (f *fakeEtcd) Revoke(_ context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	if !f.expire(id) {
+		return nil, rpctypes.ErrLeaseNotFound
+	}
+	return &clientv3.LeaseRevokeResponse{}, nil
+}
+
+func (f *fakeEtcd) KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	f.mu.Lock()
+	ch, ok := f.leases[id]
+	f.mu.Unlock()
+	if !ok {
+		return nil, rpctypes.ErrLeaseNotFound
+	}
+	// come nel client reale, il canale si chiude con il contesto
+	go func() {
+		<-ctx.Done()
+		f.expire(id)
+	}()
+	return ch, nil
+}
+
+// expire cancella il lease id e le sue chiavi, riportando se esisteva.
+func (f *fakeEtcd) expire(id clientv3.LeaseID) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ch, ok := f.leases[id]
+	if !ok {
+		return false
+	}
+	delete(f.leases, id)
+	close(ch)
+	for k, l := range f.owner {
+		if l == id {
+			delete(f.owner, k)
+			delete(f.kvs, k)
+		}
+	}
+	return true
+}
+
+func (f *fakeEtcd) leaseOf(key string) clientv3.LeaseID {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.owner[key]
+}
+
+func TestEtcdBootstrap(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 2)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	node := func(addr string) *domain.Node { return &domain.Node{ID: sp.NewIdFromString(addr), Addr: addr} }
+	a, b := node("10.0.0.1:4000"), node("10.0.0.2:4000")
+	cfg := configloader.EtcdConfig{Prefix: "/koorde/nodes", LeaseTTL: time.Second}
+	ctx := context.Background()
+
+	discover := func(t *testing.T, e *EtcdBootstrap) []string {
+		t.Helper()
+		peers, err := e.Discover(ctx)
+		if err != nil {
+			t.Fatalf("Discover: %v", err)
+		}
+		slices.Sort(peers)
+		return peers
+	}
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, cli *fakeEtcd, ea, eb *EtcdBootstrap)
+	}{
+		{name: "register and deregister", run: func(t *testing.T, cli *fakeEtcd, ea, eb *EtcdBootstrap) {
+			// una chiave fuori dal prefisso non è un nodo
+			_, _ = cli.Put(ctx, "/koorde/other", "10.0.0.9:4000")
+			if got := discover(t, eb); len(got) != 0 {
+				t.Fatalf("Discover before Register = %v, want none", got)
+			}
+			if err := ea.Register(ctx, a); err != nil {
+				t.Fatalf("Register(a): %v", err)
+			}
+			if err := eb.Register(ctx, b); err != nil {
+				t.Fatalf("Register(b): %v", err)
+			}
+			// il nodo stesso non compare tra i peer
+			if got, want := discover(t, ea), []string{b.Addr}; !slices.Equal(got, want) {
+				t.Errorf("Discover from a = %v, want %v", got, want)
+			}
+			if err := ea.Deregister(ctx, a); err != nil {
+				t.Fatalf("Deregister(a): %v", err)
+			}
+			if got := discover(t, eb); len(got) != 0 {
+				t.Errorf("Discover after Deregister = %v, want none", got)
+			}
+		}},
+		{name: "crashed node expires", run: func(t *testing.T, cli *fakeEtcd, ea, eb *EtcdBootstrap) {
+			if err := ea.Register(ctx, a); err != nil {
+				t.Fatalf("Register(a): %v", err)
+			}
+			// arresto senza Deregister: etcd revoca il lease alla scadenza del TTL
+			ea.mu.Lock()
+			ea.stop()
+			<-ea.done
+			ea.stop = nil
+			ea.mu.Unlock()
+			if got := discover(t, eb); len(got) != 0 {
+				t.Errorf("Discover after the lease expired = %v, want none", got)
+			}
+			// la deregistrazione di un lease già scaduto non è un errore
+			if err := ea.Deregister(ctx, a); err != nil {
+				t.Errorf("Deregister after expiry: %v", err)
+			}
+		}},
+		{name: "lost lease registered again", run: func(t *testing.T, cli *fakeEtcd, ea, eb *EtcdBootstrap) {
+			if err := ea.Register(ctx, a); err != nil {
+				t.Fatalf("Register(a): %v", err)
+			}
+			key := ea.key(a)
+			lost := cli.leaseOf(key)
+			cli.expire(lost) // etcd irraggiungibile oltre il TTL
+			deadline := time.Now().Add(3 * time.Second)
+			for l := cli.leaseOf(key); l == 0 || l == lost; l = cli.leaseOf(key) {
+				if time.Now().After(deadline) {
+					t.Fatal("node not registered again after losing its lease")
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+			if got, want := discover(t, eb), []string{a.Addr}; !slices.Equal(got, want) {
+				t.Errorf("Discover = %v, want %v", got, want)
+			}
+			if err := ea.Deregister(ctx, a); err != nil {
+				t.Fatalf("Deregister(a): %v", err)
+			}
+			if got := discover(t, eb); len(got) != 0 {
+				t.Errorf("Discover after Deregister = %v, want none", got)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newFakeEtcd()
+			ea := newEtcdBootstrap(cli, cfg, a.Addr)
+			eb := newEtcdBootstrap(cli, cfg, b.Addr)
+			tt.run(t, cli, ea, eb)
+			_ = ea.Deregister(ctx, a)
+			_ = eb.Deregister(ctx, b)
+		})
+	}
+}
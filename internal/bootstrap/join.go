@@ -0,0 +1,86 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// JoinPolicy controls how JoinWithRetry retries a failed join.
+type JoinPolicy struct {
+	Retries         int           // joins retried after the first failure (0 = a single attempt)
+	Interval        time.Duration // delay before the first retry, doubled after every failure
+	DiscoverTimeout time.Duration // timeout of each Discover (0 = only the caller's context)
+	// Permanent reports the join errors not worth retrying (e.g. an ID
+	// collision); nil retries every error.
+	Permanent func(error) bool
+}
+
+// errNoPeers is the error of an attempt whose Discover found no peer after
+// an earlier one did: the peers are down (e.g. a rolling deployment) and
+// creating a new DHT would split the ring.
+var errNoPeers = errors.New("no bootstrap peers found")
+
+// JoinWithRetry discovers the peers through b and passes them to join,
+// retrying a failed Discover or join up to policy.Retries times with
+// exponential backoff. Every retry runs Discover again, so that the peers
+// replaced in the meantime are found. Every failed attempt is logged at
+// WARN level through lgr (nil disables logging).
+//
+// It reports false, without calling join, if the first Discover that
+// succeeds finds no peer: the caller is the first node and creates a new
+// DHT. The last error is returned once the retries are exhausted or ctx
+// is done.
+func JoinWithRetry(ctx context.Context, b Bootstrap, join func(peers []string) error, policy JoinPolicy, lgr logger.Logger) (bool, error) {
+	if lgr == nil {
+		lgr = &logger.NopLogger{}
+	}
+	attempts := max(policy.Retries, 0) + 1
+	backoff := policy.Interval
+	seen := false // some Discover returned peers
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var peers []string
+		peers, err = discover(ctx, b, policy.DiscoverTimeout)
+		switch {
+		case err != nil:
+			err = fmt.Errorf("discover: %w", err)
+		case len(peers) == 0 && !seen:
+			return false, nil
+		case len(peers) == 0:
+			err = errNoPeers
+		default:
+			seen = true
+			lgr.Info("resolved bootstrap peers", logger.F("peers", peers), logger.F("attempt", attempt))
+			if err = join(peers); err == nil {
+				return true, nil
+			}
+			if policy.Permanent != nil && policy.Permanent(err) {
+				return false, err
+			}
+		}
+		lgr.Warn("bootstrap: join attempt failed",
+			logger.F("attempt", attempt), logger.F("of", attempts), logger.F("err", err))
+		if attempt == attempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return false, fmt.Errorf("bootstrap: join: %w (last error: %v)", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return false, fmt.Errorf("bootstrap: join failed after %d attempt(s): %w", attempts, err)
+}
+
+func discover(ctx context.Context, b Bootstrap, timeout time.Duration) ([]string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return b.Discover(ctx)
+}
@@ -0,0 +1,89 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// scriptedBootstrap restituisce in ordine i risultati di discoveries,
+// ripetendo l'ultimo.
+type scriptedBootstrap struct {
+	StaticBootstrap
+	discoveries [][]string // nil = errore
+	calls       int
+}
+
+func (s *scriptedBootstrap) Discover(ctx context.Context) ([]string, error) {
+	peers := s.discoveries[min(s.calls, len(s.discoveries)-1)]
+	s.calls++
+	if peers == nil {
+		return nil, errors.New("discovery unavailable")
+	}
+	return peers, nil
+}
+
+func TestJoinWithRetry(t *testing.T) {
+	errDown := errors.New("all peers down")
+	errCollision := errors.New("id collision")
+	tests := []struct {
+		name        string
+		retries     int
+		discoveries [][]string
+		joins       []error // esito delle join in ordine, nil = successo
+		wantJoined  bool
+		wantErr     bool
+		wantJoins   [][]string // peer passati a ogni join
+	}{
+		{name: "first node", retries: 3, discoveries: [][]string{{}}},
+		{name: "joined at once", retries: 3, discoveries: [][]string{{"a:1"}}, joins: []error{nil},
+			wantJoined: true, wantJoins: [][]string{{"a:1"}}},
+		// rolling deploy: i peer vengono sostituiti tra un tentativo e l'altro
+		{name: "peers replaced", retries: 3, discoveries: [][]string{{"a:1"}, {}, {"b:1"}}, joins: []error{errDown, nil},
+			wantJoined: true, wantJoins: [][]string{{"a:1"}, {"b:1"}}},
+		{name: "discovery down then up", retries: 3, discoveries: [][]string{nil, {"a:1"}}, joins: []error{nil},
+			wantJoined: true, wantJoins: [][]string{{"a:1"}}},
+		{name: "retries exhausted", retries: 2, discoveries: [][]string{{"a:1"}}, joins: []error{errDown, errDown, errDown},
+			wantErr: true, wantJoins: [][]string{{"a:1"}, {"a:1"}, {"a:1"}}},
+		{name: "no retries", discoveries: [][]string{{"a:1"}}, joins: []error{errDown},
+			wantErr: true, wantJoins: [][]string{{"a:1"}}},
+		{name: "permanent error", retries: 3, discoveries: [][]string{{"a:1"}}, joins: []error{errCollision},
+			wantErr: true, wantJoins: [][]string{{"a:1"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &scriptedBootstrap{discoveries: tt.discoveries}
+			var got [][]string
+			join := func(peers []string) error {
+				got = append(got, peers)
+				return tt.joins[len(got)-1]
+			}
+			joined, err := JoinWithRetry(context.Background(), b, join, JoinPolicy{
+				Retries:   tt.retries,
+				Interval:  time.Millisecond,
+				Permanent: func(err error) bool { return errors.Is(err, errCollision) },
+			}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JoinWithRetry: err = %v, want error %v", err, tt.wantErr)
+			}
+			if joined != tt.wantJoined {
+				t.Errorf("joined = %v, want %v", joined, tt.wantJoined)
+			}
+			if !slices.EqualFunc(got, tt.wantJoins, slices.Equal) {
+				t.Errorf("joins = %v, want %v", got, tt.wantJoins)
+			}
+		})
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		b := &scriptedBootstrap{discoveries: [][]string{nil}}
+		_, err := JoinWithRetry(ctx, b, nil, JoinPolicy{Retries: 10, Interval: time.Hour}, nil)
+		if !errors.Is(err, context.Canceled) || b.calls != 1 {
+			t.Errorf("got %v after %d discoveries, want Canceled after 1", err, b.calls)
+		}
+	})
+}
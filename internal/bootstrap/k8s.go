@@ -0,0 +1,153 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/configloader"
+	"KoordeDHT/internal/domain"
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// K8sBootstrap discovers peers through the DNS records of a Kubernetes
+// headless service (e.g. the governing service of a StatefulSet): every
+// ready pod backing the service has an A record under
+// <service>.<namespace>.svc.<clusterDomain> and, for each named port, an SRV
+// record under _<port>._tcp.<service>.<namespace>.svc.<clusterDomain>.
+//
+// Kubernetes maintains the endpoints of the service, so Register and
+// Deregister do nothing.
+type K8sBootstrap struct {
+	host     string // FQDN of the headless service
+	portName string // named port of the service ("" = A records, with the port of this node)
+	selfHost string // advertised host of this node, filtered out of Discover
+	selfPort int    // advertised port of this node
+
+	lookupSRV  func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+	localAddrs func() ([]net.Addr, error) // addresses of this pod, filtered out of Discover
+}
+
+func init() {
+	Register("k8s", func(cfg Config) (Bootstrap, error) {
+		return NewK8sBootstrap(cfg.K8s, cfg.SelfAddr)
+	})
+}
+
+// NewK8sBootstrap returns a bootstrap for the headless service described
+// by cfg. selfAddr is the advertised address of this node: Discover never
+// returns it, nor the addresses of the local interfaces with its port.
+// If cfg.DNSServer is set, the records are resolved by that server
+// (host:port, e.g. the kube-dns service) instead of the resolver of the pod.
+func NewK8sBootstrap(cfg configloader.K8sConfig, selfAddr string) (*K8sBootstrap, error) {
+	host, strPort, err := net.SplitHostPort(selfAddr)
+	if err != nil {
+		return nil, fmt.Errorf("k8s: invalid self address %q: %w", selfAddr, err)
+	}
+	port, err := strconv.Atoi(strPort)
+	if err != nil {
+		return nil, fmt.Errorf("k8s: invalid self port %q: %w", strPort, err)
+	}
+	resolver := net.DefaultResolver
+	if cfg.DNSServer != "" {
+		server := cfg.DNSServer
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				d := net.Dialer{Timeout: 5 * time.Second}
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+	clusterDomain := strings.Trim(cfg.ClusterDomain, ".")
+	if clusterDomain == "" {
+		clusterDomain = "cluster.local"
+	}
+	return &K8sBootstrap{
+		host:       fmt.Sprintf("%s.%s.svc.%s.", cfg.ServiceName, cfg.Namespace, clusterDomain),
+		portName:   cfg.PortName,
+		selfHost:   host,
+		selfPort:   port,
+		lookupSRV:  resolver.LookupSRV,
+		lookupHost: resolver.LookupHost,
+		localAddrs: net.InterfaceAddrs,
+	}, nil
+}
+
+// Discover resolves the pods behind the headless service: through the SRV
+// records of the named port if one is configured, through the A/AAAA
+// records (with the port of this node) otherwise. This node is excluded.
+func (k *K8sBootstrap) Discover(ctx context.Context) ([]string, error) {
+	var endpoints []string
+	if k.portName != "" {
+		_, srvs, err := k.lookupSRV(ctx, k.portName, "tcp", k.host)
+		if err != nil {
+			return nil, fmt.Errorf("k8s: lookup SRV of %s: %w", k.host, err)
+		}
+		for _, srv := range srvs {
+			target := strings.TrimSuffix(srv.Target, ".")
+			ips, err := k.lookupHost(ctx, target)
+			if err != nil {
+				continue // pod gone between the two lookups
+			}
+			for _, ip := range ips {
+				endpoints = append(endpoints, net.JoinHostPort(ip, strconv.Itoa(int(srv.Port))))
+			}
+		}
+	} else {
+		ips, err := k.lookupHost(ctx, k.host)
+		if err != nil {
+			return nil, fmt.Errorf("k8s: lookup %s: %w", k.host, err)
+		}
+		for _, ip := range ips {
+			endpoints = append(endpoints, net.JoinHostPort(ip, strconv.Itoa(k.selfPort)))
+		}
+	}
+
+	self := k.selfEndpoints()
+	peers := make([]string, 0, len(endpoints))
+	seen := make(map[string]bool)
+	for _, e := range endpoints {
+		if !self[e] && !seen[e] {
+			seen[e] = true
+			peers = append(peers, e)
+		}
+	}
+	sort.Strings(peers)
+	return peers, nil
+}
+
+// selfEndpoints returns the endpoints that reach this node: its advertised
+// address and the addresses of the local interfaces with its port.
+func (k *K8sBootstrap) selfEndpoints() map[string]bool {
+	port := strconv.Itoa(k.selfPort)
+	self := map[string]bool{net.JoinHostPort(k.selfHost, port): true}
+	addrs, err := k.localAddrs()
+	if err != nil {
+		return self
+	}
+	for _, a := range addrs {
+		if ipNet, ok := a.(*net.IPNet); ok {
+			self[net.JoinHostPort(ipNet.IP.String(), port)] = true
+		}
+	}
+	return self
+}
+
+// Register does nothing: Kubernetes publishes the pod in the service
+// records once it is ready.
+func (k *K8sBootstrap) Register(ctx context.Context, node *domain.Node) error {
+	return nil
+}
+
+// Deregister does nothing: Kubernetes withdraws the pod from the service
+// records when it terminates.
+func (k *K8sBootstrap) Deregister(ctx context.Context, node *domain.Node) error {
+	return nil
+}
@@ -0,0 +1,92 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/configloader"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestK8sDiscover(t *testing.T) {
+	const svc = "koorde.dht.svc.cluster.local."
+	// Tre pod dietro il service: koorde-1 è questo nodo
+	pods := map[string][]string{
+		"koorde-0.koorde.dht.svc.cluster.local": {"10.0.0.10"},
+		"koorde-1.koorde.dht.svc.cluster.local": {"10.0.0.11"},
+		"koorde-2.koorde.dht.svc.cluster.local": {"10.0.0.12"},
+		svc:                                     {"10.0.0.10", "10.0.0.11", "10.0.0.12"},
+	}
+	lookupHost := func(_ context.Context, host string) ([]string, error) {
+		if ips, ok := pods[host]; ok {
+			return ips, nil
+		}
+		return nil, fmt.Errorf("no such host %s", host)
+	}
+	lookupSRV := func(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		if service != "dht" || proto != "tcp" || name != svc {
+			return "", nil, fmt.Errorf("no SRV for _%s._%s.%s", service, proto, name)
+		}
+		return "", []*net.SRV{
+			{Target: "koorde-0.koorde.dht.svc.cluster.local.", Port: 4000},
+			{Target: "koorde-1.koorde.dht.svc.cluster.local.", Port: 4000},
+			{Target: "koorde-2.koorde.dht.svc.cluster.local.", Port: 4000},
+			{Target: "koorde-3.koorde.dht.svc.cluster.local.", Port: 4000}, // pod terminato
+		}, nil
+	}
+
+	tests := []struct {
+		name     string
+		portName string
+		selfAddr string
+		localIPs []string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "SRV records", portName: "dht", selfAddr: "10.0.0.11:4000",
+			want: []string{"10.0.0.10:4000", "10.0.0.12:4000"}},
+		{name: "A records with the node port", selfAddr: "10.0.0.11:4000",
+			want: []string{"10.0.0.10:4000", "10.0.0.12:4000"}},
+		// indirizzo pubblicizzato come nome DNS: si esclude l'IP dell'interfaccia locale
+		{name: "self filtered by pod IP", selfAddr: "koorde-1.koorde.dht.svc.cluster.local:4000", localIPs: []string{"10.0.0.11"},
+			want: []string{"10.0.0.10:4000", "10.0.0.12:4000"}},
+		{name: "unknown port name", portName: "grpc", selfAddr: "10.0.0.11:4000", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := configloader.K8sConfig{ServiceName: "koorde", Namespace: "dht", PortName: tt.portName}
+			b, err := NewK8sBootstrap(cfg, tt.selfAddr)
+			if err != nil {
+				t.Fatalf("NewK8sBootstrap: %v", err)
+			}
+			b.lookupHost, b.lookupSRV = lookupHost, lookupSRV
+			b.localAddrs = func() ([]net.Addr, error) {
+				var addrs []net.Addr
+				for _, ip := range tt.localIPs {
+					addrs = append(addrs, &net.IPNet{IP: net.ParseIP(ip), Mask: net.CIDRMask(24, 32)})
+				}
+				return addrs, nil
+			}
+
+			got, err := b.Discover(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Discover = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Discover: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Discover = %v, want %v", got, tt.want)
+			}
+			// Register e Deregister non fanno nulla: gli endpoint li gestisce Kubernetes
+			if err := errors.Join(b.Register(context.Background(), nil), b.Deregister(context.Background(), nil)); err != nil {
+				t.Fatalf("Register/Deregister: %v", err)
+			}
+		})
+	}
+}
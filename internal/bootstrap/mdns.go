@@ -0,0 +1,369 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/configloader"
+	"KoordeDHT/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/net/ipv4"
+)
+
+// MDNSBootstrap discovers peers on the local network through multicast DNS
+// (RFC 6762/6763): each registered node answers the queries for the
+// service (e.g. _koorde._tcp.local.) with a PTR record to its instance, an
+// SRV record with its advertised host and port and, if the host is an IP,
+// the address record of the SRV target.
+type MDNSBootstrap struct {
+	service string         // fully qualified service name, e.g. "_koorde._tcp.local."
+	iface   *net.Interface // interface for multicast traffic (nil = system default)
+	timeout time.Duration  // how long Discover collects answers
+	group   *net.UDPAddr   // multicast group the queries and announcements are sent to
+	// listen opens the socket of the responder, joined to group
+	listen func() (net.PacketConn, error)
+
+	mu      sync.Mutex
+	conn    net.PacketConn        // responder socket (nil = not registered)
+	records []dnsmessage.Resource // records answered for the registered node
+	done    chan struct{}         // closed when the responder goroutine returns
+}
+
+const (
+	// mdnsTTL is the TTL, in seconds, of the advertised records.
+	mdnsTTL = 120
+	// mdnsLegacyTTL caps the TTL of unicast answers to one-shot queries
+	// (RFC 6762, section 6.7).
+	mdnsLegacyTTL = 10
+	// mdnsCacheFlush is the class bit marking records owned by one host.
+	mdnsCacheFlush = 1 << 15
+	// mdnsUnicastResponse is the class bit of a question asking for a
+	// unicast answer (QU).
+	mdnsUnicastResponse = 1 << 15
+	// mdnsPort is the port of mDNS responders: queries from other ports are
+	// one-shot queries, answered with unicast.
+	mdnsPort = 5353
+)
+
+var mdnsGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: mdnsPort}
+
+func init() {
+	Register("mdns", func(cfg Config) (Bootstrap, error) {
+		return NewMDNSBootstrap(cfg.MDNS)
+	})
+}
+
+// NewMDNSBootstrap returns an mDNS bootstrap for cfg.Service (default
+// _koorde._tcp) in the .local domain, sending multicast traffic on
+// cfg.Interface (empty = system default).
+func NewMDNSBootstrap(cfg configloader.MDNSConfig) (*MDNSBootstrap, error) {
+	var iface *net.Interface
+	if cfg.Interface != "" {
+		var err error
+		if iface, err = net.InterfaceByName(cfg.Interface); err != nil {
+			return nil, fmt.Errorf("mdns: interface %q: %w", cfg.Interface, err)
+		}
+	}
+	return newMDNSBootstrap(cfg.Service, iface, cfg.Timeout, mdnsGroup, func() (net.PacketConn, error) {
+		return net.ListenMulticastUDP("udp4", iface, mdnsGroup)
+	}), nil
+}
+
+func newMDNSBootstrap(service string, iface *net.Interface, timeout time.Duration, group *net.UDPAddr, listen func() (net.PacketConn, error)) *MDNSBootstrap {
+	if service == "" {
+		service = "_koorde._tcp"
+	}
+	return &MDNSBootstrap{
+		service: strings.TrimSuffix(service, ".") + ".local.",
+		iface:   iface,
+		timeout: timeout,
+		group:   group,
+		listen:  listen,
+	}
+}
+
+// Discover multicasts a one-shot PTR query for the service and returns the
+// host:port of the instances that answer within the configured timeout (or
+// before ctx expires).
+func (m *MDNSBootstrap) Discover(ctx context.Context) ([]string, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, fmt.Errorf("mdns: open query socket: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if m.iface != nil {
+		if err := ipv4.NewPacketConn(conn).SetMulticastInterface(m.iface); err != nil {
+			return nil, fmt.Errorf("mdns: select interface %s: %w", m.iface.Name, err)
+		}
+	}
+
+	service, err := dnsmessage.NewName(m.service)
+	if err != nil {
+		return nil, fmt.Errorf("mdns: service name: %w", err)
+	}
+	query := dnsmessage.Message{
+		Questions: []dnsmessage.Question{{Name: service, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET}},
+	}
+	packet, err := query.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("mdns: pack query: %w", err)
+	}
+	if _, err := conn.WriteTo(packet, m.group); err != nil {
+		return nil, fmt.Errorf("mdns: send query: %w", err)
+	}
+
+	deadline := time.Now().Add(m.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetReadDeadline(deadline)
+
+	instances := make(map[string]bool)              // instances of the service
+	srvs := make(map[string]dnsmessage.SRVResource) // SRV record per instance
+	addrs := make(map[string]string)                // address per SRV target
+	buf := make([]byte, 9000)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				break // collection window over
+			}
+			return nil, fmt.Errorf("mdns: read answers: %w", err)
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil || !msg.Response {
+			continue
+		}
+		for _, rr := range append(msg.Answers, msg.Additionals...) {
+			name := strings.ToLower(rr.Header.Name.String())
+			switch body := rr.Body.(type) {
+			case *dnsmessage.PTRResource:
+				if strings.EqualFold(name, m.service) && rr.Header.TTL > 0 {
+					instances[strings.ToLower(body.PTR.String())] = true
+				}
+			case *dnsmessage.SRVResource:
+				srvs[name] = *body
+			case *dnsmessage.AResource:
+				addrs[name] = netip.AddrFrom4(body.A).String()
+			case *dnsmessage.AAAAResource:
+				addrs[name] = netip.AddrFrom16(body.AAAA).String()
+			}
+		}
+	}
+
+	var endpoints []string
+	for instance := range instances {
+		srv, ok := srvs[instance]
+		if !ok {
+			continue
+		}
+		target := strings.ToLower(srv.Target.String())
+		host, ok := addrs[target]
+		if !ok {
+			host = strings.TrimSuffix(srv.Target.String(), ".")
+		}
+		endpoints = append(endpoints, net.JoinHostPort(host, strconv.Itoa(int(srv.Port))))
+	}
+	sort.Strings(endpoints)
+	return endpoints, nil
+}
+
+// Register advertises the node: it starts answering the queries for the
+// service (or replaces the advertised records, if already registered) and
+// multicasts an announcement of the records.
+func (m *MDNSBootstrap) Register(ctx context.Context, node *domain.Node) error {
+	records, err := m.nodeRecords(node)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.conn == nil {
+		conn, err := m.listen()
+		if err != nil {
+			return fmt.Errorf("mdns: open responder socket: %w", err)
+		}
+		m.conn = conn
+		m.done = make(chan struct{})
+		go m.respond(conn, m.done)
+	}
+	m.records = records
+	// Announcements are best effort: the responder answers queries anyway
+	_ = m.send(m.conn, dnsmessage.Header{Response: true, Authoritative: true}, nil, records, mdnsTTL, true, m.group)
+	return nil
+}
+
+// Deregister withdraws the advertisement: it multicasts the records with
+// TTL 0 (a goodbye, RFC 6762 section 10.1) and stops answering queries.
+func (m *MDNSBootstrap) Deregister(ctx context.Context, node *domain.Node) error {
+	m.mu.Lock()
+	conn, done, records := m.conn, m.done, m.records
+	m.conn, m.records = nil, nil
+	m.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	err := m.send(conn, dnsmessage.Header{Response: true, Authoritative: true}, nil, records, 0, true, m.group)
+	_ = conn.Close()
+	<-done
+	if err != nil {
+		return fmt.Errorf("mdns: send goodbye: %w", err)
+	}
+	return nil
+}
+
+// nodeRecords builds the records advertising node: the PTR record of the
+// service, the SRV and TXT records of the instance and, if the advertised
+// host is an IP, the address record of the SRV target.
+func (m *MDNSBootstrap) nodeRecords(node *domain.Node) ([]dnsmessage.Resource, error) {
+	host, strPort, err := net.SplitHostPort(node.Addr)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(strPort, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+	// Instance labels are limited to 63 bytes: a prefix of the ID suffices
+	label := "koorde-" + node.ID.ToHexString(false)
+	if len(label) > 39 {
+		label = label[:39]
+	}
+	instance, err := dnsmessage.NewName(label + "." + m.service)
+	if err != nil {
+		return nil, err
+	}
+	service, err := dnsmessage.NewName(m.service)
+	if err != nil {
+		return nil, err
+	}
+	ip, ipErr := netip.ParseAddr(host)
+	targetName := strings.TrimSuffix(host, ".") + "."
+	if ipErr == nil {
+		targetName = label + ".local."
+	}
+	target, err := dnsmessage.NewName(targetName)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []dnsmessage.Resource{
+		{Header: dnsmessage.ResourceHeader{Name: service, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET},
+			Body: &dnsmessage.PTRResource{PTR: instance}},
+		{Header: dnsmessage.ResourceHeader{Name: instance, Type: dnsmessage.TypeSRV, Class: dnsmessage.ClassINET},
+			Body: &dnsmessage.SRVResource{Target: target, Port: uint16(port)}},
+		{Header: dnsmessage.ResourceHeader{Name: instance, Type: dnsmessage.TypeTXT, Class: dnsmessage.ClassINET},
+			Body: &dnsmessage.TXTResource{TXT: []string{"id=" + node.ID.ToHexString(false)}}},
+	}
+	switch {
+	case ipErr != nil:
+	case ip.Is4() || ip.Is4In6():
+		records = append(records, dnsmessage.Resource{
+			Header: dnsmessage.ResourceHeader{Name: target, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+			Body:   &dnsmessage.AResource{A: ip.Unmap().As4()}})
+	default:
+		records = append(records, dnsmessage.Resource{
+			Header: dnsmessage.ResourceHeader{Name: target, Type: dnsmessage.TypeAAAA, Class: dnsmessage.ClassINET},
+			Body:   &dnsmessage.AAAAResource{AAAA: ip.As16()}})
+	}
+	return records, nil
+}
+
+// respond answers the queries received on conn that ask for the service or
+// for one of the advertised records, until conn is closed.
+func (m *MDNSBootstrap) respond(conn net.PacketConn, done chan struct{}) {
+	defer close(done)
+	buf := make([]byte, 9000)
+	for {
+		n, src, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil || msg.Response {
+			continue
+		}
+		m.mu.Lock()
+		records := m.records
+		m.mu.Unlock()
+		if !asksFor(msg.Questions, records) {
+			continue
+		}
+		// One-shot queries (from a port other than 5353) get a unicast
+		// answer repeating the query ID and questions; the others a
+		// multicast one, unless they ask for unicast (QU bit)
+		udp, _ := src.(*net.UDPAddr)
+		switch {
+		case udp != nil && udp.Port != mdnsPort:
+			h := dnsmessage.Header{ID: msg.ID, Response: true, Authoritative: true}
+			_ = m.send(conn, h, msg.Questions, records, mdnsLegacyTTL, false, src)
+		case unicastRequested(msg.Questions):
+			_ = m.send(conn, dnsmessage.Header{Response: true, Authoritative: true}, nil, records, mdnsTTL, true, src)
+		default:
+			_ = m.send(conn, dnsmessage.Header{Response: true, Authoritative: true}, nil, records, mdnsTTL, true, m.group)
+		}
+	}
+}
+
+// send writes a response carrying records to dst: the first record (the
+// PTR of the service) as answer, the others as additional records, all
+// with the given TTL. flush sets the cache-flush bit on the records unique
+// to this node.
+func (m *MDNSBootstrap) send(conn net.PacketConn, h dnsmessage.Header, questions []dnsmessage.Question, records []dnsmessage.Resource, ttl uint32, flush bool, dst net.Addr) error {
+	if len(records) == 0 {
+		return nil
+	}
+	msg := dnsmessage.Message{Header: h, Questions: questions}
+	for i, rr := range records {
+		rr.Header.TTL = ttl
+		if flush && rr.Header.Type != dnsmessage.TypePTR {
+			rr.Header.Class |= mdnsCacheFlush
+		}
+		if i == 0 {
+			msg.Answers = append(msg.Answers, rr)
+		} else {
+			msg.Additionals = append(msg.Additionals, rr)
+		}
+	}
+	packet, err := msg.Pack()
+	if err != nil {
+		return err
+	}
+	_, err = conn.WriteTo(packet, dst)
+	return err
+}
+
+// asksFor reports whether one of the questions is about one of records.
+func asksFor(questions []dnsmessage.Question, records []dnsmessage.Resource) bool {
+	for _, q := range questions {
+		for _, rr := range records {
+			if strings.EqualFold(q.Name.String(), rr.Header.Name.String()) &&
+				(q.Type == rr.Header.Type || q.Type == dnsmessage.TypeALL) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unicastRequested reports whether a question asks for a unicast answer.
+func unicastRequested(questions []dnsmessage.Question) bool {
+	for _, q := range questions {
+		if q.Class&mdnsUnicastResponse != 0 {
+			return true
+		}
+	}
+	return false
+}
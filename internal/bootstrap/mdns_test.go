@@ -0,0 +1,88 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/domain"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+// newLoopbackMDNS crea un bootstrap mDNS il cui "gruppo" è un socket unicast
+// su loopback, così il test non dipende dal multicast della macchina. Le
+// query partono da una porta diversa da 5353 e ricevono risposta unicast.
+func newLoopbackMDNS(t *testing.T) *MDNSBootstrap {
+	t.Helper()
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	opened := false
+	return newMDNSBootstrap("", nil, 200*time.Millisecond, conn.LocalAddr().(*net.UDPAddr), func() (net.PacketConn, error) {
+		if opened {
+			return nil, fmt.Errorf("responder socket already used")
+		}
+		opened = true
+		return conn, nil
+	})
+}
+
+func TestMDNSRegisterDiscoverDeregister(t *testing.T) {
+	sp, err := domain.NewSpace(160, 2, 4)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	tests := []struct {
+		name string
+		addr string // indirizzo pubblicizzato
+	}{
+		{name: "ipv4 host", addr: "127.0.0.1:4000"},
+		{name: "hostname", addr: "node-a:4100"},
+		{name: "ipv6 host", addr: "[fd00::1]:4200"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newLoopbackMDNS(t)
+			node := &domain.Node{ID: sp.NewIdFromString(tt.addr), Addr: tt.addr}
+			ctx := context.Background()
+
+			// Prima della registrazione nessuno risponde
+			if peers, err := b.Discover(ctx); err != nil || len(peers) != 0 {
+				t.Fatalf("Discover before Register: %v, %v", peers, err)
+			}
+
+			if err := b.Register(ctx, node); err != nil {
+				t.Fatalf("Register: %v", err)
+			}
+			peers, err := b.Discover(ctx)
+			if err != nil {
+				t.Fatalf("Discover: %v", err)
+			}
+			if len(peers) != 1 || peers[0] != tt.addr {
+				t.Fatalf("Discover = %v, want [%s]", peers, tt.addr)
+			}
+
+			// Una nuova registrazione sostituisce i record pubblicizzati
+			moved := &domain.Node{ID: node.ID, Addr: "127.0.0.2:5000"}
+			if err := b.Register(ctx, moved); err != nil {
+				t.Fatalf("Register again: %v", err)
+			}
+			if peers, err := b.Discover(ctx); err != nil || len(peers) != 1 || peers[0] != moved.Addr {
+				t.Fatalf("Discover after re-Register = %v, %v, want [%s]", peers, err, moved.Addr)
+			}
+
+			// Dopo la deregistrazione il nodo non risponde più
+			if err := b.Deregister(ctx, moved); err != nil {
+				t.Fatalf("Deregister: %v", err)
+			}
+			if peers, err := b.Discover(ctx); err != nil || len(peers) != 0 {
+				t.Fatalf("Discover after Deregister = %v, %v, want none", peers, err)
+			}
+			if err := b.Deregister(ctx, moved); err != nil {
+				t.Fatalf("second Deregister: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"context"
+	"time"
+)
+
+// Refresher periodically re-registers a node with a Bootstrap, so that
+// the registration (e.g. a Route53 record) acts as a heartbeat: discovery
+// can tell the records of live nodes from the ones left behind by nodes
+// that crashed without deregistering.
+type Refresher struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartRefresher calls b.Register(node) every interval in the background
+// until Stop is called. Failures are logged at WARN level through lgr (nil
+// disables logging) and the registration is attempted again at the next
+// tick. A non-positive interval returns a Refresher that does nothing.
+func StartRefresher(b Bootstrap, node *domain.Node, interval time.Duration, lgr logger.Logger) *Refresher {
+	if lgr == nil {
+		lgr = &logger.NopLogger{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Refresher{cancel: cancel, done: make(chan struct{})}
+	if interval <= 0 {
+		close(r.done)
+		return r
+	}
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := b.Register(ctx, node); err != nil && ctx.Err() == nil {
+					lgr.Warn("bootstrap: registration refresh failed", logger.F("err", err))
+				}
+			}
+		}
+	}()
+	return r
+}
+
+// Stop stops the refresher and waits for an in-flight registration to
+// return, so that a Deregister issued afterwards is not undone by a late
+// refresh.
+func (r *Refresher) Stop() {
+	r.cancel()
+	<-r.done
+}
@@ -0,0 +1,79 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/domain"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingBootstrap conta le chiamate a Register e fallisce le prime `failures`.
+type countingBootstrap struct {
+	StaticBootstrap
+	failures int64
+	calls    atomic.Int64
+}
+
+func (c *countingBootstrap) Register(ctx context.Context, node *domain.Node) error {
+	if c.calls.Add(1) <= c.failures {
+		return errors.New("transient error")
+	}
+	return nil
+}
+
+func TestRefresherReRegistersOnInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		failures int64
+		wantMin  int64 // chiamate minime attese in 200ms
+		wantMax  int64
+	}{
+		{name: "refreshes on the interval", interval: 20 * time.Millisecond, wantMin: 5, wantMax: 11},
+		{name: "keeps going after failures", interval: 20 * time.Millisecond, failures: 2, wantMin: 5, wantMax: 11},
+		{name: "disabled", interval: 0, wantMin: 0, wantMax: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &countingBootstrap{failures: tt.failures}
+			r := StartRefresher(b, &domain.Node{Addr: "127.0.0.1:4000"}, tt.interval, nil)
+			time.Sleep(200 * time.Millisecond)
+			r.Stop()
+
+			calls := b.calls.Load()
+			if calls < tt.wantMin || calls > tt.wantMax {
+				t.Fatalf("Register called %d times, want [%d, %d]", calls, tt.wantMin, tt.wantMax)
+			}
+			// Dopo Stop nessuna ri-registrazione, che annullerebbe il Deregister
+			time.Sleep(3 * tt.interval)
+			if after := b.calls.Load(); after != calls {
+				t.Fatalf("Register called %d times after Stop", after-calls)
+			}
+		})
+	}
+}
+
+func TestHeartbeatRoundTrip(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Time
+		wantOk bool
+	}{
+		{name: "written by Register", value: heartbeatValue(now), want: now, wantOk: true},
+		{name: "unquoted", value: "updated=1760000000", want: now, wantOk: true},
+		{name: "other TXT record", value: `"v=spf1 -all"`},
+		{name: "malformed time", value: `"updated=soon"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseHeartbeat(tt.value)
+			if ok != tt.wantOk || !got.Equal(tt.want) {
+				t.Fatalf("parseHeartbeat(%s) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
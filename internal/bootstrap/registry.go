@@ -0,0 +1,71 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/configloader"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Config holds the settings a Factory builds its Bootstrap from.
+type Config struct {
+	configloader.BootstrapConfig
+	// SelfAddr is the advertised address of this node, for the providers
+	// that must tell it apart from the peers they discover (e.g. k8s).
+	SelfAddr string
+	// Extra carries the settings of the providers registered outside this
+	// package (e.g. the docker provider of the tester), nil otherwise.
+	Extra any
+}
+
+// Factory builds the Bootstrap of a mode from its settings.
+type Factory func(cfg Config) (Bootstrap, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes the provider built by f available as mode to New.
+// Providers usually register themselves from an init function. Register
+// panics if f is nil or mode is already registered.
+func Register(mode string, f Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if f == nil {
+		panic("bootstrap: Register of a nil factory for mode " + mode)
+	}
+	if _, dup := factories[mode]; dup {
+		panic("bootstrap: Register called twice for mode " + mode)
+	}
+	factories[mode] = f
+}
+
+// Modes returns the registered modes, sorted.
+func Modes() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	modes := make([]string, 0, len(factories))
+	for mode := range factories {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+// New returns the Bootstrap of mode built from cfg, or an error if mode
+// is not registered or its provider cannot be initialized.
+func New(mode string, cfg Config) (Bootstrap, error) {
+	factoriesMu.RLock()
+	f, ok := factories[mode]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("bootstrap: unsupported mode %q (must be one of %s)", mode, strings.Join(Modes(), ", "))
+	}
+	b, err := f(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("bootstrap: mode %s: %w", mode, err)
+	}
+	return b, nil
+}
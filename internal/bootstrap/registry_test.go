@@ -0,0 +1,71 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/configloader"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	for _, mode := range []string{"etcd", "k8s", "mdns", "route53", "static"} {
+		if !slices.Contains(Modes(), mode) {
+			t.Errorf("mode %s not registered (got %v)", mode, Modes())
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mode    string
+		cfg     Config
+		wantErr string // sottostringa dell'errore atteso, "" se nessuno
+		want    any    // tipo atteso del Bootstrap
+	}{
+		{name: "static", mode: "static",
+			cfg:  Config{BootstrapConfig: configloader.BootstrapConfig{Peers: []string{"a:1", "b:2"}}},
+			want: &StaticBootstrap{}},
+		{name: "k8s", mode: "k8s",
+			cfg:  Config{BootstrapConfig: configloader.BootstrapConfig{K8s: configloader.K8sConfig{ServiceName: "koorde", Namespace: "default"}}, SelfAddr: "10.0.0.1:4000"},
+			want: &K8sBootstrap{}},
+		{name: "provider error", mode: "k8s", cfg: Config{SelfAddr: "no-port"}, wantErr: "mode k8s"},
+		{name: "unknown mode", mode: "carrier-pigeon", wantErr: "unsupported mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := New(tt.mode, tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("New(%s): got %v, want error containing %q", tt.mode, err, tt.wantErr)
+				}
+				if b != nil {
+					t.Errorf("New(%s): got %T together with an error", tt.mode, b)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New(%s): %v", tt.mode, err)
+			}
+			if got, want := fmt.Sprintf("%T", b), fmt.Sprintf("%T", tt.want); got != want {
+				t.Fatalf("New(%s) = %s, want %s", tt.mode, got, want)
+			}
+		})
+	}
+
+	// la modalità static restituisce i peer configurati
+	b, _ := New("static", tests[0].cfg)
+	peers, err := b.Discover(context.Background())
+	if err != nil || !slices.Equal(peers, []string{"a:1", "b:2"}) {
+		t.Errorf("Discover = %v, %v, want the configured peers", peers, err)
+	}
+}
+
+func TestRegisterTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register of an existing mode did not panic")
+		}
+	}()
+	Register("static", func(Config) (Bootstrap, error) { return NewStaticBootstrap(nil), nil })
+}
@@ -0,0 +1,79 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"context"
+	"fmt"
+	"time"
+)
+
+// RetryPolicy controls how Register and Deregister are retried on
+// transient failures (e.g., Route53 throttling or DNS errors).
+type RetryPolicy struct {
+	Attempts int           // total number of attempts (values < 1 mean a single attempt)
+	Timeout  time.Duration // timeout of each attempt (0 = only the caller's context)
+	Backoff  time.Duration // delay before the second attempt, doubled after every failure
+}
+
+// Retrying wraps a Bootstrap so that Register and Deregister are retried
+// with exponential backoff according to a RetryPolicy. Discover is passed
+// through unchanged.
+type Retrying struct {
+	Bootstrap
+	policy RetryPolicy
+	lgr    logger.Logger
+}
+
+// NewRetrying returns b decorated with the given retry policy. Every failed
+// attempt is logged at WARN level through lgr (nil disables logging).
+func NewRetrying(b Bootstrap, policy RetryPolicy, lgr logger.Logger) *Retrying {
+	if lgr == nil {
+		lgr = &logger.NopLogger{}
+	}
+	return &Retrying{Bootstrap: b, policy: policy, lgr: lgr}
+}
+
+// Register registers node, retrying until it succeeds, the attempts are
+// exhausted or ctx is done. The last error is returned on failure.
+func (r *Retrying) Register(ctx context.Context, node *domain.Node) error {
+	return r.retry(ctx, "register", func(ctx context.Context) error {
+		return r.Bootstrap.Register(ctx, node)
+	})
+}
+
+// Deregister removes node, with the same retry semantics as Register.
+func (r *Retrying) Deregister(ctx context.Context, node *domain.Node) error {
+	return r.retry(ctx, "deregister", func(ctx context.Context) error {
+		return r.Bootstrap.Deregister(ctx, node)
+	})
+}
+
+func (r *Retrying) retry(ctx context.Context, op string, fn func(context.Context) error) error {
+	attempts := max(r.policy.Attempts, 1)
+	backoff := r.policy.Backoff
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if r.policy.Timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, r.policy.Timeout)
+		}
+		err = fn(attemptCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		r.lgr.Warn("bootstrap: attempt failed",
+			logger.F("op", op), logger.F("attempt", attempt), logger.F("of", attempts), logger.F("err", err))
+		if attempt == attempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("bootstrap: %s: %w (last error: %v)", op, ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("bootstrap: %s failed after %d attempt(s): %w", op, attempts, err)
+}
@@ -0,0 +1,70 @@
+package bootstrap
+
+import (
+	"KoordeDHT/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyBootstrap fallisce le prime `failures` chiamate a Register/Deregister.
+type flakyBootstrap struct {
+	StaticBootstrap
+	failures int
+	calls    int
+}
+
+func (f *flakyBootstrap) Register(ctx context.Context, node *domain.Node) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("transient error")
+	}
+	return nil
+}
+
+func (f *flakyBootstrap) Deregister(ctx context.Context, node *domain.Node) error {
+	return f.Register(ctx, node)
+}
+
+func TestRetryingRegister(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		attempts  int
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "first attempt fails, retry succeeds", failures: 1, attempts: 3, wantErr: false, wantCalls: 2},
+		{name: "succeeds immediately", failures: 0, attempts: 3, wantErr: false, wantCalls: 1},
+		{name: "attempts exhausted", failures: 5, attempts: 3, wantErr: true, wantCalls: 3},
+		{name: "zero attempts means one", failures: 1, attempts: 0, wantErr: true, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fb := &flakyBootstrap{failures: tt.failures}
+			r := NewRetrying(fb, RetryPolicy{Attempts: tt.attempts, Timeout: time.Second, Backoff: time.Millisecond}, nil)
+			err := r.Register(context.Background(), &domain.Node{Addr: "127.0.0.1:4000"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Register error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if fb.calls != tt.wantCalls {
+				t.Errorf("Register called %d times, want %d", fb.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryingDeregisterStopsOnContextDone(t *testing.T) {
+	fb := &flakyBootstrap{failures: 10}
+	r := NewRetrying(fb, RetryPolicy{Attempts: 10, Backoff: time.Hour}, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := r.Deregister(ctx, &domain.Node{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Deregister error = %v, want DeadlineExceeded", err)
+	}
+	if fb.calls != 1 {
+		t.Errorf("Deregister called %d times, want 1", fb.calls)
+	}
+}
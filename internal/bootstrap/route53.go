@@ -8,6 +8,7 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -21,6 +22,23 @@ type Route53Bootstrap struct {
 	hostedZoneID string
 	domainSuffix string
 	ttl          int64
+	// staleAfter is the age after which Discover skips a record whose
+	// heartbeat was not refreshed (0 = no heartbeat, see heartbeatMisses)
+	staleAfter time.Duration
+	now        func() time.Time
+
+	mu         sync.Mutex
+	heartbeats map[string]string // last heartbeat TXT value written per record name, deleted by Deregister
+}
+
+// heartbeatMisses is the number of refresh intervals a record can go
+// without a heartbeat before Discover considers its node gone.
+const heartbeatMisses = 3
+
+func init() {
+	Register("route53", func(cfg Config) (Bootstrap, error) {
+		return NewRoute53Bootstrap(cfg.Route53)
+	})
 }
 
 func NewRoute53Bootstrap(cfg configloader.Route53Config) (*Route53Bootstrap, error) {
@@ -41,13 +59,22 @@ func NewRoute53Bootstrap(cfg configloader.Route53Config) (*Route53Bootstrap, err
 		hostedZoneID: cfg.HostedZoneID,
 		domainSuffix: strings.TrimSuffix(cfg.DomainSuffix, "."),
 		ttl:          cfg.TTL,
+		staleAfter:   heartbeatMisses * max(cfg.RefreshInterval, 0),
+		now:          time.Now,
+		heartbeats:   make(map[string]string),
 	}, nil
 }
 
-// Discover queries Route53 for SRV records in the specified hosted zone
+// Discover queries Route53 for SRV records in the specified hosted zone.
+// When the registrations are refreshed (route53.refreshInterval), the
+// records whose heartbeat is older than heartbeatMisses intervals are
+// skipped: their nodes stopped without deregistering. Records without a
+// heartbeat are always returned.
 func (r *Route53Bootstrap) Discover(ctx context.Context) ([]string, error) {
 	// create a list to hold the discovered endpoints
 	var endpoints []string
+	var srvs []types.ResourceRecordSet
+	beats := make(map[string]time.Time) // heartbeat per record name
 	// get the list of resource record sets in the hosted zone
 	input := &route53.ListResourceRecordSetsInput{
 		HostedZoneId: aws.String(r.hostedZoneID),
@@ -59,31 +86,43 @@ func (r *Route53Bootstrap) Discover(ctx context.Context) ([]string, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to list records: %w", err)
 		}
-		// Process each record set in the page
+		// Collect the SRV records and the heartbeats of the domain
 		for _, rrset := range page.ResourceRecordSets {
-			if rrset.Type != "SRV" {
-				continue
-			}
 			if !strings.HasSuffix(strings.TrimSuffix(*rrset.Name, "."), r.domainSuffix) {
 				continue
 			}
-
-			for _, rr := range rrset.ResourceRecords {
-				var prio, weight, port int
-				var target string
-				_, err := fmt.Sscanf(*rr.Value, "%d %d %d %s", &prio, &weight, &port, &target)
-				if err != nil {
-					continue
+			switch rrset.Type {
+			case types.RRTypeSrv:
+				srvs = append(srvs, rrset)
+			case types.RRTypeTxt:
+				for _, rr := range rrset.ResourceRecords {
+					if t, ok := parseHeartbeat(aws.ToString(rr.Value)); ok {
+						beats[*rrset.Name] = t
+					}
 				}
-				target = strings.TrimSuffix(target, ".")
+			}
+		}
+	}
 
-				ips, err := net.LookupHost(target)
-				if err != nil {
-					continue
-				}
-				for _, ip := range ips {
-					endpoints = append(endpoints, fmt.Sprintf("%s:%d", ip, port))
-				}
+	for _, rrset := range srvs {
+		if t, ok := beats[*rrset.Name]; ok && r.staleAfter > 0 && r.now().Sub(t) > r.staleAfter {
+			continue // not refreshed recently: the node is gone
+		}
+		for _, rr := range rrset.ResourceRecords {
+			var prio, weight, port int
+			var target string
+			_, err := fmt.Sscanf(*rr.Value, "%d %d %d %s", &prio, &weight, &port, &target)
+			if err != nil {
+				continue
+			}
+			target = strings.TrimSuffix(target, ".")
+
+			ips, err := net.LookupHost(target)
+			if err != nil {
+				continue
+			}
+			for _, ip := range ips {
+				endpoints = append(endpoints, net.JoinHostPort(ip, strconv.Itoa(port)))
 			}
 		}
 	}
@@ -92,6 +131,8 @@ func (r *Route53Bootstrap) Discover(ctx context.Context) ([]string, error) {
 }
 
 // Register creates or updates an SRV record in Route53 for the given node.
+// With refreshes enabled it also upserts a TXT record, with the same name,
+// holding the time of the registration (the heartbeat checked by Discover).
 func (r *Route53Bootstrap) Register(ctx context.Context, node *domain.Node) error {
 	// create the full record name
 	recordName := fmt.Sprintf("%s.%s.", node.ID.ToHexString(true), r.domainSuffix)
@@ -105,29 +146,41 @@ func (r *Route53Bootstrap) Register(ctx context.Context, node *domain.Node) erro
 		return err
 	}
 	// Insert the record into Route53
-	input := &route53.ChangeResourceRecordSetsInput{
-		HostedZoneId: aws.String(r.hostedZoneID),
-		ChangeBatch: &types.ChangeBatch{
-			Changes: []types.Change{
-				{
-					Action: types.ChangeActionUpsert,
-					ResourceRecordSet: &types.ResourceRecordSet{
-						Name: aws.String(recordName),
-						Type: types.RRTypeSrv,
-						TTL:  aws.Int64(r.ttl),
-						ResourceRecords: []types.ResourceRecord{
-							{
-								// Format: priority weight port target (priority and weight set to 0)
-								Value: aws.String(fmt.Sprintf("0 0 %d %s.", port, host)),
-							},
-						},
+	changes := []types.Change{
+		{
+			Action: types.ChangeActionUpsert,
+			ResourceRecordSet: &types.ResourceRecordSet{
+				Name: aws.String(recordName),
+				Type: types.RRTypeSrv,
+				TTL:  aws.Int64(r.ttl),
+				ResourceRecords: []types.ResourceRecord{
+					{
+						// Format: priority weight port target (priority and weight set to 0)
+						Value: aws.String(fmt.Sprintf("0 0 %d %s.", port, host)),
 					},
 				},
 			},
 		},
 	}
-	_, err = r.client.ChangeResourceRecordSets(ctx, input)
-	return err
+	// With refreshes enabled, record the time of this registration as well
+	var beat string
+	if r.staleAfter > 0 {
+		beat = heartbeatValue(r.now())
+		changes = append(changes, r.heartbeatChange(types.ChangeActionUpsert, recordName, beat))
+	}
+	input := &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(r.hostedZoneID),
+		ChangeBatch:  &types.ChangeBatch{Changes: changes},
+	}
+	if _, err = r.client.ChangeResourceRecordSets(ctx, input); err != nil {
+		return err
+	}
+	if beat != "" {
+		r.mu.Lock()
+		r.heartbeats[recordName] = beat
+		r.mu.Unlock()
+	}
+	return nil
 }
 
 // Deregister removes the SRV record for the given node from Route53.
@@ -144,26 +197,70 @@ func (r *Route53Bootstrap) Deregister(ctx context.Context, node *domain.Node) er
 		return err
 	}
 	// remove the record from Route53
-	input := &route53.ChangeResourceRecordSetsInput{
-		HostedZoneId: aws.String(r.hostedZoneID),
-		ChangeBatch: &types.ChangeBatch{
-			Changes: []types.Change{
-				{
-					Action: types.ChangeActionDelete,
-					ResourceRecordSet: &types.ResourceRecordSet{
-						Name: aws.String(recordName),
-						Type: types.RRTypeSrv,
-						TTL:  aws.Int64(r.ttl),
-						ResourceRecords: []types.ResourceRecord{
-							{
-								Value: aws.String(fmt.Sprintf("0 0 %d %s.", port, host)),
-							},
-						},
+	changes := []types.Change{
+		{
+			Action: types.ChangeActionDelete,
+			ResourceRecordSet: &types.ResourceRecordSet{
+				Name: aws.String(recordName),
+				Type: types.RRTypeSrv,
+				TTL:  aws.Int64(r.ttl),
+				ResourceRecords: []types.ResourceRecord{
+					{
+						Value: aws.String(fmt.Sprintf("0 0 %d %s.", port, host)),
 					},
 				},
 			},
 		},
 	}
-	_, err = r.client.ChangeResourceRecordSets(ctx, input)
-	return err
+	// a deletion must match the current value: remove the last heartbeat written
+	r.mu.Lock()
+	beat, ok := r.heartbeats[recordName]
+	r.mu.Unlock()
+	if ok {
+		changes = append(changes, r.heartbeatChange(types.ChangeActionDelete, recordName, beat))
+	}
+	input := &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(r.hostedZoneID),
+		ChangeBatch:  &types.ChangeBatch{Changes: changes},
+	}
+	if _, err = r.client.ChangeResourceRecordSets(ctx, input); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	delete(r.heartbeats, recordName)
+	r.mu.Unlock()
+	return nil
+}
+
+// heartbeatChange returns the change applying action to the heartbeat TXT
+// record of recordName, with the given value.
+func (r *Route53Bootstrap) heartbeatChange(action types.ChangeAction, recordName, value string) types.Change {
+	return types.Change{
+		Action: action,
+		ResourceRecordSet: &types.ResourceRecordSet{
+			Name:            aws.String(recordName),
+			Type:            types.RRTypeTxt,
+			TTL:             aws.Int64(r.ttl),
+			ResourceRecords: []types.ResourceRecord{{Value: aws.String(value)}},
+		},
+	}
+}
+
+// heartbeatValue returns the TXT value recording that a registration was
+// refreshed at t.
+func heartbeatValue(t time.Time) string {
+	return fmt.Sprintf("\"updated=%d\"", t.Unix())
+}
+
+// parseHeartbeat decodes a TXT value written by heartbeatValue.
+func parseHeartbeat(v string) (time.Time, bool) {
+	s, ok := strings.CutPrefix(strings.Trim(v, "\""), "updated=")
+	if !ok {
+		return time.Time{}, false
+	}
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0), true
 }
@@ -10,6 +10,12 @@ type StaticBootstrap struct {
 	peers []string
 }
 
+func init() {
+	Register("static", func(cfg Config) (Bootstrap, error) {
+		return NewStaticBootstrap(cfg.Peers), nil
+	})
+}
+
 func NewStaticBootstrap(peers []string) *StaticBootstrap {
 	return &StaticBootstrap{peers: peers}
 }
@@ -0,0 +1,146 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchPutAndGet(t *testing.T) {
+	r := testring.New(t, 5)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	api, conn, err := client.Connect(r.Members[1].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+
+	// Chiavi distribuite su tutto l'anello, più una coppia non valida
+	const numKeys = 40
+	items := make([]client.PutItem, numKeys)
+	for i := range items {
+		items[i] = client.PutItem{Key: fmt.Sprintf("key-%d", i), Value: fmt.Sprintf("value-%d", i)}
+	}
+	stored, failed, _, err := client.BatchPut(ctx, api, append(items, client.PutItem{Key: "empty"}))
+	if err != nil {
+		t.Fatalf("BatchPut: %v", err)
+	}
+	if stored != numKeys || len(failed) != 1 || failed["empty"] == nil {
+		t.Fatalf("BatchPut: stored %d, failed %v; want %d stored and only %q failed", stored, failed, numKeys, "empty")
+	}
+
+	// Ogni risorsa deve trovarsi sul proprio successore
+	for _, it := range items {
+		owner := r.Owner(r.Space.NewIdFromString(it.Key))
+		var ok bool
+		for _, res := range owner.Node.GetAllResourceStored() {
+			ok = ok || (res.RawKey == it.Key && res.Value == it.Value)
+		}
+		if !ok {
+			t.Errorf("key %s not stored on its owner %s", it.Key, owner.Addr)
+		}
+	}
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr error
+	}{
+		{key: "key-0", want: "value-0"},
+		{key: "key-17", want: "value-17"},
+		{key: "key-39", want: "value-39"},
+		{key: "missing-1", wantErr: client.ErrNotFound},
+		{key: "missing-2", wantErr: client.ErrNotFound},
+	}
+	keys := make([]string, 0, len(tests)+1)
+	for _, tt := range tests {
+		keys = append(keys, tt.key)
+	}
+	keys = append(keys, "key-0") // i duplicati sono riportati una volta sola
+
+	results, _, err := client.BatchGet(ctx, api, keys)
+	if err != nil {
+		t.Fatalf("BatchGet: %v", err)
+	}
+	if len(results) != len(tests) {
+		t.Fatalf("BatchGet: got %d results, want %d", len(results), len(tests))
+	}
+	for _, tt := range tests {
+		got, ok := results[tt.key]
+		if !ok {
+			t.Errorf("BatchGet: no result for %s", tt.key)
+			continue
+		}
+		if got.Value != tt.want || !errors.Is(got.Err, tt.wantErr) {
+			t.Errorf("BatchGet %s = {%q, %v}, want {%q, %v}", tt.key, got.Value, got.Err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestBatchPutQuotaPartial(t *testing.T) {
+	// Ogni risorsa occupa 2 (ID a 16 bit) + 6 (chiave) + 20 (valore) = 28
+	// byte: la quota ne ammette 3 per nodo
+	const quota, perOwner = 100, 5
+	value := strings.Repeat("v", 20)
+	r := testring.New(t, 2, testring.WithStorageOptions(storage.WithQuota(quota)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	api, conn, err := client.Connect(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+
+	// perOwner chiavi per ciascun nodo: quelle del nodo contattato sono
+	// scritte localmente, quelle dell'altro su un unico stream
+	byOwner := make(map[string][]string)
+	var items []client.PutItem
+	seen := make(map[string]struct{})
+	for i := 0; i < 200 && len(items) < 2*perOwner; i++ {
+		key := fmt.Sprintf("key-%02d", i)
+		id := r.Space.NewIdFromString(key)
+		owner := r.Owner(id).Addr
+		if _, dup := seen[id.ToHexString(false)]; dup || len(byOwner[owner]) == perOwner {
+			continue
+		}
+		seen[id.ToHexString(false)] = struct{}{}
+		byOwner[owner] = append(byOwner[owner], key)
+		items = append(items, client.PutItem{Key: key, Value: value})
+	}
+	if len(items) != 2*perOwner {
+		t.Fatalf("found %d keys, want %d per owner", len(items), perOwner)
+	}
+
+	stored, failed, _, err := client.BatchPut(ctx, api, items)
+	if err != nil {
+		t.Fatalf("BatchPut: %v", err)
+	}
+	if stored != 2*3 || len(failed) != 2*(perOwner-3) {
+		t.Fatalf("BatchPut: stored %d, failed %d; want 3 stored per owner", stored, len(failed))
+	}
+	// Nell'ordine del batch: le prime 3 chiavi di ogni nodo sono scritte,
+	// solo le successive sono rifiutate dalla quota
+	for _, m := range r.Members {
+		for j, key := range byOwner[m.Addr] {
+			_, err := m.Node.RetrieveLocal(r.Space.NewIdFromString(key))
+			if j < 3 {
+				if failed[key] != nil || err != nil {
+					t.Errorf("key %s on %s: reported %v, stored err %v; want stored", key, m.Addr, failed[key], err)
+				}
+				continue
+			}
+			if failed[key] == nil || err == nil {
+				t.Errorf("key %s on %s: reported %v, stored err %v; want rejected", key, m.Addr, failed[key], err)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBatchDeleteMixedKeys(t *testing.T) {
+	r := testring.New(t, 5)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// Chiavi pari presenti, dispari assenti, distribuite su tutto l'anello
+	const numKeys = 40
+	keys := make([]string, numKeys)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		if i%2 == 0 {
+			res := domain.Resource{Key: r.Space.NewIdFromString(keys[i]), RawKey: keys[i], Value: keys[i]}
+			if err := r.Members[0].Node.Put(ctx, res); err != nil {
+				t.Fatalf("Put %s: %v", keys[i], err)
+			}
+		}
+	}
+
+	api, conn, err := client.Connect(r.Members[1].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+
+	results, _, err := client.BatchDelete(ctx, api, keys)
+	if err != nil {
+		t.Fatalf("BatchDelete: %v", err)
+	}
+	if len(results) != numKeys {
+		t.Fatalf("got %d results, want %d", len(results), numKeys)
+	}
+	for i, res := range results {
+		var want error
+		if i%2 == 1 {
+			want = client.ErrNotFound
+		}
+		if res.Key != keys[i] || !errors.Is(res.Err, want) {
+			t.Errorf("result %d = {%s, %v}, want {%s, %v}", i, res.Key, res.Err, keys[i], want)
+		}
+	}
+
+	// Nessuna chiave deve essere rimasta su alcun nodo
+	for _, m := range r.Members {
+		if n := len(m.Node.GetAllResourceStored()); n != 0 {
+			t.Errorf("member %s still stores %d resources", m.Addr, n)
+		}
+	}
+
+	// Una seconda cancellazione trova solo chiavi assenti
+	results, _, err = client.BatchDelete(ctx, api, keys[:4])
+	if err != nil {
+		t.Fatalf("second BatchDelete: %v", err)
+	}
+	for _, res := range results {
+		if !errors.Is(res.Err, client.ErrNotFound) {
+			t.Errorf("second delete of %s: got %v, want %v", res.Key, res.Err, client.ErrNotFound)
+		}
+	}
+}
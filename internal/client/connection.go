@@ -2,19 +2,123 @@ package client
 
 import (
 	clientv1 "KoordeDHT/internal/api/client/v1"
+	"KoordeDHT/internal/domain"
+	"context"
+	"crypto/tls"
 	"fmt"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-func Connect(addr string) (clientv1.ClientAPIClient, *grpc.ClientConn, error) {
-	conn, err := grpc.NewClient(
-		addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+// ConnectOption configures Connect.
+type ConnectOption func(*connectConfig)
+
+type connectConfig struct {
+	tlsConfig      *tls.Config
+	maxRecvMsgSize int
+	expectedSpace  *domain.Space
+}
+
+// spaceTimeout bounds the GetSpace issued by Connect with WithExpectedSpace.
+const spaceTimeout = 5 * time.Second
+
+// WithTLS makes Connect use TLS with the given configuration (see
+// security.ClientTLS). A nil configuration keeps the plaintext transport.
+func WithTLS(cfg *tls.Config) ConnectOption {
+	return func(c *connectConfig) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithMaxRecvMsgSize raises the maximum size of the responses Connect's
+// client accepts, so that Get can return values larger than the gRPC
+// default (4 MiB). n <= 0 keeps the default.
+func WithMaxRecvMsgSize(n int) ConnectOption {
+	return func(c *connectConfig) {
+		c.maxRecvMsgSize = n
+	}
+}
+
+// WithExpectedSpace makes Connect fetch the identifier space of the ring
+// (see GetSpace) and fail with an error wrapping ErrSpaceMismatch if it
+// does not match sp (see CheckSpace for the fields compared), instead of
+// letting the client derive identifiers the ring does not use.
+func WithExpectedSpace(sp domain.Space) ConnectOption {
+	return func(c *connectConfig) {
+		c.expectedSpace = &sp
+	}
+}
+
+// Connect creates a client of the node at addr. The connection is lazy,
+// unless WithExpectedSpace is given: the space of the ring is then checked
+// before returning.
+func Connect(addr string, opts ...ConnectOption) (clientv1.ClientAPIClient, *grpc.ClientConn, error) {
+	var cc connectConfig
+	for _, o := range opts {
+		o(&cc)
+	}
+	creds := insecure.NewCredentials()
+	if cc.tlsConfig != nil {
+		creds = credentials.NewTLS(cc.tlsConfig)
+	}
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if cc.maxRecvMsgSize > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(cc.maxRecvMsgSize)))
+	}
+	conn, err := grpc.NewClient(addr, dialOpts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
 	}
-	return clientv1.NewClientAPIClient(conn), conn, nil
+	api := clientv1.NewClientAPIClient(conn)
+	if cc.expectedSpace != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), spaceTimeout)
+		defer cancel()
+		if _, err := checkedSpace(ctx, api, *cc.expectedSpace); err != nil {
+			_ = conn.Close()
+			return nil, nil, fmt.Errorf("%s: %w", addr, err)
+		}
+	}
+	return api, conn, nil
+}
+
+// ConnectSpace is like Connect, but also returns the identifier space of
+// the ring, fetched within ctx, so that tools can derive identifiers of the
+// right size without configuring it. With WithExpectedSpace the space is
+// checked as well.
+func ConnectSpace(ctx context.Context, addr string, opts ...ConnectOption) (clientv1.ClientAPIClient, *grpc.ClientConn, domain.Space, error) {
+	var cc connectConfig
+	for _, o := range opts {
+		o(&cc)
+	}
+	want := domain.Space{}
+	if cc.expectedSpace != nil {
+		want = *cc.expectedSpace
+	}
+	// the check is done here, within ctx
+	api, conn, err := Connect(addr, append(opts, func(c *connectConfig) { c.expectedSpace = nil })...)
+	if err != nil {
+		return nil, nil, domain.Space{}, err
+	}
+	sp, err := checkedSpace(ctx, api, want)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, domain.Space{}, fmt.Errorf("%s: %w", addr, err)
+	}
+	return api, conn, sp, nil
+}
+
+// checkedSpace fetches the identifier space of the ring and compares it
+// with want.
+func checkedSpace(ctx context.Context, api clientv1.ClientAPIClient, want domain.Space) (domain.Space, error) {
+	sp, _, err := GetSpace(ctx, api)
+	if err != nil {
+		return domain.Space{}, fmt.Errorf("failed to get the identifier space: %w", err)
+	}
+	if err := CheckSpace(sp, want); err != nil {
+		return domain.Space{}, err
+	}
+	return sp, nil
 }
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"KoordeDHT/internal/domain"
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lookupCacheBytes is the number of high-order bytes of a target ID that
+// select its cache entry: targets sharing them share one entry.
+const lookupCacheBytes = 2
+
+// --------------------------------------
+// LookupCache
+// --------------------------------------
+
+// LookupCache remembers the owner found by recent lookups, so that repeated
+// operations on the same region of the ring can skip the full lookup. It is
+// an LRU of bounded size whose entries expire after a fixed TTL.
+//
+// An entry records the owner together with the smallest target it was
+// resolved for: no node lies between that target and the owner, so the
+// entry answers only for targets in [from, owner]. A nil *LookupCache is
+// valid and caches nothing.
+type LookupCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	lru     *list.List // front = most recently used
+}
+
+type lookupEntry struct {
+	bucket  string
+	from    domain.ID // smallest target resolved to owner in this bucket
+	owner   *domain.Node
+	expires time.Time
+}
+
+// NewLookupCache returns a cache of at most size entries, each valid for
+// ttl. It returns nil (no caching) if size or ttl is not positive.
+func NewLookupCache(size int, ttl time.Duration) *LookupCache {
+	if size <= 0 || ttl <= 0 {
+		return nil
+	}
+	return &LookupCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func bucketOf(target domain.ID) string {
+	return string(target[:min(lookupCacheBytes, len(target))])
+}
+
+// Get returns the cached owner of target, or nil if the cache has no live
+// entry for it or the owner of the entry is not known to cover target.
+func (c *LookupCache) Get(target domain.ID) *domain.Node {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[bucketOf(target)]
+	if !ok {
+		return nil
+	}
+	e := el.Value.(*lookupEntry)
+	if time.Now().After(e.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, e.bucket)
+		return nil
+	}
+	if !target.Equal(e.from) && !target.Between(e.from, e.owner.ID) {
+		return nil
+	}
+	c.lru.MoveToFront(el)
+	return e.owner
+}
+
+// Add records that the lookup of target returned owner. An entry of the
+// same bucket for the same owner is widened to cover target as well, one
+// for a different owner is replaced. The least recently used entry is
+// evicted when the cache is full.
+func (c *LookupCache) Add(target domain.ID, owner *domain.Node) {
+	if c == nil || owner == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bucket := bucketOf(target)
+	expires := time.Now().Add(c.ttl)
+	if el, ok := c.entries[bucket]; ok {
+		e := el.Value.(*lookupEntry)
+		if e.owner.ID.Equal(owner.ID) && !e.from.Between(target, owner.ID) {
+			target = e.from // target already covered
+		}
+		e.from, e.owner, e.expires = target, owner, expires
+		c.lru.MoveToFront(el)
+		return
+	}
+	if c.lru.Len() >= c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lookupEntry).bucket)
+	}
+	c.entries[bucket] = c.lru.PushFront(&lookupEntry{bucket: bucket, from: target, owner: owner, expires: expires})
+}
+
+// Invalidate drops every entry whose owner is the node at addr, e.g. after
+// an RPC to it failed.
+func (c *LookupCache) Invalidate(addr string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.lru.Front(); el != nil; {
+		next := el.Next()
+		if e := el.Value.(*lookupEntry); e.owner.Addr == addr {
+			c.lru.Remove(el)
+			delete(c.entries, e.bucket)
+		}
+		el = next
+	}
+}
+
+// Len returns the number of entries in the cache, including expired ones
+// not yet evicted.
+func (c *LookupCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
@@ -0,0 +1,103 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestLookupCache(t *testing.T) {
+	sp, err := domain.NewSpace(32, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &domain.Node{ID: sp.FromUint64(0x12340100), Addr: "a:1"}
+	b := &domain.Node{ID: sp.FromUint64(0x12340200), Addr: "b:1"}
+
+	c := client.NewLookupCache(4, time.Minute)
+	c.Add(sp.FromUint64(0x12340010), a)
+
+	tests := []struct {
+		name   string
+		target uint64
+		want   *domain.Node
+	}{
+		{name: "resolved target", target: 0x12340010, want: a},
+		{name: "between target and owner", target: 0x12340080, want: a},
+		{name: "owner itself", target: 0x12340100, want: a},
+		{name: "before resolved target", target: 0x12340008, want: nil},
+		{name: "after owner", target: 0x12340180, want: nil},
+		{name: "other bucket", target: 0x56780010, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Get(sp.FromUint64(tt.target)); got != tt.want {
+				t.Errorf("Get(%#x) = %v, want %v", tt.target, got, tt.want)
+			}
+		})
+	}
+
+	// un nuovo lookup con lo stesso proprietario allarga l'intervallo coperto
+	c.Add(sp.FromUint64(0x12340004), a)
+	if got := c.Get(sp.FromUint64(0x12340008)); got != a {
+		t.Errorf("Get after widening = %v, want %v", got, a)
+	}
+
+	// un proprietario diverso nello stesso bucket sostituisce la voce
+	c.Add(sp.FromUint64(0x12340180), b)
+	if got := c.Get(sp.FromUint64(0x12340080)); got != nil {
+		t.Errorf("Get of replaced entry = %v, want nil", got)
+	}
+	if got := c.Get(sp.FromUint64(0x12340190)); got != b {
+		t.Errorf("Get = %v, want %v", got, b)
+	}
+
+	// un RPC fallito verso b elimina le sue voci
+	c.Invalidate(b.Addr)
+	if got := c.Get(sp.FromUint64(0x12340190)); got != nil {
+		t.Errorf("Get after Invalidate = %v, want nil", got)
+	}
+}
+
+func TestLookupCacheEviction(t *testing.T) {
+	sp, err := domain.NewSpace(32, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := func(x uint64) *domain.Node { return &domain.Node{ID: sp.FromUint64(x), Addr: "n:1"} }
+
+	// capacità 2: la voce usata meno di recente viene scartata
+	c := client.NewLookupCache(2, time.Minute)
+	c.Add(sp.FromUint64(0x10000000), owner(0x10000001))
+	c.Add(sp.FromUint64(0x20000000), owner(0x20000001))
+	c.Get(sp.FromUint64(0x10000000))
+	c.Add(sp.FromUint64(0x30000000), owner(0x30000001))
+	if c.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", c.Len())
+	}
+	if c.Get(sp.FromUint64(0x20000000)) != nil {
+		t.Error("least recently used entry not evicted")
+	}
+	if c.Get(sp.FromUint64(0x10000000)) == nil || c.Get(sp.FromUint64(0x30000000)) == nil {
+		t.Error("recent entries evicted")
+	}
+
+	// le voci scadono dopo il TTL
+	c = client.NewLookupCache(2, 20*time.Millisecond)
+	c.Add(sp.FromUint64(0x10000000), owner(0x10000001))
+	time.Sleep(40 * time.Millisecond)
+	if c.Get(sp.FromUint64(0x10000000)) != nil {
+		t.Error("expired entry returned")
+	}
+
+	// dimensione o TTL nulli disabilitano la cache
+	if c := client.NewLookupCache(0, time.Minute); c != nil {
+		t.Fatal("NewLookupCache(0, ttl) != nil")
+	}
+	var disabled *client.LookupCache
+	disabled.Add(sp.FromUint64(0x10000000), owner(0x10000001))
+	if disabled.Get(sp.FromUint64(0x10000000)) != nil || disabled.Len() != 0 {
+		t.Error("nil cache returned an entry")
+	}
+}
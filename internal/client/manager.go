@@ -0,0 +1,173 @@
+package client
+
+import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// ErrManagerClosed is returned by the Manager methods called after Close.
+var ErrManagerClosed = errors.New("connection manager closed")
+
+// Manager shares the connections of a client to the nodes of a ring: Get
+// dials a node only the first time and returns the same connection
+// afterwards, so that tools issuing many requests (the CLI, the tester) do
+// not open one connection per request. A background goroutine closes the
+// connections that have not been requested for the idle TTL.
+//
+// The connections belong to the Manager: callers must not close them, and
+// should not keep using one for longer than the idle TTL without calling
+// Get again. A Manager must be closed with Close.
+type Manager struct {
+	opts    []ConnectOption
+	idleTTL time.Duration
+
+	mu     sync.Mutex
+	conns  map[string]*managedConn
+	closed bool
+	stopCh chan struct{} // closed by Close, stops the eviction loop
+	done   chan struct{} // closed when the eviction loop has returned
+}
+
+type managedConn struct {
+	api      clientv1.ClientAPIClient
+	conn     *grpc.ClientConn
+	lastUsed time.Time // last Get of the connection
+}
+
+// NewManager returns a Manager that dials the nodes with opts (see
+// Connect) and closes the connections not requested for idleTTL. A
+// non-positive idleTTL keeps the connections until Close or Forget.
+func NewManager(idleTTL time.Duration, opts ...ConnectOption) *Manager {
+	m := &Manager{
+		opts:    opts,
+		idleTTL: idleTTL,
+		conns:   make(map[string]*managedConn),
+		stopCh:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if idleTTL > 0 {
+		go m.evictLoop()
+	} else {
+		close(m.done)
+	}
+	return m
+}
+
+// Get returns the connection to addr, dialing it if the Manager has none.
+func (m *Manager) Get(addr string) (*grpc.ClientConn, error) {
+	c, err := m.get(addr)
+	if err != nil {
+		return nil, err
+	}
+	return c.conn, nil
+}
+
+// API is like Get, but returns the client of the ClientAPI service of the
+// node at addr.
+func (m *Manager) API(addr string) (clientv1.ClientAPIClient, error) {
+	c, err := m.get(addr)
+	if err != nil {
+		return nil, err
+	}
+	return c.api, nil
+}
+
+func (m *Manager) get(addr string) (*managedConn, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrManagerClosed
+	}
+	c, ok := m.conns[addr]
+	if !ok {
+		// Connect does not block (unless WithExpectedSpace is given)
+		api, conn, err := Connect(addr, m.opts...)
+		if err != nil {
+			return nil, err
+		}
+		c = &managedConn{api: api, conn: conn}
+		m.conns[addr] = c
+	}
+	c.lastUsed = time.Now()
+	return c, nil
+}
+
+// Forget closes the connection to addr, if any, e.g. because the node
+// left the ring: the next Get dials it again.
+func (m *Manager) Forget(addr string) {
+	m.mu.Lock()
+	c, ok := m.conns[addr]
+	delete(m.conns, addr)
+	m.mu.Unlock()
+	if ok {
+		_ = c.conn.Close()
+	}
+}
+
+// Len returns the number of open connections.
+func (m *Manager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.conns)
+}
+
+// evictLoop closes the idle connections every half idle TTL until Close.
+func (m *Manager) evictLoop() {
+	defer close(m.done)
+	ticker := time.NewTicker(m.idleTTL / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case now := <-ticker.C:
+			m.evictIdle(now)
+		}
+	}
+}
+
+// evictIdle closes the connections not requested since now minus the idle
+// TTL and returns how many were closed.
+func (m *Manager) evictIdle(now time.Time) int {
+	var idle []*grpc.ClientConn
+	m.mu.Lock()
+	for addr, c := range m.conns {
+		if now.Sub(c.lastUsed) >= m.idleTTL {
+			idle = append(idle, c.conn)
+			delete(m.conns, addr)
+		}
+	}
+	m.mu.Unlock()
+	for _, conn := range idle {
+		_ = conn.Close()
+	}
+	return len(idle)
+}
+
+// Close stops the eviction and closes every connection. Get fails with
+// ErrManagerClosed afterwards; further calls to Close are no-ops.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	conns := m.conns
+	m.conns = nil
+	m.mu.Unlock()
+
+	close(m.stopCh)
+	<-m.done
+	var errs []error
+	for _, c := range conns {
+		if err := c.conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
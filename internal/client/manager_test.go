@@ -0,0 +1,110 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestManagerReusesConnections(t *testing.T) {
+	r := testring.New(t, 2)
+	m := client.NewManager(0)
+	defer m.Close()
+
+	first, err := m.Get(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	again, err := m.Get(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if first != again {
+		t.Errorf("Get of the same address returned two connections")
+	}
+	other, err := m.Get(r.Members[1].Addr)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if other == first {
+		t.Errorf("Get of two addresses returned the same connection")
+	}
+	if got := m.Len(); got != 2 {
+		t.Errorf("Len: got %d, want 2", got)
+	}
+
+	// la connessione condivisa serve le richieste
+	api, err := m.API(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("API: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Put(ctx, api, "key", "value"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	m.Forget(r.Members[1].Addr)
+	if got := m.Len(); got != 1 {
+		t.Errorf("Len after Forget: got %d, want 1", got)
+	}
+	if state := other.GetState(); state != connectivity.Shutdown {
+		t.Errorf("forgotten connection: got state %s, want %s", state, connectivity.Shutdown)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if state := first.GetState(); state != connectivity.Shutdown {
+		t.Errorf("connection after Close: got state %s, want %s", state, connectivity.Shutdown)
+	}
+	if _, err := m.Get(r.Members[0].Addr); !errors.Is(err, client.ErrManagerClosed) {
+		t.Errorf("Get after Close: got %v, want ErrManagerClosed", err)
+	}
+}
+
+func TestManagerEvictsIdleConnections(t *testing.T) {
+	r := testring.New(t, 1)
+	const ttl = 100 * time.Millisecond
+	m := client.NewManager(ttl)
+	defer m.Close()
+
+	conn, err := m.Get(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	// una connessione usata di continuo non viene chiusa
+	for i := 0; i < 5; i++ {
+		time.Sleep(ttl / 2)
+		if _, err := m.Get(r.Members[0].Addr); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+	if state := conn.GetState(); state == connectivity.Shutdown {
+		t.Fatalf("connection in use was evicted")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for m.Len() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("idle connection not evicted after %s", 5*time.Second)
+		}
+		time.Sleep(ttl / 4)
+	}
+	if state := conn.GetState(); state != connectivity.Shutdown {
+		t.Errorf("evicted connection: got state %s, want %s", state, connectivity.Shutdown)
+	}
+	// la richiesta successiva apre una nuova connessione
+	fresh, err := m.Get(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Get after eviction: %v", err)
+	}
+	if fresh == conn {
+		t.Errorf("Get after eviction returned the closed connection")
+	}
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// OwnershipInterval is the slice of the identifier space owned by a node:
+// the circular interval (Start, End].
+type OwnershipInterval struct {
+	Start string   `json:"start"` // exclusive bound: ID of the owner's predecessor
+	End   string   `json:"end"`   // inclusive bound: ID of the owner
+	Owner string   `json:"owner"` // address of the owner
+	Size  *big.Int `json:"size"`  // number of identifiers in (Start, End]
+	Share float64  `json:"share"` // Size as a fraction of the identifier space
+	Keys  int      `json:"keys"`  // keys stored by the owner (-1 if it could not be queried)
+}
+
+// Ownership is the keyspace ownership map of the whole ring.
+type Ownership struct {
+	Bits      int                 `json:"bits"`      // size of the identifier space (2^Bits IDs)
+	Intervals []OwnershipInterval `json:"intervals"` // sorted by End
+	TotalKeys int                 `json:"totalKeys"` // sum of the Keys of the reachable owners
+	MinShare  float64             `json:"minShare"`  // smallest interval share
+	MaxShare  float64             `json:"maxShare"`  // largest interval share
+	// Imbalance is MaxShare divided by the ideal share 1/len(Intervals):
+	// 1 means a perfectly balanced ring.
+	Imbalance float64 `json:"imbalance"`
+	// Truncated reports that the ring walk hit the step limit: the map
+	// covers only the members visited so far.
+	Truncated bool `json:"truncated"`
+}
+
+// OwnershipMap walks the ring starting from seed and returns, for every
+// member, the interval (pred, self] of identifiers it owns together with
+// the number of keys it stores.
+//
+// The interval start is the predecessor reported by the member itself, so
+// the map shows the ownership as the nodes currently see it; if a member
+// has no predecessor, the previous member of the successor walk is used.
+//
+// bits is the size of the identifier space. If bits <= 0 it is derived
+// from the width of the encoded IDs (4 bits per hex digit), which is exact
+// only for byte-aligned spaces.
+//
+// An error is returned if the seed cannot be queried or the successor walk
+// does not return to the seed. If the walk is stopped by the step limit
+// (see WithMaxRingWalk), the partial map is returned with Truncated set.
+func OwnershipMap(ctx context.Context, seed string, bits int, opts ...WalkOption) (*Ownership, error) {
+	return ownershipMap(ctx, seed, bits, true, opts)
+}
+
+// ownershipMap builds the map of OwnershipMap; if withKeys is false the
+// members are not asked for their key counts and Keys is left at -1.
+func ownershipMap(ctx context.Context, seed string, bits int, withKeys bool, opts []WalkOption) (*Ownership, error) {
+	report, _, err := walkRing(ctx, seed, newWalkOptions(opts).maxSteps)
+	if err != nil {
+		return nil, err
+	}
+	if !report.Complete && !report.Truncated {
+		return nil, fmt.Errorf("ownership: ring walk from %s did not return to the seed", seed)
+	}
+
+	if bits <= 0 {
+		bits = 4 * len(strings.TrimPrefix(strings.ToLower(report.Nodes[0].Self.Id), "0x"))
+	}
+	space := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+
+	nodes := report.Nodes
+	om := &Ownership{Bits: bits, Truncated: report.Truncated}
+	for i, rt := range nodes {
+		start := nodes[(i-1+len(nodes))%len(nodes)].Self.Id
+		if rt.Predecessor != nil {
+			start = rt.Predecessor.Id
+		}
+		size, err := intervalSize(start, rt.Self.Id, space)
+		if err != nil {
+			return nil, fmt.Errorf("ownership: node %s: %w", nodeString(rt.Self), err)
+		}
+		share, _ := new(big.Rat).SetFrac(size, space).Float64()
+		iv := OwnershipInterval{
+			Start: start,
+			End:   rt.Self.Id,
+			Owner: rt.Self.Addr,
+			Size:  size,
+			Share: share,
+			Keys:  -1,
+		}
+		if withKeys {
+			iv.Keys = countKeys(ctx, rt.Self.Addr)
+		}
+		if iv.Keys > 0 {
+			om.TotalKeys += iv.Keys
+		}
+		om.Intervals = append(om.Intervals, iv)
+	}
+
+	sort.Slice(om.Intervals, func(i, j int) bool {
+		a, _ := parseHexID(om.Intervals[i].End)
+		b, _ := parseHexID(om.Intervals[j].End)
+		return a.Cmp(b) < 0
+	})
+
+	om.MinShare, om.MaxShare = om.Intervals[0].Share, om.Intervals[0].Share
+	for _, iv := range om.Intervals[1:] {
+		om.MinShare = min(om.MinShare, iv.Share)
+		om.MaxShare = max(om.MaxShare, iv.Share)
+	}
+	om.Imbalance = om.MaxShare * float64(len(om.Intervals))
+	return om, nil
+}
+
+// intervalSize returns the number of identifiers in the circular interval
+// (start, end] of a space of the given size. start == end denotes the
+// whole ring (single-node case).
+func intervalSize(start, end string, space *big.Int) (*big.Int, error) {
+	a, err := parseHexID(start)
+	if err != nil {
+		return nil, err
+	}
+	b, err := parseHexID(end)
+	if err != nil {
+		return nil, err
+	}
+	size := new(big.Int).Sub(b, a)
+	if size.Sign() <= 0 {
+		size.Add(size, space)
+	}
+	return size, nil
+}
+
+// countKeys returns the number of keys stored by the node at addr, or -1
+// if it cannot be queried.
+func countKeys(ctx context.Context, addr string) int {
+	api, conn, err := Connect(addr)
+	if err != nil {
+		return -1
+	}
+	defer conn.Close()
+	items, _, err := GetStore(ctx, api)
+	if err != nil {
+		return -1
+	}
+	return len(items)
+}
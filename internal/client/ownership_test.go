@@ -0,0 +1,67 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestOwnershipMapTilesRing(t *testing.T) {
+	const bits = 16
+	r := testring.New(t, 5, testring.WithSpace(bits, 2, 4))
+
+	const numKeys = 50
+	for i := 0; i < numKeys; i++ {
+		raw := fmt.Sprintf("key-%d", i)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := r.Members[0].Node.Put(ctx, domain.Resource{Key: r.Space.NewIdFromString(raw), RawKey: raw, Value: raw})
+		cancel()
+		if err != nil {
+			t.Fatalf("Put %s: %v", raw, err)
+		}
+	}
+	r.StopStabilizers()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	om, err := client.OwnershipMap(ctx, r.Members[2].Addr, bits)
+	if err != nil {
+		t.Fatalf("OwnershipMap: %v", err)
+	}
+	if len(om.Intervals) != len(r.Members) {
+		t.Fatalf("got %d intervals, want %d", len(om.Intervals), len(r.Members))
+	}
+
+	// Gli intervalli (Start, End] devono coprire l'anello senza buchi né sovrapposizioni
+	total := new(big.Int)
+	var share float64
+	for i, iv := range om.Intervals {
+		prev := om.Intervals[(i-1+len(om.Intervals))%len(om.Intervals)]
+		if iv.Start != prev.End {
+			t.Errorf("interval %d starts at %s, previous ends at %s", i, iv.Start, prev.End)
+		}
+		if want := r.Members[i]; iv.End != want.Node.Self().ID.ToHexString(true) || iv.Owner != want.Addr {
+			t.Errorf("interval %d owned by %s(%s), want %s(%s)", i, iv.End, iv.Owner,
+				want.Node.Self().ID.ToHexString(true), want.Addr)
+		}
+		total.Add(total, iv.Size)
+		share += iv.Share
+	}
+	if want := new(big.Int).Lsh(big.NewInt(1), bits); total.Cmp(want) != 0 {
+		t.Errorf("interval sizes sum to %s, want %s", total, want)
+	}
+	if share < 0.999 || share > 1.001 {
+		t.Errorf("shares sum to %f, want 1", share)
+	}
+	if om.TotalKeys != numKeys {
+		t.Errorf("TotalKeys = %d, want %d", om.TotalKeys, numKeys)
+	}
+	if om.Imbalance < 1 {
+		t.Errorf("Imbalance = %f, must be >= 1", om.Imbalance)
+	}
+}
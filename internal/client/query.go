@@ -2,8 +2,12 @@ package client
 
 import (
 	clientv1 "KoordeDHT/internal/api/client/v1"
+	"KoordeDHT/internal/domain"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc/codes"
@@ -16,9 +20,48 @@ var (
 	ErrUnavailable      = errors.New("node unavailable")
 	ErrDeadlineExceeded = errors.New("request timeout exceeded")
 	ErrInternal         = errors.New("internal gRPC error")
+	ErrCorrupted        = errors.New("resource corrupted")
+	ErrStorageFull      = errors.New("node storage full")
+	ErrInvalidArgument  = errors.New("invalid request")
+
+	// ErrLookupFailed is wrapped together with one of the errors above when
+	// the node could not locate the one responsible for the key (a routing
+	// failure), as opposed to a failure of the responsible node's storage.
+	ErrLookupFailed = errors.New("lookup failed")
+
+	// ErrSpaceMismatch is returned when the identifier space of a ring does
+	// not match the one the client expects (see CheckSpace).
+	ErrSpaceMismatch = errors.New("identifier space mismatch")
+
+	// ErrPermissionDenied is returned by the admin RPCs (see Shutdown) of a
+	// node that does not enable them.
+	ErrPermissionDenied = errors.New("permission denied")
+
+	// ErrPreconditionFailed is returned by PutIfAbsent and CompareAndSwap
+	// when their condition does not hold and nothing was stored.
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrOverloaded is returned when the node rejected the request because
+	// it was already serving as many as its concurrency limits allow: the
+	// request was not executed and can be retried later or on another node.
+	ErrOverloaded = errors.New("node overloaded")
+
+	// ErrNotResponsible is returned when the node that received a write is
+	// no longer responsible for its key (the ring changed since it was
+	// located): nothing was stored and the owner must be looked up again.
+	ErrNotResponsible = errors.New("node not responsible for the key")
 )
 
+// overloadedMsg starts the message of the requests rejected by the
+// concurrency limiter of a node, the only ResourceExhausted errors not
+// caused by a full storage.
+const overloadedMsg = "server overloaded"
+
 // normalizeError converts a gRPC status error into a common internal error.
+//
+// If the node attached a failure detail (stage, last hop, retryability),
+// the returned error also wraps the decoded *domain.Failure, which callers
+// can extract with errors.As; a routing failure also wraps ErrLookupFailed.
 func normalizeError(err error) error {
 	if err == nil {
 		return nil
@@ -29,27 +72,131 @@ func normalizeError(err error) error {
 		return ErrInternal
 	}
 
+	var base error
 	switch s.Code() {
 	case codes.NotFound:
-		return ErrNotFound
+		base = ErrNotFound
 	case codes.Unavailable:
-		return ErrUnavailable
+		base = ErrUnavailable
 	case codes.DeadlineExceeded:
-		return ErrDeadlineExceeded
+		base = ErrDeadlineExceeded
+	case codes.DataLoss:
+		base = ErrCorrupted
+	case codes.ResourceExhausted:
+		base = ErrStorageFull
+		if strings.HasPrefix(s.Message(), overloadedMsg) {
+			base = ErrOverloaded
+		}
+	case codes.InvalidArgument:
+		base = ErrInvalidArgument
+	case codes.PermissionDenied:
+		base = ErrPermissionDenied
+	case codes.Aborted:
+		base = ErrNotResponsible
 	default:
-		return ErrInternal
+		base = ErrInternal
+	}
+	if f, ok := domain.FailureFromError(err); ok {
+		if f.Stage == domain.StageRouting {
+			return fmt.Errorf("%w: %w: %w", base, ErrLookupFailed, f)
+		}
+		return fmt.Errorf("%w: %w", base, f)
 	}
+	return base
 }
 
 // Put inserts or updates a key-value pair on the node.
 func Put(ctx context.Context, client clientv1.ClientAPIClient, key, value string) (time.Duration, error) {
+	return PutTTL(ctx, client, key, value, 0)
+}
+
+// PutTTL is like Put, but the pair expires ttl after the call (rounded
+// down to whole seconds; 0 = never expires).
+func PutTTL(ctx context.Context, client clientv1.ClientAPIClient, key, value string, ttl time.Duration) (time.Duration, error) {
+	start := time.Now()
+	_, err := client.Put(ctx, &clientv1.PutRequest{
+		Resource:   &clientv1.Resource{Key: key, Value: value},
+		TtlSeconds: uint32(ttl / time.Second),
+	})
+	return time.Since(start), normalizeError(err)
+}
+
+// PutMeta is like PutTTL, but attaches the application metadata meta to
+// the pair (nil = none), returned by GetMeta and GetStore.
+func PutMeta(ctx context.Context, client clientv1.ClientAPIClient, key, value string, meta map[string]string, ttl time.Duration) (time.Duration, error) {
+	start := time.Now()
+	_, err := client.Put(ctx, &clientv1.PutRequest{
+		Resource:   &clientv1.Resource{Key: key, Value: value, Metadata: meta},
+		TtlSeconds: uint32(ttl / time.Second),
+	})
+	return time.Since(start), normalizeError(err)
+}
+
+// PutID is like PutTTL, but the key is given as a pre-hashed ID: idHex is
+// a hex digest (e.g. a content hash computed elsewhere) that the node
+// truncates into its ID space instead of hashing a key. GetID and DeleteID
+// address the pair in the same way.
+func PutID(ctx context.Context, client clientv1.ClientAPIClient, idHex, value string, ttl time.Duration) (time.Duration, error) {
 	start := time.Now()
 	_, err := client.Put(ctx, &clientv1.PutRequest{
-		Resource: &clientv1.Resource{Key: key, Value: value},
+		Resource:   &clientv1.Resource{Key: idHex, Value: value, Id: idHex},
+		TtlSeconds: uint32(ttl / time.Second),
 	})
 	return time.Since(start), normalizeError(err)
 }
 
+// PutContent stores value under a key derived from the value itself by the
+// node (content addressing) and returns the hex ID of that key, to be
+// passed to GetID and DeleteID. ttl is as for PutTTL.
+func PutContent(ctx context.Context, client clientv1.ClientAPIClient, value string, ttl time.Duration) (string, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.PutContent(ctx, &clientv1.PutContentRequest{
+		Value:      value,
+		TtlSeconds: uint32(ttl / time.Second),
+	})
+	if err != nil {
+		return "", time.Since(start), normalizeError(err)
+	}
+	return resp.Id, time.Since(start), nil
+}
+
+// PutIfAbsent stores a key-value pair only if the key is not stored yet
+// (expired pairs count as absent). It returns ErrPreconditionFailed if it
+// is, leaving the stored value unchanged. ttl is as for PutTTL.
+func PutIfAbsent(ctx context.Context, client clientv1.ClientAPIClient, key, value string, ttl time.Duration) (time.Duration, error) {
+	start := time.Now()
+	_, err := client.PutIf(ctx, &clientv1.PutIfRequest{
+		Resource:   &clientv1.Resource{Key: key, Value: value},
+		TtlSeconds: uint32(ttl / time.Second),
+		IfAbsent:   true,
+	})
+	return time.Since(start), putIfError(err)
+}
+
+// CompareAndSwap replaces the value of a key with value only if its
+// current value is old, atomically on the node responsible for the key. It
+// returns ErrPreconditionFailed if the key holds another value or is not
+// stored. ttl is as for PutTTL.
+func CompareAndSwap(ctx context.Context, client clientv1.ClientAPIClient, key, old, value string, ttl time.Duration) (time.Duration, error) {
+	start := time.Now()
+	_, err := client.PutIf(ctx, &clientv1.PutIfRequest{
+		Resource:   &clientv1.Resource{Key: key, Value: value},
+		TtlSeconds: uint32(ttl / time.Second),
+		IfEqual:    true,
+		Expected:   old,
+	})
+	return time.Since(start), putIfError(err)
+}
+
+// putIfError is normalizeError for the PutIf RPC, which reports an unmet
+// condition with FailedPrecondition.
+func putIfError(err error) error {
+	if status.Code(err) == codes.FailedPrecondition {
+		return ErrPreconditionFailed
+	}
+	return normalizeError(err)
+}
+
 // Get retrieves the value for a given key.
 func Get(ctx context.Context, client clientv1.ClientAPIClient, key string) (string, time.Duration, error) {
 	start := time.Now()
@@ -60,6 +207,27 @@ func Get(ctx context.Context, client clientv1.ClientAPIClient, key string) (stri
 	return resp.Value, time.Since(start), nil
 }
 
+// GetMeta is like Get, but also returns the metadata stored with the
+// value by PutMeta (nil if there is none).
+func GetMeta(ctx context.Context, client clientv1.ClientAPIClient, key string) (string, map[string]string, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.Get(ctx, &clientv1.GetRequest{Key: key})
+	if err != nil {
+		return "", nil, time.Since(start), normalizeError(err)
+	}
+	return resp.Value, resp.Metadata, time.Since(start), nil
+}
+
+// GetID is like Get for a pair stored with PutID.
+func GetID(ctx context.Context, client clientv1.ClientAPIClient, idHex string) (string, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.Get(ctx, &clientv1.GetRequest{Key: idHex, Id: idHex})
+	if err != nil {
+		return "", time.Since(start), normalizeError(err)
+	}
+	return resp.Value, time.Since(start), nil
+}
+
 // Delete removes a key from the node.
 func Delete(ctx context.Context, client clientv1.ClientAPIClient, key string) (time.Duration, error) {
 	start := time.Now()
@@ -67,6 +235,147 @@ func Delete(ctx context.Context, client clientv1.ClientAPIClient, key string) (t
 	return time.Since(start), normalizeError(err)
 }
 
+// DeleteID is like Delete for a pair stored with PutID.
+func DeleteID(ctx context.Context, client clientv1.ClientAPIClient, idHex string) (time.Duration, error) {
+	start := time.Now()
+	_, err := client.Delete(ctx, &clientv1.DeleteRequest{Key: idHex, Id: idHex})
+	return time.Since(start), normalizeError(err)
+}
+
+// DeleteRange removes every key whose ID (hex) lies in (from, to], wrapping
+// around zero if from > to (from == to = the whole ring), and returns how
+// many resources were removed, counting each replica copy.
+func DeleteRange(ctx context.Context, client clientv1.ClientAPIClient, from, to string) (int, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.DeleteRange(ctx, &clientv1.DeleteRangeRequest{From: from, To: to})
+	if err != nil {
+		return 0, time.Since(start), normalizeError(err)
+	}
+	return int(resp.GetDeleted()), time.Since(start), nil
+}
+
+// DeleteResult is the outcome of the deletion of a single key of a
+// BatchDelete.
+type DeleteResult struct {
+	Key string
+	Err error // nil if deleted, ErrNotFound if the key did not exist, ErrInternal otherwise
+}
+
+// BatchDelete removes many keys through a single BatchDelete stream: the
+// node routes each key to its owner and deletes them with one batched call
+// per owner. It returns one result per key, in order; missing keys do not
+// abort the batch. The error reports a failure of the stream itself, in
+// which case the results received so far are returned.
+func BatchDelete(ctx context.Context, client clientv1.ClientAPIClient, keys []string) ([]DeleteResult, time.Duration, error) {
+	start := time.Now()
+	stream, err := client.BatchDelete(ctx)
+	if err != nil {
+		return nil, time.Since(start), normalizeError(err)
+	}
+
+	// Send the keys while receiving the results, so that neither side
+	// blocks on flow control with large batches
+	sendErr := make(chan error, 1)
+	go func() {
+		for _, k := range keys {
+			if err := stream.Send(&clientv1.DeleteRequest{Key: k}); err != nil {
+				sendErr <- err
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	results := make([]DeleteResult, 0, len(keys))
+	for {
+		resp, recvErr := stream.Recv()
+		if recvErr == io.EOF {
+			break
+		}
+		if recvErr != nil {
+			return results, time.Since(start), normalizeError(recvErr)
+		}
+		r := DeleteResult{Key: resp.GetKey()}
+		switch {
+		case resp.GetError() != "":
+			r.Err = fmt.Errorf("%w: %s", ErrInternal, resp.GetError())
+		case !resp.GetDeleted():
+			r.Err = ErrNotFound
+		}
+		results = append(results, r)
+	}
+	if err := <-sendErr; err != nil && err != io.EOF {
+		return results, time.Since(start), normalizeError(err)
+	}
+	return results, time.Since(start), nil
+}
+
+// PutItem is a key-value pair of a BatchPut, with an optional time to
+// live (see PutTTL).
+type PutItem struct {
+	Key   string
+	Value string
+	TTL   time.Duration
+}
+
+// BatchPut stores many pairs through a single BatchPut stream: the node
+// routes each key to its owner and stores co-located keys with one stream
+// per owner. It returns the number of pairs stored and the error of each
+// pair that was not (by key, ErrInternal wrapping the reason). The error
+// reports a failure of the stream itself.
+func BatchPut(ctx context.Context, client clientv1.ClientAPIClient, items []PutItem) (int, map[string]error, time.Duration, error) {
+	start := time.Now()
+	stream, err := client.BatchPut(ctx)
+	if err != nil {
+		return 0, nil, time.Since(start), normalizeError(err)
+	}
+	for _, it := range items {
+		err := stream.Send(&clientv1.PutRequest{
+			Resource:   &clientv1.Resource{Key: it.Key, Value: it.Value},
+			TtlSeconds: uint32(it.TTL / time.Second),
+		})
+		if err != nil {
+			break // the actual error is returned by CloseAndRecv
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return 0, nil, time.Since(start), normalizeError(err)
+	}
+	failed := make(map[string]error, len(resp.GetFailed()))
+	for _, f := range resp.GetFailed() {
+		failed[f.GetKey()] = fmt.Errorf("%w: %s", ErrInternal, f.GetError())
+	}
+	return int(resp.GetStored()), failed, time.Since(start), nil
+}
+
+// GetResult is the outcome of the retrieval of a single key of a BatchGet.
+type GetResult struct {
+	Value string
+	Err   error // nil if found, ErrNotFound if the key does not exist, ErrInternal otherwise
+}
+
+// BatchGet retrieves many keys with a single request. It returns one
+// result per distinct key; missing keys do not fail the batch.
+func BatchGet(ctx context.Context, client clientv1.ClientAPIClient, keys []string) (map[string]GetResult, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.BatchGet(ctx, &clientv1.BatchGetRequest{Keys: keys})
+	if err != nil {
+		return nil, time.Since(start), normalizeError(err)
+	}
+	results := make(map[string]GetResult, len(keys))
+	for k, v := range resp.GetFound() {
+		results[k] = GetResult{Value: v}
+	}
+	for _, k := range resp.GetNotFound() {
+		results[k] = GetResult{Err: ErrNotFound}
+	}
+	for k, e := range resp.GetFailed() {
+		results[k] = GetResult{Err: fmt.Errorf("%w: %s", ErrInternal, e)}
+	}
+	return results, time.Since(start), nil
+}
+
 // Lookup performs a DHT lookup by ID and returns the successor node.
 func Lookup(ctx context.Context, client clientv1.ClientAPIClient, id string) (*clientv1.NodeInfo, time.Duration, error) {
 	start := time.Now()
@@ -77,6 +386,23 @@ func Lookup(ctx context.Context, client clientv1.ClientAPIClient, id string) (*c
 	return resp.Successor, time.Since(start), nil
 }
 
+// LookupTrace is like Lookup, but also returns the nodes traversed by the
+// lookup, from the contacted node to the successor, and the hop count.
+func LookupTrace(ctx context.Context, client clientv1.ClientAPIClient, id string) (*clientv1.LookupTraceResponse, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.LookupTrace(ctx, &clientv1.LookupTraceRequest{Id: id})
+	return resp, time.Since(start), normalizeError(err)
+}
+
+// LookupOwnership is like Lookup, but also returns the predecessor of the
+// successor (which owns the ids in (predecessor, successor]) and its
+// replicas, to check where a key is placed without touching it.
+func LookupOwnership(ctx context.Context, client clientv1.ClientAPIClient, id string) (*clientv1.LookupOwnershipResponse, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.LookupOwnership(ctx, &clientv1.LookupOwnershipRequest{Id: id})
+	return resp, time.Since(start), normalizeError(err)
+}
+
 // GetRoutingTable retrieves the node’s routing table.
 func GetRoutingTable(ctx context.Context, client clientv1.ClientAPIClient) (*clientv1.GetRoutingTableResponse, time.Duration, error) {
 	start := time.Now()
@@ -84,6 +410,121 @@ func GetRoutingTable(ctx context.Context, client clientv1.ClientAPIClient) (*cli
 	return resp, time.Since(start), normalizeError(err)
 }
 
+// Info retrieves a summary of the node's state (neighbours, list fill
+// levels, stored keys, uptime, pooled connections).
+func Info(ctx context.Context, client clientv1.ClientAPIClient) (*clientv1.InfoResponse, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.Info(ctx, &emptypb.Empty{})
+	return resp, time.Since(start), normalizeError(err)
+}
+
+// GetConfig retrieves the node's effective configuration, with secrets
+// redacted by the node.
+func GetConfig(ctx context.Context, client clientv1.ClientAPIClient) ([]*clientv1.ConfigEntry, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.GetConfig(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, time.Since(start), normalizeError(err)
+	}
+	return resp.GetEntries(), time.Since(start), nil
+}
+
+// PauseStabilization suspends the maintenance loops of the node for d
+// (0 = resume now), optionally still probing its predecessor, and returns
+// when they resume by themselves (zero if not paused).
+func PauseStabilization(ctx context.Context, client clientv1.ClientAPIClient, d time.Duration, keepFailureDetection bool) (time.Time, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.PauseStabilization(ctx, &clientv1.PauseStabilizationRequest{
+		DurationMs:           uint64(max(d, 0).Milliseconds()),
+		KeepFailureDetection: keepFailureDetection,
+	})
+	if err != nil {
+		return time.Time{}, time.Since(start), normalizeError(err)
+	}
+	var until time.Time
+	if ms := resp.GetResumeAtUnixMs(); ms != 0 {
+		until = time.UnixMilli(ms)
+	}
+	return until, time.Since(start), nil
+}
+
+// GetSpace returns the identifier space of the ring of the node, with
+// which the client can derive identifiers the way the nodes do.
+func GetSpace(ctx context.Context, client clientv1.ClientAPIClient) (domain.Space, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.GetSpace(ctx, &emptypb.Empty{})
+	if err != nil {
+		return domain.Space{}, time.Since(start), normalizeError(err)
+	}
+	sp, err := domain.NewSpace(int(resp.GetIdBits()), int(resp.GetDegree()), int(resp.GetSuccListSize()),
+		domain.WithHash(resp.GetHash()))
+	if err != nil {
+		return domain.Space{}, time.Since(start), fmt.Errorf("invalid space from node: %w", err)
+	}
+	return sp.WithNamespace(resp.GetNamespace()), time.Since(start), nil
+}
+
+// CheckSpace compares the identifier space got from a ring with the one the
+// client expects and returns an error wrapping ErrSpaceMismatch that lists
+// every difference. Zero fields of want (and an empty namespace) are not
+// checked; an empty hash stands for domain.DefaultHash.
+func CheckSpace(got, want domain.Space) error {
+	hash := func(sp domain.Space) string {
+		if sp.Hash == "" {
+			return domain.DefaultHash
+		}
+		return sp.Hash
+	}
+	var diffs []string
+	if want.Bits != 0 && want.Bits != got.Bits {
+		diffs = append(diffs, fmt.Sprintf("idBits %d, ring has %d", want.Bits, got.Bits))
+	}
+	if want.GraphGrade != 0 && want.GraphGrade != got.GraphGrade {
+		diffs = append(diffs, fmt.Sprintf("degree %d, ring has %d", want.GraphGrade, got.GraphGrade))
+	}
+	if want.SuccListSize != 0 && want.SuccListSize != got.SuccListSize {
+		diffs = append(diffs, fmt.Sprintf("succListSize %d, ring has %d", want.SuccListSize, got.SuccListSize))
+	}
+	if want.Hash != "" && hash(want) != hash(got) {
+		diffs = append(diffs, fmt.Sprintf("hash %s, ring has %s", hash(want), hash(got)))
+	}
+	if want.Namespace != "" && want.Namespace != got.Namespace {
+		diffs = append(diffs, fmt.Sprintf("namespace %q, ring has %q", want.Namespace, got.Namespace))
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("%w: expected %s", ErrSpaceMismatch, strings.Join(diffs, "; "))
+	}
+	return nil
+}
+
+// Rebalance makes the node transfer right away the resources it stores but
+// no longer owns, and returns how many were transferred and how many
+// failed.
+func Rebalance(ctx context.Context, client clientv1.ClientAPIClient) (int, int, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.Rebalance(ctx, &emptypb.Empty{})
+	if err != nil {
+		return 0, 0, time.Since(start), normalizeError(err)
+	}
+	return int(resp.GetTransferred()), int(resp.GetFailed()), time.Since(start), nil
+}
+
+// Shutdown makes the node leave the ring, handing its resources over to its
+// successors, and then stop. It returns once the handoff has completed,
+// reporting true, or after timeout (0 = the node's default of 30 seconds),
+// reporting false and the reason: the node stops in either case. ctx must
+// outlast timeout. It is an admin RPC: nodes that do not enable it fail with
+// ErrPermissionDenied.
+func Shutdown(ctx context.Context, client clientv1.ClientAPIClient, timeout time.Duration) (bool, string, time.Duration, error) {
+	start := time.Now()
+	secs := uint32((timeout + time.Second - 1) / time.Second) // rounded up
+	resp, err := client.Shutdown(ctx, &clientv1.ShutdownRequest{TimeoutSeconds: secs})
+	if err != nil {
+		return false, "", time.Since(start), normalizeError(err)
+	}
+	return resp.GetHandoffCompleted(), resp.GetDetail(), time.Since(start), nil
+}
+
 // GetStore streams all key-value pairs stored in the node.
 func GetStore(ctx context.Context, client clientv1.ClientAPIClient) ([]*clientv1.Resource, time.Duration, error) {
 	start := time.Now()
@@ -104,3 +545,26 @@ func GetStore(ctx context.Context, client clientv1.ClientAPIClient) ([]*clientv1
 	}
 	return resources, time.Since(start), nil
 }
+
+// GetStorePage returns one page of the key-value pairs stored in the node,
+// ordered by ID and limited to the keys starting with prefix, together with
+// the token of the next page ("" on the last one). An empty after requests
+// the first page; limit 0 lets the node choose the page size.
+func GetStorePage(ctx context.Context, client clientv1.ClientAPIClient, prefix, after string, limit int) ([]*clientv1.Resource, string, time.Duration, error) {
+	start := time.Now()
+	resp, err := client.GetStorePage(ctx, &clientv1.GetStorePageRequest{
+		Prefix:    prefix,
+		PageToken: after,
+		Limit:     uint32(max(limit, 0)),
+	})
+	if err != nil {
+		return nil, "", time.Since(start), normalizeError(err)
+	}
+	resources := make([]*clientv1.Resource, 0, len(resp.GetItems()))
+	for _, it := range resp.GetItems() {
+		if it.GetItem() != nil {
+			resources = append(resources, it.Item)
+		}
+	}
+	return resources, resp.GetNextPageToken(), time.Since(start), nil
+}
@@ -0,0 +1,110 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStorageQuotaBackpressure(t *testing.T) {
+	// Ogni risorsa occupa 2 (ID a 16 bit) + 6 (chiave) + 20 (valore) = 28
+	// byte: la quota ne ammette 3 per nodo
+	const quota = 100
+	value := strings.Repeat("v", 20)
+	r := testring.New(t, 2, testring.WithStorageOptions(storage.WithQuota(quota)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	api, conn, err := client.Connect(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+
+	// Riempie l'anello finché un nodo rifiuta una Put
+	stored := make(map[string][]string) // indirizzo del responsabile -> chiavi
+	seen := make(map[string]struct{})
+	var rejected string
+	for i := 0; i < 100 && rejected == ""; i++ {
+		key := fmt.Sprintf("key-%02d", i)
+		id := r.Space.NewIdFromString(key)
+		if _, dup := seen[id.ToHexString(false)]; dup {
+			continue // collisione nello spazio a 16 bit
+		}
+		seen[id.ToHexString(false)] = struct{}{}
+		_, err := client.Put(ctx, api, key, value)
+		switch {
+		case err == nil:
+			owner := r.Owner(id).Addr
+			stored[owner] = append(stored[owner], key)
+		case errors.Is(err, client.ErrStorageFull):
+			rejected = key
+		default:
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+	if rejected == "" {
+		t.Fatal("no Put rejected by the storage quota")
+	}
+	owner := r.Owner(r.Space.NewIdFromString(rejected))
+	if got := len(stored[owner.Addr]); got != 3 {
+		t.Fatalf("owner %s holds %d keys before rejecting, want 3", owner.Addr, got)
+	}
+
+	// Il rifiuto è temporaneo: il client può ritentare più tardi
+	_, err = client.Put(ctx, api, rejected, value)
+	var f *domain.Failure
+	if !errors.As(err, &f) || !f.Retryable {
+		t.Fatalf("Put(%s) on full node: got %v, want a retryable failure", rejected, err)
+	}
+
+	// Liberato lo spazio, la stessa Put va a buon fine
+	if _, err := client.Delete(ctx, api, stored[owner.Addr][0]); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := client.Put(ctx, api, rejected, value); err != nil {
+		t.Fatalf("Put(%s) after Delete: %v", rejected, err)
+	}
+	if got, _, err := client.Get(ctx, api, rejected); err != nil || got != value {
+		t.Fatalf("Get(%s): got %q, %v", rejected, got, err)
+	}
+}
+
+func TestMaxKeysBackpressure(t *testing.T) {
+	r := testring.New(t, 1, testring.WithStorageOptions(storage.WithMaxKeys(2, storage.EvictReject)))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	api, conn, err := client.Connect(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+
+	for _, key := range []string{"a", "b"} {
+		if _, err := client.Put(ctx, api, key, "v"); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+	if _, err := client.Put(ctx, api, "c", "v"); !errors.Is(err, client.ErrStorageFull) {
+		t.Fatalf("Put over maxKeys: got %v, want ErrStorageFull", err)
+	}
+	// una sovrascrittura non aggiunge chiavi
+	if _, err := client.Put(ctx, api, "a", "w"); err != nil {
+		t.Fatalf("overwrite at maxKeys: %v", err)
+	}
+
+	info, _, err := client.Info(ctx, api)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if info.StoredKeys != 2 || info.MaxKeys != 2 {
+		t.Errorf("Info: %d/%d stored keys, want 2/2", info.StoredKeys, info.MaxKeys)
+	}
+}
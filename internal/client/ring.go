@@ -0,0 +1,333 @@
+package client
+
+import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
+	"KoordeDHT/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// DefaultRingRefresh is the interval at which a Ring refreshes its view of
+// the membership unless WithRingRefresh overrides it.
+const DefaultRingRefresh = 30 * time.Second
+
+// RingOption configures a Ring.
+type RingOption func(*ringOptions)
+
+type ringOptions struct {
+	refresh  time.Duration
+	connect  []ConnectOption
+	walkOpts []WalkOption
+}
+
+// WithRingRefresh sets the interval at which the ring view is refreshed in
+// the background (<= 0 = only after a request that could not reach the
+// owner of the view).
+func WithRingRefresh(d time.Duration) RingOption {
+	return func(o *ringOptions) {
+		o.refresh = d
+	}
+}
+
+// WithRingConnectOptions sets the options of the connections opened to
+// the members (e.g. WithTLS).
+func WithRingConnectOptions(opts ...ConnectOption) RingOption {
+	return func(o *ringOptions) {
+		o.connect = append(o.connect, opts...)
+	}
+}
+
+// WithRingWalkOptions sets the options of the ring walks that build the
+// membership view (e.g. WithMaxRingWalk).
+func WithRingWalkOptions(opts ...WalkOption) RingOption {
+	return func(o *ringOptions) {
+		o.walkOpts = append(o.walkOpts, opts...)
+	}
+}
+
+// Ring is a client-side view of the ring membership for read-heavy
+// clients: it maps every key to its owner with the same consistent hashing
+// as the nodes and sends the request to the owner directly, which then
+// serves it without any routing hop (a node runs no lookup for the keys
+// between its predecessor and itself).
+//
+// The view is built by walking the ring (see OwnershipMap, whose walk
+// uses plaintext connections) and refreshed periodically. If the owner of
+// the view cannot be reached, the write reached a node that is no longer
+// responsible for its key (ErrNotResponsible), or the view is empty, the
+// owner is found with a full Lookup through another member, the request
+// is sent there and the view is refreshed. A stale view never
+// makes a request fail as long as the contacted node is alive: the node
+// then routes the request as usual, at the cost of the saved hop.
+type Ring struct {
+	space domain.Space
+	opts  ringOptions
+
+	mu      sync.Mutex
+	seeds   []string     // addresses tried to refresh the view, seed first
+	members []ringMember // sorted by ID
+	conns   map[string]*ringConn
+
+	refreshMu sync.Mutex // serializes refreshes
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+type ringMember struct {
+	id   domain.ID
+	addr string
+}
+
+type ringConn struct {
+	api  clientv1.ClientAPIClient
+	conn *grpc.ClientConn
+}
+
+// NewRing builds the view of the ring reachable from seed, whose
+// identifiers live in sp (same bits, hash and namespace as the nodes), and
+// starts refreshing it in the background. Close releases it.
+func NewRing(ctx context.Context, seed string, sp domain.Space, opts ...RingOption) (*Ring, error) {
+	o := ringOptions{refresh: DefaultRingRefresh}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	r := &Ring{
+		space: sp,
+		opts:  o,
+		seeds: []string{seed},
+		conns: make(map[string]*ringConn),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	if err := r.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	go r.refreshLoop()
+	return r, nil
+}
+
+// refreshLoop refreshes the view every refresh interval until Close.
+func (r *Ring) refreshLoop() {
+	defer close(r.done)
+	if r.opts.refresh <= 0 {
+		<-r.stop
+		return
+	}
+	ticker := time.NewTicker(r.opts.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.opts.refresh)
+			_ = r.Refresh(ctx)
+			cancel()
+		}
+	}
+}
+
+// Refresh rebuilds the view by walking the ring from the first seed or
+// known member that answers.
+func (r *Ring) Refresh(ctx context.Context) error {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	r.mu.Lock()
+	seeds := append([]string(nil), r.seeds...)
+	for _, m := range r.members {
+		seeds = append(seeds, m.addr)
+	}
+	r.mu.Unlock()
+
+	lastErr := errors.New("ring: no seed")
+	tried := make(map[string]bool)
+	for _, seed := range seeds {
+		if tried[seed] {
+			continue
+		}
+		tried[seed] = true
+		om, err := ownershipMap(ctx, seed, r.space.Bits, false, r.opts.walkOpts)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		members := make([]ringMember, 0, len(om.Intervals))
+		for _, iv := range om.Intervals {
+			id, err := r.space.FromHexString(iv.End)
+			if err != nil {
+				return fmt.Errorf("ring: member %s: %w", iv.Owner, err)
+			}
+			members = append(members, ringMember{id: id, addr: iv.Owner})
+		}
+		sort.Slice(members, func(i, j int) bool { return members[i].id.Cmp(members[j].id) < 0 })
+
+		r.mu.Lock()
+		r.members = members
+		live := make(map[string]bool, len(members))
+		for _, m := range members {
+			live[m.addr] = true
+		}
+		for addr, c := range r.conns {
+			if !live[addr] {
+				_ = c.conn.Close()
+				delete(r.conns, addr)
+			}
+		}
+		r.mu.Unlock()
+		return nil
+	}
+	return fmt.Errorf("ring: refresh failed: %w", lastErr)
+}
+
+// Members returns the addresses of the members in the view, by ID.
+func (r *Ring) Members() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]string, len(r.members))
+	for i, m := range r.members {
+		out[i] = m.addr
+	}
+	return out
+}
+
+// Owner returns the address of the owner of key according to the view, or
+// false if the view is empty.
+func (r *Ring) Owner(key string) (string, bool) {
+	id := r.space.NewIdFromString(key)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.members) == 0 {
+		return "", false
+	}
+	i := sort.Search(len(r.members), func(i int) bool { return r.members[i].id.Cmp(id) >= 0 })
+	return r.members[i%len(r.members)].addr, true
+}
+
+// Put stores the pair on the owner of key (see Ring).
+func (r *Ring) Put(ctx context.Context, key, value string) (time.Duration, error) {
+	return r.do(ctx, key, func(api clientv1.ClientAPIClient) error {
+		_, err := Put(ctx, api, key, value)
+		return err
+	})
+}
+
+// Get reads key from its owner (see Ring).
+func (r *Ring) Get(ctx context.Context, key string) (string, time.Duration, error) {
+	var value string
+	d, err := r.do(ctx, key, func(api clientv1.ClientAPIClient) error {
+		var err error
+		value, _, err = Get(ctx, api, key)
+		return err
+	})
+	return value, d, err
+}
+
+// Delete removes key from its owner (see Ring).
+func (r *Ring) Delete(ctx context.Context, key string) (time.Duration, error) {
+	return r.do(ctx, key, func(api clientv1.ClientAPIClient) error {
+		_, err := Delete(ctx, api, key)
+		return err
+	})
+}
+
+// do runs op on the owner of key in the view and, if it cannot be reached
+// or is no longer responsible for key, on the owner found by a full
+// Lookup, refreshing the view.
+func (r *Ring) do(ctx context.Context, key string, op func(clientv1.ClientAPIClient) error) (time.Duration, error) {
+	start := time.Now()
+	addr, ok := r.Owner(key)
+	if ok {
+		err := r.call(addr, op)
+		switch {
+		case errors.Is(err, ErrUnavailable):
+			r.forget(addr)
+		case !errors.Is(err, ErrNotResponsible):
+			return time.Since(start), err
+		}
+	}
+
+	// fallback: full lookup through another member
+	owner, err := r.lookup(ctx, key, addr)
+	if err != nil {
+		return time.Since(start), err
+	}
+	err = r.call(owner, op)
+	_ = r.Refresh(ctx) // best-effort: the periodic refresh retries
+	return time.Since(start), err
+}
+
+// lookup returns the owner of key found by a Lookup through the first
+// member of the view other than skip that answers.
+func (r *Ring) lookup(ctx context.Context, key, skip string) (string, error) {
+	id := r.space.NewIdFromString(key).ToHexString(true)
+	lastErr := fmt.Errorf("%w: no member to look up %q", ErrUnavailable, key)
+	for _, addr := range append(r.Members(), r.seeds...) {
+		if addr == skip {
+			continue
+		}
+		var owner *clientv1.NodeInfo
+		err := r.call(addr, func(api clientv1.ClientAPIClient) error {
+			var err error
+			owner, _, err = Lookup(ctx, api, id)
+			return err
+		})
+		if err == nil && owner.GetAddr() != "" {
+			return owner.GetAddr(), nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return "", lastErr
+}
+
+// call runs op on the member at addr, over a cached connection.
+func (r *Ring) call(addr string, op func(clientv1.ClientAPIClient) error) error {
+	r.mu.Lock()
+	c, ok := r.conns[addr]
+	if !ok {
+		api, conn, err := Connect(addr, r.opts.connect...)
+		if err != nil {
+			r.mu.Unlock()
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+		c = &ringConn{api: api, conn: conn}
+		r.conns[addr] = c
+	}
+	r.mu.Unlock()
+	return op(c.api)
+}
+
+// forget closes the connection to addr, which did not answer.
+func (r *Ring) forget(addr string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.conns[addr]; ok {
+		_ = c.conn.Close()
+		delete(r.conns, addr)
+	}
+}
+
+// Close stops the background refresh and closes the connections.
+func (r *Ring) Close() error {
+	close(r.stop)
+	<-r.done
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var errs []error
+	for addr, c := range r.conns {
+		errs = append(errs, c.conn.Close())
+		delete(r.conns, addr)
+	}
+	return errors.Join(errs...)
+}
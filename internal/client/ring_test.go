@@ -0,0 +1,173 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestRingRoutesToOwner(t *testing.T) {
+	// Registra il nodo che ha ricevuto l'ultima Get di un client
+	var mu sync.Mutex
+	var served string
+	record := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasSuffix(info.FullMethod, "ClientAPI/Get") {
+			if p, ok := peer.FromContext(ctx); ok && p.LocalAddr != nil {
+				mu.Lock()
+				served = p.LocalAddr.String()
+				mu.Unlock()
+			}
+		}
+		return handler(ctx, req)
+	}
+	r := testring.New(t, 4, testring.WithServerOptions(server.WithUnaryInterceptors(record)))
+	r.WaitStable()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// senza aggiornamento periodico: la vista si corregge solo dopo un errore
+	ring, err := client.NewRing(ctx, r.Members[0].Addr, r.Space, client.WithRingRefresh(0))
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+	defer ring.Close()
+	if got := len(ring.Members()); got != len(r.Members) {
+		t.Fatalf("view has %d members, want %d", got, len(r.Members))
+	}
+
+	// checkDirect verifica che la Get di key arrivi al responsabile attuale
+	checkDirect := func(key string, wantErr error) {
+		t.Helper()
+		owner := r.Owner(r.Space.NewIdFromString(key))
+		if addr, _ := ring.Owner(key); addr != owner.Addr {
+			t.Errorf("view owner of %s = %s, want %s", key, addr, owner.Addr)
+		}
+		_, _, err := ring.Get(ctx, key)
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("Get(%s): %v, want %v", key, err, wantErr)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if served != owner.Addr {
+			t.Errorf("Get(%s) served by %s, want the owner %s", key, served, owner.Addr)
+		}
+	}
+
+	keys := make([]string, 8)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("ring-%d", i)
+		if _, err := ring.Put(ctx, keys[i], "v"); err != nil {
+			t.Fatalf("Put(%s): %v", keys[i], err)
+		}
+	}
+	for _, k := range keys {
+		checkDirect(k, nil)
+	}
+
+	// il responsabile di una chiave esce: la Get ripiega su una Lookup e la
+	// vista si corregge (la chiave, senza repliche, è persa)
+	lost := keys[0]
+	r.Kill(r.Owner(r.Space.NewIdFromString(lost)))
+	r.WaitStable()
+	if _, _, err := ring.Get(ctx, lost); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("Get(%s) after the owner left: %v, want ErrNotFound", lost, err)
+	}
+	checkDirect(lost, client.ErrNotFound)
+
+	// un nuovo membro entra nella vista con l'aggiornamento periodico
+	watcher, err := client.NewRing(ctx, r.Live()[0].Addr, r.Space, client.WithRingRefresh(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+	defer watcher.Close()
+	r.Add()
+	r.WaitStable()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(watcher.Members()) != len(r.Live()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("view has %d members, want %d", len(watcher.Members()), len(r.Live()))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	for _, k := range keys[1:] {
+		owner := r.Owner(r.Space.NewIdFromString(k))
+		if addr, _ := watcher.Owner(k); addr != owner.Addr {
+			t.Errorf("view owner of %s = %s, want %s", k, addr, owner.Addr)
+		}
+	}
+}
+
+func TestRingOwnerRunsNoLookup(t *testing.T) {
+	// Conta i passi di lookup (su qualsiasi nodo) diretti a una delle
+	// chiavi, e rifiuta come "non responsabile" la prima Put se reject è
+	// impostato
+	var mu sync.Mutex
+	var steps int
+	var reject bool
+	ids := make(map[string]bool)
+	count := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		mu.Lock()
+		if r, ok := req.(interface{ GetTargetId() []byte }); ok && ids[string(r.GetTargetId())] {
+			steps++
+		}
+		if reject && strings.HasSuffix(info.FullMethod, "ClientAPI/Put") {
+			reject = false
+			mu.Unlock()
+			return nil, status.Error(codes.Aborted, "not responsible")
+		}
+		mu.Unlock()
+		return handler(ctx, req)
+	}
+	r := testring.New(t, 8, testring.WithServerOptions(server.WithUnaryInterceptors(count)))
+	r.WaitStable()
+	keys := make([]string, 20)
+	mu.Lock()
+	for i := range keys {
+		keys[i] = fmt.Sprintf("direct-%d", i)
+		ids[string(r.Space.NewIdFromString(keys[i]))] = true
+	}
+	mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ring, err := client.NewRing(ctx, r.Members[0].Addr, r.Space, client.WithRingRefresh(0))
+	if err != nil {
+		t.Fatalf("NewRing: %v", err)
+	}
+	defer ring.Close()
+
+	// il responsabile serve le richieste senza alcun passo di lookup
+	for _, k := range keys {
+		if _, err := ring.Put(ctx, k, "v"); err != nil {
+			t.Fatalf("Put(%s): %v", k, err)
+		}
+		if got, _, err := ring.Get(ctx, k); err != nil || got != "v" {
+			t.Fatalf("Get(%s) = %q, %v, want v", k, got, err)
+		}
+	}
+	mu.Lock()
+	if steps != 0 {
+		t.Errorf("%d lookup steps for requests sent to the owner, want 0", steps)
+	}
+	// una Put rifiutata dal responsabile della vista ripiega su una Lookup
+	reject = true
+	mu.Unlock()
+	if _, err := ring.Put(ctx, keys[0], "w"); err != nil {
+		t.Fatalf("Put(%s) rejected as not responsible: %v, want the fallback to store it", keys[0], err)
+	}
+	if got, _, err := ring.Get(ctx, keys[0]); err != nil || got != "w" {
+		t.Errorf("Get(%s) = %q, %v, want w", keys[0], got, err)
+	}
+}
@@ -0,0 +1,389 @@
+package client
+
+import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
+	"KoordeDHT/internal/domain"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DefaultMaxRingWalk bounds the successor walks performed by CheckRing and
+// OwnershipMap so that a corrupted ring (e.g. a successor chain that never
+// returns to the seed and escapes loop detection) cannot make them run
+// forever. Override it with WithMaxRingWalk.
+const DefaultMaxRingWalk = 4096
+
+// WalkOption configures the ring walks performed by CheckRing and
+// OwnershipMap.
+type WalkOption func(*walkOptions)
+
+type walkOptions struct {
+	maxSteps int
+}
+
+// WithMaxRingWalk sets the maximum number of successor hops of a ring walk.
+// When the limit is hit the walk stops and the partial result is marked as
+// truncated. Values <= 0 keep DefaultMaxRingWalk.
+func WithMaxRingWalk(steps int) WalkOption {
+	return func(o *walkOptions) {
+		if steps > 0 {
+			o.maxSteps = steps
+		}
+	}
+}
+
+func newWalkOptions(opts []WalkOption) walkOptions {
+	o := walkOptions{maxSteps: DefaultMaxRingWalk}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Kinds of violations reported by CheckRing.
+const (
+	ViolationUnreachable        = "unreachable"         // a node listed as successor did not answer
+	ViolationMissingSuccessor   = "missing-successor"   // a node has an empty successor list
+	ViolationSuccessorLoop      = "successor-loop"      // the walk revisited a node other than the seed
+	ViolationMissingPredecessor = "missing-predecessor" // a ring member has no predecessor
+	ViolationUnknownPredecessor = "unknown-predecessor" // the predecessor is not a ring member
+	ViolationPredecessor        = "predecessor"         // succ(pred(n)) != n
+	ViolationOrdering           = "ordering"            // IDs along the walk wrap more than once (gap/overlap)
+	ViolationDeBruijn           = "debruijn"            // a de Bruijn pointer is dead, not a member or not pred(k·n)
+)
+
+// RingViolation describes a single broken invariant found by CheckRing.
+type RingViolation struct {
+	Kind    string             // one of the Violation* constants
+	Node    *clientv1.NodeInfo // node on which the violation was observed
+	Related *clientv1.NodeInfo // other node involved, if any
+	Detail  string             // human-readable explanation
+}
+
+func (v RingViolation) String() string {
+	s := fmt.Sprintf("[%s] %s", v.Kind, nodeString(v.Node))
+	if v.Related != nil {
+		s += " -> " + nodeString(v.Related)
+	}
+	if v.Detail != "" {
+		s += ": " + v.Detail
+	}
+	return s
+}
+
+// RingReport is the result of a ring consistency check.
+type RingReport struct {
+	// Nodes holds the routing tables of the ring members, in the order
+	// in which they were visited following successor pointers from the seed.
+	Nodes []*clientv1.GetRoutingTableResponse
+	// Violations lists every broken invariant that was detected.
+	Violations []RingViolation
+	// Complete reports whether the successor walk returned to the seed.
+	Complete bool
+	// Truncated reports whether the walk was stopped by the maximum number
+	// of steps (see WithMaxRingWalk) before returning to the seed.
+	Truncated bool
+}
+
+// OK reports whether the ring was fully traversed without violations.
+func (r *RingReport) OK() bool {
+	return r.Complete && len(r.Violations) == 0
+}
+
+// CheckRing walks the whole ring starting from seed by following successor
+// pointers and verifies the global invariants of the overlay:
+//
+//   - the walk returns to the seed (the ring is closed);
+//   - every member's predecessor is a ring member and its successor points
+//     back to the member (succ(pred(n)) == n);
+//   - node IDs increase along the walk and wrap exactly once, i.e. the
+//     ownership intervals (pred, n] tile the identifier space without gaps
+//     or overlaps;
+//   - every de Bruijn pointer resolves to a live node, and the first one
+//     (the anchor) is the predecessor of k·n among the ring members.
+//
+// Unreachable successors are reported and skipped using the next entry of
+// the successor list. An error is returned only if the seed itself cannot
+// be queried; every other problem is reported as a violation. A walk
+// stopped by the step limit returns the partial report with Truncated set.
+func CheckRing(ctx context.Context, seed string, opts ...WalkOption) (*RingReport, error) {
+	report, members, err := walkRing(ctx, seed, newWalkOptions(opts).maxSteps)
+	if err != nil {
+		return nil, err
+	}
+	if !report.Complete {
+		return report, nil
+	}
+
+	checkPredecessors(report, members)
+	checkOrdering(report)
+	space, err := fetchSpace(ctx, seed)
+	if err != nil {
+		return nil, fmt.Errorf("ringcheck: seed %s: %w", seed, err)
+	}
+	checkDeBruijn(ctx, report, members, space)
+	return report, nil
+}
+
+// walkRing follows successor pointers from seed until the walk returns to
+// it, collecting the routing table of every member visited. Unreachable
+// successors, empty successor lists and loops are recorded as violations.
+// The returned map indexes the visited routing tables by node ID.
+//
+// At most maxSteps successor hops are followed; if the limit is hit the
+// report is marked as truncated. walkRing is shared by every ring walk of
+// this package.
+func walkRing(ctx context.Context, seed string, maxSteps int) (*RingReport, map[string]*clientv1.GetRoutingTableResponse, error) {
+	first, err := fetchRoutingTable(ctx, seed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ringcheck: seed %s: %w", seed, err)
+	}
+
+	report := &RingReport{}
+	members := make(map[string]*clientv1.GetRoutingTableResponse) // key: node ID
+	members[first.Self.Id] = first
+	report.Nodes = append(report.Nodes, first)
+
+	cur := first
+	step := 0
+	for ; step < maxSteps; step++ {
+		if len(cur.Successors) == 0 {
+			report.Violations = append(report.Violations, RingViolation{
+				Kind: ViolationMissingSuccessor, Node: cur.Self,
+				Detail: "successor list is empty",
+			})
+			break
+		}
+
+		// Pick the first reachable successor, reporting the dead ones.
+		var next *clientv1.GetRoutingTableResponse
+		var nextInfo *clientv1.NodeInfo
+		for _, succ := range cur.Successors {
+			if succ.Id == first.Self.Id {
+				next, nextInfo = first, succ
+				break
+			}
+			if known, ok := members[succ.Id]; ok {
+				next, nextInfo = known, succ
+				break
+			}
+			rt, err := fetchRoutingTable(ctx, succ.Addr)
+			if err != nil {
+				report.Violations = append(report.Violations, RingViolation{
+					Kind: ViolationUnreachable, Node: cur.Self, Related: succ,
+					Detail: err.Error(),
+				})
+				continue
+			}
+			next, nextInfo = rt, succ
+			break
+		}
+		if next == nil {
+			break
+		}
+		if nextInfo.Id == first.Self.Id {
+			report.Complete = true
+			break
+		}
+		if _, ok := members[nextInfo.Id]; ok {
+			report.Violations = append(report.Violations, RingViolation{
+				Kind: ViolationSuccessorLoop, Node: cur.Self, Related: nextInfo,
+				Detail: "successor walk revisited a node without returning to the seed",
+			})
+			break
+		}
+		members[nextInfo.Id] = next
+		report.Nodes = append(report.Nodes, next)
+		cur = next
+	}
+	report.Truncated = step == maxSteps
+	return report, members, nil
+}
+
+// checkPredecessors verifies that succ(pred(n)) == n for every member.
+func checkPredecessors(report *RingReport, members map[string]*clientv1.GetRoutingTableResponse) {
+	nodes := report.Nodes
+	for i, rt := range nodes {
+		pred := rt.Predecessor
+		if pred == nil {
+			report.Violations = append(report.Violations, RingViolation{
+				Kind: ViolationMissingPredecessor, Node: rt.Self,
+				Detail: "predecessor is not set",
+			})
+			continue
+		}
+		predRT, ok := members[pred.Id]
+		if !ok {
+			report.Violations = append(report.Violations, RingViolation{
+				Kind: ViolationUnknownPredecessor, Node: rt.Self, Related: pred,
+				Detail: "predecessor is not a member of the ring",
+			})
+			continue
+		}
+		if len(predRT.Successors) == 0 || predRT.Successors[0].Id != rt.Self.Id {
+			report.Violations = append(report.Violations, RingViolation{
+				Kind: ViolationPredecessor, Node: rt.Self, Related: pred,
+				Detail: "successor of predecessor does not point back",
+			})
+			continue
+		}
+		// The walk order gives the expected predecessor directly.
+		expected := nodes[(i-1+len(nodes))%len(nodes)].Self
+		if pred.Id != expected.Id {
+			report.Violations = append(report.Violations, RingViolation{
+				Kind: ViolationPredecessor, Node: rt.Self, Related: pred,
+				Detail: fmt.Sprintf("expected predecessor %s", nodeString(expected)),
+			})
+		}
+	}
+}
+
+// checkOrdering verifies that IDs along the walk wrap around zero exactly
+// once: any additional descent means two ownership intervals overlap and
+// some other part of the ring is not covered.
+func checkOrdering(report *RingReport) {
+	nodes := report.Nodes
+	if len(nodes) < 2 {
+		return
+	}
+	wraps := 0
+	for i, rt := range nodes {
+		next := nodes[(i+1)%len(nodes)]
+		a, errA := parseHexID(rt.Self.Id)
+		b, errB := parseHexID(next.Self.Id)
+		if errA != nil || errB != nil {
+			report.Violations = append(report.Violations, RingViolation{
+				Kind: ViolationOrdering, Node: rt.Self, Related: next.Self,
+				Detail: "unparsable node ID",
+			})
+			continue
+		}
+		if b.Cmp(a) <= 0 {
+			wraps++
+			if wraps > 1 {
+				report.Violations = append(report.Violations, RingViolation{
+					Kind: ViolationOrdering, Node: rt.Self, Related: next.Self,
+					Detail: "successor ID is not greater: ownership intervals overlap",
+				})
+			}
+		}
+	}
+}
+
+// checkDeBruijn verifies that every de Bruijn pointer refers to a ring
+// member, or at least to a node that still answers, and that the anchor of
+// every window is the member preceding k·n, the node a Koorde lookup from n
+// expects to reach with its first de Bruijn hop.
+func checkDeBruijn(ctx context.Context, report *RingReport, members map[string]*clientv1.GetRoutingTableResponse, space domain.Space) {
+	alive := make(map[string]bool) // cache for non-member probes, key: addr
+	for _, rt := range report.Nodes {
+		for _, d := range rt.DeBruijnList {
+			if m, ok := members[d.Id]; ok && m.Self.Addr == d.Addr {
+				continue
+			}
+			up, seen := alive[d.Addr]
+			if !seen {
+				_, err := fetchRoutingTable(ctx, d.Addr)
+				up = err == nil
+				alive[d.Addr] = up
+			}
+			detail := "pointer is not a ring member"
+			if !up {
+				detail = "pointer does not resolve to a live node"
+			}
+			report.Violations = append(report.Violations, RingViolation{
+				Kind: ViolationDeBruijn, Node: rt.Self, Related: d, Detail: detail,
+			})
+		}
+	}
+
+	ids := make([]*big.Int, 0, len(report.Nodes))
+	for _, rt := range report.Nodes {
+		id, err := parseHexID(rt.Self.Id)
+		if err != nil {
+			return // already reported by checkOrdering
+		}
+		ids = append(ids, id)
+	}
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(space.Bits))
+	for i, rt := range report.Nodes {
+		if len(rt.DeBruijnList) == 0 {
+			continue // de Bruijn routing disabled or window not built yet
+		}
+		target := new(big.Int).Mul(ids[i], big.NewInt(int64(space.GraphGrade)))
+		target.Mod(target, modulus)
+		want := report.Nodes[predecessorIndex(ids, target)].Self
+		if got := rt.DeBruijnList[0]; got.Id != want.Id {
+			report.Violations = append(report.Violations, RingViolation{
+				Kind: ViolationDeBruijn, Node: rt.Self, Related: got,
+				Detail: fmt.Sprintf("anchor is not pred(k·n), want %s", nodeString(want)),
+			})
+		}
+	}
+}
+
+// predecessorIndex returns the index of the member preceding target, i.e.
+// the one with the greatest ID smaller than target, wrapping around to the
+// greatest ID of all when no ID is smaller.
+func predecessorIndex(ids []*big.Int, target *big.Int) int {
+	best, last := -1, 0
+	for i, id := range ids {
+		if id.Cmp(ids[last]) > 0 {
+			last = i
+		}
+		if id.Cmp(target) < 0 && (best < 0 || id.Cmp(ids[best]) > 0) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return last
+	}
+	return best
+}
+
+// fetchRoutingTable opens a short-lived connection to addr and returns its
+// routing table.
+func fetchRoutingTable(ctx context.Context, addr string) (*clientv1.GetRoutingTableResponse, error) {
+	api, conn, err := Connect(addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	rt, _, err := GetRoutingTable(ctx, api)
+	if err != nil {
+		return nil, err
+	}
+	if rt.GetSelf() == nil {
+		return nil, fmt.Errorf("node %s returned an empty routing table", addr)
+	}
+	return rt, nil
+}
+
+// fetchSpace opens a short-lived connection to addr and returns the
+// identifier space of its ring.
+func fetchSpace(ctx context.Context, addr string) (domain.Space, error) {
+	api, conn, err := Connect(addr)
+	if err != nil {
+		return domain.Space{}, err
+	}
+	defer conn.Close()
+	space, _, err := GetSpace(ctx, api)
+	return space, err
+}
+
+func parseHexID(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(strings.TrimPrefix(strings.ToLower(s), "0x"), 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hex ID %q", s)
+	}
+	return v, nil
+}
+
+func nodeString(n *clientv1.NodeInfo) string {
+	if n == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%s(%s)", n.Id, n.Addr)
+}
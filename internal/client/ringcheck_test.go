@@ -0,0 +1,111 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCheckRing(t *testing.T) {
+	r := testring.New(t, 4)
+	// WaitStable non guarda le ancore de Bruijn: si attende che anche
+	// queste siano corrette prima di fermare la manutenzione.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		report, err := client.CheckRing(ctx, r.Members[0].Addr)
+		cancel()
+		if err == nil && report.OK() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("ring not consistent before the test: err=%v report=%+v", err, report)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	r.StopStabilizers()
+
+	t.Run("consistent ring", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		report, err := client.CheckRing(ctx, r.Members[0].Addr)
+		if err != nil {
+			t.Fatalf("CheckRing: %v", err)
+		}
+		if !report.OK() {
+			t.Fatalf("expected a consistent ring, got complete=%v violations=%v", report.Complete, report.Violations)
+		}
+		if len(report.Nodes) != len(r.Members) {
+			t.Fatalf("visited %d nodes, want %d", len(report.Nodes), len(r.Members))
+		}
+	})
+
+	t.Run("wrong de Bruijn anchor", func(t *testing.T) {
+		// L'ancora di target viene sostituita con un membro vivo ma
+		// diverso da pred(k·target): il puntatore risponde, però è sbagliato.
+		target := r.Members[0]
+		anchor := target.Node.DeBruijnList()[0]
+		var wrong *domain.Node
+		for _, m := range r.Members {
+			if !m.Node.Self().ID.Equal(anchor.ID) {
+				wrong = m.Node.Self()
+				break
+			}
+		}
+		target.RT.SetDeBruijn(0, wrong)
+		defer target.RT.SetDeBruijn(0, anchor)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		report, err := client.CheckRing(ctx, r.Members[0].Addr)
+		if err != nil {
+			t.Fatalf("CheckRing: %v", err)
+		}
+		found := false
+		for _, v := range report.Violations {
+			if v.Kind == client.ViolationDeBruijn && v.Node.Addr == target.Addr && v.Related.Addr == wrong.Addr {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("wrong anchor of %s not reported: %v", target.Addr, report.Violations)
+		}
+	})
+
+	t.Run("broken predecessor", func(t *testing.T) {
+		// Un nodo inesistente appena dopo il predecessore reale diventa
+		// il nuovo predecessore di target: succ(pred(target)) != target.
+		target := r.Members[2]
+		pred := r.Members[1].Node.Self()
+		fakeID, err := r.Space.AddMod(pred.ID, r.Space.FromUint64(1))
+		if err != nil {
+			t.Fatalf("AddMod: %v", err)
+		}
+		if fakeID.Equal(target.Node.Self().ID) {
+			t.Skip("no room between predecessor and target")
+		}
+		target.Node.Notify(&domain.Node{ID: fakeID, Addr: "127.0.0.1:1"})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		report, err := client.CheckRing(ctx, r.Members[0].Addr)
+		if err != nil {
+			t.Fatalf("CheckRing: %v", err)
+		}
+		if report.OK() {
+			t.Fatal("expected violations, ring reported as consistent")
+		}
+		found := false
+		for _, v := range report.Violations {
+			if v.Kind == client.ViolationUnknownPredecessor && v.Node.Addr == target.Addr {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("broken predecessor of %s not reported: %v", target.Addr, report.Violations)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"sort"
+)
+
+// Shard is a segment [Start, End] of the identifier space assigned to a
+// ring member by ShardPlan.
+type Shard struct {
+	Index   int    `json:"index"`
+	Start   string `json:"start"`   // first identifier of the segment (inclusive)
+	End     string `json:"end"`     // last identifier of the segment (inclusive)
+	Owner   string `json:"owner"`   // address of the member owning Start
+	OwnerID string `json:"ownerId"` // ID of the member owning Start
+	// Exact reports that Owner owns the whole segment. Otherwise the
+	// segment crosses an ownership boundary and the keys past it belong
+	// to the following members: clients routing them to Owner rely on
+	// the node forwarding the request.
+	Exact bool `json:"exact"`
+}
+
+// Plan is a deterministic assignment of equal keyspace segments to the
+// ring members that own them, computed by ShardPlan.
+type Plan struct {
+	Bits int `json:"bits"` // size of the identifier space (2^Bits IDs)
+	// Version identifies the ring membership the plan was computed from:
+	// plans computed with the same parameters from the same members have
+	// the same Version, so a client can periodically recompute the plan
+	// and re-route its work only when the Version changes.
+	Version string  `json:"version"`
+	Shards  []Shard `json:"shards"` // sorted by Index
+}
+
+// ShardPlan splits the identifier space into shardCount segments of equal
+// size (the first ones one identifier larger if 2^bits is not a multiple
+// of shardCount) and assigns each of them to the member owning its first
+// identifier, according to the ownership map of the ring reached from
+// seed (see OwnershipMap, whose bits semantics it shares).
+//
+// Batch jobs can use the plan to send the work of a segment straight to
+// its owner instead of looking up every key. The plan is only as fresh as
+// the ring walk: Version changes whenever the membership does.
+//
+// An error is returned if shardCount is not in [1, 2^bits] or the ring
+// walk is incomplete (including when it is truncated by WithMaxRingWalk).
+func ShardPlan(ctx context.Context, seed string, bits, shardCount int, opts ...WalkOption) (*Plan, error) {
+	if shardCount < 1 {
+		return nil, fmt.Errorf("shard plan: invalid shard count %d", shardCount)
+	}
+	om, err := ownershipMap(ctx, seed, bits, false, opts)
+	if err != nil {
+		return nil, err
+	}
+	if om.Truncated {
+		return nil, fmt.Errorf("shard plan: ring walk from %s truncated, the ownership map is partial", seed)
+	}
+	space := new(big.Int).Lsh(big.NewInt(1), uint(om.Bits))
+	count := big.NewInt(int64(shardCount))
+	if count.Cmp(space) > 0 {
+		return nil, fmt.Errorf("shard plan: %d shards exceed the %d-bit identifier space", shardCount, om.Bits)
+	}
+
+	ends := make([]*big.Int, len(om.Intervals))
+	for i, iv := range om.Intervals {
+		if ends[i], err = parseHexID(iv.End); err != nil {
+			return nil, fmt.Errorf("shard plan: node %s: %w", iv.Owner, err)
+		}
+	}
+
+	plan := &Plan{Bits: om.Bits, Version: planVersion(om, shardCount)}
+	digits := 2 * ((om.Bits + 7) / 8)
+	for i := 0; i < shardCount; i++ {
+		start := segmentBound(i, space, count)
+		end := segmentBound(i+1, space, count)
+		end.Sub(end, big.NewInt(1))
+
+		// owner of start: first member with ID >= start, wrapping around
+		j := sort.Search(len(ends), func(k int) bool { return ends[k].Cmp(start) >= 0 })
+		exact := true
+		if j == len(ends) {
+			j = 0 // the segment lies past the last member: all of it wraps to the first
+		} else if len(ends) > 1 {
+			exact = ends[j].Cmp(end) >= 0
+		}
+		plan.Shards = append(plan.Shards, Shard{
+			Index:   i,
+			Start:   fmt.Sprintf("0x%0*x", digits, start),
+			End:     fmt.Sprintf("0x%0*x", digits, end),
+			Owner:   om.Intervals[j].Owner,
+			OwnerID: om.Intervals[j].End,
+			Exact:   exact,
+		})
+	}
+	return plan, nil
+}
+
+// ShardOf returns the index of the shard containing the identifier id
+// (hex, with or without the 0x prefix).
+func (p *Plan) ShardOf(id string) (int, error) {
+	v, err := parseHexID(id)
+	if err != nil {
+		return 0, err
+	}
+	space := new(big.Int).Lsh(big.NewInt(1), uint(p.Bits))
+	if v.Cmp(space) >= 0 {
+		return 0, fmt.Errorf("ID %s outside the %d-bit identifier space", id, p.Bits)
+	}
+	count := big.NewInt(int64(len(p.Shards)))
+	// segment i starts at ceil(i*space/count): the shard of v is the
+	// largest i with that bound <= v, i.e. floor(v*count / space)
+	i := new(big.Int).Mul(v, count)
+	i.Div(i, space)
+	return int(i.Int64()), nil
+}
+
+// segmentBound returns the first identifier of segment i, ceil(i*space/count).
+func segmentBound(i int, space, count *big.Int) *big.Int {
+	b := new(big.Int).Mul(big.NewInt(int64(i)), space)
+	b.Add(b, count).Sub(b, big.NewInt(1))
+	return b.Div(b, count)
+}
+
+// planVersion hashes the plan parameters and the ring membership (member
+// IDs and addresses, in ID order).
+func planVersion(om *Ownership, shardCount int) string {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d/%d", om.Bits, shardCount)
+	for _, iv := range om.Intervals {
+		_, _ = fmt.Fprintf(h, "|%s@%s", iv.End, iv.Owner)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
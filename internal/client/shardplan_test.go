@@ -0,0 +1,95 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestShardPlanCoversKeyspace(t *testing.T) {
+	const bits = 16
+	r := testring.New(t, 5, testring.WithSpace(bits, 2, 4))
+
+	space := new(big.Int).Lsh(big.NewInt(1), bits)
+	plan := func(shards int) *client.Plan {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		p, err := client.ShardPlan(ctx, r.Members[1].Addr, bits, shards)
+		if err != nil {
+			t.Fatalf("ShardPlan(%d): %v", shards, err)
+		}
+		return p
+	}
+	// ritorna il proprietario dell'ID esadecimale secondo l'anello di test
+	owner := func(hex string) *testring.Member {
+		t.Helper()
+		id, err := r.Space.FromHexString(hex)
+		if err != nil {
+			t.Fatalf("FromHexString(%s): %v", hex, err)
+		}
+		return r.Owner(id)
+	}
+
+	for _, shards := range []int{1, 3, 7, 64, 1000} {
+		p := plan(shards)
+		if len(p.Shards) != shards {
+			t.Fatalf("%d shards: got %d segments", shards, len(p.Shards))
+		}
+
+		// I segmenti devono essere contigui e coprire [0, 2^bits) senza buchi né sovrapposizioni
+		next := new(big.Int)
+		for i, s := range p.Shards {
+			start, _ := new(big.Int).SetString(s.Start[2:], 16)
+			end, _ := new(big.Int).SetString(s.End[2:], 16)
+			if s.Index != i || start.Cmp(next) != 0 || end.Cmp(start) < 0 {
+				t.Fatalf("%d shards: segment %d = [%s, %s], want start %#x", shards, i, s.Start, s.End, next)
+			}
+			next.Add(end, big.NewInt(1))
+
+			if want := owner(s.Start); s.Owner != want.Addr || s.OwnerID != want.Node.Self().ID.ToHexString(true) {
+				t.Errorf("%d shards: segment %d owned by %s, want %s", shards, i, s.Owner, want.Addr)
+			}
+			if s.Exact && owner(s.End).Addr != s.Owner {
+				t.Errorf("%d shards: segment %d marked exact but %s is owned by %s",
+					shards, i, s.End, owner(s.End).Addr)
+			}
+			for _, id := range []string{s.Start, s.End} {
+				if got, err := p.ShardOf(id); err != nil || got != i {
+					t.Errorf("%d shards: ShardOf(%s) = %d, %v; want %d", shards, id, got, err, i)
+				}
+			}
+		}
+		if next.Cmp(space) != 0 {
+			t.Fatalf("%d shards: segments end at %#x, want %#x", shards, next, space)
+		}
+	}
+
+	// La versione è stabile finché i membri non cambiano
+	before := plan(64)
+	if again := plan(64); again.Version != before.Version {
+		t.Fatalf("version changed without membership changes: %s -> %s", before.Version, again.Version)
+	}
+	if other := plan(32); other.Version == before.Version {
+		t.Fatalf("plans with different shard counts share version %s", before.Version)
+	}
+	r.Add()
+	r.WaitStable()
+	if after := plan(64); after.Version == before.Version {
+		t.Fatalf("version %s unchanged after a join", before.Version)
+	}
+}
+
+func TestShardPlanInvalidCount(t *testing.T) {
+	r := testring.New(t, 1, testring.WithSpace(4, 2, 4))
+	for _, shards := range []int{0, -1, 17} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if _, err := client.ShardPlan(ctx, r.Members[0].Addr, 4, shards); err == nil {
+			t.Errorf("ShardPlan(%d) succeeded, want error", shards)
+		}
+		cancel()
+	}
+}
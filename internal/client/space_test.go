@@ -0,0 +1,56 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetSpace(t *testing.T) {
+	r := testring.New(t, 3, testring.WithSpace(16, 4, 3))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	api, conn, sp, err := client.ConnectSpace(ctx, r.Members[1].Addr)
+	if err != nil {
+		t.Fatalf("ConnectSpace: %v", err)
+	}
+	defer conn.Close()
+	if sp.Bits != 16 || sp.GraphGrade != 4 || sp.SuccListSize != 3 || sp.Hash != domain.DefaultHash {
+		t.Errorf("ConnectSpace: got %+v, want 16 bits, degree 4, succ list 3, hash %s", sp, domain.DefaultHash)
+	}
+	// lo spazio ricevuto deve derivare gli stessi identificatori del nodo
+	if got, want := sp.NewIdFromString("key"), r.Space.NewIdFromString("key"); !got.Equal(want) {
+		t.Errorf("NewIdFromString: got %s, want %s", got.ToHexString(true), want.ToHexString(true))
+	}
+	if _, _, err := client.GetSpace(ctx, api); err != nil {
+		t.Errorf("GetSpace: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		want    domain.Space
+		wantErr error
+	}{
+		{name: "same space", want: r.Space},
+		{name: "only bits", want: domain.Space{Bits: 16}},
+		{name: "bits mismatch", want: domain.Space{Bits: 64}, wantErr: client.ErrSpaceMismatch},
+		{name: "degree mismatch", want: domain.Space{Bits: 16, GraphGrade: 2}, wantErr: client.ErrSpaceMismatch},
+		{name: "hash mismatch", want: domain.Space{Hash: "sha256"}, wantErr: client.ErrSpaceMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, conn, err := client.Connect(r.Members[0].Addr, client.WithExpectedSpace(tt.want))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Connect: got %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				conn.Close()
+			}
+		})
+	}
+}
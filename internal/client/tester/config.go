@@ -2,6 +2,7 @@ package tester
 
 import (
 	"KoordeDHT/internal/configloader"
+	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
 	"fmt"
 	"os"
@@ -18,7 +19,8 @@ type SimulationConfig struct {
 
 // DHTConfig defines the Koorde DHT keyspace parameters used by the tester.
 type DHTConfig struct {
-	IDBits int `yaml:"idBits"` // number of bits in the identifier space
+	IDBits int    `yaml:"idBits"` // number of bits in the identifier space (0 = discovered from the ring)
+	Hash   string `yaml:"hash"`   // hash function used to derive IDs (see domain.HashNames)
 }
 
 // DockerBootstrapConfig contains Docker-specific bootstrap parameters.
@@ -54,6 +56,12 @@ type QueryConfig struct {
 	Parallelism ParallelismConfig `yaml:"parallelism"` // worker concurrency
 }
 
+// MetricsConfig defines the optional Prometheus-style metrics endpoint.
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"` // listen address of the /metrics endpoint (e.g. ":9100")
+}
+
 // Config is the root configuration for the KoordeDHT tester client.
 type Config struct {
 	Logger     configloader.LoggerConfig `yaml:"logger"`
@@ -62,6 +70,7 @@ type Config struct {
 	Bootstrap  BootstrapConfig           `yaml:"bootstrap"`
 	CSV        CSVConfig                 `yaml:"csv"`
 	Query      QueryConfig               `yaml:"query"`
+	Metrics    MetricsConfig             `yaml:"metrics"`
 }
 
 // Load reads the configuration file and applies environment overrides.
@@ -89,6 +98,7 @@ func Load(path string) (*Config, error) {
 
 	configloader.OverrideDuration(&cfg.Simulation.Duration, "SIM_DURATION")
 	configloader.OverrideInt(&cfg.DHT.IDBits, "DHT_ID_BITS")
+	configloader.OverrideString(&cfg.DHT.Hash, "DHT_HASH")
 
 	configloader.OverrideString(&cfg.Bootstrap.Mode, "BOOTSTRAP_MODE")
 
@@ -100,6 +110,7 @@ func Load(path string) (*Config, error) {
 	configloader.OverrideString(&cfg.Bootstrap.Route53.DomainSuffix, "ROUTE53_DOMAIN_SUFFIX")
 	configloader.OverrideInt64(&cfg.Bootstrap.Route53.TTL, "ROUTE53_TTL")
 	configloader.OverrideString(&cfg.Bootstrap.Route53.Region, "ROUTE53_REGION")
+	configloader.OverrideDuration(&cfg.Bootstrap.Route53.RefreshInterval, "ROUTE53_REFRESH_INTERVAL")
 
 	configloader.OverrideBool(&cfg.CSV.Enabled, "CSV_ENABLED")
 	configloader.OverrideString(&cfg.CSV.Path, "CSV_PATH")
@@ -109,6 +120,9 @@ func Load(path string) (*Config, error) {
 	configloader.OverrideInt(&cfg.Query.Parallelism.MinWorkers, "QUERY_PARALLELISM_MIN")
 	configloader.OverrideInt(&cfg.Query.Parallelism.MaxWorkers, "QUERY_PARALLELISM_MAX")
 
+	configloader.OverrideBool(&cfg.Metrics.Enabled, "METRICS_ENABLED")
+	configloader.OverrideString(&cfg.Metrics.Addr, "METRICS_ADDR")
+
 	return cfg, nil
 }
 
@@ -133,8 +147,18 @@ func (c *Config) Validate() error {
 	}
 
 	// DHT
-	if c.DHT.IDBits <= 0 {
-		errs = append(errs, fmt.Sprintf("dht.idBits must be > 0 (got %d)", c.DHT.IDBits))
+	if c.DHT.IDBits < 0 {
+		errs = append(errs, fmt.Sprintf("dht.idBits must be >= 0 (got %d)", c.DHT.IDBits))
+	} else {
+		// with idBits 0 the space is discovered from the ring: only the hash
+		// name is checked here
+		bits := c.DHT.IDBits
+		if bits == 0 {
+			bits = 8
+		}
+		if _, err := domain.NewSpace(bits, 2, 2, domain.WithHash(c.DHT.Hash)); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid dht.hash: %v", err))
+		}
 	}
 
 	// Bootstrap
@@ -179,6 +203,11 @@ func (c *Config) Validate() error {
 			c.Query.Parallelism.MaxWorkers, c.Query.Parallelism.MinWorkers))
 	}
 
+	// Metrics
+	if c.Metrics.Enabled && c.Metrics.Addr == "" {
+		errs = append(errs, "metrics.addr must be set when metrics.enabled = true")
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("configuration errors:\n  - %s", strings.Join(errs, "\n  - "))
 	}
@@ -195,6 +224,7 @@ func (cfg *Config) LogConfig(lgr logger.Logger) {
 		logger.F("simulation.duration", cfg.Simulation.Duration.String()),
 
 		logger.F("dht.idBits", cfg.DHT.IDBits),
+		logger.F("dht.hash", cfg.DHT.Hash),
 
 		logger.F("bootstrap.mode", cfg.Bootstrap.Mode),
 		logger.F("bootstrap.docker.suffix", cfg.Bootstrap.Docker.ContainerSuffix),
@@ -207,5 +237,8 @@ func (cfg *Config) LogConfig(lgr logger.Logger) {
 		logger.F("query.rate", cfg.Query.Rate),
 		logger.F("query.parallelism.min", cfg.Query.Parallelism.MinWorkers),
 		logger.F("query.parallelism.max", cfg.Query.Parallelism.MaxWorkers),
+
+		logger.F("metrics.enabled", cfg.Metrics.Enabled),
+		logger.F("metrics.addr", cfg.Metrics.Addr),
 	)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"KoordeDHT/internal/bootstrap"
 	"KoordeDHT/internal/domain"
 
 	"github.com/docker/docker/api/types/container"
@@ -18,6 +19,18 @@ type DockerBootstrap struct {
 	Network string
 }
 
+// The docker mode takes its settings from the Extra field of the
+// bootstrap configuration, as a DockerBootstrapConfig.
+func init() {
+	bootstrap.Register("docker", func(cfg bootstrap.Config) (bootstrap.Bootstrap, error) {
+		d, ok := cfg.Extra.(DockerBootstrapConfig)
+		if !ok {
+			return nil, fmt.Errorf("docker: missing docker settings (got %T)", cfg.Extra)
+		}
+		return NewDockerBootstrap(d.ContainerSuffix, d.Port, d.Network), nil
+	})
+}
+
 // NewDockerBootstrap creates a Docker-based bootstrapper.
 func NewDockerBootstrap(suffix string, port int, network string) *DockerBootstrap {
 	return &DockerBootstrap{
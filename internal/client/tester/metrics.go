@@ -0,0 +1,32 @@
+package tester
+
+import "KoordeDHT/internal/telemetry/metrics"
+
+// Lookup outcomes, used as values of the "result" label.
+const (
+	ResultSuccess     = "success"
+	ResultNotFound    = "not_found"
+	ResultTimeout     = "timeout"
+	ResultUnavailable = "unavailable"
+	ResultError       = "error"
+)
+
+// Metrics are the scrapeable counters of a tester run. The request rate is
+// obtained from koorde_tester_requests_total (e.g. rate() in Prometheus).
+type Metrics struct {
+	Requests *metrics.CounterVec // lookups issued
+	Results  *metrics.CounterVec // lookups completed, by result
+	Latency  *metrics.Histogram  // lookup latency in seconds, every result but unavailable
+}
+
+// NewMetrics registers the tester metrics on reg.
+func NewMetrics(reg *metrics.Registry) *Metrics {
+	return &Metrics{
+		Requests: reg.NewCounterVec("koorde_tester_requests_total",
+			"Total number of lookups issued by the tester.", ""),
+		Results: reg.NewCounterVec("koorde_tester_results_total",
+			"Total number of completed lookups, by result.", "result"),
+		Latency: reg.NewHistogram("koorde_tester_lookup_duration_seconds",
+			"Latency of the lookups that reached a node.", nil),
+	}
+}
@@ -0,0 +1,82 @@
+package tester_test
+
+import (
+	"KoordeDHT/internal/client/tester"
+	"KoordeDHT/internal/client/tester/writer"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/testring"
+	"KoordeDHT/internal/telemetry/metrics"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// staticBootstrap restituisce sempre gli stessi indirizzi
+type staticBootstrap []string
+
+func (s staticBootstrap) Discover(context.Context) ([]string, error)   { return s, nil }
+func (staticBootstrap) Register(context.Context, *domain.Node) error   { return nil }
+func (staticBootstrap) Deregister(context.Context, *domain.Node) error { return nil }
+
+func TestMetricsCountLookups(t *testing.T) {
+	r := testring.New(t, 3)
+	var addrs staticBootstrap
+	for _, m := range r.Members {
+		addrs = append(addrs, m.Addr)
+	}
+
+	cfg := &tester.Config{
+		Simulation: tester.SimulationConfig{Duration: 500 * time.Millisecond},
+		Query: tester.QueryConfig{
+			Rate:        20,
+			Timeout:     time.Second,
+			Parallelism: tester.ParallelismConfig{MinWorkers: 1, MaxWorkers: 2},
+		},
+	}
+	reg := metrics.NewRegistry()
+	m := tester.NewMetrics(reg)
+	run := tester.New(cfg, &logger.NopLogger{}, writer.NopWriter{}, addrs, r.Space, tester.WithMetrics(m))
+	if err := run.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	requests := m.Requests.Value("")
+	if requests == 0 {
+		t.Fatal("no lookup counted")
+	}
+	// Tutti i nodi sono vivi: ogni lookup deve avere successo
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{name: "success", got: m.Results.Value(tester.ResultSuccess), want: requests},
+		{name: "timeout", got: m.Results.Value(tester.ResultTimeout), want: 0},
+		{name: "error", got: m.Results.Value(tester.ResultError), want: 0},
+		{name: "latency observations", got: float64(m.Latency.Count()), want: requests},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// L'endpoint espone i contatori nel formato testuale di Prometheus
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		"# TYPE koorde_tester_requests_total counter",
+		`koorde_tester_results_total{result="success"}`,
+		"# TYPE koorde_tester_lookup_duration_seconds histogram",
+		`koorde_tester_lookup_duration_seconds_bucket{le="+Inf"}`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("/metrics output lacks %q:\n%s", want, body)
+		}
+	}
+}
@@ -13,34 +13,60 @@ import (
 	"math/rand"
 	"sync"
 	"time"
-
-	"google.golang.org/grpc"
 )
 
+// connIdleTTL is how long the connection to a node is kept without lookups:
+// nodes that left the ring are dropped, the others are reused across waves.
+const connIdleTTL = time.Minute
+
 type Tester struct {
 	cfg     *Config
 	logger  logger.Logger
 	writer  writer.Writer
 	boot    bootstrap.Bootstrap
 	space   domain.Space
+	metrics *Metrics // nil = metrics disabled
 	started time.Time
+	checked bool // space checked against (or adopted from) the ring
+	conns   *client.Manager
+}
+
+type Option func(*Tester)
+
+// WithMetrics records the outcome and latency of every lookup in m.
+func WithMetrics(m *Metrics) Option {
+	return func(t *Tester) {
+		t.metrics = m
+	}
 }
 
-// New create a new Tester instance
-func New(cfg *Config, lgr logger.Logger, writer writer.Writer, boot bootstrap.Bootstrap, space domain.Space) *Tester {
-	return &Tester{
+// New create a new Tester instance. A space with zero Bits is discovered
+// from the ring before the first query wave; otherwise the space of the
+// ring is checked against it and Run fails on a mismatch.
+func New(cfg *Config, lgr logger.Logger, writer writer.Writer, boot bootstrap.Bootstrap, space domain.Space, opts ...Option) *Tester {
+	t := &Tester{
 		cfg:    cfg,
 		logger: lgr,
 		writer: writer,
 		space:  space,
 		boot:   boot,
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 // Run starts the tester for the configured duration or until the context is cancelled
 func (t *Tester) Run(ctx context.Context) error {
 	t.logger.Info("Tester started", logger.F("duration", t.cfg.Simulation.Duration))
 	t.started = time.Now()
+	t.conns = client.NewManager(connIdleTTL)
+	defer func() {
+		if err := t.conns.Close(); err != nil {
+			t.logger.Warn("failed to close connections", logger.F("err", err))
+		}
+	}()
 	endTime := t.started.Add(t.cfg.Simulation.Duration)
 	interval := time.Duration(float64(time.Second) / t.cfg.Query.Rate)
 
@@ -57,7 +83,9 @@ func (t *Tester) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
-			if err := t.runQueryWave(ctx); err != nil {
+			if err := t.runQueryWave(ctx); errors.Is(err, client.ErrSpaceMismatch) {
+				return err
+			} else if err != nil {
 				t.logger.Error("query wave failed", logger.F("err", err))
 			}
 		}
@@ -77,6 +105,9 @@ func (t *Tester) runQueryWave(ctx context.Context) error {
 		t.logger.Warn("no nodes discovered")
 		return nil
 	}
+	if err := t.resolveSpace(ctx, nodes); err != nil {
+		return err
+	}
 
 	// choise a random number of parallel workers between min and max
 	p := randomInt(t.cfg.Query.Parallelism.MinWorkers, t.cfg.Query.Parallelism.MaxWorkers)
@@ -104,6 +135,43 @@ func (t *Tester) runQueryWave(ctx context.Context) error {
 	return nil
 }
 
+// resolveSpace fetches the identifier space of the ring from the first of
+// nodes that answers, once: it is adopted if the tester has none, and
+// compared with the configured one (only idBits and hash, the fields the
+// tester uses to derive keys) otherwise.
+func (t *Tester) resolveSpace(ctx context.Context, nodes []string) error {
+	if t.checked {
+		return nil
+	}
+	want := domain.Space{Bits: t.space.Bits, Hash: t.space.Hash}
+	var lastErr error
+	for _, node := range nodes {
+		cctx, cancel := context.WithTimeout(ctx, t.cfg.Query.Timeout)
+		_, conn, sp, err := client.ConnectSpace(cctx, node, client.WithExpectedSpace(want))
+		cancel()
+		if errors.Is(err, client.ErrSpaceMismatch) {
+			return err
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		_ = conn.Close()
+		if t.space.Bits == 0 {
+			t.space = sp
+		}
+		t.checked = true
+		t.logger.Info("identifier space of the ring",
+			logger.F("idBits", sp.Bits),
+			logger.F("degree", sp.GraphGrade),
+			logger.F("hash", sp.Hash),
+			logger.F("node", node),
+		)
+		return nil
+	}
+	return fmt.Errorf("failed to get the identifier space: %w", lastErr)
+}
+
 // doLookup performs a single lookup operation on a random node
 func (t *Tester) doLookup(nodes []string) {
 	node := nodes[rand.Intn(len(nodes))]
@@ -116,23 +184,20 @@ func (t *Tester) doLookup(nodes []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), t.cfg.Query.Timeout)
 	defer cancel()
 
-	c, conn, err := client.Connect(node)
+	c, err := t.conns.API(node)
 	if err != nil {
 		t.logger.Warn("failed to connect to node", logger.F("node", node), logger.F("err", err))
 		return
 	}
-	defer func(conn *grpc.ClientConn) {
-		err := conn.Close()
-		if err != nil {
-			t.logger.Warn("failed to close connection", logger.F("node", node), logger.F("err", err))
-		}
-	}(conn)
 
+	t.recordRequest()
 	_, delay, err := client.Lookup(ctx, c, key)
 	var result string
 	if err != nil {
 		switch {
 		case errors.Is(err, client.ErrUnavailable):
+			t.recordResult(ResultUnavailable, delay)
+			t.conns.Forget(node)
 			// Node not reachable, skip writing to CSV
 			t.logger.Debug("node unavailable (skipping CSV)",
 				logger.F("node", node),
@@ -143,15 +208,19 @@ func (t *Tester) doLookup(nodes []string) {
 
 		case errors.Is(err, client.ErrDeadlineExceeded):
 			result = "TIMEOUT"
+			t.recordResult(ResultTimeout, delay)
 
 		case errors.Is(err, client.ErrNotFound):
 			result = "NOT_FOUND"
+			t.recordResult(ResultNotFound, delay)
 
 		default:
 			result = fmt.Sprintf("ERROR_%v", err)
+			t.recordResult(ResultError, delay)
 		}
 	} else {
 		result = "SUCCESS"
+		t.recordResult(ResultSuccess, delay)
 	}
 
 	// log the result
@@ -168,6 +237,25 @@ func (t *Tester) doLookup(nodes []string) {
 	}
 }
 
+// recordRequest counts an issued lookup, if metrics are enabled.
+func (t *Tester) recordRequest() {
+	if t.metrics != nil {
+		t.metrics.Requests.Inc("")
+	}
+}
+
+// recordResult counts a completed lookup and its latency, if metrics are
+// enabled. Unreachable nodes are counted but their latency is not.
+func (t *Tester) recordResult(result string, delay time.Duration) {
+	if t.metrics == nil {
+		return
+	}
+	t.metrics.Results.Inc(result)
+	if result != ResultUnavailable {
+		t.metrics.Latency.ObserveDuration(delay)
+	}
+}
+
 // randomInt returns a random integer between min and max (inclusive)
 func randomInt(min, max int) int {
 	if min >= max {
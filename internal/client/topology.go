@@ -0,0 +1,128 @@
+package client
+
+import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formats accepted by Topology.Write.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// TopologyPeer identifies a node referenced by a ring member.
+type TopologyPeer struct {
+	ID   string `json:"id"`
+	Addr string `json:"addr"`
+}
+
+// TopologyNode is a ring member as it sees itself.
+type TopologyNode struct {
+	ID          string         `json:"id"`
+	Addr        string         `json:"addr"`
+	Predecessor *TopologyPeer  `json:"predecessor"` // nil if the member has none
+	Successors  []TopologyPeer `json:"successors"`  // successor list, closest first
+	// Unreachable lists the entries of Successors that did not answer
+	// during the walk: a break of the ring at this member.
+	Unreachable []TopologyPeer `json:"unreachable,omitempty"`
+}
+
+// Topology is a snapshot of the whole ring, taken by a single walk.
+type Topology struct {
+	Seed  string         `json:"seed"`  // address the walk started from
+	Nodes []TopologyNode `json:"nodes"` // in walk order, from the seed, each member once
+	// Complete reports whether the successor walk returned to the seed;
+	// if not, the ring is broken after the last member of Nodes.
+	Complete bool `json:"complete"`
+	// Truncated reports that the walk hit the step limit (see
+	// WithMaxRingWalk): Nodes covers only the members visited so far.
+	Truncated bool `json:"truncated"`
+}
+
+// RingTopology walks the ring starting from seed, following the successor
+// lists of the routing tables of the members, and returns every member
+// found with its predecessor and successor list. Every member is listed
+// once, even if the walk meets it again. A successor that does not answer
+// is recorded in the Unreachable list of the member pointing to it and
+// skipped in favour of the next entry of the successor list; a walk that
+// cannot go on or does not return to the seed leaves Complete unset.
+//
+// An error is returned only if the seed cannot be queried.
+func RingTopology(ctx context.Context, seed string, opts ...WalkOption) (*Topology, error) {
+	report, _, err := walkRing(ctx, seed, newWalkOptions(opts).maxSteps)
+	if err != nil {
+		return nil, err
+	}
+	unreachable := make(map[string][]TopologyPeer) // key: node ID
+	for _, v := range report.Violations {
+		if v.Kind == ViolationUnreachable {
+			unreachable[v.Node.Id] = append(unreachable[v.Node.Id], topologyPeer(v.Related))
+		}
+	}
+
+	topo := &Topology{Seed: seed, Complete: report.Complete, Truncated: report.Truncated}
+	for _, rt := range report.Nodes {
+		nd := TopologyNode{
+			ID:          rt.Self.Id,
+			Addr:        rt.Self.Addr,
+			Successors:  make([]TopologyPeer, 0, len(rt.Successors)),
+			Unreachable: unreachable[rt.Self.Id],
+		}
+		if rt.Predecessor != nil {
+			p := topologyPeer(rt.Predecessor)
+			nd.Predecessor = &p
+		}
+		for _, s := range rt.Successors {
+			nd.Successors = append(nd.Successors, topologyPeer(s))
+		}
+		topo.Nodes = append(topo.Nodes, nd)
+	}
+	return topo, nil
+}
+
+func topologyPeer(n *clientv1.NodeInfo) TopologyPeer {
+	return TopologyPeer{ID: n.Id, Addr: n.Addr}
+}
+
+// Write encodes the topology to w in the given format:
+//
+//   - FormatJSON: the Topology itself, indented;
+//   - FormatCSV: a header and one row per member with the columns id, addr,
+//     predecessor_id, predecessor_addr, successors and unreachable, the last
+//     two being space-separated lists of addresses.
+func (t *Topology) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t)
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"id", "addr", "predecessor_id", "predecessor_addr", "successors", "unreachable"})
+		for _, nd := range t.Nodes {
+			var predID, predAddr string
+			if nd.Predecessor != nil {
+				predID, predAddr = nd.Predecessor.ID, nd.Predecessor.Addr
+			}
+			_ = cw.Write([]string{nd.ID, nd.Addr, predID, predAddr, peerAddrs(nd.Successors), peerAddrs(nd.Unreachable)})
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("topology: unknown format %q (want %s or %s)", format, FormatCSV, FormatJSON)
+	}
+}
+
+func peerAddrs(peers []TopologyPeer) string {
+	addrs := make([]string, len(peers))
+	for i, p := range peers {
+		addrs[i] = p.Addr
+	}
+	return strings.Join(addrs, " ")
+}
@@ -0,0 +1,107 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/testring"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRingTopology(t *testing.T) {
+	r := testring.New(t, 4)
+	r.StopStabilizers()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	topo, err := client.RingTopology(ctx, r.Members[1].Addr)
+	if err != nil {
+		t.Fatalf("RingTopology: %v", err)
+	}
+	if !topo.Complete || len(topo.Nodes) != len(r.Members) {
+		t.Fatalf("got complete=%v with %d nodes, want a complete walk of %d", topo.Complete, len(topo.Nodes), len(r.Members))
+	}
+	// la visita parte dal seed e segue i successori, ordinati per ID
+	for i, nd := range topo.Nodes {
+		m := r.Members[(i+1)%len(r.Members)]
+		pred := r.Members[i%len(r.Members)]
+		if nd.Addr != m.Addr || nd.ID != m.Node.Self().ID.ToHexString(true) {
+			t.Errorf("node %d is %s(%s), want %s", i, nd.ID, nd.Addr, m.Addr)
+		}
+		if nd.Predecessor == nil || nd.Predecessor.Addr != pred.Addr {
+			t.Errorf("node %s: predecessor %v, want %s", nd.Addr, nd.Predecessor, pred.Addr)
+		}
+		if len(nd.Successors) == 0 || len(nd.Unreachable) != 0 {
+			t.Errorf("node %s: %d successors, %d unreachable", nd.Addr, len(nd.Successors), len(nd.Unreachable))
+		}
+	}
+
+	tests := []struct {
+		format string
+		check  func(t *testing.T, out []byte)
+	}{
+		{format: client.FormatJSON, check: func(t *testing.T, out []byte) {
+			var got client.Topology
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if len(got.Nodes) != len(topo.Nodes) || !got.Complete || got.Seed != r.Members[1].Addr {
+				t.Errorf("decoded %d nodes, complete=%v, seed=%s", len(got.Nodes), got.Complete, got.Seed)
+			}
+		}},
+		{format: client.FormatCSV, check: func(t *testing.T, out []byte) {
+			rows, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll: %v", err)
+			}
+			if len(rows) != len(topo.Nodes)+1 {
+				t.Fatalf("got %d rows, want header and %d nodes", len(rows), len(topo.Nodes))
+			}
+			for i, nd := range topo.Nodes {
+				row := rows[i+1]
+				if row[1] != nd.Addr || row[3] != nd.Predecessor.Addr || !strings.HasPrefix(row[4], nd.Successors[0].Addr) {
+					t.Errorf("row %d = %v, want node %s", i+1, row, nd.Addr)
+				}
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := topo.Write(&buf, tt.format); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			tt.check(t, buf.Bytes())
+		})
+	}
+	if err := topo.Write(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("Write with an unknown format: got nil error")
+	}
+
+	t.Run("ring break", func(t *testing.T) {
+		// senza stabilizzazione il predecessore del nodo morto lo elenca
+		// ancora come successore: la visita lo salta e segnala la rottura
+		dead := r.Members[2]
+		r.Kill(dead)
+		topo, err := client.RingTopology(ctx, r.Members[0].Addr)
+		if err != nil {
+			t.Fatalf("RingTopology: %v", err)
+		}
+		if !topo.Complete || len(topo.Nodes) != len(r.Members)-1 {
+			t.Fatalf("got complete=%v with %d nodes, want a complete walk of %d", topo.Complete, len(topo.Nodes), len(r.Members)-1)
+		}
+		for _, nd := range topo.Nodes {
+			if nd.Addr == dead.Addr {
+				t.Errorf("dead node %s listed as a member", dead.Addr)
+			}
+			broken := len(nd.Unreachable) == 1 && nd.Unreachable[0].Addr == dead.Addr
+			if want := nd.Addr == r.Members[1].Addr; broken != want {
+				t.Errorf("node %s: unreachable %v, want break %v", nd.Addr, nd.Unreachable, want)
+			}
+		}
+	})
+}
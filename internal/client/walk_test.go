@@ -0,0 +1,92 @@
+package client_test
+
+import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
+	"KoordeDHT/internal/client"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// endlessRing simula un anello rotto: ogni richiesta risponde con un nodo
+// nuovo il cui successore ha un ID mai visto, per cui la rilevazione dei
+// cicli non scatta mai e la visita non torna al seed.
+type endlessRing struct {
+	clientv1.UnimplementedClientAPIServer
+	addr string
+	mu   sync.Mutex
+	next uint64
+}
+
+func (e *endlessRing) GetRoutingTable(context.Context, *emptypb.Empty) (*clientv1.GetRoutingTableResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	id := e.next
+	e.next++
+	return &clientv1.GetRoutingTableResponse{
+		Self:        &clientv1.NodeInfo{Id: fmt.Sprintf("0x%016x", id), Addr: e.addr},
+		Predecessor: &clientv1.NodeInfo{Id: fmt.Sprintf("0x%016x", id-1), Addr: e.addr},
+		Successors:  []*clientv1.NodeInfo{{Id: fmt.Sprintf("0x%016x", id+1), Addr: e.addr}},
+	}, nil
+}
+
+func TestRingWalkStopsAtCap(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	clientv1.RegisterClientAPIServer(srv, &endlessRing{addr: lis.Addr().String()})
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	const maxWalk = 16
+	tests := []struct {
+		name string
+		walk func(ctx context.Context) (visited int, truncated bool, err error)
+	}{
+		{
+			name: "CheckRing",
+			walk: func(ctx context.Context) (int, bool, error) {
+				report, err := client.CheckRing(ctx, lis.Addr().String(), client.WithMaxRingWalk(maxWalk))
+				if err != nil {
+					return 0, false, err
+				}
+				return len(report.Nodes), report.Truncated, nil
+			},
+		},
+		{
+			name: "OwnershipMap",
+			walk: func(ctx context.Context) (int, bool, error) {
+				om, err := client.OwnershipMap(ctx, lis.Addr().String(), 64, client.WithMaxRingWalk(maxWalk))
+				if err != nil {
+					return 0, false, err
+				}
+				return len(om.Intervals), om.Truncated, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			visited, truncated, err := tt.walk(ctx)
+			if err != nil {
+				t.Fatalf("walk: %v", err)
+			}
+			if !truncated {
+				t.Errorf("walk of a broken ring not marked as truncated")
+			}
+			// il seed più un nodo per ogni passo
+			if visited != maxWalk+1 {
+				t.Errorf("visited %d nodes, want %d", visited, maxWalk+1)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package configloader
 
+import "time"
+
 type FileLoggerConfig struct {
 	Path       string `yaml:"path"`
 	MaxSize    int    `yaml:"maxSize"`
@@ -9,22 +11,58 @@ type FileLoggerConfig struct {
 }
 
 type LoggerConfig struct {
-	Active   bool             `yaml:"active"`
-	Level    string           `yaml:"level"`
-	Encoding string           `yaml:"encoding"`
-	Mode     string           `yaml:"mode"`
-	File     FileLoggerConfig `yaml:"file"`
+	Active    bool             `yaml:"active"`
+	Level     string           `yaml:"level"`
+	Encoding  string           `yaml:"encoding"`
+	Mode      string           `yaml:"mode"`
+	File      FileLoggerConfig `yaml:"file"`
+	AccessLog bool             `yaml:"accessLog"`
 }
 
 type Route53Config struct {
-	HostedZoneID string `yaml:"hostedZoneId"`
-	DomainSuffix string `yaml:"domainSuffix"`
-	TTL          int64  `yaml:"ttl"`
-	Region       string `yaml:"region"`
+	HostedZoneID    string        `yaml:"hostedZoneId"`
+	DomainSuffix    string        `yaml:"domainSuffix"`
+	TTL             int64         `yaml:"ttl"`
+	Region          string        `yaml:"region"`
+	RefreshInterval time.Duration `yaml:"refreshInterval"`
+}
+
+type MDNSConfig struct {
+	Service   string        `yaml:"service"`
+	Interface string        `yaml:"interface"`
+	Timeout   time.Duration `yaml:"timeout"`
+}
+
+type K8sConfig struct {
+	ServiceName   string `yaml:"serviceName"`
+	Namespace     string `yaml:"namespace"`
+	PortName      string `yaml:"portName"`
+	ClusterDomain string `yaml:"clusterDomain"`
+	DNSServer     string `yaml:"dnsServer"`
+}
+
+type EtcdConfig struct {
+	Endpoints []string      `yaml:"endpoints"`
+	Prefix    string        `yaml:"prefix"`
+	LeaseTTL  time.Duration `yaml:"leaseTTL"`
+}
+
+type BootstrapRetryConfig struct {
+	Attempts int           `yaml:"attempts"`
+	Timeout  time.Duration `yaml:"timeout"`
+	Backoff  time.Duration `yaml:"backoff"`
 }
 
 type BootstrapConfig struct {
-	Mode    string        `yaml:"mode"`
-	Peers   []string      `yaml:"peers"`
-	Route53 Route53Config `yaml:"route53"`
+	Mode                string               `yaml:"mode"`
+	Peers               []string             `yaml:"peers"`
+	Route53             Route53Config        `yaml:"route53"`
+	MDNS                MDNSConfig           `yaml:"mdns"`
+	K8s                 K8sConfig            `yaml:"k8s"`
+	Etcd                EtcdConfig           `yaml:"etcd"`
+	RequireRegistration bool                 `yaml:"requireRegistration"`
+	GateUntilRegistered bool                 `yaml:"gateUntilRegistered"`
+	Retry               BootstrapRetryConfig `yaml:"retry"`
+	JoinRetries         int                  `yaml:"joinRetries"`
+	JoinRetryInterval   time.Duration        `yaml:"joinRetryInterval"`
 }
@@ -1,20 +1,99 @@
 package configloader
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
-// LoadYAML reads a YAML file into the given struct pointer.
-func LoadYAML(path string, out any) error {
-	data, err := os.ReadFile(path)
+// FetchTimeout bounds the download of a configuration served over HTTP(S).
+var FetchTimeout = 10 * time.Second
+
+// maxRemoteSize caps the size of a configuration downloaded over HTTP(S).
+const maxRemoteSize = 1 << 20
+
+// LoadYAML reads a YAML configuration into the given struct pointer.
+//
+// The location is either a bare filesystem path (e.g. a Kubernetes
+// ConfigMap mounted at /etc/koorde/config.yaml) or a URI:
+//   - file:///etc/koorde/config.yaml reads a local file;
+//   - http:// and https:// URLs are fetched with a GET bounded by
+//     FetchTimeout; any status other than 200 is an error.
+//
+// Other schemes are rejected.
+func LoadYAML(location string, out any) error {
+	data, err := readSource(location)
 	if err != nil {
-		return fmt.Errorf("failed to read config file %s: %w", path, err)
+		return err
 	}
 	if err := yaml.Unmarshal(data, out); err != nil {
-		return fmt.Errorf("failed to parse yaml: %w", err)
+		return fmt.Errorf("failed to parse yaml from %s: %w", location, err)
 	}
 	return nil
 }
+
+// readSource returns the raw contents of a configuration location (see
+// LoadYAML for the supported forms).
+func readSource(location string) ([]byte, error) {
+	if !strings.Contains(location, "://") {
+		return readFile(location)
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config location %q: %w", location, err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return nil, fmt.Errorf("invalid config location %q: file URIs must not name a remote host", location)
+		}
+		return readFile(u.Path)
+	case "http", "https":
+		return fetch(u)
+	default:
+		return nil, fmt.Errorf("unsupported config location scheme %q (must be file, http or https)", u.Scheme)
+	}
+}
+
+func readFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// fetch downloads a configuration over HTTP(S). Errors mention the URL
+// with its password redacted.
+func fetch(u *url.URL) ([]byte, error) {
+	location := u.Redacted()
+	ctx, cancel := context.WithTimeout(context.Background(), FetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config URL %s: %w", location, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config from %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch config from %s: unexpected status %s", location, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config from %s: %w", location, err)
+	}
+	if len(data) > maxRemoteSize {
+		return nil, fmt.Errorf("config at %s exceeds %d bytes", location, maxRemoteSize)
+	}
+	return data, nil
+}
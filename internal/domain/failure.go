@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// FailureStage identifies the phase of a DHT operation that failed.
+type FailureStage string
+
+const (
+	StageRouting  FailureStage = "routing"  // locating the node responsible for the key
+	StageTransfer FailureStage = "transfer" // contacting the responsible node
+	StageStorage  FailureStage = "storage"  // accessing the store of the responsible node
+)
+
+// FailureDomain is the ErrorInfo domain of the failures reported by
+// Koorde nodes.
+const FailureDomain = "koorde.dht"
+
+// CauseMaxHops is the cause of the failure of a lookup aborted by a node
+// on its path because it made more forwarding hops than the limit of that
+// node: the nodes along the path stop trying other candidates and unwind.
+const CauseMaxHops = "maxHops"
+
+// Failure is an error of a DHT operation annotated with the stage at which
+// it failed. It travels between nodes and to clients as a
+// google.rpc.ErrorInfo detail of the gRPC status (see ErrorInfo and
+// FailureFromError).
+type Failure struct {
+	Stage     FailureStage
+	Cause     string // specific cause the callers act upon ("" if none, see CauseMaxHops)
+	LastHop   string // address of the last node that handled the request ("" if unknown)
+	Retryable bool   // whether retrying the operation later may succeed
+	Err       error  // underlying error
+}
+
+func (f *Failure) Error() string {
+	retry := "not retryable"
+	if f.Retryable {
+		retry = "retryable"
+	}
+	return fmt.Sprintf("%s failure after %s (%s): %v", f.Stage, f.LastHop, retry, f.Err)
+}
+
+func (f *Failure) Unwrap() error { return f.Err }
+
+// ErrorInfo encodes the failure as a google.rpc.ErrorInfo status detail.
+func (f *Failure) ErrorInfo() *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{
+		Reason: strings.ToUpper(string(f.Stage)) + "_FAILED",
+		Domain: FailureDomain,
+		Metadata: map[string]string{
+			"stage":     string(f.Stage),
+			"cause":     f.Cause,
+			"lastHop":   f.LastHop,
+			"retryable": strconv.FormatBool(f.Retryable),
+		},
+	}
+}
+
+// FailureFromError decodes the Failure attached as ErrorInfo detail to the
+// gRPC status carried by err. The returned Failure wraps err.
+func FailureFromError(err error) (*Failure, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st == nil {
+		return nil, false
+	}
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != FailureDomain {
+			continue
+		}
+		md := info.GetMetadata()
+		retryable, _ := strconv.ParseBool(md["retryable"])
+		return &Failure{
+			Stage:     FailureStage(md["stage"]),
+			Cause:     md["cause"],
+			LastHop:   md["lastHop"],
+			Retryable: retryable,
+			Err:       err,
+		}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"testing"
+)
+
+func TestFromBytes(t *testing.T) {
+	sp, err := NewSpace(66, 2, 4, WithHash("sha256"))
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	digest := sha256.Sum256([]byte("content"))
+
+	tests := []struct {
+		name    string
+		in      []byte
+		want    string
+		wantErr bool
+	}{
+		// i byte più significativi del digest, con i 6 bit alti mascherati
+		{name: "truncated digest", in: []byte{0xff, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x11}, want: "0x03123456789abcdef0"},
+		{name: "exact length", in: []byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}, want: "0x020000000000000001"},
+		{name: "short input padded", in: []byte{0x12, 0x34}, want: "0x000000000000001234"},
+		{name: "empty", in: nil, wantErr: true},
+		{name: "sha256 digest", in: digest[:], want: sp.NewIdFromString("content").ToHexString(true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := sp.FromBytes(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromBytes(%x): err = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if err := sp.IsValidID(id); err != nil {
+				t.Fatalf("FromBytes(%x) is not valid: %v", tt.in, err)
+			}
+			want, err := sp.FromHexString(tt.want)
+			if err != nil {
+				t.Fatalf("FromHexString(%s): %v", tt.want, err)
+			}
+			if !id.Equal(want) {
+				t.Errorf("FromBytes(%x) = %s, want %s", tt.in, id.ToHexString(true), tt.want)
+			}
+		})
+	}
+
+	// NewIdFromBytes e NewIdFromString coincidono, anche con un namespace
+	ns := sp.WithNamespace("tenant")
+	if a, b := ns.NewIdFromBytes([]byte("key")), ns.NewIdFromString("key"); !a.Equal(b) {
+		t.Errorf("NewIdFromBytes = %s, NewIdFromString = %s", a.ToHexString(true), b.ToHexString(true))
+	}
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultHash is the hash function used to derive identifiers when none
+// is configured.
+const DefaultHash = "sha1"
+
+// HashFunc computes the digest of its input.
+type HashFunc func([]byte) []byte
+
+var (
+	hashMu sync.RWMutex
+	hashes = map[string]HashFunc{
+		"sha1":     func(b []byte) []byte { h := sha1.Sum(b); return h[:] },
+		"sha256":   func(b []byte) []byte { h := sha256.Sum256(b); return h[:] },
+		"sha512":   func(b []byte) []byte { h := sha512.Sum512(b); return h[:] },
+		"sha3-256": func(b []byte) []byte { h := sha3.Sum256(b); return h[:] },
+		"sha3-512": func(b []byte) []byte { h := sha3.Sum512(b); return h[:] },
+	}
+)
+
+// RegisterHash makes a custom hash function available under name, so that
+// it can be selected with WithHash (e.g. from configuration). Registering
+// an existing name replaces it. All nodes of a DHT must use the same hash.
+func RegisterHash(name string, fn HashFunc) {
+	hashMu.Lock()
+	defer hashMu.Unlock()
+	hashes[name] = fn
+}
+
+// HashNames returns the names of the available hash functions, sorted.
+func HashNames() []string {
+	hashMu.RLock()
+	defer hashMu.RUnlock()
+	names := make([]string, 0, len(hashes))
+	for n := range hashes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupHash returns the hash function registered under name ("" = DefaultHash).
+func lookupHash(name string) (HashFunc, error) {
+	if name == "" {
+		name = DefaultHash
+	}
+	hashMu.RLock()
+	fn, ok := hashes[name]
+	hashMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown hash function %q (available: %v)", name, HashNames())
+	}
+	return fn, nil
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSpaceHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		bits    int
+		hash    string
+		wantErr bool
+	}{
+		{name: "default is sha1", bits: 160, hash: ""},
+		{name: "sha1 too short", bits: 161, hash: "sha1", wantErr: true},
+		{name: "sha256", bits: 256, hash: "sha256"},
+		{name: "sha256 too short", bits: 257, hash: "sha256", wantErr: true},
+		{name: "sha512", bits: 512, hash: "sha512"},
+		{name: "sha3-256", bits: 200, hash: "sha3-256"},
+		{name: "unknown", bits: 64, hash: "md4", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, err := NewSpace(tt.bits, 2, 4, WithHash(tt.hash))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSpace(%d, %q): err = %v, wantErr %v", tt.bits, tt.hash, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			id := sp.NewIdFromString("key")
+			if err := sp.IsValidID(id); err != nil {
+				t.Fatalf("derived ID is not valid: %v", err)
+			}
+			// Gli ultimi byte dell'ID provengono dal digest, non dal padding
+			if tail := id[len(id)-4:]; bytes.Equal(tail, make([]byte, 4)) {
+				t.Errorf("ID %s has a zero tail, digest does not cover the space", id.ToHexString(true))
+			}
+		})
+	}
+
+	// Le configurazioni esistenti (sha1 implicito) producono gli stessi ID
+	plain, _ := NewSpace(64, 2, 4)
+	sha1Sp, _ := NewSpace(64, 2, 4, WithHash("sha1"))
+	sha256Sp, _ := NewSpace(64, 2, 4, WithHash("sha256"))
+	if !plain.NewIdFromString("key").Equal(sha1Sp.NewIdFromString("key")) {
+		t.Error("default hash differs from explicit sha1")
+	}
+	if plain.NewIdFromString("key").Equal(sha256Sp.NewIdFromString("key")) {
+		t.Error("sha256 produced the same ID as sha1")
+	}
+}
@@ -2,7 +2,6 @@ package domain
 
 import (
 	"bytes"
-	"crypto/sha1"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -49,10 +48,25 @@ var (
 // parameters, allowing consistent reasoning about identifiers,
 // encoding, and routing properties.
 type Space struct {
-	Bits         int // Number of bits in the identifier space
-	ByteLen      int // Number of bytes needed to represent an identifier
-	GraphGrade   int // Base k of the de Bruijn graph (must be a power of 2)
-	SuccListSize int // Length of the successor list for fault tolerance
+	Bits         int    // Number of bits in the identifier space
+	ByteLen      int    // Number of bytes needed to represent an identifier
+	GraphGrade   int    // Base k of the de Bruijn graph (must be a power of 2)
+	SuccListSize int    // Length of the successor list for fault tolerance
+	Namespace    string // Mixed into every derived identifier ("" = no namespace)
+	Hash         string // Hash function used to derive identifiers ("" = DefaultHash)
+}
+
+// SpaceOption customizes the Space built by NewSpace.
+type SpaceOption func(*Space)
+
+// WithHash selects the hash function used by NewIdFromString (see
+// HashNames for the available ones; default DefaultHash). Its digest must
+// be at least as long as an identifier: e.g. sha1 (160 bits) is too short
+// for an identifier space of more than 160 bits, use sha256 or sha512.
+func WithHash(name string) SpaceOption {
+	return func(sp *Space) {
+		sp.Hash = name
+	}
 }
 
 // NewSpace initializes a new identifier space for the Koorde DHT.
@@ -63,11 +77,13 @@ type Space struct {
 //     Must be >= 2 and preferably a power of 2.
 //   - succListSize: number of successors to maintain for fault tolerance.
 //     Must be > 0 (commonly O(log n)).
+//   - opts: optional settings (e.g. WithHash).
 //
 // Returns:
 //   - Space: a fully initialized Space instance with derived parameters.
-//   - error: if one or more input parameters are invalid.
-func NewSpace(b int, degree int, succListSize int) (Space, error) {
+//   - error: if one or more input parameters are invalid, the hash function
+//     is unknown, or its digest is shorter than an identifier.
+func NewSpace(b int, degree int, succListSize int, opts ...SpaceOption) (Space, error) {
 	if b <= 0 {
 		return Space{}, fmt.Errorf("invalid identifier bits: %d (must be > 0)", b)
 	}
@@ -80,12 +96,40 @@ func NewSpace(b int, degree int, succListSize int) (Space, error) {
 	if succListSize <= 0 {
 		return Space{}, fmt.Errorf("invalid successor list size: %d (must be > 0)", succListSize)
 	}
-	return Space{
+	sp := Space{
 		Bits:         b,
 		ByteLen:      (b + 7) / 8,
 		GraphGrade:   degree,
 		SuccListSize: succListSize,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(&sp)
+	}
+	hash, err := lookupHash(sp.Hash)
+	if err != nil {
+		return Space{}, err
+	}
+	if n := len(hash(nil)); n < sp.ByteLen {
+		return Space{}, fmt.Errorf("hash function %q produces %d bits, fewer than the %d identifier bits", sp.hashName(), n*8, b)
+	}
+	return sp, nil
+}
+
+// hashName returns the name of the configured hash function.
+func (sp Space) hashName() string {
+	if sp.Hash == "" {
+		return DefaultHash
+	}
+	return sp.Hash
+}
+
+// WithNamespace returns a copy of the space whose derived identifiers
+// (see NewIdFromString) are scoped to the given namespace. Separate DHTs
+// sharing the same infrastructure use different namespaces so that the
+// same node address or key maps to unrelated identifiers in each of them.
+func (sp Space) WithNamespace(ns string) Space {
+	sp.Namespace = ns
+	return sp
 }
 
 // -------------------------------
@@ -114,31 +158,73 @@ func (sp Space) Zero() ID {
 // or resource keys.
 //
 // The ID is produced as follows:
-//  1. Compute the SHA-1 digest (160 bits) of the input string.
+//  1. Compute the digest of the input string with the configured hash
+//     function (SHA-1 by default), prefixed with the namespace and a NUL
+//     separator if a namespace is set.
 //  2. Copy the most significant bytes (big-endian order) into a buffer
 //     of length sp.ByteLen.
 //  3. If Bits is not a multiple of 8, mask the unused high-order bits
 //     in the first byte so that the ID falls strictly within the range
 //     [0, 2^Bits - 1].
 //
+// Steps 2 and 3 are those of FromBytes.
+//
 // This ensures the generated ID is uniformly distributed and valid
 // for the configured identifier space.
 func (sp Space) NewIdFromString(s string) ID {
-	// SHA-1 digest of the input
-	h := sha1.Sum([]byte(s)) // returns [20]byte (160 bits)
+	return sp.NewIdFromBytes([]byte(s))
+}
+
+// NewIdFromBytes derives a new identifier from arbitrary bytes, exactly
+// as NewIdFromString does from a string: the digest of b (with the
+// namespace prefix, if any) truncated into the space by FromBytes.
+func (sp Space) NewIdFromBytes(b []byte) ID {
+	input := b
+	if sp.Namespace != "" {
+		input = append([]byte(sp.Namespace+"\x00"), b...)
+	}
+	hash, err := lookupHash(sp.Hash)
+	if err != nil {
+		panic(fmt.Sprintf("domain: %v", err)) // NewSpace rejects unknown hash functions
+	}
+	id, err := sp.FromBytes(hash(input))
+	if err != nil {
+		panic(fmt.Sprintf("domain: %v", err)) // hash functions never return an empty digest
+	}
+	return id
+}
 
-	// allocate buffer of correct length and copy MSBs
-	buf := make([]byte, sp.ByteLen)
-	copy(buf, h[:sp.ByteLen])
+// FromBytes maps a raw digest (e.g. a content hash computed elsewhere)
+// into the identifier space, so that systems that already address their
+// data by hash can use it directly as a key ID. With a digest of the
+// configured hash function, FromBytes(digest) == NewIdFromString(input)
+// when no namespace is set.
+//
+// Rules:
+//   - If the digest has more bytes than ByteLen, only its most
+//     significant ByteLen bytes are kept (truncation, as for hashes).
+//   - If shorter, it's left-padded with zeros, as FromHexString.
+//   - If Bits is not a multiple of 8, the unused high-order bits in the
+//     first byte are masked to zero (unlike FromHexString, which rejects
+//     them).
+//   - Empty input: error.
+func (sp Space) FromBytes(b []byte) (ID, error) {
+	if len(b) == 0 {
+		return nil, fmt.Errorf("invalid digest: empty input")
+	}
+	id := make(ID, sp.ByteLen)
+	if len(b) >= sp.ByteLen {
+		copy(id, b[:sp.ByteLen])
+	} else {
+		copy(id[sp.ByteLen-len(b):], b)
+	}
 
 	// mask unused bits if identifier length is not byte-aligned
 	extraBits := sp.ByteLen*8 - sp.Bits
 	if extraBits > 0 {
-		mask := byte(0xFF >> extraBits)
-		buf[0] &= mask // apply mask on the first (most significant) byte
+		id[0] &= byte(0xFF >> extraBits)
 	}
-
-	return buf
+	return id, nil
 }
 
 // IsValidID verifies whether the given byte slice represents
@@ -370,28 +456,54 @@ func (x ID) Between(a, b ID) bool {
 //   - If Bits is not a multiple of 8, the unused high-order bits
 //     of the most significant byte are masked to zero.
 //
-// Panics if the length of `a` is not sp.ByteLen.
+// The result is a newly allocated ID; see MulKModInto for the
+// allocation-free variant.
 func (sp Space) MulKMod(a ID) (ID, error) {
-	if err := sp.IsValidID(a); err != nil {
+	res := make(ID, sp.ByteLen)
+	if err := sp.MulKModInto(res, a); err != nil {
 		return nil, err
 	}
-	res := make(ID, sp.ByteLen)
-	carry := uint64(0)
+	return res, nil
+}
+
+// MulKModInto computes (GraphGrade * a) modulo 2^Bits and stores the
+// result in dst, which must have length sp.ByteLen. dst may alias a.
+//
+// Returns an error if a is not a valid ID or dst has the wrong length.
+func (sp Space) MulKModInto(dst, a ID) error {
+	return sp.MulKAddModInto(dst, a, 0)
+}
+
+// MulKAddModInto computes (GraphGrade * a + digit) modulo 2^Bits in a
+// single pass and stores the result in dst (length sp.ByteLen, may alias a).
+//
+// This is the step nextI = k*currentI + digit of the Koorde routing
+// algorithm; compared to MulKMod followed by AddMod(FromUint64(digit))
+// it performs no allocations. digit is expected to be a base-k digit
+// (digit < GraphGrade); larger values are still added modulo 2^Bits.
+func (sp Space) MulKAddModInto(dst, a ID, digit uint64) error {
+	if err := sp.IsValidID(a); err != nil {
+		return err
+	}
+	if len(dst) != sp.ByteLen {
+		return fmt.Errorf("invalid destination length: %d (want %d)", len(dst), sp.ByteLen)
+	}
+	carry := digit
 	k := uint64(sp.GraphGrade)
 	// Multiply each byte (big-endian order)
 	for i := sp.ByteLen - 1; i >= 0; i-- {
 		prod := uint64(a[i])*k + carry
-		res[i] = byte(prod & 0xFF)
+		dst[i] = byte(prod & 0xFF)
 		carry = prod >> 8
 	}
 	// Apply mask if identifier size is not byte-aligned
 	extraBits := sp.ByteLen*8 - sp.Bits
 	if extraBits > 0 {
 		mask := byte(0xFF >> extraBits)
-		res[0] &= mask
+		dst[0] &= mask
 	}
 	// carry is ignored (mod 2^Bits)
-	return res, nil
+	return nil
 }
 
 // AddMod computes (a + b) modulo 2^Bits.
@@ -406,20 +518,32 @@ func (sp Space) MulKMod(a ID) (ID, error) {
 //     the most significant byte are masked to zero.
 //   - Returns an error if either input is not a valid ID.
 func (sp Space) AddMod(a, b ID) (ID, error) {
+	res := make(ID, sp.ByteLen)
+	if err := sp.AddModInto(res, a, b); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// AddModInto computes (a + b) modulo 2^Bits and stores the result in
+// dst, which must have length sp.ByteLen. dst may alias a or b.
+func (sp Space) AddModInto(dst, a, b ID) error {
 	if err := sp.IsValidID(a); err != nil {
-		return nil, fmt.Errorf("invalid ID a: %w", err)
+		return fmt.Errorf("invalid ID a: %w", err)
 	}
 	if err := sp.IsValidID(b); err != nil {
-		return nil, fmt.Errorf("invalid ID b: %w", err)
+		return fmt.Errorf("invalid ID b: %w", err)
+	}
+	if len(dst) != sp.ByteLen {
+		return fmt.Errorf("invalid destination length: %d (want %d)", len(dst), sp.ByteLen)
 	}
 
-	res := make(ID, sp.ByteLen)
 	carry := 0
 
 	// Add from least significant to most significant byte
 	for i := sp.ByteLen - 1; i >= 0; i-- {
 		sum := int(a[i]) + int(b[i]) + carry
-		res[i] = byte(sum & 0xFF)
+		dst[i] = byte(sum & 0xFF)
 		carry = sum >> 8
 	}
 
@@ -427,10 +551,10 @@ func (sp Space) AddMod(a, b ID) (ID, error) {
 	extraBits := sp.ByteLen*8 - sp.Bits
 	if extraBits > 0 {
 		mask := byte(0xFF >> extraBits)
-		res[0] &= mask
+		dst[0] &= mask
 	}
 
-	return res, nil
+	return nil
 }
 
 // NextDigitBaseK extracts the most significant digit of x in base-k,
@@ -449,8 +573,23 @@ func (sp Space) AddMod(a, b ID) (ID, error) {
 //   - rest:  the remaining ID after shifting left by r bits.
 //   - error: if x is invalid or GraphGrade is not a power of 2.
 func (sp Space) NextDigitBaseK(x ID) (digit uint64, rest ID, err error) {
+	rest = make(ID, sp.ByteLen)
+	digit, err = sp.NextDigitBaseKInto(rest, x)
+	if err != nil {
+		return 0, nil, err
+	}
+	return digit, rest, nil
+}
+
+// NextDigitBaseKInto is the allocation-free variant of NextDigitBaseK:
+// the shifted remainder is stored in dst (length sp.ByteLen, may alias x)
+// and the extracted digit is returned.
+func (sp Space) NextDigitBaseKInto(dst, x ID) (digit uint64, err error) {
 	if err := sp.IsValidID(x); err != nil {
-		return 0, nil, fmt.Errorf("NextDigitBaseK: invalid ID: %w", err)
+		return 0, fmt.Errorf("NextDigitBaseK: invalid ID: %w", err)
+	}
+	if len(dst) != sp.ByteLen {
+		return 0, fmt.Errorf("NextDigitBaseK: invalid destination length: %d (want %d)", len(dst), sp.ByteLen)
 	}
 
 	// Number of unused MSBs in the first byte
@@ -470,21 +609,20 @@ func (sp Space) NextDigitBaseK(x ID) (digit uint64, rest ID, err error) {
 	}
 
 	// shift left by r bits
-	rest = make(ID, sp.ByteLen)
 	carry := byte(0)
 	for i := sp.ByteLen - 1; i >= 0; i-- {
 		val := x[i]
-		rest[i] = (val << r) | carry
+		dst[i] = (val << r) | carry
 		carry = val >> (8 - r)
 	}
 
 	// mask unused high-order bits
 	if extraBits > 0 {
 		mask := byte(0xFF >> extraBits)
-		rest[0] &= mask
+		dst[0] &= mask
 	}
 
-	return digit, rest, nil
+	return digit, nil
 }
 
 func (sp Space) BestImaginarySimple(self, succ, target ID) (currentI, kshift ID, err error) {
@@ -71,3 +71,79 @@ func TestMulKMod(t *testing.T) {
 		})
 	}
 }
+
+func TestMulKAddModInto(t *testing.T) {
+	tests := []struct {
+		name       string
+		bits       int
+		graphGrade int
+		aHex       string
+		digit      uint64
+		wantHex    string
+	}{
+		{
+			name:       "16-bit *2 +1",
+			bits:       16,
+			graphGrade: 2,
+			aHex:       "7fff",
+			digit:      1,
+			wantHex:    "ffff",
+		},
+		{
+			name:       "32-bit *4 +3 overflow",
+			bits:       32,
+			graphGrade: 4,
+			aHex:       "f1c8502c",
+			digit:      3,
+			wantHex:    "c72140b3",
+		},
+		{
+			name:       "12-bit (not byte aligned) *2 +1",
+			bits:       12,
+			graphGrade: 2,
+			aHex:       "0fff",
+			digit:      1,
+			wantHex:    "0fff",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := Space{
+				Bits:       tt.bits,
+				ByteLen:    (tt.bits + 7) / 8,
+				GraphGrade: tt.graphGrade,
+			}
+			a := mustHex(tt.aHex)
+
+			// Deve coincidere con MulKMod seguito da AddMod
+			mul, err := sp.MulKMod(a)
+			if err != nil {
+				t.Fatalf("MulKMod returned error: %v", err)
+			}
+			ref, err := sp.AddMod(mul, sp.FromUint64(tt.digit))
+			if err != nil {
+				t.Fatalf("AddMod returned error: %v", err)
+			}
+			if got := hex.EncodeToString(ref); got != tt.wantHex {
+				t.Fatalf("MulKMod+AddMod(%s, %d) = %s, want %s", tt.aHex, tt.digit, got, tt.wantHex)
+			}
+
+			dst := make(ID, sp.ByteLen)
+			if err := sp.MulKAddModInto(dst, a, tt.digit); err != nil {
+				t.Fatalf("MulKAddModInto returned error: %v", err)
+			}
+			if got := hex.EncodeToString(dst); got != tt.wantHex {
+				t.Errorf("MulKAddModInto(%s, %d) = %s, want %s", tt.aHex, tt.digit, got, tt.wantHex)
+			}
+
+			// Calcolo in-place (dst == a)
+			if err := sp.MulKAddModInto(a, a, tt.digit); err != nil {
+				t.Fatalf("MulKAddModInto in place returned error: %v", err)
+			}
+			if got := hex.EncodeToString(a); got != tt.wantHex {
+				t.Errorf("MulKAddModInto in place = %s, want %s", got, tt.wantHex)
+			}
+		})
+	}
+}
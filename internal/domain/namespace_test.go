@@ -0,0 +1,38 @@
+package domain
+
+import "testing"
+
+func TestNamespaceSeparatesIDs(t *testing.T) {
+	sp, err := NewSpace(66, 2, 4)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	plain := sp.NewIdFromString("my-key")
+
+	tests := []struct {
+		name      string
+		nsA, keyA string
+		nsB, keyB string
+		equal     bool
+	}{
+		{name: "same namespace", nsA: "alpha", keyA: "my-key", nsB: "alpha", keyB: "my-key", equal: true},
+		{name: "different namespaces", nsA: "alpha", keyA: "my-key", nsB: "beta", keyB: "my-key", equal: false},
+		{name: "namespace vs none", nsA: "alpha", keyA: "my-key", nsB: "", keyB: "my-key", equal: false},
+		// il separatore evita collisioni tra namespace e chiave concatenati
+		{name: "ambiguous concatenation", nsA: "alpha", keyA: "my-key", nsB: "alph", keyB: "amy-key", equal: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idA := sp.WithNamespace(tt.nsA).NewIdFromString(tt.keyA)
+			idB := sp.WithNamespace(tt.nsB).NewIdFromString(tt.keyB)
+			if got := idA.Equal(idB); got != tt.equal {
+				t.Errorf("IDs equal = %v, want %v (%s vs %s)", got, tt.equal, idA.ToHexString(true), idB.ToHexString(true))
+			}
+		})
+	}
+
+	// Senza namespace la derivazione non cambia
+	if !sp.WithNamespace("").NewIdFromString("my-key").Equal(plain) {
+		t.Errorf("empty namespace changed the derived ID")
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"fmt"
+)
+
+// NextHop is the outcome of one step of an iterative lookup: either the
+// lookup ended and Successor is the node responsible for the target, or it
+// continues at one of Candidates (in order of preference, the last one
+// being the successor of the answering node) with the imaginary node
+// CurrentI and the shifted key KShift.
+type NextHop struct {
+	Successor  *Node
+	Candidates []*Node
+	CurrentI   ID
+	KShift     ID
+
+	// The fields below describe the routing decision of the answering node
+	// for tracing; they are not part of the protobuf representation.
+
+	// Shifted reports whether the step applied de Bruijn routing, i.e.
+	// CurrentI is the next imaginary node.
+	Shifted bool
+	// DeBruijnIdx holds the index in the de Bruijn list of each de Bruijn
+	// candidate (Candidates but the last); a backup carries the index of
+	// its digit.
+	DeBruijnIdx []int
+}
+
+// ToProtoDHT converts a NextHop into its DHT protobuf representation.
+func (h *NextHop) ToProtoDHT() *dhtv1.NextHopResponse {
+	if h == nil {
+		return nil
+	}
+	resp := &dhtv1.NextHopResponse{
+		Successor: h.Successor.ToProtoDHT(),
+		CurrentI:  h.CurrentI,
+		KShift:    h.KShift,
+	}
+	for _, c := range h.Candidates {
+		resp.Candidates = append(resp.Candidates, c.ToProtoDHT())
+	}
+	return resp
+}
+
+// NextHopFromProtoDHT converts a DHT NextHopResponse into a NextHop,
+// validating every identifier against sp.
+func NextHopFromProtoDHT(sp *Space, p *dhtv1.NextHopResponse) (*NextHop, error) {
+	if p == nil {
+		return nil, fmt.Errorf("missing next hop")
+	}
+	succ, err := NodeFromProtoDHT(sp, p.Successor)
+	if err != nil {
+		return nil, err
+	}
+	if succ != nil {
+		return &NextHop{Successor: succ}, nil
+	}
+	if len(p.Candidates) == 0 {
+		return nil, fmt.Errorf("next hop without successor nor candidates")
+	}
+	if err := sp.IsValidID(p.CurrentI); err != nil {
+		return nil, fmt.Errorf("invalid current_i: %w", err)
+	}
+	if err := sp.IsValidID(p.KShift); err != nil {
+		return nil, fmt.Errorf("invalid k_shift: %w", err)
+	}
+	h := &NextHop{CurrentI: p.CurrentI, KShift: p.KShift}
+	for _, c := range p.Candidates {
+		node, err := NodeFromProtoDHT(sp, c)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			h.Candidates = append(h.Candidates, node)
+		}
+	}
+	return h, nil
+}
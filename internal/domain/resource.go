@@ -4,17 +4,119 @@ import (
 	clientv1 "KoordeDHT/internal/api/client/v1"
 	dhtv1 "KoordeDHT/internal/api/dht/v1"
 	"errors"
+	"time"
 )
 
 var (
-	ErrResourceNotFound = errors.New("resource not found")
-	ErrNotResponsible   = errors.New("node not responsible for the given key")
+	ErrResourceNotFound  = errors.New("resource not found")
+	ErrNotResponsible    = errors.New("node not responsible for the given key")
+	ErrResourceCorrupted = errors.New("resource corrupted: checksum mismatch")
+	ErrStorageFull       = errors.New("storage quota exceeded")
+	// ErrPreconditionFailed is returned by a conditional write whose
+	// Condition does not hold for the stored resource.
+	ErrPreconditionFailed = errors.New("write precondition not met")
 )
 
 type Resource struct {
 	Key    ID
 	RawKey string
 	Value  string
+	Expiry time.Time // zero = never expires
+	// Origin is the ID of the node that first accepted the Put of the
+	// resource (nil = unknown). It is preserved by transfers and repairs.
+	Origin ID
+	// Version orders the writes of the same key, so that copies can be
+	// reconciled. A Put carries none (0): the owner assigns it its
+	// wall-clock time (unix nanoseconds), raised above the version it
+	// stores so that it never decreases, and the Put is always applied.
+	// Replication, transfers and repairs carry the version of the owner and
+	// never replace a copy with an older one (0 = unknown, older than any
+	// other).
+	Version uint64
+	// Metadata is small application data attached to the value (e.g.
+	// content-type, owner), stored and transferred with it (nil = none).
+	Metadata map[string]string
+}
+
+// Condition is the precondition of a conditional write: the resource is
+// stored only if it holds for the resource currently stored under the same
+// key (expired resources count as absent). The zero Condition always holds.
+type Condition struct {
+	IfAbsent bool   // no resource is stored under the key
+	IfEqual  bool   // the stored resource has value Expected
+	Expected string // value compared by IfEqual
+}
+
+// IsZero reports whether c is the unconditional zero Condition.
+func (c Condition) IsZero() bool {
+	return c == Condition{}
+}
+
+// Holds reports whether c holds for cur, the resource stored under the key
+// (nil if there is none).
+func (c Condition) Holds(cur *Resource) bool {
+	if c.IfAbsent && cur != nil {
+		return false
+	}
+	if c.IfEqual && (cur == nil || cur.Value != c.Expected) {
+		return false
+	}
+	return true
+}
+
+// ToProtoDHT converts c into its node-to-node protobuf representation (nil
+// for the zero Condition).
+func (c Condition) ToProtoDHT() *dhtv1.Condition {
+	if c.IsZero() {
+		return nil
+	}
+	return &dhtv1.Condition{IfAbsent: c.IfAbsent, IfEqual: c.IfEqual, Expected: c.Expected}
+}
+
+// ConditionFromProtoDHT converts a node-to-node condition into a Condition
+// (the zero one for nil).
+func ConditionFromProtoDHT(p *dhtv1.Condition) Condition {
+	return Condition{IfAbsent: p.GetIfAbsent(), IfEqual: p.GetIfEqual(), Expected: p.GetExpected()}
+}
+
+// WithTTL returns a copy of r that expires ttl after now (ttl <= 0 leaves
+// r without expiry).
+func (r Resource) WithTTL(now time.Time, ttl time.Duration) Resource {
+	if ttl > 0 {
+		r.Expiry = now.Add(ttl)
+	}
+	return r
+}
+
+// Size returns the number of bytes r occupies in a storage quota: its
+// identifier, raw key, value, origin and metadata.
+func (r *Resource) Size() int64 {
+	size := len(r.Key) + len(r.RawKey) + len(r.Value) + len(r.Origin)
+	for k, v := range r.Metadata {
+		size += len(k) + len(v)
+	}
+	return int64(size)
+}
+
+// Expired reports whether r has an expiry time and it is not after now.
+func (r *Resource) Expired(now time.Time) bool {
+	return !r.Expiry.IsZero() && !now.Before(r.Expiry)
+}
+
+// expiryToProto encodes an expiry time as unix milliseconds (0 = never).
+func expiryToProto(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// expiryFromProto decodes unix milliseconds into an expiry time.
+func expiryFromProto(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
 }
 
 // ToProtoDHT converts a domain.Resource into its DHT-facing
@@ -24,9 +126,13 @@ func (r *Resource) ToProtoDHT() *dhtv1.Resource {
 		return nil
 	}
 	return &dhtv1.Resource{
-		Key:    r.Key,    // already []byte
-		RawKey: r.RawKey, // debug only
-		Value:  r.Value,
+		Key:       r.Key,    // already []byte
+		RawKey:    r.RawKey, // debug only
+		Value:     r.Value,
+		ExpiresAt: expiryToProto(r.Expiry),
+		Origin:    r.Origin,
+		Version:   r.Version,
+		Metadata:  r.Metadata,
 	}
 }
 
@@ -39,10 +145,19 @@ func ResourceFromProtoDHT(sp *Space, p *dhtv1.Resource) (*Resource, error) {
 	if err := sp.IsValidID(p.Key); err != nil {
 		return nil, errors.New("invalid resource key ID")
 	}
+	if len(p.Origin) > 0 {
+		if err := sp.IsValidID(p.Origin); err != nil {
+			return nil, errors.New("invalid resource origin ID")
+		}
+	}
 	return &Resource{
-		Key:    p.Key,
-		RawKey: p.RawKey,
-		Value:  p.Value,
+		Key:      p.Key,
+		RawKey:   p.RawKey,
+		Value:    p.Value,
+		Expiry:   expiryFromProto(p.ExpiresAt),
+		Origin:   p.Origin,
+		Version:  p.Version,
+		Metadata: p.Metadata,
 	}, nil
 }
 
@@ -52,23 +167,30 @@ func (r *Resource) ToProtoClient() *clientv1.Resource {
 	if r == nil {
 		return nil
 	}
-	return &clientv1.Resource{
-		Key:   r.RawKey,
-		Value: r.Value,
+	res := &clientv1.Resource{
+		Key:      r.RawKey,
+		Value:    r.Value,
+		Metadata: r.Metadata,
+	}
+	if len(r.Origin) > 0 {
+		res.Origin = r.Origin.ToHexString(true)
 	}
+	return res
 }
 
 // ResourceFromProtoClient converts a client-facing resource
 // into a domain.Resource. The ID must be computed later
-// by hashing the RawKey into the DHT space.
+// by hashing the RawKey into the DHT space. The origin is
+// assigned by the node, so the one sent by the client is ignored.
 func ResourceFromProtoClient(sp *Space, p *clientv1.Resource) *Resource {
 	if p == nil {
 		return nil
 	}
 	key := sp.NewIdFromString(p.Key)
 	return &Resource{
-		RawKey: p.Key,
-		Key:    key,
-		Value:  p.Value,
+		RawKey:   p.Key,
+		Key:      key,
+		Value:    p.Value,
+		Metadata: p.Metadata,
 	}
 }
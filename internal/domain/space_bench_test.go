@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// Benchmarks for the identifier arithmetic executed on every routing hop.
+//
+// Each operation is measured in its allocating form and in its *Into
+// variant writing into a caller-provided buffer. Run with
+//
+//	go test -run '^$' -bench . -benchmem ./internal/domain/
+//
+// Allocations per operation (before → after):
+//   - MulKMod → MulKModInto:                          1 → 0
+//   - AddMod → AddModInto:                            1 → 0
+//   - NextDigitBaseK → NextDigitBaseKInto:            1 → 0
+//   - nextI (MulKMod + AddMod → MulKAddModInto):      2 → 0
+
+var benchBits = []int{64, 160, 256}
+var benchDegrees = []int{2, 16}
+
+// benchSpace returns a Space and a pool of random valid IDs in it.
+func benchSpace(b *testing.B, bits, degree int) (Space, []ID) {
+	b.Helper()
+	sp, err := NewSpace(bits, degree, 1)
+	if err != nil {
+		b.Fatalf("NewSpace: %v", err)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	ids := make([]ID, 64)
+	for i := range ids {
+		id := make(ID, sp.ByteLen)
+		rnd.Read(id)
+		if extra := sp.ByteLen*8 - sp.Bits; extra > 0 {
+			id[0] &= byte(0xFF >> extra)
+		}
+		ids[i] = id
+	}
+	return sp, ids
+}
+
+// forEachSpace runs fn as a sub-benchmark for every bit size and degree.
+func forEachSpace(b *testing.B, fn func(b *testing.B, sp Space, ids []ID)) {
+	for _, bits := range benchBits {
+		for _, degree := range benchDegrees {
+			b.Run(fmt.Sprintf("bits=%d/k=%d", bits, degree), func(b *testing.B) {
+				sp, ids := benchSpace(b, bits, degree)
+				b.ReportAllocs()
+				b.ResetTimer()
+				fn(b, sp, ids)
+			})
+		}
+	}
+}
+
+func BenchmarkMulKMod(b *testing.B) {
+	forEachSpace(b, func(b *testing.B, sp Space, ids []ID) {
+		for i := 0; i < b.N; i++ {
+			if _, err := sp.MulKMod(ids[i%len(ids)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkMulKModInto(b *testing.B) {
+	forEachSpace(b, func(b *testing.B, sp Space, ids []ID) {
+		dst := make(ID, sp.ByteLen)
+		for i := 0; i < b.N; i++ {
+			if err := sp.MulKModInto(dst, ids[i%len(ids)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkAddMod(b *testing.B) {
+	forEachSpace(b, func(b *testing.B, sp Space, ids []ID) {
+		for i := 0; i < b.N; i++ {
+			if _, err := sp.AddMod(ids[i%len(ids)], ids[(i+1)%len(ids)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkAddModInto(b *testing.B) {
+	forEachSpace(b, func(b *testing.B, sp Space, ids []ID) {
+		dst := make(ID, sp.ByteLen)
+		for i := 0; i < b.N; i++ {
+			if err := sp.AddModInto(dst, ids[i%len(ids)], ids[(i+1)%len(ids)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkNextDigitBaseK(b *testing.B) {
+	forEachSpace(b, func(b *testing.B, sp Space, ids []ID) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := sp.NextDigitBaseK(ids[i%len(ids)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkNextDigitBaseKInto(b *testing.B) {
+	forEachSpace(b, func(b *testing.B, sp Space, ids []ID) {
+		dst := make(ID, sp.ByteLen)
+		for i := 0; i < b.N; i++ {
+			if _, err := sp.NextDigitBaseKInto(dst, ids[i%len(ids)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkNextImaginary measures the computation of the next imaginary
+// node (nextI = k*currentI + digit) as it was done before MulKAddModInto.
+func BenchmarkNextImaginary(b *testing.B) {
+	forEachSpace(b, func(b *testing.B, sp Space, ids []ID) {
+		for i := 0; i < b.N; i++ {
+			nextI, err := sp.MulKMod(ids[i%len(ids)])
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := sp.AddMod(nextI, sp.FromUint64(uint64(i%sp.GraphGrade))); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkNextImaginaryInto measures the same step with MulKAddModInto,
+// as used by FindSuccessorStep.
+func BenchmarkNextImaginaryInto(b *testing.B) {
+	forEachSpace(b, func(b *testing.B, sp Space, ids []ID) {
+		dst := make(ID, sp.ByteLen)
+		for i := 0; i < b.N; i++ {
+			if err := sp.MulKAddModInto(dst, ids[i%len(ids)], uint64(i%sp.GraphGrade)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -10,6 +10,14 @@ import (
 )
 
 func New(cfg configloader.LoggerConfig) (*zap.Logger, error) {
+	lgr, _, err := NewWithAudit(cfg)
+	return lgr, err
+}
+
+// NewWithAudit builds the main logger and an audit logger sharing the same
+// encoder and destination. The audit logger always writes at info level,
+// regardless of cfg.Level, so that access entries are never filtered out.
+func NewWithAudit(cfg configloader.LoggerConfig) (*zap.Logger, *zap.Logger, error) {
 	// log level
 	level := zap.NewAtomicLevel()
 	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
@@ -44,6 +52,10 @@ func New(cfg configloader.LoggerConfig) (*zap.Logger, error) {
 	default:
 		ws = zapcore.AddSync(os.Stdout) // fallback console
 	}
+	// the writer is shared: lumberjack rotates a single file per process
+	ws = zapcore.Lock(ws)
 	core := zapcore.NewCore(encoder, ws, level)
-	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel)), nil
+	auditCore := zapcore.NewCore(encoder.Clone(), ws, zap.InfoLevel)
+	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel)),
+		zap.New(auditCore).Named("audit"), nil
 }
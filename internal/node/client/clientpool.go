@@ -4,6 +4,10 @@ import (
 	dhtv1 "KoordeDHT/internal/api/dht/v1"
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/ctxutil"
+	"KoordeDHT/internal/node/telemetry/lookuptrace"
+	"context"
+	"crypto/tls"
 	"fmt"
 	"sync"
 	"time"
@@ -11,11 +15,15 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
 var (
 	ErrNoConnInPool = fmt.Errorf("clientpool: no connection in pool")
+	ErrUnreachable  = fmt.Errorf("clientpool: node unreachable")
 )
 
 // --------------------------------------
@@ -27,8 +35,9 @@ var (
 // de Bruijn pointer) currently rely on this connection. The connection is only
 // closed when the reference count drops to zero.
 type refConn struct {
-	conn *grpc.ClientConn // active gRPC connection to the remote node
-	refs int              // number of active references to this connection
+	conn     *grpc.ClientConn // active gRPC connection to the remote node
+	refs     int              // number of active references to this connection
+	failures int              // consecutive failed health checks (see WithHealthCheck)
 }
 
 // --------------------------------------
@@ -44,8 +53,22 @@ type Pool struct {
 	lgr            logger.Logger
 	mu             sync.Mutex
 	clients        map[string]*refConn
-	closed         bool          // indicates if the pool has been closed
-	failureTimeout time.Duration // timeout for RPC calls (after which the server is considered unresponsive)
+	evicted        map[string]int // references still held on connections evicted by the health check
+	closed         bool           // indicates if the pool has been closed
+	done           chan struct{}  // closed by Close, stops the health loop
+	failureTimeout time.Duration  // timeout for RPC calls (after which the server is considered unresponsive)
+	dialTimeout    time.Duration  // bound on every connection attempt (default failureTimeout)
+	failFast       bool           // ping ephemeral connections before returning them (see WithDialTimeout)
+
+	unaryInts      []grpc.UnaryClientInterceptor  // user interceptors, chained after the built-ins
+	streamInts     []grpc.StreamClientInterceptor // user interceptors, chained after the built-ins
+	compressor     string                         // compressor used on outbound calls ("" = no compression)
+	tlsConfig      *tls.Config                    // client TLS configuration (nil = plaintext)
+	maxRecvMsgSize int                            // limit on received messages (0 = gRPC default)
+	keepalive      keepalive.ClientParameters     // keepalive pings of idle connections (Time 0 = disabled)
+
+	healthInterval  time.Duration // interval of the health loop (0 = disabled)
+	healthThreshold int           // consecutive failed pings after which a connection is evicted
 }
 
 // New creates a new empty Pool. It accepts a list of functional options
@@ -55,26 +78,89 @@ func New(selfId domain.ID, selfAddr string, failTO time.Duration, opt ...Option)
 		selfId:         selfId,
 		selfAddr:       selfAddr,
 		clients:        make(map[string]*refConn),
+		evicted:        make(map[string]int),
 		lgr:            &logger.NopLogger{}, // default: no logging
 		closed:         false,
+		done:           make(chan struct{}),
 		failureTimeout: failTO,
 	}
 	// Apply functional options
 	for _, o := range opt {
 		o(p)
 	}
+	if p.dialTimeout <= 0 {
+		p.dialTimeout = failTO
+	}
+	if p.healthInterval > 0 {
+		go p.healthLoop()
+	}
 	return p
 }
 
+// Size returns the number of connections currently held in the pool
+// (ephemeral connections are not counted).
+func (p *Pool) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.clients)
+}
+
 // FailureTimeout returns the default timeout for RPC calls.
 func (p *Pool) FailureTimeout() time.Duration {
 	return p.failureTimeout
 }
 
+// dialOptions returns the gRPC dial options shared by pooled and ephemeral
+// connections: TLS transport if configured (plaintext otherwise), the otelgrpc stats handler, the
+// interceptor chain (built-in peer flag and lookuptrace first, then user interceptors),
+// the bound on connection attempts and, if configured, the compressor for
+// outbound messages, the limit on received ones and the keepalive
+// parameters.
+func (p *Pool) dialOptions() []grpc.DialOption {
+	unary := append([]grpc.UnaryClientInterceptor{peerUnary, lookuptrace.ClientInterceptor()}, p.unaryInts...)
+	stream := append([]grpc.StreamClientInterceptor{peerStream}, p.streamInts...)
+	creds := insecure.NewCredentials()
+	if p.tlsConfig != nil {
+		creds = credentials.NewTLS(p.tlsConfig)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithTracerProvider(otel.GetTracerProvider()),
+			otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
+		)),
+		grpc.WithChainUnaryInterceptor(unary...),
+		grpc.WithChainStreamInterceptor(stream...),
+	}
+	if p.keepalive.Time > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(p.keepalive))
+	}
+	if p.dialTimeout > 0 {
+		opts = append(opts, grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoff.DefaultConfig,
+			MinConnectTimeout: p.dialTimeout,
+		}))
+	}
+	var callOpts []grpc.CallOption
+	if p.compressor != "" {
+		callOpts = append(callOpts, grpc.UseCompressor(p.compressor))
+	}
+	if p.maxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(p.maxRecvMsgSize))
+	}
+	if len(callOpts) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+	return opts
+}
+
 // AddRef ensures that a gRPC connection to the given node exists in the pool.
 // If the connection already exists, its reference count is incremented.
 // If not, a new connection is created and tracked with an initial reference count of 1.
 //
+// New connections start connecting right away, and every connection
+// attempt is bounded by the dial timeout of the pool (see WithDialTimeout).
+//
 // This method should be called whenever a node is added to the RoutingTable
 // (e.g., as successor or de Bruijn pointer or Predecessor).
 func (p *Pool) AddRef(addr string) error {
@@ -86,6 +172,7 @@ func (p *Pool) AddRef(addr string) error {
 	}
 	p.mu.Lock()
 	if p.closed {
+		p.mu.Unlock()
 		return fmt.Errorf("clientpool: pool is closed")
 	}
 	// if connection already exists, increment refs and return
@@ -95,19 +182,17 @@ func (p *Pool) AddRef(addr string) error {
 		return nil
 	}
 	// otherwise create new connection
-	conn, dialErr := grpc.NewClient(
-		addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()), // plaintext, no TLS
-		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
-			otelgrpc.WithTracerProvider(otel.GetTracerProvider()),
-			otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
-		)),
-	)
+	conn, dialErr := grpc.NewClient(addr, p.dialOptions()...)
 	if dialErr != nil {
 		p.mu.Unlock()
 		return dialErr
 	}
-	p.clients[addr] = &refConn{conn: conn, refs: 1}
+	conn.Connect()
+	// references taken before an eviction are still held by their owners,
+	// which will release them: carry them over to the new connection
+	refs := 1 + p.evicted[addr]
+	delete(p.evicted, addr)
+	p.clients[addr] = &refConn{conn: conn, refs: refs}
 	p.mu.Unlock()
 	p.lgr.Debug("Pool: new connection added", logger.F("addr", addr))
 	return nil
@@ -124,6 +209,7 @@ func (p *Pool) GetFromPool(addr string) (dhtv1.DHTClient, error) {
 	}
 	p.mu.Lock()
 	if p.closed {
+		p.mu.Unlock()
 		return nil, fmt.Errorf("clientpool: pool is closed")
 	}
 	rc, ok := p.clients[addr]
@@ -136,6 +222,11 @@ func (p *Pool) GetFromPool(addr string) (dhtv1.DHTClient, error) {
 
 // DialEphemeral creates a new one-shot gRPC connection to the given address.
 // The connection is NOT added to the pool; the caller is responsible for closing it.
+//
+// The connection starts connecting right away, with every attempt bounded
+// by the dial timeout of the pool. With fail-fast (see WithDialTimeout) the
+// node is also pinged within the dial timeout, and an unreachable node is
+// reported with ErrUnreachable instead of on the first RPC.
 func (p *Pool) DialEphemeral(addr string) (dhtv1.DHTClient, *grpc.ClientConn, error) {
 	p.mu.Lock()
 	closed := p.closed
@@ -149,14 +240,7 @@ func (p *Pool) DialEphemeral(addr string) (dhtv1.DHTClient, *grpc.ClientConn, er
 	if addr == p.selfAddr {
 		return nil, nil, fmt.Errorf("clientpool: requested self address")
 	}
-	conn, err := grpc.NewClient(
-		addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()), // plaintext, no TLS
-		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
-			otelgrpc.WithTracerProvider(otel.GetTracerProvider()),
-			otelgrpc.WithPropagators(otel.GetTextMapPropagator()),
-		)),
-	)
+	conn, err := grpc.NewClient(addr, p.dialOptions()...)
 	if err != nil {
 		p.lgr.Error("DialEphemeral: failed to dial",
 			logger.F("addr", addr),
@@ -164,10 +248,40 @@ func (p *Pool) DialEphemeral(addr string) (dhtv1.DHTClient, *grpc.ClientConn, er
 		)
 		return nil, nil, fmt.Errorf("clientpool: failed to dial %s: %w", addr, err)
 	}
+	conn.Connect()
+	cli := dhtv1.NewDHTClient(conn)
+	if p.failFast {
+		ctx, cancel := context.WithTimeout(context.Background(), p.dialTimeout)
+		err := Ping(ctx, cli)
+		cancel()
+		if err != nil {
+			_ = conn.Close()
+			p.lgr.Warn("DialEphemeral: node unreachable",
+				logger.F("addr", addr),
+				logger.F("err", err),
+			)
+			return nil, nil, fmt.Errorf("%w: %s: %w", ErrUnreachable, addr, err)
+		}
+	}
 	p.lgr.Debug("DialEphemeral: connection created",
 		logger.F("addr", addr),
 	)
-	return dhtv1.NewDHTClient(conn), conn, nil
+	return cli, conn, nil
+}
+
+// Probe dials addr on a one-shot connection, with the same options as the
+// pooled ones, and pings it. Unlike DialEphemeral it accepts the node's own
+// address, so a node can check that its advertised address reaches it.
+func (p *Pool) Probe(ctx context.Context, addr string) error {
+	if addr == "" {
+		return fmt.Errorf("clientpool: empty address")
+	}
+	conn, err := grpc.NewClient(addr, p.dialOptions()...)
+	if err != nil {
+		return fmt.Errorf("clientpool: failed to dial %s: %w", addr, err)
+	}
+	defer func() { _ = conn.Close() }()
+	return Ping(ctx, dhtv1.NewDHTClient(conn))
 }
 
 // Release decreases the reference count for the given node.
@@ -185,6 +299,7 @@ func (p *Pool) Release(addr string) error {
 	var ok bool
 	p.mu.Lock()
 	if p.closed {
+		p.mu.Unlock()
 		return fmt.Errorf("clientpool: pool is closed")
 	}
 	rc, ok = p.clients[addr]
@@ -194,6 +309,13 @@ func (p *Pool) Release(addr string) error {
 		if refs <= 0 {
 			delete(p.clients, addr)
 		}
+	} else if n := p.evicted[addr]; n > 0 {
+		// reference to an evicted connection: nothing left to close
+		if n == 1 {
+			delete(p.evicted, addr)
+		} else {
+			p.evicted[addr] = n - 1
+		}
 	}
 	p.mu.Unlock()
 	if !ok || refs > 0 {
@@ -228,6 +350,7 @@ func (p *Pool) Close() error {
 		return nil
 	}
 	p.closed = true
+	close(p.done)
 
 	// Take a snapshot of current connections
 	conns := make([]*grpc.ClientConn, 0, len(p.clients))
@@ -237,6 +360,7 @@ func (p *Pool) Close() error {
 
 	// Reset the pool map so new operations see an empty pool
 	p.clients = make(map[string]*refConn)
+	p.evicted = make(map[string]int)
 	p.mu.Unlock()
 
 	var firstErr error
@@ -253,6 +377,80 @@ func (p *Pool) Close() error {
 	return firstErr
 }
 
+// healthLoop pings every pooled connection each healthInterval until the
+// pool is closed (see WithHealthCheck).
+func (p *Pool) healthLoop() {
+	ticker := time.NewTicker(p.healthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.checkHealth()
+		}
+	}
+}
+
+// checkHealth pings the pooled connections concurrently and evicts those
+// that failed healthThreshold consecutive checks: they are closed and
+// removed, so GetFromPool returns ErrNoConnInPool and callers re-dial.
+// Their outstanding references are remembered, so that the Release calls
+// of their holders stay balanced if the address is added again.
+func (p *Pool) checkHealth() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	targets := make(map[string]*refConn, len(p.clients))
+	for addr, rc := range p.clients {
+		targets[addr] = rc
+	}
+	p.mu.Unlock()
+
+	type result struct {
+		addr string
+		rc   *refConn
+		err  error
+	}
+	results := make(chan result, len(targets))
+	for addr, rc := range targets {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), p.failureTimeout)
+			defer cancel()
+			results <- result{addr: addr, rc: rc, err: Ping(ctx, dhtv1.NewDHTClient(rc.conn))}
+		}()
+	}
+
+	var dead []*grpc.ClientConn
+	p.mu.Lock()
+	for range targets {
+		r := <-results
+		// skip entries released or replaced while the ping was in flight
+		if p.closed || p.clients[r.addr] != r.rc {
+			continue
+		}
+		if r.err == nil {
+			r.rc.failures = 0
+			continue
+		}
+		r.rc.failures++
+		if r.rc.failures < p.healthThreshold {
+			continue
+		}
+		delete(p.clients, r.addr)
+		p.evicted[r.addr] += r.rc.refs
+		dead = append(dead, r.rc.conn)
+		p.lgr.Warn("Pool: connection evicted after failed health checks",
+			logger.F("addr", r.addr), logger.F("failures", r.rc.failures), logger.F("err", r.err))
+	}
+	p.mu.Unlock()
+	for _, conn := range dead {
+		_ = conn.Close()
+	}
+}
+
 // DebugLog emits a structured DEBUG-level log with a snapshot of the client pool.
 //
 // The log entry includes all active connections with their reference counts.
@@ -278,3 +476,13 @@ func (p *Pool) DebugLog() {
 	}
 	p.lgr.Debug("ClientPool snapshot", logger.F("entries", entries))
 }
+
+// peerUnary and peerStream flag every call of the pool as sent by a node
+// (see ctxutil.WithPeer).
+func peerUnary(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(ctxutil.WithPeer(ctx), method, req, reply, cc, opts...)
+}
+
+func peerStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(ctxutil.WithPeer(ctx), desc, cc, method, opts...)
+}
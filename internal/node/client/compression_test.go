@@ -0,0 +1,90 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+)
+
+// storeBytes conta i byte ricevuti sul filo dalle RPC Store
+type storeBytes struct {
+	wire atomic.Int64
+}
+
+type methodKey struct{}
+
+func (s *storeBytes) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, methodKey{}, info.FullMethodName)
+}
+
+func (s *storeBytes) HandleRPC(ctx context.Context, st stats.RPCStats) {
+	if in, ok := st.(*stats.InPayload); ok && ctx.Value(methodKey{}) == "/dht.v1.DHT/Store" {
+		s.wire.Add(int64(in.WireLength))
+	}
+}
+
+func (s *storeBytes) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (s *storeBytes) HandleConn(context.Context, stats.ConnStats) {}
+
+func TestCompressedTransfer(t *testing.T) {
+	value := strings.Repeat("koorde de bruijn ", 64*1024) // ~1 MiB, molto comprimibile
+
+	tests := []struct {
+		name       string
+		compressor string
+		maxWire    int64 // limite superiore dei byte sul filo
+	}{
+		{name: "none", compressor: "none", maxWire: 0},
+		{name: "gzip", compressor: "gzip", maxWire: int64(len(value) / 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := &storeBytes{}
+			r := testring.New(t, 3,
+				testring.WithPoolOptions(client.WithCompression(tt.compressor)),
+				testring.WithGRPCOptions(grpc.StatsHandler(counter)))
+
+			// Chiave posseduta da un nodo diverso da quello che riceve la Put
+			origin := r.Members[0]
+			var res domain.Resource
+			for i := 0; ; i++ {
+				raw := fmt.Sprintf("big-%d", i)
+				if id := r.Space.NewIdFromString(raw); r.Owner(id) != origin {
+					res = domain.Resource{Key: id, RawKey: raw, Value: value}
+					break
+				}
+			}
+			before := counter.wire.Load()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := origin.Node.Put(ctx, res); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			got, err := r.Owner(res.Key).Node.RetrieveLocal(res.Key)
+			if err != nil || got.Value != value {
+				t.Fatalf("value not transferred intact to the owner (err %v)", err)
+			}
+
+			wire := counter.wire.Load() - before
+			if tt.maxWire > 0 && wire > tt.maxWire {
+				t.Errorf("Store sent %d bytes on the wire, want <= %d", wire, tt.maxWire)
+			}
+			if tt.maxWire == 0 && wire < int64(len(value)) {
+				t.Errorf("uncompressed Store sent %d bytes on the wire, want >= %d", wire, len(value))
+			}
+		})
+	}
+}
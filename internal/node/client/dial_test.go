@@ -0,0 +1,66 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/testring"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// deadAddr restituisce un indirizzo locale su cui nessuno è in ascolto.
+func deadAddr(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+	return addr
+}
+
+func TestDialEphemeralFailFast(t *testing.T) {
+	r := testring.New(t, 1)
+	dead := deadAddr(t)
+	tests := []struct {
+		name     string
+		addr     string
+		failFast bool
+		wantErr  bool
+	}{
+		{name: "live node", addr: r.Members[0].Addr, failFast: true},
+		{name: "dead node", addr: dead, failFast: true, wantErr: true},
+		// senza fail-fast la connessione è pigra e l'errore emerge alla prima RPC
+		{name: "dead node lazy", addr: dead},
+		{name: "self", addr: "127.0.0.1:1", failFast: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := client.New(r.Space.NewIdFromString("dialer"), "127.0.0.1:1", 5*time.Second,
+				client.WithDialTimeout(time.Second, tt.failFast))
+			t.Cleanup(func() { _ = p.Close() })
+
+			start := time.Now()
+			_, conn, err := p.DialEphemeral(tt.addr)
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("DialEphemeral(%s) took %v, want within the dial timeout", tt.addr, elapsed)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DialEphemeral(%s): err = %v, wantErr %v", tt.addr, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		})
+	}
+
+	// un nodo irraggiungibile viene segnalato con ErrUnreachable
+	p := client.New(r.Space.NewIdFromString("dialer"), "127.0.0.1:1", time.Second, client.WithDialTimeout(0, true))
+	t.Cleanup(func() { _ = p.Close() })
+	if _, _, err := p.DialEphemeral(dead); !errors.Is(err, client.ErrUnreachable) {
+		t.Errorf("DialEphemeral(%s): got %v, want ErrUnreachable", dead, err)
+	}
+}
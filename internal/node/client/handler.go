@@ -4,10 +4,13 @@ import (
 	pb "KoordeDHT/internal/api/dht/v1"
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/node/ctxutil"
+	"KoordeDHT/internal/node/storage"
 	"KoordeDHT/internal/node/telemetry"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"unicode/utf8"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -102,6 +105,84 @@ func FindSuccessorStep(ctx context.Context, client pb.DHTClient, sp *domain.Spac
 	return domain.NodeFromProtoDHT(sp, resp.Node)
 }
 
+// FindPredecessor performs a FindPredecessor RPC in "Initial" mode: it
+// starts a lookup of the predecessor of the node responsible for target,
+// i.e. the node n with target in (n, successor(n)].
+//
+// The caller is responsible for providing a ready-to-use gRPC client.
+// This function does not manage client connection pooling or closing.
+//
+// Returns:
+//   - *domain.Node: the predecessor returned by the remote server
+//   - error: ErrTimeout if the RPC timed out, or a wrapped RPC error otherwise.
+func FindPredecessor(ctx context.Context, client pb.DHTClient, sp *domain.Space, target domain.ID) (*domain.Node, error) {
+	return findPredecessor(ctx, client, sp, &pb.FindSuccessorRequest{
+		TargetId: target,
+		Mode:     &pb.FindSuccessorRequest_Initial{Initial: &pb.Initial{}},
+	})
+}
+
+// FindPredecessorStep performs a FindPredecessor RPC in "Step" mode,
+// continuing a predecessor lookup with the given imaginary node (currentI)
+// and shifted key state (kshift). Return values are as for FindPredecessor.
+func FindPredecessorStep(ctx context.Context, client pb.DHTClient, sp *domain.Space, target, currentI, kshift domain.ID) (*domain.Node, error) {
+	return findPredecessor(ctx, client, sp, &pb.FindSuccessorRequest{
+		TargetId: target,
+		Mode:     &pb.FindSuccessorRequest_Step{Step: &pb.Step{CurrentI: currentI, KShift: kshift}},
+	})
+}
+
+func findPredecessor(ctx context.Context, client pb.DHTClient, sp *domain.Space, req *pb.FindSuccessorRequest) (*domain.Node, error) {
+	// Check for canceled/expired context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	// Perform the RPC
+	resp, err := client.FindPredecessor(ctx, req)
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.DeadlineExceeded {
+			return nil, ErrTimeout
+		}
+		return nil, fmt.Errorf("client: FindPredecessor RPC failed: %w", err)
+	}
+	// Convert the protobuf Node into a domain.Node
+	return domain.NodeFromProtoDHT(sp, resp.Node)
+}
+
+// FindSuccessorNextHop asks the given remote node for the next step of an
+// iterative lookup of target, without the remote node forwarding it. With
+// currentI == nil the step is computed in "Initial" mode, otherwise in
+// "Step" mode with the given imaginary node and shifted key.
+//
+// The caller is responsible for providing a ready-to-use gRPC client.
+// This function does not manage client connection pooling or closing.
+//
+// Returns:
+//   - *domain.NextHop: the successor, if the lookup ended at the remote
+//     node, or the candidates and state of the next step
+//   - error: ErrTimeout if the RPC timed out, or a wrapped RPC error otherwise.
+func FindSuccessorNextHop(ctx context.Context, client pb.DHTClient, sp *domain.Space, target, currentI, kshift domain.ID) (*domain.NextHop, error) {
+	// Check for canceled/expired context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	req := &pb.FindSuccessorRequest{TargetId: target}
+	if currentI == nil {
+		req.Mode = &pb.FindSuccessorRequest_Initial{Initial: &pb.Initial{}}
+	} else {
+		req.Mode = &pb.FindSuccessorRequest_Step{Step: &pb.Step{CurrentI: currentI, KShift: kshift}}
+	}
+	// Perform the RPC
+	resp, err := client.FindSuccessorNextHop(ctx, req)
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.DeadlineExceeded {
+			return nil, ErrTimeout
+		}
+		return nil, fmt.Errorf("client: FindSuccessorNextHop RPC failed: %w", err)
+	}
+	return domain.NextHopFromProtoDHT(sp, resp)
+}
+
 // GetPredecessor contacts the given remote node and asks for its predecessor.
 //
 // The caller must provide a ready-to-use gRPC client.
@@ -231,15 +312,66 @@ func Ping(ctx context.Context, client pb.DHTClient) error {
 //
 // Behavior:
 //   - Opens a client stream.
-//   - Attempts to send all resources in the input slice.
+//   - Attempts to send all resources in the input slice, splitting values
+//     larger than storeChunkBytes across several frames (see StoreRequest).
 //   - Collects any resources that could not be sent successfully.
 //   - Closes the stream and waits for server acknowledgment.
+//   - Sets the version of every resource to the one the remote node
+//     stored under its key (the one it assigned to a new write, see
+//     domain.Resource.Version).
 //
 // Returns:
 //   - A slice of resources that failed to be stored (empty if all succeeded).
 //   - An error if the stream could not be opened or if the final acknowledgment failed.
 //     (In such case, all resources are considered failed.)
 func StoreRemote(ctx context.Context, client pb.DHTClient, resources []domain.Resource) ([]domain.Resource, error) {
+	return store(ctx, client, resources, false, domain.Condition{})
+}
+
+// StoreIf stores resource on a remote node via the Store RPC only if cond
+// holds for the resource the node currently stores under its key (see
+// domain.Condition), and returns the version the node stored. It returns
+// an error wrapping domain.ErrPreconditionFailed if cond does not hold.
+func StoreIf(ctx context.Context, client pb.DHTClient, resource domain.Resource, cond domain.Condition) (uint64, error) {
+	resources := []domain.Resource{resource}
+	failed, err := store(ctx, client, resources, false, cond)
+	if err == nil && len(failed) > 0 {
+		return 0, errors.New("client: failed to send the resource on the store stream")
+	}
+	return resources[0].Version, err
+}
+
+// StoreReplicas streams a batch of replica copies to a remote node via the
+// Store RPC. Unlike StoreRemote, the remote node stores them without
+// checking that it owns their keys. Return values are as for StoreRemote.
+func StoreReplicas(ctx context.Context, client pb.DHTClient, resources []domain.Resource) ([]domain.Resource, error) {
+	return store(ctx, client, resources, true, domain.Condition{})
+}
+
+// storeChunkBytes is the largest value chunk sent in a single StoreRequest
+// frame, well below the 4 MiB default gRPC message limit.
+const storeChunkBytes = 1 << 20
+
+// splitValue splits v into chunks of at most size bytes, cutting only at
+// UTF-8 rune boundaries (proto3 strings must be valid UTF-8). An empty
+// value yields a single empty chunk.
+func splitValue(v string, size int) []string {
+	chunks := make([]string, 0, len(v)/size+1)
+	for len(v) > size {
+		cut := size
+		for cut > 0 && !utf8.RuneStart(v[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = size // not UTF-8: any cut will do
+		}
+		chunks = append(chunks, v[:cut])
+		v = v[cut:]
+	}
+	return append(chunks, v)
+}
+
+func store(ctx context.Context, client pb.DHTClient, resources []domain.Resource, replica bool, cond domain.Condition) ([]domain.Resource, error) {
 	// Check for canceled/expired context
 	if err := ctxutil.CheckContext(ctx); err != nil {
 		return nil, err
@@ -252,25 +384,46 @@ func StoreRemote(ctx context.Context, client pb.DHTClient, resources []domain.Re
 
 	var failed []domain.Resource
 
-	// Send each resource
+	// Send each resource, its value split in chunks of at most storeChunkBytes
 	for _, res := range resources {
-		req := &pb.StoreRequest{
-			Resource: res.ToProtoDHT(),
-		}
-		if err := stream.Send(req); err != nil {
-			// Mark as failed, continue with others
-			failed = append(failed, res)
+		chunks := splitValue(res.Value, storeChunkBytes)
+		for i, chunk := range chunks {
+			req := &pb.StoreRequest{
+				Resource: &pb.Resource{Value: chunk},
+				Replica:  replica,
+				Seq:      uint32(i),
+				More:     i < len(chunks)-1,
+			}
+			if i == 0 {
+				req.Resource = res.ToProtoDHT()
+				req.Resource.Value = chunk
+				req.Condition = cond.ToProtoDHT()
+			}
+			if err := stream.Send(req); err != nil {
+				// Mark as failed, continue with others
+				failed = append(failed, res)
+				break
+			}
 		}
 	}
 
 	// Close and wait for server ack
-	_, err = stream.CloseAndRecv()
+	resp, err := stream.CloseAndRecv()
 	if err != nil {
 		if st, ok := status.FromError(err); ok && st.Code() == codes.DeadlineExceeded {
 			return nil, ErrTimeout
 		}
+		if status.Code(err) == codes.FailedPrecondition {
+			return resources, fmt.Errorf("client: store stream failed: %w: %w", domain.ErrPreconditionFailed, err)
+		}
 		return resources, fmt.Errorf("client: store stream failed: %w", err)
 	}
+	// A replica copy keeps the version of the owner it was sent with
+	if versions := resp.GetVersions(); !replica && len(versions) == len(resources) {
+		for i, v := range versions {
+			resources[i].Version = v
+		}
+	}
 
 	return failed, nil
 }
@@ -347,6 +500,114 @@ func RemoveRemote(ctx context.Context, client pb.DHTClient, key domain.ID) error
 	return nil
 }
 
+// RemoveBatchRemote sends a RemoveBatch RPC to the given remote node to
+// delete several resources in a single round trip.
+//
+// The caller must provide a ready-to-use gRPC client.
+// This function does not manage client connection pooling or closing.
+//
+// Returns:
+//   - one flag per key, in order: true if the key was removed, false if
+//     the remote node did not store it
+//   - ErrTimeout if the RPC timed out
+//   - a wrapped RPC error otherwise (no key is known to be removed)
+func RemoveBatchRemote(ctx context.Context, client pb.DHTClient, keys []domain.ID) ([]bool, error) {
+	// Check for canceled/expired context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	req := &pb.RemoveBatchRequest{Keys: make([][]byte, len(keys))}
+	for i, k := range keys {
+		req.Keys[i] = k
+	}
+
+	// Perform the RPC
+	resp, err := client.RemoveBatch(ctx, req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, ErrTimeout
+		}
+		return nil, fmt.Errorf("client: RemoveBatch RPC failed: %w", err)
+	}
+	if len(resp.Removed) != len(keys) {
+		return nil, fmt.Errorf("client: RemoveBatch returned %d results for %d keys", len(resp.Removed), len(keys))
+	}
+	return resp.Removed, nil
+}
+
+// RemoveRangeRemote sends a RemoveRange RPC to the given remote node to
+// delete every resource it stores with key in (from, to].
+//
+// The caller must provide a ready-to-use gRPC client.
+// This function does not manage client connection pooling or closing.
+//
+// Returns:
+//   - the number of resources removed
+//   - ErrTimeout if the RPC timed out
+//   - a wrapped RPC error otherwise
+func RemoveRangeRemote(ctx context.Context, client pb.DHTClient, from, to domain.ID) (int, error) {
+	// Check for canceled/expired context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return 0, err
+	}
+
+	// Perform the RPC
+	resp, err := client.RemoveRange(ctx, &pb.RemoveRangeRequest{From: from, To: to})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, ErrTimeout
+		}
+		return 0, fmt.Errorf("client: RemoveRange RPC failed: %w", err)
+	}
+	return int(resp.GetRemoved()), nil
+}
+
+// RetrieveRangeRemote pulls from the given remote node a copy of every
+// resource it stores with key in (from, to]. The remote node keeps its copy.
+//
+// The caller must provide a ready-to-use gRPC client.
+// This function does not manage client connection pooling or closing.
+//
+// Returns:
+//   - the resources received (possibly empty)
+//   - ErrTimeout if the RPC timed out
+//   - a wrapped RPC error otherwise
+func RetrieveRangeRemote(ctx context.Context, client pb.DHTClient, sp *domain.Space, from, to domain.ID) ([]domain.Resource, error) {
+	// Check for canceled/expired context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Open the server stream
+	stream, err := client.RetrieveRange(ctx, &pb.RetrieveRangeRequest{From: from, To: to})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, ErrTimeout
+		}
+		return nil, fmt.Errorf("client: RetrieveRange RPC failed: %w", err)
+	}
+
+	var resources []domain.Resource
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return resources, nil
+		}
+		if err != nil {
+			if st, ok := status.FromError(err); ok && st.Code() == codes.DeadlineExceeded {
+				return nil, ErrTimeout
+			}
+			return nil, fmt.Errorf("client: RetrieveRange stream failed: %w", err)
+		}
+		res, convErr := domain.ResourceFromProtoDHT(sp, resp.Resource)
+		if convErr != nil {
+			return nil, fmt.Errorf("client: failed to convert resource: %w", convErr)
+		}
+		resources = append(resources, *res)
+	}
+}
+
 // Leave sends a Leave RPC to the given remote node to inform it that this node is leaving the DHT.
 //
 // The caller must provide a ready-to-use gRPC client.
@@ -375,3 +636,132 @@ func Leave(ctx context.Context, client pb.DHTClient, self *domain.Node) error {
 	}
 	return nil
 }
+
+// PredecessorLeaving sends a PredecessorLeaving RPC to the predecessor of
+// this node to inform it that this node (self) is leaving the DHT and that
+// succs should replace it in the predecessor's successor list.
+//
+// The caller must provide a ready-to-use gRPC client.
+// This function does not manage client connection pooling or closing.
+//
+// Returns:
+//   - nil on success
+//   - ErrTimeout if the RPC timed out
+//   - a wrapped RPC error otherwise
+func PredecessorLeaving(ctx context.Context, client pb.DHTClient, self *domain.Node, succs []*domain.Node) error {
+	// Check for canceled/expired context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return err
+	}
+
+	// Build the request
+	req := &pb.PredecessorLeavingRequest{Leaving: self.ToProtoDHT()}
+	for _, nd := range succs {
+		if nd != nil {
+			req.Successors = append(req.Successors, nd.ToProtoDHT())
+		}
+	}
+
+	// Perform the RPC
+	_, err := client.PredecessorLeaving(ctx, req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ErrTimeout
+		}
+		return fmt.Errorf("client: PredecessorLeaving RPC failed: %w", err)
+	}
+	return nil
+}
+
+// SyncDigest asks the given remote node for the hashes of the nodes at
+// level, with the given indices, of the Merkle tree of depth levels over
+// the resources it stores with key in (from, to] (see storage.MerkleTree).
+//
+// The caller must provide a ready-to-use gRPC client.
+// This function does not manage client connection pooling or closing.
+//
+// Returns:
+//   - one hash per index, in order (nil = empty subtree)
+//   - ErrTimeout if the RPC timed out
+//   - a wrapped RPC error otherwise
+func SyncDigest(ctx context.Context, client pb.DHTClient, from, to domain.ID, depth, level int, indices []int) ([][]byte, error) {
+	resp, err := syncDigest(ctx, client, &pb.SyncDigestRequest{
+		From:    from,
+		To:      to,
+		Depth:   uint32(depth),
+		Level:   uint32(level),
+		Indices: toUint32s(indices),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Hashes) != len(indices) {
+		return nil, fmt.Errorf("client: SyncDigest returned %d hashes for %d nodes", len(resp.Hashes), len(indices))
+	}
+	hashes := make([][]byte, len(resp.Hashes))
+	for i, h := range resp.Hashes {
+		if len(h) > 0 {
+			hashes[i] = h
+		}
+	}
+	return hashes, nil
+}
+
+// SyncEntries asks the given remote node for the entries of the given
+// leaves of the Merkle tree of depth levels over the resources it stores
+// with key in (from, to] (see SyncDigest).
+//
+// The caller must provide a ready-to-use gRPC client.
+// This function does not manage client connection pooling or closing.
+//
+// Returns:
+//   - the entries, by leaf index
+//   - ErrTimeout if the RPC timed out
+//   - a wrapped RPC error otherwise
+func SyncEntries(ctx context.Context, client pb.DHTClient, sp *domain.Space, from, to domain.ID, depth int, leaves []int) (map[int][]storage.MerkleEntry, error) {
+	resp, err := syncDigest(ctx, client, &pb.SyncDigestRequest{
+		From:    from,
+		To:      to,
+		Depth:   uint32(depth),
+		Level:   uint32(depth),
+		Indices: toUint32s(leaves),
+		Entries: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[int][]storage.MerkleEntry)
+	for _, e := range resp.Entries {
+		if err := sp.IsValidID(e.Key); err != nil {
+			return nil, fmt.Errorf("client: SyncDigest returned an invalid key: %w", err)
+		}
+		leaf := int(e.Leaf)
+		entries[leaf] = append(entries[leaf], storage.MerkleEntry{Key: domain.ID(e.Key), Digest: e.Digest})
+	}
+	return entries, nil
+}
+
+func syncDigest(ctx context.Context, client pb.DHTClient, req *pb.SyncDigestRequest) (*pb.SyncDigestResponse, error) {
+	// Check for canceled/expired context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Perform the RPC
+	resp, err := client.SyncDigest(ctx, req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, ErrTimeout
+		}
+		return nil, fmt.Errorf("client: SyncDigest RPC failed: %w", err)
+	}
+	return resp, nil
+}
+
+func toUint32s(xs []int) []uint32 {
+	out := make([]uint32, len(xs))
+	for i, x := range xs {
+		out[i] = uint32(x)
+	}
+	return out
+}
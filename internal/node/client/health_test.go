@@ -0,0 +1,80 @@
+package client_test
+
+import (
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/testring"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestHealthCheckEviction verifica che il loop di health check rimuova dal
+// pool le connessioni verso nodi morti, lasci intatte quelle sane e che il
+// conteggio dei riferimenti resti coerente quando l'indirizzo viene riaggiunto.
+func TestHealthCheckEviction(t *testing.T) {
+	r := testring.New(t, 3)
+	dead, alive := r.Members[0], r.Members[1]
+
+	p := client.New(r.Space.NewIdFromString("probe"), "127.0.0.1:1", 200*time.Millisecond,
+		client.WithHealthCheck(20*time.Millisecond, 2))
+	t.Cleanup(func() { _ = p.Close() })
+
+	// due riferimenti per ciascun nodo, come un nodo che compare in più ruoli
+	for _, m := range []*testring.Member{dead, alive} {
+		for range 2 {
+			if err := p.AddRef(m.Addr); err != nil {
+				t.Fatalf("AddRef(%s): %v", m.Addr, err)
+			}
+		}
+	}
+
+	// con entrambi i nodi vivi nessuna connessione deve essere rimossa
+	time.Sleep(200 * time.Millisecond)
+	if got := p.Size(); got != 2 {
+		t.Fatalf("Size con nodi sani = %d, atteso 2", got)
+	}
+
+	r.Kill(dead)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := p.GetFromPool(dead.Addr)
+		if errors.Is(err, client.ErrNoConnInPool) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("connessione verso %s non rimossa (err = %v)", dead.Addr, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := p.Size(); got != 1 {
+		t.Fatalf("Size dopo la rimozione = %d, atteso 1", got)
+	}
+	if _, err := p.GetFromPool(alive.Addr); err != nil {
+		t.Fatalf("connessione sana rimossa: %v", err)
+	}
+
+	// uno dei due riferimenti precedenti viene rilasciato dopo la rimozione,
+	// l'altro è ancora in mano al suo proprietario quando l'indirizzo viene
+	// riaggiunto: la nuova connessione deve sopravvivere a un solo Release
+	if err := p.Release(dead.Addr); err != nil {
+		t.Fatalf("Release dopo la rimozione: %v", err)
+	}
+	if err := p.AddRef(dead.Addr); err != nil {
+		t.Fatalf("AddRef dopo la rimozione: %v", err)
+	}
+	if err := p.Release(dead.Addr); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := p.GetFromPool(dead.Addr); err != nil {
+		t.Fatalf("connessione riaggiunta chiusa con un riferimento ancora attivo: %v", err)
+	}
+	if err := p.Release(dead.Addr); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := p.GetFromPool(dead.Addr); !errors.Is(err, client.ErrNoConnInPool) {
+		t.Fatalf("connessione ancora nel pool dopo l'ultimo Release (err = %v)", err)
+	}
+	if got := p.Size(); got != 1 {
+		t.Fatalf("Size finale = %d, atteso 1", got)
+	}
+}
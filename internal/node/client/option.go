@@ -2,6 +2,12 @@ package client
 
 import (
 	"KoordeDHT/internal/logger"
+	"crypto/tls"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
 )
 
 type Option func(pool *Pool)
@@ -12,3 +18,106 @@ func WithLogger(l logger.Logger) Option {
 		p.lgr = l
 	}
 }
+
+// WithUnaryInterceptors appends user-supplied unary client interceptors
+// to every connection created by the Pool (pooled and ephemeral).
+//
+// Ordering: the built-in interceptors (the peer flag, see
+// ctxutil.WithPeer, then lookuptrace) run first, followed by the user
+// interceptors in the order given (the first one is the outermost); the
+// otelgrpc stats handler observes the RPC at the transport level, after
+// the whole chain. Multiple calls accumulate.
+func WithUnaryInterceptors(ints ...grpc.UnaryClientInterceptor) Option {
+	return func(p *Pool) {
+		p.unaryInts = append(p.unaryInts, ints...)
+	}
+}
+
+// WithStreamInterceptors appends user-supplied stream client interceptors
+// to every connection created by the Pool. Ordering follows the same rules
+// as WithUnaryInterceptors (the only built-in stream interceptor sets the
+// peer flag).
+func WithStreamInterceptors(ints ...grpc.StreamClientInterceptor) Option {
+	return func(p *Pool) {
+		p.streamInts = append(p.streamInts, ints...)
+	}
+}
+
+// WithCompression enables message compression on every outbound call made
+// through the Pool (pooled and ephemeral connections). Supported values are
+// "gzip" and "none" (default); any other value disables compression.
+// Servers answer compressed requests with the same compressor, so the
+// responses (e.g. successor lists, range pulls) are compressed as well.
+func WithCompression(name string) Option {
+	return func(p *Pool) {
+		switch name {
+		case gzip.Name:
+			p.compressor = gzip.Name
+		default:
+			p.compressor = ""
+		}
+	}
+}
+
+// WithTLS makes every connection created by the Pool (pooled and
+// ephemeral) use TLS with the given client configuration (see
+// security.ClientTLS). A nil configuration keeps the plaintext transport.
+func WithTLS(cfg *tls.Config) Option {
+	return func(p *Pool) {
+		p.tlsConfig = cfg
+	}
+}
+
+// WithMaxRecvMsgSize raises the maximum size of the responses received
+// on every connection created by the Pool, so that the Retrieve of values
+// larger than the gRPC default (4 MiB) succeeds. Stores are not affected:
+// their values are streamed in chunks. n <= 0 keeps the gRPC default.
+func WithMaxRecvMsgSize(n int) Option {
+	return func(p *Pool) {
+		p.maxRecvMsgSize = max(n, 0)
+	}
+}
+
+// WithHealthCheck starts a background loop that pings every pooled
+// connection each interval (with the pool failure timeout) and evicts the
+// connections that fail threshold consecutive pings: they are closed and
+// removed, so GetFromPool returns ErrNoConnInPool and callers re-dial.
+// A non-positive interval disables the loop (default); thresholds below
+// 1 are raised to 1. The loop stops when the pool is closed.
+func WithHealthCheck(interval time.Duration, threshold int) Option {
+	return func(p *Pool) {
+		p.healthInterval = interval
+		p.healthThreshold = max(threshold, 1)
+	}
+}
+
+// WithKeepalive makes every connection created by the Pool send a
+// keepalive ping after idle without activity and close the connection if
+// the ping is not acknowledged within timeout, so that pooled connections
+// silently dropped by a NAT or load balancer are detected and re-dialed.
+// With permitWithoutStream the pings are sent also when no RPC is in
+// flight, as on an idle pooled connection. gRPC raises times below 10s to
+// 10s; the servers must accept pings that frequent (see
+// server.WithKeepalive). A non-positive idle disables keepalive (default).
+func WithKeepalive(idle, timeout time.Duration, permitWithoutStream bool) Option {
+	return func(p *Pool) {
+		p.keepalive = keepalive.ClientParameters{
+			Time:                idle,
+			Timeout:             timeout,
+			PermitWithoutStream: permitWithoutStream,
+		}
+	}
+}
+
+// WithDialTimeout bounds every connection attempt of the Pool (pooled and
+// ephemeral) to timeout, instead of the gRPC default of 20s; a
+// non-positive timeout uses the failure timeout of the pool (default).
+// With failFast, DialEphemeral also pings the node within timeout before
+// returning the connection, so that operations such as Join fail right
+// away against an unreachable address.
+func WithDialTimeout(timeout time.Duration, failFast bool) Option {
+	return func(p *Pool) {
+		p.dialTimeout = timeout
+		p.failFast = failFast
+	}
+}
@@ -0,0 +1,56 @@
+package client_test
+
+import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TestStoreChunksLargeValues verifica che i valori oltre il limite di 4 MiB
+// dei messaggi gRPC vengano inviati a blocchi e ricomposti dal server, anche
+// quando i tagli cadono in mezzo a caratteri multibyte.
+func TestStoreChunksLargeValues(t *testing.T) {
+	r := testring.New(t, 1)
+	conn, err := grpc.NewClient(r.Members[0].Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	cli := dhtv1.NewDHTClient(conn)
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "small", value: "v"},
+		{name: "ascii", value: strings.Repeat("x", 5<<20)},
+		// il carattere da 2 byte sfasato di uno: nessun blocco termina su un confine di runa
+		{name: "multibyte", value: "x" + strings.Repeat("è", 3<<20)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := domain.Resource{Key: r.Space.NewIdFromString(tt.name), RawKey: tt.name, Value: tt.value}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			failed, err := client.StoreRemote(ctx, cli, []domain.Resource{res})
+			if err != nil || len(failed) > 0 {
+				t.Fatalf("StoreRemote: failed %d, %v", len(failed), err)
+			}
+			got, err := r.Members[0].Node.RetrieveLocal(res.Key)
+			if err != nil {
+				t.Fatalf("RetrieveLocal: %v", err)
+			}
+			if got.Value != tt.value {
+				t.Fatalf("stored value of %d bytes, want %d", len(got.Value), len(tt.value))
+			}
+		})
+	}
+}
@@ -2,10 +2,14 @@ package config
 
 import (
 	"KoordeDHT/internal/configloader"
+	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/security"
 	"fmt"
 	"math/bits"
 	"net"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -16,39 +20,153 @@ type TracingConfig struct {
 	Endpoint string `yaml:"endpoint"`
 }
 
+type MetricsConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	Port           int           `yaml:"port"`
+	OTLP           bool          `yaml:"otlp"`
+	ExportInterval time.Duration `yaml:"exportInterval"`
+}
+
 type TelemetryConfig struct {
-	Tracing TracingConfig `yaml:"tracing"`
+	Tracing         TracingConfig `yaml:"tracing"`
+	Metrics         MetricsConfig `yaml:"metrics"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
 }
 
 type DeBruijnConfig struct {
-	Degree      int           `yaml:"degree"`
-	FixInterval time.Duration `yaml:"fixInterval"`
+	Degree        int           `yaml:"degree"`
+	FixInterval   time.Duration `yaml:"fixInterval"`
+	MaxRefreshAge time.Duration `yaml:"maxRefreshAge"`
+	BackupSize    int           `yaml:"backupSize"`
 }
 
 type FaultToleranceConfig struct {
 	SuccessorListSize     int           `yaml:"successorListSize"`
 	StabilizationInterval time.Duration `yaml:"stabilizationInterval"`
 	FailureTimeout        time.Duration `yaml:"failureTimeout"`
+	AdaptiveSuccessorList bool          `yaml:"adaptiveSuccessorList"`
+	MinSuccessorListSize  int           `yaml:"minSuccessorListSize"`
+	MaxSuccessorListSize  int           `yaml:"maxSuccessorListSize"`
+	MinInterval           time.Duration `yaml:"minInterval"`
+	MaxInterval           time.Duration `yaml:"maxInterval"`
+	AsymmetryRounds       int           `yaml:"asymmetryRounds"`
+	AsymmetryRenotify     bool          `yaml:"asymmetryRenotify"`
+	Jitter                float64       `yaml:"jitter"`
+	DialTimeout           time.Duration `yaml:"dialTimeout"`
+	DialFailFast          bool          `yaml:"dialFailFast"`
 }
 
 type StorageConfig struct {
-	FixInterval time.Duration `yaml:"fixInterval"`
+	FixInterval   time.Duration `yaml:"fixInterval"`
+	PullOnJoin    bool          `yaml:"pullOnJoin"`
+	Checksum      bool          `yaml:"checksum"`
+	Replicas      int           `yaml:"replicas"`
+	ReadRepair    bool          `yaml:"readRepair"`
+	SweepInterval time.Duration `yaml:"sweepInterval"`
+	QuotaBytes    int64         `yaml:"quotaBytes"`
+	MaxKeys       int           `yaml:"maxKeys"`
+	Eviction      string        `yaml:"eviction"`
+	MaxValueBytes int64         `yaml:"maxValueBytes"`
+	Backend       string        `yaml:"backend"`
+	Path          string        `yaml:"path"`
+	// NoPredecessor is what a node does with the stores it receives while
+	// its predecessor is unknown but its successor is another node:
+	// accept, repair or reject (see logicnode.WithNoPredecessorPolicy).
+	NoPredecessor string `yaml:"noPredecessor"`
+
+	AntiEntropyInterval time.Duration `yaml:"antiEntropyInterval"`
+	AntiEntropyDepth    int           `yaml:"antiEntropyDepth"`
+}
+
+type CompressionConfig struct {
+	GRPC string `yaml:"grpc"`
+}
+
+type GRPCConfig struct {
+	Keepalive KeepaliveConfig `yaml:"keepalive"`
+	// MaxConcurrentRequests bounds the client RPCs served at once, the
+	// excess being rejected with ResourceExhausted (0 = unlimited). The
+	// RPCs between nodes (stabilization, forwarded lookups, replicas) have
+	// their own budget, MaxConcurrentMaintenance (0 = twice
+	// MaxConcurrentRequests).
+	MaxConcurrentRequests    int `yaml:"maxConcurrentRequests"`
+	MaxConcurrentMaintenance int `yaml:"maxConcurrentMaintenance"`
+	// MethodLimits gives single methods their own budget, by method name
+	// ("Put") or full name ("/client.v1.ClientAPI/Put"); 0 = unlimited.
+	MethodLimits map[string]int `yaml:"methodLimits"`
+}
+
+type KeepaliveConfig struct {
+	Time                time.Duration `yaml:"time"`
+	Timeout             time.Duration `yaml:"timeout"`
+	PermitWithoutStream bool          `yaml:"permitWithoutStream"`
+}
+
+type RoutingConfig struct {
+	DeBruijn bool `yaml:"deBruijn"`
+}
+
+type LookupConfig struct {
+	HopReserve     time.Duration `yaml:"hopReserve"`
+	MinHopBudget   time.Duration `yaml:"minHopBudget"`
+	OwnerReserve   float64       `yaml:"ownerReserve"`
+	MaxRetries     int           `yaml:"maxRetries"`
+	RetryBaseDelay time.Duration `yaml:"retryBaseDelay"`
+	OwnerRetries   int           `yaml:"ownerRetries"`
+	MaxLocalDepth  int           `yaml:"maxLocalDepth"`
+	MaxHops        int           `yaml:"maxHops"`
+	Parallelism    int           `yaml:"parallelism"`
+	MaxOutbound    int           `yaml:"maxOutbound"`
+	CacheSize      int           `yaml:"cacheSize"`
+	CacheTTL       time.Duration `yaml:"cacheTTL"`
+}
+
+type CatchUpConfig struct {
+	Interval  time.Duration `yaml:"interval"`
+	MaxRounds int           `yaml:"maxRounds"`
 }
 
 type DHTConfig struct {
 	IDBits         int                          `yaml:"idBits"`
 	Mode           string                       `yaml:"mode"`
+	LookupMode     string                       `yaml:"lookupMode"`
+	Namespace      string                       `yaml:"namespace"`
+	Hash           string                       `yaml:"hash"`
+	VNodes         int                          `yaml:"vnodes"`
 	DeBruijn       DeBruijnConfig               `yaml:"deBruijn"`
 	FaultTolerance FaultToleranceConfig         `yaml:"faultTolerance"`
 	Storage        StorageConfig                `yaml:"storage"`
+	Compression    CompressionConfig            `yaml:"compression"`
+	GRPC           GRPCConfig                   `yaml:"grpc"`
+	Routing        RoutingConfig                `yaml:"routing"`
+	Lookup         LookupConfig                 `yaml:"lookup"`
+	CatchUp        CatchUpConfig                `yaml:"catchUp"`
 	Bootstrap      configloader.BootstrapConfig `yaml:"bootstrap"`
 }
 
 type NodeConfig struct {
-	Id   string `yaml:"id"`
-	Bind string `yaml:"bind"`
-	Host string `yaml:"host"`
-	Port int    `yaml:"port"`
+	Id         string `yaml:"id"`
+	IdFile     string `yaml:"idFile"`
+	IdStrategy string `yaml:"idStrategy"`
+	Role       string `yaml:"role"`
+	Bind       string `yaml:"bind"`
+	Host       string `yaml:"host"`
+	Port       int    `yaml:"port"`
+	// IPVersion is the family of the advertised address picked when Host
+	// is empty: v4, v6 (global unicast IPv6) or auto (IPv4 if the host has
+	// one, else IPv6).
+	IPVersion string `yaml:"ipVersion"`
+	// VerifySelfReachable makes the node ping its own advertised address at
+	// startup and exit, without registering, if the ping does not arrive.
+	VerifySelfReachable bool `yaml:"verifySelfReachable"`
+	// RoutingFile is the file the routing table is saved to, and restored
+	// from at startup instead of joining if its neighbors are still alive.
+	RoutingFile         string        `yaml:"routingFile"`
+	RoutingSaveInterval time.Duration `yaml:"routingSaveInterval"`
+	// AdminRPC enables the admin RPCs of the client API (Shutdown), which
+	// let any client make the node leave the ring: keep it disabled on
+	// nodes reachable by untrusted clients.
+	AdminRPC bool `yaml:"adminRPC"`
 }
 
 type Config struct {
@@ -56,10 +174,20 @@ type Config struct {
 	DHT       DHTConfig                 `yaml:"dht"`
 	Node      NodeConfig                `yaml:"node"`
 	Telemetry TelemetryConfig           `yaml:"telemetry"`
+	Security  security.Config           `yaml:"security"`
 }
 
+// LoadConfig loads the configuration from path, a file path or a file://,
+// http:// or https:// URI (see configloader.LoadYAML), then applies the
+// environment overrides and the defaults.
 func LoadConfig(path string) (*Config, error) {
-	cfg := &Config{}
+	cfg := &Config{ // defaults when omitted from the file
+		DHT: DHTConfig{
+			Routing: RoutingConfig{DeBruijn: true},
+			Storage: StorageConfig{PullOnJoin: true},
+			Lookup:  LookupConfig{HopReserve: 2 * time.Millisecond, MinHopBudget: 5 * time.Millisecond},
+		},
+	}
 	// Load from YAML file
 	if err := configloader.LoadYAML(path, cfg); err != nil {
 		return nil, fmt.Errorf("load config: %w", err)
@@ -67,33 +195,126 @@ func LoadConfig(path string) (*Config, error) {
 
 	// Override with environment variables
 	configloader.OverrideString(&cfg.Node.Id, "NODE_ID")
+	configloader.OverrideString(&cfg.Node.IdFile, "NODE_ID_FILE")
+	configloader.OverrideString(&cfg.Node.RoutingFile, "NODE_ROUTING_FILE")
+	configloader.OverrideDuration(&cfg.Node.RoutingSaveInterval, "NODE_ROUTING_SAVE_INTERVAL")
+	configloader.OverrideString(&cfg.Node.IdStrategy, "NODE_ID_STRATEGY")
+	configloader.OverrideString(&cfg.Node.Role, "NODE_ROLE")
 	configloader.OverrideString(&cfg.Node.Bind, "NODE_BIND")
 	configloader.OverrideString(&cfg.Node.Host, "NODE_HOST")
 	configloader.OverrideInt(&cfg.Node.Port, "NODE_PORT")
+	configloader.OverrideString(&cfg.Node.IPVersion, "NODE_IP_VERSION")
+	configloader.OverrideBool(&cfg.Node.VerifySelfReachable, "NODE_VERIFY_SELF_REACHABLE")
+	configloader.OverrideBool(&cfg.Node.AdminRPC, "NODE_ADMIN_RPC")
 
 	configloader.OverrideString(&cfg.DHT.Mode, "DHT_MODE")
+	configloader.OverrideString(&cfg.DHT.LookupMode, "LOOKUP_MODE")
 	configloader.OverrideInt(&cfg.DHT.IDBits, "DHT_ID_BITS")
+	configloader.OverrideString(&cfg.DHT.Namespace, "DHT_NAMESPACE")
+	configloader.OverrideString(&cfg.DHT.Hash, "DHT_HASH")
+	configloader.OverrideInt(&cfg.DHT.VNodes, "DHT_VNODES")
 
 	configloader.OverrideInt(&cfg.DHT.DeBruijn.Degree, "DEBRUIJN_DEGREE")
 	configloader.OverrideDuration(&cfg.DHT.DeBruijn.FixInterval, "DEBRUIJN_FIX_INTERVAL")
+	configloader.OverrideDuration(&cfg.DHT.DeBruijn.MaxRefreshAge, "DEBRUIJN_MAX_REFRESH_AGE")
+	configloader.OverrideInt(&cfg.DHT.DeBruijn.BackupSize, "DEBRUIJN_BACKUP_SIZE")
 
 	configloader.OverrideInt(&cfg.DHT.FaultTolerance.SuccessorListSize, "SUCCESSOR_LIST_SIZE")
 	configloader.OverrideDuration(&cfg.DHT.FaultTolerance.StabilizationInterval, "STABILIZATION_INTERVAL")
 	configloader.OverrideDuration(&cfg.DHT.FaultTolerance.FailureTimeout, "FAILURE_TIMEOUT")
+	configloader.OverrideBool(&cfg.DHT.FaultTolerance.AdaptiveSuccessorList, "ADAPTIVE_SUCCESSOR_LIST")
+	configloader.OverrideInt(&cfg.DHT.FaultTolerance.MinSuccessorListSize, "MIN_SUCCESSOR_LIST_SIZE")
+	configloader.OverrideInt(&cfg.DHT.FaultTolerance.MaxSuccessorListSize, "MAX_SUCCESSOR_LIST_SIZE")
+	configloader.OverrideDuration(&cfg.DHT.FaultTolerance.MinInterval, "MIN_STABILIZATION_INTERVAL")
+	configloader.OverrideDuration(&cfg.DHT.FaultTolerance.MaxInterval, "MAX_STABILIZATION_INTERVAL")
+	configloader.OverrideInt(&cfg.DHT.FaultTolerance.AsymmetryRounds, "ASYMMETRY_ROUNDS")
+	configloader.OverrideBool(&cfg.DHT.FaultTolerance.AsymmetryRenotify, "ASYMMETRY_RENOTIFY")
+	configloader.OverrideFloat(&cfg.DHT.FaultTolerance.Jitter, "STABILIZATION_JITTER")
+	configloader.OverrideDuration(&cfg.DHT.FaultTolerance.DialTimeout, "DIAL_TIMEOUT")
+	configloader.OverrideBool(&cfg.DHT.FaultTolerance.DialFailFast, "DIAL_FAIL_FAST")
 
 	configloader.OverrideDuration(&cfg.DHT.Storage.FixInterval, "STORAGE_FIX_INTERVAL")
+	configloader.OverrideBool(&cfg.DHT.Storage.PullOnJoin, "STORAGE_PULL_ON_JOIN")
+	configloader.OverrideBool(&cfg.DHT.Storage.Checksum, "STORAGE_CHECKSUM")
+	configloader.OverrideInt(&cfg.DHT.Storage.Replicas, "STORAGE_REPLICAS")
+	configloader.OverrideBool(&cfg.DHT.Storage.ReadRepair, "STORAGE_READ_REPAIR")
+	configloader.OverrideDuration(&cfg.DHT.Storage.SweepInterval, "STORAGE_SWEEP_INTERVAL")
+	configloader.OverrideInt64(&cfg.DHT.Storage.QuotaBytes, "STORAGE_QUOTA_BYTES")
+	configloader.OverrideInt(&cfg.DHT.Storage.MaxKeys, "STORAGE_MAX_KEYS")
+	configloader.OverrideString(&cfg.DHT.Storage.Eviction, "STORAGE_EVICTION")
+	configloader.OverrideInt64(&cfg.DHT.Storage.MaxValueBytes, "STORAGE_MAX_VALUE_BYTES")
+	configloader.OverrideString(&cfg.DHT.Storage.Backend, "STORAGE_BACKEND")
+	configloader.OverrideString(&cfg.DHT.Storage.Path, "STORAGE_PATH")
+	configloader.OverrideString(&cfg.DHT.Storage.NoPredecessor, "STORAGE_NO_PREDECESSOR")
+	configloader.OverrideDuration(&cfg.DHT.Storage.AntiEntropyInterval, "STORAGE_ANTI_ENTROPY_INTERVAL")
+	configloader.OverrideInt(&cfg.DHT.Storage.AntiEntropyDepth, "STORAGE_ANTI_ENTROPY_DEPTH")
+	configloader.OverrideString(&cfg.DHT.Compression.GRPC, "COMPRESSION_GRPC")
+	configloader.OverrideDuration(&cfg.DHT.GRPC.Keepalive.Time, "GRPC_KEEPALIVE_TIME")
+	configloader.OverrideDuration(&cfg.DHT.GRPC.Keepalive.Timeout, "GRPC_KEEPALIVE_TIMEOUT")
+	configloader.OverrideBool(&cfg.DHT.GRPC.Keepalive.PermitWithoutStream, "GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM")
+	configloader.OverrideInt(&cfg.DHT.GRPC.MaxConcurrentRequests, "GRPC_MAX_CONCURRENT_REQUESTS")
+	configloader.OverrideInt(&cfg.DHT.GRPC.MaxConcurrentMaintenance, "GRPC_MAX_CONCURRENT_MAINTENANCE")
+
+	configloader.OverrideBool(&cfg.DHT.Routing.DeBruijn, "ROUTING_DE_BRUIJN")
+	configloader.OverrideDuration(&cfg.DHT.Lookup.HopReserve, "LOOKUP_HOP_RESERVE")
+	configloader.OverrideDuration(&cfg.DHT.Lookup.MinHopBudget, "LOOKUP_MIN_HOP_BUDGET")
+	configloader.OverrideFloat(&cfg.DHT.Lookup.OwnerReserve, "LOOKUP_OWNER_RESERVE")
+	configloader.OverrideInt(&cfg.DHT.Lookup.MaxRetries, "LOOKUP_MAX_RETRIES")
+	configloader.OverrideDuration(&cfg.DHT.Lookup.RetryBaseDelay, "LOOKUP_RETRY_BASE_DELAY")
+	configloader.OverrideInt(&cfg.DHT.Lookup.OwnerRetries, "LOOKUP_OWNER_RETRIES")
+	configloader.OverrideInt(&cfg.DHT.Lookup.MaxLocalDepth, "LOOKUP_MAX_LOCAL_DEPTH")
+	configloader.OverrideInt(&cfg.DHT.Lookup.MaxHops, "LOOKUP_MAX_HOPS")
+	configloader.OverrideInt(&cfg.DHT.Lookup.Parallelism, "LOOKUP_PARALLELISM")
+	configloader.OverrideInt(&cfg.DHT.Lookup.MaxOutbound, "LOOKUP_MAX_OUTBOUND")
+	configloader.OverrideInt(&cfg.DHT.Lookup.CacheSize, "LOOKUP_CACHE_SIZE")
+	configloader.OverrideDuration(&cfg.DHT.Lookup.CacheTTL, "LOOKUP_CACHE_TTL")
+
+	configloader.OverrideDuration(&cfg.DHT.CatchUp.Interval, "CATCHUP_INTERVAL")
+	configloader.OverrideInt(&cfg.DHT.CatchUp.MaxRounds, "CATCHUP_MAX_ROUNDS")
 
 	configloader.OverrideString(&cfg.DHT.Bootstrap.Mode, "BOOTSTRAP_MODE")
 	configloader.OverrideStringSlice(&cfg.DHT.Bootstrap.Peers, "BOOTSTRAP_PEERS") // comma-separated list
+	configloader.OverrideBool(&cfg.DHT.Bootstrap.RequireRegistration, "BOOTSTRAP_REQUIRE_REGISTRATION")
+	configloader.OverrideBool(&cfg.DHT.Bootstrap.GateUntilRegistered, "BOOTSTRAP_GATE_UNTIL_REGISTERED")
+	configloader.OverrideInt(&cfg.DHT.Bootstrap.Retry.Attempts, "BOOTSTRAP_RETRY_ATTEMPTS")
+	configloader.OverrideDuration(&cfg.DHT.Bootstrap.Retry.Timeout, "BOOTSTRAP_RETRY_TIMEOUT")
+	configloader.OverrideDuration(&cfg.DHT.Bootstrap.Retry.Backoff, "BOOTSTRAP_RETRY_BACKOFF")
+	configloader.OverrideInt(&cfg.DHT.Bootstrap.JoinRetries, "BOOTSTRAP_JOIN_RETRIES")
+	configloader.OverrideDuration(&cfg.DHT.Bootstrap.JoinRetryInterval, "BOOTSTRAP_JOIN_RETRY_INTERVAL")
 
 	configloader.OverrideString(&cfg.DHT.Bootstrap.Route53.HostedZoneID, "ROUTE53_ZONE_ID")
 	configloader.OverrideString(&cfg.DHT.Bootstrap.Route53.DomainSuffix, "ROUTE53_SUFFIX")
 	configloader.OverrideInt64(&cfg.DHT.Bootstrap.Route53.TTL, "ROUTE53_TTL")
 	configloader.OverrideString(&cfg.DHT.Bootstrap.Route53.Region, "ROUTE53_REGION")
+	configloader.OverrideDuration(&cfg.DHT.Bootstrap.Route53.RefreshInterval, "ROUTE53_REFRESH_INTERVAL")
+
+	configloader.OverrideString(&cfg.DHT.Bootstrap.MDNS.Service, "MDNS_SERVICE")
+	configloader.OverrideString(&cfg.DHT.Bootstrap.MDNS.Interface, "MDNS_INTERFACE")
+	configloader.OverrideDuration(&cfg.DHT.Bootstrap.MDNS.Timeout, "MDNS_TIMEOUT")
+
+	configloader.OverrideString(&cfg.DHT.Bootstrap.K8s.ServiceName, "K8S_SERVICE_NAME")
+	configloader.OverrideString(&cfg.DHT.Bootstrap.K8s.Namespace, "K8S_NAMESPACE")
+	configloader.OverrideString(&cfg.DHT.Bootstrap.K8s.PortName, "K8S_PORT_NAME")
+	configloader.OverrideString(&cfg.DHT.Bootstrap.K8s.ClusterDomain, "K8S_CLUSTER_DOMAIN")
+	configloader.OverrideString(&cfg.DHT.Bootstrap.K8s.DNSServer, "K8S_DNS_SERVER")
+
+	configloader.OverrideStringSlice(&cfg.DHT.Bootstrap.Etcd.Endpoints, "ETCD_ENDPOINTS") // comma-separated list
+	configloader.OverrideString(&cfg.DHT.Bootstrap.Etcd.Prefix, "ETCD_PREFIX")
+	configloader.OverrideDuration(&cfg.DHT.Bootstrap.Etcd.LeaseTTL, "ETCD_LEASE_TTL")
 
 	configloader.OverrideBool(&cfg.Telemetry.Tracing.Enabled, "TRACING_ENABLED")
 	configloader.OverrideString(&cfg.Telemetry.Tracing.Exporter, "TRACING_EXPORTER")
 	configloader.OverrideString(&cfg.Telemetry.Tracing.Endpoint, "TRACING_ENDPOINT")
+	configloader.OverrideBool(&cfg.Telemetry.Metrics.Enabled, "METRICS_ENABLED")
+	configloader.OverrideInt(&cfg.Telemetry.Metrics.Port, "METRICS_PORT")
+	configloader.OverrideBool(&cfg.Telemetry.Metrics.OTLP, "METRICS_OTLP")
+	configloader.OverrideDuration(&cfg.Telemetry.Metrics.ExportInterval, "METRICS_EXPORT_INTERVAL")
+	configloader.OverrideDuration(&cfg.Telemetry.ShutdownTimeout, "TELEMETRY_SHUTDOWN_TIMEOUT")
+
+	configloader.OverrideString(&cfg.Security.Mode, "SECURITY_MODE")
+	configloader.OverrideString(&cfg.Security.CertFile, "SECURITY_CERT_FILE")
+	configloader.OverrideString(&cfg.Security.KeyFile, "SECURITY_KEY_FILE")
+	configloader.OverrideString(&cfg.Security.CAFile, "SECURITY_CA_FILE")
 
 	configloader.OverrideBool(&cfg.Logger.Active, "LOGGER_ENABLED")
 	configloader.OverrideString(&cfg.Logger.Level, "LOGGER_LEVEL")
@@ -104,11 +325,113 @@ func LoadConfig(path string) (*Config, error) {
 	configloader.OverrideInt(&cfg.Logger.File.MaxBackups, "LOGGER_FILE_MAX_BACKUPS")
 	configloader.OverrideInt(&cfg.Logger.File.MaxAge, "LOGGER_FILE_MAX_AGE")
 	configloader.OverrideBool(&cfg.Logger.File.Compress, "LOGGER_FILE_COMPRESS")
+	configloader.OverrideBool(&cfg.Logger.AccessLog, "LOGGER_ACCESS_LOG")
 
 	// Apply defaults
 	if cfg.Node.Bind == "" {
 		cfg.Node.Bind = "0.0.0.0"
 	}
+	if cfg.Node.IPVersion == "" {
+		cfg.Node.IPVersion = "v4"
+	}
+	if cfg.Node.IdStrategy == "" {
+		cfg.Node.IdStrategy = "persisted"
+	}
+	if cfg.Node.RoutingFile != "" && cfg.Node.RoutingSaveInterval == 0 {
+		cfg.Node.RoutingSaveInterval = 10 * time.Second
+	}
+	if cfg.DHT.Hash == "" {
+		cfg.DHT.Hash = domain.DefaultHash
+	}
+	if cfg.DHT.Lookup.RetryBaseDelay == 0 {
+		cfg.DHT.Lookup.RetryBaseDelay = 50 * time.Millisecond
+	}
+	if cfg.DHT.Lookup.CacheTTL == 0 {
+		cfg.DHT.Lookup.CacheTTL = time.Second
+	}
+	if cfg.Telemetry.Metrics.ExportInterval == 0 {
+		cfg.Telemetry.Metrics.ExportInterval = 15 * time.Second
+	}
+	if cfg.Telemetry.ShutdownTimeout == 0 {
+		cfg.Telemetry.ShutdownTimeout = 5 * time.Second
+	}
+	if cfg.DHT.LookupMode == "" {
+		cfg.DHT.LookupMode = "recursive"
+	}
+	if cfg.DHT.VNodes == 0 {
+		cfg.DHT.VNodes = 1
+	}
+	if cfg.Security.Mode == "" {
+		cfg.Security.Mode = security.ModeNone
+	}
+	if cfg.DHT.Compression.GRPC == "" {
+		cfg.DHT.Compression.GRPC = "none"
+	}
+	if cfg.DHT.GRPC.Keepalive.Time > 0 && cfg.DHT.GRPC.Keepalive.Timeout == 0 {
+		cfg.DHT.GRPC.Keepalive.Timeout = 20 * time.Second
+	}
+	if cfg.DHT.GRPC.MaxConcurrentRequests > 0 && cfg.DHT.GRPC.MaxConcurrentMaintenance == 0 {
+		cfg.DHT.GRPC.MaxConcurrentMaintenance = 2 * cfg.DHT.GRPC.MaxConcurrentRequests
+	}
+	if cfg.DHT.Storage.SweepInterval == 0 {
+		cfg.DHT.Storage.SweepInterval = time.Minute
+	}
+	if cfg.DHT.Storage.Backend == "" {
+		cfg.DHT.Storage.Backend = "memory"
+	}
+	if cfg.DHT.Storage.Eviction == "" {
+		cfg.DHT.Storage.Eviction = storage.EvictReject
+	}
+	if cfg.DHT.Storage.NoPredecessor == "" {
+		cfg.DHT.Storage.NoPredecessor = "accept"
+	}
+	if cfg.DHT.Storage.Replicas == 0 {
+		cfg.DHT.Storage.Replicas = 1
+	}
+	if cfg.DHT.FaultTolerance.AdaptiveSuccessorList {
+		if cfg.DHT.FaultTolerance.MinSuccessorListSize == 0 {
+			cfg.DHT.FaultTolerance.MinSuccessorListSize = max(cfg.DHT.DeBruijn.Degree, 2)
+		}
+		if cfg.DHT.FaultTolerance.MaxSuccessorListSize == 0 {
+			cfg.DHT.FaultTolerance.MaxSuccessorListSize = 32
+		}
+	}
+	if cfg.DHT.FaultTolerance.MaxInterval > 0 && cfg.DHT.FaultTolerance.MinInterval == 0 {
+		cfg.DHT.FaultTolerance.MinInterval = cfg.DHT.FaultTolerance.StabilizationInterval
+	}
+	if cfg.DHT.FaultTolerance.AsymmetryRounds == 0 {
+		cfg.DHT.FaultTolerance.AsymmetryRounds = 3
+	}
+	if cfg.Node.Role == "" {
+		cfg.Node.Role = "member"
+	}
+	if cfg.DHT.Bootstrap.Retry.Attempts == 0 {
+		cfg.DHT.Bootstrap.Retry.Attempts = 3
+	}
+	if cfg.DHT.Bootstrap.Retry.Timeout == 0 {
+		cfg.DHT.Bootstrap.Retry.Timeout = 10 * time.Second
+	}
+	if cfg.DHT.Bootstrap.Retry.Backoff == 0 {
+		cfg.DHT.Bootstrap.Retry.Backoff = time.Second
+	}
+	if cfg.DHT.Bootstrap.JoinRetryInterval == 0 {
+		cfg.DHT.Bootstrap.JoinRetryInterval = 2 * time.Second
+	}
+	if cfg.DHT.Bootstrap.MDNS.Service == "" {
+		cfg.DHT.Bootstrap.MDNS.Service = "_koorde._tcp"
+	}
+	if cfg.DHT.Bootstrap.MDNS.Timeout == 0 {
+		cfg.DHT.Bootstrap.MDNS.Timeout = 2 * time.Second
+	}
+	if cfg.DHT.Bootstrap.K8s.ClusterDomain == "" {
+		cfg.DHT.Bootstrap.K8s.ClusterDomain = "cluster.local"
+	}
+	if cfg.DHT.Bootstrap.Etcd.Prefix == "" {
+		cfg.DHT.Bootstrap.Etcd.Prefix = "/koorde/nodes/"
+	}
+	if cfg.DHT.Bootstrap.Etcd.LeaseTTL == 0 {
+		cfg.DHT.Bootstrap.Etcd.LeaseTTL = 10 * time.Second
+	}
 
 	return cfg, nil
 }
@@ -155,17 +478,31 @@ func (cfg *Config) ValidateConfig() error {
 	if cfg.DHT.IDBits <= 0 {
 		errs = append(errs, "dht.idBits must be > 0")
 	}
+	if cfg.DHT.IDBits > 0 {
+		if _, err := domain.NewSpace(cfg.DHT.IDBits, 2, 1, domain.WithHash(cfg.DHT.Hash)); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid dht.hash: %v", err))
+		}
+	}
 	switch cfg.DHT.Mode {
 	case "public", "private":
 	default:
 		errs = append(errs, fmt.Sprintf("invalid dht.mode: %s", cfg.DHT.Mode))
 	}
+	if cfg.DHT.VNodes < 1 {
+		errs = append(errs, "dht.vnodes must be >= 1")
+	}
 	if cfg.DHT.DeBruijn.Degree <= 0 {
 		errs = append(errs, "dht.deBruijn.degree must be > 0")
 	}
 	if cfg.DHT.DeBruijn.FixInterval <= 0 {
 		errs = append(errs, "dht.deBruijn.fixInterval must be > 0")
 	}
+	if cfg.DHT.DeBruijn.MaxRefreshAge < 0 {
+		errs = append(errs, "dht.deBruijn.maxRefreshAge must be >= 0")
+	}
+	if cfg.DHT.DeBruijn.BackupSize < 0 {
+		errs = append(errs, "dht.deBruijn.backupSize must be >= 0")
+	}
 	if cfg.DHT.FaultTolerance.SuccessorListSize <= 0 {
 		errs = append(errs, "dht.faultTolerance.successorListSize must be > 0")
 	}
@@ -178,6 +515,32 @@ func (cfg *Config) ValidateConfig() error {
 	if cfg.DHT.DeBruijn.Degree > cfg.DHT.FaultTolerance.SuccessorListSize {
 		errs = append(errs, "dht.deBruijn.degree must be <= dht.faultTolerance.successorListSize")
 	}
+	if ft := cfg.DHT.FaultTolerance; ft.AdaptiveSuccessorList {
+		if ft.MinSuccessorListSize < cfg.DHT.DeBruijn.Degree || ft.MaxSuccessorListSize < ft.MinSuccessorListSize {
+			errs = append(errs, fmt.Sprintf(
+				"invalid dht.faultTolerance successor list bounds [%d, %d] (must satisfy degree <= minSuccessorListSize <= maxSuccessorListSize)",
+				ft.MinSuccessorListSize, ft.MaxSuccessorListSize))
+		}
+		if ft.SuccessorListSize < ft.MinSuccessorListSize || ft.SuccessorListSize > ft.MaxSuccessorListSize {
+			errs = append(errs, "dht.faultTolerance.successorListSize (initial size) must be within [minSuccessorListSize, maxSuccessorListSize]")
+		}
+	}
+	if ft := cfg.DHT.FaultTolerance; ft.MinInterval < 0 || ft.MaxInterval < 0 {
+		errs = append(errs, "dht.faultTolerance.minInterval and maxInterval must be >= 0")
+	} else if ft.MaxInterval > 0 && ft.MinInterval > ft.MaxInterval {
+		errs = append(errs, "dht.faultTolerance.minInterval must be <= maxInterval")
+	} else if ft.MaxInterval == 0 && ft.MinInterval > 0 {
+		errs = append(errs, "dht.faultTolerance.minInterval requires maxInterval (adaptive stabilization)")
+	}
+	if cfg.DHT.FaultTolerance.AsymmetryRounds < 0 {
+		errs = append(errs, "dht.faultTolerance.asymmetryRounds must be >= 0")
+	}
+	if cfg.DHT.FaultTolerance.Jitter < 0 || cfg.DHT.FaultTolerance.Jitter >= 1 {
+		errs = append(errs, fmt.Sprintf("dht.faultTolerance.jitter must be in [0,1), got %g", cfg.DHT.FaultTolerance.Jitter))
+	}
+	if cfg.DHT.FaultTolerance.DialTimeout < 0 {
+		errs = append(errs, "dht.faultTolerance.dialTimeout must be >= 0")
+	}
 	if cfg.DHT.IDBits%bits.TrailingZeros(uint(cfg.DHT.DeBruijn.Degree)) != 0 {
 		errs = append(errs, fmt.Sprintf(
 			"dht.idBits (%d) must be a multiple of log2(dht.deBruijn.degree) = %d",
@@ -186,6 +549,120 @@ func (cfg *Config) ValidateConfig() error {
 		))
 	}
 
+	if cfg.DHT.Storage.SweepInterval < 0 {
+		errs = append(errs, "dht.storage.sweepInterval must be > 0")
+	}
+	if cfg.DHT.Storage.QuotaBytes < 0 {
+		errs = append(errs, "dht.storage.quotaBytes must be >= 0")
+	}
+	if cfg.DHT.Storage.MaxKeys < 0 {
+		errs = append(errs, "dht.storage.maxKeys must be >= 0")
+	}
+	switch cfg.DHT.Storage.Eviction {
+	case storage.EvictReject, storage.EvictLRU:
+	default:
+		errs = append(errs, fmt.Sprintf("invalid dht.storage.eviction: %s (must be %s or %s)",
+			cfg.DHT.Storage.Eviction, storage.EvictReject, storage.EvictLRU))
+	}
+	switch cfg.DHT.Storage.NoPredecessor {
+	case "accept", "repair", "reject":
+	default:
+		errs = append(errs, fmt.Sprintf("invalid dht.storage.noPredecessor: %s (must be accept, repair or reject)",
+			cfg.DHT.Storage.NoPredecessor))
+	}
+	if cfg.DHT.Storage.AntiEntropyInterval < 0 {
+		errs = append(errs, "dht.storage.antiEntropyInterval must be >= 0")
+	}
+	if d := cfg.DHT.Storage.AntiEntropyDepth; d < 0 || d > storage.MaxMerkleDepth {
+		errs = append(errs, fmt.Sprintf("dht.storage.antiEntropyDepth must be in [0, %d]", storage.MaxMerkleDepth))
+	}
+	if cfg.DHT.Storage.MaxValueBytes < 0 {
+		errs = append(errs, "dht.storage.maxValueBytes must be >= 0")
+	}
+	switch cfg.DHT.Storage.Backend {
+	case "memory":
+	case "bolt":
+		if cfg.DHT.Storage.Path == "" {
+			errs = append(errs, "dht.storage.path is required with dht.storage.backend bolt")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("invalid dht.storage.backend: %s (must be memory or bolt)", cfg.DHT.Storage.Backend))
+	}
+	minSucc := cfg.DHT.FaultTolerance.SuccessorListSize
+	if cfg.DHT.FaultTolerance.AdaptiveSuccessorList {
+		minSucc = cfg.DHT.FaultTolerance.MinSuccessorListSize
+	}
+	if r := cfg.DHT.Storage.Replicas; r < 1 || r > minSucc+1 {
+		errs = append(errs, fmt.Sprintf("invalid dht.storage.replicas: %d (must be in [1, successorListSize+1], minSuccessorListSize+1 if adaptive)", r))
+	}
+	switch cfg.DHT.Compression.GRPC {
+	case "none", "gzip":
+	default:
+		errs = append(errs, fmt.Sprintf("invalid dht.compression.grpc: %s (must be none or gzip)", cfg.DHT.Compression.GRPC))
+	}
+	if ka := cfg.DHT.GRPC.Keepalive; ka.Time < 0 || (ka.Time > 0 && ka.Time < 10*time.Second) {
+		errs = append(errs, fmt.Sprintf("invalid dht.grpc.keepalive.time: %s (must be 0 to disable or >= 10s)", ka.Time))
+	}
+	if cfg.DHT.GRPC.Keepalive.Timeout < 0 {
+		errs = append(errs, "dht.grpc.keepalive.timeout must be >= 0")
+	}
+	if cfg.DHT.GRPC.MaxConcurrentRequests < 0 || cfg.DHT.GRPC.MaxConcurrentMaintenance < 0 {
+		errs = append(errs, "dht.grpc.maxConcurrentRequests and dht.grpc.maxConcurrentMaintenance must be >= 0")
+	}
+	for method, n := range cfg.DHT.GRPC.MethodLimits {
+		if method == "" || n < 0 {
+			errs = append(errs, fmt.Sprintf("invalid dht.grpc.methodLimits entry %q: %d (must be a method name with a limit >= 0)", method, n))
+		}
+	}
+
+	switch cfg.DHT.LookupMode {
+	case "recursive", "iterative":
+	default:
+		errs = append(errs, fmt.Sprintf("invalid dht.lookupMode: %s (must be recursive or iterative)", cfg.DHT.LookupMode))
+	}
+	if cfg.DHT.Lookup.HopReserve < 0 {
+		errs = append(errs, "dht.lookup.hopReserve must be >= 0")
+	}
+	if cfg.DHT.Lookup.OwnerReserve < 0 || cfg.DHT.Lookup.OwnerReserve >= 1 {
+		errs = append(errs, fmt.Sprintf("dht.lookup.ownerReserve must be in [0,1), got %g", cfg.DHT.Lookup.OwnerReserve))
+	}
+	if cfg.DHT.Lookup.MinHopBudget < 0 {
+		errs = append(errs, "dht.lookup.minHopBudget must be >= 0")
+	}
+	if cfg.DHT.Lookup.MaxRetries < 0 {
+		errs = append(errs, "dht.lookup.maxRetries must be >= 0")
+	}
+	if cfg.DHT.Lookup.RetryBaseDelay < 0 {
+		errs = append(errs, "dht.lookup.retryBaseDelay must be >= 0")
+	}
+	if cfg.DHT.Lookup.OwnerRetries < 0 {
+		errs = append(errs, "dht.lookup.ownerRetries must be >= 0")
+	}
+	if cfg.DHT.Lookup.MaxLocalDepth < 0 {
+		errs = append(errs, "dht.lookup.maxLocalDepth must be >= 0")
+	}
+	if cfg.DHT.Lookup.MaxHops < 0 {
+		errs = append(errs, "dht.lookup.maxHops must be >= 0")
+	}
+	if cfg.DHT.Lookup.Parallelism < 0 {
+		errs = append(errs, "dht.lookup.parallelism must be >= 0")
+	}
+	if cfg.DHT.Lookup.MaxOutbound < 0 {
+		errs = append(errs, "dht.lookup.maxOutbound must be >= 0")
+	}
+	if cfg.DHT.Lookup.CacheSize < 0 {
+		errs = append(errs, "dht.lookup.cacheSize must be >= 0")
+	}
+	if cfg.DHT.Lookup.CacheTTL < 0 {
+		errs = append(errs, "dht.lookup.cacheTTL must be >= 0")
+	}
+	if cfg.DHT.CatchUp.Interval < 0 {
+		errs = append(errs, "dht.catchUp.interval must be >= 0")
+	}
+	if cfg.DHT.CatchUp.MaxRounds < 0 {
+		errs = append(errs, "dht.catchUp.maxRounds must be >= 0")
+	}
+
 	// Bootstrap
 	b := cfg.DHT.Bootstrap
 	switch b.Mode {
@@ -202,6 +679,9 @@ func (cfg *Config) ValidateConfig() error {
 		if b.Route53.Region == "" {
 			errs = append(errs, "bootstrap.route53.region is required in mode=route53")
 		}
+		if b.Route53.RefreshInterval < 0 {
+			errs = append(errs, "bootstrap.route53.refreshInterval must be >= 0")
+		}
 	case "static":
 		if len(b.Peers) != 0 {
 			for _, p := range b.Peers {
@@ -210,14 +690,85 @@ func (cfg *Config) ValidateConfig() error {
 				}
 			}
 		}
+	case "mdns":
+		if !validMDNSService(b.MDNS.Service) {
+			errs = append(errs, fmt.Sprintf("invalid bootstrap.mdns.service: %q (must be _name._tcp or _name._udp)", b.MDNS.Service))
+		}
+		if b.MDNS.Timeout <= 0 {
+			errs = append(errs, "bootstrap.mdns.timeout must be > 0 in mode=mdns")
+		}
+	case "k8s":
+		if !validDNSLabel(b.K8s.ServiceName) {
+			errs = append(errs, fmt.Sprintf("invalid bootstrap.k8s.serviceName: %q (required in mode=k8s, must be a DNS label)", b.K8s.ServiceName))
+		}
+		if !validDNSLabel(b.K8s.Namespace) {
+			errs = append(errs, fmt.Sprintf("invalid bootstrap.k8s.namespace: %q (required in mode=k8s, must be a DNS label)", b.K8s.Namespace))
+		}
+		if b.K8s.PortName != "" && (!validDNSLabel(b.K8s.PortName) || len(b.K8s.PortName) > 15) {
+			errs = append(errs, fmt.Sprintf("invalid bootstrap.k8s.portName: %q (must be a DNS label of at most 15 characters)", b.K8s.PortName))
+		}
+		if b.K8s.DNSServer != "" {
+			if _, _, err := net.SplitHostPort(b.K8s.DNSServer); err != nil && net.ParseIP(b.K8s.DNSServer) == nil {
+				errs = append(errs, fmt.Sprintf("invalid bootstrap.k8s.dnsServer: %q (must be host:port or an IP)", b.K8s.DNSServer))
+			}
+		}
+	case "etcd":
+		if len(b.Etcd.Endpoints) == 0 {
+			errs = append(errs, "bootstrap.etcd.endpoints is required in mode=etcd")
+		}
+		for _, ep := range b.Etcd.Endpoints {
+			if strings.TrimSpace(ep) == "" {
+				errs = append(errs, "empty endpoint in bootstrap.etcd.endpoints")
+			}
+		}
+		if !strings.HasPrefix(b.Etcd.Prefix, "/") {
+			errs = append(errs, fmt.Sprintf("invalid bootstrap.etcd.prefix: %q (must start with /)", b.Etcd.Prefix))
+		}
+		if b.Etcd.LeaseTTL < time.Second || b.Etcd.LeaseTTL%time.Second != 0 {
+			errs = append(errs, fmt.Sprintf("invalid bootstrap.etcd.leaseTTL: %s (must be a whole number of seconds >= 1s)", b.Etcd.LeaseTTL))
+		}
 	default:
-		errs = append(errs, fmt.Sprintf("invalid bootstrap.mode: %s (must be dns, static or init)", b.Mode))
+		errs = append(errs, fmt.Sprintf("invalid bootstrap.mode: %s (must be static, route53, mdns, k8s or etcd)", b.Mode))
+	}
+
+	if b.Retry.Attempts < 1 {
+		errs = append(errs, "bootstrap.retry.attempts must be >= 1")
+	}
+	if b.Retry.Timeout < 0 || b.Retry.Backoff < 0 {
+		errs = append(errs, "bootstrap.retry.timeout and bootstrap.retry.backoff must be non-negative")
+	}
+	if b.JoinRetries < 0 {
+		errs = append(errs, "bootstrap.joinRetries must be >= 0")
+	}
+	if b.JoinRetryInterval < 0 {
+		errs = append(errs, "bootstrap.joinRetryInterval must be non-negative")
 	}
 
 	// Node
 	if cfg.Node.Port < 0 || cfg.Node.Port > 65535 {
 		errs = append(errs, fmt.Sprintf("node.port must be in [0,65535], got %d", cfg.Node.Port))
 	}
+	switch cfg.Node.IPVersion {
+	case "v4", "v6", "auto":
+	default:
+		errs = append(errs, fmt.Sprintf("invalid node.ipVersion: %s (must be v4, v6 or auto)", cfg.Node.IPVersion))
+	}
+	switch cfg.Node.IdStrategy {
+	case "persisted", "address":
+	default:
+		errs = append(errs, fmt.Sprintf("invalid node.idStrategy: %s (must be persisted or address)", cfg.Node.IdStrategy))
+	}
+	switch cfg.Node.Role {
+	case "member", "observer":
+	default:
+		errs = append(errs, fmt.Sprintf("invalid node.role: %s (must be member or observer)", cfg.Node.Role))
+	}
+	if cfg.DHT.VNodes > 1 && cfg.Node.Role == "observer" {
+		errs = append(errs, "dht.vnodes > 1 requires node.role member (an observer owns no keys)")
+	}
+	if cfg.Node.RoutingSaveInterval < 0 {
+		errs = append(errs, "node.routingSaveInterval must be >= 0")
+	}
 
 	// Telemetry
 	if cfg.Telemetry.Tracing.Enabled {
@@ -230,6 +781,27 @@ func (cfg *Config) ValidateConfig() error {
 			errs = append(errs, "telemetry.tracing.endpoint is required")
 		}
 	}
+	if cfg.Telemetry.Metrics.Enabled {
+		if p := cfg.Telemetry.Metrics.Port; p <= 0 || p > 65535 {
+			errs = append(errs, fmt.Sprintf("invalid telemetry.metrics.port: %d", p))
+		} else if p == cfg.Node.Port {
+			errs = append(errs, "telemetry.metrics.port must differ from node.port")
+		}
+		if cfg.Telemetry.Metrics.OTLP {
+			if cfg.Telemetry.Tracing.Endpoint == "" {
+				errs = append(errs, "telemetry.tracing.endpoint is required by telemetry.metrics.otlp")
+			}
+			if cfg.Telemetry.Metrics.ExportInterval <= 0 {
+				errs = append(errs, "telemetry.metrics.exportInterval must be > 0")
+			}
+		}
+	}
+	if cfg.Telemetry.ShutdownTimeout < 0 {
+		errs = append(errs, "telemetry.shutdownTimeout must be >= 0")
+	}
+	if err := cfg.Security.Validate(true); err != nil {
+		errs = append(errs, err.Error())
+	}
 
 	// Return result
 	if len(errs) > 0 {
@@ -242,7 +814,22 @@ func (cfg *Config) ValidateConfig() error {
 // This is useful for debugging startup issues and verifying
 // that the configuration file has been parsed correctly.
 func (cfg *Config) LogConfig(lgr logger.Logger) {
-	lgr.Info("Loaded configuration",
+	lgr.Info("Loaded configuration", cfg.Fields()...)
+}
+
+// Redacted replaces secrets in the values returned by Fields.
+const Redacted = "REDACTED"
+
+// Fields returns the effective configuration (after environment overrides
+// and defaults) as a flat list of named fields, the ones logged by
+// LogConfig and returned by the GetConfig RPC.
+//
+// Secrets are redacted explicitly, field by field: credentials embedded in
+// the tracing endpoint (user info and query parameters) are replaced by
+// Redacted. AWS credentials for Route53 are never part of the
+// configuration (they come from the default AWS credential chain).
+func (cfg *Config) Fields() []logger.Field {
+	return []logger.Field{
 		// Logger
 		logger.F("logger.active", cfg.Logger.Active),
 		logger.F("logger.level", cfg.Logger.Level),
@@ -253,19 +840,69 @@ func (cfg *Config) LogConfig(lgr logger.Logger) {
 		logger.F("logger.file.maxBackups", cfg.Logger.File.MaxBackups),
 		logger.F("logger.file.maxAgeDays", cfg.Logger.File.MaxAge),
 		logger.F("logger.file.compress", cfg.Logger.File.Compress),
+		logger.F("logger.accessLog", cfg.Logger.AccessLog),
 
 		// DHT
 		logger.F("dht.idBits", cfg.DHT.IDBits),
+		logger.F("dht.namespace", cfg.DHT.Namespace),
+		logger.F("dht.hash", cfg.DHT.Hash),
 		logger.F("dht.mode", cfg.DHT.Mode),
+		logger.F("dht.vnodes", cfg.DHT.VNodes),
 
 		// de Bruijn
 		logger.F("dht.deBruijn.degree", cfg.DHT.DeBruijn.Degree),
 		logger.F("dht.deBruijn.fixInterval", cfg.DHT.DeBruijn.FixInterval.String()),
 		logger.F("dht.deBruijn.fixIntervalMs", cfg.DHT.DeBruijn.FixInterval.Milliseconds()),
+		logger.F("dht.deBruijn.maxRefreshAge", cfg.DHT.DeBruijn.MaxRefreshAge.String()),
+		logger.F("dht.deBruijn.backupSize", cfg.DHT.DeBruijn.BackupSize),
 
 		// storage
 		logger.F("dht.storage.fixInterval", cfg.DHT.Storage.FixInterval.String()),
 		logger.F("dht.storage.fixIntervalMs", cfg.DHT.Storage.FixInterval.Milliseconds()),
+		logger.F("dht.storage.pullOnJoin", cfg.DHT.Storage.PullOnJoin),
+		logger.F("dht.storage.checksum", cfg.DHT.Storage.Checksum),
+		logger.F("dht.storage.replicas", cfg.DHT.Storage.Replicas),
+		logger.F("dht.storage.readRepair", cfg.DHT.Storage.ReadRepair),
+		logger.F("dht.storage.sweepInterval", cfg.DHT.Storage.SweepInterval.String()),
+		logger.F("dht.storage.quotaBytes", cfg.DHT.Storage.QuotaBytes),
+		logger.F("dht.storage.maxKeys", cfg.DHT.Storage.MaxKeys),
+		logger.F("dht.storage.eviction", cfg.DHT.Storage.Eviction),
+		logger.F("dht.storage.maxValueBytes", cfg.DHT.Storage.MaxValueBytes),
+		logger.F("dht.storage.backend", cfg.DHT.Storage.Backend),
+		logger.F("dht.storage.path", cfg.DHT.Storage.Path),
+		logger.F("dht.storage.noPredecessor", cfg.DHT.Storage.NoPredecessor),
+		logger.F("dht.storage.antiEntropyInterval", cfg.DHT.Storage.AntiEntropyInterval.String()),
+		logger.F("dht.storage.antiEntropyDepth", cfg.DHT.Storage.AntiEntropyDepth),
+		logger.F("dht.compression.grpc", cfg.DHT.Compression.GRPC),
+		logger.F("dht.grpc.keepalive.time", cfg.DHT.GRPC.Keepalive.Time.String()),
+		logger.F("dht.grpc.keepalive.timeout", cfg.DHT.GRPC.Keepalive.Timeout.String()),
+		logger.F("dht.grpc.keepalive.permitWithoutStream", cfg.DHT.GRPC.Keepalive.PermitWithoutStream),
+		logger.F("dht.grpc.maxConcurrentRequests", cfg.DHT.GRPC.MaxConcurrentRequests),
+		logger.F("dht.grpc.maxConcurrentMaintenance", cfg.DHT.GRPC.MaxConcurrentMaintenance),
+		logger.F("dht.grpc.methodLimits", cfg.DHT.GRPC.MethodLimits),
+
+		// lookup
+		logger.F("dht.lookupMode", cfg.DHT.LookupMode),
+		logger.F("dht.routing.deBruijn", cfg.DHT.Routing.DeBruijn),
+		logger.F("dht.lookup.hopReserve", cfg.DHT.Lookup.HopReserve.String()),
+		logger.F("dht.lookup.hopReserveMs", cfg.DHT.Lookup.HopReserve.Milliseconds()),
+		logger.F("dht.lookup.minHopBudget", cfg.DHT.Lookup.MinHopBudget.String()),
+		logger.F("dht.lookup.minHopBudgetMs", cfg.DHT.Lookup.MinHopBudget.Milliseconds()),
+		logger.F("dht.lookup.ownerReserve", cfg.DHT.Lookup.OwnerReserve),
+		logger.F("dht.lookup.maxRetries", cfg.DHT.Lookup.MaxRetries),
+		logger.F("dht.lookup.retryBaseDelay", cfg.DHT.Lookup.RetryBaseDelay.String()),
+		logger.F("dht.lookup.ownerRetries", cfg.DHT.Lookup.OwnerRetries),
+		logger.F("dht.lookup.maxLocalDepth", cfg.DHT.Lookup.MaxLocalDepth),
+		logger.F("dht.lookup.maxHops", cfg.DHT.Lookup.MaxHops),
+		logger.F("dht.lookup.parallelism", cfg.DHT.Lookup.Parallelism),
+		logger.F("dht.lookup.maxOutbound", cfg.DHT.Lookup.MaxOutbound),
+		logger.F("dht.lookup.cacheSize", cfg.DHT.Lookup.CacheSize),
+		logger.F("dht.lookup.cacheTTL", cfg.DHT.Lookup.CacheTTL.String()),
+
+		// catch-up
+		logger.F("dht.catchUp.interval", cfg.DHT.CatchUp.Interval.String()),
+		logger.F("dht.catchUp.intervalMs", cfg.DHT.CatchUp.Interval.Milliseconds()),
+		logger.F("dht.catchUp.maxRounds", cfg.DHT.CatchUp.MaxRounds),
 
 		// fault tolerance
 		logger.F("dht.faultTolerance.successorListSize", cfg.DHT.FaultTolerance.SuccessorListSize),
@@ -273,26 +910,121 @@ func (cfg *Config) LogConfig(lgr logger.Logger) {
 		logger.F("dht.faultTolerance.stabilizationIntervalMs", cfg.DHT.FaultTolerance.StabilizationInterval.Milliseconds()),
 		logger.F("dht.faultTolerance.failureTimeout", cfg.DHT.FaultTolerance.FailureTimeout.String()),
 		logger.F("dht.faultTolerance.failureTimeoutMs", cfg.DHT.FaultTolerance.FailureTimeout.Milliseconds()),
+		logger.F("dht.faultTolerance.adaptiveSuccessorList", cfg.DHT.FaultTolerance.AdaptiveSuccessorList),
+		logger.F("dht.faultTolerance.minSuccessorListSize", cfg.DHT.FaultTolerance.MinSuccessorListSize),
+		logger.F("dht.faultTolerance.maxSuccessorListSize", cfg.DHT.FaultTolerance.MaxSuccessorListSize),
+		logger.F("dht.faultTolerance.minInterval", cfg.DHT.FaultTolerance.MinInterval.String()),
+		logger.F("dht.faultTolerance.maxInterval", cfg.DHT.FaultTolerance.MaxInterval.String()),
+		logger.F("dht.faultTolerance.asymmetryRounds", cfg.DHT.FaultTolerance.AsymmetryRounds),
+		logger.F("dht.faultTolerance.asymmetryRenotify", cfg.DHT.FaultTolerance.AsymmetryRenotify),
+		logger.F("dht.faultTolerance.jitter", cfg.DHT.FaultTolerance.Jitter),
+		logger.F("dht.faultTolerance.dialTimeout", cfg.DHT.FaultTolerance.DialTimeout.String()),
+		logger.F("dht.faultTolerance.dialFailFast", cfg.DHT.FaultTolerance.DialFailFast),
 
 		// bootstrap
 		logger.F("dht.bootstrap.mode", cfg.DHT.Bootstrap.Mode),
 		logger.F("dht.bootstrap.peers", cfg.DHT.Bootstrap.Peers),
+		logger.F("dht.bootstrap.requireRegistration", cfg.DHT.Bootstrap.RequireRegistration),
+		logger.F("dht.bootstrap.gateUntilRegistered", cfg.DHT.Bootstrap.GateUntilRegistered),
+		logger.F("dht.bootstrap.retry.attempts", cfg.DHT.Bootstrap.Retry.Attempts),
+		logger.F("dht.bootstrap.retry.timeout", cfg.DHT.Bootstrap.Retry.Timeout.String()),
+		logger.F("dht.bootstrap.retry.backoff", cfg.DHT.Bootstrap.Retry.Backoff.String()),
+		logger.F("dht.bootstrap.joinRetries", cfg.DHT.Bootstrap.JoinRetries),
+		logger.F("dht.bootstrap.joinRetryInterval", cfg.DHT.Bootstrap.JoinRetryInterval.String()),
 
 		// route53
 		logger.F("dht.bootstrap.register.hostedZoneId", cfg.DHT.Bootstrap.Route53.HostedZoneID),
 		logger.F("dht.bootstrap.register.domainSuffix", cfg.DHT.Bootstrap.Route53.DomainSuffix),
 		logger.F("dht.bootstrap.register.ttl", cfg.DHT.Bootstrap.Route53.TTL),
 		logger.F("dht.bootstrap.register.region", cfg.DHT.Bootstrap.Route53.Region),
+		logger.F("dht.bootstrap.register.refreshInterval", cfg.DHT.Bootstrap.Route53.RefreshInterval),
+		logger.F("dht.bootstrap.mdns.service", cfg.DHT.Bootstrap.MDNS.Service),
+		logger.F("dht.bootstrap.mdns.interface", cfg.DHT.Bootstrap.MDNS.Interface),
+		logger.F("dht.bootstrap.mdns.timeout", cfg.DHT.Bootstrap.MDNS.Timeout.String()),
+		logger.F("dht.bootstrap.k8s.serviceName", cfg.DHT.Bootstrap.K8s.ServiceName),
+		logger.F("dht.bootstrap.k8s.namespace", cfg.DHT.Bootstrap.K8s.Namespace),
+		logger.F("dht.bootstrap.k8s.portName", cfg.DHT.Bootstrap.K8s.PortName),
+		logger.F("dht.bootstrap.k8s.clusterDomain", cfg.DHT.Bootstrap.K8s.ClusterDomain),
+		logger.F("dht.bootstrap.k8s.dnsServer", cfg.DHT.Bootstrap.K8s.DNSServer),
+		logger.F("dht.bootstrap.etcd.endpoints", cfg.DHT.Bootstrap.Etcd.Endpoints),
+		logger.F("dht.bootstrap.etcd.prefix", cfg.DHT.Bootstrap.Etcd.Prefix),
+		logger.F("dht.bootstrap.etcd.leaseTTL", cfg.DHT.Bootstrap.Etcd.LeaseTTL.String()),
 
 		// Node
 		logger.F("node.id", cfg.Node.Id),
+		logger.F("node.idFile", cfg.Node.IdFile),
+		logger.F("node.routingFile", cfg.Node.RoutingFile),
+		logger.F("node.routingSaveInterval", cfg.Node.RoutingSaveInterval.String()),
+		logger.F("node.idStrategy", cfg.Node.IdStrategy),
+		logger.F("node.role", cfg.Node.Role),
 		logger.F("node.host", cfg.Node.Host),
 		logger.F("node.bind", cfg.Node.Bind),
 		logger.F("node.port", cfg.Node.Port),
+		logger.F("node.ipVersion", cfg.Node.IPVersion),
+		logger.F("node.verifySelfReachable", cfg.Node.VerifySelfReachable),
+		logger.F("node.adminRPC", cfg.Node.AdminRPC),
 
 		// Telemetry
 		logger.F("telemetry.tracing.enabled", cfg.Telemetry.Tracing.Enabled),
 		logger.F("telemetry.tracing.exporter", cfg.Telemetry.Tracing.Exporter),
-		logger.F("telemetry.tracing.endpoint", cfg.Telemetry.Tracing.Endpoint),
-	)
+		logger.F("telemetry.tracing.endpoint", redactURL(cfg.Telemetry.Tracing.Endpoint)),
+		logger.F("telemetry.metrics.enabled", cfg.Telemetry.Metrics.Enabled),
+		logger.F("telemetry.metrics.port", cfg.Telemetry.Metrics.Port),
+		logger.F("telemetry.metrics.otlp", cfg.Telemetry.Metrics.OTLP),
+		logger.F("telemetry.metrics.exportInterval", cfg.Telemetry.Metrics.ExportInterval.String()),
+		logger.F("telemetry.shutdownTimeout", cfg.Telemetry.ShutdownTimeout.String()),
+
+		// Security (file paths only, never their contents)
+		logger.F("security.mode", cfg.Security.Mode),
+		logger.F("security.certFile", cfg.Security.CertFile),
+		logger.F("security.keyFile", cfg.Security.KeyFile),
+		logger.F("security.caFile", cfg.Security.CAFile),
+	}
+}
+
+// redactURL replaces the user info and the query parameter values of
+// endpoint, which may carry credentials, with Redacted. Endpoints that are
+// not URLs (e.g. host:port) are returned unchanged.
+func redactURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+	if u.User != nil {
+		u.User = url.User(Redacted)
+	}
+	if u.RawQuery != "" {
+		q := u.Query()
+		for k := range q {
+			q.Set(k, Redacted)
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+// validMDNSService reports whether service is a DNS-SD service type,
+// _name._tcp or _name._udp (RFC 6763, section 7).
+func validMDNSService(service string) bool {
+	name, proto, ok := strings.Cut(service, ".")
+	if !ok || (proto != "_tcp" && proto != "_udp") {
+		return false
+	}
+	name, ok = strings.CutPrefix(name, "_")
+	return ok && name != "" && len(name) <= 15 && !strings.ContainsAny(name, "._")
+}
+
+// validDNSLabel reports whether s is an RFC 1123 label, the form of
+// Kubernetes service, namespace and port names: at most 63 lowercase
+// alphanumeric characters or '-', starting and ending with an alphanumeric.
+func validDNSLabel(s string) bool {
+	if s == "" || len(s) > 63 || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
 }
@@ -3,8 +3,11 @@ package ctxutil
 import (
 	"context"
 	"errors"
+	"strconv"
+	"time"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -33,3 +36,87 @@ func CheckContext(ctx context.Context) error {
 	}
 	return nil
 }
+
+// HopContext derives the context used to forward a request to the next
+// hop of a multi-hop operation (e.g., a recursive lookup).
+//
+// Behavior:
+//   - If ctx has no deadline, ctx is returned unchanged.
+//   - If less than minBudget remains before the deadline, no context is
+//     derived and a DeadlineExceeded gRPC error is returned: there is not
+//     enough time for the hop to complete, so the request must not be
+//     forwarded at all.
+//   - Otherwise, the hop receives the remaining time minus reserve (kept
+//     for the response path), but never less than minBudget.
+//
+// The reserve is a fixed amount rather than a share of the remaining time:
+// every level of a recursion keeps the same time to send its answer back,
+// so a chain of n hops gives up n·reserve of the deadline instead of a
+// share growing geometrically with n.
+//
+// The returned CancelFunc must always be called when err is nil.
+func HopContext(ctx context.Context, reserve, minBudget time.Duration) (context.Context, context.CancelFunc, error) {
+	if err := CheckContext(ctx); err != nil {
+		return nil, nil, err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return ctx, func() {}, nil
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 || remaining < minBudget {
+		return nil, nil, status.Error(codes.DeadlineExceeded, "deadline too close to forward request")
+	}
+	budget := remaining - reserve
+	if budget < minBudget {
+		budget = minBudget
+	}
+	hopCtx, cancel := context.WithTimeout(ctx, budget)
+	return hopCtx, cancel, nil
+}
+
+// hopsMetaKey is the metadata key carrying the forwarding hops of a
+// lookup (see WithHops).
+const hopsMetaKey = "x-koorde-hop"
+
+// WithHops returns a copy of ctx whose outgoing RPCs carry the number of
+// forwarding hops made so far by the multi-hop request they belong to,
+// which the receiving node reads with Hops. A lookup sets it on every
+// step it forwards.
+func WithHops(ctx context.Context, hops int) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(hopsMetaKey, strconv.Itoa(hops))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// Hops returns the number of forwarding hops carried by the incoming RPC
+// of ctx (see WithHops), 0 if none.
+func Hops(ctx context.Context) int {
+	vals := metadata.ValueFromIncomingContext(ctx, hopsMetaKey)
+	if len(vals) == 0 {
+		return 0
+	}
+	hops, _ := strconv.Atoi(vals[0])
+	return hops
+}
+
+// peerMetaKey is the metadata key flagging the RPCs a node sends to
+// another one (see WithPeer).
+const peerMetaKey = "x-koorde-peer"
+
+// WithPeer returns a copy of ctx whose outgoing RPCs are flagged as sent
+// by a node rather than by a client, so that the receiving node counts
+// them against the budget of the RPCs between nodes instead of the one of
+// its clients (see server.Limits). The connection pool of the nodes sets
+// it on every call.
+func WithPeer(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, peerMetaKey, "true")
+}
+
+// FromPeer reports whether the incoming RPC of ctx was flagged by the
+// sending node with WithPeer.
+func FromPeer(ctx context.Context) bool {
+	vals := metadata.ValueFromIncomingContext(ctx, peerMetaKey)
+	return len(vals) > 0 && vals[0] == "true"
+}
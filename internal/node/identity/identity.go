@@ -0,0 +1,129 @@
+// Package identity persists the identifier of a node across restarts and
+// decides which ID to use when the advertised address has changed.
+//
+// When a node derives its ID from its advertised address (host:port), a
+// legitimate address change (e.g. cloud IP reassignment) silently changes
+// the ID and orphans the keys the node used to own. The identity file
+// records the last (ID, address) pair so the change can be detected at
+// startup and handled according to the configured strategy.
+package identity
+
+import (
+	"KoordeDHT/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Supported ID strategies.
+const (
+	// StrategyPersisted keeps the previously persisted ID even if the
+	// advertised address changed. This is the default: key ownership is
+	// preserved and the address change is only logged.
+	StrategyPersisted = "persisted"
+	// StrategyAddress always derives the ID from the current address.
+	// On an address change the node rejoins the ring under the new ID
+	// and hands off the keys that no longer belong to it.
+	StrategyAddress = "address"
+)
+
+// Record is the on-disk representation of a node identity.
+type Record struct {
+	ID   string `json:"id"`   // hex-encoded identifier (0x-prefixed)
+	Addr string `json:"addr"` // advertised address the ID was used with
+}
+
+// Load reads the identity record stored at path.
+// A missing file is not an error: (nil, nil) is returned.
+func Load(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("identity: read %s: %w", path, err)
+	}
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("identity: decode %s: %w", path, err)
+	}
+	return &rec, nil
+}
+
+// Save atomically writes the identity record to path.
+func Save(path string, rec Record) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("identity: encode: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".identity-*")
+	if err != nil {
+		return fmt.Errorf("identity: write %s: %w", path, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("identity: write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("identity: write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("identity: write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Decision is the outcome of Resolve.
+type Decision struct {
+	// ID is the identifier the node must use.
+	ID domain.ID
+	// AddrChanged reports whether the advertised address differs from
+	// the persisted one.
+	AddrChanged bool
+	// Previous is the persisted identity, if any.
+	Previous *domain.Node
+	// Rejoin is true when the node must join under a new ID and hand off
+	// the keys it owned under the previous one.
+	Rejoin bool
+}
+
+// Resolve chooses the node ID given the persisted record (possibly nil),
+// the currently advertised address and the configured strategy.
+//
+// Behavior:
+//   - No record: the ID is derived from the address (first start).
+//   - Same address: the persisted ID is reused.
+//   - Address changed, StrategyPersisted: the persisted ID is kept.
+//   - Address changed, StrategyAddress: the ID is re-derived from the new
+//     address and Rejoin is set if it differs from the persisted one.
+func Resolve(sp domain.Space, strategy string, rec *Record, advertised string) (Decision, error) {
+	derived := sp.NewIdFromString(advertised)
+	if rec == nil {
+		return Decision{ID: derived}, nil
+	}
+
+	prevID, err := sp.FromHexString(rec.ID)
+	if err != nil {
+		return Decision{}, fmt.Errorf("identity: persisted ID %q: %w", rec.ID, err)
+	}
+	d := Decision{
+		Previous:    &domain.Node{ID: prevID, Addr: rec.Addr},
+		AddrChanged: rec.Addr != advertised,
+	}
+
+	switch strategy {
+	case StrategyPersisted, "":
+		d.ID = prevID
+	case StrategyAddress:
+		d.ID = derived
+		d.Rejoin = !derived.Equal(prevID)
+	default:
+		return Decision{}, fmt.Errorf("identity: unknown strategy %q", strategy)
+	}
+	return d, nil
+}
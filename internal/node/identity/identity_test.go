@@ -0,0 +1,182 @@
+package identity
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResolveAddressChange(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 4)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	oldAddr, newAddr := "10.0.0.1:4000", "10.0.0.2:4000"
+	oldID := sp.NewIdFromString(oldAddr)
+	newID := sp.NewIdFromString(newAddr)
+	rec := &Record{ID: oldID.ToHexString(true), Addr: oldAddr}
+
+	tests := []struct {
+		name        string
+		strategy    string
+		rec         *Record
+		addr        string
+		wantID      domain.ID
+		wantChanged bool
+		wantRejoin  bool
+	}{
+		{name: "primo avvio", strategy: StrategyPersisted, rec: nil, addr: newAddr, wantID: newID},
+		{name: "indirizzo invariato", strategy: StrategyAddress, rec: rec, addr: oldAddr, wantID: oldID},
+		{name: "persisted mantiene l'ID", strategy: StrategyPersisted, rec: rec, addr: newAddr, wantID: oldID, wantChanged: true},
+		{name: "default mantiene l'ID", strategy: "", rec: rec, addr: newAddr, wantID: oldID, wantChanged: true},
+		{name: "address rideriva l'ID", strategy: StrategyAddress, rec: rec, addr: newAddr, wantID: newID, wantChanged: true, wantRejoin: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Resolve(sp, tt.strategy, tt.rec, tt.addr)
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			if !d.ID.Equal(tt.wantID) {
+				t.Errorf("ID = %s, want %s", d.ID.ToHexString(true), tt.wantID.ToHexString(true))
+			}
+			if d.AddrChanged != tt.wantChanged {
+				t.Errorf("AddrChanged = %v, want %v", d.AddrChanged, tt.wantChanged)
+			}
+			if d.Rejoin != tt.wantRejoin {
+				t.Errorf("Rejoin = %v, want %v", d.Rejoin, tt.wantRejoin)
+			}
+			if tt.wantRejoin && (d.Previous == nil || !d.Previous.ID.Equal(oldID)) {
+				t.Errorf("Previous = %v, want old identity", d.Previous)
+			}
+		})
+	}
+
+	t.Run("strategia sconosciuta", func(t *testing.T) {
+		if _, err := Resolve(sp, "random", rec, newAddr); err == nil {
+			t.Fatal("expected error for unknown strategy")
+		}
+	})
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.id")
+
+	// File assente: nessun record e nessun errore
+	rec, err := Load(path)
+	if err != nil || rec != nil {
+		t.Fatalf("Load(missing) = %v, %v; want nil, nil", rec, err)
+	}
+
+	want := Record{ID: "0x1234", Addr: "10.0.0.1:4000"}
+	if err := Save(path, want); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	rec, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if rec == nil || *rec != want {
+		t.Fatalf("Load = %v, want %v", rec, want)
+	}
+}
+
+func TestAddressChangeRestart(t *testing.T) {
+	tests := []struct {
+		strategy   string
+		wantRejoin bool
+	}{
+		// il nodo riprende il vecchio ID e con esso le proprie chiavi
+		{strategy: StrategyPersisted},
+		// il nodo rientra con l'ID del nuovo indirizzo e cede le chiavi
+		{strategy: StrategyAddress, wantRejoin: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			r := testring.New(t, 4)
+			m := r.Members[1]
+			oldID := m.Node.Self().ID
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			var keys []domain.ID // chiavi di m prima del riavvio
+			for i := 0; i < 200 && len(keys) < 5; i++ {
+				k := fmt.Sprintf("key-%d", i)
+				id := r.Space.NewIdFromString(k)
+				if r.Owner(id) != m {
+					continue
+				}
+				if err := m.Node.Put(ctx, domain.Resource{Key: id, RawKey: k, Value: k}); err != nil {
+					t.Fatalf("Put %s: %v", k, err)
+				}
+				keys = append(keys, id)
+			}
+			if len(keys) == 0 {
+				t.Fatal("no key owned by the restarted member")
+			}
+
+			// Identità persistita come all'avvio del nodo
+			path := filepath.Join(t.TempDir(), "node.id")
+			if err := Save(path, Record{ID: oldID.ToHexString(true), Addr: m.Addr}); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			rec, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			// Il riavvio avviene dopo che l'anello ha dimenticato il vecchio
+			// indirizzo: altrimenti Join vedrebbe lo stesso ID e fallirebbe
+			// con ErrIDCollision
+			r.Kill(m)
+			r.WaitStable()
+			var d Decision
+			nm := r.Restart(m, func(addr string) domain.ID {
+				d, err = Resolve(r.Space, tt.strategy, rec, addr)
+				if err != nil {
+					t.Fatalf("Resolve: %v", err)
+				}
+				return d.ID
+			})
+			if !d.AddrChanged || d.Rejoin != tt.wantRejoin {
+				t.Fatalf("Decision = %+v, want AddrChanged and Rejoin %v", d, tt.wantRejoin)
+			}
+
+			if !tt.wantRejoin {
+				if !nm.Node.Self().ID.Equal(oldID) {
+					t.Fatalf("ID = %s, want the persisted %s", nm.Node.Self().ID.ToHexString(true), oldID.ToHexString(true))
+				}
+				r.WaitStable()
+				for _, id := range keys {
+					if owner := r.Owner(id); owner != nm {
+						t.Errorf("key %s owned by %s, want the restarted member", id.ToHexString(true), owner.Addr)
+					}
+					if _, err := nm.Node.RetrieveLocal(id); err != nil {
+						t.Errorf("key %s lost by the restarted member: %v", id.ToHexString(true), err)
+					}
+				}
+				return
+			}
+
+			// Senza manutenzione periodica solo HandOff consegna le chiavi,
+			// che un singolo passaggio copia senza cancellarle localmente
+			r.Pause(nm)
+			r.WaitStable()
+			nm.Node.HandOff(ctx)
+			for _, id := range keys {
+				owner := r.Owner(id)
+				if _, err := owner.Node.RetrieveLocal(id); err != nil {
+					t.Errorf("key %s not on its new owner %s: %v", id.ToHexString(true), owner.Addr, err)
+				}
+				if _, err := nm.Node.RetrieveLocal(id); err != nil {
+					t.Errorf("key %s deleted by a single HandOff: %v", id.ToHexString(true), err)
+				}
+			}
+		})
+	}
+}
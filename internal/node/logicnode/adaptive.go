@@ -0,0 +1,102 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"math"
+	"math/big"
+)
+
+// EstimateRingSize estimates the number of members of the ring from the
+// density of the nodes around this one: if the k gaps between the
+// predecessor, this node and its successors span d of the 2^b identifiers,
+// the ring holds about k·2^b/d members (never fewer than the nodes seen).
+// The estimate is only as accurate as the spacing of the nodes is uniform,
+// which hashed IDs make true on average; it improves with the list size.
+func (n *Node) EstimateRingSize() int {
+	self := n.rt.Self()
+	seen := map[string]struct{}{self.Addr: {}}
+	var last *domain.Node
+	for _, s := range n.rt.SuccessorList() {
+		if s.ID.Equal(self.ID) {
+			break // wrapped around the ring
+		}
+		if _, dup := seen[s.Addr]; !dup {
+			seen[s.Addr] = struct{}{}
+			last = s
+		}
+	}
+	if last == nil {
+		return 1
+	}
+	gaps := len(seen) - 1
+	from := self.ID
+	if pred := n.rt.GetPredecessor(); pred != nil {
+		if _, dup := seen[pred.Addr]; !dup {
+			seen[pred.Addr] = struct{}{}
+			from = pred.ID
+			gaps++
+		}
+	}
+
+	ring := new(big.Int).Lsh(big.NewInt(1), uint(n.Space().Bits))
+	dist := new(big.Int).Sub(last.ID.ToBigInt(), from.ToBigInt())
+	dist.Mod(dist, ring)
+	if dist.Sign() == 0 {
+		return len(seen)
+	}
+	est, _ := new(big.Rat).SetFrac(new(big.Int).Mul(ring, big.NewInt(int64(gaps))), dist).Float64()
+	if est > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return max(int(math.Round(est)), len(seen))
+}
+
+// successorListTarget returns the successor list size suited to a ring of
+// about size members, ⌈log2(size)⌉, bounded by [minSucc, maxSucc].
+func successorListTarget(size, minSucc, maxSucc int) int {
+	target := 1
+	if size > 1 {
+		target = int(math.Ceil(math.Log2(float64(size))))
+	}
+	return min(max(target, minSucc), maxSucc)
+}
+
+// adaptSuccessorList resizes the successor list to the size suited to the
+// current ring size estimate (see WithAdaptiveSuccessorList). Nodes that
+// drop out of a shrunk list are released from the client pool; the entries
+// of a grown list are filled by the next fixSuccessorList. It runs within
+// the Chord round, so it never overlaps fixSuccessorList.
+func (n *Node) adaptSuccessorList() {
+	est := n.EstimateRingSize()
+	target := successorListTarget(est, n.minSuccList, n.maxSuccList)
+	current := n.rt.SuccessorListSize()
+	if target == current {
+		return
+	}
+
+	dropped := n.rt.ResizeSuccessorList(target)
+	kept := make(map[string]struct{})
+	for _, s := range n.rt.SuccessorList() {
+		kept[s.Addr] = struct{}{}
+	}
+	released := make(map[string]struct{})
+	for _, d := range dropped {
+		if _, ok := kept[d.Addr]; ok {
+			continue
+		}
+		if _, ok := released[d.Addr]; ok {
+			continue
+		}
+		released[d.Addr] = struct{}{}
+		if err := n.cp.Release(d.Addr); err != nil {
+			n.lgr.Warn("adaptSuccessorList: release failed",
+				logger.FNode("node", d), logger.F("err", err))
+		}
+	}
+	n.lgr.Info("adaptSuccessorList: successor list resized",
+		logger.F("estimatedRingSize", est), logger.F("from", current), logger.F("to", target))
+	if target > current {
+		n.fixSuccessorList()
+	}
+}
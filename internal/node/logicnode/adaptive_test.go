@@ -0,0 +1,60 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveSuccessorListGrowsWithRing(t *testing.T) {
+	const minSize, maxSize = 2, 16
+	// con 24 membri i cicli di manutenzione ogni 20ms (default di testring)
+	// saturano una sola CPU sotto -race e le lookup di join scadono
+	const round = 100 * time.Millisecond
+	r := testring.New(t, 4,
+		testring.WithSpace(16, 2, 2),
+		testring.WithInterval(round),
+		testring.WithNodeOptions(
+			logicnode.WithAdaptiveSuccessorList(minSize, maxSize),
+		),
+	)
+
+	// meanSize restituisce la lunghezza media delle liste dei successori
+	meanSize := func() float64 {
+		total := 0
+		for _, m := range r.Live() {
+			size := m.Node.SuccessorListSize()
+			if size < minSize || size > maxSize {
+				t.Errorf("%s: successor list size %d outside [%d, %d]", m.Addr, size, minSize, maxSize)
+			}
+			total += size
+		}
+		return float64(total) / float64(len(r.Live()))
+	}
+
+	r.WaitStable()
+	time.Sleep(10 * round) // alcuni round per adattare le liste
+	prev := meanSize()
+
+	// La stima dei singoli nodi è rumorosa: si confronta la media sull'anello
+	for _, members := range []int{10, 24} {
+		for len(r.Members) < members {
+			r.Add()
+			r.WaitStable()
+		}
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			mean := meanSize()
+			if mean > prev {
+				t.Logf("%d members: mean successor list size %.2f (was %.2f)", members, mean, prev)
+				prev = mean
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%d members: mean successor list size %.2f, did not grow from %.2f", members, mean, prev)
+			}
+			time.Sleep(round)
+		}
+	}
+}
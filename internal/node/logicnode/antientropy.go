@@ -0,0 +1,177 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultSyncDepth is the depth of the Merkle trees compared by
+// anti-entropy when WithAntiEntropy is given none (256 leaves).
+const defaultSyncDepth = 8
+
+// Anti-entropy (WithAntiEntropy)
+//
+// resourceRepair only pushes misplaced resources outward, so it can neither
+// detect replicas that silently diverged nor recover resources a node
+// should hold but does not. Anti-entropy periodically compares the
+// resources of this node with those of its successor over the range both
+// are expected to hold, through a Merkle summary of it (see SyncDigest),
+// and transfers only the resources on which they differ:
+//   - resources missing here are pulled from the successor;
+//   - with replication, resources missing on the successor, or that differ
+//     from the local copy, are pushed to it: this node is the owner or an
+//     earlier replica of the range, so its copy wins.
+//
+// Without tombstones, a resource whose deletion reached only one of the
+// two nodes is restored on the other.
+
+// antiEntropy runs one anti-entropy pass with the successor, bounded by
+// the anti-entropy interval (or the failure timeout, if longer).
+func (n *Node) antiEntropy(ctx context.Context) {
+	if n.observer {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, max(n.syncInterval, n.cp.FailureTimeout()))
+	defer cancel()
+	self := n.rt.Self()
+	succ := n.rt.FirstSuccessor()
+	if succ == nil || succ.ID.Equal(self.ID) {
+		return
+	}
+	from, err := n.syncRangeStart(ctx)
+	if err != nil {
+		n.lgr.Warn("AntiEntropy: skipping pass", logger.F("err", err))
+		return
+	}
+	pushed, pulled, err := n.syncWith(ctx, succ, from, self.ID)
+	if err != nil {
+		n.lgr.Warn("AntiEntropy: sync with successor failed",
+			logger.FNode("successor", succ), logger.F("err", err))
+	}
+	if pushed > 0 || pulled > 0 {
+		n.lgr.Info("AntiEntropy: resources synchronized with successor",
+			logger.FNode("successor", succ), logger.F("pushed", pushed), logger.F("pulled", pulled))
+	}
+}
+
+// syncRangeStart returns the exclusive lower bound of the range that this
+// node and its successor are both expected to hold: the predecessor of this
+// node or, with R > 2 replicas, its (R-1)-th predecessor, since the
+// successor also holds the replicas of the ranges of this node's R-2
+// predecessors. Predecessors beyond the first are fetched with
+// GetPredecessor; the range stops growing at the first one that fails.
+func (n *Node) syncRangeStart(ctx context.Context) (domain.ID, error) {
+	self := n.rt.Self()
+	start := n.rt.GetPredecessor()
+	if start == nil {
+		return nil, errors.New("predecessor is nil")
+	}
+	for i := 2; i < n.replicas; i++ {
+		cli, release, err := n.clientFor(start.Addr)
+		if err != nil {
+			break
+		}
+		p, err := client.GetPredecessor(ctx, cli, n.Space())
+		release()
+		if err != nil || p == nil || p.ID.Equal(self.ID) {
+			break // wrapped around the ring
+		}
+		start = p
+	}
+	return start.ID, nil
+}
+
+// syncWith compares the resources with key in (from, to] stored here and
+// on peer, descending their Merkle trees level by level along the subtrees
+// whose hashes differ, then transfers the resources of the differing
+// leaves (see antiEntropy). It returns the number of resources pushed to
+// and pulled from peer.
+func (n *Node) syncWith(ctx context.Context, peer *domain.Node, from, to domain.ID) (pushed, pulled int, err error) {
+	cli, release, err := n.clientFor(peer.Addr)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer release()
+
+	depth := n.syncDepth
+	local := n.MerkleTree(from, to, depth)
+	indices := []int{0}
+	for level := 0; ; level++ {
+		remote, err := client.SyncDigest(ctx, cli, from, to, depth, level, indices)
+		if err != nil {
+			return 0, 0, err
+		}
+		var differ []int
+		for i, idx := range indices {
+			if !local.Equal(level, idx, remote[i]) {
+				differ = append(differ, idx)
+			}
+		}
+		if len(differ) == 0 {
+			return 0, 0, nil // in sync
+		}
+		if level == depth {
+			indices = differ
+			break
+		}
+		indices = make([]int, 0, 2*len(differ))
+		for _, idx := range differ {
+			indices = append(indices, 2*idx, 2*idx+1)
+		}
+	}
+
+	remote, err := client.SyncEntries(ctx, cli, n.Space(), from, to, depth, indices)
+	if err != nil {
+		return 0, 0, err
+	}
+	var push []domain.Resource
+	var missing []domain.ID
+	for _, leaf := range indices {
+		theirs := make(map[string][]byte, len(remote[leaf]))
+		for _, e := range remote[leaf] {
+			theirs[string(e.Key)] = e.Digest
+		}
+		for _, e := range local.Leaf(leaf) {
+			digest, ok := theirs[string(e.Key)]
+			delete(theirs, string(e.Key))
+			if (ok && bytes.Equal(digest, e.Digest)) || n.replicas <= 1 {
+				continue
+			}
+			if res, err := n.s.Get(e.Key); err == nil {
+				push = append(push, res)
+			}
+		}
+		for _, e := range remote[leaf] {
+			if _, ok := theirs[string(e.Key)]; ok {
+				missing = append(missing, e.Key)
+			}
+		}
+	}
+
+	if len(push) > 0 {
+		if err := n.storeReplicas(ctx, peer, push); err != nil {
+			return 0, 0, fmt.Errorf("push of %d resources: %w", len(push), err)
+		}
+		pushed = len(push)
+	}
+	for _, id := range missing {
+		res, err := client.RetrieveRemote(ctx, cli, n.Space(), id)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				continue // removed meanwhile
+			}
+			return pushed, pulled, fmt.Errorf("pull of %s: %w", id.ToHexString(true), err)
+		}
+		n.s.Put(*res)
+		pulled++
+	}
+	return pushed, pulled, nil
+}
@@ -0,0 +1,85 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAntiEntropyRepairsDivergence(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(t *testing.T, r *testring.Ring, owner, replica *testring.Member, key string)
+	}{
+		{
+			name: "replica lost",
+			damage: func(t *testing.T, r *testring.Ring, owner, replica *testring.Member, key string) {
+				if err := replica.Node.RemoveLocal(r.Space.NewIdFromString(key)); err != nil {
+					t.Fatalf("RemoveLocal on replica: %v", err)
+				}
+			},
+		},
+		{
+			name: "owner lost",
+			damage: func(t *testing.T, r *testring.Ring, owner, replica *testring.Member, key string) {
+				if err := owner.Node.RemoveLocal(r.Space.NewIdFromString(key)); err != nil {
+					t.Fatalf("RemoveLocal on owner: %v", err)
+				}
+			},
+		},
+		{
+			name: "replica diverged",
+			damage: func(t *testing.T, r *testring.Ring, owner, replica *testring.Member, key string) {
+				res, err := replica.Node.RetrieveLocal(r.Space.NewIdFromString(key))
+				if err != nil {
+					t.Fatalf("RetrieveLocal on replica: %v", err)
+				}
+				res.Value = "stale"
+				if err := replica.Node.StoreReplica(context.Background(), res); err != nil {
+					t.Fatalf("StoreReplica: %v", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 5, testring.WithNodeOptions(
+				logicnode.WithReplicas(replicas),
+				logicnode.WithAntiEntropy(50*time.Millisecond, 4)))
+			r.WaitStable()
+			stored := putKeys(t, r, 8)
+
+			// danneggia una copia della prima chiave: il responsabile o il suo successore
+			res := stored[0]
+			owner := r.Owner(res.Key)
+			live := r.Live()
+			var replica *testring.Member
+			for i, m := range live {
+				if m == owner {
+					replica = live[(i+1)%len(live)]
+				}
+			}
+			tt.damage(t, r, owner, replica, res.RawKey)
+
+			// l'anti-entropy ripristina una copia identica su tutti i nodi attesi
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				want := expectedHolders(r, res.Key)
+				ok := len(holders(r, res.Key)) == len(want)
+				for _, m := range holders(r, res.Key) {
+					got, _ := m.Node.RetrieveLocal(res.Key)
+					ok = ok && want[m.Addr] && got.Value == res.Value
+				}
+				if ok {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("copies of %s not repaired: held by %d nodes, want %d", res.RawKey, len(holders(r, res.Key)), len(want))
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestApproxLookup(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "single node", size: 1},
+		{name: "ring", size: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, tt.size)
+			r.WaitStable()
+			// WaitStable controlla solo il primo successore: le risposte esatte
+			// usano l'intera lista dei successori
+			deadline := time.Now().Add(5 * time.Second)
+			for _, m := range r.Members {
+				for !succsConverged(r, m, min(tt.size, m.Node.SuccessorListSize())) {
+					if time.Now().After(deadline) {
+						t.Fatal("successor lists did not converge")
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			}
+			r.StopStabilizers()
+			for _, m := range r.Members {
+				m.Node.FixDeBruijn()
+			}
+
+			for i := 0; i < 64; i++ {
+				key := r.Space.NewIdFromString(fmt.Sprintf("approx-%d", i))
+				owner := r.Owner(key).Node.Self()
+				for _, m := range r.Members {
+					self := m.Node.Self()
+					ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+					got, err := m.Node.LookUp(ctx, key)
+					cancel()
+					if err != nil {
+						t.Fatalf("LookUp(%s) from %s: %v", key.ToHexString(true), self.Addr, err)
+					}
+					if !got.ID.Equal(owner.ID) {
+						t.Fatalf("LookUp(%s) = %s, want %s", key.ToHexString(true), got.Addr, owner.Addr)
+					}
+
+					approx, exact := m.Node.ApproxLookup(key)
+					if exact {
+						if !approx.ID.Equal(owner.ID) {
+							t.Errorf("ApproxLookup(%s) from %s = %s (exact), want %s",
+								key.ToHexString(true), self.Addr, approx.Addr, owner.Addr)
+						}
+						continue
+					}
+					// stima approssimata: un nodo che precede la chiave, tra
+					// l'origine (inclusa) e il proprietario (escluso)
+					if approx.ID.Equal(owner.ID) || key.Between(self.ID, approx.ID) {
+						t.Errorf("ApproxLookup(%s) from %s = %s, not preceding the key (owner %s)",
+							key.ToHexString(true), self.Addr, approx.Addr, owner.Addr)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"testing"
+	"time"
+)
+
+// converged verifica che la lista dei successori e il predecessore di m
+// coincidano con i membri vivi dell'anello e che la finestra de Bruijn
+// non contenga nodi morti.
+func converged(r *testring.Ring, m *testring.Member) bool {
+	live := r.Live()
+	idx := -1
+	alive := make(map[string]bool)
+	for i, x := range live {
+		alive[x.Addr] = true
+		if x == m {
+			idx = i
+		}
+	}
+	succs := m.Node.SuccessorList()
+	for j := 0; j < len(succs) && j < len(live)-1; j++ {
+		want := live[(idx+1+j)%len(live)]
+		if succs[j] == nil || succs[j].Addr != want.Addr {
+			return false
+		}
+	}
+	pred := m.Node.Predecessor()
+	if pred == nil || pred.Addr != live[(idx-1+len(live))%len(live)].Addr {
+		return false
+	}
+	debruijn := m.Node.DeBruijnList()
+	if len(debruijn) == 0 {
+		return false
+	}
+	for _, d := range debruijn {
+		if d != nil && !alive[d.Addr] {
+			return false
+		}
+	}
+	return true
+}
+
+// rejoinConvergence sospende un nodo, uccide due nodi della sua lista di
+// successori, lo riavvia con un intervallo di stabilizzazione lento e
+// misura il tempo necessario perché il suo stato di routing torni corretto.
+func rejoinConvergence(t *testing.T, opts ...testring.Option) time.Duration {
+	t.Helper()
+	const slow = 400 * time.Millisecond
+
+	r := testring.New(t, 7, opts...)
+	m := r.Members[0]
+	r.Pause(m)
+	time.Sleep(50 * time.Millisecond) // lascia terminare il round di stabilizzazione in corso
+	r.Kill(r.Members[2])
+	r.Kill(r.Members[3])
+	r.WaitStable()
+	if converged(r, m) {
+		t.Fatalf("paused node already has up-to-date pointers")
+	}
+
+	start := time.Now()
+	r.Resume(m, slow)
+	for !converged(r, m) {
+		if time.Since(start) > 10*time.Second {
+			t.Fatalf("node did not converge after rejoin")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return time.Since(start)
+}
+
+func TestCatchUpAfterRejoin(t *testing.T) {
+	without := rejoinConvergence(t)
+	with := rejoinConvergence(t, testring.WithNodeOptions(logicnode.WithCatchUp(10*time.Millisecond, 100)))
+	t.Logf("convergence after rejoin: without catch-up %v, with catch-up %v", without, with)
+
+	if with >= without/2 {
+		t.Errorf("catch-up did not speed up convergence: with %v, without %v", with, without)
+	}
+}
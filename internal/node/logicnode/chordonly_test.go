@@ -0,0 +1,36 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLookupWithDeBruijnDisabled(t *testing.T) {
+	r := testring.New(t, 6, testring.WithNodeOptions(logicnode.WithDeBruijn(false)))
+
+	for _, m := range r.Members {
+		if d := m.Node.DeBruijnList(); len(d) != 0 {
+			t.Fatalf("member %s keeps %d de Bruijn pointers in Chord-only mode", m.Addr, len(d))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// Ogni membro risolve ogni chiave percorrendo solo l'anello dei successori
+	for _, origin := range r.Members {
+		for i := 0; i < 32; i++ {
+			id := r.Space.NewIdFromString(fmt.Sprintf("key-%d", i))
+			got, err := origin.Node.LookUp(ctx, id)
+			if err != nil {
+				t.Fatalf("LookUp(%s) from %s: %v", id.ToHexString(true), origin.Addr, err)
+			}
+			if want := r.Owner(id); got.Addr != want.Addr {
+				t.Errorf("LookUp(%s) from %s = %s, want %s", id.ToHexString(true), origin.Addr, got.Addr, want.Addr)
+			}
+		}
+	}
+}
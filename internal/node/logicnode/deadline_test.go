@@ -0,0 +1,211 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// hopRecorder registra la deadline residua di ogni FindSuccessor ricevuta.
+type hopRecorder struct {
+	mu        sync.Mutex
+	deadlines []time.Time
+}
+
+func (h *hopRecorder) interceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if info.FullMethod == "/dht.v1.DHT/FindSuccessor" {
+		d, _ := ctx.Deadline()
+		h.mu.Lock()
+		h.deadlines = append(h.deadlines, d)
+		h.mu.Unlock()
+	}
+	return handler(ctx, req)
+}
+
+func (h *hopRecorder) reset() []time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := h.deadlines
+	h.deadlines = nil
+	return out
+}
+
+func TestLookupHopBudget(t *testing.T) {
+	const (
+		reserve   = 10 * time.Millisecond
+		minBudget = 20 * time.Millisecond
+	)
+	rec := &hopRecorder{}
+	r := testring.New(t, 8,
+		testring.WithNodeOptions(logicnode.WithHopBudget(reserve, minBudget)),
+		testring.WithServerOptions(server.WithUnaryInterceptors(rec.interceptor)),
+	)
+	r.StopStabilizers()
+	time.Sleep(50 * time.Millisecond) // lascia terminare le RPC di manutenzione in volo
+	rec.reset()
+
+	// Target che richiedono almeno un inoltro remoto partendo da origin
+	origin := r.Members[0]
+	var targets []domain.ID
+	for _, m := range r.Members[2:] {
+		targets = append(targets, m.Node.Self().ID)
+	}
+
+	t.Run("near-expiry deadline", func(t *testing.T) {
+		for _, target := range targets {
+			ctx, cancel := context.WithTimeout(context.Background(), minBudget/2)
+			start := time.Now()
+			_, err := origin.Node.FindSuccessorInit(ctx, target)
+			elapsed := time.Since(start)
+			cancel()
+			if status.Code(err) != codes.DeadlineExceeded {
+				t.Fatalf("target %s: got %v, want DeadlineExceeded", target.ToHexString(true), err)
+			}
+			if elapsed > minBudget/2+5*time.Millisecond {
+				t.Errorf("target %s: lookup returned after %v, past its deadline", target.ToHexString(true), elapsed)
+			}
+		}
+		if hops := rec.reset(); len(hops) != 0 {
+			t.Errorf("%d hop(s) forwarded without enough budget", len(hops))
+		}
+	})
+
+	t.Run("forwarded hops shorten the deadline", func(t *testing.T) {
+		const timeout = 2 * time.Second
+		for _, target := range targets {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			orig, _ := ctx.Deadline()
+			got, err := origin.Node.FindSuccessorInit(ctx, target)
+			cancel()
+			if err != nil {
+				t.Fatalf("target %s: %v", target.ToHexString(true), err)
+			}
+			if want := r.Owner(target).Node.Self(); !got.ID.Equal(want.ID) {
+				t.Fatalf("target %s: got %s, want %s", target.ToHexString(true), got.ID.ToHexString(true), want.ID.ToHexString(true))
+			}
+			hops := rec.reset()
+			if len(hops) == 0 {
+				t.Fatalf("target %s: lookup was not forwarded", target.ToHexString(true))
+			}
+			// Ogni hop cede al successivo la propria deadline meno reserve,
+			// qualunque sia la profondità: l'i-esimo hop scade circa
+			// reserve·i prima della deadline originale, non una frazione
+			// crescente di essa. gRPC trasmette il timeout residuo, quindi
+			// ogni deadline ricevuta slitta in avanti del tempo di transito.
+			const transit = 3 * time.Millisecond
+			prev := orig
+			for i, d := range hops {
+				if d.IsZero() || d.After(prev.Add(-reserve+transit)) || d.Before(prev.Add(-reserve)) {
+					t.Errorf("target %s: hop %d deadline %v, want reserve %v before %v", target.ToHexString(true), i+1, d, reserve, prev)
+				}
+				prev = d
+			}
+		}
+	})
+}
+
+func TestOwnerBudget(t *testing.T) {
+	const (
+		ownerReserve = 0.2
+		minBudget    = 20 * time.Millisecond
+		timeout      = time.Second
+	)
+	rec := &hopRecorder{}
+	r := testring.New(t, 4,
+		testring.WithNodeOptions(
+			logicnode.WithHopBudget(2*time.Millisecond, minBudget),
+			logicnode.WithOwnerReserve(ownerReserve),
+			logicnode.WithOwnerRetries(0)),
+		testring.WithServerOptions(server.WithUnaryInterceptors(rec.interceptor)),
+	)
+	r.WaitStable()
+	r.StopStabilizers()
+	time.Sleep(50 * time.Millisecond) // lascia terminare le RPC di manutenzione in volo
+	origin, owner := r.Members[0], r.Members[2]
+
+	// chiave posseduta da un nodo diverso dall'origine
+	var key string
+	for i := 0; key == "" && i < 1<<20; i++ {
+		if k := fmt.Sprintf("key-%d", i); r.Owner(r.Space.NewIdFromString(k)) == owner {
+			key = k
+		}
+	}
+	if key == "" {
+		t.Skip("no key owned by the target member")
+	}
+	id := r.Space.NewIdFromString(key)
+
+	t.Run("lookup leaves the reserve to the owner", func(t *testing.T) {
+		rec.reset()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		orig, _ := ctx.Deadline()
+		if err := origin.Node.Put(ctx, domain.Resource{Key: id, RawKey: key, Value: "v"}); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		hops := rec.reset()
+		if len(hops) == 0 {
+			t.Fatal("lookup was not forwarded")
+		}
+		// nessun hop del lookup può consumare la quota riservata al proprietario
+		limit := orig.Add(-time.Duration(float64(timeout)*ownerReserve) + 20*time.Millisecond)
+		for _, d := range hops {
+			if d.IsZero() || d.After(limit) {
+				t.Errorf("hop deadline %v past the owner reserve (limit %v)", d, limit)
+			}
+		}
+	})
+
+	// il lookup termina quando mancano meno di minBudget alla scadenza
+	// (se lo scheduler ritarda il risveglio oltre la scadenza, l'errore deve
+	// essere lo stesso) oppure dopo la scadenza
+	tests := []struct {
+		name  string
+		op    string        // "put", "get" o "delete"
+		after time.Duration // fine del lookup rispetto alla scadenza
+	}{
+		{name: "put", op: "put", after: -minBudget / 2},
+		{name: "get", op: "get", after: -minBudget / 2},
+		{name: "delete", op: "delete", after: -minBudget / 2},
+		{name: "put after the deadline", op: "put", after: time.Millisecond},
+		{name: "get after the deadline", op: "get", after: time.Millisecond},
+		{name: "delete after the deadline", op: "delete", after: time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run("no time left for the owner/"+tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			deadline, _ := ctx.Deadline()
+			origin.Node.OnOwnerResolved(func(*domain.Node) {
+				time.Sleep(time.Until(deadline.Add(tt.after)))
+			})
+			defer origin.Node.OnOwnerResolved(nil)
+			var err error
+			switch tt.op {
+			case "put":
+				err = origin.Node.Put(ctx, domain.Resource{Key: id, RawKey: key, Value: "v"})
+			case "get":
+				_, err = origin.Node.Get(ctx, id)
+			case "delete":
+				err = origin.Node.Delete(ctx, id)
+			}
+			if status.Code(err) != codes.DeadlineExceeded {
+				t.Fatalf("%s: got %v, want DeadlineExceeded", tt.op, err)
+			}
+			if msg := err.Error(); !strings.Contains(msg, tt.op) || !strings.Contains(msg, owner.Addr) {
+				t.Errorf("%s: error %q does not name the operation and the owner", tt.op, msg)
+			}
+		})
+	}
+}
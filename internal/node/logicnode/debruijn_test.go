@@ -0,0 +1,67 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/testring"
+	"testing"
+)
+
+func TestFixDeBruijnDeadAnchor(t *testing.T) {
+	r := testring.New(t, 6)
+	r.StopStabilizers()
+
+	// Cerca un nodo il cui anchor (cifra 0 della finestra) sia un altro membro
+	var owner, anchor *testring.Member
+	for _, m := range r.Members {
+		window := m.Node.DeBruijnList()
+		if len(window) == 0 || window[0] == nil || window[0].ID.Equal(m.Node.Self().ID) {
+			continue
+		}
+		for _, other := range r.Members {
+			if other.Node.Self().ID.Equal(window[0].ID) {
+				owner, anchor = m, other
+			}
+		}
+		if owner != nil {
+			break
+		}
+	}
+	if owner == nil {
+		t.Skip("no node with a remote de Bruijn anchor")
+	}
+
+	r.Kill(anchor)
+	owner.Node.FixDeBruijn()
+
+	window := owner.Node.DeBruijnList()
+	if len(window) == 0 || window[0] == nil {
+		t.Fatal("de Bruijn window was not refreshed")
+	}
+	if window[0].Addr == anchor.Addr {
+		t.Fatalf("window still anchored at dead node %s", anchor.Addr)
+	}
+	alive := false
+	for _, m := range r.Live() {
+		if m.Addr == window[0].Addr {
+			alive = true
+		}
+	}
+	if !alive {
+		t.Fatalf("new anchor %s is not a live ring member", window[0].Addr)
+	}
+
+	if got := owner.Node.AnchorFailures(anchor.Addr); got == 0 {
+		t.Fatalf("no failure recorded for dead anchor %s", anchor.Addr)
+	}
+
+	// Una volta riparato l'anello il nodo morto non è più un candidato:
+	// il suo contatore viene dimenticato
+	for _, m := range r.Live() {
+		r.Resume(m, 0)
+	}
+	r.WaitStable()
+	r.StopStabilizers()
+	owner.Node.FixDeBruijn()
+	if got := owner.Node.AnchorFailures(anchor.Addr); got != 0 {
+		t.Errorf("failures of departed anchor %s = %d after a refresh without it, want 0", anchor.Addr, got)
+	}
+}
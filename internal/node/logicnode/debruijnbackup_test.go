@@ -0,0 +1,106 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDeBruijnBackups(t *testing.T) {
+	const backups = 2
+	r := testring.New(t, 8, testring.WithRoutingTableOptions(routingtable.WithDeBruijnBackups(backups)))
+	r.StopStabilizers()
+	for _, m := range r.Members {
+		m.Node.FixDeBruijn()
+	}
+
+	index := make(map[string]int)
+	for i, m := range r.Members {
+		index[m.Addr] = i
+	}
+	// le riserve di una cifra sono i nodi che precedono il puntatore sull'anello, il più vicino per primo
+	full := 0
+	for _, m := range r.Members {
+		for digit, ptr := range m.Node.DeBruijnList() {
+			got := m.Node.DeBruijnBackups(digit)
+			if len(got) > backups {
+				t.Fatalf("node %s digit %d: %d backups, want at most %d", m.Addr, digit, len(got), backups)
+			}
+			if len(got) == backups {
+				full++
+			}
+			for k, b := range got {
+				want := r.Members[(index[ptr.Addr]-k-1+2*len(r.Members))%len(r.Members)]
+				if b.Addr != want.Addr {
+					t.Errorf("node %s digit %d: backup %d is %s, want %s", m.Addr, digit, k, b.Addr, want.Addr)
+				}
+				if b.Addr != m.Addr && !m.Node.Pooled(b.Addr) {
+					t.Errorf("node %s digit %d: backup %s not in the client pool", m.Addr, digit, b.Addr)
+				}
+			}
+		}
+	}
+	if full == 0 {
+		t.Fatal("no de Bruijn digit got all its backups")
+	}
+
+	// Ogni puntatore candidato al passo de Bruijn è seguito dalle sue riserve
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	withBackups := 0
+	for _, m := range r.Members {
+		window := m.Node.DeBruijnList()
+		for i := 0; i < 20; i++ {
+			hop, err := m.Node.NextHopInit(ctx, r.Space.NewIdFromString(fmt.Sprintf("key-%d", i)))
+			if err != nil {
+				t.Fatalf("NextHopInit: %v", err)
+			}
+			for c := 0; c < len(hop.DeBruijnIdx); c++ {
+				digit := hop.DeBruijnIdx[c]
+				if hop.Candidates[c].Addr != window[digit].Addr {
+					continue // riserva, già controllata col suo puntatore
+				}
+				for k, b := range m.Node.DeBruijnBackups(digit) {
+					if c+1+k < len(hop.DeBruijnIdx) && hop.Candidates[c+1+k].Addr == b.Addr {
+						withBackups++
+					}
+				}
+			}
+		}
+	}
+	if withBackups == 0 {
+		t.Fatal("no lookup step offered de Bruijn backups as candidates")
+	}
+
+	// Un puntatore de Bruijn muore: con i successori riparati ma la finestra
+	// ferma, i lookup proseguono dalle riserve
+	var from, dead *testring.Member
+	for _, m := range r.Members {
+		if w := m.Node.DeBruijnList(); len(w) > 0 && w[0] != nil && w[0].Addr != m.Addr {
+			from, dead = m, r.Members[index[w[0].Addr]]
+			break
+		}
+	}
+	if from == nil {
+		t.Fatal("no member with a remote de Bruijn pointer for digit 0")
+	}
+	r.Kill(dead)
+	for round := 0; round < 3; round++ {
+		for _, m := range r.Live() {
+			m.Node.ChordRound()
+		}
+	}
+	for i := 0; i < 50; i++ {
+		id := r.Space.NewIdFromString(fmt.Sprintf("key-%d", i))
+		got, err := from.Node.FindSuccessorRecursive(ctx, id)
+		if err != nil {
+			t.Fatalf("lookup of key-%d with pointer %s down: %v", i, dead.Addr, err)
+		}
+		if want := r.Owner(id); got.Addr != want.Addr {
+			t.Errorf("lookup of key-%d: got %s, want %s", i, got.Addr, want.Addr)
+		}
+	}
+}
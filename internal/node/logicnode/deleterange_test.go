@@ -0,0 +1,58 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeleteRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas int
+		from, to uint64 // estremi dell'intervallo (from, to] nello spazio a 16 bit
+	}{
+		{name: "linear", replicas: 1, from: 0x1000, to: 0x9000},
+		{name: "wrap-around", replicas: 1, from: 0xc000, to: 0x4000},
+		{name: "whole ring", replicas: 1, from: 0x5000, to: 0x5000},
+		{name: "narrow", replicas: 1, from: 0x2000, to: 0x2800},
+		{name: "replicated", replicas: 3, from: 0xc000, to: 0x4000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 5, testring.WithNodeOptions(logicnode.WithReplicas(tt.replicas)))
+			res := putKeys(t, r, 40)
+			from, to := r.Space.FromUint64(tt.from), r.Space.FromUint64(tt.to)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			removed, err := r.Members[0].Node.DeleteRange(ctx, from, to)
+			if err != nil {
+				t.Fatalf("DeleteRange: %v", err)
+			}
+
+			// nessuna copia delle chiavi nell'intervallo resta nell'anello,
+			// tutte le altre restano con tutte le loro copie
+			inRange := 0
+			for _, rs := range res {
+				got := len(holders(r, rs.Key))
+				if rs.Key.Between(from, to) {
+					inRange++
+					if got != 0 {
+						t.Errorf("key %s in range still has %d copies", rs.RawKey, got)
+					}
+				} else if got != tt.replicas {
+					t.Errorf("key %s out of range has %d copies, want %d", rs.RawKey, got, tt.replicas)
+				}
+			}
+			if inRange == 0 {
+				t.Fatalf("no key in the range, the case tests nothing")
+			}
+			if want := inRange * tt.replicas; removed != want {
+				t.Errorf("DeleteRange removed %d resources, want %d", removed, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestStopDrainsOperations(t *testing.T) {
+	const delay = 300 * time.Millisecond
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		drained bool // la Put in corso termina prima che il pool venga chiuso
+	}{
+		{name: "drained", timeout: 5 * time.Second, drained: true},
+		{name: "timeout", timeout: 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Store lente verso il responsabile, per avere una Put in volo durante lo Stop
+			started := make(chan struct{}, 1)
+			slow := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				if strings.HasSuffix(method, "/Store") {
+					select {
+					case started <- struct{}{}:
+					default:
+					}
+					time.Sleep(delay)
+				}
+				return streamer(ctx, desc, cc, method, opts...)
+			}
+			r := testring.New(t, 3,
+				testring.WithNodeOptions(logicnode.WithDrainTimeout(tt.timeout)),
+				testring.WithPoolOptions(client.WithStreamInterceptors(slow)))
+			m := r.Members[0]
+
+			var res domain.Resource
+			for i := 0; ; i++ {
+				raw := fmt.Sprintf("key-%d", i)
+				res = domain.Resource{Key: r.Space.NewIdFromString(raw), RawKey: raw, Value: raw}
+				if r.Owner(res.Key) != m {
+					break
+				}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			putErr := make(chan error, 1)
+			go func() { putErr <- m.Node.Put(ctx, res) }()
+			select {
+			case <-started:
+			case err := <-putErr:
+				t.Fatalf("Put completed without a Store to the owner: %v", err)
+			}
+
+			stopped := make(chan struct{})
+			start := time.Now()
+			go func() {
+				m.Node.Stop()
+				close(stopped)
+			}()
+
+			// le operazioni avviate dopo lo Stop vengono rifiutate
+			deadline := time.Now().Add(time.Second)
+			for {
+				_, err := m.Node.Get(ctx, res.Key)
+				if errors.Is(err, logicnode.ErrStopping) {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("Get after Stop: got %v, want ErrStopping", err)
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+
+			<-stopped
+			if tt.drained {
+				if err := <-putErr; err != nil {
+					t.Errorf("in-flight Put failed: %v", err)
+				}
+				if !holds(r.Owner(res.Key), res.Key) {
+					t.Errorf("owner does not hold the key stored by the in-flight Put")
+				}
+			} else if elapsed := time.Since(start); elapsed >= delay {
+				t.Errorf("Stop took %v, want it bounded by the drain timeout %v", elapsed, tt.timeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLookupErrors(t *testing.T) {
+	r := testring.New(t, 1)
+
+	// Nodo che non è mai entrato nell'anello: nessun successore
+	self := &domain.Node{ID: r.Space.NewIdFromString("uninitialized"), Addr: "127.0.0.1:2"}
+	cp := client.New(self.ID, self.Addr, time.Second)
+	defer cp.Close()
+	n := logicnode.New(routingtable.New(self, r.Space), cp, storage.NewMemoryStorage(&logger.NopLogger{}))
+
+	key := r.Space.NewIdFromString("key")
+	tests := []struct {
+		name    string
+		op      func(ctx context.Context) error
+		failure bool // l'errore porta anche un domain.Failure di routing
+	}{
+		{name: "put", failure: true, op: func(ctx context.Context) error {
+			return n.Put(ctx, domain.Resource{Key: key, RawKey: "key", Value: "value"})
+		}},
+		{name: "get", failure: true, op: func(ctx context.Context) error {
+			_, err := n.Get(ctx, key)
+			return err
+		}},
+		{name: "delete", failure: true, op: func(ctx context.Context) error {
+			return n.Delete(ctx, key)
+		}},
+		{name: "lookup", op: func(ctx context.Context) error {
+			_, err := n.LookUp(ctx, key)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			err := tt.op(ctx)
+			if !errors.Is(err, logicnode.ErrLookupFailed) || !errors.Is(err, logicnode.ErrRoutingNotInitialized) {
+				t.Fatalf("got %v, want ErrLookupFailed wrapping ErrRoutingNotInitialized", err)
+			}
+			if errors.Is(err, domain.ErrResourceNotFound) {
+				t.Errorf("%v is reported as a missing resource", err)
+			}
+			var f *domain.Failure
+			if tt.failure && (!errors.As(err, &f) || f.Stage != domain.StageRouting) {
+				t.Errorf("%v does not carry a routing failure", err)
+			}
+		})
+	}
+}
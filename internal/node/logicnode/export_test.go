@@ -0,0 +1,65 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/domain"
+	"context"
+	"time"
+)
+
+// FixDeBruijn exposes the de Bruijn stabilizer to the external tests.
+func (n *Node) FixDeBruijn() { n.fixDeBruijn() }
+
+// DeBruijnRound runs one pass of the de Bruijn stabilizer, after the one
+// in progress, if any.
+func (n *Node) DeBruijnRound() { n.deBruijnRound(context.Background()) }
+
+// WithClock replaces the clock of the lazy de Bruijn refresh.
+func WithClock(now func() time.Time) Option {
+	return func(n *Node) { n.now = now }
+}
+
+// AnchorFailures returns the failures recorded for the anchor candidate
+// at addr.
+func (n *Node) AnchorFailures(addr string) int {
+	return n.anchorFailures(&domain.Node{Addr: addr})
+}
+
+// FindSuccessorRecursive runs a recursive lookup of target regardless of
+// the lookup mode of the node.
+func (n *Node) FindSuccessorRecursive(ctx context.Context, target domain.ID) (*domain.Node, error) {
+	hop, err := n.initialHop(target)
+	if err != nil || hop.Successor != nil {
+		return hop.Successor, err
+	}
+	return n.FindSuccessorStep(ctx, target, hop.CurrentI, hop.KShift)
+}
+
+// Pooled reports whether the client pool of the node holds a connection
+// to addr.
+func (n *Node) Pooled(addr string) bool {
+	_, err := n.cp.GetFromPool(addr)
+	return err == nil
+}
+
+// ChordRound runs one pass of the Chord stabilizers.
+func (n *Node) ChordRound() { n.chordRound(context.Background()) }
+
+// SetPredecessor overwrites the predecessor of the node.
+func (n *Node) SetPredecessor(p *domain.Node) { n.rt.SetPredecessor(p) }
+
+// Jittered exposes the randomization of the stabilizer intervals.
+func (n *Node) Jittered(d time.Duration) time.Duration { return n.jittered(d) }
+
+// FixSuccessorList runs the successor list repair of the Chord stabilizer.
+func (n *Node) FixSuccessorList() { n.fixSuccessorList() }
+
+// SetSuccessorList overwrites the successor list of the node, without
+// adjusting the references of the client pool.
+func (n *Node) SetSuccessorList(list []*domain.Node) { n.rt.SetSuccessorList(list) }
+
+// DeBruijnBackups returns the backups of a de Bruijn digit.
+func (n *Node) DeBruijnBackups(digit int) []*domain.Node { return n.rt.DeBruijnBackups(digit) }
+
+// OnOwnerResolved installs f as the hook called with every owner found by
+// the lookup of a client operation, before the owner is contacted.
+func (n *Node) OnOwnerResolved(f func(*domain.Node)) { n.ownerResolved = f }
@@ -0,0 +1,62 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/domain"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Failure annotates err with the stage at which an operation failed.
+//
+// The last hop is the one reported by the failure of a downstream node, if
+// err carries one (i.e. the deepest node that handled the request);
+// otherwise it is this node. So is the cause, unless err is a local one
+// with a cause of its own (ErrMaxHops).
+func (n *Node) Failure(stage domain.FailureStage, err error) *domain.Failure {
+	lastHop, cause := n.rt.Self().Addr, ""
+	if f, ok := domain.FailureFromError(err); ok {
+		if f.LastHop != "" {
+			lastHop = f.LastHop
+		}
+		cause = f.Cause
+	}
+	if errors.Is(err, ErrMaxHops) {
+		cause = domain.CauseMaxHops
+	}
+	return &domain.Failure{
+		Stage:     stage,
+		Cause:     cause,
+		LastHop:   lastHop,
+		Retryable: retryable(stage, err),
+		Err:       err,
+	}
+}
+
+// opError wraps err in a Failure unless it reports a missing key, which is
+// an outcome of the operation rather than a failure.
+func (n *Node) opError(stage domain.FailureStage, err error) error {
+	if errors.Is(err, domain.ErrResourceNotFound) || status.Code(err) == codes.NotFound {
+		return err
+	}
+	return n.Failure(stage, err)
+}
+
+// retryable reports whether an operation that failed with err at the given
+// stage may succeed if retried later. Routing and transfer failures are
+// usually transient (the ring repairs itself), storage failures are not,
+// except a full storage, which frees up as resources are deleted or expire,
+// and a store rejected until the owner learns its predecessor.
+func retryable(stage domain.FailureStage, err error) bool {
+	if errors.Is(err, domain.ErrStorageFull) || errors.Is(err, ErrNoPredecessor) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.DataLoss:
+		return false
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted, codes.ResourceExhausted:
+		return true
+	}
+	return stage != domain.StageStorage
+}
@@ -0,0 +1,91 @@
+package logicnode_test
+
+import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestFindPredecessor(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []testring.Option
+	}{
+		{name: "de Bruijn"},
+		{name: "Chord only", opts: []testring.Option{testring.WithNodeOptions(logicnode.WithDeBruijn(false))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 8, tt.opts...)
+
+			// predecessore atteso: il membro che precede il responsabile dell'ID
+			predOf := func(id domain.ID) *testring.Member {
+				owner := r.Owner(id)
+				for i, m := range r.Members {
+					if m == owner {
+						return r.Members[(i-1+len(r.Members))%len(r.Members)]
+					}
+				}
+				t.Fatalf("owner of %s not among the members", id.ToHexString(true))
+				return nil
+			}
+
+			// ogni lookup ha la propria deadline: una condivisa da centinaia
+			// di lookup si esaurisce sotto -race
+			lookupCtx := func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 5*time.Second)
+			}
+			var ids []domain.ID
+			for i := 0; i < 32; i++ {
+				ids = append(ids, r.Space.NewIdFromString(fmt.Sprintf("key-%d", i)))
+			}
+			// anche gli ID dei membri: il predecessore di n.ID è il membro prima di n
+			for _, m := range r.Members {
+				ids = append(ids, m.Node.Self().ID)
+			}
+
+			for _, origin := range r.Members {
+				for _, id := range ids {
+					ctx, cancel := lookupCtx()
+					got, err := origin.Node.FindPredecessor(ctx, id)
+					cancel()
+					if err != nil {
+						t.Fatalf("FindPredecessor(%s) from %s: %v", id.ToHexString(true), origin.Addr, err)
+					}
+					if want := predOf(id); got.Addr != want.Addr {
+						t.Errorf("FindPredecessor(%s) from %s = %s, want %s",
+							id.ToHexString(true), origin.Addr, got.Addr, want.Addr)
+					}
+				}
+			}
+
+			// Stesso risultato attraverso l'RPC
+			conn, err := grpc.NewClient(r.Members[0].Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer conn.Close()
+			cli := dhtv1.NewDHTClient(conn)
+			for _, id := range ids {
+				ctx, cancel := lookupCtx()
+				got, err := client.FindPredecessor(ctx, cli, &r.Space, id)
+				cancel()
+				if err != nil {
+					t.Fatalf("client.FindPredecessor(%s): %v", id.ToHexString(true), err)
+				}
+				if want := predOf(id); got.Addr != want.Addr {
+					t.Errorf("client.FindPredecessor(%s) = %s, want %s", id.ToHexString(true), got.Addr, want.Addr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/logger"
+	"context"
+	"sync"
+	"time"
+)
+
+// intervalController adapts the period of a stabilization loop to the churn
+// of the ring (see WithAdaptiveStabilization): every round that leaves the
+// observed routing state unchanged doubles the interval, up to max, and any
+// change brings it back to min.
+type intervalController struct {
+	name     string // loop name, for the logs
+	min, max time.Duration
+	lgr      logger.Logger
+
+	mu   sync.Mutex
+	cur  time.Duration
+	wake chan struct{} // signals the loop that the interval was reset from outside
+}
+
+func newIntervalController(name string, min, max time.Duration, lgr logger.Logger) *intervalController {
+	return &intervalController{
+		name: name,
+		min:  min,
+		max:  max,
+		lgr:  lgr,
+		cur:  min,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// current returns the interval before the next round.
+func (c *intervalController) current() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cur
+}
+
+// observe records the outcome of a round and returns the interval before
+// the next one.
+func (c *intervalController) observe(changed bool) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if changed {
+		c.set(c.min, "routing state changed")
+	} else {
+		c.set(min(2*c.cur, c.max), "routing state unchanged")
+	}
+	return c.cur
+}
+
+// reset brings the interval back to min, waking the loop if it is waiting
+// for a longer one.
+func (c *intervalController) reset(reason string) {
+	c.mu.Lock()
+	changed := c.set(c.min, reason)
+	c.mu.Unlock()
+	if changed {
+		select {
+		case c.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// set updates the interval and logs the transition, if any. c.mu must be held.
+func (c *intervalController) set(d time.Duration, reason string) bool {
+	if d == c.cur {
+		return false
+	}
+	c.lgr.Debug("stabilization interval changed",
+		logger.F("loop", c.name), logger.F("from", c.cur.String()), logger.F("to", d.String()),
+		logger.F("reason", reason))
+	c.cur = d
+	return true
+}
+
+// adaptiveLoop runs round with the interval given by c until ctx is
+// canceled. After every round the state returned by state is compared with
+// the one observed after the previous round, so changes made between two
+// rounds (e.g. a Notify from a new predecessor) count as well; onChange,
+// if not nil, is called when it differs. Rounds skipped because
+// stabilization is paused leave the interval unchanged. Every wait is
+// jittered around the interval of c (see jittered).
+func (n *Node) adaptiveLoop(ctx context.Context, c *intervalController, round func(context.Context), state func() string, onChange func()) {
+	timer := time.NewTimer(n.jittered(c.current()))
+	defer timer.Stop()
+
+	prev := state()
+	for {
+		select {
+		case <-ctx.Done():
+			n.lgr.Info(c.name + " stabilizer stopped")
+			return
+		case <-c.wake:
+			timer.Reset(n.jittered(c.current()))
+			continue
+		case <-timer.C:
+		}
+		if n.StabilizationPaused() {
+			round(ctx)
+			timer.Reset(n.jittered(c.current()))
+			continue
+		}
+		round(ctx)
+		cur := state()
+		changed := cur != prev
+		prev = cur
+		if changed && onChange != nil {
+			onChange()
+		}
+		timer.Reset(n.jittered(c.observe(changed)))
+	}
+}
+
+// chordState returns the predecessor and successor list, to detect changes
+// of the Chord routing state between two rounds.
+func (n *Node) chordState() string {
+	pred := ""
+	if p := n.rt.GetPredecessor(); p != nil {
+		pred = p.Addr
+	}
+	return pred + "|" + n.successorsKey()
+}
+
+// deBruijnState returns the de Bruijn window, to detect changes between two
+// rounds.
+func (n *Node) deBruijnState() string {
+	return nodesKey(n.rt.DeBruijnList())
+}
@@ -0,0 +1,71 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestAdaptiveStabilization(t *testing.T) {
+	const (
+		minInterval = 10 * time.Millisecond
+		maxInterval = 400 * time.Millisecond
+		size        = 5
+	)
+
+	// Conta le GetPredecessor inviate: una per round di stabilizzazione di ogni nodo
+	var probes atomic.Int64
+	count := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if strings.HasSuffix(method, "/GetPredecessor") {
+			probes.Add(1)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	r := testring.New(t, size,
+		testring.WithPoolOptions(client.WithUnaryInterceptors(count)),
+		testring.WithNodeOptions(logicnode.WithAdaptiveStabilization(minInterval, maxInterval)))
+	r.WaitStable()
+
+	waitConverged := func(within time.Duration) time.Duration {
+		t.Helper()
+		start := time.Now()
+		for {
+			done := true
+			for _, m := range r.Live() {
+				done = done && converged(r, m)
+			}
+			if done {
+				return time.Since(start)
+			}
+			if time.Since(start) > within {
+				t.Fatal("routing state did not converge")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitConverged(5 * time.Second)
+
+	// anello stabile: gli intervalli crescono fino al massimo
+	time.Sleep(2 * maxInterval)
+	probes.Store(0)
+	const window = time.Second
+	time.Sleep(window)
+	fixed := int64(size) * int64(window/minInterval)
+	if got := probes.Load(); got > fixed/5 {
+		t.Errorf("stable ring sent %d GetPredecessor in %v, want well below %d (fixed interval)", got, window, fixed)
+	}
+
+	// un guasto riporta gli intervalli al minimo: l'anello converge entro
+	// pochi intervalli massimi
+	r.Kill(r.Members[2])
+	if took := waitConverged(5 * time.Second); took > 4*maxInterval {
+		t.Errorf("ring converged in %v after a failure, want <= %v", took, 4*maxInterval)
+	}
+}
@@ -0,0 +1,170 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/ctxutil"
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Iterative lookups (WithIterativeLookup)
+//
+// In the default recursive mode every hop forwards the FindSuccessor request
+// to the next one and the result travels back along the same path. In the
+// iterative mode the originating node drives the lookup: it asks each hop
+// only for its routing decision (FindSuccessorNextHop) and contacts the next
+// node itself. Both modes share the same decision function (nextHop), so
+// they terminate on the same Between check and return the same successor.
+
+// NextHopInit computes the first step of a lookup of target at this node,
+// without forwarding it: the successor if the lookup ends here, otherwise
+// the next candidates and the routing state to send them.
+func (n *Node) NextHopInit(ctx context.Context, target domain.ID) (*domain.NextHop, error) {
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	hop, err := n.initialHop(target)
+	if err != nil || hop.Successor != nil {
+		return hop, err
+	}
+	return n.nextHop(target, hop.CurrentI, hop.KShift)
+}
+
+// NextHopStep computes the next step of a lookup of target that reached
+// this node with the given imaginary node and shifted target, without
+// forwarding it (see NextHopInit).
+func (n *Node) NextHopStep(ctx context.Context, target, currentI, kshift domain.ID) (*domain.NextHop, error) {
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	return n.nextHop(target, currentI, kshift)
+}
+
+// findSuccessorIterative resolves the successor of target driving the
+// lookup from this node. At each hop the candidates returned by the
+// previous one are tried in order (the closest de Bruijn node first, the
+// successor last) and the first that answers becomes the current hop.
+// If visit is not nil, it is called with that node after every hop.
+// The lookup is bounded as the recursive one: by the hop limit (see
+// WithMaxHops) on the changes of node and by the local depth limit (see
+// WithMaxLocalDepth) on the steps in a row computed by the same node.
+func (n *Node) findSuccessorIterative(ctx context.Context, target domain.ID, visit func(*domain.Node)) (*domain.Node, error) {
+	hop, err := n.NextHopInit(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	limit, depthLimit := n.hopLimit(), n.localDepthLimit()
+	at, hops, depth := n.rt.Self(), 0, 0 // node of the current hop, hops and steps run there
+	for hop.Successor == nil {
+		if hops > limit {
+			n.lgr.Error("FindSuccessorIterative: hop limit reached",
+				logger.F("target", target.ToHexString(true)), logger.F("currentI", hop.CurrentI.ToHexString(true)),
+				logger.F("kshift", hop.KShift.ToHexString(true)), logger.F("hops", hops))
+			return nil, fmt.Errorf("iterative %w (%d)", ErrMaxHops, limit)
+		}
+		if depth > depthLimit {
+			n.lgr.Error("FindSuccessorIterative: too many steps on one node, aborting lookup",
+				logger.F("target", target.ToHexString(true)), logger.FNode("node", at), logger.F("depth", depth))
+			return nil, status.Error(codes.ResourceExhausted, "lookup exceeded the maximum local recursion depth")
+		}
+		var via *domain.Node
+		hop, via, err = n.iterativeStep(ctx, target, hop)
+		if err != nil {
+			return nil, err
+		}
+		if visit != nil {
+			visit(via)
+		}
+		// as in the recursive lookup, a step computed by the node of the
+		// previous one (its own de Bruijn candidate) is not a hop
+		if via.Addr == at.Addr {
+			depth++
+		} else {
+			at, hops, depth = via, hops+1, 0
+		}
+	}
+	return hop.Successor, nil
+}
+
+// iterativeStep asks the candidates of prev, in order, for the next step
+// of the lookup, and returns it with the candidate that computed it. Each
+// remote request is bounded by the failure timeout of the pool, so that a
+// dead candidate does not consume the whole deadline.
+func (n *Node) iterativeStep(ctx context.Context, target domain.ID, prev *domain.NextHop) (*domain.NextHop, *domain.Node, error) {
+	self := n.rt.Self()
+	var lastErr error
+	for i, c := range prev.Candidates {
+		if err := ctxutil.CheckContext(ctx); err != nil {
+			return nil, nil, err
+		}
+		if c.ID.Equal(self.ID) {
+			hop, err := n.NextHopStep(ctx, target, prev.CurrentI, prev.KShift)
+			if err == nil {
+				return hop, self, nil
+			}
+			lastErr = err
+			continue
+		}
+
+		cli, release, err := n.clientFor(c.Addr)
+		if err != nil {
+			n.lgr.Warn("FindSuccessorIterative: failed to connect to candidate",
+				logger.F("tryIdx", i), logger.FNode("candidate", c), logger.F("err", err))
+			lastErr = err
+			continue
+		}
+		slot, err := n.acquireOutbound(ctx)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		hopCtx, cancel := context.WithTimeout(ctx, n.cp.FailureTimeout())
+		hop, err := client.FindSuccessorNextHop(hopCtx, cli, n.Space(), target, prev.CurrentI, prev.KShift)
+		cancel()
+		slot()
+		release()
+		if err == nil {
+			n.lgr.Debug("FindSuccessorIterative: hop completed",
+				logger.F("target", target.ToHexString(true)), logger.FNode("node", c))
+			return hop, c, nil
+		}
+		if ctxErr := ctxutil.CheckContext(ctx); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		n.lgr.Warn("FindSuccessorIterative: candidate failed, trying next one",
+			logger.F("tryIdx", i), logger.FNode("candidate", c), logger.F("err", err))
+		lastErr = err
+	}
+	return nil, nil, status.Errorf(codes.Unavailable, "no next hop reachable: %v", lastErr)
+}
+
+// LookUpTrace locates the node responsible for id like LookUp and also
+// returns the path of the lookup: the nodes that made each routing
+// decision, in order, from this node to the responsible node (last). To
+// record it the lookup is driven from this node as in the iterative mode,
+// whatever the lookup mode, so the path is the one a recursive lookup
+// would follow; a node runs consecutive steps on itself when its closest
+// de Bruijn candidate is itself, and is then repeated. It is meant for
+// debugging: a failed lookup is not retried and returns the partial path.
+func (n *Node) LookUpTrace(ctx context.Context, id domain.ID) (*domain.Node, []*domain.Node, error) {
+	if err := n.beginOp(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer n.ops.Done()
+	path := []*domain.Node{n.rt.Self()}
+	succ, err := n.findSuccessorIterative(ctx, id, func(nd *domain.Node) {
+		path = append(path, nd)
+	})
+	if err != nil {
+		return nil, path, fmt.Errorf("lookuptrace: key %s: %w: %w", id.ToHexString(true), ErrLookupFailed, err)
+	}
+	if !path[len(path)-1].ID.Equal(succ.ID) { // unless alone in the ring
+		path = append(path, succ)
+	}
+	return succ, path, nil
+}
@@ -0,0 +1,50 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIterativeLookupMatchesRecursive(t *testing.T) {
+	tests := []struct {
+		name     string
+		deBruijn bool
+	}{
+		{name: "de Bruijn", deBruijn: true},
+		{name: "Chord-only", deBruijn: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 6, testring.WithNodeOptions(
+				logicnode.WithDeBruijn(tt.deBruijn), logicnode.WithIterativeLookup(true)))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			// Ogni membro guida il lookup chiedendo a ogni hop solo il prossimo
+			// nodo; il risultato deve coincidere con quello ricorsivo
+			for _, origin := range r.Members {
+				for i := 0; i < 32; i++ {
+					id := r.Space.NewIdFromString(fmt.Sprintf("key-%d", i))
+					got, err := origin.Node.LookUp(ctx, id)
+					if err != nil {
+						t.Fatalf("iterative LookUp(%s) from %s: %v", id.ToHexString(true), origin.Addr, err)
+					}
+					if want := r.Owner(id); got.Addr != want.Addr {
+						t.Errorf("iterative LookUp(%s) from %s = %s, want %s", id.ToHexString(true), origin.Addr, got.Addr, want.Addr)
+					}
+					rec, err := origin.Node.FindSuccessorRecursive(ctx, id)
+					if err != nil {
+						t.Fatalf("recursive lookup of %s from %s: %v", id.ToHexString(true), origin.Addr, err)
+					}
+					if rec.Addr != got.Addr {
+						t.Errorf("lookup of %s from %s: iterative %s, recursive %s", id.ToHexString(true), origin.Addr, got.Addr, rec.Addr)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"testing"
+	"time"
+)
+
+func TestStabilizationJitter(t *testing.T) {
+	const interval = time.Second
+	tests := []struct {
+		name     string
+		opts     []logicnode.Option
+		min, max time.Duration
+	}{
+		{name: "default", min: 900 * time.Millisecond, max: 1100 * time.Millisecond},
+		{name: "custom", opts: []logicnode.Option{logicnode.WithStabilizationJitter(0.5)}, min: 500 * time.Millisecond, max: 1500 * time.Millisecond},
+		{name: "disabled", opts: []logicnode.Option{logicnode.WithStabilizationJitter(0)}, min: interval, max: interval},
+		// valore fuori da [0,1): resta il default
+		{name: "invalid", opts: []logicnode.Option{logicnode.WithStabilizationJitter(1.5)}, min: 900 * time.Millisecond, max: 1100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 1, testring.WithNodeOptions(tt.opts...))
+			n := r.Members[0].Node
+
+			seen := make(map[time.Duration]struct{})
+			for range 200 {
+				d := n.Jittered(interval)
+				if d < tt.min || d > tt.max {
+					t.Fatalf("Jittered(%s) = %s, want in [%s, %s]", interval, d, tt.min, tt.max)
+				}
+				seen[d] = struct{}{}
+			}
+			// con jitter attivo gli intervalli devono effettivamente variare
+			if tt.min != tt.max && len(seen) < 2 {
+				t.Errorf("Jittered(%s) always returned the same interval", interval)
+			}
+		})
+	}
+}
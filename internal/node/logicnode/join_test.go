@@ -0,0 +1,132 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestJoinPullBeforeNotify(t *testing.T) {
+	r := testring.New(t, 4)
+
+	// Popola la DHT prima del join
+	const numKeys = 200
+	keys := make([]domain.ID, numKeys)
+	for i := range keys {
+		raw := fmt.Sprintf("key-%d", i)
+		res := domain.Resource{Key: r.Space.NewIdFromString(raw), RawKey: raw, Value: raw}
+		keys[i] = res.Key
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := r.Members[0].Node.Put(ctx, res)
+		cancel()
+		if err != nil {
+			t.Fatalf("Put %s: %v", raw, err)
+		}
+	}
+
+	// Get continue da tutti i membri mentre un nuovo nodo entra nell'anello
+	var notFound atomic.Int64
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, m := range r.Members {
+		wg.Add(1)
+		go func(m *testring.Member) {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				_, err := m.Node.Get(ctx, keys[i%numKeys])
+				cancel()
+				if status.Code(err) == codes.NotFound {
+					notFound.Add(1)
+				}
+			}
+		}(m)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	r.Add()
+	r.WaitStable()
+	time.Sleep(200 * time.Millisecond) // lascia girare resourceRepair
+	close(stop)
+	wg.Wait()
+
+	if n := notFound.Load(); n > 0 {
+		t.Fatalf("%d Get returned NotFound during join", n)
+	}
+}
+
+func TestJoinIDCollision(t *testing.T) {
+	tests := []struct {
+		name string
+		// collide restituisce l'ID del nuovo nodo, preparando l'anello
+		collide func(t *testing.T, r *testring.Ring) domain.ID
+	}{
+		{
+			name: "successor has the same ID",
+			collide: func(t *testing.T, r *testring.Ring) domain.ID {
+				return r.Members[1].Node.Self().ID
+			},
+		},
+		{
+			// un nodo con lo stesso ID è entrato davanti al successore, ma
+			// l'anello non si è ancora stabilizzato: la lookup arriva al
+			// successore, il cui predecessore ha già l'ID del nuovo nodo
+			name: "predecessor of the successor has the same ID",
+			collide: func(t *testing.T, r *testring.Ring) domain.ID {
+				pred, succ := r.Members[0], r.Members[1]
+				id, err := r.Space.AddMod(pred.Node.Self().ID, r.Space.FromUint64(1))
+				if err != nil {
+					t.Fatalf("AddMod: %v", err)
+				}
+				if id.Equal(succ.Node.Self().ID) {
+					t.Skip("no free ID between the first two members")
+				}
+				succ.Node.ChordRound() // attende l'eventuale round ancora in corso
+				succ.Node.SetPredecessor(&domain.Node{ID: id, Addr: "127.0.0.1:1"})
+				return id
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 3)
+			r.WaitStable()
+			r.StopStabilizers()
+			id := tt.collide(t, r)
+
+			self := &domain.Node{ID: id, Addr: "127.0.0.1:2"}
+			cp := client.New(self.ID, self.Addr, time.Second)
+			defer cp.Close()
+			n := logicnode.New(routingtable.New(self, r.Space), cp, storage.NewMemoryStorage(&logger.NopLogger{}))
+			err := n.Join([]string{r.Members[0].Addr})
+			if !errors.Is(err, logicnode.ErrIDCollision) {
+				t.Fatalf("Join: %v, want ErrIDCollision", err)
+			}
+			// il join interrotto non ha annunciato il nodo all'anello
+			for _, m := range r.Members {
+				if p := m.Node.Predecessor(); p != nil && p.Addr == self.Addr {
+					t.Errorf("%s adopted the colliding node as predecessor", m.Addr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestLazyDeBruijnRefreshSkipsLookup(t *testing.T) {
+	const maxAge = time.Hour
+
+	// Orologio dei nodi: avanza solo quando lo sposta il test
+	var offset atomic.Int64
+	clock := func() time.Time { return time.Now().Add(time.Duration(offset.Load())) }
+
+	// Conta le RPC in uscita: a stabilizzatori fermi le invia solo il nodo sotto test
+	var rpcs atomic.Int64
+	count := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		rpcs.Add(1)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	r := testring.New(t, 6,
+		testring.WithPoolOptions(client.WithUnaryInterceptors(count)),
+		testring.WithNodeOptions(logicnode.WithLazyDeBruijnRefresh(maxAge), logicnode.WithClock(clock)))
+	r.WaitStable()
+	deadline := time.Now().Add(5 * time.Second)
+	for _, m := range r.Members {
+		for !succsConverged(r, m, min(len(r.Members)-1, m.Node.SuccessorListSize())) {
+			if time.Now().After(deadline) {
+				t.Fatal("successor lists did not converge")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	r.StopStabilizers()
+
+	// un nodo con l'anchor remoto: il suo aggiornamento richiede delle RPC
+	var m *testring.Member
+	for _, x := range r.Members {
+		if w := x.Node.DeBruijnList(); len(w) > 0 && w[0] != nil && w[0].Addr != x.Addr {
+			m = x
+			break
+		}
+	}
+	if m == nil {
+		t.Fatal("no member with a remote de Bruijn anchor")
+	}
+	// attende l'aggiornamento eventualmente ancora in corso allo stop
+	m.Node.DeBruijnRound()
+
+	steps := []struct {
+		name        string
+		advance     time.Duration // avanzamento dell'orologio prima dell'aggiornamento
+		wantRefresh bool
+	}{
+		{name: "stale window is refreshed", advance: maxAge, wantRefresh: true},
+		{name: "unchanged window is skipped", advance: maxAge - time.Second, wantRefresh: false},
+		{name: "refreshed again after max age", advance: time.Second, wantRefresh: true},
+	}
+	for _, st := range steps {
+		offset.Add(int64(st.advance))
+		rpcs.Store(0)
+		m.Node.FixDeBruijn()
+		if got := rpcs.Load() > 0; got != st.wantRefresh {
+			t.Errorf("%s: refresh issued RPCs = %v (%d), want %v", st.name, got, rpcs.Load(), st.wantRefresh)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stored reports whether the storage of m holds key.
+func stored(m *testring.Member, key string) bool {
+	for _, res := range m.Node.GetAllResourceStored() {
+		if res.RawKey == key {
+			return true
+		}
+	}
+	return false
+}
+
+// succsConverged reports whether the first k entries of the successor list
+// of m are the members that follow it on the ring.
+func succsConverged(r *testring.Ring, m *testring.Member, k int) bool {
+	succs := m.Node.SuccessorList()
+	if len(succs) < k {
+		return false
+	}
+	i := slices.Index(r.Members, m)
+	for j := 0; j < k; j++ {
+		next := r.Members[(i+1+j)%len(r.Members)]
+		if succs[j] == nil || !succs[j].ID.Equal(next.Node.Self().ID) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStoreDuringLeaveEndsOnSuccessor(t *testing.T) {
+	r := testring.New(t, 3)
+	r.StopStabilizers()
+	time.Sleep(50 * time.Millisecond) // lascia terminare i round di stabilizzazione in volo
+
+	leaving, succ := r.Members[1], r.Members[2]
+
+	// Chiavi di cui è responsabile il nodo che lascia l'anello, con ID
+	// distinti (nello spazio a 16 bit due chiavi potrebbero collidere)
+	var keys []string
+	ids := make(map[string]struct{})
+	for i := 0; len(keys) < 200 && i < 1<<20; i++ { // l'arco del nodo può contenere meno chiavi
+		k := fmt.Sprintf("key-%d", i)
+		id := r.Space.NewIdFromString(k)
+		if _, dup := ids[id.ToHexString(false)]; dup || r.Owner(id) != leaving {
+			continue
+		}
+		ids[id.ToHexString(false)] = struct{}{}
+		keys = append(keys, k)
+	}
+	resource := func(k string) domain.Resource {
+		return domain.Resource{Key: r.Space.NewIdFromString(k), RawKey: k, Value: k}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// Store in arrivo mentre il nodo esegue Leave
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+		ok []string
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, k := range keys {
+			if err := leaving.Node.StoreLocal(ctx, resource(k)); err == nil {
+				mu.Lock()
+				ok = append(ok, k)
+				mu.Unlock()
+			}
+		}
+	}()
+	if err := leaving.Node.Leave(); err != nil {
+		t.Fatalf("Leave: %v", err)
+	}
+	wg.Wait()
+
+	if len(ok) == 0 {
+		t.Fatal("no Store succeeded during the leave")
+	}
+	// Ogni Store riuscito deve trovarsi sul successore, trasferito o inoltrato
+	for _, k := range ok {
+		if !stored(succ, k) {
+			t.Errorf("key %s stored during the leave is not on the successor", k)
+		}
+	}
+
+	// Dopo Leave gli Store sono inoltrati al successore, non memorizzati in locale
+	const late = "late-key"
+	if err := leaving.Node.StoreLocal(ctx, resource(late)); err != nil {
+		t.Fatalf("StoreLocal after Leave: %v", err)
+	}
+	if stored(leaving, late) {
+		t.Errorf("key %s stored on the leaving node", late)
+	}
+	if !stored(succ, late) {
+		t.Errorf("key %s not forwarded to the successor", late)
+	}
+}
+
+func TestLeaveHandsOffToPredecessorAndHeir(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		killSucc bool // il successore è morto: le risorse passano al nodo seguente
+	}{
+		{name: "successor alive", size: 4},
+		{name: "successor dead", size: 4, killSucc: true},
+		{name: "two nodes", size: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, tt.size)
+			// WaitStable controlla solo il primo successore: la lista di chi
+			// lascia l'anello deve contenere anche il nodo seguente
+			deadline := time.Now().Add(5 * time.Second)
+			for !succsConverged(r, r.Members[1], min(tt.size-1, 2)) {
+				if time.Now().After(deadline) {
+					t.Fatal("successor list of the leaving node did not converge")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			r.StopStabilizers()
+			time.Sleep(50 * time.Millisecond) // lascia terminare i round di stabilizzazione in volo
+
+			n := len(r.Members)
+			pred, leaving := r.Members[0], r.Members[1]
+			heir := r.Members[2%n]
+			if tt.killSucc {
+				r.Kill(heir)
+				heir = r.Members[3]
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			var keys []string
+			ids := make(map[string]struct{})
+			for i := 0; len(keys) < 20 && i < 1<<20; i++ { // l'arco del nodo può contenere meno chiavi
+				k := fmt.Sprintf("key-%d", i)
+				id := r.Space.NewIdFromString(k)
+				if _, dup := ids[id.ToHexString(false)]; dup || r.Owner(id) != leaving {
+					continue
+				}
+				ids[id.ToHexString(false)] = struct{}{}
+				keys = append(keys, k)
+				if err := leaving.Node.StoreLocal(ctx, domain.Resource{Key: id, RawKey: k, Value: k}); err != nil {
+					t.Fatalf("StoreLocal %s: %v", k, err)
+				}
+			}
+
+			if err := leaving.Node.Leave(); err != nil {
+				t.Fatalf("Leave: %v", err)
+			}
+			for _, k := range keys {
+				if !stored(heir, k) {
+					t.Errorf("key %s not handed off to %s", k, heir.Addr)
+				}
+			}
+
+			// Con la stabilizzazione ferma il predecessore può aver aggiornato
+			// il successore solo tramite PredecessorLeaving
+			want := heir.Node.Self()
+			if tt.size == 2 {
+				want = pred.Node.Self() // unico nodo rimasto: successore di sé stesso
+			}
+			succs := pred.Node.SuccessorList()
+			if len(succs) == 0 || !succs[0].ID.Equal(want.ID) {
+				t.Fatalf("predecessor successor = %v, want %s", succs, want.Addr)
+			}
+			for _, s := range succs {
+				if s != nil && s.ID.Equal(leaving.Node.Self().ID) {
+					t.Errorf("leaving node still in the predecessor successor list: %v", succs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/storage"
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLookupLocalRecursionBounded(t *testing.T) {
+	tests := []struct {
+		name     string
+		depth    int
+		wantCode codes.Code
+	}{
+		// il lookup scende di una cifra per passo: oltre 3 livelli si interrompe
+		{name: "bound reached", depth: 3, wantCode: codes.ResourceExhausted},
+		// con il limite di default la ricorsione termina da sola sul successore
+		// (irraggiungibile: nessuna connessione nel pool)
+		{name: "default bound", depth: 0, wantCode: codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, err := domain.NewSpace(16, 2, 4)
+			if err != nil {
+				t.Fatalf("NewSpace: %v", err)
+			}
+			// Tabella patologica: i puntatori de Bruijn sono tutti il nodo
+			// stesso e il successore copre tutto l'anello tranne self, quindi
+			// ogni passo prosegue localmente
+			self := &domain.Node{ID: sp.NewIdFromString("self"), Addr: "127.0.0.1:1"}
+			succID := sp.FromUint64((self.ID.ToBigInt().Uint64() + 1<<16 - 1) % (1 << 16))
+			rt := routingtable.New(self, sp)
+			rt.SetSuccessor(0, &domain.Node{ID: succID, Addr: "127.0.0.1:2"})
+			rt.SetDeBruijnList([]*domain.Node{self, self})
+
+			cp := client.New(self.ID, self.Addr, time.Second)
+			defer cp.Close()
+			n := logicnode.New(rt, cp, storage.NewMemoryStorage(&logger.NopLogger{}), logicnode.WithMaxLocalDepth(tt.depth))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// target = self: mai in (self, successore]; currentI nell'intervallo
+			_, err = n.FindSuccessorStep(ctx, self.ID, succID, self.ID)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("FindSuccessorStep: code %v (%v), want %v", got, err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestLookupCacheSkipsLookups(t *testing.T) {
+	// Conta i passi di lookup ricevuti da tutti i nodi
+	var lookups atomic.Int64
+	count := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.Contains(info.FullMethod, "FindSuccessor") {
+			lookups.Add(1)
+		}
+		return handler(ctx, req)
+	}
+	r := testring.New(t, 4,
+		testring.WithServerOptions(server.WithUnaryInterceptors(count)),
+		testring.WithNodeOptions(logicnode.WithLookupCache(16, time.Minute)))
+	r.WaitStable()
+	origin := r.Members[0]
+
+	// chiave posseduta da un nodo che l'origine raggiunge solo con un lookup
+	var key string
+	for i := 0; key == "" && i < 1<<20; i++ {
+		if k := fmt.Sprintf("key-%d", i); r.Owner(r.Space.NewIdFromString(k)) == r.Members[2] {
+			key = k
+		}
+	}
+	if key == "" {
+		t.Skip("no key owned by the target member")
+	}
+	id := r.Space.NewIdFromString(key)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := origin.Node.Put(ctx, domain.Resource{Key: id, RawKey: key, Value: "v1"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if lookups.Load() == 0 {
+		t.Fatal("first Put did not run a lookup")
+	}
+
+	// il proprietario in cache risponde senza lookup
+	lookups.Store(0)
+	res, err := origin.Node.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if res.Value != "v1" {
+		t.Errorf("Get = %q, want v1", res.Value)
+	}
+	if n := lookups.Load(); n != 0 {
+		t.Errorf("cached Get ran %d lookup steps, want 0", n)
+	}
+
+	// il proprietario in cache muore: la voce viene scartata e l'operazione
+	// ripetuta con un lookup completo verso il nuovo proprietario
+	r.Kill(r.Members[2])
+	r.WaitStable()
+	if err := origin.Node.Put(ctx, domain.Resource{Key: id, RawKey: key, Value: "v2"}); err != nil {
+		t.Fatalf("Put after owner failure: %v", err)
+	}
+	res, err = r.Owner(id).Node.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get from the new owner: %v", err)
+	}
+	if res.Value != "v2" {
+		t.Errorf("Get from the new owner = %q, want v2", res.Value)
+	}
+}
+
+func TestLookupCacheOwnerMoved(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(ctx context.Context, origin *testring.Member, id domain.ID, key string) (string, error)
+	}{
+		{
+			// il vecchio proprietario rifiuta la scrittura: non è più responsabile
+			name: "put",
+			op: func(ctx context.Context, origin *testring.Member, id domain.ID, key string) (string, error) {
+				return "v2", origin.Node.Put(ctx, domain.Resource{Key: id, RawKey: key, Value: "v2"})
+			},
+		},
+		{
+			// il vecchio proprietario non ha più la chiave
+			name: "get",
+			op: func(ctx context.Context, origin *testring.Member, id domain.ID, key string) (string, error) {
+				res, err := origin.Node.Get(ctx, id)
+				if err != nil {
+					return "", err
+				}
+				return res.Value, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 4, testring.WithNodeOptions(logicnode.WithLookupCache(16, time.Minute)))
+			r.WaitStable()
+			origin, owner := r.Members[0], r.Members[2]
+
+			// chiave del proprietario con ID libero: vi entrerà un nuovo nodo
+			var key string
+			for i := 0; key == "" && i < 1<<20; i++ {
+				k := fmt.Sprintf("key-%d", i)
+				id := r.Space.NewIdFromString(k)
+				if r.Owner(id) == owner && !id.Equal(owner.Node.Self().ID) {
+					key = k
+				}
+			}
+			if key == "" {
+				t.Skip("no key owned by the target member")
+			}
+			id := r.Space.NewIdFromString(key)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := origin.Node.Put(ctx, domain.Resource{Key: id, RawKey: key, Value: "v1"}); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			// un nodo entra davanti al proprietario in cache e ne prende la chiave
+			joined := r.AddWithID(id)
+			r.WaitStable()
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				_, err := owner.Node.RetrieveLocal(id)
+				if errors.Is(err, domain.ErrResourceNotFound) {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("key still on the old owner: %v", err)
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+
+			want, err := tt.op(ctx, origin, id, key)
+			if err != nil {
+				t.Fatalf("%s through the stale cache entry: %v", tt.name, err)
+			}
+			res, err := joined.Node.RetrieveLocal(id)
+			if err != nil {
+				t.Fatalf("key not on the new owner: %v", err)
+			}
+			if res.Value != want {
+				t.Errorf("new owner holds %q, want %q", res.Value, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLookUpRetriesTransientFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		retries int
+		delay   time.Duration
+		timeout time.Duration // deadline del lookup
+		wantErr bool
+	}{
+		{name: "no retries", retries: 0, wantErr: true, timeout: 2 * time.Second},
+		// 40+80+160ms di attesa superano la finestra di errori
+		{name: "retries outlast the outage", retries: 4, delay: 40 * time.Millisecond, timeout: 2 * time.Second},
+		// l'attesa supera la deadline: i tentativi si interrompono con il contesto
+		{name: "stops at the deadline", retries: 10, delay: time.Second, timeout: 100 * time.Millisecond, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Finché outage è nel futuro ogni nodo rifiuta i passi di lookup
+			var outage atomic.Int64
+			reject := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				if strings.Contains(info.FullMethod, "FindSuccessor") && time.Now().UnixNano() < outage.Load() {
+					return nil, status.Error(codes.Unavailable, "hop unreachable")
+				}
+				return handler(ctx, req)
+			}
+			r := testring.New(t, 4,
+				testring.WithServerOptions(server.WithUnaryInterceptors(reject)),
+				testring.WithNodeOptions(logicnode.WithLookupRetry(tt.retries, tt.delay)))
+			origin := r.Members[0]
+
+			// ID non compreso in (origin, successore]: il lookup deve inoltrare
+			var key string
+			for i := 0; key == "" && i < 1<<20; i++ {
+				if k := fmt.Sprintf("key-%d", i); r.Owner(r.Space.NewIdFromString(k)) == r.Members[2] {
+					key = k
+				}
+			}
+			if key == "" {
+				t.Skip("no key owned by the target member")
+			}
+			id := r.Space.NewIdFromString(key)
+
+			outage.Store(time.Now().Add(150 * time.Millisecond).UnixNano())
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			start := time.Now()
+			succ, err := origin.Node.LookUp(ctx, id)
+			elapsed := time.Since(start)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("LookUp succeeded during the outage, want error")
+				}
+				if elapsed > tt.timeout+100*time.Millisecond {
+					t.Fatalf("LookUp returned after %v, past the %v deadline", elapsed, tt.timeout)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LookUp: %v", err)
+			}
+			if succ.Addr != r.Members[2].Addr {
+				t.Fatalf("LookUp = %s, want %s", succ.Addr, r.Members[2].Addr)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLookUpTrace(t *testing.T) {
+	r := testring.New(t, 8)
+	r.WaitStable()
+	for _, m := range r.Members {
+		m.Node.FixDeBruijn()
+	}
+	origin := r.Members[0]
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	multiHop := false
+	for i := 0; i < 32; i++ {
+		id := r.Space.NewIdFromString(fmt.Sprintf("trace-%d", i))
+		succ, path, err := origin.Node.LookUpTrace(ctx, id)
+		if err != nil {
+			t.Fatalf("LookUpTrace(%s): %v", id.ToHexString(true), err)
+		}
+		// stesso responsabile della lookup normale
+		want, err := origin.Node.LookUp(ctx, id)
+		if err != nil {
+			t.Fatalf("LookUp(%s): %v", id.ToHexString(true), err)
+		}
+		if succ.Addr != want.Addr || succ.Addr != r.Owner(id).Addr {
+			t.Fatalf("LookUpTrace(%s) = %s, want %s", id.ToHexString(true), succ.Addr, want.Addr)
+		}
+		// il percorso parte dal nodo contattato e termina sul responsabile
+		if len(path) < 2 || path[0].Addr != origin.Addr || path[len(path)-1].Addr != succ.Addr {
+			t.Fatalf("LookUpTrace(%s): path %v does not go from %s to %s", id.ToHexString(true), path, origin.Addr, succ.Addr)
+		}
+		multiHop = multiHop || len(path) > 2
+	}
+	if !multiHop {
+		t.Errorf("no lookup took more than one hop, the paths test nothing")
+	}
+
+	// la stessa traccia via RPC
+	api, conn, err := client.Connect(origin.Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	id := r.Space.NewIdFromString("trace-rpc")
+	resp, _, err := client.LookupTrace(ctx, api, id.ToHexString(true))
+	if err != nil {
+		t.Fatalf("client.LookupTrace: %v", err)
+	}
+	if resp.GetSuccessor().GetAddr() != r.Owner(id).Addr || int(resp.GetHops()) != len(resp.GetPath())-1 {
+		t.Errorf("client.LookupTrace: successor %s, %d hops over a path of %d nodes, want %s and len(path)-1 hops",
+			resp.GetSuccessor().GetAddr(), resp.GetHops(), len(resp.GetPath()), r.Owner(id).Addr)
+	}
+}
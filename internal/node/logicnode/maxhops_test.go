@@ -0,0 +1,153 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestLookupMaxHops(t *testing.T) {
+	// Senza de Bruijn il lookup percorre l'anello: dal membro 0, la chiave
+	// del membro j richiede j-1 inoltri
+	r := testring.New(t, 6, testring.WithNodeOptions(logicnode.WithDeBruijn(false), logicnode.WithMaxHops(2)))
+	tests := []struct {
+		name    string
+		owner   int
+		wantErr bool
+	}{
+		{name: "successor", owner: 1},
+		{name: "within the limit", owner: 3},
+		{name: "beyond the limit", owner: 4, wantErr: true},
+		{name: "far beyond the limit", owner: 5, wantErr: true},
+	}
+	origin := r.Members[0]
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			want := r.Members[tt.owner]
+			start := time.Now()
+			got, err := origin.Node.FindSuccessorRecursive(ctx, want.Node.Self().ID)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("lookup: %v", err)
+				}
+				if got.Addr != want.Addr {
+					t.Fatalf("lookup: got %s, want %s", got.Addr, want.Addr)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("lookup: got %s, want hop limit error", got.Addr)
+			}
+			if status.Code(err) != codes.Internal || !logicnode.ExceededHops(err) {
+				t.Fatalf("lookup: got %v, want Internal with the hop limit cause", err)
+			}
+			// il lookup viene interrotto subito, non alla scadenza
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("lookup failed after %s, want an immediate failure", elapsed)
+			}
+		})
+	}
+}
+
+func TestLookupMaxHopsDefault(t *testing.T) {
+	// Il limite automatico non interrompe i lookup di un anello stabile,
+	// né con de Bruijn né nel giro dell'anello della modalità Chord
+	tests := []struct {
+		name     string
+		deBruijn bool
+	}{
+		{name: "de Bruijn", deBruijn: true},
+		{name: "Chord-only", deBruijn: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 8, testring.WithNodeOptions(logicnode.WithDeBruijn(tt.deBruijn)))
+			r.StopStabilizers()
+			for _, origin := range r.Members {
+				for _, want := range r.Members {
+					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					got, err := origin.Node.FindSuccessorRecursive(ctx, want.Node.Self().ID)
+					cancel()
+					if err != nil {
+						t.Fatalf("lookup from %s: %v", origin.Addr, err)
+					}
+					if got.Addr != want.Addr {
+						t.Fatalf("lookup from %s: got %s, want %s", origin.Addr, got.Addr, want.Addr)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLookupMaxHopsDefaultBound(t *testing.T) {
+	// Spazio di 16 cifre in base 2: il limite automatico è 3·16+16 = 64 hop
+	const limit = 64
+	tests := []struct {
+		name     string
+		deBruijn bool
+		hops     int
+		wantErr  bool
+	}{
+		{name: "de Bruijn within the limit", deBruijn: true, hops: limit},
+		{name: "de Bruijn beyond the limit", deBruijn: true, hops: limit + 1, wantErr: true},
+		// i vicini coprono tutto l'anello: la stima è di 4 membri, e senza
+		// il minimo il limite sarebbe 4·4+16 = 32 hop
+		{name: "Chord-only within the floor", deBruijn: false, hops: limit},
+		{name: "Chord-only beyond the floor", deBruijn: false, hops: limit + 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, err := domain.NewSpace(16, 2, 4)
+			if err != nil {
+				t.Fatalf("NewSpace: %v", err)
+			}
+			self := &domain.Node{ID: sp.FromUint64(0), Addr: "127.0.0.1:1"}
+			succ := &domain.Node{ID: sp.FromUint64(0x4000), Addr: "127.0.0.1:2"}
+			rt := routingtable.New(self, sp)
+			rt.SetPredecessor(&domain.Node{ID: sp.FromUint64(0xC000), Addr: "127.0.0.1:4"})
+			rt.SetSuccessorList([]*domain.Node{succ, {ID: sp.FromUint64(0x8000), Addr: "127.0.0.1:3"}})
+
+			cp := client.New(self.ID, self.Addr, time.Second)
+			defer cp.Close()
+			n := logicnode.New(rt, cp, storage.NewMemoryStorage(&logger.NopLogger{}), logicnode.WithDeBruijn(tt.deBruijn))
+			if !tt.deBruijn {
+				if est := n.EstimateRingSize(); est != 4 {
+					t.Fatalf("EstimateRingSize: got %d, want 4", est)
+				}
+			}
+
+			// passo ricevuto dopo tt.hops inoltri; target in (self, successore]
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-koorde-hop", strconv.Itoa(tt.hops)))
+			got, err := n.FindSuccessorStep(ctx, sp.FromUint64(0x2000), self.ID, self.ID)
+			if tt.wantErr {
+				if !logicnode.ExceededHops(err) {
+					t.Fatalf("FindSuccessorStep: got %v, want the hop limit error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindSuccessorStep: %v", err)
+			}
+			if got.Addr != succ.Addr {
+				t.Fatalf("FindSuccessorStep: got %s, want %s", got.Addr, succ.Addr)
+			}
+		})
+	}
+}
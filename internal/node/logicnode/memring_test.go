@@ -0,0 +1,235 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/storage"
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// memTransport è un trasporto in memoria per le simulazioni di routing:
+// risolve gli indirizzi nei nodi dell'anello e offre le stesse chiamate
+// delle funzioni client.*, con l'indirizzo al posto della connessione
+// gRPC. Nessun nodo apre connessioni: ogni decisione di routing è quella
+// di NextHopInit/NextHopStep, la stessa del lookup ricorsivo e iterativo.
+type memTransport map[string]*logicnode.Node
+
+func (t memTransport) node(addr string) (*logicnode.Node, error) {
+	n, ok := t[addr]
+	if !ok {
+		return nil, status.Errorf(codes.Unavailable, "memTransport: no node at %s", addr)
+	}
+	return n, nil
+}
+
+// FindSuccessorNextHop corrisponde a client.FindSuccessorNextHop.
+func (t memTransport) FindSuccessorNextHop(ctx context.Context, addr string, target, currentI, kshift domain.ID) (*domain.NextHop, error) {
+	n, err := t.node(addr)
+	if err != nil {
+		return nil, err
+	}
+	return n.NextHopStep(ctx, target, currentI, kshift)
+}
+
+// GetSuccessorList corrisponde a client.GetSuccessorList.
+func (t memTransport) GetSuccessorList(_ context.Context, addr string) ([]*domain.Node, error) {
+	n, err := t.node(addr)
+	if err != nil {
+		return nil, err
+	}
+	return n.SuccessorList(), nil
+}
+
+// GetPredecessor corrisponde a client.GetPredecessor.
+func (t memTransport) GetPredecessor(_ context.Context, addr string) (*domain.Node, error) {
+	n, err := t.node(addr)
+	if err != nil {
+		return nil, err
+	}
+	return n.Predecessor(), nil
+}
+
+// FindSuccessorStart corrisponde a client.FindSuccessorStart: il lookup di
+// target parte dal nodo addr e segue, a ogni passo, il primo candidato
+// proposto dal passo precedente. Restituisce il responsabile e il numero
+// di passi; oltre maxHops il lookup è interrotto con Aborted.
+func (t memTransport) FindSuccessorStart(ctx context.Context, addr string, target domain.ID, maxHops int) (*domain.Node, int, error) {
+	n, err := t.node(addr)
+	if err != nil {
+		return nil, 0, err
+	}
+	hop, err := n.NextHopInit(ctx, target)
+	if err != nil {
+		return nil, 0, err
+	}
+	hops := 0
+	for hop.Successor == nil {
+		if hops == maxHops {
+			return nil, hops, status.Errorf(codes.Aborted, "lookup of %s exceeded %d hops", target.ToHexString(true), maxHops)
+		}
+		if len(hop.Candidates) == 0 {
+			return nil, hops, status.Errorf(codes.Internal, "lookup of %s: no candidate at hop %d", target.ToHexString(true), hops)
+		}
+		hop, err = t.FindSuccessorNextHop(ctx, hop.Candidates[0].Addr, target, hop.CurrentI, hop.KShift)
+		if err != nil {
+			return nil, hops, err
+		}
+		hops++
+	}
+	return hop.Successor, hops, nil
+}
+
+// memRing è un anello simulato: nodi ordinati per ID, con le tabelle di
+// routing riempite nello stato stabile, raggiungibili tramite transport.
+type memRing struct {
+	nodes     []*domain.Node // ordinati per ID
+	transport memTransport
+}
+
+// newMemRing costruisce un anello di n nodi con ID derivati da stringhe
+// fisse, quindi deterministici, e ne riempie le tabelle di routing come
+// farebbero gli stabilizzatori a convergenza: predecessore, lista dei
+// successori e finestra de Bruijn (àncora = predecessore di k*self, le
+// altre cifre dalla lista dei successori dell'àncora, come in fixDeBruijn).
+func newMemRing(t *testing.T, sp domain.Space, n int) *memRing {
+	t.Helper()
+	r := &memRing{transport: make(memTransport)}
+	seen := make(map[string]bool)
+	for i := 0; len(r.nodes) < n; i++ {
+		id := sp.NewIdFromString(fmt.Sprintf("node-%d", i))
+		if seen[id.ToHexString(false)] {
+			continue // collisione nello spazio ridotto
+		}
+		seen[id.ToHexString(false)] = true
+		r.nodes = append(r.nodes, &domain.Node{ID: id, Addr: fmt.Sprintf("mem-%d", i)})
+	}
+	slices.SortFunc(r.nodes, func(a, b *domain.Node) int { return a.ID.Cmp(b.ID) })
+
+	rts := make([]*routingtable.RoutingTable, len(r.nodes))
+	for i, self := range r.nodes {
+		rt := routingtable.New(self, sp)
+		if len(r.nodes) == 1 {
+			rt.InitSingleNode()
+		} else {
+			rt.SetPredecessor(r.nodes[(i+len(r.nodes)-1)%len(r.nodes)])
+			succs := make([]*domain.Node, 0, sp.SuccListSize)
+			for j := 1; j <= sp.SuccListSize && j < len(r.nodes); j++ {
+				succs = append(succs, r.nodes[(i+j)%len(r.nodes)])
+			}
+			rt.SetSuccessorList(succs)
+		}
+		cp := client.New(self.ID, self.Addr, time.Second)
+		t.Cleanup(func() { cp.Close() })
+		r.transport[self.Addr] = logicnode.New(rt, cp, storage.NewMemoryStorage(&logger.NopLogger{}))
+		rts[i] = rt
+	}
+
+	ctx := context.Background()
+	for i, self := range r.nodes {
+		km, err := sp.MulKMod(self.ID)
+		if err != nil {
+			t.Fatalf("MulKMod: %v", err)
+		}
+		anchor := r.predecessorOf(km)
+		succList, err := r.transport.GetSuccessorList(ctx, anchor.Addr)
+		if err != nil {
+			t.Fatalf("GetSuccessorList(%s): %v", anchor.Addr, err)
+		}
+		window := make([]*domain.Node, sp.GraphGrade)
+		window[0] = anchor
+		for j := 1; j < sp.GraphGrade && j-1 < len(succList); j++ {
+			window[j] = succList[j-1]
+		}
+		rts[i].SetDeBruijnList(window)
+	}
+	return r
+}
+
+// successorOf è il responsabile di id secondo l'ordinamento dell'anello:
+// il primo nodo con ID >= id, ricominciando dal primo.
+func (r *memRing) successorOf(id domain.ID) *domain.Node {
+	for _, n := range r.nodes {
+		if n.ID.Cmp(id) >= 0 {
+			return n
+		}
+	}
+	return r.nodes[0]
+}
+
+// predecessorOf è il nodo p con id in (p, successore di p].
+func (r *memRing) predecessorOf(id domain.ID) *domain.Node {
+	for i := len(r.nodes) - 1; i >= 0; i-- {
+		if r.nodes[i].ID.Cmp(id) < 0 {
+			return r.nodes[i]
+		}
+	}
+	return r.nodes[len(r.nodes)-1]
+}
+
+func TestMemRingRouting(t *testing.T) {
+	// idBits multiplo di log2(k), come richiesto dalla configurazione
+	tests := []struct {
+		bits, degree, nodes int
+	}{
+		{bits: 16, degree: 2, nodes: 1},
+		{bits: 16, degree: 2, nodes: 2},
+		{bits: 16, degree: 2, nodes: 8},
+		{bits: 16, degree: 2, nodes: 64},
+		{bits: 16, degree: 4, nodes: 64},
+		{bits: 15, degree: 8, nodes: 64},
+		{bits: 16, degree: 16, nodes: 100},
+		{bits: 32, degree: 4, nodes: 200},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("b%d-k%d-n%d", tt.bits, tt.degree, tt.nodes), func(t *testing.T) {
+			sp, err := domain.NewSpace(tt.bits, tt.degree, tt.degree)
+			if err != nil {
+				t.Fatalf("NewSpace: %v", err)
+			}
+			r := newMemRing(t, sp, tt.nodes)
+
+			// Ogni cifra in base k consuma log2(k) bit dell'identificatore:
+			// per cifra un passo de Bruijn più le correzioni sul successore
+			digits := (tt.bits + log2(tt.degree) - 1) / log2(tt.degree)
+			maxHops := digits*(1+2*tt.degree) + tt.nodes
+
+			ctx := context.Background()
+			total, lookups := 0, 0
+			for i := 0; i < 200; i++ {
+				key := sp.NewIdFromString(fmt.Sprintf("key-%d", i))
+				want := r.successorOf(key)
+				for _, start := range r.nodes {
+					got, hops, err := r.transport.FindSuccessorStart(ctx, start.Addr, key, maxHops)
+					if err != nil {
+						t.Fatalf("lookup of %s from %s: %v", key.ToHexString(true), start.Addr, err)
+					}
+					if !got.ID.Equal(want.ID) {
+						t.Fatalf("lookup of %s from %s = %s, want %s",
+							key.ToHexString(true), start.Addr, got.ID.ToHexString(true), want.ID.ToHexString(true))
+					}
+					total += hops
+					lookups++
+				}
+			}
+			t.Logf("%d lookups, %.2f hops on average (bound %d)", lookups, float64(total)/float64(lookups), maxHops)
+		})
+	}
+}
+
+func log2(k int) int {
+	n := 0
+	for ; k > 1; k >>= 1 {
+		n++
+	}
+	return n
+}
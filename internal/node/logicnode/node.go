@@ -1,30 +1,181 @@
 package logicnode
 
 import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/client"
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
 	client2 "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/ctxutil"
 	"KoordeDHT/internal/node/routingtable"
 	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/telemetry/nodemetrics"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
 )
 
+var (
+	// ErrIDCollision is returned by Join when another member of the ring has
+	// the identifier of this node (an address hash collision or a duplicated
+	// node.id): joining anyway would make the two nodes indistinguishable to
+	// the routing tables and corrupt the ring.
+	ErrIDCollision = errors.New("another node has the same ID")
+
+	// ErrRoutingNotInitialized is returned by a lookup step on a node whose
+	// routing table has no successor yet (it has not joined the ring).
+	ErrRoutingNotInitialized = errors.New("routing table not initialized")
+
+	// ErrLookupFailed wraps the cause of a failed lookup of the node
+	// responsible for a key, telling routing failures apart from failures
+	// of the storage on the responsible node.
+	ErrLookupFailed = errors.New("lookup failed")
+
+	// ErrNoSuccessor is returned when a lookup ends without a responsible
+	// node.
+	ErrNoSuccessor = errors.New("no successor found")
+
+	// ErrNoPredecessor is returned by Rebalance when the node does not know
+	// its predecessor yet, and so cannot tell which keys it owns, and by
+	// StoreLocal in that case under NoPredecessorReject.
+	ErrNoPredecessor = errors.New("predecessor not known")
+
+	// ErrMaxHops is returned by a lookup step that reached this node after
+	// more forwarding hops than the hop limit (see WithMaxHops). The nodes
+	// along the lookup recognize it, once returned by a remote node, from
+	// the cause of the failure attached to the status (domain.CauseMaxHops).
+	ErrMaxHops = errors.New("lookup exceeded the maximum hop count")
+
+	// ErrStopping is returned by the client operations (Put, Get, Delete,
+	// their batch variants, DeleteRange and the lookups) started after Stop.
+	ErrStopping = errors.New("node is shutting down")
+)
+
 type Node struct {
 	lgr logger.Logger
 	rt  *routingtable.RoutingTable
-	s   *storage.Storage
+	s   storage.Store
 	cp  *client2.Pool
+
+	pullOnJoin bool // pull (pred, self] from the successor before the first Notify
+	observer   bool // route and answer lookups, but never own keys (see WithObserver)
+	deBruijn   bool // maintain and route through de Bruijn pointers (false = Chord-only ring walk)
+	replicas   int  // replication factor: copies of each resource, owner included (see replication.go)
+	readRepair bool // on an owner miss, read the replicas and store a copy back on the owner
+	iterative  bool // lookups originated here are driven hop by hop by this node (see iterative.go)
+
+	minSuccList, maxSuccList int // adaptive successor list bounds (maxSuccList 0 = fixed size, see adaptive.go)
+
+	metrics *nodemetrics.Metrics // scrapeable metrics (nil = disabled, see WithMetrics)
+
+	hopReserve   time.Duration // time each forwarded hop keeps for its response path
+	minHopBudget time.Duration // minimum time a forwarded lookup hop must have to be issued
+	ownerReserve float64       // fraction of the remaining deadline of a client operation kept to contact the owner after the lookup
+
+	lookupRetries int           // retries of a failed client lookup (see WithLookupRetry)
+	lookupBackoff time.Duration // delay before the first retry, doubled after each failure
+	ownerRetries  int           // new lookups of a client operation whose owner cannot be reached (see WithOwnerRetries)
+
+	ownerResolved func(*domain.Node) // test hook: called with every owner found by lookupOwner before it is contacted
+
+	lookupCache *client.LookupCache // owners found by recent client lookups (nil = disabled, see WithLookupCache)
+
+	lookupParallelism int // de Bruijn candidates queried concurrently by a lookup step (0/1 = one at a time)
+	maxLocalDepth     int // bound on the local recursion of a lookup step (0 = twice the digits of an ID, see WithMaxLocalDepth)
+	maxHops           int // bound on the forwarding hops of a lookup (0 = automatic, see WithMaxHops)
+
+	outbound         chan struct{} // slots of the concurrent outbound forward RPCs (nil = unlimited, see WithMaxOutbound)
+	outboundInFlight atomic.Int64  // outbound forward RPCs in progress
+
+	predMu sync.Mutex // serializes predecessor updates (Notify, checkPredecessor, HandleLeave)
+
+	pausedUntil atomic.Int64 // maintenance loops skip their work until this time (unix ns, 0 = not paused, see pause.go)
+	pauseDetect atomic.Bool  // keep probing the predecessor while paused
+
+	readRepairs atomic.Int64 // resources stored back on their owner by read repair
+
+	leaveMu sync.RWMutex // held for reading by StoreLocal, for writing by Leave when it starts the handoff
+	leaving bool         // Leave started: StoreLocal forwards to the successor instead of storing
+
+	opsMu        sync.Mutex     // guards stopping, so that no operation is registered once Stop drains ops
+	stopping     bool           // Stop started: new client operations fail with ErrStopping
+	ops          sync.WaitGroup // in-flight client operations (see beginOp)
+	drainTimeout time.Duration  // how long Stop waits for the in-flight operations (see WithDrainTimeout)
+
+	chordMu          sync.Mutex    // serializes Chord stabilization rounds (regular and catch-up loops)
+	deBruijnMu       sync.Mutex    // serializes de Bruijn refresh rounds (regular and catch-up loops)
+	catchUpInterval  time.Duration // interval of the catch-up loop after (re)join (0 = disabled)
+	catchUpRounds    int           // maximum number of catch-up rounds
+	minInterval      time.Duration // adaptive stabilization: interval after a change of the routing state
+	maxInterval      time.Duration // adaptive stabilization: interval reached by a stable ring (0 = fixed intervals, see WithAdaptiveStabilization)
+	jitter           float64       // fraction by which every stabilizer interval is randomized (see WithStabilizationJitter)
+	succListFallback bool          // ask the next successor for the list when the first one does not answer
+	asymRounds       int           // consecutive asymmetric rounds before the successor is reported (0 = check disabled, see WithAsymmetryCheck)
+	asymRenotify     bool          // force a fresh Notify while the successor is asymmetric
+	asymStreak       int           // current consecutive asymmetric rounds (guarded by chordMu)
+
+	rtFile         string        // file the routing table is saved to (empty = disabled, see WithRoutingPersistence)
+	rtSaveInterval time.Duration // interval at which a changed routing table is saved
+
+	repairMu  sync.Mutex // serializes storage maintenance passes (periodic and Rebalance)
+	handoffMu sync.Mutex
+	handedOff map[string]struct{} // keys copied to their owner by the last resourceRepair pass
+
+	noPredPolicy  string        // stores received without a predecessor on a multi-node ring (see WithNoPredecessorPolicy)
+	repairPending atomic.Bool   // a store was accepted without a predecessor and waits for a maintenance pass
+	repairNow     chan struct{} // wakes up the storage maintenance loop (see requestPendingRepair)
+
+	anchorMu   sync.Mutex
+	anchorFail map[string]int // consecutive failures of the current de Bruijn anchor candidates, by address
+
+	deBruijnMaxAge time.Duration    // lazy de Bruijn refresh: maximum age of an unchanged window (0 = refresh every round, see WithLazyDeBruijnRefresh)
+	lastAnchor     *domain.Node     // anchor found by the last de Bruijn refresh (guarded by anchorMu)
+	lastSuccs      string           // successor list at the last de Bruijn refresh (see successorsKey)
+	lastRefresh    time.Time        // time of the last de Bruijn refresh
+	now            func() time.Time // clock of the lazy de Bruijn refresh
+
+	startedAt time.Time // creation time of the node, for Uptime
+
+	syncInterval time.Duration // interval of the anti-entropy pass with the successor (0 = disabled, see WithAntiEntropy)
+	syncDepth    int           // depth of the Merkle trees compared by anti-entropy
+
+	vnodes *VNodeGroup // virtual nodes of the same physical node, sharing pool and storage (nil = none, see vnode.go)
+
+	replicaMu     sync.Mutex
+	replicaPred   domain.ID           // predecessor when the owned range was last pushed to the replicas
+	replicaPushed map[string]struct{} // replicas that received the owned range, by address
 }
 
-func New(rout *routingtable.RoutingTable, clientpool *client2.Pool, storage *storage.Storage, opts ...Option) *Node {
+func New(rout *routingtable.RoutingTable, clientpool *client2.Pool, storage storage.Store, opts ...Option) *Node {
 	n := &Node{
 		lgr: &logger.NopLogger{},
 		rt:  rout,
 		cp:  clientpool,
 		s:   storage,
+
+		pullOnJoin:       true,
+		deBruijn:         true,
+		replicas:         1,
+		hopReserve:       2 * time.Millisecond,
+		minHopBudget:     5 * time.Millisecond,
+		ownerReserve:     0.2,
+		jitter:           defaultJitter,
+		succListFallback: true,
+		drainTimeout:     defaultDrainTimeout,
+		syncDepth:        defaultSyncDepth,
+		asymRounds:       defaultAsymmetryRounds,
+		handedOff:        make(map[string]struct{}),
+		noPredPolicy:     NoPredecessorAccept,
+		repairNow:        make(chan struct{}, 1),
+		anchorFail:       make(map[string]int),
+		now:              time.Now,
+		startedAt:        time.Now(),
 	}
 	// Apply options
 	for _, opt := range opts {
@@ -42,7 +193,8 @@ func New(rout *routingtable.RoutingTable, clientpool *client2.Pool, storage *sto
 //   - peers:   slice of bootstrap peer addresses ("host:port")
 //
 // Returns:
-//   - error: if no bootstrap peer responded successfully
+//   - error: if no bootstrap peer responded successfully, or ErrIDCollision
+//     if the successor or its predecessor has the ID of this node
 func (n *Node) Join(peers []string) error {
 	if len(peers) == 0 {
 		return fmt.Errorf("join: no bootstrap peers provided")
@@ -67,7 +219,7 @@ func (n *Node) Join(peers []string) error {
 		conn.Close()
 		if lastErr == nil && succ != nil {
 			if succ.ID.Equal(self.ID) {
-				return fmt.Errorf("join: there is already a node with the same ID")
+				return fmt.Errorf("join: successor %s: %w", succ.Addr, ErrIDCollision)
 			}
 			n.lgr.Info("join: candidate successor found",
 				logger.F("bootstrap", addr),
@@ -85,6 +237,10 @@ func (n *Node) Join(peers []string) error {
 	if succ == nil {
 		return fmt.Errorf("join: all bootstrap attempts failed: %w", lastErr)
 	}
+	// An observer owns no range and never announces itself
+	if n.observer {
+		return n.joinAsObserver(succ)
+	}
 
 	// Ask successor for its predecessor
 	ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
@@ -95,14 +251,34 @@ func (n *Node) Join(peers []string) error {
 	}
 	pred, err := client2.GetPredecessor(ctx, cli, n.Space())
 	cancel()
+	if errors.Is(err, client2.ErrNoPredecessor) {
+		pred, err = nil, nil // successor is still stabilizing, proceed without a predecessor
+	}
 	if err != nil {
 		conn.Close()
 		return fmt.Errorf("join: failed to get predecessor of successor %s: %w", succ.Addr, err)
 	}
+	if pred != nil && pred.ID.Equal(self.ID) && pred.Addr != self.Addr {
+		// The colliding node joined in front of succ but the ring has not
+		// stabilized around it yet: our Notify would be taken for its own
+		conn.Close()
+		n.lgr.Error("join: predecessor of the successor has the same ID",
+			logger.FNode("successor", succ), logger.FNode("predecessor", pred))
+		return fmt.Errorf("join: predecessor %s of successor %s: %w", pred.Addr, succ.Addr, ErrIDCollision)
+	}
 	if pred != nil {
 		n.lgr.Info("join: successor has predecessor", logger.FNode("predecessor", pred))
 	}
 
+	// Pull our range (pred, self] before announcing ourselves, so that the
+	// keys are already here when requests for them start being routed to us.
+	if n.pullOnJoin {
+		if err := n.pullRange(cli, succ, pred); err != nil {
+			conn.Close()
+			return fmt.Errorf("join: %w", err)
+		}
+	}
+
 	// Notify successor that we may be its predecessor
 	ctx, cancel = context.WithTimeout(context.Background(), n.cp.FailureTimeout())
 	err = client2.Notify(ctx, cli, self)
@@ -114,11 +290,16 @@ func (n *Node) Join(peers []string) error {
 
 	// Update local routing table (release old, set new)
 	if pred != nil {
-		err = n.cp.AddRef(pred.Addr)
-		if err != nil {
-			n.lgr.Warn("join: failed to add ref to predecessor", logger.F("err", err))
+		n.predMu.Lock()
+		if n.rt.GetPredecessor() == nil { // a Notify may have already set it
+			err = n.cp.AddRef(pred.Addr)
+			if err != nil {
+				n.lgr.Warn("join: failed to add ref to predecessor", logger.F("err", err))
+			}
+			n.rt.SetPredecessor(pred)
+			n.requestPendingRepair()
 		}
-		n.rt.SetPredecessor(pred)
+		n.predMu.Unlock()
 	}
 	err = n.cp.AddRef(succ.Addr)
 	if err != nil {
@@ -130,7 +311,9 @@ func (n *Node) Join(peers []string) error {
 	n.fixSuccessorList()
 
 	// Initialize de Bruijn pointers
-	n.fixDeBruijn()
+	if n.deBruijn {
+		n.fixDeBruijn()
+	}
 
 	n.lgr.Info("join: completed successfully",
 		logger.FNode("self", self),
@@ -138,6 +321,74 @@ func (n *Node) Join(peers []string) error {
 	return nil
 }
 
+// pullRange copies the range (pred, self] from succ, over cli, into the
+// local storage. Without a predecessor the whole ring except (self, succ]
+// is pulled.
+func (n *Node) pullRange(cli dhtv1.DHTClient, succ, pred *domain.Node) error {
+	from := succ.ID
+	if pred != nil {
+		from = pred.ID
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+	defer cancel()
+	resources, err := client2.RetrieveRangeRemote(ctx, cli, n.Space(), from, n.rt.Self().ID)
+	if err != nil {
+		return fmt.Errorf("failed to pull range from successor %s: %w", succ.Addr, err)
+	}
+	for _, res := range resources {
+		n.s.Put(res)
+	}
+	n.lgr.Info("join: pulled range from successor",
+		logger.FNode("successor", succ), logger.F("count", len(resources)))
+	return nil
+}
+
+// joinAsObserver completes the join of an observer node. The observer
+// adopts succ as its successor and builds its successor list and de Bruijn
+// pointers like any other node, but it never notifies succ and keeps no
+// predecessor: no member ever adopts it as successor or predecessor, so no
+// key range is assigned to it, while lookups issued through it still end at
+// the real owners (its own (self, succ] check returns succ).
+func (n *Node) joinAsObserver(succ *domain.Node) error {
+	if err := n.cp.AddRef(succ.Addr); err != nil {
+		n.lgr.Warn("join: failed to add ref to successor", logger.F("err", err))
+	}
+	n.rt.SetSuccessor(0, succ)
+	n.fixSuccessorList()
+	if n.deBruijn {
+		n.fixDeBruijn()
+	}
+
+	n.lgr.Info("join: completed successfully as observer",
+		logger.FNode("self", n.rt.Self()),
+		logger.FNode("successor", succ))
+	return nil
+}
+
+// DeBruijnEnabled reports whether the node routes through de Bruijn
+// pointers (see WithDeBruijn).
+func (n *Node) DeBruijnEnabled() bool {
+	return n.deBruijn
+}
+
+// IsObserver reports whether the node runs in observer mode.
+func (n *Node) IsObserver() bool {
+	return n.observer
+}
+
+// HandOff transfers every locally stored resource that this node is no
+// longer responsible for to its current owner. It is meant to be called
+// right after a rejoin under a new identifier (e.g. when the ID is
+// re-derived from a changed address), without waiting for the periodic
+// storage maintenance to run.
+//
+// A single call only copies the resources: like every maintenance pass
+// (see rebalanceOnce) the handoff is two-phase, and the local copies are
+// deleted by the next pass that still finds them misplaced.
+func (n *Node) HandOff(ctx context.Context) {
+	n.resourceRepair(ctx)
+}
+
 // CreateNewDHT initializes this node as the first member of a new Koorde DHT.
 //
 // In single-node mode, the routing table is set so that:
@@ -150,6 +401,9 @@ func (n *Node) Join(peers []string) error {
 // are available and the node is intended to start a brand new DHT ring.
 func (n *Node) CreateNewDHT() {
 	n.rt.InitSingleNode()
+	if !n.deBruijn {
+		n.rt.SetDeBruijn(0, nil) // Chord-only mode: no de Bruijn pointers at all
+	}
 }
 
 // Leave gracefully removes the current node from the DHT.
@@ -157,12 +411,20 @@ func (n *Node) CreateNewDHT() {
 // to transfer all resources currently stored at this node.
 //
 // Behavior:
-//   - If this is the only node in the ring, the leave is a no-op.
+//   - If this is the only node in the ring, or the node has already left,
+//     the leave is a no-op.
 //   - Otherwise:
 //     1. Notify the successor of the departure.
-//     2. Attempt to transfer all resources to the immediate successor.
-//     3. If some resources cannot be transferred, resolve their
+//     2. Enter the leaving state: from now on StoreLocal forwards incoming
+//     resources to the successor, so that none is stored after the
+//     transfer snapshot and stranded here.
+//     3. Attempt to transfer all resources to the immediate successor,
+//     falling back to the next entries of the successor list while the
+//     transfer fails (e.g. the successor is unreachable).
+//     4. If some resources cannot be transferred, resolve their
 //     responsible node via FindSuccessor and retry individually.
+//     5. Notify the predecessor (PredecessorLeaving), which replaces this
+//     node with the successor that received the resources right away.
 //   - Logs INFO on successful transfers, WARN/ERROR on failures.
 //
 // Returns:
@@ -173,37 +435,104 @@ func (n *Node) Leave() error {
 	self := n.rt.Self()
 	succ := n.rt.FirstSuccessor()
 
+	// Case: observer, nobody references it and it stores nothing
+	if n.observer {
+		n.lgr.Info("leave: observer node, no need to notify others", logger.FNode("self", self))
+		return nil
+	}
+
+	// Case: already left (e.g. Stop after a Leave requested over RPC)
+	n.leaveMu.RLock()
+	left := n.leaving
+	n.leaveMu.RUnlock()
+	if left {
+		n.lgr.Debug("leave: node has already left the DHT", logger.FNode("self", self))
+		return nil
+	}
+
 	// Case: single node in the ring
 	if succ == nil || succ.ID.Equal(self.ID) {
 		n.lgr.Warn("leave: single node in DHT, no need to notify others", logger.FNode("self", self))
 		return nil
 	}
 
-	cli, err := n.cp.GetFromPool(succ.Addr)
-	if err != nil {
-		return fmt.Errorf("leave: failed to get client for successor %s: %w", succ.Addr, err)
-	}
-
 	// Notify successor of departure (best-effort)
-	{
+	if cli, release, err := n.clientFor(succ.Addr); err != nil {
+		n.lgr.Error("leave: failed to get client for successor", logger.F("successor", succ.Addr), logger.F("err", err))
+	} else {
 		ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
 		if err := client2.Leave(ctx, cli, self); err != nil {
 			n.lgr.Error("leave: failed to notify successor", logger.F("successor", succ.Addr), logger.F("err", err))
 			// Continue anyway with resource transfer
 		}
 		cancel()
+		release()
 	}
 
-	// Attempt bulk transfer to successor
-	data := n.s.All()
+	// Enter the leaving state. Taking leaveMu for writing waits for the
+	// in-flight StoreLocal calls, so the snapshot below includes them.
+	n.leaveMu.Lock()
+	n.leaving = true
+	n.leaveMu.Unlock()
+
+	// Candidates for the handoff: the successor list, in ring order
+	var heirs []*domain.Node
+	for _, nd := range n.rt.SuccessorList() {
+		if nd != nil && !nd.ID.Equal(self.ID) && !slices.ContainsFunc(heirs, func(h *domain.Node) bool { return h.ID.Equal(nd.ID) }) {
+			heirs = append(heirs, nd)
+		}
+	}
+	heir := 0 // index in heirs of the node that received the bulk transfer
+
+	// Attempt bulk transfer to the successor, falling back to the next
+	// entries of the successor list while it is unreachable
+	data := n.leaveData()
 	if len(data) > 0 {
-		ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
-		failed, err := client2.StoreRemote(ctx, cli, data)
-		cancel()
+		failed, transferred := data, false // treat all as failed until a transfer succeeds
+		for i, cand := range heirs {
+			if n.sibling(cand) {
+				// a virtual node sharing the storage already holds the data
+				heir, failed, transferred = i, nil, true
+				break
+			}
+			cli, release, err := n.clientFor(cand.Addr)
+			if err != nil {
+				n.lgr.Warn("Leave: failed to connect to successor, trying the next one",
+					logger.FNode("successor", cand), logger.F("err", err))
+				continue
+			}
+			// A fallback heir still sees the unreachable nodes before it as
+			// its predecessors and would reject keys it is about to own:
+			// hand them over without the ownership check
+			store := client2.StoreRemote
+			if i > 0 {
+				store = client2.StoreReplicas
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+			f, err := store(ctx, cli, data)
+			cancel()
+			release()
+			if err != nil {
+				n.lgr.Warn("Leave: bulk transfer to successor failed, trying the next one",
+					logger.FNode("successor", cand), logger.F("total", len(data)), logger.F("err", err))
+				continue
+			}
+			heir, failed, transferred = i, f, true
+			break
+		}
+		if !transferred {
+			n.lgr.Warn("Leave: bulk transfer failed on every successor, retrying individually",
+				logger.F("total", len(data)))
+		}
+
+		// Resolve the responsible nodes through the successor that took the data
+		cli, release, err := n.clientFor(heirs[heir].Addr)
 		if err != nil {
-			n.lgr.Warn("Leave: bulk transfer to successor failed, retrying individually",
-				logger.F("total", len(data)), logger.F("err", err))
-			failed = data // treat all as failed
+			n.lgr.Error("Leave: no successor reachable to resolve the resources left",
+				logger.F("left", len(failed)), logger.F("err", err))
+			failed = nil
+		} else {
+			defer release()
 		}
 
 		// Retry individually for any failed resources
@@ -252,19 +581,97 @@ func (n *Node) Leave() error {
 		}
 	}
 
+	// Let the predecessor replace this node with the successors that
+	// now hold its resources, without waiting for stabilization
+	if pred := n.rt.GetPredecessor(); pred != nil && !pred.ID.Equal(self.ID) {
+		if cli, release, err := n.clientFor(pred.Addr); err != nil {
+			n.lgr.Warn("leave: failed to get client for predecessor", logger.F("predecessor", pred.Addr), logger.F("err", err))
+		} else {
+			ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+			if err := client2.PredecessorLeaving(ctx, cli, self, heirs[heir:]); err != nil {
+				n.lgr.Warn("leave: failed to notify predecessor", logger.F("predecessor", pred.Addr), logger.F("err", err))
+			}
+			cancel()
+			release()
+		}
+	}
+
 	n.lgr.Info("leave: node has gracefully left the DHT", logger.FNode("self", self))
 	return nil
 }
 
+// leaveData returns the resources Leave hands over: the whole storage or,
+// for a virtual node, only the resources that are not owned by another
+// virtual node sharing the storage.
+func (n *Node) leaveData() []domain.Resource {
+	data := n.s.All()
+	if n.vnodes == nil {
+		return data
+	}
+	out := data[:0]
+	for _, res := range data {
+		if !n.ownedBySibling(res.Key) {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
 // Stop releases all resources owned by the node.
 // Should be called on shutdown.
+//
+// New client operations are rejected with ErrStopping from now on, and the
+// in-flight ones are given up to the drain timeout (see WithDrainTimeout)
+// to complete before the node leaves the ring and the client pool is
+// closed under them.
 func (n *Node) Stop() {
 	if n == nil {
 		return
 	}
+	n.drain()
 	_ = n.Leave()
 	if n.cp != nil {
 		_ = n.cp.Close()
 	}
 	n.lgr.Info("node stopped gracefully")
 }
+
+// defaultDrainTimeout is how long Stop waits for the in-flight client
+// operations (see WithDrainTimeout).
+const defaultDrainTimeout = 5 * time.Second
+
+// beginOp registers a client operation, so that Stop waits for it before
+// closing the client pool. It fails if ctx is done or with ErrStopping once
+// Stop has started; every successful call must be paired with n.ops.Done().
+func (n *Node) beginOp(ctx context.Context) error {
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return err
+	}
+	n.opsMu.Lock()
+	defer n.opsMu.Unlock()
+	if n.stopping {
+		return ErrStopping
+	}
+	n.ops.Add(1)
+	return nil
+}
+
+// drain rejects new client operations and waits up to the drain timeout
+// for the in-flight ones to complete.
+func (n *Node) drain() {
+	n.opsMu.Lock()
+	n.stopping = true
+	n.opsMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		n.ops.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(n.drainTimeout):
+		n.lgr.Warn("stop: in-flight operations still running after the drain timeout, closing anyway",
+			logger.F("timeout", n.drainTimeout.String()))
+	}
+}
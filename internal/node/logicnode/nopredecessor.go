@@ -0,0 +1,58 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"fmt"
+)
+
+// Policies for the stores received by a node that does not know its
+// predecessor but is not alone in the ring (see WithNoPredecessorPolicy).
+const (
+	NoPredecessorAccept = "accept" // store the resource, as if the node owned every key
+	NoPredecessorRepair = "repair" // store it and run a storage maintenance pass as soon as the predecessor is known
+	NoPredecessorReject = "reject" // refuse it with ErrNoPredecessor, so that the caller resolves the owner again
+)
+
+// storeWithoutPredecessor applies the no-predecessor policy to a store of
+// resource received while the predecessor is unknown. It returns an error
+// wrapping ErrNoPredecessor if the store must be rejected, and nil if it
+// may proceed. A node whose first successor is itself (or unknown) is
+// alone in the ring and owns every key, so the policy does not apply.
+func (n *Node) storeWithoutPredecessor(resource domain.Resource) error {
+	if n.noPredPolicy != NoPredecessorRepair && n.noPredPolicy != NoPredecessorReject {
+		return nil
+	}
+	succ := n.rt.FirstSuccessor()
+	if succ == nil || succ.ID.Equal(n.rt.Self().ID) {
+		return nil
+	}
+	n.metrics.ObserveStoreWithoutPredecessor(n.noPredPolicy)
+	if n.noPredPolicy == NoPredecessorReject {
+		n.lgr.Debug("StoreLocal: predecessor unknown, store rejected",
+			logger.F("key", resource.RawKey), logger.FNode("successor", succ))
+		return fmt.Errorf("storelocal: key %s: %w", resource.RawKey, ErrNoPredecessor)
+	}
+	n.lgr.Warn("StoreLocal: predecessor unknown, resource stored and flagged for repair",
+		logger.F("key", resource.RawKey), logger.FNode("successor", succ))
+	n.repairPending.Store(true)
+	return nil
+}
+
+// requestPendingRepair wakes up the storage maintenance loop if a store
+// accepted without a predecessor is waiting for a pass (see
+// NoPredecessorRepair). It is called whenever a predecessor is set.
+func (n *Node) requestPendingRepair() {
+	if !n.repairPending.Swap(false) {
+		return
+	}
+	n.signalRepair()
+}
+
+// signalRepair asks the storage maintenance loop for a pass.
+func (n *Node) signalRepair() {
+	select {
+	case n.repairNow <- struct{}{}:
+	default: // a pass is already requested
+	}
+}
@@ -0,0 +1,84 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/telemetry/nodemetrics"
+	"KoordeDHT/internal/node/testring"
+	"KoordeDHT/internal/telemetry/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNoPredecessorPolicy(t *testing.T) {
+	tests := []struct {
+		policy    string
+		wantErr   bool // la scrittura è rifiutata con ErrNoPredecessor
+		wantLocal bool // la risorsa resta sul nodo senza predecessore
+		wantCount float64
+		wantOwner bool // la risorsa raggiunge il responsabile appena il predecessore è noto
+	}{
+		{policy: logicnode.NoPredecessorAccept, wantLocal: true},
+		{policy: logicnode.NoPredecessorRepair, wantLocal: true, wantCount: 1, wantOwner: true},
+		{policy: logicnode.NoPredecessorReject, wantErr: true, wantCount: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			m := nodemetrics.New(metrics.NewRegistry())
+			r := testring.New(t, 3, testring.WithNodeOptions(
+				logicnode.WithNoPredecessorPolicy(tt.policy), logicnode.WithMetrics(m)))
+			r.StopStabilizers()
+			n, owner := r.Members[1], r.Members[2]
+
+			// Una chiave di owner, che n accetta solo perché ha perso il predecessore
+			var res domain.Resource
+			for i := 0; ; i++ {
+				k := fmt.Sprintf("key-%d", i)
+				if id := r.Space.NewIdFromString(k); r.Owner(id) == owner {
+					res = domain.Resource{Key: id, RawKey: k, Value: k}
+					break
+				}
+			}
+			n.Node.SetPredecessor(nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			err := n.Node.StoreLocal(ctx, res)
+			if got := errors.Is(err, logicnode.ErrNoPredecessor); got != tt.wantErr {
+				t.Fatalf("StoreLocal error = %v, want ErrNoPredecessor: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("StoreLocal: %v", err)
+			}
+			if _, err := n.Node.RetrieveLocal(res.Key); (err == nil) != tt.wantLocal {
+				t.Errorf("stored locally = %v, want %v", err == nil, tt.wantLocal)
+			}
+			if got := m.NoPredStores.Value(tt.policy); got != tt.wantCount {
+				t.Errorf("koorde_stores_without_predecessor_total{action=%q} = %v, want %v", tt.policy, got, tt.wantCount)
+			}
+
+			// Con intervalli lunghi solo la riparazione richiesta dalla
+			// scrittura può consegnare la risorsa al responsabile
+			sctx, stop := context.WithCancel(context.Background())
+			defer stop()
+			n.Node.StartStabilizers(sctx, time.Hour, time.Hour, time.Hour)
+			n.Node.Notify(r.Members[0].Node.Self())
+			wait := 300 * time.Millisecond
+			if tt.wantOwner {
+				wait = 2 * time.Second
+			}
+			deadline := time.Now().Add(wait)
+			_, err = owner.Node.RetrieveLocal(res.Key)
+			for err != nil && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+				_, err = owner.Node.RetrieveLocal(res.Key)
+			}
+			if (err == nil) != tt.wantOwner {
+				t.Errorf("resource on the owner = %v, want %v", err == nil, tt.wantOwner)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"testing"
+	"time"
+)
+
+func TestRepeatNotifyRefreshesLastSeen(t *testing.T) {
+	r := testring.New(t, 3)
+	r.StopStabilizers()
+	time.Sleep(50 * time.Millisecond) // lascia terminare i round di stabilizzazione in volo
+
+	m := r.Members[1]
+	pred := m.Node.Predecessor()
+	if pred == nil || pred.Addr != r.Members[0].Addr {
+		t.Fatalf("unexpected predecessor %v", pred)
+	}
+
+	tests := []struct {
+		name    string
+		from    *domain.Node
+		refresh bool
+	}{
+		// Notify ripetuta dal predecessore attuale: solo segnale di vita
+		{name: "current predecessor", from: &domain.Node{ID: pred.ID, Addr: pred.Addr}, refresh: true},
+		// Candidato fuori da (pred, self): ignorato del tutto
+		{name: "worse candidate", from: r.Members[2].Node.Self(), refresh: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := m.Node.PredecessorLastSeen()
+			if before.IsZero() {
+				t.Fatal("predecessor has no last-seen time")
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			m.Node.Notify(tt.from)
+
+			after := m.Node.PredecessorLastSeen()
+			if got := after.After(before); got != tt.refresh {
+				t.Errorf("last-seen refreshed = %v, want %v (before %v, after %v)", got, tt.refresh, before, after)
+			}
+			if now := m.Node.Predecessor(); now == nil || !now.ID.Equal(pred.ID) {
+				t.Errorf("predecessor changed to %v, want %s", now, pred.Addr)
+			}
+		})
+	}
+}
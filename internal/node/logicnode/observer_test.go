@@ -0,0 +1,62 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestObserverForwardsPutsAndStoresNothing(t *testing.T) {
+	r := testring.New(t, 4)
+	obs := r.AddObserver()
+	// lascia girare qualche round di stabilizzazione con l'observer attivo
+	time.Sleep(200 * time.Millisecond)
+	r.WaitStable()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const keys = 64
+	for i := 0; i < keys; i++ {
+		raw := fmt.Sprintf("key-%d", i)
+		res := domain.Resource{Key: r.Space.NewIdFromString(raw), RawKey: raw, Value: "v"}
+		if err := obs.Node.Put(ctx, res); err != nil {
+			t.Fatalf("Put(%s) via observer: %v", raw, err)
+		}
+		// Ogni chiave deve trovarsi presso il vero proprietario
+		if _, err := r.Owner(res.Key).Node.RetrieveLocal(res.Key); err != nil {
+			t.Errorf("key %s not stored at its owner: %v", raw, err)
+		}
+		got, err := obs.Node.Get(ctx, res.Key)
+		if err != nil || got.Value != "v" {
+			t.Errorf("Get(%s) via observer: got %v, %v", raw, got, err)
+		}
+	}
+
+	if stored := obs.Node.GetAllResourceStored(); len(stored) != 0 {
+		t.Errorf("observer stores %d resources, want 0", len(stored))
+	}
+
+	// Nessun membro adotta l'observer come vicino
+	self := obs.Node.Self()
+	for _, m := range r.Members {
+		if p := m.Node.Predecessor(); p != nil && p.ID.Equal(self.ID) {
+			t.Errorf("member %s has the observer as predecessor", m.Addr)
+		}
+		for _, s := range m.Node.SuccessorList() {
+			if s != nil && s.ID.Equal(self.ID) {
+				t.Errorf("member %s has the observer in its successor list", m.Addr)
+			}
+		}
+	}
+
+	// StoreLocal rifiuta sempre, qualunque sia la chiave
+	res := domain.Resource{Key: self.ID, RawKey: "self", Value: "v"}
+	if err := obs.Node.StoreLocal(ctx, res); !errors.Is(err, domain.ErrNotResponsible) {
+		t.Errorf("StoreLocal on observer: got %v, want ErrNotResponsible", err)
+	}
+}
@@ -1,14 +1,22 @@
 package logicnode
 
 import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
 	"KoordeDHT/internal/node/client"
 	"KoordeDHT/internal/node/ctxutil"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/telemetry"
 	"context"
 	"errors"
 	"fmt"
+	"math/bits"
+	"slices"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -93,28 +101,235 @@ func (n *Node) findNextHop(list []*domain.Node, currentI domain.ID) int {
 //   - Otherwise, the method computes the initial imaginary node currentI
 //     and the shifted target kshift using BestImaginarySimple, and forwards
 //     the request to FindSuccessorStep for continued routing.
+//   - In iterative mode (WithIterativeLookup) the whole lookup is driven
+//     by this node instead (see findSuccessorIterative).
 //
 // Errors:
 //   - Returns an error if the routing table is not initialized (successor is nil).
 //   - Returns an error if initial currentI and kshift cannot be computed.
-func (n *Node) FindSuccessorInit(ctx context.Context, target domain.ID) (*domain.Node, error) {
+func (n *Node) FindSuccessorInit(ctx context.Context, target domain.ID) (succ *domain.Node, err error) {
+	defer func(start time.Time) {
+		n.metrics.ObserveLookup(err)
+		telemetry.RecordLookup(ctx, start, err)
+	}(time.Now())
 	// Abort if context expired
 	if err := ctxutil.CheckContext(ctx); err != nil {
 		return nil, err
 	}
 
+	if n.iterative {
+		return n.findSuccessorIterative(ctx, target, nil)
+	}
+	hop, err := n.initialHop(target)
+	if err != nil {
+		return nil, err
+	}
+	if hop.Successor != nil {
+		return hop.Successor, nil
+	}
+
+	// Continue the lookup in STEP mode
+	return n.FindSuccessorStep(ctx, target, hop.CurrentI, hop.KShift)
+}
+
+// findSuccessorRetry runs FindSuccessorInit and, if it fails, runs the
+// whole lookup again up to lookupRetries times with exponential backoff
+// (see WithLookupRetry). It returns the last error once the retries are
+// exhausted or ctx is done.
+func (n *Node) findSuccessorRetry(ctx context.Context, target domain.ID) (*domain.Node, error) {
+	delay := n.lookupBackoff
+	for attempt := 1; ; attempt++ {
+		succ, err := n.FindSuccessorInit(ctx, target)
+		if err == nil || attempt > n.lookupRetries || ctx.Err() != nil {
+			return succ, err
+		}
+		n.lgr.Debug("lookup failed, retrying",
+			logger.F("target", target.ToHexString(true)), logger.F("attempt", attempt),
+			logger.F("delay", delay), logger.F("err", err))
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// lookupOwner returns the node responsible for target for a client
+// operation. If target is in (predecessor, self] and the node has not
+// started to leave, the node itself is the owner and no lookup runs, so a
+// client that sends its request to the owner directly (see client.Ring)
+// costs no routing hop. With useCache, a live entry of the lookup cache
+// (see WithLookupCache) answers without any lookup and cached is true;
+// otherwise the owner is found by findSuccessorRetry and recorded in the
+// cache. The lookup runs under a share of the deadline of ctx, the rest
+// being kept to contact the owner (see WithOwnerReserve).
+func (n *Node) lookupOwner(ctx context.Context, target domain.ID, useCache bool) (succ *domain.Node, cached bool, err error) {
+	self := n.rt.Self()
+	if pred := n.rt.GetPredecessor(); !n.observer && pred != nil && target.Between(pred.ID, self.ID) && !n.isLeaving() {
+		return self, false, nil
+	}
+	if useCache {
+		if succ := n.lookupCache.Get(target); succ != nil {
+			return succ, true, nil
+		}
+	}
+	var reserve time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		reserve = time.Duration(float64(time.Until(deadline)) * n.ownerReserve)
+	}
+	lookupCtx, cancel, err := ctxutil.HopContext(ctx, reserve, n.minHopBudget)
+	if err != nil {
+		return nil, false, err
+	}
+	defer cancel()
+	succ, err = n.findSuccessorRetry(lookupCtx, target)
+	if err == nil && succ != nil {
+		n.lookupCache.Add(target, succ)
+		if n.ownerResolved != nil {
+			n.ownerResolved(succ)
+		}
+	}
+	return succ, false, err
+}
+
+// ownerBudget checks, before a client operation (op is "put", "get" or
+// "delete") sends its request for key to the owner found by its lookup,
+// that ctx still leaves the minimum hop budget (see WithHopBudget) for
+// it. Otherwise the lookup consumed the deadline and a DeadlineExceeded
+// error naming the operation and the owner is returned, since the request
+// could not complete. A ctx already done yields its status (see
+// ctxutil.CheckContext), named the same way.
+func (n *Node) ownerBudget(ctx context.Context, op, key string, owner *domain.Node) error {
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		st := status.Convert(err)
+		return status.Errorf(st.Code(), "%s: key %s: %s before contacting owner %s", op, key, st.Message(), owner.Addr)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline); left < n.minHopBudget {
+			n.lgr.Debug("not enough time left to contact the owner",
+				logger.F("op", op), logger.F("key", key), logger.FNode("owner", owner), logger.F("left", left))
+			return status.Errorf(codes.DeadlineExceeded, "%s: key %s: deadline exhausted by the lookup, %s left to contact owner %s",
+				op, key, left.Round(time.Microsecond), owner.Addr)
+		}
+	}
+	return nil
+}
+
+// retryOwner is called by the client operations (Put, Get, Delete) when
+// the owner found by their lookup cannot be reached (see ownerConnFailed),
+// at their attempt-th try (0 = first). It drops owner from the lookup cache
+// and reports whether the operation must run again with a new lookup:
+// always if the owner came from the cache, otherwise up to ownerRetries
+// times (see WithOwnerRetries), waiting the lookup backoff, doubled at
+// every attempt, so that the stabilization can route around the dead node.
+// It reports false as soon as ctx is done.
+func (n *Node) retryOwner(ctx context.Context, owner *domain.Node, cached bool, attempt int) bool {
+	n.lookupCache.Invalidate(owner.Addr)
+	if cached {
+		return true
+	}
+	if attempt >= n.ownerRetries {
+		return false
+	}
+	delay := n.lookupBackoff << attempt
+	n.lgr.Debug("owner unreachable, resolving it again",
+		logger.FNode("owner", owner), logger.F("attempt", attempt+1), logger.F("delay", delay))
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
+
+// staleCachedOwner is called by Put and Get when the owner they took from
+// the lookup cache answered err. It reports whether the answer means that
+// the owner no longer holds the key, because a node joined between the key
+// and the cached owner since the entry was recorded: the owner rejected the
+// write as not responsible (domain.ErrNotResponsible, Aborted over gRPC) or,
+// with notFound, the key is not there. The entries of owner are then
+// dropped, and the operation must run again with a full lookup.
+func (n *Node) staleCachedOwner(owner *domain.Node, cached bool, err error, notFound bool) bool {
+	if !cached || err == nil {
+		return false
+	}
+	moved := errors.Is(err, domain.ErrNotResponsible) || status.Code(err) == codes.Aborted
+	if notFound {
+		moved = moved || errors.Is(err, domain.ErrResourceNotFound) || status.Code(err) == codes.NotFound
+	}
+	if !moved {
+		return false
+	}
+	n.lgr.Debug("cached owner no longer holds the key, looking it up again",
+		logger.FNode("owner", owner), logger.F("err", err))
+	n.lookupCache.Invalidate(owner.Addr)
+	return true
+}
+
+// ownerConnFailed reports whether err means that the owner found by the
+// lookup could not be reached at all (it may have died or left since), the
+// only failure a new lookup can route around: Unavailable, which is also
+// the code of a failed dial, or a timeout. Any other error is an answer of
+// a live owner, such as a full storage, and is returned as it is.
+func ownerConnFailed(err error) bool {
+	if errors.Is(err, client.ErrTimeout) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// FindPredecessor starts a predecessor lookup from this node: it returns
+// the node n with target ∈ (n, successor(n)], i.e. the predecessor of the
+// node responsible for target, as seen by n.
+//
+// The lookup follows the same de Bruijn routing as FindSuccessorInit
+// (always recursively, even in iterative mode) but ends one step earlier:
+// the last node returns itself instead of its successor, which saves the
+// GetPredecessor round trip of a lookup followed by a predecessor query.
+//
+// Errors: as FindSuccessorInit.
+func (n *Node) FindPredecessor(ctx context.Context, target domain.ID) (*domain.Node, error) {
+	// Abort if context expired
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	hop, err := n.initialHop(target)
+	if err != nil {
+		return nil, err
+	}
+	if hop.Successor != nil {
+		return n.rt.Self(), nil
+	}
+	return n.FindPredecessorStep(ctx, target, hop.CurrentI, hop.KShift)
+}
+
+// FindPredecessorStep continues a predecessor lookup from this node (see
+// FindPredecessor). The routing is that of FindSuccessorStep.
+func (n *Node) FindPredecessorStep(ctx context.Context, target, currentI, kshift domain.ID) (*domain.Node, error) {
+	return n.lookupStep(ctx, target, currentI, kshift, true, 0)
+}
+
+// initialHop starts a lookup of target at this node: it returns the
+// successor if the target lies in (self, successor], and otherwise the
+// initial imaginary node and shifted target (no candidates).
+func (n *Node) initialHop(target domain.ID) (*domain.NextHop, error) {
 	self := n.rt.Self()
 	succ := n.rt.FirstSuccessor()
 
 	// check if the target is in (self, successor]
 	if succ == nil {
 		n.lgr.Error("routing table not initialized: successor is nil")
-		return nil, status.Error(codes.Internal, "node not initialized (routing table not initialized)")
+		return nil, ErrRoutingNotInitialized
 	}
 	if target.Between(self.ID, succ.ID) {
 		n.lgr.Debug("EndLookup: target in (self, successor], returning successor",
 			logger.F("target", target.ToHexString(true)), logger.FNode("successor", succ))
-		return succ, nil
+		return &domain.NextHop{Successor: succ}, nil
 	}
 
 	// Compute initial imaginary node and shifted target
@@ -124,9 +339,7 @@ func (n *Node) FindSuccessorInit(ctx context.Context, target domain.ID) (*domain
 			logger.F("target", target.ToHexString(true)), logger.F("err", err))
 		return nil, status.Error(codes.Internal, "failed to compute initial currentI and kshift")
 	}
-
-	// Continue the lookup in STEP mode
-	return n.FindSuccessorStep(ctx, target, currentI, kshift)
+	return &domain.NextHop{CurrentI: currentI, KShift: kshift}, nil
 }
 
 // FindSuccessorStep continues a successor lookup from this node.
@@ -142,122 +355,404 @@ func (n *Node) FindSuccessorInit(ctx context.Context, target domain.ID) (*domain
 //     Each candidate node is tried in reverse order (from closest to farthest).
 //     If all fail, fallback to the immediate successor.
 //   - If not, forward directly to the successor (this node is not the predecessor of currentI).
+//   - With de Bruijn routing disabled (WithDeBruijn(false)) every step is
+//     forwarded to the successor, walking the ring until the owner is found.
 //
 // Errors:
 //   - Returns an error if the routing table is not initialized (successor is nil).
-//   - Returns an error if arithmetic (MulKMod, AddMod, NextDigitBaseK) fails.
+//   - Returns an error if arithmetic (MulKAddModInto, NextDigitBaseK) fails.
 //   - Returns ctx.Err() if the context has expired or been canceled.
 func (n *Node) FindSuccessorStep(ctx context.Context, target, currentI, kshift domain.ID) (*domain.Node, error) {
+	return n.lookupStep(ctx, target, currentI, kshift, false, 0)
+}
+
+// lookupStep runs one recursive lookup step at this node. The lookup ends
+// at the node n with target ∈ (n, successor(n)], which returns its
+// successor, or itself if wantPred is set (see FindPredecessor). depth
+// counts the steps run on this node before this one within the same
+// request (this node being its own de Bruijn candidate); beyond the local
+// depth limit the lookup fails with ResourceExhausted. A lookup that made
+// more forwarding hops (see ctxutil.Hops) than the hop limit fails with
+// Internal (see WithMaxHops).
+func (n *Node) lookupStep(ctx context.Context, target, currentI, kshift domain.ID, wantPred bool, depth int) (*domain.Node, error) {
 	// Abort if context expired
 	if err := ctxutil.CheckContext(ctx); err != nil {
 		return nil, err
 	}
+	if limit := n.localDepthLimit(); depth > limit {
+		n.lgr.Error("FindSuccessorStep: local recursion too deep, aborting lookup",
+			logger.F("target", target.ToHexString(true)), logger.F("depth", depth), logger.F("limit", limit))
+		return nil, status.Error(codes.ResourceExhausted, "lookup exceeded the maximum local recursion depth")
+	}
+	if limit, hops := n.hopLimit(), ctxutil.Hops(ctx); limit > 0 && hops > limit {
+		n.lgr.Error("FindSuccessorStep: too many hops, aborting lookup",
+			logger.F("target", target.ToHexString(true)), logger.F("currentI", currentI.ToHexString(true)),
+			logger.F("kshift", kshift.ToHexString(true)), logger.F("hops", hops), logger.F("limit", limit))
+		return nil, fmt.Errorf("%w (%d)", ErrMaxHops, limit)
+	}
+
+	hop, err := n.nextHop(target, currentI, kshift)
+	if err != nil {
+		return nil, err
+	}
+	self := n.rt.Self()
+	span := trace.SpanFromContext(ctx)
+	if hop.Successor != nil {
+		traceStep(span, hop, "end", -1, 0)
+		if wantPred {
+			return self, nil
+		}
+		return hop.Successor, nil
+	}
+
+	// de Bruijn candidates (all but the last one, the successor), tried in
+	// order or, with WithLookupParallelism, width at a time
+	last := len(hop.Candidates) - 1
+	width := max(n.lookupParallelism, 1)
+	for start := 0; start < last; start += width {
+		batch := hop.Candidates[start:min(start+width, last)]
+		res, won, failed := n.tryCandidates(ctx, batch, start, target, hop, wantPred, depth)
+		tried := start + len(batch)
+		if won >= 0 {
+			traceStep(span, hop, "debruijn", hop.DeBruijnIdx[won], tried)
+			return res, nil
+		}
+		for _, f := range failed {
+			if f.fatal {
+				return nil, f.err // local recursion too deep or too many hops: unwind the whole lookup
+			}
+		}
+		// Abort if the deadline of the step expired or the lookup was canceled:
+		// there is no time left to try another candidate. A candidate that
+		// timed out (its hop budget expired, or a later hop reported a timeout)
+		// while ctx is live is a failed hop like any other, and so is a
+		// Canceled status with a live ctx, which comes from a closing connection.
+		if ctxErr := ctxutil.CheckContext(ctx); ctxErr != nil {
+			f := failed[len(failed)-1]
+			n.lgr.Error("FindSuccessorStep: lookup interrupted by timeout/cancel",
+				logger.F("tryIdx", f.idx), logger.F("addr", hop.Candidates[f.idx].Addr), logger.F("err", f.err))
+			traceStep(span, hop, "interrupted", -1, tried)
+			return nil, ctxErr
+		}
+		for _, f := range failed {
+			n.lgr.Warn("FindSuccessorStep: de Bruijn hop failed, trying previous candidate",
+				logger.F("tryIdx", f.idx), logger.FNode("failedNode", hop.Candidates[f.idx]), logger.F("err", f.err))
+		}
+	}
+
+	// Successor: de Bruijn list empty or all failed, or this node is not
+	// the predecessor of currentI
+	succ := hop.Candidates[last]
+	if hop.Shifted {
+		traceStep(span, hop, "fallback", -1, last+1)
+	} else {
+		traceStep(span, hop, "successor", -1, 1)
+	}
+	if last > 0 {
+		n.lgr.Warn("FindSuccessorStep: de Bruijn failed or empty, falling back to successor",
+			logger.F("target", target.ToHexString(true)), logger.FNode("nextHop", succ))
+	} else {
+		n.lgr.Debug("FindSuccessorStep: forwarding to successor",
+			logger.F("target", target.ToHexString(true)), logger.FNode("nextHop", succ))
+	}
+	cli, err := n.cp.GetFromPool(succ.Addr)
+	if err != nil {
+		n.lgr.Error("FindSuccessorStep: failed to get connection from pool for successor",
+			logger.F("addr", succ.Addr), logger.F("err", err))
+		return nil, status.Error(codes.Internal, "failed to get connection to successor")
+	}
+	res, err := n.forwardStep(ctx, cli, target, hop.CurrentI, hop.KShift, wantPred)
+	if status.Code(err) == codes.Canceled && ctx.Err() == nil {
+		// The connection was closed under the hop: the successor changed
+		// and the old one was released. Route the step again on the
+		// updated routing table (bounded by the local depth limit).
+		n.lgr.Warn("FindSuccessorStep: connection to successor closed, routing the step again",
+			logger.FNode("successor", succ), logger.F("err", err))
+		return n.lookupStep(ctx, target, currentI, kshift, wantPred, depth+1)
+	}
+	return res, err
+}
+
+// candidateResult is the outcome of forwarding a lookup step to one de
+// Bruijn candidate.
+type candidateResult struct {
+	idx   int // index of the candidate in hop.Candidates
+	res   *domain.Node
+	err   error
+	fatal bool // err ends the whole lookup (local recursion too deep, too many hops)
+}
+
+// tryCandidates forwards the lookup step to the de Bruijn candidates of
+// batch, whose first element is hop.Candidates[start]. A single candidate
+// is tried inline; several are queried concurrently, each under its own
+// context derived from ctx, and the first valid answer wins and cancels
+// the others. It returns the answer and the index in hop.Candidates of the
+// winner, or -1 and the failures of all the candidates.
+func (n *Node) tryCandidates(ctx context.Context, batch []*domain.Node, start int, target domain.ID, hop *domain.NextHop, wantPred bool, depth int) (*domain.Node, int, []candidateResult) {
+	if len(batch) == 1 {
+		r := n.tryCandidate(ctx, batch[0], start, target, hop, wantPred, depth)
+		if r.err == nil && r.res != nil {
+			return r.res, start, nil
+		}
+		return nil, -1, []candidateResult{r}
+	}
+
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel() // the losers stop as soon as a winner is found
+	results := make(chan candidateResult, len(batch))
+	for i, d := range batch {
+		go func() {
+			candCtx, candCancel := context.WithCancel(batchCtx)
+			defer candCancel()
+			results <- n.tryCandidate(candCtx, d, start+i, target, hop, wantPred, depth)
+		}()
+	}
+	var failed []candidateResult
+	for range batch {
+		r := <-results
+		if r.err == nil && r.res != nil {
+			return r.res, r.idx, nil
+		}
+		failed = append(failed, r)
+	}
+	return nil, -1, failed
+}
+
+// tryCandidate forwards the lookup step to the de Bruijn candidate d, the
+// idx-th of hop.Candidates, or continues it locally if d is this node.
+func (n *Node) tryCandidate(ctx context.Context, d *domain.Node, idx int, target domain.ID, hop *domain.NextHop, wantPred bool, depth int) candidateResult {
+	n.lgr.Debug("FindSuccessorStep: forwarding to de Bruijn node",
+		logger.F("target", target.ToHexString(true)), logger.FNode("nextHop", d))
+	r := candidateResult{idx: idx}
+	if d.ID.Equal(n.rt.Self().ID) {
+		r.res, r.err = n.lookupStep(ctx, target, hop.CurrentI, hop.KShift, wantPred, depth+1)
+		r.fatal = status.Code(r.err) == codes.ResourceExhausted || ExceededHops(r.err)
+		return r
+	}
+	cli, err := n.cp.GetFromPool(d.Addr)
+	if err != nil {
+		n.lgr.Warn("FindSuccessorStep: failed to get connection from pool",
+			logger.F("tryIdx", idx), logger.F("addr", d.Addr), logger.F("err", err))
+		r.err = err
+		return r
+	}
+	r.res, r.err = n.forwardStep(ctx, cli, target, hop.CurrentI, hop.KShift, wantPred)
+	r.fatal = ExceededHops(r.err)
+	return r
+}
+
+// localDepthLimit returns the maximum depth of the local recursion of a
+// lookup step (see WithMaxLocalDepth): the configured one, or twice the
+// base-k digits of an identifier.
+func (n *Node) localDepthLimit() int {
+	if n.maxLocalDepth > 0 {
+		return n.maxLocalDepth
+	}
+	return 2 * n.idDigits()
+}
+
+// hopSlack is the number of hops the default hop limit grants beyond its
+// per-digit bound: the successor corrections of a digit come in runs of
+// several on the uneven arcs of small rings and of rings still
+// stabilizing.
+const hopSlack = 16
+
+// hopLimit returns the maximum forwarding hops of a lookup (see
+// WithMaxHops). By default a de Bruijn lookup is given three hops per
+// base-k digit of an ID: the de Bruijn hop, the successor correction it
+// needs on average, and one more for the de Bruijn pointers not yet
+// stabilized. A Chord-only one, which walks the ring a successor at a
+// time, is given four times the estimated size of the ring (see
+// EstimateRingSize), but never less than a de Bruijn one, as the estimate
+// of a node with unevenly spaced neighbours can fall well short of the
+// ring. Both limits add hopSlack.
+func (n *Node) hopLimit() int {
+	if n.maxHops > 0 {
+		return n.maxHops
+	}
+	perDigit := 3 * n.idDigits()
+	if !n.deBruijn {
+		return max(4*n.EstimateRingSize(), perDigit) + hopSlack
+	}
+	return perDigit + hopSlack
+}
+
+// ExceededHops reports whether err comes from a lookup aborted by the hop
+// limit of this node (ErrMaxHops) or, through the cause of the failure
+// attached to its status, of a remote one.
+func ExceededHops(err error) bool {
+	if errors.Is(err, ErrMaxHops) {
+		return true
+	}
+	f, ok := domain.FailureFromError(err)
+	return ok && f.Cause == domain.CauseMaxHops
+}
+
+// idDigits returns the number of base-k digits of an identifier.
+func (n *Node) idDigits() int {
+	sp := n.rt.Space()
+	digitBits := bits.Len(uint(sp.GraphGrade)) - 1
+	if digitBits < 1 {
+		digitBits = 1
+	}
+	return (sp.Bits + digitBits - 1) / digitBits
+}
+
+// traceStep records on span the routing decision of a lookup step:
+//   - dht.findsucc.decision: end (target in (self, successor]), debruijn
+//     (forwarded to a de Bruijn node), successor (forwarded to the successor,
+//     de Bruijn routing not applicable), fallback (de Bruijn routing applied
+//     but no de Bruijn node answered: forwarded to the successor) or
+//     interrupted (the lookup expired while trying the candidates);
+//   - dht.findsucc.nextI: the next imaginary node, if de Bruijn routing applied;
+//   - dht.findsucc.debruijn.index: the index in the de Bruijn list of the
+//     node that answered (-1 = none);
+//   - dht.findsucc.candidates.tried: the candidates contacted, the
+//     successor included;
+//   - dht.findsucc.fallback: whether the step fell back to the successor.
+//
+// If the step continues locally (this node is its own de Bruijn candidate),
+// the attributes describe the last local step.
+func traceStep(span trace.Span, hop *domain.NextHop, decision string, deBruijnIdx, tried int) {
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("dht.findsucc.decision", decision),
+		attribute.Int("dht.findsucc.debruijn.index", deBruijnIdx),
+		attribute.Int("dht.findsucc.candidates.tried", tried),
+		attribute.Bool("dht.findsucc.fallback", decision == "fallback"),
+	)
+	if hop.Shifted {
+		span.SetAttributes(telemetry.IdAttributes("dht.findsucc.nextI", hop.CurrentI)...)
+	}
+}
 
+// nextHop computes the next step of a lookup of target at this node,
+// without forwarding it. It is the routing decision shared by the
+// recursive (FindSuccessorStep) and the iterative (NextHopStep) lookups:
+//   - If the target lies in (self, successor], the lookup ends at the successor.
+//   - If currentI ∈ (self, successor] (and de Bruijn routing is enabled),
+//     the imaginary node advances by one digit (nextI = k*currentI + digit)
+//     and the candidates are the de Bruijn nodes preceding nextI, from the
+//     closest to the farthest, followed by the successor as fallback.
+//   - Otherwise the only candidate is the successor, with the state unchanged.
+func (n *Node) nextHop(target, currentI, kshift domain.ID) (*domain.NextHop, error) {
 	self := n.rt.Self()
 	succ := n.rt.FirstSuccessor()
 	// check if the target is in (self, successor]
 	if succ == nil {
 		n.lgr.Error("FindSuccessorStep: routing table not initialized (successor is nil)")
-		return nil, status.Error(codes.Internal, "routing table not initialized")
+		return nil, ErrRoutingNotInitialized
 	}
 	if target.Between(self.ID, succ.ID) {
 		n.lgr.Debug("EndLookup: target in (self, successor], returning successor",
 			logger.F("target", target.ToHexString(true)), logger.FNode("successor", succ))
-		return succ, nil
+		return &domain.NextHop{Successor: succ}, nil
 	}
 
-	// currentI is in (self, successor]: try de Bruijn routing
-	if currentI.Between(self.ID, succ.ID) {
+	// currentI is not in (self, successor], or de Bruijn routing is disabled:
+	// continue at the successor
+	if !n.deBruijn || !currentI.Between(self.ID, succ.ID) {
+		return &domain.NextHop{Candidates: []*domain.Node{succ}, CurrentI: currentI, KShift: kshift}, nil
+	}
 
-		// Compute next digit and shifted target
-		nextDigit, nextKshift, err := n.rt.Space().NextDigitBaseK(kshift)
-		if err != nil {
-			n.lgr.Error("FindSuccessorStep: failed to compute next digit and kshift",
-				logger.F("target", target.ToHexString(true)), logger.F("err", err))
-			return nil, status.Error(codes.Internal, "failed to compute next digit and kshift")
-		}
-		// Compute next imaginary node
-		nextI, err := n.rt.Space().MulKMod(currentI)
-		if err != nil {
-			n.lgr.Error("FindSuccessorStep: failed to compute nextI (MulKMod)",
-				logger.F("target", target.ToHexString(true)), logger.F("err", err))
-			return nil, status.Error(codes.Internal, "failed to compute nextI")
+	// Compute next digit and shifted target
+	nextDigit, nextKshift, err := n.rt.Space().NextDigitBaseK(kshift)
+	if err != nil {
+		n.lgr.Error("FindSuccessorStep: failed to compute next digit and kshift",
+			logger.F("target", target.ToHexString(true)), logger.F("err", err))
+		return nil, status.Error(codes.Internal, "failed to compute next digit and kshift")
+	}
+	// Compute next imaginary node: nextI = k*currentI + nextDigit (mod 2^b)
+	nextI := make(domain.ID, n.rt.Space().ByteLen)
+	if err := n.rt.Space().MulKAddModInto(nextI, currentI, nextDigit); err != nil {
+		n.lgr.Error("FindSuccessorStep: failed to compute nextI (MulKAddMod)",
+			logger.F("target", target.ToHexString(true)), logger.F("err", err))
+		return nil, status.Error(codes.Internal, "failed to compute nextI")
+	}
+	hop := &domain.NextHop{CurrentI: nextI, KShift: nextKshift, Shifted: true}
+
+	window := n.rt.DeBruijnWindow() // de Bruijn pointers, each with its backups
+	if len(window) > 0 {
+		if nextI.Equal(currentI) {
+			n.lgr.Error("FindSuccessorStep: nextI equals currentI, potential infinite loop",
+				logger.F("target", target.ToHexString(true)), logger.F("currentI", currentI.ToHexString(true)), logger.F("nextI", nextI.ToHexString(true)), logger.F("kshift", kshift.ToHexString(true)), logger.F("nextKshift", nextKshift.ToHexString(true)))
+			return nil, status.Error(codes.Internal, "nextI equals currentI, potential infinite loop")
 		}
-		nextI, err = n.rt.Space().AddMod(nextI, n.rt.Space().FromUint64(nextDigit))
-		if err != nil {
-			n.lgr.Error("FindSuccessorStep: failed to compute nextI (AddMod)",
-				logger.F("target", target.ToHexString(true)), logger.F("err", err))
-			return nil, status.Error(codes.Internal, "failed to compute nextI")
+		Bruijn := make([]*domain.Node, len(window))
+		for i, nodes := range window {
+			Bruijn[i] = nodes[0]
 		}
-
-		Bruijn := n.rt.DeBruijnList() // get de Bruijn list
-		if Bruijn != nil && len(Bruijn) > 0 {
-
-			if nextI.Equal(currentI) {
-				n.lgr.Error("FindSuccessorStep: nextI equals currentI, potential infinite loop",
-					logger.F("target", target.ToHexString(true)), logger.F("currentI", currentI.ToHexString(true)), logger.F("nextI", nextI.ToHexString(true)), logger.F("kshift", kshift.ToHexString(true)), logger.F("nextKshift", nextKshift.ToHexString(true)))
-				return nil, status.Error(codes.Internal, "nextI equals currentI, potential infinite loop")
-			}
-
-			// Select de Bruijn next hop
-			index := n.findNextHop(Bruijn, nextI)
-			for i := index; i >= 0; i-- {
-				d := Bruijn[i]
-				if d == nil {
-					continue
+		// de Bruijn next hops, from the closest predecessor of nextI
+		// backwards, each pointer followed by its backups
+		seen := make(map[string]bool)
+		for i := n.findNextHop(Bruijn, nextI); i >= 0; i-- {
+			for _, node := range window[i] {
+				if !seen[node.Addr] {
+					seen[node.Addr] = true
+					hop.Candidates = append(hop.Candidates, node)
+					hop.DeBruijnIdx = append(hop.DeBruijnIdx, i)
 				}
-				n.lgr.Debug("FindSuccessorStep: forwarding to de Bruijn node",
-					logger.F("target", target.ToHexString(true)), logger.FNode("nextHop", d))
-				var res *domain.Node
-				var err error
-				if d.ID.Equal(self.ID) {
-					res, err = n.FindSuccessorStep(ctx, target, nextI, nextKshift)
-				} else {
-					cli, err := n.cp.GetFromPool(d.Addr)
-					if err != nil {
-						n.lgr.Warn("FindSuccessorStep: failed to get connection from pool",
-							logger.F("tryIdx", i), logger.F("addr", d.Addr), logger.F("err", err))
-						continue
-					}
-					res, err = client.FindSuccessorStep(ctx, cli, n.Space(), target, nextI, nextKshift)
-				}
-
-				if err == nil && res != nil {
-					return res, nil
-				}
-				// Abort if context expired
-				if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || ctx.Err() != nil {
-					n.lgr.Error("FindSuccessorStep: lookup interrupted by timeout/cancel",
-						logger.F("tryIdx", i), logger.F("addr", d.Addr), logger.F("err", err))
-					return nil, ctx.Err()
-				}
-				n.lgr.Warn("FindSuccessorStep: de Bruijn hop failed, trying previous candidate",
-					logger.F("tryIdx", i), logger.FNode("failedNode", d), logger.F("err", err))
 			}
 		}
+	}
+	hop.Candidates = append(hop.Candidates, succ)
+	return hop, nil
+}
 
-		// Fallback: de Bruijn list empty or all failed: use successor
-		n.lgr.Warn("FindSuccessorStep: de Bruijn failed or empty, falling back to successor",
-			logger.F("target", target.ToHexString(true)), logger.FNode("nextHop", succ))
-		cli, err := n.cp.GetFromPool(succ.Addr)
-		if err != nil {
-			n.lgr.Error("FindSuccessorStep: failed to get connection from pool (successor)",
-				logger.F("addr", succ.Addr), logger.F("err", err))
-			return nil, status.Error(codes.Internal, "failed to get connection to successor")
+// forwardStep sends a FindSuccessor (or, with wantPred, FindPredecessor)
+// step to the next hop under a shortened deadline (see ctxutil.HopContext),
+// so that part of the caller's budget is always left to report the outcome
+// back along the recursion. The step waits for a slot of the outbound
+// budget (see WithMaxOutbound) within the hop budget.
+//
+// Errors:
+//   - DeadlineExceeded if the remaining time is below the minimum hop budget
+//     (the hop is not issued), if the hop budget expired (while waiting for
+//     an outbound slot as well), or if a later hop
+//     reported a timeout; the message names the target and the hop.
+//   - Canceled if the lookup was canceled.
+//   - The RPC error otherwise.
+func (n *Node) forwardStep(ctx context.Context, cli dhtv1.DHTClient, target, currentI, kshift domain.ID, wantPred bool) (*domain.Node, error) {
+	hop := ctxutil.Hops(ctx) + 1
+	hopCtx, cancel, err := ctxutil.HopContext(ctx, n.hopReserve, n.minHopBudget)
+	if err != nil {
+		n.lgr.Debug("FindSuccessorStep: not enough time left to forward the lookup",
+			logger.F("target", target.ToHexString(true)), logger.F("hop", hop), logger.F("err", err))
+		if status.Code(err) == codes.DeadlineExceeded {
+			return nil, status.Errorf(codes.DeadlineExceeded, "lookup of %s: deadline too close to forward hop %d",
+				target.ToHexString(true), hop)
 		}
-		return client.FindSuccessorStep(ctx, cli, n.Space(), target, nextI, nextKshift)
+		return nil, err
 	}
-
-	// Default: forward to successor
-	n.lgr.Debug("FindSuccessorStep: forwarding to successor",
-		logger.F("target", target.ToHexString(true)), logger.FNode("nextHop", succ))
-	cli, err := n.cp.GetFromPool(succ.Addr)
+	defer cancel()
+	release, err := n.acquireOutbound(hopCtx)
 	if err != nil {
-		n.lgr.Error("FindSuccessorStep: failed to get connection from pool for successor",
-			logger.F("addr", succ.Addr), logger.F("err", err))
-		return nil, status.Error(codes.Internal, "failed to get connection to successor")
+		if ctxErr := ctxutil.CheckContext(ctx); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, status.Errorf(codes.DeadlineExceeded, "lookup of %s: budget of hop %d exhausted before an outbound slot was free: %v",
+			target.ToHexString(true), hop, err)
+	}
+	defer release()
+	hopCtx = ctxutil.WithHops(hopCtx, hop)
+	step := client.FindSuccessorStep
+	if wantPred {
+		step = client.FindPredecessorStep
+	}
+	res, err := step(hopCtx, cli, n.Space(), target, currentI, kshift)
+	if err == nil {
+		return res, nil
+	}
+	if ctxErr := ctxutil.CheckContext(ctx); ctxErr != nil {
+		return nil, ctxErr
 	}
-	return client.FindSuccessorStep(ctx, cli, n.Space(), target, currentI, kshift)
+	if errors.Is(err, client.ErrTimeout) || hopCtx.Err() != nil {
+		return nil, status.Errorf(codes.DeadlineExceeded, "lookup of %s: budget of hop %d exhausted: %v",
+			target.ToHexString(true), hop, err)
+	}
+	return nil, err
 }
 
 // Self returns the local node information.
@@ -280,6 +775,13 @@ func (n *Node) Predecessor() *domain.Node {
 	return n.rt.GetPredecessor()
 }
 
+// PredecessorLastSeen returns the last time the current predecessor was
+// known to be alive (set, notified us, or answered a ping), or the zero
+// time if no predecessor is set.
+func (n *Node) PredecessorLastSeen() time.Time {
+	return n.rt.PredecessorLastSeen()
+}
+
 // SuccessorList returns the current successor list of this node.
 //
 // The successor list provides fault tolerance by keeping track of
@@ -294,6 +796,29 @@ func (n *Node) SuccessorList() []*domain.Node {
 	return n.rt.SuccessorList()
 }
 
+// SuccessorListSize returns the current capacity of the successor list,
+// which changes over time with WithAdaptiveSuccessorList.
+func (n *Node) SuccessorListSize() int {
+	return n.rt.SuccessorListSize()
+}
+
+// Uptime returns the time elapsed since the node was created.
+func (n *Node) Uptime() time.Duration {
+	return time.Since(n.startedAt)
+}
+
+// MaxStoredKeys returns the maximum number of resources the local storage
+// holds (see storage.WithMaxKeys), 0 if unlimited.
+func (n *Node) MaxStoredKeys() int {
+	return n.s.MaxKeys()
+}
+
+// PooledConnections returns the number of connections currently open in
+// the client pool of the node.
+func (n *Node) PooledConnections() int {
+	return n.cp.Size()
+}
+
 // DeBruijnList returns the current de Bruijn list of this node.
 //
 // Returns:
@@ -317,9 +842,12 @@ func (n *Node) DeBruijnList() []*domain.Node {
 //
 // Behavior:
 //   - Ignores nil or self notifications.
+//   - If p is the current predecessor, only refreshes its last-seen time
+//     (a repeated Notify is a liveness signal; no pointer or pool change).
 //   - If no predecessor is set, or if p ∈ (pred, self), updates the predecessor.
 //   - On update: AddRef(p), SetPredecessor(p), Release(old pred),
-//     and transfer resources in (pred, p] to p.
+//     and copy resources in (pred, p] to p (local copies are dropped later
+//     by resourceRepair, see transferResourcesAsync).
 func (n *Node) Notify(p *domain.Node) {
 	self := n.rt.Self()
 	// check if the notifier is nil or self
@@ -327,9 +855,19 @@ func (n *Node) Notify(p *domain.Node) {
 		return
 	}
 
+	n.predMu.Lock()
+	defer n.predMu.Unlock()
+
 	// get current predecessor
 	pred := n.rt.GetPredecessor()
 
+	// Notify from the current predecessor: record the liveness signal
+	if pred != nil && p.ID.Equal(pred.ID) {
+		n.rt.TouchPredecessor(p.ID)
+		n.lgr.Debug("Notify: predecessor confirmed", logger.FNode("predecessor", pred))
+		return
+	}
+
 	// Update if no predecessor is set, or p is a better candidate
 	if pred == nil || p.ID.Between(pred.ID, self.ID) {
 		// addRef new predecessor
@@ -340,6 +878,7 @@ func (n *Node) Notify(p *domain.Node) {
 
 		// Update routing table
 		n.rt.SetPredecessor(p)
+		n.requestPendingRepair()
 
 		// Release old predecessor
 		if pred != nil {
@@ -349,9 +888,15 @@ func (n *Node) Notify(p *domain.Node) {
 			}
 		}
 
-		// Asynchronous resource transfer: (self.ID, p.ID]
-		resources := n.s.Between(self.ID, p.ID)
-		if len(resources) > 0 {
+		// Asynchronous resource transfer: (pred, p], the part of our range
+		// p now owns (replica copies of other ranges stay where they are),
+		// unless p is a virtual node sharing our storage
+		from := self.ID
+		if pred != nil {
+			from = pred.ID
+		}
+		resources := n.s.Between(from, p.ID)
+		if len(resources) > 0 && !n.sibling(p) {
 			go n.transferResourcesAsync(p, resources)
 		}
 		// log update
@@ -361,6 +906,13 @@ func (n *Node) Notify(p *domain.Node) {
 	}
 }
 
+// transferResourcesAsync copies to the new predecessor p the resources it
+// is now responsible for.
+//
+// The local copies are not deleted here: until the rest of the ring has
+// learned about p (i.e. p's own predecessor has updated its successor),
+// requests for these keys are still routed to this node. The copies are
+// removed by resourceRepair once a fresh lookup confirms that p owns them.
 func (n *Node) transferResourcesAsync(p *domain.Node, resources []domain.Resource) {
 	ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
 	defer cancel()
@@ -379,19 +931,6 @@ func (n *Node) transferResourcesAsync(p *domain.Node, resources []domain.Resourc
 			logger.F("attempted", len(resources)))
 		return
 	}
-	// Remove successfully transferred resources from local storage
-	success := make(map[string]struct{}, len(resources))
-	for _, r := range resources {
-		success[r.Key.ToHexString(false)] = struct{}{}
-	}
-	for _, r := range failed {
-		delete(success, r.Key.ToHexString(false))
-	}
-	for _, r := range resources {
-		if _, ok := success[r.Key.ToHexString(false)]; ok {
-			_ = n.s.Delete(r.Key)
-		}
-	}
 	if len(failed) > 0 {
 		n.lgr.Warn("transferResourcesAsync: some resources failed to transfer",
 			logger.FNode("predecessor", p),
@@ -406,121 +945,252 @@ func (n *Node) transferResourcesAsync(p *domain.Node, resources []domain.Resourc
 //
 // Behavior:
 //   - Validates the context (propagating client timeouts/cancellations).
-//   - Locates the successor node responsible for the resource key.
+//   - Locates the successor node responsible for the resource key, retrying
+//     a failed lookup if configured (see WithLookupRetry).
 //   - If this node is the successor, stores the resource locally.
-//   - Otherwise, forwards the request to the responsible successor.
+//   - Otherwise, forwards the request to the responsible successor. The
+//     lookup leaves part of the deadline for this (see WithOwnerReserve).
+//   - The expiry of res (see domain.Resource.WithTTL), if any, is stored
+//     with it and travels with every copy and transfer of the resource.
+//   - This node is recorded as the origin of res (see domain.Resource.Origin)
+//     unless res already carries one. Any version res carries is dropped:
+//     the responsible node assigns it one above the version it stores (see
+//     domain.Resource.Version), so the Put is never ignored as stale.
 //
 // Errors:
 //   - Propagates context errors (canceled/deadline exceeded).
+//   - DeadlineExceeded if the lookup left no time to contact the successor.
 //   - Returns wrapped errors for lookup failures, missing successors,
 //     connection pool issues, or store failures.
-func (n *Node) Put(ctx context.Context, res domain.Resource) error {
+func (n *Node) Put(ctx context.Context, res domain.Resource) (err error) {
+	defer func(start time.Time) { telemetry.RecordStore(ctx, "put", start, err) }(time.Now())
+	if err := n.beginOp(ctx); err != nil {
+		return err
+	}
+	defer n.ops.Done()
+	return n.put(ctx, res, domain.Condition{}, 0)
+}
+
+// PutIf is like Put, but the responsible node stores res only if cond
+// holds for the resource it currently stores under the same key, checked
+// atomically with the write (see storage.Store.PutConditional). If it does
+// not, nothing is stored and the returned error wraps
+// domain.ErrPreconditionFailed. Replicas are updated only after a
+// successful write.
+func (n *Node) PutIf(ctx context.Context, res domain.Resource, cond domain.Condition) error {
+	if err := n.beginOp(ctx); err != nil {
+		return err
+	}
+	defer n.ops.Done()
+	return n.put(ctx, res, cond, 0)
+}
+
+// put implements Put and PutIf, at its attempt-th try. If the owner cannot
+// be reached, the Put runs again with a new lookup as allowed by
+// retryOwner; only the first try may take the owner from the lookup cache,
+// and a cached owner that is no longer responsible for the key is looked up
+// again (see staleCachedOwner).
+func (n *Node) put(ctx context.Context, res domain.Resource, cond domain.Condition, attempt int) error {
 	// Abort if context already canceled/expired
 	if err := ctxutil.CheckContext(ctx); err != nil {
 		return err
 	}
+	if len(res.Origin) == 0 {
+		res.Origin = n.rt.Self().ID
+	}
+	res.Version = 0 // a new write: the owner assigns its version
 	// Find the successor node responsible for this key
-	succ, err := n.FindSuccessorInit(ctx, res.Key)
+	succ, cached, err := n.lookupOwner(ctx, res.Key, attempt == 0)
 	if err != nil {
-		return fmt.Errorf("put: failed to find successor for key %s: %w", res.RawKey, err)
+		return n.Failure(domain.StageRouting, fmt.Errorf("put: key %s: %w: %w", res.RawKey, ErrLookupFailed, err))
 	}
 	if succ == nil {
-		return fmt.Errorf("put: no successor found for key %s", res.RawKey)
+		return fmt.Errorf("put: key %s: %w", res.RawKey, ErrNoSuccessor)
 	}
 
 	// If this node is the successor, store locally
 	if succ.ID.Equal(n.rt.Self().ID) {
-		if err := n.StoreLocal(ctx, res); err != nil {
+		version, err := n.StoreLocalIf(ctx, res, cond)
+		if err != nil {
+			if errors.Is(err, domain.ErrPreconditionFailed) {
+				return fmt.Errorf("put: key %s: %w", res.RawKey, err)
+			}
+			if errors.Is(err, ErrNoPredecessor) && n.retryOwner(ctx, succ, cached, attempt) {
+				return n.put(ctx, res, cond, attempt+1)
+			}
+			if n.staleCachedOwner(succ, cached, err, false) {
+				return n.put(ctx, res, cond, attempt+1)
+			}
 			n.lgr.Error("Put: failed to store resource locally",
 				logger.F("key", res.RawKey), logger.F("err", err))
-			return fmt.Errorf("put: failed to store resource locally: %w", err)
+			return n.Failure(domain.StageStorage, fmt.Errorf("put: failed to store resource locally: %w", err))
 		}
 		n.lgr.Info("Put: resource stored locally",
 			logger.F("key", res.RawKey))
+		res.Version = version
+		n.replicate(ctx, succ, res)
 		return nil
 	}
 
 	// Otherwise, forward the resource to the successor
-	sres := []domain.Resource{res}
+	if err := n.ownerBudget(ctx, "put", res.RawKey, succ); err != nil {
+		return n.Failure(domain.StageTransfer, err)
+	}
 	cli, err := n.cp.GetFromPool(succ.Addr)
 	var econn *grpc.ClientConn
 	if err != nil {
 		// create an ephimeral connection
 		cli, econn, err = n.cp.DialEphemeral(succ.Addr)
 		if err != nil {
+			if n.retryOwner(ctx, succ, cached, attempt) {
+				return n.put(ctx, res, cond, attempt+1)
+			}
 			n.lgr.Error("Put: failed to get connection to successor",
 				logger.F("key", res.RawKey), logger.FNode("successor", succ), logger.F("err", err))
-			return fmt.Errorf("put: failed to get connection to successor %s: %w", succ.Addr, err)
+			return n.Failure(domain.StageTransfer, fmt.Errorf("put: failed to get connection to successor %s: %w", succ.Addr, err))
 		}
 		defer econn.Close()
 	}
-	if _, err := client.StoreRemote(ctx, cli, sres); err != nil {
+	release, err := n.acquireOutbound(ctx)
+	if err != nil {
+		return n.Failure(domain.StageTransfer, fmt.Errorf("put: key %s: %w", res.RawKey, err))
+	}
+	version, err := client.StoreIf(ctx, cli, res, cond)
+	release()
+	if err != nil {
+		if errors.Is(err, domain.ErrPreconditionFailed) {
+			return fmt.Errorf("put: key %s: %w", res.RawKey, err)
+		}
+		if ownerConnFailed(err) && n.retryOwner(ctx, succ, cached, attempt) {
+			return n.put(ctx, res, cond, attempt+1)
+		}
+		if n.staleCachedOwner(succ, cached, err, false) {
+			return n.put(ctx, res, cond, attempt+1)
+		}
 		n.lgr.Error("Put: failed to store resource at successor",
 			logger.F("key", res.RawKey), logger.FNode("successor", succ), logger.F("err", err))
-		return fmt.Errorf("put: failed to store resource at successor %s: %w", succ.Addr, err)
+		return n.Failure(domain.StageTransfer, fmt.Errorf("put: failed to store resource at successor %s: %w", succ.Addr, err))
 	}
 	// Success
 	n.lgr.Info("Put: resource stored at successor",
 		logger.F("key", res.RawKey), logger.FNode("successor", succ))
+	res.Version = version
+	n.replicate(ctx, succ, res)
 	return nil
 }
 
 // Get retrieves a resource from the DHT on behalf of an external client.
-// The node computes the ID of the key, finds the successor responsible for it,
-// and either fetches the resource locally or forwards the request to the
-// successor node.
+// The node computes the ID of the key, finds the successor responsible for it
+// (retrying a failed lookup if configured, see WithLookupRetry), and either
+// fetches the resource locally or forwards the request to the successor node.
 //
 // Returns:
 //   - *domain.Resource if found
 //   - status.Error(codes.NotFound, ...) if the resource does not exist
 //   - error in case of routing or RPC issues
-func (n *Node) Get(ctx context.Context, id domain.ID) (*domain.Resource, error) {
+func (n *Node) Get(ctx context.Context, id domain.ID) (res *domain.Resource, err error) {
+	defer func(start time.Time) { telemetry.RecordStore(ctx, "get", start, err) }(time.Now())
+	if err := n.beginOp(ctx); err != nil {
+		return nil, err
+	}
+	defer n.ops.Done()
+	return n.get(ctx, id, 0)
+}
+
+// get implements Get, at its attempt-th try. If the owner cannot be
+// reached, the Get runs again with a new lookup as allowed by retryOwner
+// before falling back to the replicas; only the first try may take the
+// owner from the lookup cache, and a cached owner that does not have the
+// key is looked up again (see staleCachedOwner). With read repair, a miss on the owner is also
+// retried on the replicas (see repairFromReplicas).
+func (n *Node) get(ctx context.Context, id domain.ID, attempt int) (*domain.Resource, error) {
 	// Abort if context already canceled/expired
 	if err := ctxutil.CheckContext(ctx); err != nil {
 		return nil, err
 	}
 
 	// Find the successor node responsible for this key
-	succ, err := n.FindSuccessorInit(ctx, id) // is used the context from client
+	succ, cached, err := n.lookupOwner(ctx, id, attempt == 0) // is used the context from client
 	if err != nil {
-		return nil, fmt.Errorf("get: failed to find successor for key %s: %w", id.ToHexString(true), err)
+		return nil, n.Failure(domain.StageRouting, fmt.Errorf("get: key %s: %w: %w", id.ToHexString(true), ErrLookupFailed, err))
 	}
 	if succ == nil {
-		return nil, fmt.Errorf("get: no successor found for key %s", id.ToHexString(true))
+		return nil, fmt.Errorf("get: key %s: %w", id.ToHexString(true), ErrNoSuccessor)
 	}
 
 	// If this node is the successor, retrieve locally
 	if succ.ID.Equal(n.rt.Self().ID) {
 		res, err := n.RetrieveLocal(id)
+		if n.staleCachedOwner(succ, cached, err, true) {
+			return n.get(ctx, id, attempt+1)
+		}
 		if err != nil {
 			if errors.Is(err, domain.ErrResourceNotFound) {
+				if rres, ok := n.repairFromReplicas(ctx, succ, id); ok {
+					return rres, nil
+				}
 				return nil, status.Error(codes.NotFound, "key not found")
 			}
+			if errors.Is(err, domain.ErrResourceCorrupted) {
+				n.lgr.Error("Get: stored resource is corrupted",
+					logger.F("key", id.ToHexString(true)))
+				return nil, n.Failure(domain.StageStorage, status.Error(codes.DataLoss, "resource corrupted"))
+			}
 			n.lgr.Error("Get: failed to retrieve resource locally",
 				logger.F("key", id.ToHexString(true)), logger.F("err", err))
-			return nil, fmt.Errorf("get: failed to retrieve resource locally: %w", err)
+			return nil, n.Failure(domain.StageStorage, fmt.Errorf("get: failed to retrieve resource locally: %w", err))
 		}
 		return &res, nil
 	}
 
 	// Otherwise, forward the request to the successor
+	if err := n.ownerBudget(ctx, "get", id.ToHexString(true), succ); err != nil {
+		return nil, n.Failure(domain.StageTransfer, err)
+	}
 	var econn *grpc.ClientConn
 	cli, err := n.cp.GetFromPool(succ.Addr)
 	if err != nil {
 		// fallback: create ephemeral connection
 		cli, econn, err = n.cp.DialEphemeral(succ.Addr)
 		if err != nil {
+			if n.retryOwner(ctx, succ, cached, attempt) {
+				return n.get(ctx, id, attempt+1)
+			}
 			n.lgr.Error("Get: failed to get connection to successor",
 				logger.F("key", id.ToHexString(true)), logger.FNode("successor", succ), logger.F("err", err))
-			return nil, fmt.Errorf("get: failed to get connection to successor %s: %w", succ.Addr, err)
+			if res, rerr := n.retrieveFromReplicas(ctx, succ, id); rerr == nil {
+				return res, nil
+			}
+			return nil, n.Failure(domain.StageTransfer, fmt.Errorf("get: failed to get connection to successor %s: %w", succ.Addr, err))
 		}
 		defer econn.Close()
 	}
+	release, err := n.acquireOutbound(ctx)
+	if err != nil {
+		return nil, n.Failure(domain.StageTransfer, fmt.Errorf("get: key %s: %w", id.ToHexString(true), err))
+	}
 	res, err := client.RetrieveRemote(ctx, cli, n.Space(), id)
+	release()
+	if n.staleCachedOwner(succ, cached, err, true) {
+		return n.get(ctx, id, attempt+1)
+	}
+	if status.Code(err) == codes.NotFound {
+		if rres, ok := n.repairFromReplicas(ctx, succ, id); ok {
+			return rres, nil
+		}
+	}
 	if err != nil {
+		if ownerConnFailed(err) && n.retryOwner(ctx, succ, cached, attempt) {
+			return n.get(ctx, id, attempt+1)
+		}
 		n.lgr.Error("Get: failed to retrieve resource from successor",
 			logger.F("key", id.ToHexString(true)), logger.FNode("successor", succ), logger.F("err", err))
-		return nil, fmt.Errorf("get: failed to retrieve resource from successor %s: %w", succ.Addr, err)
+		if ownerUnreachable(err) {
+			if res, rerr := n.retrieveFromReplicas(ctx, succ, id); rerr == nil {
+				return res, nil
+			}
+		}
+		return nil, n.opError(domain.StageTransfer, fmt.Errorf("get: failed to retrieve resource from successor %s: %w", succ.Addr, err))
 	}
 
 	// Success
@@ -533,27 +1203,43 @@ func (n *Node) Get(ctx context.Context, id domain.ID) (*domain.Resource, error)
 //
 // Behavior:
 //   - Validates the context.
-//   - Locates the successor responsible for the given key.
+//   - Locates the successor responsible for the given key, retrying a failed
+//     lookup if configured (see WithLookupRetry).
 //   - If this node is the successor, deletes the resource locally.
-//   - Otherwise, forwards the request to the successor.
+//   - Otherwise, forwards the request to the successor, resolving it again
+//     if it cannot be reached (see WithOwnerRetries). The lookup leaves part
+//     of the deadline for this (see WithOwnerReserve).
 //
 // Returns:
 //   - nil if the resource was deleted successfully.
 //   - status.Error(codes.NotFound, ...) if the resource does not exist.
 //   - error for routing or RPC failures.
-func (n *Node) Delete(ctx context.Context, id domain.ID) error {
+func (n *Node) Delete(ctx context.Context, id domain.ID) (err error) {
+	defer func(start time.Time) { telemetry.RecordStore(ctx, "delete", start, err) }(time.Now())
+	// Abort if context already canceled/expired or the node is stopping
+	if err := n.beginOp(ctx); err != nil {
+		return err
+	}
+	defer n.ops.Done()
+	return n.delete(ctx, id, 0)
+}
+
+// delete implements Delete, at its attempt-th try. If the owner cannot be
+// reached, the Delete runs again with a new lookup as allowed by
+// retryOwner. The lookup cache is never used.
+func (n *Node) delete(ctx context.Context, id domain.ID, attempt int) error {
 	// Abort if context already canceled/expired
 	if err := ctxutil.CheckContext(ctx); err != nil {
 		return err
 	}
 
 	// Find successor
-	succ, err := n.FindSuccessorInit(ctx, id)
+	succ, _, err := n.lookupOwner(ctx, id, false)
 	if err != nil {
-		return fmt.Errorf("delete: failed to find successor for key %s: %w", id.ToHexString(true), err)
+		return n.Failure(domain.StageRouting, fmt.Errorf("delete: key %s: %w: %w", id.ToHexString(true), ErrLookupFailed, err))
 	}
 	if succ == nil {
-		return fmt.Errorf("delete: no successor found for key %s", id.ToHexString(true))
+		return fmt.Errorf("delete: key %s: %w", id.ToHexString(true), ErrNoSuccessor)
 	}
 
 	// If this node is the successor, delete locally
@@ -561,58 +1247,540 @@ func (n *Node) Delete(ctx context.Context, id domain.ID) error {
 		if err := n.RemoveLocal(id); err != nil {
 			n.lgr.Error("Delete: failed to delete resource locally",
 				logger.F("key", id.ToHexString(true)), logger.F("err", err))
-			return fmt.Errorf("delete: failed to delete resource locally: %w", err)
+			return n.opError(domain.StageStorage, fmt.Errorf("delete: failed to delete resource locally: %w", err))
 		}
 		n.lgr.Info("Delete: resource deleted locally",
 			logger.F("key", id.ToHexString(true)))
+		n.removeReplicas(ctx, succ, id)
 		return nil
 	}
 	// Otherwise, forward the request to the successor
+	if err := n.ownerBudget(ctx, "delete", id.ToHexString(true), succ); err != nil {
+		return n.Failure(domain.StageTransfer, err)
+	}
 	var econn *grpc.ClientConn
 	cli, err := n.cp.GetFromPool(succ.Addr)
 	if err != nil {
 		// fallback: create ephemeral connection
 		cli, econn, err = n.cp.DialEphemeral(succ.Addr)
 		if err != nil {
+			if n.retryOwner(ctx, succ, false, attempt) {
+				return n.delete(ctx, id, attempt+1)
+			}
 			n.lgr.Error("Delete: failed to get connection to successor",
 				logger.F("key", id.ToHexString(true)), logger.FNode("successor", succ), logger.F("err", err))
-			return fmt.Errorf("delete: failed to get connection to successor %s: %w", succ.Addr, err)
+			return n.Failure(domain.StageTransfer, fmt.Errorf("delete: failed to get connection to successor %s: %w", succ.Addr, err))
 		}
 		defer econn.Close()
 	}
-	if err := client.RemoveRemote(ctx, cli, id); err != nil {
+	release, err := n.acquireOutbound(ctx)
+	if err != nil {
+		return n.Failure(domain.StageTransfer, fmt.Errorf("delete: key %s: %w", id.ToHexString(true), err))
+	}
+	err = client.RemoveRemote(ctx, cli, id)
+	release()
+	if err != nil {
+		if ownerConnFailed(err) && n.retryOwner(ctx, succ, false, attempt) {
+			return n.delete(ctx, id, attempt+1)
+		}
 		n.lgr.Error("Delete: failed to delete resource at successor",
 			logger.F("key", id.ToHexString(true)), logger.FNode("successor", succ), logger.F("err", err))
-		return fmt.Errorf("delete: failed to delete resource at successor %s: %w", succ.Addr, err)
+		return n.opError(domain.StageTransfer, fmt.Errorf("delete: failed to delete resource at successor %s: %w", succ.Addr, err))
 	}
 	n.lgr.Info("Delete: resource deleted at successor",
 		logger.F("key", id.ToHexString(true)), logger.FNode("successor", succ))
+	n.removeReplicas(ctx, succ, id)
 	return nil
 }
 
+// DeleteBatch removes several resources from the DHT on behalf of an
+// external client, with one round trip per owner instead of one per key.
+//
+// Behavior:
+//   - Locates the successor responsible for each key (with the lookup
+//     retries of WithLookupRetry).
+//   - Groups the keys by successor and removes each group with a single
+//     RemoveBatch call (locally if this node is the successor).
+//   - Removes the replicas of each group as well (best-effort).
+//
+// Returns one error per key, in order:
+//   - nil if the resource was deleted.
+//   - an error wrapping domain.ErrResourceNotFound if it did not exist.
+//   - a *domain.Failure for routing or RPC failures, which only affect
+//     the keys being routed to (or stored on) the failing node.
+func (n *Node) DeleteBatch(ctx context.Context, ids []domain.ID) []error {
+	errs := make([]error, len(ids))
+	// Abort if context already canceled/expired or the node is stopping
+	if err := n.beginOp(ctx); err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	defer n.ops.Done()
+
+	groups := n.groupByOwner(ctx, "deletebatch", ids, errs)
+
+	// One batch per successor
+	for _, g := range groups {
+		keys := make([]domain.ID, len(g.idx))
+		for j, i := range g.idx {
+			keys[j] = ids[i]
+		}
+		removed, err := n.removeBatchAt(ctx, g.succ, keys)
+		if err != nil {
+			n.lgr.Error("DeleteBatch: failed to delete resources at successor",
+				logger.F("keys", len(keys)), logger.FNode("successor", g.succ), logger.F("err", err))
+			ferr := n.Failure(domain.StageTransfer, fmt.Errorf("deletebatch: failed to delete resources at successor %s: %w", g.succ.Addr, err))
+			for _, i := range g.idx {
+				errs[i] = ferr
+			}
+			continue
+		}
+		for j, i := range g.idx {
+			if !removed[j] {
+				errs[i] = fmt.Errorf("deletebatch: key %s: %w", ids[i].ToHexString(true), domain.ErrResourceNotFound)
+			}
+		}
+		n.lgr.Info("DeleteBatch: resources deleted at successor",
+			logger.F("keys", len(keys)), logger.FNode("successor", g.succ))
+		n.removeReplicas(ctx, g.succ, keys...)
+	}
+	return errs
+}
+
+// DeleteRange removes from the DHT every resource with key in (from, to]
+// on behalf of an external client. The range follows domain.ID.Between:
+// it wraps around zero if from > to and covers the whole ring if from ==
+// to.
+//
+// Behavior:
+//   - Locates the owner of from (with the lookup retries of
+//     WithLookupRetry), the first node whose range intersects (from, to].
+//   - Walks the ring from there through the successor pointers, sending
+//     each node a single RemoveRange call (locally if this node is one of
+//     them), up to the owner of to and R-1 nodes beyond it, so that the
+//     replica copies of the range are removed as well.
+//   - Stops at the first node that cannot be reached.
+//
+// Returns the number of resources removed, counting each replica copy,
+// and a *domain.Failure for routing or RPC failures, in which case the
+// resources removed so far are counted.
+func (n *Node) DeleteRange(ctx context.Context, from, to domain.ID) (int, error) {
+	// Abort if context already canceled/expired or the node is stopping
+	if err := n.beginOp(ctx); err != nil {
+		return 0, err
+	}
+	defer n.ops.Done()
+
+	first, err := n.findSuccessorRetry(ctx, from)
+	if err != nil {
+		return 0, n.Failure(domain.StageRouting, fmt.Errorf("deleterange: %s: %w: %w", from.ToHexString(true), ErrLookupFailed, err))
+	}
+	if first == nil {
+		return 0, fmt.Errorf("deleterange: %s: %w", from.ToHexString(true), ErrNoSuccessor)
+	}
+
+	whole := from.Equal(to)
+	total, remaining := 0, -1 // nodes still to visit past the owner of to (-1 = not reached yet)
+	visited := make(map[string]bool)
+	lo := from // the range of cur starts after lo
+	for cur := first; !visited[cur.Addr]; {
+		visited[cur.Addr] = true
+		removed, err := n.removeRangeAt(ctx, cur, from, to)
+		if err != nil {
+			n.lgr.Error("DeleteRange: failed to delete resources at node",
+				logger.FNode("node", cur), logger.F("removed", total), logger.F("err", err))
+			return total, n.Failure(domain.StageTransfer, fmt.Errorf("deleterange: failed to delete resources at %s: %w", cur.Addr, err))
+		}
+		total += removed
+		if remaining > 0 {
+			remaining--
+		} else if remaining < 0 && !whole && to.Between(lo, cur.ID) {
+			remaining = n.replicas - 1 // cur owns to
+		}
+		if remaining == 0 {
+			break
+		}
+
+		// Move to the successor of cur
+		var succs []*domain.Node
+		if cur.ID.Equal(n.rt.Self().ID) {
+			succs = n.rt.SuccessorList()
+		} else if succs, err = n.remoteSuccessorList(cur); err != nil {
+			n.lgr.Error("DeleteRange: failed to get the successor of node",
+				logger.FNode("node", cur), logger.F("removed", total), logger.F("err", err))
+			return total, n.Failure(domain.StageRouting, fmt.Errorf("deleterange: failed to get the successor of %s: %w", cur.Addr, err))
+		}
+		if len(succs) == 0 || succs[0] == nil {
+			break // single node
+		}
+		lo, cur = cur.ID, succs[0]
+	}
+	n.lgr.Info("DeleteRange: resources deleted",
+		logger.F("from", from.ToHexString(true)), logger.F("to", to.ToHexString(true)),
+		logger.F("removed", total), logger.F("nodes", len(visited)))
+	return total, nil
+}
+
+// PutBatch stores several resources in the DHT on behalf of an external
+// client, with one round trip per owner instead of one per key.
+//
+// Behavior:
+//   - Locates the successor responsible for each key (with the lookup
+//     retries of WithLookupRetry).
+//   - Groups the resources by successor and sends each group over a single
+//     Store stream (locally if this node is the successor).
+//   - Replicates the resources of each group that were stored (best-effort,
+//     see WithReplicas).
+//   - Records this node as the origin of the resources without one and
+//     lets their owner version them, as Put.
+//
+// Returns one error per resource, in order: nil if it was stored, or a
+// *domain.Failure for routing, RPC or storage failures. A connection
+// failure affects every resource of the group sent to the failing node, a
+// storage failure (e.g. a full quota) only the resources it rejected.
+func (n *Node) PutBatch(ctx context.Context, resources []domain.Resource) []error {
+	errs := make([]error, len(resources))
+	// Abort if context already canceled/expired or the node is stopping
+	if err := n.beginOp(ctx); err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	defer n.ops.Done()
+
+	ids := make([]domain.ID, len(resources))
+	for i, res := range resources {
+		ids[i] = res.Key
+	}
+	groups := n.groupByOwner(ctx, "putbatch", ids, errs)
+
+	// One stream per successor
+	for _, g := range groups {
+		batch := make([]domain.Resource, len(g.idx))
+		for j, i := range g.idx {
+			batch[j] = resources[i]
+			if len(batch[j].Origin) == 0 {
+				batch[j].Origin = n.rt.Self().ID
+			}
+			batch[j].Version = 0 // a new write: the owner assigns its version
+		}
+		stage := domain.StageTransfer
+		if g.succ.ID.Equal(n.rt.Self().ID) {
+			stage = domain.StageStorage
+		}
+		stored := make([]domain.Resource, 0, len(batch))
+		var lastErr error
+		for j, err := range n.storeBatchAt(ctx, g.succ, batch) {
+			if err != nil {
+				errs[g.idx[j]] = n.Failure(stage, fmt.Errorf("putbatch: failed to store key %s at successor %s: %w",
+					batch[j].RawKey, g.succ.Addr, err))
+				lastErr = err
+				continue
+			}
+			stored = append(stored, batch[j])
+		}
+		if lastErr != nil {
+			n.lgr.Error("PutBatch: failed to store resources at successor",
+				logger.F("keys", len(batch)), logger.F("failed", len(batch)-len(stored)),
+				logger.FNode("successor", g.succ), logger.F("err", lastErr))
+		}
+		if len(stored) == 0 {
+			continue
+		}
+		n.lgr.Info("PutBatch: resources stored at successor",
+			logger.F("keys", len(stored)), logger.FNode("successor", g.succ))
+		n.replicateBatch(ctx, g.succ, stored)
+	}
+	return errs
+}
+
+// GetBatch retrieves several resources from the DHT on behalf of an
+// external client. Keys are grouped by successor, so that each owner is
+// resolved and contacted over a single connection; a key whose owner
+// cannot be reached is read from its replicas, as in Get.
+//
+// Returns one resource and one error per key, in order: the resource if
+// found, otherwise an error that is (or wraps) a NotFound status if it
+// does not exist, or a *domain.Failure for routing or RPC failures.
+func (n *Node) GetBatch(ctx context.Context, ids []domain.ID) ([]*domain.Resource, []error) {
+	found := make([]*domain.Resource, len(ids))
+	errs := make([]error, len(ids))
+	// Abort if context already canceled/expired or the node is stopping
+	if err := n.beginOp(ctx); err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return found, errs
+	}
+	defer n.ops.Done()
+
+	for _, g := range n.groupByOwner(ctx, "getbatch", ids, errs) {
+		if g.succ.ID.Equal(n.rt.Self().ID) {
+			for _, i := range g.idx {
+				res, err := n.RetrieveLocal(ids[i])
+				switch {
+				case err == nil:
+					found[i] = &res
+				case errors.Is(err, domain.ErrResourceNotFound):
+					errs[i] = status.Error(codes.NotFound, "key not found")
+				case errors.Is(err, domain.ErrResourceCorrupted):
+					errs[i] = n.Failure(domain.StageStorage, status.Error(codes.DataLoss, "resource corrupted"))
+				default:
+					errs[i] = n.Failure(domain.StageStorage, fmt.Errorf("getbatch: failed to retrieve resource locally: %w", err))
+				}
+			}
+			continue
+		}
+
+		cli, release, err := n.clientFor(g.succ.Addr)
+		if err != nil {
+			n.lgr.Error("GetBatch: failed to get connection to successor",
+				logger.F("keys", len(g.idx)), logger.FNode("successor", g.succ), logger.F("err", err))
+		}
+		for _, i := range g.idx {
+			rerr := err
+			if cli != nil {
+				var res *domain.Resource
+				if res, rerr = client.RetrieveRemote(ctx, cli, n.Space(), ids[i]); rerr == nil {
+					found[i] = res
+					continue
+				}
+			}
+			if ownerUnreachable(rerr) {
+				if res, ferr := n.retrieveFromReplicas(ctx, g.succ, ids[i]); ferr == nil {
+					found[i] = res
+					continue
+				}
+			}
+			errs[i] = n.opError(domain.StageTransfer, fmt.Errorf("getbatch: failed to retrieve resource from successor %s: %w", g.succ.Addr, rerr))
+		}
+		if release != nil {
+			release()
+		}
+	}
+	return found, errs
+}
+
+// ownerGroup is a set of keys of a batch operation owned by the same node.
+type ownerGroup struct {
+	succ *domain.Node
+	idx  []int // positions in the batch
+}
+
+// groupByOwner locates the successor of each of ids and groups them by
+// successor, preserving the order of first appearance. Keys whose lookup
+// fails are left out, with the failure recorded in errs (op prefixes the
+// error messages).
+func (n *Node) groupByOwner(ctx context.Context, op string, ids []domain.ID, errs []error) []*ownerGroup {
+	groups := make(map[string]*ownerGroup)
+	var order []*ownerGroup
+	for i, id := range ids {
+		succ, err := n.findSuccessorRetry(ctx, id)
+		if err != nil {
+			errs[i] = n.Failure(domain.StageRouting, fmt.Errorf("%s: key %s: %w: %w", op, id.ToHexString(true), ErrLookupFailed, err))
+			continue
+		}
+		if succ == nil {
+			errs[i] = fmt.Errorf("%s: key %s: %w", op, id.ToHexString(true), ErrNoSuccessor)
+			continue
+		}
+		g, ok := groups[succ.Addr]
+		if !ok {
+			g = &ownerGroup{succ: succ}
+			groups[succ.Addr] = g
+			order = append(order, g)
+		}
+		g.idx = append(g.idx, i)
+	}
+	return order
+}
+
+// storeBatchAt stores resources on target, the owner of their keys
+// (locally if target is this node), over a single Store stream, and
+// returns one error per resource: nil if it was stored, in which case its
+// version is set to the one the owner stored.
+//
+// The owner aborts the stream at the first resource it cannot store and
+// keeps the ones received before it, so when the stream is rejected by
+// the storage of the owner (see storeRejected) the resources are sent again
+// one per stream, to report only the ones that actually failed. Any other
+// failure of the stream is reported for every resource.
+func (n *Node) storeBatchAt(ctx context.Context, target *domain.Node, resources []domain.Resource) []error {
+	errs := make([]error, len(resources))
+	if target.ID.Equal(n.rt.Self().ID) {
+		for i, res := range resources {
+			resources[i].Version, errs[i] = n.StoreLocalIf(ctx, res, domain.Condition{})
+		}
+		return errs
+	}
+	cli, release, err := n.clientFor(target.Addr)
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	defer release()
+	failed, err := client.StoreRemote(ctx, cli, resources)
+	switch {
+	case err != nil && len(resources) > 1 && storeRejected(err) && ctx.Err() == nil:
+		for i := range resources {
+			if _, errs[i] = client.StoreRemote(ctx, cli, resources[i:i+1]); errs[i] != nil && ctx.Err() != nil {
+				// out of time: the remaining resources are not sent
+				for j := i + 1; j < len(errs); j++ {
+					errs[j] = errs[i]
+				}
+				break
+			}
+		}
+	case err != nil:
+		for i := range errs {
+			errs[i] = err
+		}
+	default:
+		for i, res := range resources {
+			if slices.ContainsFunc(failed, func(f domain.Resource) bool { return f.Key.Equal(res.Key) }) {
+				errs[i] = errors.New("resource not sent on the store stream")
+			}
+		}
+	}
+	return errs
+}
+
+// storeRejected reports whether a Store stream was aborted by the storage
+// of the remote node (a full quota, an invalid resource, a condition that
+// does not hold or a storage failure) rather than by the connection.
+func storeRejected(err error) bool {
+	switch status.Code(err) {
+	case codes.ResourceExhausted, codes.InvalidArgument, codes.FailedPrecondition, codes.Internal:
+		return true
+	}
+	return false
+}
+
+// removeBatchAt removes keys from the storage of target (locally if target
+// is this node) and reports, for each key, whether it was stored there.
+func (n *Node) removeBatchAt(ctx context.Context, target *domain.Node, keys []domain.ID) ([]bool, error) {
+	if target.ID.Equal(n.rt.Self().ID) {
+		removed := make([]bool, len(keys))
+		for i, k := range keys {
+			err := n.RemoveLocal(k)
+			if err != nil && !errors.Is(err, domain.ErrResourceNotFound) {
+				return nil, err
+			}
+			removed[i] = err == nil
+		}
+		return removed, nil
+	}
+	cli, release, err := n.clientFor(target.Addr)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return client.RemoveBatchRemote(ctx, cli, keys)
+}
+
+// removeRangeAt removes the resources with key in (from, to] stored on
+// target, locally if target is this node, and returns how many were removed.
+func (n *Node) removeRangeAt(ctx context.Context, target *domain.Node, from, to domain.ID) (int, error) {
+	if target.ID.Equal(n.rt.Self().ID) {
+		return n.RemoveRangeLocal(from, to), nil
+	}
+	cli, release, err := n.clientFor(target.Addr)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	return client.RemoveRangeRemote(ctx, cli, from, to)
+}
+
 // StoreLocal stores the given resource in the local node's storage.
 // This method is invoked in the node-to-node path (via StoreRemote).
 //
 // Behavior:
+//   - If this node is an observer, it is never responsible and the
+//     resource is rejected with domain.ErrNotResponsible.
+//   - If this node is leaving the ring (see Leave), the resource is
+//     forwarded to the successor, which takes over its range.
 //   - If this node has no predecessor (bootstrap phase), it considers
-//     itself responsible for all keys and stores the resource.
-//   - If the resource key ∈ (pred, self], the resource is stored locally.
+//     itself responsible for all keys and stores the resource. If its
+//     successor is another node, the predecessor is only missing for now
+//     (e.g. it has just failed) and the store follows the policy set by
+//     WithNoPredecessorPolicy: by default it is accepted as well.
+//   - If the resource key ∈ (pred, self], the resource is stored locally,
+//     unless it does not fit in the storage quota or maximum number of keys
+//     (domain.ErrStorageFull).
 //   - Otherwise, this node is not responsible and returns an error
-//     (the caller must retry the lookup and forward correctly).
+//     wrapping domain.ErrNotResponsible (the caller must retry the lookup
+//     and forward correctly).
 func (n *Node) StoreLocal(ctx context.Context, resource domain.Resource) error {
+	_, err := n.StoreLocalIf(ctx, resource, domain.Condition{})
+	return err
+}
+
+// StoreLocalIf is like StoreLocal, but the resource is stored only if cond
+// holds for the one stored under its key (see domain.Condition); otherwise
+// it returns an error wrapping domain.ErrPreconditionFailed. A leaving node
+// forwards the condition to its successor along with the resource. It
+// returns the version stored under the key: the one this node assigned to
+// a new write (see storage.Store.PutConditional).
+func (n *Node) StoreLocalIf(ctx context.Context, resource domain.Resource, cond domain.Condition) (uint64, error) {
 	// Abort if context already canceled/expired
 	if err := ctxutil.CheckContext(ctx); err != nil {
-		return err
+		return 0, err
+	}
+
+	if n.observer {
+		return 0, fmt.Errorf("storelocal: observer node: %w", domain.ErrNotResponsible)
+	}
+
+	n.leaveMu.RLock()
+	defer n.leaveMu.RUnlock()
+	if n.leaving {
+		return n.forwardStore(ctx, resource, cond)
 	}
 
 	pred := n.rt.GetPredecessor()
+	if pred == nil {
+		if err := n.storeWithoutPredecessor(resource); err != nil {
+			return 0, err
+		}
+	}
 	// If no predecessor or key in (pred, self], store locally
 	if pred == nil || resource.Key.Between(pred.ID, n.rt.Self().ID) {
-		n.s.Put(resource)
-		return nil
+		version, err := n.s.PutConditional(resource, cond)
+		if err != nil {
+			return 0, fmt.Errorf("storelocal: key %s: %w", resource.RawKey, err)
+		}
+		return version, nil
 	}
 	// Not responsible: return error
-	return fmt.Errorf("storelocal: not responsible for key %s", resource.RawKey)
+	return 0, fmt.Errorf("storelocal: key %s: %w", resource.RawKey, domain.ErrNotResponsible)
+}
+
+// forwardStore stores resource on the successor of this node, which takes
+// over its range while it is leaving the ring, and returns the version the
+// successor stored.
+func (n *Node) forwardStore(ctx context.Context, resource domain.Resource, cond domain.Condition) (uint64, error) {
+	succ := n.rt.FirstSuccessor()
+	if succ == nil || succ.ID.Equal(n.rt.Self().ID) {
+		return 0, fmt.Errorf("storelocal: leaving node without successor: %w", domain.ErrNotResponsible)
+	}
+	cli, release, err := n.clientFor(succ.Addr)
+	if err != nil {
+		return 0, fmt.Errorf("storelocal: leaving, failed to connect to successor %s: %w", succ.Addr, err)
+	}
+	defer release()
+	version, err := client.StoreIf(ctx, cli, resource, cond)
+	if err != nil {
+		return 0, fmt.Errorf("storelocal: leaving, failed to forward to successor %s: %w", succ.Addr, err)
+	}
+	n.lgr.Info("StoreLocal: node leaving, resource forwarded to successor",
+		logger.F("key", resource.RawKey), logger.FNode("successor", succ))
+	return version, nil
 }
 
 // RetrieveLocal fetches a resource from the local storage by its identifier.
@@ -621,7 +1789,8 @@ func (n *Node) StoreLocal(ctx context.Context, resource domain.Resource) error {
 // Behavior:
 //   - Looks up the given ID in the local storage.
 //   - Returns the resource if found.
-//   - Returns domain.ErrResourceNotFound if the resource does not exist.
+//   - Returns domain.ErrResourceNotFound if the resource does not exist
+//     or has expired (expired resources are deleted lazily).
 //
 // Note: Unlike Get (client-facing), this method does not perform routing.
 // It only checks the local storage of this node.
@@ -643,6 +1812,34 @@ func (n *Node) RemoveLocal(id domain.ID) error {
 	return n.s.Delete(id)
 }
 
+// RemoveRangeLocal removes the resources stored locally whose key lies in
+// (from, to], owned or replica copies, and returns how many were removed.
+// This method is invoked in the node-to-node path (via RemoveRangeRemote)
+// and does not perform routing.
+func (n *Node) RemoveRangeLocal(from, to domain.ID) int {
+	removed := 0
+	for _, res := range n.s.Between(from, to) {
+		if n.s.Delete(res.Key) == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// RetrieveRangeLocal returns a snapshot of the resources stored locally
+// whose key lies in (from, to]. This method is invoked in the node-to-node
+// path (via RetrieveRangeRemote) and does not perform routing.
+func (n *Node) RetrieveRangeLocal(from, to domain.ID) []domain.Resource {
+	return n.s.Between(from, to)
+}
+
+// MerkleTree returns the Merkle tree of depth levels over the resources
+// stored locally whose key lies in (from, to]. This method is invoked in
+// the node-to-node path (via SyncDigest) and does not perform routing.
+func (n *Node) MerkleTree(from, to domain.ID, depth int) *storage.MerkleTree {
+	return storage.NewMerkleTree(n.s.Between(from, to), from, to, n.Space().Bits, depth)
+}
+
 // GetAllResourceStored returns a snapshot of all resources currently
 // stored in this node's local storage.
 //
@@ -660,12 +1857,21 @@ func (n *Node) GetAllResourceStored() []domain.Resource {
 	return n.s.All()
 }
 
+// GetStoredPage returns a page of the resources in this node's local
+// storage, ordered by ID and filtered by raw key prefix, plus the token of
+// the next page ("" on the last one). See storage.Page for the meaning of
+// after and limit.
+func (n *Node) GetStoredPage(prefix, after string, limit int) ([]domain.Resource, string) {
+	return storage.Page(n.s, prefix, after, limit)
+}
+
 // LookUp performs a DHT lookup for the given identifier and returns
 // the successor node responsible for it.
 //
 // Behavior:
 //   - Validates the context (propagating client deadlines/cancellations).
-//   - Runs a FindSuccessor lookup starting from this node.
+//   - Runs a FindSuccessor lookup starting from this node, run again on
+//     failure if configured (see WithLookupRetry).
 //   - Returns the successor node if found.
 //
 // Returns:
@@ -675,21 +1881,69 @@ func (n *Node) GetAllResourceStored() []domain.Resource {
 // Note: This method only locates the node responsible for the given ID.
 // It does not retrieve or modify any resource stored in the DHT.
 func (n *Node) LookUp(ctx context.Context, id domain.ID) (*domain.Node, error) {
-	if err := ctxutil.CheckContext(ctx); err != nil {
+	if err := n.beginOp(ctx); err != nil {
 		return nil, err
 	}
+	defer n.ops.Done()
 
-	succ, err := n.FindSuccessorInit(ctx, id)
+	succ, err := n.findSuccessorRetry(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("lookup: failed to find successor for key %s: %w", id.ToHexString(true), err)
+		return nil, fmt.Errorf("lookup: key %s: %w: %w", id.ToHexString(true), ErrLookupFailed, err)
 	}
 	if succ == nil {
-		return nil, fmt.Errorf("lookup: no successor found for key %s", id.ToHexString(true))
+		return nil, fmt.Errorf("lookup: key %s: %w", id.ToHexString(true), ErrNoSuccessor)
 	}
 
 	return succ, nil
 }
 
+// ApproxLookup returns a local estimate of the node responsible for id,
+// answered from the routing table alone (no RPC is issued), for clients
+// that prefer a single hop to an exact multi-hop lookup.
+//
+// exact is true when the routing table proves the answer: id falls in
+// (predecessor, self], or between two consecutive entries of the successor
+// list. Otherwise the returned node is the known one (self, predecessor,
+// successor list, de Bruijn list) that most closely precedes id: the owner
+// lies after it, usually among its successors, so a client can continue
+// the lookup from there.
+func (n *Node) ApproxLookup(id domain.ID) (node *domain.Node, exact bool) {
+	self := n.rt.Self()
+	if id.Equal(self.ID) {
+		return self, true
+	}
+	if pred := n.rt.GetPredecessor(); pred != nil && id.Between(pred.ID, self.ID) {
+		return self, true
+	}
+	prev := self
+	for _, s := range n.rt.SuccessorList() {
+		if s == nil {
+			continue
+		}
+		if id.Between(prev.ID, s.ID) {
+			return s, true
+		}
+		prev = s
+	}
+
+	// closest preceding known node: a candidate x improves on best if it
+	// lies in (best, id); a node whose ID is id owns it
+	best := self
+	known := append([]*domain.Node{n.rt.GetPredecessor()}, n.rt.SuccessorList()...)
+	for _, x := range append(known, slices.Concat(n.rt.DeBruijnWindow()...)...) {
+		if x == nil {
+			continue
+		}
+		if x.ID.Equal(id) {
+			return x, true
+		}
+		if x.ID.Between(best.ID, id) {
+			best = x
+		}
+	}
+	return best, false
+}
+
 // HandleLeave processes a graceful leave notification from a predecessor.
 //
 // Behavior:
@@ -703,6 +1957,9 @@ func (n *Node) LookUp(ctx context.Context, id domain.ID) (*domain.Node, error) {
 //   - nil if the leave was processed or safely ignored.
 //   - error only if the input was invalid.
 func (n *Node) HandleLeave(leaveNode *domain.Node) error {
+	n.predMu.Lock()
+	defer n.predMu.Unlock()
+
 	pred := n.rt.GetPredecessor()
 	if leaveNode == nil || pred == nil || !leaveNode.ID.Equal(pred.ID) {
 		n.lgr.Warn("HandleLeave: ignoring leave for nil or non-predecessor node",
@@ -723,3 +1980,89 @@ func (n *Node) HandleLeave(leaveNode *domain.Node) error {
 		logger.FNode("leavingNode", leaveNode))
 	return nil
 }
+
+// HandleSuccessorLeave processes a graceful leave notification from the
+// successor, which sends its own successors along (see Leave).
+//
+// Behavior:
+//   - If the leaving node is nil or not the current successor, the
+//     leave is ignored (benign event: stabilization already moved on).
+//   - Otherwise the successor list is rebuilt from succs, dropping the
+//     leaving node and stopping at self as fixSuccessorList does; if no
+//     entry is left this node becomes its own successor (single-node ring).
+//   - Pool references are added for the new entries before the list is
+//     installed and released for the dropped ones afterwards.
+//
+// The update runs under chordMu, so it never interleaves with a Chord
+// stabilization round; the next round then notifies the new successor.
+//
+// Returns:
+//   - nil if the leave was processed or safely ignored.
+func (n *Node) HandleSuccessorLeave(leaving *domain.Node, succs []*domain.Node) error {
+	n.chordMu.Lock()
+	defer n.chordMu.Unlock()
+
+	self := n.rt.Self()
+	succ := n.rt.FirstSuccessor()
+	if leaving == nil || succ == nil || !leaving.ID.Equal(succ.ID) {
+		n.lgr.Warn("HandleSuccessorLeave: ignoring leave for nil or non-successor node",
+			logger.FNode("leavingNode", leaving))
+		return nil
+	}
+
+	// Build the new list (fixed size, entries past the leaving node)
+	size := n.rt.SuccessorListSize()
+	newList := make([]*domain.Node, size)
+	i := 0
+	for _, nd := range succs {
+		if i == size {
+			break
+		}
+		if nd == nil || nd.ID.Equal(leaving.ID) {
+			continue
+		}
+		if nd.ID.Equal(self.ID) {
+			break
+		}
+		newList[i] = nd
+		i++
+	}
+	if newList[0] == nil {
+		newList[0] = self
+	}
+
+	// Adjust pool references: addRef new nodes, release removed ones
+	oldSet := make(map[string]*domain.Node)
+	for _, nd := range n.rt.SuccessorList() {
+		if nd != nil && !nd.ID.Equal(self.ID) {
+			oldSet[nd.Addr] = nd
+		}
+	}
+	newSet := make(map[string]*domain.Node)
+	for _, nd := range newList {
+		if nd != nil && !nd.ID.Equal(self.ID) {
+			newSet[nd.Addr] = nd
+		}
+	}
+	for addr, nd := range newSet {
+		if _, ok := oldSet[addr]; !ok {
+			if err := n.cp.AddRef(addr); err != nil {
+				n.lgr.Warn("HandleSuccessorLeave: addref failed",
+					logger.FNode("node", nd), logger.F("err", err))
+			}
+		}
+	}
+	n.rt.SetSuccessorList(newList)
+	for addr, nd := range oldSet {
+		if _, ok := newSet[addr]; !ok {
+			if err := n.cp.Release(addr); err != nil {
+				n.lgr.Warn("HandleSuccessorLeave: release failed",
+					logger.FNode("node", nd), logger.F("err", err))
+			}
+		}
+	}
+
+	n.lgr.Info("HandleSuccessorLeave: leaving successor replaced",
+		logger.FNode("leavingNode", leaving), logger.FNode("successor", newList[0]))
+	return nil
+}
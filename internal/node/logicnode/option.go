@@ -1,6 +1,12 @@
 package logicnode
 
-import "KoordeDHT/internal/logger"
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/telemetry/nodemetrics"
+	"time"
+)
 
 type Option func(*Node)
 
@@ -12,3 +18,389 @@ func WithLogger(l logger.Logger) Option {
 		}
 	}
 }
+
+// WithMetrics enables the node metrics (default disabled): every lookup
+// originated by the node is counted, and the storage and connection pool
+// gauges are updated at every stabilization pass.
+func WithMetrics(m *nodemetrics.Metrics) Option {
+	return func(n *Node) {
+		n.metrics = m
+	}
+}
+
+// WithPullOnJoin controls whether Join pulls the keys of the new node's
+// range from its successor before sending the first Notify (default true).
+// Keys are therefore already available locally when other nodes start
+// routing requests for that range to the new node.
+func WithPullOnJoin(enabled bool) Option {
+	return func(n *Node) {
+		n.pullOnJoin = enabled
+	}
+}
+
+// WithObserver runs the node as an observer (default false). An observer
+// joins the ring and maintains its successor list and de Bruijn pointers,
+// so it can serve lookups and client requests, but it never announces
+// itself to its successor: it is never chosen as the owner of a key and
+// StoreLocal always rejects. An observer cannot create a new DHT.
+func WithObserver(enabled bool) Option {
+	return func(n *Node) {
+		n.observer = enabled
+	}
+}
+
+// WithDeBruijn enables or disables de Bruijn routing (default true).
+// When disabled the node keeps no de Bruijn pointers and forwards every
+// lookup step to its successor, as a plain Chord ring walk without finger
+// tables: lookups still resolve, in O(n) hops. Meant for comparison studies
+// and to tell de Bruijn bugs apart from ring bugs.
+func WithDeBruijn(enabled bool) Option {
+	return func(n *Node) {
+		n.deBruijn = enabled
+	}
+}
+
+// WithIterativeLookup selects the iterative lookup mode (default false,
+// recursive): the lookups originated by this node ask each hop only for
+// the next node (FindSuccessorNextHop) and contact it themselves, instead
+// of having every hop forward the request. Routing decisions, and thus
+// the resulting successor, are the same in both modes.
+func WithIterativeLookup(enabled bool) Option {
+	return func(n *Node) {
+		n.iterative = enabled
+	}
+}
+
+// WithReplicas sets the replication factor r (default 1, no replication):
+// every resource is stored on its owner and on the owner's next r-1
+// successors. Replication is best-effort; values below 1 are ignored.
+// r-1 should not exceed the successor list size.
+func WithReplicas(r int) Option {
+	return func(n *Node) {
+		if r >= 1 {
+			n.replicas = r
+		}
+	}
+}
+
+// WithReadRepair enables read repair (default false): when the owner of
+// a key answers a Get that it does not hold it, the Get reads the key from
+// the replicas and, if one has it, returns it and stores it back on the
+// owner in the background. It has no effect without replication (see
+// WithReplicas). A replica that missed a Delete can bring the key back.
+func WithReadRepair(enabled bool) Option {
+	return func(n *Node) {
+		n.readRepair = enabled
+	}
+}
+
+// WithAdaptiveSuccessorList makes the successor list size follow the
+// estimated ring size (⌈log2 n⌉, see EstimateRingSize), bounded by
+// [minSize, maxSize]; it is recomputed at every Chord stabilization round.
+// Invalid bounds (minSize < 1 or maxSize < minSize) leave the size fixed.
+func WithAdaptiveSuccessorList(minSize, maxSize int) Option {
+	return func(n *Node) {
+		if minSize >= 1 && maxSize >= minSize {
+			n.minSuccList, n.maxSuccList = minSize, maxSize
+		}
+	}
+}
+
+// WithHopBudget configures how a lookup deadline is split across hops.
+// Each forwarded FindSuccessor step receives the remaining time minus
+// reserve (kept to report the result back), but at least minBudget; when
+// less than minBudget remains the lookup fails immediately with
+// DeadlineExceeded instead of issuing a hop that cannot complete. A lookup
+// of n hops thus spends n·reserve of its deadline on the way back.
+// Defaults: reserve 2ms, minBudget 5ms; negative values are ignored.
+func WithHopBudget(reserve, minBudget time.Duration) Option {
+	return func(n *Node) {
+		if reserve >= 0 {
+			n.hopReserve = reserve
+		}
+		if minBudget >= 0 {
+			n.minHopBudget = minBudget
+		}
+	}
+}
+
+// WithLookupRetry makes the lookups of client operations (Put, Get, Delete,
+// their batch variants and LookUp) retry a failed lookup up to maxRetries
+// times, waiting baseDelay before the first retry and doubling the delay
+// after every further failure. Every retry runs the whole lookup again from
+// this node, so it can route around a hop that was momentarily
+// unreachable; retries stop as soon as the caller's context is done.
+// maxRetries 0 disables retries (default); negative values are ignored.
+func WithLookupRetry(maxRetries int, baseDelay time.Duration) Option {
+	return func(n *Node) {
+		if maxRetries >= 0 {
+			n.lookupRetries = maxRetries
+		}
+		if baseDelay >= 0 {
+			n.lookupBackoff = baseDelay
+		}
+	}
+}
+
+// WithLookupCache makes Put and Get remember the owner found by their
+// lookups for ttl, in an LRU cache of at most size entries keyed by the
+// high-order bits of the key (see client.LookupCache), and skip the lookup
+// for keys a live entry covers. An entry is dropped, and the operation is
+// retried with a full lookup, as soon as its owner cannot be reached or
+// answers that it no longer holds the key, e.g. because a node joined in
+// front of it. Size or ttl 0 disables the cache (default).
+func WithLookupCache(size int, ttl time.Duration) Option {
+	return func(n *Node) {
+		n.lookupCache = client.NewLookupCache(size, ttl)
+	}
+}
+
+// WithLookupParallelism makes each lookup step query up to m de Bruijn
+// candidates concurrently, from the closest to the farthest, instead of one
+// at a time: the first valid answer wins and the other queries are
+// canceled, so a single slow hop no longer holds the lookup for the whole
+// failure timeout. If a whole group fails the next m candidates are tried,
+// then the successor as usual. Values below 2 keep the sequential order
+// (default).
+func WithLookupParallelism(m int) Option {
+	return func(n *Node) {
+		n.lookupParallelism = max(m, 0)
+	}
+}
+
+// WithMaxLocalDepth bounds the local recursion of a lookup step, i.e. the
+// consecutive steps this node runs on itself when it is its own de Bruijn
+// candidate: beyond depth nested steps the lookup fails with
+// ResourceExhausted instead of growing the stack. The bound is independent
+// of the network hop count. A correct lookup never needs more steps than
+// the base-k digits of an ID; 0 selects twice that many (default),
+// negative values are ignored.
+func WithMaxLocalDepth(depth int) Option {
+	return func(n *Node) {
+		if depth >= 0 {
+			n.maxLocalDepth = depth
+		}
+	}
+}
+
+// WithLazyDeBruijnRefresh makes the de Bruijn stabilizer skip its round,
+// anchor lookup included, while nothing suggests the window is stale: the
+// successor list is unchanged since the last refresh, the window is
+// complete and still anchored at the anchor that refresh found, and the
+// refresh is younger than maxAge. This trades reaction time to joins near
+// the anchor (bounded by maxAge) for one lookup per node per round. 0
+// refreshes at every round (default).
+func WithLazyDeBruijnRefresh(maxAge time.Duration) Option {
+	return func(n *Node) {
+		n.deBruijnMaxAge = max(maxAge, 0)
+	}
+}
+
+// WithAdaptiveStabilization makes the Chord and de Bruijn stabilizers adapt
+// their interval to the churn of the ring, instead of running at the
+// intervals given to StartStabilizers: every round that finds the routing
+// state unchanged doubles the interval, up to maxInterval, and any change
+// brings it back to minInterval. A non-positive maxInterval, or a
+// minInterval not in (0, maxInterval], keeps fixed intervals (default).
+func WithAdaptiveStabilization(minInterval, maxInterval time.Duration) Option {
+	return func(n *Node) {
+		if minInterval <= 0 || maxInterval < minInterval {
+			n.minInterval, n.maxInterval = 0, 0
+			return
+		}
+		n.minInterval, n.maxInterval = minInterval, maxInterval
+	}
+}
+
+// WithAntiEntropy enables a maintenance loop that, every interval,
+// compares the resources of the node with those of its successor through
+// Merkle trees of depth levels (2^depth leaves, 0 = 8, at most
+// storage.MaxMerkleDepth) and transfers only the resources on which they
+// differ (see antiEntropy). Deeper trees transfer fewer unchanged resources
+// along with the differing ones, at the cost of more round trips. A
+// non-positive interval disables anti-entropy (default).
+func WithAntiEntropy(interval time.Duration, depth int) Option {
+	return func(n *Node) {
+		n.syncInterval = max(interval, 0)
+		if depth <= 0 {
+			depth = defaultSyncDepth
+		}
+		n.syncDepth = min(depth, storage.MaxMerkleDepth)
+	}
+}
+
+// WithAsymmetryCheck sets how many consecutive stabilization rounds a
+// successor may fail to acknowledge this node as its predecessor before a
+// warning is logged (default 3, <= 0 disables the check). With renotify,
+// from then on the Notify of every round is sent over a fresh connection
+// instead of the pooled one, until the successor acknowledges this node
+// (see checkSymmetry).
+func WithAsymmetryCheck(rounds int, renotify bool) Option {
+	return func(n *Node) {
+		n.asymRounds = max(rounds, 0)
+		n.asymRenotify = renotify
+	}
+}
+
+// WithCatchUp enables a catch-up phase every time the stabilizers are
+// started (i.e., after a join or rejoin): the Chord and de Bruijn
+// stabilizers also run every interval, for at most maxRounds rounds or
+// until the routing state converges, after which only the regular
+// intervals remain. A zero interval or maxRounds disables catch-up
+// (default).
+func WithCatchUp(interval time.Duration, maxRounds int) Option {
+	return func(n *Node) {
+		n.catchUpInterval = interval
+		n.catchUpRounds = maxRounds
+	}
+}
+
+// WithRoutingPersistence makes the stabilizers save the routing table to
+// path every interval in which it changed (<= 0 = 10s), and once more when
+// they stop, so that a restart can resume from it with RestoreRoutingTable
+// instead of a full join. An empty path disables persistence (default).
+func WithRoutingPersistence(path string, interval time.Duration) Option {
+	return func(n *Node) {
+		if interval <= 0 {
+			interval = defaultRoutingSaveInterval
+		}
+		n.rtFile, n.rtSaveInterval = path, interval
+	}
+}
+
+// WithVNodeGroup makes the node one of the virtual nodes of g, which must
+// all share the same client pool and storage (see vnode.go). Resources
+// owned by another virtual node of g are never moved to it, and Leave
+// hands over only the range of the node.
+func WithVNodeGroup(g *VNodeGroup) Option {
+	return func(n *Node) {
+		n.vnodes = g
+		g.add(n)
+	}
+}
+
+// WithStabilizationJitter randomizes the interval of the stabilizers
+// (Chord, de Bruijn, storage maintenance and anti-entropy) by ±fraction on
+// every pass, so that nodes started together do not keep running their
+// passes in lockstep (default 0.1). 0 disables jitter; values outside
+// [0, 1) are ignored.
+func WithStabilizationJitter(fraction float64) Option {
+	return func(n *Node) {
+		if fraction >= 0 && fraction < 1 {
+			n.jitter = fraction
+		}
+	}
+}
+
+// WithDrainTimeout sets how long Stop waits for the in-flight client
+// operations to complete before leaving the ring and closing the client
+// pool (default 5s). 0 does not wait; negative values are ignored.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(n *Node) {
+		if d >= 0 {
+			n.drainTimeout = d
+		}
+	}
+}
+
+// WithSuccessorListFallback makes the Chord stabilizer ask the next entry
+// of the successor list for its list when the first successor does not
+// answer, so that the list keeps converging while the first successor is
+// slow or failing (default true). The first successor stays at the head of
+// the list either way: replacing it is up to stabilizeSuccessor.
+func WithSuccessorListFallback(enabled bool) Option {
+	return func(n *Node) {
+		n.succListFallback = enabled
+	}
+}
+
+// WithMaxHops bounds the forwarding hops of a lookup: a node reached by a
+// lookup step that already made more than max hops aborts the lookup with
+// ErrMaxHops (codes.Internal on the wire) instead of forwarding it again,
+// so that a routing loop in a misconfigured or partially stabilized ring
+// fails at once rather than on the deadline. The bound applies to the
+// iterative lookups as well. 0 (default) selects three hops per base-k
+// digit of an ID with de Bruijn routing, and four times the estimated ring
+// size in Chord-only mode, whose lookups walk the ring in O(n) hops, but
+// no fewer than the former; both plus a small slack. Negative values are
+// ignored.
+func WithMaxHops(max int) Option {
+	return func(n *Node) {
+		if max >= 0 {
+			n.maxHops = max
+		}
+	}
+}
+
+// WithOwnerRetries makes Put, Get and Delete, when the owner found by their
+// lookup cannot be reached (it may have died or left between the lookup and
+// the forwarded request), resolve the owner again with a new lookup and
+// retry on it, up to retries times. Each new lookup waits the retry delay of
+// WithLookupRetry, doubled after every attempt, so that the stabilization
+// can route around the dead node. An owner taken from the lookup cache is
+// always looked up again at once. 0 (default) fails at once; negative values
+// are ignored.
+func WithOwnerRetries(retries int) Option {
+	return func(n *Node) {
+		if retries >= 0 {
+			n.ownerRetries = retries
+		}
+	}
+}
+
+// WithOwnerReserve makes the lookup of a Put, Get or Delete run under the
+// remaining deadline of the operation minus the fraction reserve of it
+// (but at least the minimum hop budget of WithHopBudget), which is kept to
+// contact the owner once found; if the lookup consumes its share, the
+// operation fails with DeadlineExceeded instead of sending the owner a
+// request that cannot complete. Default 0.2; values outside [0,1) are
+// ignored.
+func WithOwnerReserve(reserve float64) Option {
+	return func(n *Node) {
+		if reserve >= 0 && reserve < 1 {
+			n.ownerReserve = reserve
+		}
+	}
+}
+
+// WithNoPredecessorPolicy sets what StoreLocal does with a resource received
+// while the node does not know its predecessor but its successor is
+// another node, i.e. the predecessor is missing only for now (it has just
+// failed, or the node has just joined) and the node cannot tell whether it
+// owns the key:
+//   - NoPredecessorAccept (default): store it, as if the node owned every
+//     key.
+//   - NoPredecessorRepair: store it, and run a storage maintenance pass as
+//     soon as a predecessor is known instead of waiting for the periodic
+//     one, so that a misplaced resource reaches its owner quickly.
+//   - NoPredecessorReject: refuse it with ErrNoPredecessor (Unavailable
+//     over gRPC), so that the caller resolves the owner again (see
+//     WithOwnerRetries).
+//
+// Repaired and rejected stores are counted by the metrics of WithMetrics.
+// Unknown policies are ignored.
+func WithNoPredecessorPolicy(policy string) Option {
+	return func(n *Node) {
+		switch policy {
+		case NoPredecessorAccept, NoPredecessorRepair, NoPredecessorReject:
+			n.noPredPolicy = policy
+		}
+	}
+}
+
+// WithMaxOutbound bounds the outbound forward RPCs the node has in
+// progress at once to max: the lookup hops it forwards (recursive or
+// iterative) and the Put, Get and Delete it sends to the owner of a key.
+// Under load, further calls queue for a free slot until their context is
+// done, instead of opening more and more connections and streams. 0
+// (default) is unlimited; negative values are ignored.
+func WithMaxOutbound(max int) Option {
+	return func(n *Node) {
+		switch {
+		case max > 0:
+			n.outbound = make(chan struct{}, max)
+		case max == 0:
+			n.outbound = nil
+		}
+	}
+}
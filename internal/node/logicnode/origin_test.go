@@ -0,0 +1,100 @@
+package logicnode_test
+
+import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// waitOrigin attende che la risorsa key sia memorizzata su m e ne
+// verifica l'origine.
+func waitOrigin(t *testing.T, m *testring.Member, key domain.ID, want domain.ID) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		res, err := m.Node.RetrieveLocal(key)
+		if err == nil {
+			if !res.Origin.Equal(want) {
+				t.Errorf("key %s on %s: origin %v, want %s", res.RawKey, m.Addr, res.Origin, want.ToHexString(true))
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("key %s never reached %s: %v", key.ToHexString(true), m.Addr, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestOriginSurvivesTransfers(t *testing.T) {
+	r := testring.New(t, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Put dal primo membro, con ID distinti
+	putter := r.Members[0]
+	var keys []domain.ID
+	ids := make(map[string]struct{})
+	for i := 0; len(keys) < 50; i++ {
+		raw := fmt.Sprintf("key-%d", i)
+		id := r.Space.NewIdFromString(raw)
+		if _, dup := ids[id.ToHexString(false)]; dup {
+			continue
+		}
+		ids[id.ToHexString(false)] = struct{}{}
+		keys = append(keys, id)
+		if err := putter.Node.Put(ctx, domain.Resource{Key: id, RawKey: raw, Value: raw}); err != nil {
+			t.Fatalf("Put %s: %v", raw, err)
+		}
+	}
+	for _, id := range keys {
+		res, err := putter.Node.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("Get %s: %v", id.ToHexString(true), err)
+		}
+		if !res.Origin.Equal(putter.Node.Self().ID) {
+			t.Errorf("Get %s: origin %v, want the node that accepted the Put", res.RawKey, res.Origin)
+		}
+	}
+
+	// Trasferimento dal successore al nuovo predecessore dopo un join
+	joined := r.Add()
+	r.WaitStable()
+	moved := 0
+	for _, id := range keys {
+		if r.Owner(id) == joined {
+			waitOrigin(t, joined, id, putter.Node.Self().ID)
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Log("no key moved to the joined node")
+	}
+
+	// Spostamento di resourceRepair: una risorsa su un nodo non responsabile
+	// (StoreReplicas salta il controllo di responsabilità) passa al proprietario
+	const raw = "misplaced"
+	res := domain.Resource{Key: r.Space.NewIdFromString(raw), RawKey: raw, Value: raw, Origin: joined.Node.Self().ID}
+	owner := r.Owner(res.Key)
+	holder := r.Members[0]
+	if holder == owner {
+		holder = r.Members[1]
+	}
+	conn, err := grpc.NewClient(holder.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	if _, err := client.StoreReplicas(ctx, dhtv1.NewDHTClient(conn), []domain.Resource{res}); err != nil {
+		t.Fatalf("StoreReplicas: %v", err)
+	}
+	waitOrigin(t, owner, res.Key, joined.Node.Self().ID)
+}
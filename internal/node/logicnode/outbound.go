@@ -0,0 +1,43 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/node/ctxutil"
+	"context"
+	"fmt"
+)
+
+// acquireOutbound takes a slot of the budget of outbound forward RPCs (see
+// WithMaxOutbound), waiting for one to free up until ctx is done, and
+// returns the function that gives it back. Without a budget it never
+// waits, but the RPC is still counted by OutboundInFlight.
+func (n *Node) acquireOutbound(ctx context.Context) (release func(), err error) {
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	if n.outbound != nil {
+		select {
+		case n.outbound <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for one of %d outbound RPC slots: %w", cap(n.outbound), ctx.Err())
+		}
+	}
+	n.outboundInFlight.Add(1)
+	return func() {
+		n.outboundInFlight.Add(-1)
+		if n.outbound != nil {
+			<-n.outbound
+		}
+	}, nil
+}
+
+// OutboundInFlight returns the number of outbound forward RPCs (lookup
+// hops, and Put, Get and Delete sent to the owner) in progress.
+func (n *Node) OutboundInFlight() int {
+	return int(n.outboundInFlight.Load())
+}
+
+// MaxOutbound returns the maximum number of concurrent outbound forward
+// RPCs (0 = unlimited, see WithMaxOutbound).
+func (n *Node) MaxOutbound() int {
+	return cap(n.outbound)
+}
@@ -0,0 +1,109 @@
+package logicnode_test
+
+import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMaxOutbound(t *testing.T) {
+	// Le Store ricevute restano bloccate finché hold è chiuso
+	var blocking atomic.Bool
+	hold := make(chan struct{})
+	var stores atomic.Int32
+	block := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+		if info.FullMethod == dhtv1.DHT_Store_FullMethodName && blocking.Load() {
+			stores.Add(1)
+			<-hold
+		}
+		return h(srv, ss)
+	}
+	r := testring.New(t, 4,
+		testring.WithNodeOptions(logicnode.WithMaxOutbound(1)),
+		testring.WithServerOptions(server.WithStreamInterceptors(block)))
+	r.StopStabilizers()
+	src, succ := r.Members[0], r.Members[1]
+
+	// Due chiavi che src deve inoltrare al successore: il lookup termina
+	// su src, quindi l'unica RPC in uscita è la Store (un lookup che
+	// ripassasse da src occuperebbe un secondo slot)
+	var keys []domain.Resource
+	for i := 0; len(keys) < 2 && i < 10000; i++ {
+		k := fmt.Sprintf("key-%d", i)
+		if id := r.Space.NewIdFromString(k); r.Owner(id) == succ {
+			keys = append(keys, domain.Resource{Key: id, RawKey: k, Value: k})
+		}
+	}
+	if len(keys) < 2 {
+		t.Fatal("not enough keys owned by the successor")
+	}
+
+	blocking.Store(true)
+	first := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		first <- src.Node.Put(ctx, keys[0])
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for (stores.Load() == 0 || src.Node.OutboundInFlight() != 1) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := src.Node.OutboundInFlight(); got != 1 {
+		t.Fatalf("OutboundInFlight = %d while the first Put is forwarded, want 1", got)
+	}
+
+	// Il conteggio è esposto dalla RPC Info
+	api, conn, err := client.Connect(src.Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	ictx, icancel := context.WithTimeout(context.Background(), time.Second)
+	info, _, err := client.Info(ictx, api)
+	icancel()
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if info.OutboundInFlight != 1 || info.MaxOutbound != 1 {
+		t.Errorf("Info outbound = %d/%d, want 1/1", info.OutboundInFlight, info.MaxOutbound)
+	}
+
+	// Con l'unico slot occupato la seconda Put attende fino alla scadenza
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	err = src.Node.Put(ctx, keys[1])
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) && status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("second Put while the slot is taken: got %v, want DeadlineExceeded", err)
+	}
+	if got := stores.Load(); got != 1 {
+		t.Errorf("Store RPCs received = %d, want 1 (the second Put must queue)", got)
+	}
+
+	blocking.Store(false)
+	close(hold)
+	if err := <-first; err != nil {
+		t.Fatalf("first Put: %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := src.Node.Put(ctx, keys[1]); err != nil {
+		t.Fatalf("second Put once the slot is free: %v", err)
+	}
+	if got := src.Node.OutboundInFlight(); got != 0 {
+		t.Errorf("OutboundInFlight = %d after the Puts, want 0", got)
+	}
+}
@@ -0,0 +1,126 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOwnerDiesBetweenLookupAndForward(t *testing.T) {
+	const quota = 1024 // byte per nodo: basta per v1 e v2, non per il valore di full
+	tests := []struct {
+		name    string
+		op      string // "put", "get" o "delete"
+		retries int
+		full    bool // il proprietario resta vivo ma rifiuta la scrittura per quota piena
+		wantErr bool
+	}{
+		{name: "put re-resolves the owner", op: "put", retries: 3},
+		{name: "get re-resolves the owner", op: "get", retries: 3},
+		{name: "delete re-resolves the owner", op: "delete", retries: 3},
+		{name: "put without retries", op: "put", retries: 0, wantErr: true},
+		{name: "delete without retries", op: "delete", retries: 0, wantErr: true},
+		{name: "put rejected by a full owner", op: "put", retries: 3, full: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 4, testring.WithNodeOptions(
+				logicnode.WithReplicas(2),
+				logicnode.WithLookupRetry(0, 250*time.Millisecond),
+				logicnode.WithOwnerRetries(tt.retries)),
+				testring.WithStorageOptions(storage.WithQuota(quota)))
+			r.WaitStable()
+			origin, owner := r.Members[0], r.Members[2]
+
+			// chiave posseduta da un nodo diverso dall'origine
+			var key string
+			for i := 0; key == "" && i < 1<<20; i++ {
+				if k := fmt.Sprintf("key-%d", i); r.Owner(r.Space.NewIdFromString(k)) == owner {
+					key = k
+				}
+			}
+			if key == "" {
+				t.Skip("no key owned by the target member")
+			}
+			id := r.Space.NewIdFromString(key)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			// la copia sul successore del proprietario lo sostituisce dopo la sua morte
+			if err := origin.Node.Put(ctx, domain.Resource{Key: id, RawKey: key, Value: "v1"}); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			// il proprietario muore tra il lookup e l'inoltro della richiesta,
+			// oppure, con full, resta vivo e la nuova versione supera la quota
+			var once sync.Once
+			var lookups atomic.Int32
+			origin.Node.OnOwnerResolved(func(nd *domain.Node) {
+				lookups.Add(1)
+				if nd.Addr == owner.Addr && !tt.full {
+					once.Do(func() { r.Kill(owner) })
+				}
+			})
+			value := "v2"
+			if tt.full {
+				value = strings.Repeat("v", 2*quota)
+			}
+			var err error
+			switch tt.op {
+			case "put":
+				err = origin.Node.Put(ctx, domain.Resource{Key: id, RawKey: key, Value: value})
+			case "get":
+				var res *domain.Resource
+				if res, err = origin.Node.Get(ctx, id); err == nil && res.Value != "v1" {
+					t.Errorf("Get = %q, want v1", res.Value)
+				}
+			case "delete":
+				err = origin.Node.Delete(ctx, id)
+			}
+			if tt.full {
+				// la risposta del proprietario vivo non giustifica un nuovo lookup
+				if status.Code(err) != codes.ResourceExhausted {
+					t.Fatalf("%s on a full owner: got %v, want ResourceExhausted", tt.op, err)
+				}
+				if n := lookups.Load(); n != 1 {
+					t.Errorf("%s on a full owner: %d lookups, want 1", tt.op, n)
+				}
+				return
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("%s with a dead owner and no retries: got nil error", tt.op)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s with a dead owner: %v", tt.op, err)
+			}
+
+			// l'operazione è stata servita dal nuovo proprietario
+			heir := r.Owner(id)
+			res, err := heir.Node.RetrieveLocal(id)
+			switch tt.op {
+			case "put":
+				if err != nil || res.Value != "v2" {
+					t.Errorf("new owner holds %q (err %v), want v2", res.Value, err)
+				}
+			case "delete":
+				if !errors.Is(err, domain.ErrResourceNotFound) {
+					t.Errorf("new owner after Delete: got %v, want ErrResourceNotFound", err)
+				}
+			}
+		})
+	}
+}
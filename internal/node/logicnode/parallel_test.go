@@ -0,0 +1,127 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLookupParallelism(t *testing.T) {
+	const delay = time.Second
+
+	tests := []struct {
+		name        string
+		parallelism int
+		wantSlow    bool // il passo attende il candidato de Bruijn lento
+	}{
+		{name: "sequential", parallelism: 1, wantSlow: true},
+		{name: "parallel", parallelism: 2, wantSlow: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Il primo passo di lookup inoltrato all'indirizzo armato resta
+			// bloccato per delay (o finché il suo contesto non viene annullato)
+			var slow atomic.Pointer[string]
+			stall := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				if a := slow.Load(); a != nil && *a == cc.Target() && strings.Contains(method, "FindSuccessor") && slow.CompareAndSwap(a, nil) {
+					select {
+					case <-time.After(delay):
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}
+			// con grado 4 i passi de Bruijn hanno più candidati
+			r := testring.New(t, 8,
+				testring.WithSpace(16, 4, 4),
+				testring.WithPoolOptions(client.WithUnaryInterceptors(stall)),
+				testring.WithNodeOptions(logicnode.WithLookupParallelism(tt.parallelism)))
+			r.WaitStable()
+			// tabelle ferme: i candidati scelti restano nel pool dell'origine
+			r.StopStabilizers()
+			for _, m := range r.Members {
+				m.Node.FixDeBruijn()
+			}
+			origin, target, currentI, kshift, hop := remoteDeBruijnStep(t, r, 2)
+
+			addr := hop.Candidates[0].Addr
+			slow.Store(&addr)
+			ctx, cancel := context.WithTimeout(context.Background(), 3*delay)
+			defer cancel()
+			start := time.Now()
+			res, err := origin.Node.FindSuccessorStep(ctx, target, currentI, kshift)
+			elapsed := time.Since(start)
+			if err != nil {
+				t.Fatalf("FindSuccessorStep: %v", err)
+			}
+			if want := r.Owner(target); !res.ID.Equal(want.Node.Self().ID) {
+				t.Errorf("successor = %s, want %s", res.ID.ToHexString(true), want.Node.Self().ID.ToHexString(true))
+			}
+			if tt.wantSlow && elapsed < delay {
+				t.Errorf("step took %v, want >= %v", elapsed, delay)
+			}
+			if !tt.wantSlow && elapsed >= delay/2 {
+				t.Errorf("step took %v, want < %v", elapsed, delay/2)
+			}
+		})
+	}
+}
+
+func TestLookupCandidateTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		parallelism int
+	}{
+		{name: "sequential", parallelism: 1},
+		{name: "parallel", parallelism: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Il primo passo inoltrato all'indirizzo armato risponde subito
+			// DeadlineExceeded, come un candidato il cui budget di hop è
+			// scaduto più avanti nella catena, mentre il passo ha tempo.
+			var timedOut atomic.Pointer[string]
+			expire := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				if a := timedOut.Load(); a != nil && *a == cc.Target() && strings.Contains(method, "FindSuccessor") && timedOut.CompareAndSwap(a, nil) {
+					return status.Error(codes.DeadlineExceeded, "lookup hop budget exhausted")
+				}
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}
+			r := testring.New(t, 8,
+				testring.WithSpace(16, 4, 4),
+				testring.WithPoolOptions(client.WithUnaryInterceptors(expire)),
+				testring.WithNodeOptions(logicnode.WithLookupParallelism(tt.parallelism)))
+			r.WaitStable()
+			r.StopStabilizers()
+			for _, m := range r.Members {
+				m.Node.FixDeBruijn()
+			}
+			origin, target, currentI, kshift, hop := remoteDeBruijnStep(t, r, 2)
+
+			addr := hop.Candidates[0].Addr
+			timedOut.Store(&addr)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			res, err := origin.Node.FindSuccessorStep(ctx, target, currentI, kshift)
+			if err != nil {
+				t.Fatalf("FindSuccessorStep: %v, want the next candidate to be tried", err)
+			}
+			if timedOut.Load() != nil {
+				t.Fatal("the armed candidate was not tried")
+			}
+			if want := r.Owner(target); !res.ID.Equal(want.Node.Self().ID) {
+				t.Errorf("successor = %s, want %s", res.ID.ToHexString(true), want.Node.Self().ID.ToHexString(true))
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/logger"
+	"time"
+)
+
+// Maintenance pause (PauseStabilization)
+//
+// During bulk operations (large imports, snapshots) operators can suspend
+// the maintenance loops for a while, so that the ring does not rebalance
+// under them. While paused:
+//   - the Chord, de Bruijn, storage and catch-up loops keep ticking but
+//     skip their work; RPCs are served as usual;
+//   - with KeepFailureDetection, the Chord loop still probes the
+//     predecessor (checkPredecessor), so a crashed predecessor is cleared;
+//   - the pause ends by itself once its duration elapses.
+
+// PauseOption configures PauseStabilization.
+type PauseOption func(*pauseConfig)
+
+type pauseConfig struct {
+	detectFailures bool
+}
+
+// KeepFailureDetection keeps probing the predecessor while stabilization
+// is paused.
+func KeepFailureDetection(enabled bool) PauseOption {
+	return func(c *pauseConfig) {
+		c.detectFailures = enabled
+	}
+}
+
+// PauseStabilization suspends the maintenance loops for d and returns the
+// time at which they resume by themselves. A new call replaces the current
+// pause; d <= 0 resumes them immediately (and returns the zero time).
+//
+// It returns once the Chord and de Bruijn rounds already in flight have
+// completed, so no routing change is made by them after the call.
+func (n *Node) PauseStabilization(d time.Duration, opts ...PauseOption) time.Time {
+	var cfg pauseConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if d <= 0 {
+		n.pausedUntil.Store(0)
+		n.lgr.Info("stabilization resumed")
+		return time.Time{}
+	}
+	until := time.Now().Add(d)
+	n.pauseDetect.Store(cfg.detectFailures)
+	n.pausedUntil.Store(until.UnixNano())
+	// barrier: wait for the rounds in flight
+	n.chordMu.Lock()
+	n.deBruijnMu.Lock()
+	n.deBruijnMu.Unlock()
+	n.chordMu.Unlock()
+	n.lgr.Warn("stabilization paused", logger.F("duration", d.String()),
+		logger.F("keepFailureDetection", cfg.detectFailures))
+	return until
+}
+
+// StabilizationPaused reports whether the maintenance loops are paused.
+func (n *Node) StabilizationPaused() bool {
+	until := n.pausedUntil.Load()
+	return until != 0 && time.Now().UnixNano() < until
+}
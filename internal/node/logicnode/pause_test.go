@@ -0,0 +1,82 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPauseStabilization(t *testing.T) {
+	tests := []struct {
+		name         string
+		detect       bool
+		wantPredKept bool // il predecessore crashato resta in tabella durante la pausa
+	}{
+		{name: "paused", wantPredKept: true},
+		{name: "failure detection kept", detect: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 3)
+			pred, paused := r.Members[0], r.Members[1]
+
+			const pause = 2 * time.Second
+			until := paused.Node.PauseStabilization(pause, logicnode.KeepFailureDetection(tt.detect))
+			if !paused.Node.StabilizationPaused() {
+				t.Fatal("StabilizationPaused = false right after PauseStabilization")
+			}
+			succs := addrs(paused.Node.SuccessorList())
+			r.Kill(pred)
+
+			// Durante la pausa il predecessore crashato viene rimosso solo
+			// se la rilevazione dei guasti è rimasta attiva
+			deadline := time.Now().Add(pause / 2)
+			for time.Now().Before(deadline) {
+				if p := paused.Node.Predecessor(); p == nil || p.Addr != pred.Addr {
+					break
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+			p := paused.Node.Predecessor()
+			kept := p != nil && p.Addr == pred.Addr
+			if kept != tt.wantPredKept {
+				t.Fatalf("predecessor %v while paused: kept=%t, want %t", p, kept, tt.wantPredKept)
+			}
+			// Il resto della stabilizzazione è sospeso: la lista dei
+			// successori non cambia
+			if got := addrs(paused.Node.SuccessorList()); fmt.Sprint(got) != fmt.Sprint(succs) {
+				t.Fatalf("successor list changed while paused: got %v, want %v", got, succs)
+			}
+
+			// Allo scadere la stabilizzazione riprende da sola
+			time.Sleep(time.Until(until))
+			if paused.Node.StabilizationPaused() {
+				t.Fatal("StabilizationPaused = true after the pause expired")
+			}
+			r.WaitStable()
+		})
+	}
+}
+
+func TestPauseStabilizationResumeNow(t *testing.T) {
+	r := testring.New(t, 1)
+	n := r.Members[0].Node
+	n.PauseStabilization(time.Hour)
+	if until := n.PauseStabilization(0); !until.IsZero() || n.StabilizationPaused() {
+		t.Fatalf("PauseStabilization(0): until=%v paused=%t, want resumed", until, n.StabilizationPaused())
+	}
+}
+
+// addrs returns the addresses of list.
+func addrs(list []*domain.Node) []string {
+	out := make([]string, 0, len(list))
+	for _, n := range list {
+		if n != nil {
+			out = append(out, n.Addr)
+		}
+	}
+	return out
+}
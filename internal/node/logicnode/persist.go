@@ -0,0 +1,304 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// defaultRoutingSaveInterval is the interval at which a changed routing
+// table is saved when WithRoutingPersistence is given none.
+const defaultRoutingSaveInterval = 10 * time.Second
+
+// Routing table persistence (WithRoutingPersistence)
+//
+// A restarting node normally rebuilds its routing table from scratch with
+// a join and several stabilization rounds, during which lookups through it
+// fail or take extra hops. With persistence the routing table is saved to
+// a file whenever it changes, and a restart within a short time can resume
+// from it: the saved neighbors are pinged, the dead ones dropped, and the
+// node notifies its successor as at the end of a join, without looking
+// itself up through the ring. The regular stabilizers then correct
+// whatever changed while the node was down.
+
+// routingRecord is the on-disk representation of a routing table entry.
+type routingRecord struct {
+	ID   string `json:"id"` // hex-encoded identifier (0x-prefixed)
+	Addr string `json:"addr"`
+}
+
+// routingSnapshot is the on-disk representation of a routing table.
+type routingSnapshot struct {
+	Self        routingRecord   `json:"self"`
+	Predecessor *routingRecord  `json:"predecessor,omitempty"`
+	Successors  []routingRecord `json:"successors"`
+	DeBruijn    []routingRecord `json:"deBruijn,omitempty"`
+	SavedAt     time.Time       `json:"savedAt"`
+}
+
+func toRecord(nd *domain.Node) routingRecord {
+	return routingRecord{ID: nd.ID.ToHexString(true), Addr: nd.Addr}
+}
+
+func toRecords(nodes []*domain.Node) []routingRecord {
+	out := make([]routingRecord, 0, len(nodes))
+	for _, nd := range nodes {
+		if nd != nil {
+			out = append(out, toRecord(nd))
+		}
+	}
+	return out
+}
+
+// SaveRoutingTable atomically writes the routing table of the node to path.
+func (n *Node) SaveRoutingTable(path string) error {
+	snap := routingSnapshot{
+		Self:       toRecord(n.rt.Self()),
+		Successors: toRecords(n.rt.SuccessorList()),
+		DeBruijn:   toRecords(n.rt.DeBruijnList()),
+		SavedAt:    time.Now(),
+	}
+	if pred := n.rt.GetPredecessor(); pred != nil {
+		rec := toRecord(pred)
+		snap.Predecessor = &rec
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("routing table: encode: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".routing-*")
+	if err != nil {
+		return fmt.Errorf("routing table: write %s: %w", path, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("routing table: write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("routing table: write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("routing table: write %s: %w", path, err)
+	}
+	return nil
+}
+
+// RestoreRoutingTable loads the routing table saved at path, pings every
+// saved neighbor and installs the live ones, then pulls the owned range
+// from the successor (if pull-on-join is enabled) and notifies it, like the
+// last steps of Join. It reports false, leaving the routing table
+// untouched, if there is no saved table, if it belongs to another identity
+// (ID or address) or if no saved successor answers: the node must then
+// join the ring. The de Bruijn window is restored only if all its entries
+// answer, otherwise it is rebuilt at once.
+func (n *Node) RestoreRoutingTable(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("routing table: read %s: %w", path, err)
+	}
+	var snap routingSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return false, fmt.Errorf("routing table: decode %s: %w", path, err)
+	}
+	self := n.rt.Self()
+	if snap.Self != toRecord(self) {
+		n.lgr.Warn("routing table: saved table belongs to another identity, ignoring it",
+			logger.F("path", path), logger.F("saved_id", snap.Self.ID), logger.F("saved_addr", snap.Self.Addr))
+		return false, nil
+	}
+
+	// Ping every saved neighbor once, concurrently
+	var all []routingRecord
+	if snap.Predecessor != nil {
+		all = append(all, *snap.Predecessor)
+	}
+	all = append(append(all, snap.Successors...), snap.DeBruijn...)
+	alive := n.pingRecords(all)
+	live := func(recs []routingRecord) []*domain.Node {
+		out := make([]*domain.Node, 0, len(recs))
+		for _, rec := range recs {
+			if nd, ok := alive[rec.Addr]; ok && nd.ID.ToHexString(true) == rec.ID && nd.Addr != self.Addr {
+				out = append(out, nd)
+			}
+		}
+		return out
+	}
+	succs := live(snap.Successors)
+	if len(succs) == 0 {
+		n.lgr.Info("routing table: no saved successor is alive, a join is needed",
+			logger.F("path", path), logger.F("saved", len(snap.Successors)))
+		return false, nil
+	}
+	succs = succs[:min(len(succs), n.rt.SuccessorListSize())]
+	var pred *domain.Node
+	if snap.Predecessor != nil {
+		if p := live([]routingRecord{*snap.Predecessor}); len(p) == 1 {
+			pred = p[0]
+		}
+	}
+	window := live(snap.DeBruijn)
+
+	succ := succs[0]
+	cli, conn, err := n.cp.DialEphemeral(succ.Addr)
+	if err != nil {
+		return false, fmt.Errorf("routing table: failed to dial successor %s: %w", succ.Addr, err)
+	}
+	defer conn.Close()
+	if n.pullOnJoin && !n.observer {
+		if err := n.pullRange(cli, succ, pred); err != nil {
+			return false, fmt.Errorf("routing table: %w", err)
+		}
+	}
+
+	// Install the live entries, one pool reference per role and address
+	if pred != nil {
+		n.predMu.Lock()
+		if n.rt.GetPredecessor() == nil {
+			n.addRef(pred)
+			n.rt.SetPredecessor(pred)
+		}
+		n.predMu.Unlock()
+	}
+	for _, nd := range uniqueNodes(succs) {
+		n.addRef(nd)
+	}
+	n.rt.SetSuccessorList(succs)
+	restored := n.deBruijn && len(window) == n.rt.Space().GraphGrade
+	if restored {
+		for _, nd := range uniqueNodes(window) {
+			n.addRef(nd)
+		}
+		n.rt.SetDeBruijnList(window)
+	} else if !n.deBruijn {
+		n.rt.SetDeBruijn(0, nil)
+	}
+
+	if !n.observer {
+		ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+		err = client.Notify(ctx, cli, self)
+		cancel()
+		if err != nil {
+			n.lgr.Warn("routing table: failed to notify successor, left to stabilization",
+				logger.FNode("successor", succ), logger.F("err", err))
+		}
+	}
+	if n.deBruijn && !restored {
+		n.fixDeBruijn()
+	}
+
+	n.lgr.Info("routing table: restored",
+		logger.F("path", path),
+		logger.F("saved_at", snap.SavedAt),
+		logger.FNode("predecessor", pred),
+		logger.F("successors", len(succs)),
+		logger.F("deBruijn_restored", restored))
+	return true, nil
+}
+
+// pingRecords pings the distinct nodes of recs over ephemeral connections
+// and returns the ones that answered, by address.
+func (n *Node) pingRecords(recs []routingRecord) map[string]*domain.Node {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		alive = make(map[string]*domain.Node)
+		seen  = make(map[string]bool)
+	)
+	for _, rec := range recs {
+		if seen[rec.Addr] {
+			continue
+		}
+		seen[rec.Addr] = true
+		id, err := n.rt.Space().FromHexString(rec.ID)
+		if err != nil {
+			n.lgr.Warn("routing table: invalid saved ID, dropping entry",
+				logger.F("addr", rec.Addr), logger.F("err", err))
+			continue
+		}
+		nd := &domain.Node{ID: id, Addr: rec.Addr}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cli, conn, err := n.cp.DialEphemeral(nd.Addr)
+			if err == nil {
+				ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+				err = client.Ping(ctx, cli)
+				cancel()
+				conn.Close()
+			}
+			if err != nil {
+				n.lgr.Debug("routing table: saved neighbor unreachable, dropping it",
+					logger.FNode("node", nd), logger.F("err", err))
+				return
+			}
+			mu.Lock()
+			alive[nd.Addr] = nd
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return alive
+}
+
+// addRef adds a pool reference to nd, logging a failure.
+func (n *Node) addRef(nd *domain.Node) {
+	if err := n.cp.AddRef(nd.Addr); err != nil {
+		n.lgr.Warn("routing table: failed to add ref", logger.FNode("node", nd), logger.F("err", err))
+	}
+}
+
+// uniqueNodes returns the nodes with distinct addresses, in order.
+func uniqueNodes(nodes []*domain.Node) []*domain.Node {
+	seen := make(map[string]bool, len(nodes))
+	out := make([]*domain.Node, 0, len(nodes))
+	for _, nd := range nodes {
+		if !seen[nd.Addr] {
+			seen[nd.Addr] = true
+			out = append(out, nd)
+		}
+	}
+	return out
+}
+
+// persistLoop saves the routing table to n.rtFile every rtSaveInterval in
+// which it changed, and once more when ctx is canceled (shutdown), before
+// a graceful leave empties it.
+func (n *Node) persistLoop(ctx context.Context) {
+	ticker := time.NewTicker(n.rtSaveInterval)
+	defer ticker.Stop()
+	saved := ""
+	save := func() {
+		state := n.chordState() + "#" + n.deBruijnState()
+		if state == saved || n.rt.FirstSuccessor() == nil {
+			return
+		}
+		if err := n.SaveRoutingTable(n.rtFile); err != nil {
+			n.lgr.Warn("routing table: save failed", logger.F("path", n.rtFile), logger.F("err", err))
+			return
+		}
+		saved = state
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			save()
+			return
+		case <-ticker.C:
+			save()
+		}
+	}
+}
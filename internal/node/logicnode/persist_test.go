@@ -0,0 +1,129 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestRestoreRoutingTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		killSucc bool // il successore muore mentre il nodo è spento
+	}{
+		{name: "neighbors alive"},
+		{name: "dead successor dropped", killSucc: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 5)
+			r.WaitStable()
+			deadline := time.Now().Add(5 * time.Second)
+			for _, m := range r.Members {
+				for !succsConverged(r, m, min(len(r.Members)-1, m.Node.SuccessorListSize())) {
+					if time.Now().After(deadline) {
+						t.Fatal("successor lists did not converge")
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			}
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "routing.json")
+			m := r.Members[1]
+			if err := m.Node.SaveRoutingTable(path); err != nil {
+				t.Fatalf("SaveRoutingTable: %v", err)
+			}
+			r.Kill(m)
+			want := r.Members[2]
+			if tt.killSucc {
+				r.Kill(want)
+				want = r.Members[3]
+			}
+
+			// riavvio sullo stesso indirizzo, contando le lookup in uscita
+			var lookups atomic.Int64
+			count := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				if strings.Contains(method, "FindSuccessor") {
+					lookups.Add(1)
+				}
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}
+			lis, err := net.Listen("tcp", m.Addr)
+			if err != nil {
+				t.Fatalf("listen %s: %v", m.Addr, err)
+			}
+			self := m.Node.Self()
+			cp := client.New(self.ID, self.Addr, time.Second, client.WithUnaryInterceptors(count))
+			n := logicnode.New(routingtable.New(self, r.Space), cp, storage.NewMemoryStorage(&logger.NopLogger{}),
+				logicnode.WithRoutingPersistence(filepath.Join(dir, "saved.json"), 20*time.Millisecond))
+			srv, err := server.New(lis, n, nil)
+			if err != nil {
+				t.Fatalf("server: %v", err)
+			}
+			go func() { _ = srv.Start() }()
+			defer srv.Stop()
+			defer cp.Close()
+
+			restored, err := n.RestoreRoutingTable(path)
+			if err != nil || !restored {
+				t.Fatalf("RestoreRoutingTable = %v, %v, want true", restored, err)
+			}
+			if got := n.SuccessorList()[0]; got.Addr != want.Addr {
+				t.Fatalf("restored successor = %s, want %s", got.Addr, want.Addr)
+			}
+			if tt.killSucc {
+				if slices.ContainsFunc(n.SuccessorList(), func(nd *domain.Node) bool { return nd.Addr == r.Members[2].Addr }) {
+					t.Errorf("dead successor %s restored", r.Members[2].Addr)
+				}
+			} else {
+				// nessuna ricostruzione: né lookup né attesa della stabilizzazione
+				if got := lookups.Load(); got != 0 {
+					t.Errorf("restore issued %d lookups, want 0", got)
+				}
+				if got := want.Node.Predecessor(); got == nil || got.Addr != m.Addr {
+					t.Errorf("predecessor of the successor = %v, want %s", got, m.Addr)
+				}
+			}
+
+			// gli stabilizzatori completano la ripresa e salvano la tabella
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			n.StartStabilizers(ctx, 20*time.Millisecond, 20*time.Millisecond, 20*time.Millisecond)
+			deadline = time.Now().Add(5 * time.Second)
+			for {
+				p := want.Node.Predecessor()
+				_, err := os.Stat(filepath.Join(dir, "saved.json"))
+				if p != nil && p.Addr == m.Addr && err == nil {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("ring did not take the restarted node back (predecessor of %s = %v, saved: %v)", want.Addr, p, err)
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+		})
+	}
+
+	// senza tabella salvata serve il join
+	r := testring.New(t, 1)
+	if restored, err := r.Members[0].Node.RestoreRoutingTable(filepath.Join(t.TempDir(), "missing.json")); restored || err != nil {
+		t.Errorf("RestoreRoutingTable without a file = %v, %v, want false, nil", restored, err)
+	}
+}
@@ -0,0 +1,78 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReadRepair(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int
+		readRepair bool
+		fromOwner  bool // la Get parte dal responsabile (lettura locale)
+		found      bool
+	}{
+		{name: "remote owner", size: 4, readRepair: true, found: true},
+		{name: "local owner", size: 4, readRepair: true, fromOwner: true, found: true},
+		{name: "disabled", size: 4, readRepair: false},
+		{name: "single node", size: 1, readRepair: true, fromOwner: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, tt.size, testring.WithNodeOptions(
+				logicnode.WithReplicas(3), logicnode.WithReadRepair(tt.readRepair)))
+			res := putKeys(t, r, 8)[0]
+			r.StopStabilizers()
+
+			// il responsabile perde la sua copia, le repliche la conservano
+			owner := r.Owner(res.Key)
+			if err := owner.Node.RemoveLocal(res.Key); err != nil {
+				t.Fatalf("RemoveLocal: %v", err)
+			}
+			origin := owner
+			if !tt.fromOwner {
+				for _, m := range r.Members {
+					if m != owner {
+						origin = m
+						break
+					}
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			got, err := origin.Node.Get(ctx, res.Key)
+			if !tt.found {
+				if status.Code(err) != codes.NotFound {
+					t.Fatalf("Get: got %v, %v, want NotFound", got, err)
+				}
+				return
+			}
+			if err != nil || got.Value != res.Value {
+				t.Fatalf("Get: got %v, %v, want %q", got, err, res.Value)
+			}
+
+			// la copia viene salvata di nuovo sul responsabile in background
+			deadline := time.Now().Add(2 * time.Second)
+			for !holds(owner, res.Key) && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if !holds(owner, res.Key) {
+				t.Errorf("owner %s did not get key %s back", owner.Addr, res.RawKey)
+			}
+		})
+	}
+}
+
+func holds(m *testring.Member, id domain.ID) bool {
+	_, err := m.Node.RetrieveLocal(id)
+	return err == nil
+}
@@ -0,0 +1,71 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRebalance(t *testing.T) {
+	r := testring.New(t, 4)
+	r.StopStabilizers()
+	holder := r.Members[0]
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// risorse salvate su un nodo che non ne è responsabile
+	var misplaced []domain.Resource
+	for i := 0; len(misplaced) < 5; i++ {
+		raw := fmt.Sprintf("key-%d", i)
+		res := domain.Resource{Key: r.Space.NewIdFromString(raw), RawKey: raw, Value: "v-" + raw}
+		if r.Owner(res.Key) == holder {
+			continue
+		}
+		if err := holder.Node.StoreReplica(ctx, res); err != nil {
+			t.Fatalf("StoreReplica(%s): %v", raw, err)
+		}
+		misplaced = append(misplaced, res)
+	}
+
+	// primo passo: copia ai responsabili, la copia locale resta fino al prossimo
+	transferred, failed, err := holder.Node.Rebalance(ctx)
+	if err != nil || transferred != len(misplaced) || failed != 0 {
+		t.Fatalf("Rebalance: transferred=%d failed=%d err=%v, want %d, 0, nil", transferred, failed, err, len(misplaced))
+	}
+	for _, res := range misplaced {
+		if !holds(r.Owner(res.Key), res.Key) {
+			t.Errorf("owner of %s does not hold it after Rebalance", res.RawKey)
+		}
+	}
+
+	// secondo passo: le copie locali vengono rimosse
+	if _, _, err := holder.Node.Rebalance(ctx); err != nil {
+		t.Fatalf("second Rebalance: %v", err)
+	}
+	for _, res := range misplaced {
+		if holds(holder, res.Key) {
+			t.Errorf("%s still stored on the old holder", res.RawKey)
+		}
+	}
+	if transferred, failed, err := holder.Node.Rebalance(ctx); err != nil || transferred != 0 || failed != 0 {
+		t.Errorf("Rebalance with nothing to move: transferred=%d failed=%d err=%v", transferred, failed, err)
+	}
+
+	// un nodo senza predecessore non sa quali chiavi possiede
+	self := &domain.Node{ID: r.Space.NewIdFromString("uninitialized"), Addr: "127.0.0.1:2"}
+	cp := client.New(self.ID, self.Addr, time.Second)
+	defer cp.Close()
+	n := logicnode.New(routingtable.New(self, r.Space), cp, storage.NewMemoryStorage(&logger.NopLogger{}))
+	if _, _, err := n.Rebalance(ctx); !errors.Is(err, logicnode.ErrNoPredecessor) {
+		t.Errorf("Rebalance without predecessor: got %v, want ErrNoPredecessor", err)
+	}
+}
@@ -0,0 +1,405 @@
+package logicnode
+
+import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/ctxutil"
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Replication (WithReplicas)
+//
+// With a replication factor R > 1, every resource is stored on its owner
+// and on the owner's next R-1 successors (its replicas):
+//   - Put stores the resource on the owner and then, best-effort, on the
+//     replicas taken from the owner's successor list.
+//   - Get falls back to the replicas when the owner cannot be reached and,
+//     with read repair (WithReadRepair), when the owner does not hold the
+//     key: a copy found on a replica is returned and stored back on the
+//     owner in the background.
+//   - Delete removes the replicas as well (best-effort).
+//   - resourceRepair keeps replica copies instead of handing them off, and
+//     pushes the owned range again to replicas that joined the replica set
+//     (or to all of them when the owned range changed).
+
+// StoreReplica stores a replica copy of resource in the local storage,
+// without checking that this node owns its key. This method is invoked in
+// the node-to-node path (via StoreReplicas). Observers reject replicas.
+func (n *Node) StoreReplica(ctx context.Context, resource domain.Resource) error {
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return err
+	}
+	if n.observer {
+		return fmt.Errorf("storereplica: observer node: %w", domain.ErrNotResponsible)
+	}
+	n.s.Put(resource)
+	return nil
+}
+
+// clientFor returns a client for addr from the pool, or over an ephemeral
+// connection if the pool has none. The returned function releases it.
+func (n *Node) clientFor(addr string) (dhtv1.DHTClient, func(), error) {
+	if cli, err := n.cp.GetFromPool(addr); err == nil {
+		return cli, func() {}, nil
+	}
+	cli, conn, err := n.cp.DialEphemeral(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cli, func() { _ = conn.Close() }, nil
+}
+
+// replicaTargets returns the replicas of the keys owned by owner: the
+// first R-1 distinct nodes of its successor list other than owner itself.
+func (n *Node) replicaTargets(ctx context.Context, owner *domain.Node) ([]*domain.Node, error) {
+	if n.replicas <= 1 {
+		return nil, nil
+	}
+	var succs []*domain.Node
+	if owner.ID.Equal(n.rt.Self().ID) {
+		succs = n.rt.SuccessorList()
+	} else {
+		cli, release, err := n.clientFor(owner.Addr)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		succs, err = client.GetSuccessorList(ctx, cli, n.Space())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pickReplicas(owner, succs, n.replicas-1), nil
+}
+
+// LookUpOwnership returns the placement of id without reading or writing
+// any resource: the node owning it, the owner's predecessor (the owner
+// holds the identifiers in (pred, owner]; nil if it has none) and the
+// replicas of the owner, its next R-1 successors as reported by the owner
+// itself. With R = 1 replicas is empty.
+//
+// Errors: as LookUp, and ErrLookupFailed if the owner cannot report its
+// predecessor or successor list.
+func (n *Node) LookUpOwnership(ctx context.Context, id domain.ID) (owner, pred *domain.Node, replicas []*domain.Node, err error) {
+	if err := n.beginOp(ctx); err != nil {
+		return nil, nil, nil, err
+	}
+	defer n.ops.Done()
+
+	owner, err = n.findSuccessorRetry(ctx, id)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("lookupownership: key %s: %w: %w", id.ToHexString(true), ErrLookupFailed, err)
+	}
+	if owner == nil {
+		return nil, nil, nil, fmt.Errorf("lookupownership: key %s: %w", id.ToHexString(true), ErrNoSuccessor)
+	}
+	if owner.ID.Equal(n.rt.Self().ID) {
+		return owner, n.rt.GetPredecessor(), pickReplicas(owner, n.rt.SuccessorList(), n.replicas-1), nil
+	}
+
+	cli, release, err := n.clientFor(owner.Addr)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("lookupownership: owner %s: %w: %w", owner.Addr, ErrLookupFailed, err)
+	}
+	defer release()
+	pred, err = client.GetPredecessor(ctx, cli, n.Space())
+	if err != nil && !errors.Is(err, client.ErrNoPredecessor) {
+		return nil, nil, nil, fmt.Errorf("lookupownership: predecessor of %s: %w: %w", owner.Addr, ErrLookupFailed, err)
+	}
+	if n.replicas > 1 {
+		succs, err := client.GetSuccessorList(ctx, cli, n.Space())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("lookupownership: successors of %s: %w: %w", owner.Addr, ErrLookupFailed, err)
+		}
+		replicas = pickReplicas(owner, succs, n.replicas-1)
+	}
+	return owner, pred, replicas, nil
+}
+
+// pickReplicas returns up to count distinct nodes of succs other than owner.
+func pickReplicas(owner *domain.Node, succs []*domain.Node, count int) []*domain.Node {
+	targets := make([]*domain.Node, 0, count)
+	seen := map[string]struct{}{owner.Addr: {}}
+	for _, s := range succs {
+		if len(targets) == count {
+			break
+		}
+		if s == nil {
+			continue
+		}
+		if _, dup := seen[s.Addr]; dup {
+			continue
+		}
+		seen[s.Addr] = struct{}{}
+		targets = append(targets, s)
+	}
+	return targets
+}
+
+// storeReplicas pushes resources as replica copies to target (locally if
+// target is this node).
+func (n *Node) storeReplicas(ctx context.Context, target *domain.Node, resources []domain.Resource) error {
+	if target.ID.Equal(n.rt.Self().ID) {
+		for _, res := range resources {
+			if err := n.StoreReplica(ctx, res); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	cli, release, err := n.clientFor(target.Addr)
+	if err != nil {
+		return err
+	}
+	defer release()
+	failed, err := client.StoreReplicas(ctx, cli, resources)
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d replicas not sent", len(failed), len(resources))
+	}
+	return nil
+}
+
+// replicate stores res on the replicas of owner, best-effort: failures
+// are logged and do not fail the Put. It returns the number of replicas
+// that acknowledged the copy.
+func (n *Node) replicate(ctx context.Context, owner *domain.Node, res domain.Resource) int {
+	return n.replicateBatch(ctx, owner, []domain.Resource{res})
+}
+
+// replicateBatch stores resources, all owned by owner, on its replicas
+// with one Store stream per replica, best-effort like replicate. It
+// returns the number of replicas that acknowledged the whole batch.
+func (n *Node) replicateBatch(ctx context.Context, owner *domain.Node, resources []domain.Resource) int {
+	targets, err := n.replicaTargets(ctx, owner)
+	if err != nil {
+		n.lgr.Warn("Put: failed to determine replicas, resources stored on owner only",
+			logger.F("keys", len(resources)), logger.FNode("owner", owner), logger.F("err", err))
+		return 0
+	}
+	stored := 0
+	for _, t := range targets {
+		if err := n.storeReplicas(ctx, t, resources); err != nil {
+			n.lgr.Warn("Put: failed to store replicas",
+				logger.F("keys", len(resources)), logger.FNode("replica", t), logger.F("err", err))
+			continue
+		}
+		stored++
+	}
+	if stored < n.replicas-1 {
+		n.lgr.Warn("Put: resources under-replicated",
+			logger.F("keys", len(resources)), logger.F("replicas", stored+1), logger.F("want", n.replicas))
+	}
+	return stored
+}
+
+// removeReplicas deletes ids from the replicas of owner, best-effort.
+func (n *Node) removeReplicas(ctx context.Context, owner *domain.Node, ids ...domain.ID) {
+	targets, err := n.replicaTargets(ctx, owner)
+	if err != nil {
+		n.lgr.Warn("Delete: failed to determine replicas, replica copies left in place",
+			logger.F("keys", len(ids)), logger.FNode("owner", owner), logger.F("err", err))
+		return
+	}
+	for _, t := range targets {
+		if _, err := n.removeBatchAt(ctx, t, ids); err != nil {
+			n.lgr.Warn("Delete: failed to remove replicas",
+				logger.F("keys", len(ids)), logger.FNode("replica", t), logger.F("err", err))
+		}
+	}
+}
+
+// retrieveFromReplicas reads id from the replicas of an owner that could
+// not be reached. Candidates are the nodes following owner in this node's
+// successor list, if owner is in it, and otherwise the nodes found by
+// looking up the identifiers right after owner, one replica at a time.
+func (n *Node) retrieveFromReplicas(ctx context.Context, owner *domain.Node, id domain.ID) (*domain.Resource, error) {
+	if n.replicas <= 1 {
+		return nil, fmt.Errorf("replication disabled")
+	}
+	var candidates []*domain.Node
+	succs := n.rt.SuccessorList()
+	for i, s := range succs {
+		if s != nil && s.ID.Equal(owner.ID) {
+			candidates = pickReplicas(owner, succs[i+1:], n.replicas-1)
+			break
+		}
+	}
+
+	lastErr := fmt.Errorf("no replica found")
+	prev := owner
+	for i := 0; i < n.replicas-1; i++ {
+		var next *domain.Node
+		if i < len(candidates) {
+			next = candidates[i]
+		} else {
+			after, err := n.Space().AddMod(prev.ID, n.Space().FromUint64(1))
+			if err != nil {
+				return nil, err
+			}
+			next, err = n.FindSuccessorInit(ctx, after)
+			if err != nil {
+				return nil, fmt.Errorf("lookup of replica %d: %w", i+1, err)
+			}
+		}
+		if next == nil || next.ID.Equal(owner.ID) {
+			break // wrapped around the ring
+		}
+		prev = next
+
+		var res *domain.Resource
+		var err error
+		if next.ID.Equal(n.rt.Self().ID) {
+			var local domain.Resource
+			if local, err = n.RetrieveLocal(id); err == nil {
+				res = &local
+			}
+		} else {
+			cli, release, derr := n.clientFor(next.Addr)
+			if derr != nil {
+				lastErr = derr
+				continue
+			}
+			res, err = client.RetrieveRemote(ctx, cli, n.Space(), id)
+			release()
+		}
+		if err == nil {
+			n.lgr.Info("Get: resource retrieved from replica",
+				logger.F("key", id.ToHexString(true)), logger.FNode("owner", owner), logger.FNode("replica", next))
+			return res, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// ownerUnreachable reports whether err means that the owner itself could
+// not serve the request (so that a replica may), rather than a definitive
+// answer such as NotFound or DataLoss.
+func ownerUnreachable(err error) bool {
+	switch status.Code(err) {
+	case codes.NotFound, codes.DataLoss, codes.InvalidArgument, codes.Canceled:
+		return false
+	}
+	return true
+}
+
+// holdsReplica reports whether this node is one of the replicas of owner.
+// Successor lists are fetched once per owner and cached in cache.
+func (n *Node) holdsReplica(ctx context.Context, owner *domain.Node, cache map[string][]*domain.Node) bool {
+	targets, ok := cache[owner.Addr]
+	if !ok {
+		var err error
+		targets, err = n.replicaTargets(ctx, owner)
+		if err != nil {
+			n.lgr.Warn("ResourceRepair: failed to get replicas of owner",
+				logger.FNode("owner", owner), logger.F("err", err))
+		}
+		cache[owner.Addr] = targets
+	}
+	self := n.rt.Self()
+	for _, t := range targets {
+		if t.ID.Equal(self.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// replicateOwned pushes the resources owned by this node, i.e. in
+// (pred, self], to the replicas that have not received them yet: all of
+// them if the owned range changed since the last push (a new predecessor),
+// otherwise only those that joined the replica set (membership changes).
+func (n *Node) replicateOwned(ctx context.Context, pred *domain.Node) {
+	if n.replicas <= 1 || n.observer {
+		return
+	}
+	self := n.rt.Self()
+	targets, _ := n.replicaTargets(ctx, self)
+	if len(targets) == 0 {
+		return
+	}
+
+	n.replicaMu.Lock()
+	defer n.replicaMu.Unlock()
+	if n.replicaPred == nil || !n.replicaPred.Equal(pred.ID) {
+		n.replicaPushed = make(map[string]struct{})
+		n.replicaPred = pred.ID
+	}
+
+	owned := n.s.Between(pred.ID, self.ID)
+	pushed := make(map[string]struct{}, len(targets))
+	for _, t := range targets {
+		if _, ok := n.replicaPushed[t.Addr]; ok {
+			pushed[t.Addr] = struct{}{}
+			continue
+		}
+		if len(owned) > 0 {
+			if err := n.storeReplicas(ctx, t, owned); err != nil {
+				n.lgr.Warn("ResourceRepair: failed to re-replicate owned range",
+					logger.FNode("replica", t), logger.F("count", len(owned)), logger.F("err", err))
+				continue
+			}
+			n.lgr.Info("ResourceRepair: owned range replicated",
+				logger.FNode("replica", t), logger.F("count", len(owned)))
+		}
+		pushed[t.Addr] = struct{}{}
+	}
+	// forget replicas that left the set, so they are refilled if they come back
+	n.replicaPushed = pushed
+}
+
+// repairFromReplicas is called when owner answered that it does not hold
+// id (WithReadRepair): it reads id from the replicas of owner and, if one
+// of them has it, returns it and stores it back on owner in the
+// background. It reports false if read repair or replication is disabled,
+// if this is the only node of the ring, or if no replica has the key.
+func (n *Node) repairFromReplicas(ctx context.Context, owner *domain.Node, id domain.ID) (*domain.Resource, bool) {
+	if !n.readRepair || n.replicas <= 1 {
+		return nil, false
+	}
+	if succ := n.rt.FirstSuccessor(); succ == nil || succ.ID.Equal(n.rt.Self().ID) {
+		return nil, false // single-node ring: there are no replicas
+	}
+	res, err := n.retrieveFromReplicas(ctx, owner, id)
+	if err != nil {
+		return nil, false
+	}
+	go n.readRepairOwner(owner, *res)
+	return res, true
+}
+
+// readRepairOwner stores res back on its owner, which had lost it.
+func (n *Node) readRepairOwner(owner *domain.Node, res domain.Resource) {
+	ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+	defer cancel()
+	var err error
+	if owner.ID.Equal(n.rt.Self().ID) {
+		err = n.StoreLocal(ctx, res)
+	} else {
+		cli, release, derr := n.clientFor(owner.Addr)
+		if derr != nil {
+			err = derr
+		} else {
+			_, err = client.StoreRemote(ctx, cli, []domain.Resource{res})
+			release()
+		}
+	}
+	if err != nil {
+		n.lgr.Warn("Get: read repair failed",
+			logger.F("key", res.Key.ToHexString(true)), logger.FNode("owner", owner), logger.F("err", err))
+		return
+	}
+	n.lgr.Info("Get: read repair stored the resource back on its owner",
+		logger.F("key", res.Key.ToHexString(true)), logger.FNode("owner", owner),
+		logger.F("repairs", n.readRepairs.Add(1)))
+}
@@ -0,0 +1,134 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+const replicas = 3
+
+// holders restituisce i membri vivi che hanno una copia di id
+func holders(r *testring.Ring, id domain.ID) []*testring.Member {
+	var out []*testring.Member
+	for _, m := range r.Live() {
+		if _, err := m.Node.RetrieveLocal(id); err == nil {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// expectedHolders restituisce il responsabile di id e i suoi replicas-1 successori vivi
+func expectedHolders(r *testring.Ring, id domain.ID) map[string]bool {
+	live := r.Live()
+	owner := r.Owner(id)
+	start := 0
+	for i, m := range live {
+		if m == owner {
+			start = i
+		}
+	}
+	want := make(map[string]bool, replicas)
+	for i := 0; i < replicas && i < len(live); i++ {
+		want[live[(start+i)%len(live)].Addr] = true
+	}
+	return want
+}
+
+func putKeys(t *testing.T, r *testring.Ring, n int) []domain.Resource {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res := make([]domain.Resource, n)
+	for i := range res {
+		raw := fmt.Sprintf("key-%d", i)
+		res[i] = domain.Resource{Key: r.Space.NewIdFromString(raw), RawKey: raw, Value: "v-" + raw}
+		if err := r.Members[i%len(r.Members)].Node.Put(ctx, res[i]); err != nil {
+			t.Fatalf("Put(%s): %v", raw, err)
+		}
+	}
+	return res
+}
+
+func TestPutStoresReplicasOnSuccessors(t *testing.T) {
+	r := testring.New(t, 5, testring.WithNodeOptions(logicnode.WithReplicas(replicas)))
+	for _, res := range putKeys(t, r, 16) {
+		want := expectedHolders(r, res.Key)
+		got := holders(r, res.Key)
+		if len(got) != replicas {
+			t.Errorf("key %s stored on %d nodes, want %d", res.RawKey, len(got), replicas)
+		}
+		for _, m := range got {
+			if !want[m.Addr] {
+				t.Errorf("key %s stored on %s, which is not among its owner and %d successors", res.RawKey, m.Addr, replicas-1)
+			}
+		}
+	}
+}
+
+func TestGetFallsBackToReplica(t *testing.T) {
+	r := testring.New(t, 5, testring.WithNodeOptions(logicnode.WithReplicas(replicas)))
+	stored := putKeys(t, r, 16)
+	r.StopStabilizers()
+	time.Sleep(50 * time.Millisecond) // lascia terminare i round di stabilizzazione in volo
+
+	res := stored[0]
+	owner := r.Owner(res.Key)
+	r.Kill(owner)
+	// Il predecessore del responsabile lo ha ancora come successore
+	live := r.Live()
+	origin := live[len(live)-1]
+	for i, m := range live {
+		if m.Node.Self().ID.Cmp(owner.Node.Self().ID) > 0 {
+			origin = live[(i-1+len(live))%len(live)]
+			break
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	got, err := origin.Node.Get(ctx, res.Key)
+	if err != nil {
+		t.Fatalf("Get(%s) from %s with owner %s down: %v", res.RawKey, origin.Addr, owner.Addr, err)
+	}
+	if got.Value != res.Value {
+		t.Errorf("Get(%s) = %q, want %q", res.RawKey, got.Value, res.Value)
+	}
+}
+
+func TestRepairRestoresReplicationFactor(t *testing.T) {
+	r := testring.New(t, 6, testring.WithNodeOptions(logicnode.WithReplicas(replicas)))
+	stored := putKeys(t, r, 16)
+
+	r.Kill(r.Members[2])
+	r.WaitStable()
+
+	// Dopo la riparazione ogni chiave torna ad avere esattamente replicas copie
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var bad []string
+		for _, res := range stored {
+			want := expectedHolders(r, res.Key)
+			got := holders(r, res.Key)
+			ok := len(got) == replicas
+			for _, m := range got {
+				ok = ok && want[m.Addr]
+			}
+			if !ok {
+				bad = append(bad, fmt.Sprintf("%s on %d nodes", res.RawKey, len(got)))
+			}
+		}
+		if len(bad) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("replication factor not restored: %v", bad)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,74 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSuccessorListFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback bool
+	}{
+		{name: "fallback", fallback: true},
+		{name: "disabled", fallback: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GetSuccessorList verso il nodo bloccato fallisce
+			var blocked atomic.Value
+			blocked.Store("")
+			fail := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				if strings.HasSuffix(method, "/GetSuccessorList") && cc.Target() == blocked.Load() {
+					return status.Error(codes.Unavailable, "blocked")
+				}
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}
+			r := testring.New(t, 6,
+				testring.WithNodeOptions(logicnode.WithSuccessorListFallback(tt.fallback)),
+				testring.WithPoolOptions(client.WithUnaryInterceptors(fail)))
+			r.StopStabilizers()
+
+			m := r.Members[0]
+			want := m.Node.SuccessorList()
+			if len(want) < 4 {
+				t.Fatalf("successor list has %d entries, want 4", len(want))
+			}
+			// lista non aggiornata: solo i primi due successori
+			m.Node.SetSuccessorList([]*domain.Node{want[0], want[1], nil, nil})
+			blocked.Store(want[0].Addr)
+
+			m.Node.FixSuccessorList()
+			got := m.Node.SuccessorList()
+			if !tt.fallback {
+				if len(got) != 2 {
+					t.Errorf("without fallback the list changed: got %d entries, want 2", len(got))
+				}
+				return
+			}
+			if len(got) != len(want) {
+				t.Fatalf("successor list has %d entries, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if !got[i].ID.Equal(want[i].ID) {
+					t.Errorf("successor %d = %s, want %s", i, got[i].Addr, want[i].Addr)
+				}
+			}
+			for _, nd := range got {
+				if !m.Node.Pooled(nd.Addr) {
+					t.Errorf("successor %s not in the client pool", nd.Addr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"context"
+)
+
+// defaultAsymmetryRounds is the number of consecutive stabilization rounds
+// after which a successor that does not acknowledge this node as its
+// predecessor is reported (see WithAsymmetryCheck).
+const defaultAsymmetryRounds = 3
+
+// Routing table symmetry (WithAsymmetryCheck)
+//
+// After a stabilization round that keeps the same successor, the
+// successor's predecessor should be this node: any node between the two
+// would have been adopted as successor, and the Notify of the previous
+// round should have replaced any node behind this one. If it is not, the
+// relationship is asymmetric: this node routes to a successor that does
+// not route back (a Notify that never arrives, an ID collision, a stale
+// predecessor that the successor keeps touching). A single round of
+// asymmetry is normal right after a join; one that lasts
+// asymmetryRounds consecutive rounds is logged and, if requested, the
+// Notify is sent again over a fresh connection instead of the pooled one,
+// which may be the one silently losing it.
+
+// checkSymmetry updates the count of consecutive asymmetric rounds given
+// the successor at the start of the round (before), the one after
+// stabilization (succ) and the predecessor that before reported (pred).
+// It reports whether the Notify of this round must be forced. Called by
+// stabilizeSuccessor, serialized by chordMu.
+func (n *Node) checkSymmetry(before, succ, pred *domain.Node) bool {
+	self := n.rt.Self()
+	if n.asymRounds <= 0 || n.observer || succ.ID.Equal(self.ID) ||
+		succ.Addr != before.Addr || (pred != nil && pred.ID.Equal(self.ID) && pred.Addr == self.Addr) {
+		if n.asymStreak >= n.asymRounds && n.asymRounds > 0 {
+			n.lgr.Info("stabilize: successor acknowledges this node as predecessor again",
+				logger.FNode("succ", succ), logger.F("rounds", n.asymStreak))
+		}
+		n.asymStreak = 0
+		return false
+	}
+	n.asymStreak++
+	if n.asymStreak < n.asymRounds {
+		return false
+	}
+	if n.asymStreak%n.asymRounds == 0 {
+		n.lgr.Warn("stabilize: successor does not acknowledge this node as predecessor",
+			logger.FNode("succ", succ),
+			logger.FNode("succ_pred", pred),
+			logger.F("rounds", n.asymStreak))
+	}
+	return n.asymRenotify
+}
+
+// renotify sends the Notify to succ over an ephemeral connection.
+func (n *Node) renotify(ctx context.Context, succ *domain.Node) {
+	n.lgr.Info("stabilize: forcing notify of successor over a fresh connection",
+		logger.FNode("succ", succ), logger.F("rounds", n.asymStreak))
+	cli, conn, err := n.cp.DialEphemeral(succ.Addr)
+	if err != nil {
+		n.lgr.Warn("stabilize: forced notify failed",
+			logger.FNode("succ", succ), logger.F("err", err))
+		return
+	}
+	defer conn.Close()
+	if err := client.Notify(ctx, cli, n.rt.Self()); err != nil {
+		n.lgr.Warn("stabilize: forced notify failed",
+			logger.FNode("succ", succ), logger.F("err", err))
+	}
+}
@@ -0,0 +1,142 @@
+package logicnode_test
+
+import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recordLogger registra i messaggi di tutti i logger derivati
+type recordLogger struct {
+	mu   *sync.Mutex
+	msgs *[]string
+}
+
+func newRecordLogger() recordLogger {
+	return recordLogger{mu: &sync.Mutex{}, msgs: &[]string{}}
+}
+
+func (l recordLogger) record(level, msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.msgs = append(*l.msgs, level+" "+msg)
+}
+
+// count restituisce quanti messaggi contengono s
+func (l recordLogger) count(s string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c := 0
+	for _, m := range *l.msgs {
+		if strings.Contains(m, s) {
+			c++
+		}
+	}
+	return c
+}
+
+func (l recordLogger) Named(string) logger.Logger          { return l }
+func (l recordLogger) With(...logger.Field) logger.Logger  { return l }
+func (l recordLogger) WithNode(domain.Node) logger.Logger  { return l }
+func (l recordLogger) Debug(msg string, _ ...logger.Field) { l.record("DEBUG", msg) }
+func (l recordLogger) Info(msg string, _ ...logger.Field)  { l.record("INFO", msg) }
+func (l recordLogger) Warn(msg string, _ ...logger.Field)  { l.record("WARN", msg) }
+func (l recordLogger) Error(msg string, _ ...logger.Field) { l.record("ERROR", msg) }
+
+func TestAsymmetricSuccessorDetected(t *testing.T) {
+	const rounds = 3
+	// conta le Notify inviate da sender e, finché drop è attivo, le scarta
+	var sender atomic.Value
+	sender.Store("")
+	var drop atomic.Bool
+	var notifies atomic.Int32
+	intercept := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if nd, ok := req.(*dhtv1.Node); ok && strings.HasSuffix(info.FullMethod, "/Notify") && nd.Address == sender.Load() {
+			notifies.Add(1)
+			if drop.Load() {
+				return nil, status.Error(codes.Internal, "notify dropped")
+			}
+		}
+		return handler(ctx, req)
+	}
+	lgr := newRecordLogger()
+	r := testring.New(t, 3,
+		testring.WithLogger(lgr),
+		testring.WithServerOptions(server.WithUnaryInterceptors(intercept)),
+		testring.WithNodeOptions(logicnode.WithAsymmetryCheck(rounds, true)))
+	r.WaitStable()
+	r.StopStabilizers()
+
+	// coppia incoerente: a punta a b, ma b considera predecessore il
+	// predecessore di a e le Notify di a non arrivano
+	a := r.Members[0]
+	a.Node.ChordRound() // attende l'eventuale round ancora in corso
+	sender.Store(a.Addr)
+	var b, c *testring.Member
+	for _, m := range r.Members {
+		switch m.Addr {
+		case a.Node.SuccessorList()[0].Addr:
+			b = m
+		case a.Node.Predecessor().Addr:
+			c = m
+		}
+	}
+	if b == nil || c == nil || b == c {
+		t.Fatalf("unexpected ring layout")
+	}
+	b.Node.SetPredecessor(c.Node.Self())
+	drop.Store(true)
+
+	const warn = "WARN stabilize: successor does not acknowledge this node as predecessor"
+	const forced = "stabilize: forcing notify of successor over a fresh connection"
+	for i := 1; i <= rounds; i++ {
+		before := notifies.Load()
+		a.Node.ChordRound()
+		if got := notifies.Load() - before; got != 1 {
+			t.Errorf("round %d: successor received %d notifies, want 1", i, got)
+		}
+		wantWarn := 0
+		if i == rounds {
+			wantWarn = 1
+		}
+		if got := lgr.count(warn); got != wantWarn {
+			t.Fatalf("round %d: %d asymmetry warnings, want %d", i, got, wantWarn)
+		}
+		if got := lgr.count(forced); got != wantWarn {
+			t.Fatalf("round %d: %d forced notifies, want %d", i, got, wantWarn)
+		}
+	}
+	if got := b.Node.Predecessor(); got.Addr != c.Addr {
+		t.Fatalf("predecessor of the successor = %s, want %s (notifies are dropped)", got.Addr, c.Addr)
+	}
+
+	// la Notify forzata del round successivo viene accettata e la coppia
+	// torna coerente
+	drop.Store(false)
+	a.Node.ChordRound()
+	if got := lgr.count(forced); got != 2 {
+		t.Fatalf("%d forced notifies, want 2", got)
+	}
+	if got := b.Node.Predecessor(); got.Addr != a.Addr {
+		t.Fatalf("predecessor of the successor = %s, want %s", got.Addr, a.Addr)
+	}
+	a.Node.ChordRound()
+	if got := lgr.count("successor acknowledges this node as predecessor again"); got != 1 {
+		t.Errorf("%d recovery messages, want 1", got)
+	}
+	if got := lgr.count(forced); got != 2 {
+		t.Errorf("%d forced notifies after recovery, want 2", got)
+	}
+}
@@ -0,0 +1,127 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFindSuccessorStepTracesDecision(t *testing.T) {
+	// I passi di lookup verso gli indirizzi bloccati falliscono
+	var blocked atomic.Pointer[map[string]bool]
+	block := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if m := blocked.Load(); m != nil && (*m)[cc.Target()] && strings.Contains(method, "FindSuccessor") {
+			return status.Error(codes.Unavailable, "blocked by test")
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	r := testring.New(t, 6, testring.WithPoolOptions(client.WithUnaryInterceptors(block)))
+	r.WaitStable()
+	for _, m := range r.Members {
+		m.Node.FixDeBruijn()
+	}
+
+	origin, target, currentI, kshift, hop := remoteDeBruijnStep(t, r, 1)
+	deBruijn := make(map[string]bool)
+	for _, c := range hop.Candidates[:len(hop.Candidates)-1] {
+		deBruijn[c.Addr] = true
+	}
+
+	tests := []struct {
+		name         string
+		block        map[string]bool
+		wantDecision string
+		wantIndex    int
+		wantTried    int
+	}{
+		{name: "de Bruijn hop", wantDecision: "debruijn", wantIndex: hop.DeBruijnIdx[0], wantTried: 1},
+		{name: "fallback to successor", block: deBruijn, wantDecision: "fallback", wantIndex: -1, wantTried: len(hop.Candidates)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocked.Store(&tt.block)
+			defer blocked.Store(nil)
+
+			rec := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			ctx, span := tp.Tracer("test").Start(ctx, "FindSuccessorStep")
+			// l'esito del lookup non conta: si verifica solo la decisione dell'origine
+			_, _ = origin.Node.FindSuccessorStep(ctx, target, currentI, kshift)
+			span.End()
+
+			ended := rec.Ended()
+			if len(ended) != 1 {
+				t.Fatalf("recorded %d spans, want 1", len(ended))
+			}
+			attrs := make(map[attribute.Key]attribute.Value)
+			for _, kv := range ended[0].Attributes() {
+				attrs[kv.Key] = kv.Value
+			}
+			if got := attrs["dht.findsucc.decision"].AsString(); got != tt.wantDecision {
+				t.Errorf("decision = %q, want %q", got, tt.wantDecision)
+			}
+			if got := attrs["dht.findsucc.fallback"].AsBool(); got != (tt.wantDecision == "fallback") {
+				t.Errorf("fallback = %v", got)
+			}
+			if got := int(attrs["dht.findsucc.debruijn.index"].AsInt64()); got != tt.wantIndex {
+				t.Errorf("de Bruijn index = %d, want %d", got, tt.wantIndex)
+			}
+			if got := int(attrs["dht.findsucc.candidates.tried"].AsInt64()); got != tt.wantTried {
+				t.Errorf("candidates tried = %d, want %d", got, tt.wantTried)
+			}
+			if got := attrs["dht.findsucc.nextI.hex"].AsString(); got != hop.CurrentI.ToHexString(true) {
+				t.Errorf("nextI = %q, want %s", got, hop.CurrentI.ToHexString(true))
+			}
+		})
+	}
+}
+
+// remoteDeBruijnStep cerca un passo di lookup in cui l'origine applica il
+// routing de Bruijn con almeno minCand candidati, tutti diversi da sé
+// stessa e dal proprio successore e già connessi nel pool; salta il test
+// se non lo trova.
+func remoteDeBruijnStep(t *testing.T, r *testring.Ring, minCand int) (origin *testring.Member, target, currentI, kshift domain.ID, hop *domain.NextHop) {
+	t.Helper()
+	for i := 0; i < 1<<12; i++ {
+		id := r.Space.NewIdFromString(fmt.Sprintf("target-%d", i))
+		for _, m := range r.Members {
+			self, succ := m.Node.Self(), m.Node.SuccessorList()[0]
+			// nodi immaginari a distanza crescente da self dentro (self, succ]:
+			// i più lontani scelgono candidati più avanti nella lista de Bruijn
+			for shift := 0; shift < r.Space.Bits; shift++ {
+				ci, err := r.Space.AddMod(self.ID, r.Space.FromUint64(1<<shift))
+				if err != nil || !ci.Between(self.ID, succ.ID) {
+					break
+				}
+				h, err := m.Node.NextHopStep(context.Background(), id, ci, id)
+				if err != nil || !h.Shifted || len(h.Candidates)-1 < minCand {
+					continue
+				}
+				ok := true
+				for _, c := range h.Candidates[:len(h.Candidates)-1] {
+					ok = ok && c.Addr != self.Addr && c.Addr != succ.Addr && m.Node.Pooled(c.Addr)
+				}
+				if ok {
+					return m, id, ci, id, h
+				}
+			}
+		}
+	}
+	t.Skip("no lookup step with remote de Bruijn candidates")
+	return
+}
@@ -0,0 +1,51 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPutPreservesExpiryAcrossNodes(t *testing.T) {
+	r := testring.New(t, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	tests := []struct {
+		name string
+		ttl  time.Duration
+	}{
+		{name: "no ttl", ttl: 0},
+		{name: "with ttl", ttl: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := "ttl-" + tt.name
+			res := domain.Resource{Key: r.Space.NewIdFromString(raw), RawKey: raw, Value: "v"}.WithTTL(now, tt.ttl)
+			owner := r.Owner(res.Key)
+			// Put da un nodo diverso dal responsabile: la scadenza passa per la Store RPC
+			origin := r.Members[0]
+			if origin == owner {
+				origin = r.Members[1]
+			}
+			if err := origin.Node.Put(ctx, res); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			got, err := owner.Node.RetrieveLocal(res.Key)
+			if err != nil {
+				t.Fatalf("RetrieveLocal on owner: %v", err)
+			}
+			// la scadenza viaggia in millisecondi
+			var want time.Time
+			if tt.ttl > 0 {
+				want = time.UnixMilli(res.Expiry.UnixMilli())
+			}
+			if !got.Expiry.Equal(want) {
+				t.Errorf("expiry on owner = %v, want %v", got.Expiry, want)
+			}
+		})
+	}
+}
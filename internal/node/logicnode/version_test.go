@@ -0,0 +1,84 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestVersionsOrderCopies(t *testing.T) {
+	r := testring.New(t, 4, testring.WithNodeOptions(logicnode.WithReplicas(replicas)))
+	r.StopStabilizers()
+	res := putKeys(t, r, 8)
+
+	for _, want := range res {
+		copies := holders(r, want.Key)
+		if len(copies) != replicas {
+			t.Fatalf("key %s stored on %d nodes, want %d", want.RawKey, len(copies), replicas)
+		}
+		// tutte le copie portano la versione assegnata dal nodo che ha accettato la Put
+		var version uint64
+		for _, m := range copies {
+			got, err := m.Node.RetrieveLocal(want.Key)
+			if err != nil {
+				t.Fatalf("RetrieveLocal(%s) on %s: %v", want.RawKey, m.Addr, err)
+			}
+			if got.Version == 0 {
+				t.Fatalf("key %s on %s has no version", want.RawKey, m.Addr)
+			}
+			if version != 0 && got.Version != version {
+				t.Errorf("key %s: version %d on %s, want %d as on the other copies", want.RawKey, got.Version, m.Addr, version)
+			}
+			version = got.Version
+		}
+
+		// una copia più vecchia spinta come replica non sovrascrive quella attuale
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		stale := domain.Resource{Key: want.Key, RawKey: want.RawKey, Value: "stale", Version: version - 1}
+		owner := r.Owner(want.Key)
+		err := owner.Node.StoreReplica(ctx, stale)
+		cancel()
+		if err != nil {
+			t.Fatalf("StoreReplica(%s): %v", want.RawKey, err)
+		}
+		if got, err := owner.Node.RetrieveLocal(want.Key); err != nil || got.Value != want.Value {
+			t.Errorf("key %s after a stale replica: got %q, %v, want %q", want.RawKey, got.Value, err, want.Value)
+		}
+	}
+}
+
+func TestPutSupersedesNewerVersion(t *testing.T) {
+	r := testring.New(t, 4, testring.WithNodeOptions(logicnode.WithReplicas(replicas)))
+	r.StopStabilizers()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const ahead = uint64(1) << 62
+	for i, old := range putKeys(t, r, 8) {
+		// le copie portano la versione di una scrittura accettata da un nodo
+		// con l'orologio molto avanti rispetto a quello degli altri
+		copies := holders(r, old.Key)
+		for _, m := range copies {
+			if err := m.Node.StoreReplica(ctx, domain.Resource{Key: old.Key, RawKey: old.RawKey, Value: "ahead", Version: ahead}); err != nil {
+				t.Fatalf("StoreReplica(%s) on %s: %v", old.RawKey, m.Addr, err)
+			}
+		}
+
+		// la Put successiva, anche con una versione vecchia indicata dal
+		// chiamante, si applica sopra di essa su tutte le copie
+		entry := r.Members[(i+1)%len(r.Members)]
+		want := domain.Resource{Key: old.Key, RawKey: old.RawKey, Value: "new", Version: 1}
+		if err := entry.Node.Put(ctx, want); err != nil {
+			t.Fatalf("Put(%s) via %s: %v", want.RawKey, entry.Addr, err)
+		}
+		for _, m := range copies {
+			got, err := m.Node.RetrieveLocal(want.Key)
+			if err != nil || got.Value != want.Value || got.Version != ahead+1 {
+				t.Errorf("key %s on %s: got %q v%d, %v, want %q v%d", want.RawKey, m.Addr, got.Value, got.Version, err, want.Value, ahead+1)
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package logicnode
+
+import (
+	"KoordeDHT/internal/domain"
+	"fmt"
+	"sync"
+)
+
+// Virtual nodes (WithVNodeGroup)
+//
+// A physical node with more capacity than the others can take a larger
+// share of the key space by running several virtual nodes, each with its
+// own ID, address, routing table and stabilizers, but all sharing the same
+// client pool and storage. Since the storage is shared, a resource owned by
+// a sibling is already where it belongs: it is never transferred to the
+// sibling nor deleted after such a transfer (see sibling and
+// ownedBySibling), and a node that leaves the ring hands over only its own
+// range.
+
+// VNodeGroup is the set of virtual nodes run by one physical node.
+type VNodeGroup struct {
+	mu    sync.RWMutex
+	nodes []*Node
+}
+
+// NewVNodeGroup returns an empty group of virtual nodes.
+func NewVNodeGroup() *VNodeGroup {
+	return &VNodeGroup{}
+}
+
+// Nodes returns the virtual nodes of the group, in creation order.
+func (g *VNodeGroup) Nodes() []*Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return append([]*Node(nil), g.nodes...)
+}
+
+func (g *VNodeGroup) add(n *Node) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.nodes = append(g.nodes, n)
+}
+
+// VNodeID derives the identifier of the i-th virtual node (i >= 1) of the
+// physical node advertised at addr; virtual node 0 keeps the ID of the
+// physical node.
+func VNodeID(sp domain.Space, addr string, i int) domain.ID {
+	return sp.NewIdFromString(fmt.Sprintf("%s#%d", addr, i))
+}
+
+// VNodes returns the other virtual nodes of the physical node running this
+// one (nil without virtual nodes, see WithVNodeGroup).
+func (n *Node) VNodes() []*Node {
+	if n.vnodes == nil {
+		return nil
+	}
+	var out []*Node
+	for _, o := range n.vnodes.Nodes() {
+		if o != n {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// sibling reports whether nd is another virtual node of the same physical
+// node that still serves its range (it has not started leaving the ring).
+func (n *Node) sibling(nd *domain.Node) bool {
+	for _, o := range n.VNodes() {
+		if self := o.rt.Self(); self.Addr == nd.Addr && self.ID.Equal(nd.ID) {
+			return !o.isLeaving()
+		}
+	}
+	return false
+}
+
+// ownedBySibling reports whether key falls in the range (pred, self] of
+// another virtual node of the same physical node that still serves it.
+func (n *Node) ownedBySibling(key domain.ID) bool {
+	for _, o := range n.VNodes() {
+		pred := o.rt.GetPredecessor()
+		if pred != nil && key.Between(pred.ID, o.rt.Self().ID) && !o.isLeaving() {
+			return true
+		}
+	}
+	return false
+}
+
+// isLeaving reports whether Leave has started.
+func (n *Node) isLeaving() bool {
+	n.leaveMu.RLock()
+	defer n.leaveMu.RUnlock()
+	return n.leaving
+}
@@ -0,0 +1,92 @@
+package logicnode_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestVirtualNodes(t *testing.T) {
+	r := testring.New(t, 2)
+	vs := r.AddVNodes(3)
+	r.WaitStable()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// GetRoutingTable di un nodo virtuale riporta anche gli altri
+	api, conn, err := client.Connect(vs[0].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	rt, _, err := client.GetRoutingTable(ctx, api)
+	if err != nil {
+		t.Fatalf("GetRoutingTable: %v", err)
+	}
+	if len(rt.Vnodes) != len(vs)-1 {
+		t.Fatalf("GetRoutingTable reports %d other vnodes, want %d", len(rt.Vnodes), len(vs)-1)
+	}
+	for i, v := range rt.Vnodes {
+		if self := vs[i+1].Node.Self(); v.GetSelf().GetAddr() != self.Addr || v.GetSuccessors() == nil {
+			t.Errorf("vnode %d: got self %v, want %s with its successors", i+1, v.GetSelf(), self.Addr)
+		}
+	}
+
+	keys := make([]string, 120)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("vnode-%d", i)
+		res := domain.Resource{Key: r.Space.NewIdFromString(keys[i]), RawKey: keys[i], Value: keys[i]}
+		if err := r.Members[0].Node.Put(ctx, res); err != nil {
+			t.Fatalf("Put(%s): %v", keys[i], err)
+		}
+	}
+
+	// owned conta le chiavi di cui sono responsabili i nodi virtuali vivi
+	owned := func() int {
+		c := 0
+		for _, k := range keys {
+			if slices.Contains(vs, r.Owner(r.Space.NewIdFromString(k))) {
+				c++
+			}
+		}
+		return c
+	}
+	// checkShared verifica che lo storage condiviso contenga esattamente le
+	// chiavi dei nodi virtuali (le riparazioni non le spostano tra fratelli)
+	// e che ogni chiave resti leggibile
+	checkShared := func(phase string, live *testring.Member) {
+		t.Helper()
+		want := owned()
+		deadline := time.Now().Add(5 * time.Second)
+		for len(live.Node.GetAllResourceStored()) != want && time.Now().Before(deadline) {
+			time.Sleep(20 * time.Millisecond)
+		}
+		time.Sleep(100 * time.Millisecond) // qualche round di riparazione
+		if got := len(live.Node.GetAllResourceStored()); got != want {
+			t.Fatalf("%s: shared storage holds %d keys, want the %d owned by the vnodes", phase, got, want)
+		}
+		if want == 0 || want == len(keys) {
+			t.Fatalf("%s: vnodes own %d of %d keys, the test needs both kinds", phase, want, len(keys))
+		}
+		for _, k := range keys {
+			if _, err := r.Members[0].Node.Get(ctx, r.Space.NewIdFromString(k)); err != nil {
+				t.Errorf("%s: Get(%s): %v", phase, k, err)
+			}
+		}
+	}
+	checkShared("initial", vs[0])
+
+	// un nodo virtuale lascia l'anello: consegna solo il proprio intervallo
+	if err := vs[1].Node.Leave(); err != nil {
+		t.Fatalf("Leave: %v", err)
+	}
+	r.Kill(vs[1])
+	vs = slices.Delete(vs, 1, 2)
+	r.WaitStable()
+	checkShared("after leave", vs[0])
+}
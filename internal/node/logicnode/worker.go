@@ -5,112 +5,374 @@ import (
 	"KoordeDHT/internal/logger"
 	"KoordeDHT/internal/node/client"
 	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc"
 )
 
+// defaultJitter is the fraction by which the stabilizer intervals are
+// randomized on every pass (see WithStabilizationJitter).
+const defaultJitter = 0.1
+
 // StartStabilizers runs periodic maintenance tasks for Koorde.
-// It launches two independent loops:
+// It launches independent loops:
 //   - Chord-style stabilizers (successor/predecessor management) at chordInterval
 //   - De Bruijn pointer maintenance at deBruijnInterval
+//   - Storage maintenance at storageInterval, and as soon as the
+//     predecessor is known after a store flagged for repair (see
+//     WithNoPredecessorPolicy)
+//   - If enabled (WithAntiEntropy), anti-entropy with the successor
+//   - If enabled (WithRoutingPersistence), saving of the routing table
+//   - If enabled (WithCatchUp), a catch-up loop that runs the Chord and
+//     de Bruijn stabilizers at a fast interval right after (re)join and
+//     disables itself once the routing state has converged
+//
+// With WithAdaptiveStabilization, the Chord and de Bruijn loops ignore
+// chordInterval and deBruijnInterval and adapt their interval to the churn
+// of the ring instead (see adaptiveLoop).
+//
+// Except for catch-up, every interval is randomized by the jitter of the
+// node on each pass (see WithStabilizationJitter), so that nodes started
+// together spread their passes over time instead of ticking in lockstep.
 //
-// Both loops stop when ctx is canceled.
+// All loops stop when ctx is canceled, and skip their work while
+// stabilization is paused (see PauseStabilization).
 func (n *Node) StartStabilizers(ctx context.Context, chordInterval, deBruijnInterval, storageInterval time.Duration) {
-	// Chord-style stabilizers
+	if n.maxInterval > 0 {
+		n.startAdaptiveStabilizers(ctx)
+	} else {
+		n.startFixedStabilizers(ctx, chordInterval, deBruijnInterval)
+	}
+
+	// Storage maintenance
 	go func() {
-		ticker := time.NewTicker(chordInterval)
-		defer ticker.Stop()
+		timer := time.NewTimer(n.jittered(storageInterval))
+		defer timer.Stop()
 
 		for {
 			select {
 			case <-ctx.Done():
-				n.lgr.Info("chord stabilizers stopped")
+				n.lgr.Info("storage maintenance stopped")
 				return
-			case <-ticker.C:
-				n.stabilizeSuccessor()
-				n.fixSuccessorList()
-				n.checkPredecessor()
+			case <-timer.C:
+				if !n.StabilizationPaused() {
+					n.resourceRepair(ctx)
+				}
+				timer.Reset(n.jittered(storageInterval))
+			case <-n.repairNow:
+				// A store was accepted without a predecessor, now known
+				if ctx.Err() != nil {
+					// stopped meanwhile: leave the request to the next loop
+					n.signalRepair()
+					return
+				}
+				if !n.StabilizationPaused() {
+					n.resourceRepair(ctx)
+				}
 			}
 		}
 	}()
 
-	// De Bruijn stabilizer
+	// Anti-entropy with the successor
+	if n.syncInterval > 0 {
+		go func() {
+			timer := time.NewTimer(n.jittered(n.syncInterval))
+			defer timer.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					n.lgr.Info("anti-entropy stopped")
+					return
+				case <-timer.C:
+					if !n.StabilizationPaused() {
+						n.antiEntropy(ctx)
+					}
+					timer.Reset(n.jittered(n.syncInterval))
+				}
+			}
+		}()
+	}
+
+	// Routing table persistence
+	if n.rtFile != "" {
+		go n.persistLoop(ctx)
+	}
+
+	// Catch-up after (re)join
+	if n.catchUpInterval > 0 && n.catchUpRounds > 0 {
+		go n.catchUp(ctx)
+	}
+}
+
+// startFixedStabilizers runs the Chord and de Bruijn stabilizers at fixed
+// intervals.
+func (n *Node) startFixedStabilizers(ctx context.Context, chordInterval, deBruijnInterval time.Duration) {
+	// Chord-style stabilizers
 	go func() {
-		ticker := time.NewTicker(deBruijnInterval)
-		defer ticker.Stop()
+		timer := time.NewTimer(n.jittered(chordInterval))
+		defer timer.Stop()
 
 		for {
 			select {
 			case <-ctx.Done():
-				n.lgr.Info("de Bruijn stabilizer stopped")
+				n.lgr.Info("chord stabilizers stopped")
 				return
-			case <-ticker.C:
-				n.fixDeBruijn()
+			case <-timer.C:
+				n.chordRound(ctx)
+				timer.Reset(n.jittered(chordInterval))
 			}
 		}
 	}()
 
-	// Storage maintenance
-	go func() {
-		ticker := time.NewTicker(storageInterval)
-		defer ticker.Stop()
+	// De Bruijn stabilizer (not needed in Chord-only mode)
+	if n.deBruijn {
+		go func() {
+			timer := time.NewTimer(n.jittered(deBruijnInterval))
+			defer timer.Stop()
 
-		for {
-			select {
-			case <-ctx.Done():
-				n.lgr.Info("storage maintenance stopped")
-				return
-			case <-ticker.C:
-				n.resourceRepair(ctx)
+			for {
+				select {
+				case <-ctx.Done():
+					n.lgr.Info("de Bruijn stabilizer stopped")
+					return
+				case <-timer.C:
+					n.deBruijnRound(ctx)
+					timer.Reset(n.jittered(deBruijnInterval))
+				}
 			}
+		}()
+	}
+}
+
+// startAdaptiveStabilizers runs the Chord and de Bruijn stabilizers with
+// intervals in [minInterval, maxInterval]. A change of the Chord state
+// also resets the de Bruijn interval, since the window is rebuilt from the
+// successor list.
+func (n *Node) startAdaptiveStabilizers(ctx context.Context) {
+	var deBruijnCtl *intervalController
+	if n.deBruijn {
+		deBruijnCtl = newIntervalController("de Bruijn", n.minInterval, n.maxInterval, n.lgr)
+		go n.adaptiveLoop(ctx, deBruijnCtl, n.deBruijnRound, n.deBruijnState, nil)
+	}
+
+	chordCtl := newIntervalController("chord", n.minInterval, n.maxInterval, n.lgr)
+	var onChange func()
+	if deBruijnCtl != nil {
+		onChange = func() { deBruijnCtl.reset("chord routing state changed") }
+	}
+	go n.adaptiveLoop(ctx, chordCtl, n.chordRound, n.chordState, onChange)
+}
+
+// jittered returns d scaled by a random factor in [1-jitter, 1+jitter].
+func (n *Node) jittered(d time.Duration) time.Duration {
+	if n.jitter <= 0 || d <= 0 {
+		return d
+	}
+	f := 1 + n.jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(d) * f)
+}
+
+// chordRound runs one pass of the Chord-style stabilizers. Passes are
+// serialized, so the regular and the catch-up loops never overlap; a pass
+// still waiting for the lock when ctx is canceled is skipped. While
+// stabilization is paused only the predecessor is probed, if requested
+// (see PauseStabilization).
+func (n *Node) chordRound(ctx context.Context) {
+	n.chordMu.Lock()
+	defer n.chordMu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+	if n.StabilizationPaused() {
+		if n.pauseDetect.Load() {
+			n.checkPredecessor()
 		}
-	}()
+		return
+	}
+	n.stabilizeSuccessor()
+	n.fixSuccessorList()
+	if n.maxSuccList > 0 {
+		n.adaptSuccessorList()
+	}
+	n.checkPredecessor()
+	n.updateGauges()
 }
 
-// resourceRepair performs one maintenance pass to ensure that all resources
-// stored locally still belong to this node's primary ownership interval.
+// updateGauges refreshes the storage and connection pool gauges.
+func (n *Node) updateGauges() {
+	n.metrics.SetStoredResources(n.s.Len())
+	n.metrics.SetPoolConnections(n.cp.Size())
+}
+
+// deBruijnRound runs one (serialized) pass of the de Bruijn stabilizer.
+func (n *Node) deBruijnRound(ctx context.Context) {
+	n.deBruijnMu.Lock()
+	defer n.deBruijnMu.Unlock()
+	if ctx.Err() != nil || n.StabilizationPaused() {
+		return
+	}
+	n.fixDeBruijn()
+	n.updateGauges()
+}
+
+// catchUp runs the Chord and de Bruijn stabilizers every catchUpInterval
+// until the routing state is complete (predecessor, successor list and
+// de Bruijn window all set) and unchanged across two consecutive rounds,
+// or until catchUpRounds rounds have been executed. The regular loops
+// keep running meanwhile and take over when catch-up ends.
+func (n *Node) catchUp(ctx context.Context) {
+	ticker := time.NewTicker(n.catchUpInterval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	prev := ""
+	for round := 1; round <= n.catchUpRounds; round++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		n.chordRound(ctx)
+		if n.deBruijn {
+			n.deBruijnRound(ctx)
+		}
+
+		snap := n.routingSnapshot()
+		if snap != "" && snap == prev {
+			n.lgr.Info("catch-up: routing state converged",
+				logger.F("rounds", round), logger.F("elapsed", time.Since(start).String()))
+			return
+		}
+		prev = snap
+	}
+	n.lgr.Warn("catch-up: routing state did not converge, reverting to normal intervals",
+		logger.F("rounds", n.catchUpRounds), logger.F("elapsed", time.Since(start).String()))
+}
+
+// routingSnapshot returns a compact representation of the routing state,
+// or "" if the state is still incomplete (missing predecessor, successor
+// list or, unless de Bruijn routing is disabled, de Bruijn window).
+func (n *Node) routingSnapshot() string {
+	pred := n.rt.GetPredecessor()
+	succs := n.rt.SuccessorList()
+	debruijn := n.rt.DeBruijnList()
+	if pred == nil || len(succs) == 0 || (n.deBruijn && len(debruijn) == 0) {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(pred.ID.ToHexString(false))
+	for _, list := range [][]*domain.Node{succs, debruijn} {
+		sb.WriteByte('|')
+		for _, x := range list {
+			if x == nil {
+				sb.WriteString("-,")
+				continue
+			}
+			sb.WriteString(x.ID.ToHexString(false))
+			sb.WriteByte(',')
+		}
+	}
+	return sb.String()
+}
+
+// resourceRepair runs one periodic storage maintenance pass (see
+// rebalanceOnce).
+func (n *Node) resourceRepair(ctx context.Context) {
+	if _, _, err := n.rebalanceOnce(ctx); err != nil {
+		n.lgr.Warn("ResourceRepair: skipping pass", logger.F("err", err))
+	}
+}
+
+// Rebalance runs one storage maintenance pass right away, instead of
+// waiting for the periodic one, e.g. after a planned topology change. It
+// returns how many resources were copied to their owner and how many could
+// not be (lookup, connection or transfer failures); as in every pass, the
+// local copies of the transferred resources are deleted by the next one.
+func (n *Node) Rebalance(ctx context.Context) (transferred, failed int, err error) {
+	return n.rebalanceOnce(ctx)
+}
+
+// rebalanceOnce performs one maintenance pass to ensure that all resources
+// stored locally still belong to this node's primary ownership interval,
+// and returns how many misplaced resources were copied to their owner and
+// how many failed. Passes are serialized. Without a predecessor the pass
+// is skipped with ErrNoPredecessor.
 //
-// Ownership (no replication):
+// Ownership:
 //   - This node (self) owns keys in (pred, self].
 //   - Any local resource whose key ∉ (pred, self] should be transferred
-//     to the node that is currently responsible for it.
+//     to the node that is currently responsible for it, unless replication
+//     is enabled and this node is one of the owner's replicas.
+//   - With replication, the owned range is first pushed again to the
+//     replicas that may miss it (see replicateOwned).
 //
 // Strategy:
 //   - Fast check using the predecessor interval when available.
 //   - Robust confirmation via a fresh FindSuccessor lookup before transferring,
 //     so we do not rely solely on potentially stale predecessor information.
+//   - Two-phase handoff: a resource is copied to its owner on the first pass
+//     that finds it misplaced, and the local copy is deleted only on the next
+//     pass (after copying it again, in case it was updated meanwhile). Lookups
+//     that resolved this node just before the ownership change can still be
+//     served during that grace window.
 //
 // Logging:
 //   - WARN for lookup/transfer/delete failures.
 //   - INFO for successful transfers.
 //   - Keep logs minimal; this runs periodically.
-func (n *Node) resourceRepair(ctx context.Context) {
+func (n *Node) rebalanceOnce(ctx context.Context) (transferred, failed int, err error) {
+	n.repairMu.Lock()
+	defer n.repairMu.Unlock()
+	defer n.updateGauges()
 	self := n.rt.Self()
 	pred := n.rt.GetPredecessor()
 	if pred == nil {
-		// Without a successor, we cannot determine our responsibility interval.
-		n.lgr.Warn("ResourceRepair: skipping pass, predecessor is nil")
-		return
+		// Without a predecessor, we cannot determine our responsibility interval.
+		return 0, 0, ErrNoPredecessor
 	}
 
+	// Replication: refill the replicas of the owned range if needed
+	n.replicateOwned(ctx, pred)
+
 	resources := n.s.Between(self.ID, pred.ID)
+
+	// keys copied to their owner during the previous pass
+	n.handoffMu.Lock()
+	previous := n.handedOff
+	n.handedOff = make(map[string]struct{})
+	n.handoffMu.Unlock()
+
 	if len(resources) == 0 {
 		// No resources to check
-		return
+		return 0, 0, nil
 	}
 
+	owners := make(map[string][]*domain.Node) // replicas by owner address, fetched once per pass
 	for _, res := range resources {
+		if n.ownedBySibling(res.Key) {
+			// in the shared storage of another virtual node
+			continue
+		}
 
 		// find current responsible node
 		resp, err := n.FindSuccessorInit(ctx, res.Key)
 		if err != nil || resp == nil {
 			n.lgr.Warn("ResourceRepair: failed to find successor",
 				logger.F("key", res.RawKey), logger.F("err", err))
+			failed++
+			continue
+		}
+		if resp.ID.Equal(self.ID) || n.sibling(resp) {
+			// still responsible, or owned by a virtual node sharing the storage
 			continue
 		}
-		if resp.ID.Equal(self.ID) {
-			// still responsible
+		if n.replicas > 1 && n.holdsReplica(ctx, resp, owners) {
+			// replica copy of a key owned by one of our predecessors
 			continue
 		}
 
@@ -123,6 +385,7 @@ func (n *Node) resourceRepair(ctx context.Context) {
 			if err != nil {
 				n.lgr.Warn("ResourceRepair: failed to connect to responsible node",
 					logger.F("key", res.RawKey), logger.FNode("responsible", resp), logger.F("err", err))
+				failed++
 				continue
 			}
 			defer econn.Close()
@@ -131,6 +394,19 @@ func (n *Node) resourceRepair(ctx context.Context) {
 		if _, err := client.StoreRemote(ctx, cli, sres); err != nil {
 			n.lgr.Warn("ResourceRepair: failed to transfer resource",
 				logger.F("key", res.RawKey), logger.FNode("responsible", resp), logger.F("err", err))
+			failed++
+			continue
+		}
+		transferred++
+
+		// first pass: keep the local copy until the next one
+		hexKey := res.Key.ToHexString(false)
+		if _, ok := previous[hexKey]; !ok {
+			n.handoffMu.Lock()
+			n.handedOff[hexKey] = struct{}{}
+			n.handoffMu.Unlock()
+			n.lgr.Debug("ResourceRepair: resource copied to responsible node, deletion deferred",
+				logger.F("key", res.RawKey), logger.FNode("responsible", resp))
 			continue
 		}
 
@@ -143,6 +419,7 @@ func (n *Node) resourceRepair(ctx context.Context) {
 				logger.F("key", res.RawKey), logger.FNode("responsible", resp))
 		}
 	}
+	return transferred, failed, nil
 }
 
 // stabilizeSuccessor verifies that the current successor is alive and valid.
@@ -156,7 +433,9 @@ func (n *Node) resourceRepair(ctx context.Context) {
 //  2. If the successor is unreachable, attempt to promote a candidate
 //     from the successor list. If none is available, reset to single-node mode.
 //  3. If the successor’s predecessor is closer, adopt it as the new successor.
-//  4. Notify the successor that we may be its predecessor.
+//  4. Notify the successor that we may be its predecessor, over a fresh
+//     connection if it has not acknowledged this node for several rounds
+//     (see checkSymmetry).
 func (n *Node) stabilizeSuccessor() {
 	self := n.rt.Self()
 	succ := n.rt.FirstSuccessor()
@@ -164,9 +443,11 @@ func (n *Node) stabilizeSuccessor() {
 		n.lgr.Error("stabilize: successor is nil (invalid state)")
 		return
 	}
+	before := succ
 
 	// Step 1: ask successor for its predecessor
 	var pred *domain.Node
+	alive := true // a successor without predecessor is still alive
 	{
 		ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
 		defer cancel()
@@ -175,13 +456,17 @@ func (n *Node) stabilizeSuccessor() {
 		} else {
 			cli, err := n.cp.GetFromPool(succ.Addr)
 			if err != nil {
-				n.lgr.Warn("stabilize: failed to get client for successor",
+				// The successor lost its pool entry: probe it over an ephemeral
+				// connection so that a dead successor is still detected and replaced
+				n.lgr.Warn("stabilize: failed to get client for successor, probing directly",
 					logger.FNode("succ", succ),
 					logger.F("err", err))
-				return
+				pred, err = n.probePredecessor(ctx, succ)
+			} else {
+				pred, err = client.GetPredecessor(ctx, cli, n.rt.Space())
 			}
-			pred, err = client.GetPredecessor(ctx, cli, n.rt.Space())
-			if err != nil {
+			if err != nil && !errors.Is(err, client.ErrNoPredecessor) {
+				alive = false
 				n.lgr.Warn("stabilize: could not get predecessor from successor",
 					logger.FNode("succ", succ),
 					logger.F("err", err))
@@ -190,12 +475,12 @@ func (n *Node) stabilizeSuccessor() {
 	}
 
 	// Step 2: if unreachable, promote candidate from successor list
-	if pred == nil {
+	if !alive {
 		n.lgr.Warn("stabilize: successor unresponsive, attempting promotion",
 			logger.FNode("old_successor", succ))
 
 		promoted := false
-		for i := 1; i < n.Space().SuccListSize; i++ {
+		for i := 1; i < n.rt.SuccessorListSize(); i++ {
 			candidate := n.rt.GetSuccessor(i)
 			if candidate == nil {
 				continue
@@ -220,10 +505,8 @@ func (n *Node) stabilizeSuccessor() {
 					_ = n.cp.Release(nd.Addr)
 				}
 			}
-			for _, nd := range n.rt.DeBruijnList() {
-				if nd != nil {
-					_ = n.cp.Release(nd.Addr)
-				}
+			for _, nd := range n.deBruijnRefs() {
+				_ = n.cp.Release(nd.Addr)
 			}
 			n.rt.InitSingleNode()
 			return
@@ -246,6 +529,7 @@ func (n *Node) stabilizeSuccessor() {
 		}
 		succ = pred
 	}
+	renotify := n.checkSymmetry(before, succ, pred)
 
 	// Step 4: notify successor
 	{
@@ -256,6 +540,14 @@ func (n *Node) stabilizeSuccessor() {
 			// If successor is self, no need to notify
 			return
 		}
+		if n.observer {
+			// An observer never offers itself as predecessor
+			return
+		}
+		if renotify {
+			n.renotify(ctx, succ)
+			return
+		}
 
 		cli, err := n.cp.GetFromPool(succ.Addr)
 		if err != nil {
@@ -271,47 +563,70 @@ func (n *Node) stabilizeSuccessor() {
 	}
 }
 
+// probePredecessor asks node for its predecessor over an ephemeral
+// connection. If node answers, the pool reference held for it as successor
+// is restored.
+func (n *Node) probePredecessor(ctx context.Context, node *domain.Node) (*domain.Node, error) {
+	cli, conn, err := n.cp.DialEphemeral(node.Addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	pred, err := client.GetPredecessor(ctx, cli, n.rt.Space())
+	if err != nil && !errors.Is(err, client.ErrNoPredecessor) {
+		return nil, err
+	}
+	if addErr := n.cp.AddRef(node.Addr); addErr != nil {
+		n.lgr.Warn("stabilize: failed to restore successor in pool",
+			logger.FNode("succ", node), logger.F("err", addErr))
+	}
+	return pred, err
+}
+
 // fixSuccessorList refreshes the local successor list by contacting
-// the first successor. It maintains reference counts by AddRef() for
-// new entries before installing them, and Release() for nodes that
-// are no longer part of the list.
+// the first successor, or, if it does not answer and the fallback is
+// enabled (see WithSuccessorListFallback), the next successor of the list.
+// It maintains reference counts by AddRef() for new entries before
+// installing them, and Release() for nodes that are no longer part of the
+// list.
 //
 // The procedure is:
-//  1. Fetch the successor list from the first successor.
-//  2. Merge it into a new list of fixed size, always starting with self’s successor.
+//  1. Fetch the successor list from the first successor (or the fallback).
+//  2. Merge it into a new list of fixed size, always starting with self’s
+//     successor, followed by the fallback if it answered instead.
 //  3. Update the routing table.
 //  4. Adjust client pool references.
 func (n *Node) fixSuccessorList() {
+	self := n.rt.Self()
 	succ := n.rt.FirstSuccessor()
 	if succ == nil {
 		n.lgr.Error("fixSuccessorList: no successor set")
 		return
 	}
-	if succ.ID.Equal(n.rt.Self().ID) {
+	if succ.ID.Equal(self.ID) {
 		// Single-node mode, nothing to do
 		return
 	}
 
-	// Step 1: fetch successor list from first successor
-	var remoteList []*domain.Node
-	{
-		ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
-		cli, err := n.cp.GetFromPool(succ.Addr)
-		if err != nil {
-			n.lgr.Error("fixSuccessorList: failed to get from pool",
-				logger.FNode("succ", succ),
-				logger.F("err", err))
-			cancel()
+	// Step 1: fetch successor list from first successor, then from the fallback
+	head := []*domain.Node{succ}
+	remoteList, err := n.fetchSuccessorList(succ)
+	if err != nil {
+		n.lgr.Warn("fixSuccessorList: could not get successor list",
+			logger.FNode("succ", succ),
+			logger.F("err", err))
+		fallback := n.fallbackSuccessor(succ)
+		if fallback == nil {
 			return
 		}
-		remoteList, err = client.GetSuccessorList(ctx, cli, n.rt.Space())
-		cancel()
+		remoteList, err = n.fetchSuccessorList(fallback)
 		if err != nil {
-			n.lgr.Warn("fixSuccessorList: could not get successor list",
-				logger.FNode("succ", succ),
+			n.lgr.Warn("fixSuccessorList: could not get successor list from fallback",
+				logger.FNode("fallback", fallback),
 				logger.F("err", err))
 			return
 		}
+		head = append(head, fallback)
 	}
 
 	// Step 2: snapshot current list (for later release)
@@ -324,19 +639,19 @@ func (n *Node) fixSuccessorList() {
 	}
 
 	// Step 3: build new list (fixed size, first entry is successor)
-	size := n.Space().SuccListSize
+	size := n.rt.SuccessorListSize()
 	newList := make([]*domain.Node, size)
-	newList[0] = succ
-	for i := 1; i < size; i++ {
-		if i-1 < len(remoteList) {
-			if remoteList[i-1] != nil {
-				if remoteList[i-1].ID.Equal(n.rt.Self().ID) {
-					break
-				} else {
-					newList[i] = remoteList[i-1]
-				}
+	copy(newList, head)
+	for i := len(head); i < size; i++ {
+		j := i - len(head)
+		if j >= len(remoteList) {
+			break
+		}
+		if nd := remoteList[j]; nd != nil {
+			if nd.ID.Equal(self.ID) || nd.ID.Equal(succ.ID) {
+				break
 			}
-
+			newList[i] = nd
 		}
 	}
 
@@ -372,12 +687,44 @@ func (n *Node) fixSuccessorList() {
 	}
 }
 
+// fetchSuccessorList asks nd for its successor list, over the pooled
+// connection or an ephemeral one (see clientFor), within the failure
+// timeout.
+func (n *Node) fetchSuccessorList(nd *domain.Node) ([]*domain.Node, error) {
+	cli, release, err := n.clientFor(nd.Addr)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+	defer cancel()
+	return client.GetSuccessorList(ctx, cli, n.rt.Space())
+}
+
+// fallbackSuccessor returns the entry of the successor list to ask for
+// the list when the first successor succ does not answer: the next entry
+// that is neither succ nor self, or nil if there is none or the fallback
+// is disabled.
+func (n *Node) fallbackSuccessor(succ *domain.Node) *domain.Node {
+	if !n.succListFallback {
+		return nil
+	}
+	self := n.rt.Self()
+	for _, nd := range n.rt.SuccessorList() {
+		if nd != nil && !nd.ID.Equal(succ.ID) && !nd.ID.Equal(self.ID) {
+			return nd
+		}
+	}
+	return nil
+}
+
 // checkPredecessor verifies whether the current predecessor is still alive.
 // The method proceeds as follows:
 //   - If no predecessor is set or the predecessor is self, it returns immediately.
 //   - Otherwise, it tries to obtain a gRPC client for the predecessor from the pool.
 //   - If the client cannot be retrieved or a Ping RPC fails, the predecessor is
 //     considered dead: it is released from the pool and cleared in the routing table.
+//   - A successful Ping refreshes the predecessor's last-seen time.
 //
 // Note: a failed notification or release does not stop the cleanup process;
 // the predecessor pointer is always cleared in case of failure.
@@ -390,11 +737,28 @@ func (n *Node) checkPredecessor() {
 	// Acquire client connection from pool
 	cli, err := n.cp.GetFromPool(pred.Addr)
 	if err != nil {
+		// A missing pool entry does not mean the predecessor is dead:
+		// probe it on an ephemeral connection and restore the entry if it answers.
+		ephCli, conn, dialErr := n.cp.DialEphemeral(pred.Addr)
+		if dialErr == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+			pingErr := client.Ping(ctx, ephCli)
+			cancel()
+			conn.Close()
+			if pingErr == nil {
+				n.rt.TouchPredecessor(pred.ID)
+				if err := n.cp.AddRef(pred.Addr); err != nil {
+					n.lgr.Warn("checkPredecessor: failed to restore predecessor in pool",
+						logger.FNode("pred", pred), logger.F("err", err))
+				}
+				return
+			}
+		}
 		n.lgr.Warn("checkPredecessor: failed to get client for predecessor",
 			logger.FNode("pred", pred),
 			logger.F("err", err))
 		// Without a client, assume predecessor is dead
-		n.rt.SetPredecessor(nil)
+		n.clearPredecessor(pred, false)
 		return
 	}
 
@@ -405,17 +769,32 @@ func (n *Node) checkPredecessor() {
 		n.lgr.Warn("checkPredecessor: predecessor unresponsive, clearing",
 			logger.FNode("pred", pred),
 			logger.F("err", err))
+		n.clearPredecessor(pred, true)
+		return
+	}
+	n.rt.TouchPredecessor(pred.ID)
+}
 
+// clearPredecessor clears the predecessor pointer if it still refers to
+// pred, releasing its pool reference when release is true. A concurrent
+// Notify may have installed a new predecessor in the meantime: in that case
+// the routing table (and the pool references) are left untouched.
+func (n *Node) clearPredecessor(pred *domain.Node, release bool) {
+	n.predMu.Lock()
+	defer n.predMu.Unlock()
+	if cur := n.rt.GetPredecessor(); cur == nil || !cur.ID.Equal(pred.ID) {
+		return
+	}
+	if release {
 		// Release client from pool
 		if err := n.cp.Release(pred.Addr); err != nil {
 			n.lgr.Warn("checkPredecessor: failed to release predecessor from pool",
 				logger.FNode("pred", pred),
 				logger.F("err", err))
 		}
-
-		// Clear predecessor reference
-		n.rt.SetPredecessor(nil)
 	}
+	// Clear predecessor reference
+	n.rt.SetPredecessor(nil)
 }
 
 // fixDeBruijn refreshes the de Bruijn window for this node.
@@ -423,117 +802,86 @@ func (n *Node) checkPredecessor() {
 //  1. Compute the anchor as the predecessor of (k * self.ID) mod 2^b.
 //  2. Set digit 0 of the de Bruijn window to the anchor.
 //  3. Fill the remaining digits with entries from the anchor’s successor list.
-//  4. Update the local routing table and adjust client pool references.
+//  4. With de Bruijn backups (see routingtable.WithDeBruijnBackups), give
+//     each digit the nodes preceding it on the ring, closest first (see
+//     deBruijnBackups).
+//  5. Update the local routing table and adjust client pool references.
+//
+// If the anchor (or the lookup that resolves it) is unreachable, the
+// refresh does not give up: the entries of the current window are tried
+// in turn, so the window is rebuilt from
+// the closest live node in the same region of the ring. Candidates that
+// failed repeatedly are tried last (see deBruijnCandidates).
+//
+// With WithLazyDeBruijnRefresh the whole refresh, anchor lookup included,
+// is skipped while the window is known to be current (see deBruijnCurrent).
 func (n *Node) fixDeBruijn() {
 	self := n.rt.Self()
-	// Step 1: compute target = (k * self.ID) mod 2^b
-	target, err := n.rt.Space().MulKMod(self.ID)
-	if err != nil {
-		n.lgr.Error("fixDeBruijn: failed to compute target", logger.F("err", err))
+	if n.deBruijnCurrent() {
+		n.lgr.Debug("fixDeBruijn: window up to date, refresh skipped")
 		return
 	}
 
-	// Lookup successor of target
-	ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
-	succ, err := n.FindSuccessorInit(ctx, target)
-	cancel()
-	if err != nil || succ == nil {
-		n.lgr.Warn("fixDeBruijn: could not find successor",
-			logger.F("target", target.ToHexString(true)),
-			logger.F("err", err))
-		return
-	}
-
-	// Step 2: get anchor (predecessor of succ)
-	var anchor *domain.Node
-	{
-		if succ.ID.Equal(self.ID) {
-			anchor = n.rt.GetPredecessor()
-		} else {
-			ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
-			cli, err := n.cp.GetFromPool(succ.Addr)
-			if err != nil {
-				ephCli, conn, err := n.cp.DialEphemeral(succ.Addr)
-				if err != nil {
-					n.lgr.Warn("fixDeBruijn: could not dial anchor successor",
-						logger.FNode("succ", succ),
-						logger.F("err", err))
-					cancel()
-					return
-				}
-				cli = ephCli
-				defer conn.Close()
-			}
-			anchor, err = client.GetPredecessor(ctx, cli, n.rt.Space())
-			cancel()
-			if err != nil {
-				n.lgr.Warn("fixDeBruijn: could not get the anchor",
-					logger.FNode("succ", succ),
-					logger.F("err", err))
-				return
-			}
-		}
-		if anchor == nil {
-			n.lgr.Warn("fixDeBruijn: anchor is nil", logger.FNode("succ", succ))
-			return
-		}
-	}
-
-	// Snapshot current window
+	// Steps 1-2: compute the ordered list of anchor candidates
 	oldList := n.rt.DeBruijnList()
-	oldSet := make(map[string]*domain.Node)
-	for _, node := range oldList {
-		if node != nil {
-			oldSet[node.Addr] = node
-		}
+	candidates, lookedUp := n.deBruijnCandidates(oldList)
+	defer n.pruneAnchorFailures(candidates)
+	if len(candidates) == 0 {
+		n.lgr.Warn("fixDeBruijn: no anchor candidate available")
+		return
 	}
 
-	// Step 3: build new window (digit 0 = anchor, others from anchor’s successor list)
-	newNodes := make([]*domain.Node, n.rt.Space().GraphGrade)
-	newNodes[0] = anchor
-
-	var succList []*domain.Node
-	{
+	// Step 3: build new window from the first candidate that answers
+	// (digit 0 = anchor, others from anchor’s successor list)
+	var newNodes []*domain.Node
+	var backups [][]*domain.Node
+	for i, anchor := range candidates {
+		var succList []*domain.Node
 		if anchor.ID.Equal(self.ID) {
 			succList = n.rt.SuccessorList()
 		} else {
-			ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
-			cli, err := n.cp.GetFromPool(anchor.Addr)
-			if err != nil {
-				ephCli, conn, err := n.cp.DialEphemeral(anchor.Addr)
-				if err != nil {
-					n.lgr.Warn("fixDeBruijn: could not dial anchor",
-						logger.FNode("anchor", anchor), logger.F("err", err))
-					cancel()
-					return
-				}
-				cli = ephCli
-				defer conn.Close()
-			}
-			succList, err = client.GetSuccessorList(ctx, cli, n.rt.Space())
-			cancel()
+			var err error
+			succList, err = n.remoteSuccessorList(anchor)
 			if err != nil {
+				n.anchorFailed(anchor)
 				n.lgr.Warn("fixDeBruijn: could not get successor list from anchor",
 					logger.FNode("anchor", anchor), logger.F("err", err))
-				return
+				continue
 			}
+			n.anchorAlive(anchor)
 		}
-	}
-	for i := 1; i < n.rt.Space().GraphGrade; i++ {
-		if i-1 < len(succList) {
-			newNodes[i] = succList[i-1]
+		if i > 0 {
+			n.lgr.Info("fixDeBruijn: primary anchor unavailable, using fallback",
+				logger.FNode("anchor", anchor), logger.F("candidate", i))
+		}
+		newNodes = make([]*domain.Node, n.rt.Space().GraphGrade)
+		newNodes[0] = anchor
+		for j := 1; j < n.rt.Space().GraphGrade; j++ {
+			if j-1 < len(succList) {
+				newNodes[j] = succList[j-1]
+			}
 		}
+		backups = n.deBruijnBackups(newNodes)
+		break
+	}
+	if newNodes == nil {
+		n.lgr.Warn("fixDeBruijn: all anchor candidates unreachable, keeping current window",
+			logger.F("candidates", len(candidates)))
+		return
 	}
 
+	// Snapshot current window (backups included)
+	oldSet := n.deBruijnRefs()
+
 	// Build set of new nodes
 	newSet := make(map[string]*domain.Node)
-	for _, node := range newNodes {
+	for _, node := range append(newNodes, slices.Concat(backups...)...) {
 		if node != nil {
 			newSet[node.Addr] = node
 		}
 	}
 
-	// Step 4: update client pool references
+	// Step 5: update client pool references
 	for addr, cand := range newSet {
 		if _, ok := oldSet[addr]; !ok {
 			if err := n.cp.AddRef(addr); err != nil {
@@ -543,6 +891,9 @@ func (n *Node) fixDeBruijn() {
 		}
 	}
 	n.rt.SetDeBruijnList(newNodes)
+	for digit, b := range backups {
+		n.rt.SetDeBruijnBackups(digit, b)
+	}
 	for addr, old := range oldSet {
 		if _, ok := newSet[addr]; !ok {
 			if err := n.cp.Release(addr); err != nil {
@@ -552,6 +903,283 @@ func (n *Node) fixDeBruijn() {
 		}
 	}
 
+	// remember the anchor only if the lookup found it: a window rebuilt
+	// from a fallback candidate is refreshed again at the next round
+	if lookedUp != nil && newNodes[0].Addr == lookedUp.Addr {
+		n.deBruijnRefreshed(lookedUp)
+	} else {
+		n.deBruijnRefreshed(nil)
+	}
+
 	n.lgr.Debug("fixDeBruijn: updated de Bruijn window",
 		logger.F("degree", n.rt.Space().GraphGrade))
 }
+
+// deBruijnBackups returns the backups of each digit of the new de Bruijn
+// window (nil without backups): the nodes preceding the digit on the ring,
+// closest first. A backup must precede its digit, like the pointer does,
+// so that it still lies before the imaginary node the lookup is routed
+// to. The window is contiguous on the ring, so the backups of a digit are
+// the digits before it, continued with the predecessors of the anchor.
+func (n *Node) deBruijnBackups(window []*domain.Node) [][]*domain.Node {
+	size := n.rt.DeBruijnBackupSize()
+	if size == 0 || len(window) == 0 || window[0] == nil {
+		return nil
+	}
+	// ring = predecessors of the anchor (farthest first) + window
+	preds := n.anchorPredecessors(window[0], size)
+	ring := make([]*domain.Node, 0, len(preds)+len(window))
+	for i := len(preds) - 1; i >= 0; i-- {
+		ring = append(ring, preds[i])
+	}
+	ring = append(ring, window...)
+
+	backups := make([][]*domain.Node, len(window))
+	for digit, node := range window {
+		if node == nil {
+			continue
+		}
+		seen := map[string]bool{node.Addr: true}
+		for i := len(preds) + digit - 1; i >= 0 && len(backups[digit]) < size; i-- {
+			if b := ring[i]; b != nil && !seen[b.Addr] {
+				seen[b.Addr] = true
+				backups[digit] = append(backups[digit], b)
+			}
+		}
+	}
+	return backups
+}
+
+// anchorPredecessors walks the ring backwards from the de Bruijn anchor and
+// returns up to count of its predecessors, closest first. The walk stops
+// at this node, at a node already met (a ring smaller than count) or at
+// the first node that cannot be asked: failures only shorten the backups.
+func (n *Node) anchorPredecessors(anchor *domain.Node, count int) []*domain.Node {
+	self := n.rt.Self()
+	seen := map[string]bool{anchor.Addr: true, self.Addr: true}
+	var out []*domain.Node
+	for cur := anchor; len(out) < count; {
+		var pred *domain.Node
+		if cur.ID.Equal(self.ID) {
+			pred = n.rt.GetPredecessor()
+		} else {
+			var err error
+			pred, err = n.remotePredecessor(cur)
+			if err != nil {
+				n.lgr.Debug("fixDeBruijn: could not get predecessor for de Bruijn backups",
+					logger.FNode("node", cur), logger.F("err", err))
+				break
+			}
+		}
+		if pred == nil || seen[pred.Addr] {
+			break
+		}
+		seen[pred.Addr] = true
+		out = append(out, pred)
+		cur = pred
+	}
+	return out
+}
+
+// deBruijnRefs returns, by address, the nodes of the de Bruijn window the
+// client pool holds a reference for: the pointers and their backups.
+func (n *Node) deBruijnRefs() map[string]*domain.Node {
+	refs := make(map[string]*domain.Node)
+	for _, nodes := range n.rt.DeBruijnWindow() {
+		for _, node := range nodes {
+			refs[node.Addr] = node
+		}
+	}
+	return refs
+}
+
+// deBruijnCurrent reports whether the de Bruijn window can be kept without
+// a refresh (see WithLazyDeBruijnRefresh): the window is complete and
+// anchored at the anchor found by the last refresh, the successor list has
+// not changed since then, and the last refresh is younger than the
+// configured maximum age.
+func (n *Node) deBruijnCurrent() bool {
+	if n.deBruijnMaxAge <= 0 {
+		return false
+	}
+	n.anchorMu.Lock()
+	anchor, succs, at := n.lastAnchor, n.lastSuccs, n.lastRefresh
+	n.anchorMu.Unlock()
+	if anchor == nil || n.now().Sub(at) >= n.deBruijnMaxAge || succs != n.successorsKey() {
+		return false
+	}
+	window := n.rt.DeBruijnList()
+	if len(window) == 0 || window[0] == nil || window[0].Addr != anchor.Addr {
+		return false
+	}
+	for _, d := range window {
+		if d == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// deBruijnRefreshed records the outcome of a refresh of the de Bruijn
+// window: the anchor found by the lookup (nil if the window was rebuilt
+// from a fallback candidate) and the successor list it was built with.
+func (n *Node) deBruijnRefreshed(anchor *domain.Node) {
+	succs := n.successorsKey()
+	n.anchorMu.Lock()
+	defer n.anchorMu.Unlock()
+	n.lastAnchor, n.lastSuccs, n.lastRefresh = anchor, succs, n.now()
+}
+
+// successorsKey returns the addresses of the successor list, joined, to
+// detect changes of the list between two rounds.
+func (n *Node) successorsKey() string {
+	return nodesKey(n.rt.SuccessorList())
+}
+
+// nodesKey joins the addresses of nodes (empty for nil entries).
+func nodesKey(nodes []*domain.Node) string {
+	var b strings.Builder
+	for _, s := range nodes {
+		if s != nil {
+			b.WriteString(s.Addr)
+		}
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// deBruijnCandidates returns the ordered list of nodes from which the de
+// Bruijn window can be rebuilt:
+//  1. the anchor, i.e. pred(succ(k * self.ID)), found with a single
+//     FindPredecessor lookup;
+//  2. the entries of the current window, which lie in the same region.
+//
+// The anchor lookup is retried once before falling back to the current
+// window. Duplicates are removed, and candidates that reached
+// maxAnchorFailures consecutive failures are moved to the end of the list.
+// The anchor found by the lookup is also returned (nil if it failed).
+func (n *Node) deBruijnCandidates(current []*domain.Node) ([]*domain.Node, *domain.Node) {
+	var list []*domain.Node
+
+	// compute target = (k * self.ID) mod 2^b
+	target, err := n.rt.Space().MulKMod(n.rt.Self().ID)
+	if err != nil {
+		n.lgr.Error("fixDeBruijn: failed to compute target", logger.F("err", err))
+		return nil, nil
+	}
+
+	// Lookup the anchor, predecessor of succ(target) (one retry)
+	var anchor *domain.Node
+	for attempt := 0; attempt < 2 && anchor == nil; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+		anchor, err = n.FindPredecessor(ctx, target)
+		cancel()
+		if err != nil || anchor == nil {
+			n.lgr.Warn("fixDeBruijn: could not find the anchor",
+				logger.F("target", target.ToHexString(true)),
+				logger.F("attempt", attempt+1),
+				logger.F("err", err))
+			anchor = nil
+		}
+	}
+	if anchor != nil {
+		list = append(list, anchor)
+	}
+	for _, d := range current {
+		if d != nil {
+			list = append(list, d)
+		}
+	}
+
+	// dedup, known-dead candidates last
+	seen := make(map[string]struct{}, len(list))
+	var healthy, suspect []*domain.Node
+	for _, c := range list {
+		if _, ok := seen[c.Addr]; ok {
+			continue
+		}
+		seen[c.Addr] = struct{}{}
+		if n.anchorFailures(c) >= maxAnchorFailures {
+			suspect = append(suspect, c)
+		} else {
+			healthy = append(healthy, c)
+		}
+	}
+	return append(healthy, suspect...), anchor
+}
+
+// remoteSuccessorList asks node for its successor list, using the pooled
+// connection if present or an ephemeral one otherwise.
+func (n *Node) remoteSuccessorList(node *domain.Node) ([]*domain.Node, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+	defer cancel()
+	cli, err := n.cp.GetFromPool(node.Addr)
+	if err != nil {
+		ephCli, conn, err := n.cp.DialEphemeral(node.Addr)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		cli = ephCli
+	}
+	return client.GetSuccessorList(ctx, cli, n.rt.Space())
+}
+
+// remotePredecessor fetches the predecessor of a remote node (nil if it
+// has none), through the pool connection if any or an ephemeral one.
+func (n *Node) remotePredecessor(node *domain.Node) (*domain.Node, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), n.cp.FailureTimeout())
+	defer cancel()
+	cli, err := n.cp.GetFromPool(node.Addr)
+	if err != nil {
+		ephCli, conn, err := n.cp.DialEphemeral(node.Addr)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		cli = ephCli
+	}
+	pred, err := client.GetPredecessor(ctx, cli, n.rt.Space())
+	if errors.Is(err, client.ErrNoPredecessor) {
+		return nil, nil
+	}
+	return pred, err
+}
+
+// maxAnchorFailures is the number of consecutive failures after which a
+// de Bruijn anchor candidate is considered dead and tried only as a last resort.
+const maxAnchorFailures = 3
+
+// anchorFailed records a failed contact with a de Bruijn anchor candidate.
+func (n *Node) anchorFailed(node *domain.Node) {
+	n.anchorMu.Lock()
+	defer n.anchorMu.Unlock()
+	n.anchorFail[node.Addr]++
+}
+
+// anchorAlive resets the failure counter of a de Bruijn anchor candidate.
+func (n *Node) anchorAlive(node *domain.Node) {
+	n.anchorMu.Lock()
+	defer n.anchorMu.Unlock()
+	delete(n.anchorFail, node.Addr)
+}
+
+// pruneAnchorFailures forgets the failures of every node that is no
+// longer an anchor candidate, so that departed nodes that never answer
+// again do not accumulate in the map.
+func (n *Node) pruneAnchorFailures(candidates []*domain.Node) {
+	n.anchorMu.Lock()
+	defer n.anchorMu.Unlock()
+	for addr := range n.anchorFail {
+		if !slices.ContainsFunc(candidates, func(c *domain.Node) bool { return c.Addr == addr }) {
+			delete(n.anchorFail, addr)
+		}
+	}
+}
+
+// anchorFailures returns the consecutive failures recorded for node.
+func (n *Node) anchorFailures(node *domain.Node) int {
+	n.anchorMu.Lock()
+	defer n.anchorMu.Unlock()
+	return n.anchorFail[node.Addr]
+}
@@ -10,3 +10,12 @@ func WithLogger(l logger.Logger) Option {
 		rt.logger = l
 	}
 }
+
+// WithDeBruijnBackups makes every digit of the de Bruijn window keep up to
+// n backups besides its pointer: alternate next hops tried when the
+// pointer is unreachable (default 0, negative values are treated as 0).
+func WithDeBruijnBackups(n int) Option {
+	return func(rt *RoutingTable) {
+		rt.deBruijnBackups = max(n, 0)
+	}
+}
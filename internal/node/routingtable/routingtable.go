@@ -4,7 +4,9 @@ import (
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 )
 
 // ----------------------------------------------------------------
@@ -22,7 +24,12 @@ type routingEntry struct {
 	// It can be read and updated concurrently using mu.
 	node *domain.Node
 
-	// mu synchronizes access to node, ensuring safe
+	// lastSeen is the last time the node was known to be alive: when it
+	// was installed in the entry or when it last gave a liveness signal
+	// (see Touch). Zero if the entry is empty.
+	lastSeen time.Time
+
+	// mu synchronizes access to node and lastSeen, ensuring safe
 	// concurrent reads and writes.
 	mu sync.RWMutex
 }
@@ -34,9 +41,33 @@ type routingEntry struct {
 func (e *routingEntry) Set(n *domain.Node) {
 	e.mu.Lock()
 	e.node = n
+	e.lastSeen = time.Time{}
+	if n != nil {
+		e.lastSeen = time.Now()
+	}
 	e.mu.Unlock()
 }
 
+// Touch refreshes the last-seen time of the entry if it still holds a node
+// with the given ID. It reports whether the entry was refreshed.
+func (e *routingEntry) Touch(id domain.ID) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.node == nil || !e.node.ID.Equal(id) {
+		return false
+	}
+	e.lastSeen = time.Now()
+	return true
+}
+
+// LastSeen returns the last time the node stored in the entry was known
+// to be alive, or the zero time if the entry is empty.
+func (e *routingEntry) LastSeen() time.Time {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.lastSeen
+}
+
 // Get retrieves the current node stored in the routing entry.
 // The read is protected by a read lock to allow concurrent access.
 //
@@ -64,31 +95,38 @@ func (e *routingEntry) Get() *domain.Node {
 //   - space: identifier space configuration (bit-length and graph degree).
 //   - self: the local node that owns this routing table.
 //   - successorList: a list of O(log n) successors, providing redundancy
-//     and fault tolerance against node failures.
+//     and fault tolerance against node failures. Its length starts at
+//     space.SuccListSize and can be changed with ResizeSuccessorList.
 //   - predecessor: the immediate predecessor of this node on the ring.
 //   - deBruijn: the De Bruijn window (routing entries anchored at
 //     predecessor(k*m), followed by successors that simulate base-k
-//     de Bruijn edges).
+//     de Bruijn edges). Each digit holds its pointer followed by up to
+//     deBruijnBackups backups (see WithDeBruijnBackups).
 type RoutingTable struct {
-	logger        logger.Logger   // logger for routing table operations
-	space         domain.Space    // identifier space and de Bruijn graph degree
-	self          *domain.Node    // the local node owning this routing table
-	successorList []*routingEntry // O(log n) (set by configuration) successors for fault tolerance
-	predecessor   *routingEntry   // immediate predecessor in the ring
-	deBruijn      []*routingEntry // de Bruijn window entries for base-k routing
+	logger        logger.Logger     // logger for routing table operations
+	space         domain.Space      // identifier space and de Bruijn graph degree
+	self          *domain.Node      // the local node owning this routing table
+	successorList []*routingEntry   // O(log n) (set by configuration) successors for fault tolerance
+	succMu        sync.RWMutex      // guards the successorList slice itself (entries have their own locks)
+	predecessor   *routingEntry     // immediate predecessor in the ring
+	deBruijn      [][]*routingEntry // de Bruijn window: per digit, the pointer followed by its backups
+	// deBruijnBackups is the number of backups of each de Bruijn digit
+	deBruijnBackups int
 }
 
 // New creates and initializes a new RoutingTable for the given node.
 //
 // The routing table is initialized with empty successor entries, an empty
-// predecessor entry, and a de Bruijn window of size space.GraphGrade.
+// predecessor entry, and a de Bruijn window of size space.GraphGrade
+// (each digit with room for its backups, none by default).
 // By default, logging is disabled (NopLogger) unless overridden with options.
 //
 // Arguments:
 //   - self: the local node owning this routing table.
 //   - space: the identifier space configuration (bit-length and graph degree).
 //   - succListSize: the size of the successor list (typically O(log n)).
-//   - opts: functional options to customize the routing table (logger).
+//   - opts: functional options to customize the routing table (logger,
+//     de Bruijn backups).
 //
 // Returns:
 //   - *RoutingTable: a pointer to the newly created routing table, with all
@@ -99,21 +137,24 @@ func New(self *domain.Node, space domain.Space, opts ...Option) *RoutingTable {
 		space:         space,
 		successorList: make([]*routingEntry, space.SuccListSize), // successors initially nil
 		predecessor:   &routingEntry{},                           // predecessor initially nil
-		deBruijn:      make([]*routingEntry, space.GraphGrade),   // base-k de Bruijn window initially nil
+		deBruijn:      make([][]*routingEntry, space.GraphGrade), // base-k de Bruijn window initially nil
 		logger:        &logger.NopLogger{},                       // default: no logging
 	}
 	// Initialize successor list entries with empty routingEntry structs.
 	for i := range rt.successorList {
 		rt.successorList[i] = &routingEntry{}
 	}
-	// Initialize de Bruijn entries with empty routingEntry structs.
-	for i := range rt.deBruijn {
-		rt.deBruijn[i] = &routingEntry{}
-	}
-	// Apply functional options (custom logger).
+	// Apply functional options (custom logger, de Bruijn backups).
 	for _, opt := range opts {
 		opt(rt)
 	}
+	// Initialize de Bruijn entries (pointer and backups) with empty routingEntry structs.
+	for i := range rt.deBruijn {
+		rt.deBruijn[i] = make([]*routingEntry, 1+rt.deBruijnBackups)
+		for j := range rt.deBruijn[i] {
+			rt.deBruijn[i][j] = &routingEntry{}
+		}
+	}
 	return rt
 }
 
@@ -150,11 +191,13 @@ func (rt *RoutingTable) Self() *domain.Node {
 // the method returns nil. The underlying routingEntry manages its own
 // synchronization to ensure thread-safe concurrent access.
 func (rt *RoutingTable) GetSuccessor(i int) *domain.Node {
-	if i < 0 || i >= rt.Space().SuccListSize {
+	rt.succMu.RLock()
+	defer rt.succMu.RUnlock()
+	if i < 0 || i >= len(rt.successorList) {
 		rt.logger.Warn(
 			"GetSuccessor: index out of range",
 			logger.F("requested", i),
-			logger.F("valid_range", fmt.Sprintf("[0..%d]", rt.Space().SuccListSize-1)),
+			logger.F("valid_range", fmt.Sprintf("[0..%d]", len(rt.successorList)-1)),
 		)
 		return nil
 	}
@@ -174,17 +217,70 @@ func (rt *RoutingTable) FirstSuccessor() *domain.Node {
 // The underlying routingEntry manages its own synchronization to ensure
 // thread-safe updates.
 func (rt *RoutingTable) SetSuccessor(i int, node *domain.Node) {
-	if i < 0 || i >= rt.Space().SuccListSize {
+	rt.succMu.RLock()
+	defer rt.succMu.RUnlock()
+	rt.setSuccessorLocked(i, node)
+}
+
+// setSuccessorLocked is SetSuccessor for callers holding succMu.
+func (rt *RoutingTable) setSuccessorLocked(i int, node *domain.Node) {
+	if i < 0 || i >= len(rt.successorList) {
 		rt.logger.Warn(
 			"SetSuccessor: index out of range",
 			logger.F("requested", i),
-			logger.F("valid_range", fmt.Sprintf("[0..%d]", rt.Space().SuccListSize-1)),
+			logger.F("valid_range", fmt.Sprintf("[0..%d]", len(rt.successorList)-1)),
 		)
 		return
 	}
 	rt.successorList[i].Set(node)
 }
 
+// SuccessorListSize returns the current length of the successor list
+// (including empty entries).
+func (rt *RoutingTable) SuccessorListSize() int {
+	rt.succMu.RLock()
+	defer rt.succMu.RUnlock()
+	return len(rt.successorList)
+}
+
+// ResizeSuccessorList changes the length of the successor list to size
+// (at least 1).
+//
+// Behavior:
+//   - Growing appends empty entries, to be filled by stabilization.
+//   - Shrinking drops the entries at the tail of the list; the nodes they
+//     held are returned so that the caller can release them.
+//
+// The slice is replaced under the successor list write lock, so that
+// concurrent readers see either the old or the new list, never a mix.
+func (rt *RoutingTable) ResizeSuccessorList(size int) []*domain.Node {
+	if size < 1 {
+		size = 1
+	}
+	rt.succMu.Lock()
+	defer rt.succMu.Unlock()
+	old := len(rt.successorList)
+	if size == old {
+		return nil
+	}
+	var dropped []*domain.Node
+	if size > old {
+		for i := old; i < size; i++ {
+			rt.successorList = append(rt.successorList, &routingEntry{})
+		}
+	} else {
+		for _, e := range rt.successorList[size:] {
+			if n := e.Get(); n != nil {
+				dropped = append(dropped, n)
+			}
+		}
+		rt.successorList = slices.Clip(rt.successorList[:size])
+	}
+	rt.logger.Debug("ResizeSuccessorList: successor list resized",
+		logger.F("from", old), logger.F("to", size))
+	return dropped
+}
+
 // SuccessorList returns a slice of all non-nil successors currently known
 // in the routing table.
 //
@@ -193,6 +289,8 @@ func (rt *RoutingTable) SetSuccessor(i int, node *domain.Node) {
 // node are skipped. Callers receive a shallow copy of the successor list and
 // may safely modify it without affecting the internal state.
 func (rt *RoutingTable) SuccessorList() []*domain.Node {
+	rt.succMu.RLock()
+	defer rt.succMu.RUnlock()
 	out := make([]*domain.Node, 0, len(rt.successorList))
 	for _, entry := range rt.successorList {
 		node := entry.Get()
@@ -211,7 +309,14 @@ func (rt *RoutingTable) SuccessorList() []*domain.Node {
 //
 // Each entry is updated under a write lock on the individual routing entries.
 func (rt *RoutingTable) SetSuccessorList(nodes []*domain.Node) {
-	expected := rt.Space().SuccListSize
+	rt.succMu.RLock()
+	defer rt.succMu.RUnlock()
+	rt.setSuccessorListLocked(nodes)
+}
+
+// setSuccessorListLocked is SetSuccessorList for callers holding succMu.
+func (rt *RoutingTable) setSuccessorListLocked(nodes []*domain.Node) {
+	expected := len(rt.successorList)
 
 	if len(nodes) > expected {
 		rt.logger.Warn(
@@ -224,12 +329,12 @@ func (rt *RoutingTable) SetSuccessorList(nodes []*domain.Node) {
 
 	// fill entries with provided nodes
 	for i, node := range nodes {
-		rt.SetSuccessor(i, node)
+		rt.setSuccessorLocked(i, node)
 	}
 
 	// pad with nil if input shorter than expected
 	for i := len(nodes); i < expected; i++ {
-		rt.SetSuccessor(i, nil)
+		rt.setSuccessorLocked(i, nil)
 	}
 }
 
@@ -248,7 +353,9 @@ func (rt *RoutingTable) SetSuccessorList(nodes []*domain.Node) {
 //   - i: the index of the candidate successor to promote.
 //     If i <= 0 or out of range, the function does nothing.
 func (rt *RoutingTable) PromoteCandidate(i int) {
-	expected := rt.Space().SuccListSize
+	rt.succMu.RLock()
+	defer rt.succMu.RUnlock()
+	expected := len(rt.successorList)
 	if i <= 0 || i >= expected {
 		rt.logger.Warn(
 			"PromoteCandidate: invalid index",
@@ -277,7 +384,7 @@ func (rt *RoutingTable) PromoteCandidate(i int) {
 		}
 	}
 	// remaining slots stay nil
-	rt.SetSuccessorList(newList)
+	rt.setSuccessorListLocked(newList)
 	// log the promotion
 	rt.logger.Debug(
 		"PromoteCandidate: successor promoted",
@@ -304,8 +411,21 @@ func (rt *RoutingTable) SetPredecessor(node *domain.Node) {
 	rt.predecessor.Set(node)
 }
 
+// TouchPredecessor records a liveness signal from the predecessor: if the
+// current predecessor has the given ID, its last-seen time is refreshed.
+// It reports whether the predecessor was refreshed.
+func (rt *RoutingTable) TouchPredecessor(id domain.ID) bool {
+	return rt.predecessor.Touch(id)
+}
+
+// PredecessorLastSeen returns the last time the current predecessor was
+// known to be alive, or the zero time if no predecessor is set.
+func (rt *RoutingTable) PredecessorLastSeen() time.Time {
+	return rt.predecessor.LastSeen()
+}
+
 // GetDeBruijn returns the node pointer stored in the de Bruijn entry
-// corresponding to the given digit.
+// corresponding to the given digit (not its backups, see DeBruijnBackups).
 //
 // If digit is out of range, the method returns nil.
 // The underlying routingEntry manages its own synchronization
@@ -319,10 +439,12 @@ func (rt *RoutingTable) GetDeBruijn(digit int) *domain.Node {
 		)
 		return nil
 	}
-	return rt.deBruijn[digit].Get()
+	return rt.deBruijn[digit][0].Get()
 }
 
 // SetDeBruijn updates the de Bruijn entry for the given digit with the specified node.
+// The backups of the digit are cleared: they belong to the previous pointer
+// (see SetDeBruijnBackups).
 //
 // If digit is out of range, the method logs a warning and does nothing.
 // The underlying routingEntry manages its own synchronization
@@ -336,11 +458,56 @@ func (rt *RoutingTable) SetDeBruijn(digit int, node *domain.Node) {
 		)
 		return
 	}
-	rt.deBruijn[digit].Set(node)
+	rt.deBruijn[digit][0].Set(node)
+	for _, e := range rt.deBruijn[digit][1:] {
+		e.Set(nil)
+	}
+}
+
+// DeBruijnBackupSize returns the number of backups kept for each de
+// Bruijn digit (see WithDeBruijnBackups).
+func (rt *RoutingTable) DeBruijnBackupSize() int {
+	return rt.deBruijnBackups
+}
+
+// DeBruijnBackups returns the non-nil backups of the given digit, in order
+// of preference. It returns nil if digit is out of range.
+func (rt *RoutingTable) DeBruijnBackups(digit int) []*domain.Node {
+	if digit < 0 || digit >= len(rt.deBruijn) {
+		return nil
+	}
+	var out []*domain.Node
+	for _, e := range rt.deBruijn[digit][1:] {
+		if node := e.Get(); node != nil {
+			out = append(out, node)
+		}
+	}
+	return out
+}
+
+// SetDeBruijnBackups replaces the backups of the given digit with nodes,
+// in order of preference: extra nodes are truncated, missing ones set to
+// nil. If digit is out of range, the method logs a warning and does nothing.
+func (rt *RoutingTable) SetDeBruijnBackups(digit int, nodes []*domain.Node) {
+	if digit < 0 || digit >= len(rt.deBruijn) {
+		rt.logger.Warn(
+			"SetDeBruijnBackups: index out of range",
+			logger.F("requested", digit),
+			logger.F("valid_range", fmt.Sprintf("[0..%d]", len(rt.deBruijn)-1)),
+		)
+		return
+	}
+	for i, e := range rt.deBruijn[digit][1:] {
+		if i < len(nodes) {
+			e.Set(nodes[i])
+		} else {
+			e.Set(nil)
+		}
+	}
 }
 
 // DeBruijnList returns a slice of all non-nil de Bruijn entries currently known
-// in the routing table.
+// in the routing table (the pointers, without their backups).
 //
 // Each entry is read under a read lock to ensure thread-safe access.
 // The returned slice contains only initialized de Bruijn pointers; entries
@@ -353,19 +520,35 @@ func (rt *RoutingTable) SetDeBruijn(digit int, node *domain.Node) {
 //	// the returned slice will be [n1, n2].
 func (rt *RoutingTable) DeBruijnList() []*domain.Node {
 	out := make([]*domain.Node, 0, len(rt.deBruijn))
-	for _, entry := range rt.deBruijn {
-		if node := entry.Get(); node != nil {
+	for _, entries := range rt.deBruijn {
+		if node := entries[0].Get(); node != nil {
 			out = append(out, node)
 		}
 	}
 	return out
 }
 
+// DeBruijnWindow returns, for each digit with a non-nil pointer, the
+// pointer followed by its non-nil backups. The i-th element corresponds to
+// the i-th node returned by DeBruijnList.
+func (rt *RoutingTable) DeBruijnWindow() [][]*domain.Node {
+	out := make([][]*domain.Node, 0, len(rt.deBruijn))
+	for i, entries := range rt.deBruijn {
+		node := entries[0].Get()
+		if node == nil {
+			continue
+		}
+		out = append(out, append([]*domain.Node{node}, rt.DeBruijnBackups(i)...))
+	}
+	return out
+}
+
 // SetDeBruijnList replaces the entire de Bruijn window with the provided slice.
 //
 // Behavior:
 //   - If len(nodes) > len(deBruijn), extra nodes are truncated.
 //   - If len(nodes) < len(deBruijn), missing entries are set to nil.
+//   - The backups of every digit are cleared (see SetDeBruijn).
 //
 // Each entry is updated under a write lock on the individual routing entries.
 // This method does not modify the size of the de Bruijn window.
@@ -405,15 +588,19 @@ func (rt *RoutingTable) SetDeBruijnList(nodes []*domain.Node) {
 //   - Self node (the node that owns this routing table)
 //   - Predecessor (nil if not set)
 //   - Successor list (all entries, including nils, with indices)
-//   - De Bruijn list (all entries, including nils, with digits)
+//   - De Bruijn list (all entries, including nils, with digits and the
+//     addresses of their backups)
 func (rt *RoutingTable) DebugLog() {
 	self := rt.self
 	pred := rt.GetPredecessor()
 
 	// successors snapshot
-	successors := make([]map[string]any, 0, len(rt.successorList))
-	for i := range rt.successorList {
-		if node := rt.GetSuccessor(i); node == nil {
+	rt.succMu.RLock()
+	entries := slices.Clone(rt.successorList)
+	rt.succMu.RUnlock()
+	successors := make([]map[string]any, 0, len(entries))
+	for i, e := range entries {
+		if node := e.Get(); node == nil {
 			successors = append(successors, map[string]any{"index": i, "node": nil})
 		} else {
 			successors = append(successors, map[string]any{
@@ -431,12 +618,20 @@ func (rt *RoutingTable) DebugLog() {
 		if node := rt.GetDeBruijn(i); node == nil {
 			debruijn = append(debruijn, map[string]any{"digit": i, "node": nil})
 		} else {
-			debruijn = append(debruijn, map[string]any{
+			entry := map[string]any{
 				"digit": i,
 				"idhex": node.ID.ToHexString(true),
 				"idbin": node.ID.ToBinaryString(true),
 				"addr":  node.Addr,
-			})
+			}
+			if backups := rt.DeBruijnBackups(i); len(backups) > 0 {
+				addrs := make([]string, len(backups))
+				for j, b := range backups {
+					addrs[j] = b.Addr
+				}
+				entry["backups"] = addrs
+			}
+			debruijn = append(debruijn, entry)
 		}
 	}
 
@@ -0,0 +1,106 @@
+package server_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// entry è una voce di log registrata da recLogger.
+type entry struct {
+	msg    string
+	fields map[string]any
+}
+
+// recLogger registra i messaggi Info ricevuti (le voci di audit).
+type recLogger struct {
+	logger.NopLogger
+	mu      sync.Mutex
+	entries []entry
+}
+
+func (l *recLogger) Info(msg string, fields ...logger.Field) {
+	e := entry{msg: msg, fields: make(map[string]any)}
+	for _, f := range fields {
+		e.fields[f.Key] = f.Val
+	}
+	l.mu.Lock()
+	l.entries = append(l.entries, e)
+	l.mu.Unlock()
+}
+
+func (l *recLogger) snapshot() []entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]entry(nil), l.entries...)
+}
+
+func TestAccessLogRecordsGet(t *testing.T) {
+	audit := &recLogger{}
+	r := testring.New(t, 3, testring.WithServerOptions(server.WithAccessLog(audit)))
+	m := r.Members[0]
+
+	api, conn, err := client.Connect(m.Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := client.Put(ctx, api, "audited", "v"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		key    string
+		result string
+	}{
+		{name: "existing key", key: "audited", result: "OK"},
+		{name: "missing key", key: "missing", result: "NotFound"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqID := "req-" + tt.key
+			rctx := metadata.AppendToOutgoingContext(ctx, server.RequestIDHeader, reqID)
+			_, _, _ = client.Get(rctx, api, tt.key)
+
+			var got *entry
+			for _, e := range audit.snapshot() {
+				if e.fields["requestId"] == reqID {
+					got = &e
+				}
+			}
+			if got == nil {
+				t.Fatalf("no access log entry for request %s", reqID)
+			}
+			want := map[string]any{"op": "get", "key": tt.key, "result": tt.result, "requestId": reqID}
+			for k, v := range want {
+				if got.fields[k] != v {
+					t.Errorf("field %s = %v, want %v", k, got.fields[k], v)
+				}
+			}
+			if peer, _ := got.fields["peer"].(string); !strings.HasPrefix(peer, "127.0.0.1:") {
+				t.Errorf("field peer = %q, want the client address", peer)
+			}
+		})
+	}
+
+	// Solo Get/Put/Delete dei client producono voci di audit
+	for _, e := range audit.snapshot() {
+		switch e.fields["op"] {
+		case "get", "put", "delete":
+		default:
+			t.Errorf("unexpected access log entry: %v", e.fields)
+		}
+	}
+}
@@ -3,12 +3,18 @@ package server
 import (
 	clientv1 "KoordeDHT/internal/api/client/v1"
 	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
 	"KoordeDHT/internal/node/ctxutil"
 	"KoordeDHT/internal/node/logicnode"
 	"KoordeDHT/internal/node/telemetry"
 	"KoordeDHT/internal/node/telemetry/lookuptrace"
 	"context"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
@@ -25,6 +31,9 @@ import (
 type clientService struct {
 	clientv1.UnimplementedClientAPIServer                 // forward compatibility with proto changes
 	node                                  *logicnode.Node // reference to the local Koorde node
+	config                                []logger.Field  // effective configuration returned by GetConfig (nil = not available)
+	maxValueBytes                         int64           // largest value accepted by Put and BatchPut (0 = no limit)
+	shutdown                              func()          // stops the server gracefully once Shutdown has replied (nil = no server)
 }
 
 // NewClientService constructs a new client-facing gRPC service bound to the given node.
@@ -48,8 +57,15 @@ func NewClientService(n *logicnode.Node) clientv1.ClientAPIServer {
 // Behavior:
 //   - If the context is canceled or its deadline expires, the call is aborted.
 //   - If the request is invalid (nil resource, missing key/value), an InvalidArgument error is returned.
+//   - If the value is larger than the configured limit (see WithMaxValueBytes),
+//     an InvalidArgument error is returned.
 //   - Otherwise, the resource is converted into a domain.Resource, its ID is computed
-//     by hashing the raw key, and it is inserted into the DHT via the local node.
+//     by hashing the raw key (or taken from the pre-hashed id, see keyID), and it is
+//     inserted into the DHT via the local node.
+//   - A non-zero ttl_seconds makes the resource expire that long after the call;
+//     expired resources are reported as not found and evicted.
+//   - If the owner's storage quota or maximum number of keys is reached, a
+//     ResourceExhausted error is returned.
 func (s *clientService) Put(ctx context.Context, req *clientv1.PutRequest) (*emptypb.Empty, error) {
 	// Validate context
 	if err := ctxutil.CheckContext(ctx); err != nil {
@@ -66,24 +82,136 @@ func (s *clientService) Put(ctx context.Context, req *clientv1.PutRequest) (*emp
 	if req.Resource.Value == "" {
 		return nil, status.Error(codes.InvalidArgument, "missing value")
 	}
+	if s.tooLarge(req.Resource.Value) {
+		return nil, status.Errorf(codes.InvalidArgument, "value of %d bytes exceeds the limit of %d bytes",
+			len(req.Resource.Value), s.maxValueBytes)
+	}
 
-	// Convert client resource to domain resource (ID derived from RawKey)
+	// Convert client resource to domain resource (ID derived from RawKey,
+	// unless the client sent it pre-hashed)
 	res := domain.ResourceFromProtoClient(s.node.Space(), req.Resource)
+	id, err := s.keyID(req.Resource.Key, req.Resource.Id)
+	if err != nil {
+		return nil, err
+	}
+	res.Key = id
+	ttl := time.Duration(req.GetTtlSeconds()) * time.Second
 
 	// Store resource
-	if err := s.node.Put(ctx, *res); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to store resource: %v", err)
+	if err := s.node.Put(ctx, res.WithTTL(time.Now(), ttl)); err != nil {
+		code, ok := lookupCode(err)
+		if !ok {
+			code = storeCode(err)
+		}
+		return nil, failureStatus(code, fmt.Sprintf("failed to store resource: %v", err), err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// PutIf stores a resource only if a condition holds for the resource
+// currently stored under its key: if_absent (put-if-absent) or if_equal
+// (compare-and-swap on the current value). The check and the write are
+// atomic on the responsible node.
+//
+// Errors are as for Put, plus:
+//   - codes.InvalidArgument if neither or both conditions are set
+//   - codes.FailedPrecondition if the condition does not hold
+func (s *clientService) PutIf(ctx context.Context, req *clientv1.PutIfRequest) (*emptypb.Empty, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if req == nil || req.Resource == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing resource")
+	}
+	if req.Resource.Key == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing key")
+	}
+	if req.Resource.Value == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing value")
+	}
+	if s.tooLarge(req.Resource.Value) {
+		return nil, status.Errorf(codes.InvalidArgument, "value of %d bytes exceeds the limit of %d bytes",
+			len(req.Resource.Value), s.maxValueBytes)
+	}
+	if req.GetIfAbsent() == req.GetIfEqual() {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of if_absent and if_equal must be set")
+	}
+	cond := domain.Condition{IfAbsent: req.GetIfAbsent(), IfEqual: req.GetIfEqual(), Expected: req.GetExpected()}
+
+	res := domain.ResourceFromProtoClient(s.node.Space(), req.Resource)
+	id, err := s.keyID(req.Resource.Key, req.Resource.Id)
+	if err != nil {
+		return nil, err
+	}
+	res.Key = id
+	ttl := time.Duration(req.GetTtlSeconds()) * time.Second
+
+	// Store resource if the condition holds
+	if err := s.node.PutIf(ctx, res.WithTTL(time.Now(), ttl), cond); err != nil {
+		code, ok := lookupCode(err)
+		if !ok {
+			code = storeCode(err)
+		}
+		return nil, failureStatus(code, fmt.Sprintf("failed to store resource: %v", err), err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
+// PutContent stores a value under a key derived from the value itself
+// (content addressing): the key ID is the hash of the value, as
+// Space.NewIdFromBytes computes it, and is returned to the client as a hex
+// string, usable as the pre-hashed ID of Get and Delete. Storing the same
+// value twice yields the same key.
+//
+// Errors are as for Put.
+func (s *clientService) PutContent(ctx context.Context, req *clientv1.PutContentRequest) (*clientv1.PutContentResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if req == nil || req.Value == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing value")
+	}
+	if s.tooLarge(req.Value) {
+		return nil, status.Errorf(codes.InvalidArgument, "value of %d bytes exceeds the limit of %d bytes",
+			len(req.Value), s.maxValueBytes)
+	}
+
+	// Derive the key from the value: the raw key is the hex ID, as for the
+	// pre-hashed keys of Put
+	id := s.node.Space().NewIdFromBytes([]byte(req.Value))
+	idHex := id.ToHexString(true)
+	res := domain.Resource{Key: id, RawKey: idHex, Value: req.Value}
+	ttl := time.Duration(req.GetTtlSeconds()) * time.Second
+
+	// Store resource
+	if err := s.node.Put(ctx, res.WithTTL(time.Now(), ttl)); err != nil {
+		code, ok := lookupCode(err)
+		if !ok {
+			code = storeCode(err)
+		}
+		return nil, failureStatus(code, fmt.Sprintf("failed to store resource: %v", err), err)
+	}
+
+	return &clientv1.PutContentResponse{Id: idHex}, nil
+}
+
 // Get retrieves a resource by its raw key.
 //
 // Behavior:
 //   - If the context is canceled or its deadline expires, the call is aborted.
 //   - If the request is invalid (nil or missing key), an InvalidArgument error is returned.
 //   - If the resource does not exist, a NotFound error is returned.
+//   - If the stored resource fails its checksum, a DataLoss error is returned.
+//   - If the node responsible for the key cannot be located, an Unavailable
+//     error is returned (see lookupCode).
 //   - Otherwise, the resource is returned in the response.
 func (s *clientService) Get(
 	ctx context.Context,
@@ -99,16 +227,28 @@ func (s *clientService) Get(
 		return nil, status.Error(codes.InvalidArgument, "missing key")
 	}
 
-	// Derive ID from raw key
-	id := s.node.Space().NewIdFromString(req.Key)
+	// Derive ID from raw key (or take the pre-hashed one)
+	id, err := s.keyID(req.Key, req.Id)
+	if err != nil {
+		return nil, err
+	}
 
 	// Lookup resource
 	res, err := s.node.Get(ctx, id)
 	if err != nil {
-		if errors.Is(err, domain.ErrResourceNotFound) {
+		if code, ok := lookupCode(err); ok {
+			return nil, failureStatus(code, fmt.Sprintf("failed to retrieve resource: %v", err), err)
+		}
+		if errors.Is(err, domain.ErrResourceNotFound) || status.Code(err) == codes.NotFound {
 			return nil, status.Error(codes.NotFound, "resource not found")
 		}
-		return nil, status.Errorf(codes.Internal, "failed to retrieve resource: %v", err)
+		if status.Code(err) == codes.DataLoss {
+			return nil, failureStatus(codes.DataLoss, "resource corrupted", err)
+		}
+		if deadlineExceeded(err) {
+			return nil, failureStatus(codes.DeadlineExceeded, fmt.Sprintf("failed to retrieve resource: %v", err), err)
+		}
+		return nil, failureStatus(codes.Internal, fmt.Sprintf("failed to retrieve resource: %v", err), err)
 	}
 	if res == nil {
 		return nil, status.Error(codes.NotFound, "resource not found")
@@ -116,7 +256,9 @@ func (s *clientService) Get(
 
 	// Convert to client-facing response using helper
 	return &clientv1.GetResponse{
-		Value: res.Value,
+		Value:    res.Value,
+		Origin:   res.ToProtoClient().GetOrigin(),
+		Metadata: res.Metadata,
 	}, nil
 }
 
@@ -138,20 +280,266 @@ func (s *clientService) Delete(ctx context.Context, req *clientv1.DeleteRequest)
 		return nil, status.Error(codes.InvalidArgument, "missing key")
 	}
 
-	// Derive ID from raw key
-	id := s.node.Space().NewIdFromString(req.Key)
+	// Derive ID from raw key (or take the pre-hashed one)
+	id, err := s.keyID(req.Key, req.Id)
+	if err != nil {
+		return nil, err
+	}
 
 	// Perform delete
 	if err := s.node.Delete(ctx, id); err != nil {
-		if errors.Is(err, domain.ErrResourceNotFound) {
+		if code, ok := lookupCode(err); ok {
+			return nil, failureStatus(code, fmt.Sprintf("failed to delete resource: %v", err), err)
+		}
+		if errors.Is(err, domain.ErrResourceNotFound) || status.Code(err) == codes.NotFound {
 			return nil, status.Error(codes.NotFound, "resource not found")
 		}
-		return nil, status.Errorf(codes.Internal, "failed to delete resource: %v", err)
+		if deadlineExceeded(err) {
+			return nil, failureStatus(codes.DeadlineExceeded, fmt.Sprintf("failed to delete resource: %v", err), err)
+		}
+		return nil, failureStatus(codes.Internal, fmt.Sprintf("failed to delete resource: %v", err), err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
+// tooLarge reports whether value exceeds the limit set by WithMaxValueBytes.
+func (s *clientService) tooLarge(value string) bool {
+	return s.maxValueBytes > 0 && int64(len(value)) > s.maxValueBytes
+}
+
+// batchChunk is the maximum number of keys BatchPut and BatchDelete collect
+// from the stream before processing them.
+const batchChunk = 256
+
+// BatchPut stores the resources streamed by the client and replies, once
+// the client closes its side of the stream, with the number of resources
+// stored and the ones that failed.
+//
+// Behavior:
+//   - Resources are collected in chunks of up to batchChunk and each chunk
+//     is stored with one Store stream per owner (see
+//     logicnode.Node.PutBatch); ttl_seconds applies as in Put.
+//   - Invalid resources (missing key or value, value over the limit) and failed stores are
+//     reported in the response, without aborting the stream.
+//   - If the context is canceled or the stream breaks, the call is aborted.
+func (s *clientService) BatchPut(stream clientv1.ClientAPI_BatchPutServer) error {
+	ctx := stream.Context()
+	resp := &clientv1.BatchPutResponse{}
+	var reqs []*clientv1.PutRequest
+	flush := func() {
+		now := time.Now()
+		batch := make([]domain.Resource, 0, len(reqs))
+		keys := make([]string, 0, len(reqs))
+		for _, req := range reqs {
+			switch {
+			case req.GetResource().GetKey() == "":
+				resp.Failed = append(resp.Failed, &clientv1.BatchPutFailure{Error: "missing key"})
+			case req.GetResource().GetValue() == "":
+				resp.Failed = append(resp.Failed, &clientv1.BatchPutFailure{Key: req.Resource.Key, Error: "missing value"})
+			case s.tooLarge(req.Resource.Value):
+				resp.Failed = append(resp.Failed, &clientv1.BatchPutFailure{Key: req.Resource.Key, Error: "value too large"})
+			default:
+				id, err := s.keyID(req.Resource.Key, req.Resource.Id)
+				if err != nil {
+					resp.Failed = append(resp.Failed, &clientv1.BatchPutFailure{Key: req.Resource.Key, Error: "invalid key ID"})
+					continue
+				}
+				res := domain.ResourceFromProtoClient(s.node.Space(), req.Resource)
+				res.Key = id
+				batch = append(batch, res.WithTTL(now, time.Duration(req.GetTtlSeconds())*time.Second))
+				keys = append(keys, req.Resource.Key)
+			}
+		}
+		for i, err := range s.node.PutBatch(ctx, batch) {
+			if err != nil {
+				resp.Failed = append(resp.Failed, &clientv1.BatchPutFailure{Key: keys[i], Error: err.Error()})
+				continue
+			}
+			resp.Stored++
+		}
+		reqs = reqs[:0]
+	}
+
+	for {
+		// Validate context
+		if err := ctxutil.CheckContext(ctx); err != nil {
+			return err
+		}
+		req, err := stream.Recv()
+		if err == io.EOF {
+			flush()
+			return stream.SendAndClose(resp)
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to receive resource: %v", err)
+		}
+		reqs = append(reqs, req)
+		if len(reqs) == batchChunk {
+			flush()
+		}
+	}
+}
+
+// BatchGet retrieves several keys at once.
+//
+// Behavior:
+//   - If the context is canceled or its deadline expires, the call is aborted.
+//   - If the request has no keys or an empty key, an InvalidArgument error is returned.
+//   - Keys are grouped by owner (see logicnode.Node.GetBatch) and each is
+//     reported as found (with its value), not found, or failed (with the
+//     error); duplicate keys are reported once.
+func (s *clientService) BatchGet(ctx context.Context, req *clientv1.BatchGetRequest) (*clientv1.BatchGetResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if req == nil || len(req.Keys) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing keys")
+	}
+	var keys []string
+	seen := make(map[string]struct{}, len(req.Keys))
+	for _, k := range req.Keys {
+		if k == "" {
+			return nil, status.Error(codes.InvalidArgument, "missing key")
+		}
+		if _, dup := seen[k]; dup {
+			continue
+		}
+		seen[k] = struct{}{}
+		keys = append(keys, k)
+	}
+
+	ids := make([]domain.ID, len(keys))
+	for i, k := range keys {
+		ids[i] = s.node.Space().NewIdFromString(k)
+	}
+	found, errs := s.node.GetBatch(ctx, ids)
+
+	resp := &clientv1.BatchGetResponse{
+		Found:  make(map[string]string),
+		Failed: make(map[string]string),
+	}
+	for i, k := range keys {
+		switch err := errs[i]; {
+		case err == nil && found[i] != nil:
+			resp.Found[k] = found[i].Value
+		case err == nil, errors.Is(err, domain.ErrResourceNotFound), status.Code(err) == codes.NotFound:
+			resp.NotFound = append(resp.NotFound, k)
+		default:
+			resp.Failed[k] = err.Error()
+		}
+	}
+	return resp, nil
+}
+
+// BatchDelete removes the keys streamed by the client, replying with one
+// BatchDeleteResult per key, in order.
+//
+// Behavior:
+//   - Keys are collected in chunks of up to batchChunk (or until the
+//     client closes its side of the stream) and each chunk is deleted with
+//     one batched call per owner (see logicnode.Node.DeleteBatch).
+//   - A missing key is reported as not deleted, without aborting the stream;
+//     empty keys and failed deletions are reported in the result error.
+//   - If the context is canceled or the stream breaks, the call is aborted.
+func (s *clientService) BatchDelete(stream clientv1.ClientAPI_BatchDeleteServer) error {
+	ctx := stream.Context()
+	var keys []string
+	flush := func() error {
+		ids := make([]domain.ID, 0, len(keys))
+		for _, k := range keys {
+			if k != "" {
+				ids = append(ids, s.node.Space().NewIdFromString(k))
+			}
+		}
+		errs := s.node.DeleteBatch(ctx, ids)
+		next := 0
+		for _, k := range keys {
+			res := &clientv1.BatchDeleteResult{Key: k}
+			if k == "" {
+				res.Error = "missing key"
+			} else {
+				err := errs[next]
+				next++
+				switch {
+				case err == nil:
+					res.Deleted = true
+				case errors.Is(err, domain.ErrResourceNotFound) || status.Code(err) == codes.NotFound:
+				default:
+					res.Error = err.Error()
+				}
+			}
+			if err := stream.Send(res); err != nil {
+				return status.Errorf(codes.Internal, "failed to send result: %v", err)
+			}
+		}
+		keys = keys[:0]
+		return nil
+	}
+
+	for {
+		// Validate context
+		if err := ctxutil.CheckContext(ctx); err != nil {
+			return err
+		}
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return flush()
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to receive key: %v", err)
+		}
+		keys = append(keys, req.GetKey())
+		if len(keys) == batchChunk {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// DeleteRange removes every resource whose key ID lies in (from, to] (see
+// logicnode.Node.DeleteRange) and reports how many were removed.
+//
+// Behavior:
+//   - If the context is canceled or its deadline expires, the call is aborted.
+//   - The bounds are hex IDs; from > to wraps around zero and from == to
+//     covers the whole ring.
+//   - A failure on any node aborts the call; the resources already removed
+//     stay removed.
+func (s *clientService) DeleteRange(ctx context.Context, req *clientv1.DeleteRangeRequest) (*clientv1.DeleteRangeResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if req == nil || req.From == "" || req.To == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing range bounds")
+	}
+	from, err := s.node.Space().FromHexString(req.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid range lower bound")
+	}
+	to, err := s.node.Space().FromHexString(req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid range upper bound")
+	}
+
+	removed, err := s.node.DeleteRange(ctx, from, to)
+	if err != nil {
+		code, ok := lookupCode(err)
+		if !ok {
+			code = codes.Internal
+		}
+		return nil, failureStatus(code, fmt.Sprintf("failed to delete range after %d resources: %v", removed, err), err)
+	}
+	return &clientv1.DeleteRangeResponse{Deleted: uint64(removed)}, nil
+}
+
 // GetStore streams all key-value resources stored on this node to the client.
 //
 // Behavior:
@@ -173,11 +561,8 @@ func (s *clientService) GetStore(_ *emptypb.Empty, stream clientv1.ClientAPI_Get
 		}
 
 		res := &clientv1.GetStoreResponse{
-			Id: r.Key.ToHexString(true),
-			Item: &clientv1.Resource{
-				Key:   r.RawKey,
-				Value: r.Value,
-			},
+			Id:   r.Key.ToHexString(true),
+			Item: r.ToProtoClient(),
 		}
 
 		// Send over the stream
@@ -188,6 +573,42 @@ func (s *clientService) GetStore(_ *emptypb.Empty, stream clientv1.ClientAPI_Get
 	return nil
 }
 
+const (
+	defaultPageLimit = 100  // resources per GetStorePage page when the request sets no limit
+	maxPageLimit     = 1000 // upper bound on the limit of a GetStorePage request
+)
+
+// GetStorePage returns one page of the key-value resources stored on this
+// node, ordered by ID, filtered by key prefix, and with at most limit
+// entries (defaultPageLimit if unset, capped at maxPageLimit).
+//
+// The page starts after the resource identified by page_token, the
+// next_page_token of the previous response; an empty next_page_token
+// marks the last page.
+func (s *clientService) GetStorePage(ctx context.Context, req *clientv1.GetStorePageRequest) (*clientv1.GetStorePageResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+	limit = min(limit, maxPageLimit)
+	page, next := s.node.GetStoredPage(req.GetPrefix(), req.GetPageToken(), limit)
+	resp := &clientv1.GetStorePageResponse{
+		Items:         make([]*clientv1.GetStoreResponse, 0, len(page)),
+		NextPageToken: next,
+	}
+	for _, r := range page {
+		resp.Items = append(resp.Items, &clientv1.GetStoreResponse{
+			Id:   r.Key.ToHexString(true),
+			Item: r.ToProtoClient(),
+		})
+	}
+	return resp, nil
+}
+
 // GetRoutingTable returns the current routing table of the node.
 //
 // Behavior:
@@ -196,24 +617,88 @@ func (s *clientService) GetStore(_ *emptypb.Empty, stream clientv1.ClientAPI_Get
 //   - If the predecessor is not known yet, the field is nil.
 //   - Successor and De Bruijn lists may contain fewer entries than
 //     their configured maximum.
+//   - If the node is one of several virtual nodes of the same physical
+//     node, the routing tables of the others are reported in vnodes.
 func (s *clientService) GetRoutingTable(ctx context.Context, _ *emptypb.Empty) (*clientv1.GetRoutingTableResponse, error) {
 	// Check for canceled/expired context
 	if err := ctxutil.CheckContext(ctx); err != nil {
 		return nil, err
 	}
-	self := s.node.Self()
-	pred := s.node.Predecessor()
-	succList := s.node.SuccessorList()
-	deBruijn := s.node.DeBruijnList()
+	resp := routingTableOf(s.node)
+	for _, v := range s.node.VNodes() {
+		resp.Vnodes = append(resp.Vnodes, routingTableOf(v))
+	}
+	return resp, nil
+}
+
+// routingTableOf returns the routing table of n.
+func routingTableOf(n *logicnode.Node) *clientv1.GetRoutingTableResponse {
 	resp := &clientv1.GetRoutingTableResponse{
-		Self:        self.ToProtoClient(),
-		Predecessor: pred.ToProtoClient(),
+		Self:        n.Self().ToProtoClient(),
+		Predecessor: n.Predecessor().ToProtoClient(),
 	}
-	for _, succ := range succList {
+	for _, succ := range n.SuccessorList() {
 		resp.Successors = append(resp.Successors, succ.ToProtoClient())
 	}
-	for _, n := range deBruijn {
-		resp.DeBruijnList = append(resp.DeBruijnList, n.ToProtoClient())
+	for _, d := range n.DeBruijnList() {
+		resp.DeBruijnList = append(resp.DeBruijnList, d.ToProtoClient())
+	}
+	return resp
+}
+
+// GetSpace returns the parameters of the identifier space of the ring, so
+// that clients can derive identifiers the way the nodes do and detect a
+// ring with different parameters.
+func (s *clientService) GetSpace(ctx context.Context, _ *emptypb.Empty) (*clientv1.GetSpaceResponse, error) {
+	// Check for canceled/expired context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	sp := s.node.Space()
+	hash := sp.Hash
+	if hash == "" {
+		hash = domain.DefaultHash
+	}
+	return &clientv1.GetSpaceResponse{
+		IdBits:       uint32(sp.Bits),
+		Degree:       uint32(sp.GraphGrade),
+		SuccListSize: uint32(sp.SuccListSize),
+		Hash:         hash,
+		Namespace:    sp.Namespace,
+	}, nil
+}
+
+// Info returns a summary of the state of the node in a single message:
+// its neighbours, how full the successor and de Bruijn lists are, the
+// number of stored resources and the maximum it holds, its uptime and its
+// pooled connections.
+//
+//   - If the predecessor is not known yet, the field is nil.
+func (s *clientService) Info(ctx context.Context, _ *emptypb.Empty) (*clientv1.InfoResponse, error) {
+	// Check for canceled/expired context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	resp := &clientv1.InfoResponse{
+		Self:              s.node.Self().ToProtoClient(),
+		Predecessor:       s.node.Predecessor().ToProtoClient(),
+		DeBruijnDegree:    uint32(s.node.Space().GraphGrade),
+		StoredKeys:        uint64(len(s.node.GetAllResourceStored())),
+		UptimeMs:          s.node.Uptime().Milliseconds(),
+		PooledConnections: uint32(s.node.PooledConnections()),
+		MaxKeys:           uint64(s.node.MaxStoredKeys()),
+		OutboundInFlight:  uint32(s.node.OutboundInFlight()),
+		MaxOutbound:       uint32(s.node.MaxOutbound()),
+	}
+	for _, succ := range s.node.SuccessorList() {
+		if succ != nil {
+			resp.SuccessorCount++
+		}
+	}
+	for _, d := range s.node.DeBruijnList() {
+		if d != nil {
+			resp.DeBruijnFilled++
+		}
 	}
 	return resp, nil
 }
@@ -223,6 +708,9 @@ func (s *clientService) GetRoutingTable(ctx context.Context, _ *emptypb.Empty) (
 // Errors:
 //   - codes.InvalidArgument if the request is malformed or the ID is invalid
 //   - codes.NotFound if no successor can be determined
+//   - codes.Unavailable if the lookup fails (see lookupCode), which is
+//     usually transient
+//   - codes.DeadlineExceeded if the lookup runs out of time
 //   - codes.Internal if the lookup fails due to internal errors
 func (s *clientService) Lookup(ctx context.Context, req *clientv1.LookupRequest) (*clientv1.LookupResponse, error) {
 	// Validate context
@@ -250,7 +738,12 @@ func (s *clientService) Lookup(ctx context.Context, req *clientv1.LookupRequest)
 	// Perform lookup
 	succ, err := s.node.LookUp(ctx, id)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "lookup failed: %v", err)
+		code, ok := lookupCode(err)
+		if !ok {
+			code = codes.Internal
+		}
+		return nil, failureStatus(code, fmt.Sprintf("lookup failed: %v", err),
+			s.node.Failure(domain.StageRouting, err))
 	}
 	if succ == nil {
 		return nil, status.Error(codes.NotFound, "no successor found")
@@ -261,3 +754,218 @@ func (s *clientService) Lookup(ctx context.Context, req *clientv1.LookupRequest)
 		Successor: succ.ToProtoClient(),
 	}, nil
 }
+
+// LookupTrace finds the node responsible for the given key, like Lookup,
+// and returns the path of the lookup (see logicnode.Node.LookUpTrace).
+//
+// Errors: as Lookup.
+func (s *clientService) LookupTrace(ctx context.Context, req *clientv1.LookupTraceRequest) (*clientv1.LookupTraceResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if req == nil || len(req.Id) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing ID")
+	}
+	id, err := s.node.Space().FromHexString(req.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid ID")
+	}
+
+	ctx = lookuptrace.WithLookup(ctx)
+	if span := trace.SpanFromContext(ctx); span != nil {
+		span.SetAttributes(telemetry.IdAttributes("client.lookup.target", id)...)
+	}
+
+	succ, path, err := s.node.LookUpTrace(ctx, id)
+	if err != nil {
+		code, ok := lookupCode(err)
+		if !ok {
+			code = codes.Internal
+		}
+		return nil, failureStatus(code, fmt.Sprintf("lookup failed after %d hops: %v", len(path)-1, err),
+			s.node.Failure(domain.StageRouting, err))
+	}
+
+	resp := &clientv1.LookupTraceResponse{
+		Successor: succ.ToProtoClient(),
+		Path:      make([]*clientv1.NodeInfo, 0, len(path)),
+		Hops:      uint32(len(path) - 1),
+	}
+	for _, nd := range path {
+		resp.Path = append(resp.Path, nd.ToProtoClient())
+	}
+	return resp, nil
+}
+
+// LookupOwnership finds the node responsible for the given key, like
+// Lookup, together with its predecessor and its replicas, so that
+// operators can check where a key lands (see
+// logicnode.Node.LookUpOwnership). No resource is read or written.
+//
+// Errors: as Lookup; codes.Unavailable also if the responsible node does
+// not report its predecessor or successor list.
+func (s *clientService) LookupOwnership(ctx context.Context, req *clientv1.LookupOwnershipRequest) (*clientv1.LookupOwnershipResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if req == nil || len(req.Id) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing ID")
+	}
+	id, err := s.node.Space().FromHexString(req.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid ID")
+	}
+
+	ctx = lookuptrace.WithLookup(ctx)
+	if span := trace.SpanFromContext(ctx); span != nil {
+		span.SetAttributes(telemetry.IdAttributes("client.lookup.target", id)...)
+	}
+
+	owner, pred, replicas, err := s.node.LookUpOwnership(ctx, id)
+	if err != nil {
+		code, ok := lookupCode(err)
+		if !ok {
+			code = codes.Internal
+		}
+		return nil, failureStatus(code, fmt.Sprintf("lookup failed: %v", err),
+			s.node.Failure(domain.StageRouting, err))
+	}
+
+	resp := &clientv1.LookupOwnershipResponse{
+		Successor: owner.ToProtoClient(),
+		Replicas:  make([]*clientv1.NodeInfo, 0, len(replicas)),
+	}
+	if pred != nil {
+		resp.Predecessor = pred.ToProtoClient()
+	}
+	for _, nd := range replicas {
+		resp.Replicas = append(resp.Replicas, nd.ToProtoClient())
+	}
+	return resp, nil
+}
+
+// GetConfig returns the effective configuration of the node, as set with
+// WithConfig (secrets are expected to be already redacted, see
+// config.Config.Fields).
+//
+// Errors:
+//   - codes.FailedPrecondition if the server was not given a configuration
+func (s *clientService) GetConfig(ctx context.Context, _ *emptypb.Empty) (*clientv1.GetConfigResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	if s.config == nil {
+		return nil, status.Error(codes.FailedPrecondition, "configuration not available")
+	}
+	resp := &clientv1.GetConfigResponse{Entries: make([]*clientv1.ConfigEntry, 0, len(s.config))}
+	for _, f := range s.config {
+		resp.Entries = append(resp.Entries, &clientv1.ConfigEntry{Key: f.Key, Value: fmt.Sprint(f.Val)})
+	}
+	return resp, nil
+}
+
+// PauseStabilization suspends the maintenance loops of the node for the
+// requested duration (see logicnode.Node.PauseStabilization); they resume
+// by themselves afterwards. RPCs keep being served meanwhile. A zero
+// duration resumes them immediately.
+func (s *clientService) PauseStabilization(ctx context.Context, req *clientv1.PauseStabilizationRequest) (*clientv1.PauseStabilizationResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	d := time.Duration(req.GetDurationMs()) * time.Millisecond
+	until := s.node.PauseStabilization(d, logicnode.KeepFailureDetection(req.GetKeepFailureDetection()))
+	resp := &clientv1.PauseStabilizationResponse{}
+	if !until.IsZero() {
+		resp.ResumeAtUnixMs = until.UnixMilli()
+	}
+	return resp, nil
+}
+
+// defaultShutdownTimeout bounds the handoff of Shutdown when the request
+// does not set a timeout.
+const defaultShutdownTimeout = 30 * time.Second
+
+// Shutdown makes the node leave the ring gracefully (see
+// logicnode.Node.Leave), handing its resources over to its successors, and
+// replies once the handoff has completed or the timeout of the request has
+// expired. The server then stops gracefully, whatever the outcome, so
+// that the process can exit (see Server.ShutdownRequested). It is an
+// admin RPC, rejected unless the server was created with WithAdmin.
+func (s *clientService) Shutdown(ctx context.Context, req *clientv1.ShutdownRequest) (*clientv1.ShutdownResponse, error) {
+	timeout := defaultShutdownTimeout
+	if secs := req.GetTimeoutSeconds(); secs > 0 {
+		timeout = time.Duration(secs) * time.Second
+	}
+	if s.shutdown != nil {
+		defer s.shutdown()
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- s.node.Leave() }()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	resp := &clientv1.ShutdownResponse{}
+	select {
+	case err := <-done:
+		if err != nil {
+			resp.Detail = fmt.Sprintf("leave failed: %v", err)
+		} else {
+			resp.HandoffCompleted = true
+		}
+	case <-timer.C:
+		resp.Detail = fmt.Sprintf("handoff not completed within %s", timeout)
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	return resp, nil
+}
+
+// Rebalance runs one storage maintenance pass on the node right away (see
+// logicnode.Node.Rebalance) and reports how many misplaced resources were
+// transferred to their owner and how many failed.
+//
+// Errors:
+//   - codes.FailedPrecondition if the node does not know its predecessor yet
+func (s *clientService) Rebalance(ctx context.Context, _ *emptypb.Empty) (*clientv1.RebalanceResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+	transferred, failed, err := s.node.Rebalance(ctx)
+	if err != nil {
+		if errors.Is(err, logicnode.ErrNoPredecessor) {
+			return nil, status.Error(codes.FailedPrecondition, "node has no predecessor yet")
+		}
+		return nil, status.Errorf(codes.Internal, "rebalance: %v", err)
+	}
+	return &clientv1.RebalanceResponse{Transferred: uint32(transferred), Failed: uint32(failed)}, nil
+}
+
+// keyID returns the ID of a client key: the pre-hashed ID idHex (a hex
+// digest truncated into the space, see domain.Space.FromBytes) if set, the
+// hash of key otherwise.
+//
+// Errors:
+//   - codes.InvalidArgument if idHex is not a valid hex digest
+func (s *clientService) keyID(key, idHex string) (domain.ID, error) {
+	if idHex == "" {
+		return s.node.Space().NewIdFromString(key), nil
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(idHex, "0x"), "0X"))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid key ID %q: %v", idHex, err)
+	}
+	id, err := s.node.Space().FromBytes(b)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid key ID %q: %v", idHex, err)
+	}
+	return id, nil
+}
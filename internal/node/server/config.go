@@ -3,24 +3,31 @@ package server
 import (
 	"fmt"
 	"net"
+	"strconv"
+	"strings"
 )
 
-// pickIP selects a suitable IPv4 address from the local interfaces
-// according to the given mode ("private" or "public").
+// pickIP selects a suitable address from the local interfaces according
+// to the given mode ("private" or "public") and IP version ("v4", "v6" or
+// "auto").
 //
 // Rules:
 //   - Only considers interfaces that are up and not loopback.
-//   - Only considers IPv4 addresses (IPv6 is skipped).
+//   - Skips link-local addresses, which are not reachable from other links.
+//   - With version "v4" only IPv4 addresses are considered, with "v6" only
+//     global unicast IPv6 addresses (ULA included); "auto" prefers IPv4 and
+//     falls back to IPv6 on IPv6-only hosts.
 //   - If mode == "private", returns the first private address found.
 //   - If mode == "public", returns the first non-private address found.
 //
 // Returns an error if no suitable address is found.
-func pickIP(mode string) (net.IP, error) {
+func pickIP(mode, version string) (net.IP, error) {
 	ifaces, err := net.Interfaces()
 	if err != nil {
 		return nil, err
 	}
 
+	var v4, v6 net.IP
 	for _, iface := range ifaces {
 		// Skip interfaces that are down or loopback
 		if (iface.Flags&net.FlagUp) == 0 || (iface.Flags&net.FlagLoopback) != 0 {
@@ -38,33 +45,50 @@ func pickIP(mode string) (net.IP, error) {
 			case *net.IPAddr:
 				ip = v.IP
 			}
-			if ip == nil || ip.IsLoopback() {
+			// excludes loopback, link-local, multicast and unspecified addresses
+			if ip == nil || !ip.IsGlobalUnicast() {
 				continue
 			}
-			ip = ip.To4()
-			if ip == nil {
+			if (mode == "private") != isPrivateIP(ip) {
 				continue
 			}
-
-			if mode == "private" && isPrivateIP(ip) {
-				return ip, nil
-			}
-			if mode == "public" && !isPrivateIP(ip) {
-				return ip, nil
+			if ip4 := ip.To4(); ip4 != nil {
+				if v4 == nil {
+					v4 = ip4
+				}
+			} else if v6 == nil {
+				v6 = ip
 			}
 		}
 	}
-	return nil, fmt.Errorf("no suitable %s interface found", mode)
+
+	switch {
+	case version != "v6" && v4 != nil:
+		return v4, nil
+	case version != "v4" && v6 != nil:
+		return v6, nil
+	}
+	if version == "auto" {
+		return nil, fmt.Errorf("no suitable %s interface found", mode)
+	}
+	return nil, fmt.Errorf("no suitable %s %s interface found", mode, ipFamily(version))
+}
+
+// privateBlocks are the address ranges not routable on the Internet: the
+// RFC1918 IPv4 ranges, the IPv6 unique local addresses (RFC 4193) and the
+// link-local ranges of both families.
+var privateBlocks = []string{
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"169.254.0.0/16",
+	"fc00::/7",
+	"fe80::/10",
 }
 
-// isPrivateIP checks whether the given IPv4 address
-// belongs to one of the RFC1918 private address ranges.
+// isPrivateIP checks whether the given address belongs to one of the
+// private address ranges.
 func isPrivateIP(ip net.IP) bool {
-	privateBlocks := []string{
-		"10.0.0.0/8",
-		"172.16.0.0/12",
-		"192.168.0.0/16",
-	}
 	for _, block := range privateBlocks {
 		_, cidr, _ := net.ParseCIDR(block)
 		if cidr.Contains(ip) {
@@ -74,35 +98,41 @@ func isPrivateIP(ip net.IP) bool {
 	return false
 }
 
+// ipFamily returns the name of the address family of an IP version.
+func ipFamily(version string) string {
+	if version == "v6" {
+		return "IPv6"
+	}
+	return "IPv4"
+}
+
 // Listen starts a TCP listener on the given bind address and port,
 // and returns both the listener and the advertised address (host:port)
 // to be shared with peers.
 //
-// If 'host' is empty, it is automatically selected based on 'mode':
-//   - "private": picks a private/local IPv4 address
-//   - "public":  picks a public IPv4 address
+// If 'host' is empty, it is automatically selected based on 'mode' and
+// 'version' (see pickIP):
+//   - "private": picks a private/local address
+//   - "public":  picks a public address
 //
-// The function validates that the advertised host matches the mode.
-// If 'port' is 0, a free port is chosen automatically.
-func Listen(mode, bind, host string, port int) (net.Listener, string, error) {
-	bindAddr := fmt.Sprintf("%s:%d", bind, port)
-
-	ln, err := net.Listen("tcp", bindAddr)
-	if err != nil {
-		return nil, "", err
-	}
-
-	actualPort := ln.Addr().(*net.TCPAddr).Port
-
+// The function validates that the advertised host matches the mode and the
+// IP version. IPv6 hosts are advertised in brackets ("[2001:db8::1]:4000")
+// and may be given with or without them. If 'port' is 0, a free port is
+// chosen automatically.
+func Listen(mode, version, bind, host string, port int) (net.Listener, string, error) {
 	if host == "" {
-		ip, err := pickIP(mode)
+		ip, err := pickIP(mode, version)
 		if err != nil {
 			return nil, "", err
 		}
 		host = ip.String()
 	} else {
+		host = strings.Trim(host, "[]")
 		ip := net.ParseIP(host)
 		if ip != nil {
+			if isV4 := ip.To4() != nil; (version == "v4" && !isV4) || (version == "v6" && isV4) {
+				return nil, "", fmt.Errorf("host %s is not an %s address but ipVersion=%s", host, ipFamily(version), version)
+			}
 			if mode == "private" && !isPrivateIP(ip) {
 				return nil, "", fmt.Errorf("host %s is not private but mode=private", host)
 			}
@@ -112,6 +142,13 @@ func Listen(mode, bind, host string, port int) (net.Listener, string, error) {
 		}
 	}
 
-	advertised := fmt.Sprintf("%s:%d", host, actualPort)
+	bindAddr := net.JoinHostPort(strings.Trim(bind, "[]"), strconv.Itoa(port))
+	ln, err := net.Listen("tcp", bindAddr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	actualPort := ln.Addr().(*net.TCPAddr).Port
+	advertised := net.JoinHostPort(host, strconv.Itoa(actualPort))
 	return ln, advertised, nil
 }
@@ -5,11 +5,13 @@ import (
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/node/ctxutil"
 	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/storage"
 	"KoordeDHT/internal/node/telemetry"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -50,6 +52,7 @@ func NewDHTService(n *logicnode.Node) dhtv1.DHTServer {
 // Errors:
 //   - codes.InvalidArgument: request is malformed or missing fields
 //   - codes.NotFound: no successor could be determined
+//   - codes.Unavailable: the routing table is not initialized yet
 //   - codes.Internal: underlying node logic failed
 func (s *dhtService) FindSuccessor(ctx context.Context, req *dhtv1.FindSuccessorRequest) (*dhtv1.FindSuccessorResponse, error) {
 	// Validate request
@@ -118,7 +121,12 @@ func (s *dhtService) FindSuccessor(ctx context.Context, req *dhtv1.FindSuccessor
 	}
 
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "FindSuccessor failed: %v", err)
+		code, ok := lookupCode(err)
+		if !ok {
+			code = codes.Internal
+		}
+		return nil, failureStatus(code, fmt.Sprintf("FindSuccessor failed: %v", err),
+			s.node.Failure(domain.StageRouting, err))
 	}
 
 	if succ == nil {
@@ -128,6 +136,115 @@ func (s *dhtService) FindSuccessor(ctx context.Context, req *dhtv1.FindSuccessor
 	return &dhtv1.FindSuccessorResponse{Node: succ.ToProtoDHT()}, nil
 }
 
+// FindSuccessorNextHop handles a step of an iterative lookup.
+//
+// Unlike FindSuccessor, the request is never forwarded: the node returns
+// its routing decision, either the successor of the target (the lookup
+// ends) or the candidates for the next hop together with the updated
+// imaginary node and shifted target. The modes and the validation of the
+// request are those of FindSuccessor.
+func (s *dhtService) FindSuccessorNextHop(ctx context.Context, req *dhtv1.FindSuccessorRequest) (*dhtv1.NextHopResponse, error) {
+	// Validate request
+	if req == nil || len(req.TargetId) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing target_id")
+	}
+
+	// Check for canceled/expired context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// validate target ID
+	if err := s.node.IsValidID(req.TargetId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid target_id")
+	}
+	target := domain.ID(req.TargetId)
+
+	var (
+		hop *domain.NextHop
+		err error
+	)
+	switch mode := req.Mode.(type) {
+	case *dhtv1.FindSuccessorRequest_Initial:
+		hop, err = s.node.NextHopInit(ctx, target)
+	case *dhtv1.FindSuccessorRequest_Step:
+		if err := s.node.IsValidID(mode.Step.CurrentI); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid current_i")
+		}
+		if err := s.node.IsValidID(mode.Step.KShift); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid kshift")
+		}
+		hop, err = s.node.NextHopStep(ctx, target, domain.ID(mode.Step.CurrentI), domain.ID(mode.Step.KShift))
+	default:
+		return nil, status.Error(codes.InvalidArgument, "invalid mode")
+	}
+
+	if err != nil {
+		code, ok := lookupCode(err)
+		if !ok {
+			code = codes.Internal
+		}
+		return nil, failureStatus(code, fmt.Sprintf("FindSuccessorNextHop failed: %v", err),
+			s.node.Failure(domain.StageRouting, err))
+	}
+
+	return hop.ToProtoDHT(), nil
+}
+
+// FindPredecessor handles a predecessor lookup: it returns the node n with
+// target_id in (n, successor(n)], routing the request like FindSuccessor.
+// The modes and the validation of the request are those of FindSuccessor.
+func (s *dhtService) FindPredecessor(ctx context.Context, req *dhtv1.FindSuccessorRequest) (*dhtv1.FindSuccessorResponse, error) {
+	// Validate request
+	if req == nil || len(req.TargetId) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing target_id")
+	}
+
+	// Check for canceled/expired context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// validate target ID
+	if err := s.node.IsValidID(req.TargetId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid target_id")
+	}
+	target := domain.ID(req.TargetId)
+
+	var (
+		pred *domain.Node
+		err  error
+	)
+	switch mode := req.Mode.(type) {
+	case *dhtv1.FindSuccessorRequest_Initial:
+		pred, err = s.node.FindPredecessor(ctx, target)
+	case *dhtv1.FindSuccessorRequest_Step:
+		if err := s.node.IsValidID(mode.Step.CurrentI); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid current_i")
+		}
+		if err := s.node.IsValidID(mode.Step.KShift); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid kshift")
+		}
+		pred, err = s.node.FindPredecessorStep(ctx, target, domain.ID(mode.Step.CurrentI), domain.ID(mode.Step.KShift))
+	default:
+		return nil, status.Error(codes.InvalidArgument, "invalid mode")
+	}
+
+	if err != nil {
+		code, ok := lookupCode(err)
+		if !ok {
+			code = codes.Internal
+		}
+		return nil, failureStatus(code, fmt.Sprintf("FindPredecessor failed: %v", err),
+			s.node.Failure(domain.StageRouting, err))
+	}
+	if pred == nil {
+		return nil, status.Error(codes.NotFound, "predecessor not found")
+	}
+
+	return &dhtv1.FindSuccessorResponse{Node: pred.ToProtoDHT()}, nil
+}
+
 // GetPredecessor handles a request to retrieve the current predecessor of this node.
 //
 // Behavior:
@@ -235,14 +352,26 @@ func (s *dhtService) Ping(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty
 
 // Store handles a client-streaming request to store multiple resources.
 // The client sends a stream of StoreRequest messages, and the server replies
-// with an Empty once all resources have been processed.
+// once all resources have been processed with the version stored for each
+// of them (the one this node assigned to new writes). Values split across
+// several frames (see dht.v1.StoreRequest) are reassembled before the
+// resource is stored.
 //
 // Errors:
-//   - codes.InvalidArgument if a request is malformed
+//   - codes.InvalidArgument if a request is malformed, a value chunk is out
+//     of sequence or the stream ends in the middle of a value
+//   - codes.ResourceExhausted if the storage quota or maximum number of
+//     keys is reached
 //   - codes.Internal if receiving from the stream fails or storing fails
 func (s *dhtService) Store(stream dhtv1.DHT_StoreServer) error {
 	ctx := stream.Context()
 
+	// resource whose value is being reassembled from its chunks
+	var (
+		pending  *dhtv1.StoreRequest
+		value    strings.Builder
+		versions []uint64 // version stored for each resource received
+	)
 	for {
 		// Validate context
 		if cerr := ctxutil.CheckContext(ctx); cerr != nil {
@@ -252,27 +381,60 @@ func (s *dhtService) Store(stream dhtv1.DHT_StoreServer) error {
 		// Receive next request from stream
 		req, err := stream.Recv()
 		if err == io.EOF {
+			if pending != nil {
+				return status.Error(codes.InvalidArgument, "stream closed before the last chunk of the resource value")
+			}
 			// client has finished sending requests
-			return stream.SendAndClose(&emptypb.Empty{})
+			return stream.SendAndClose(&dhtv1.StoreResponse{Versions: versions})
 		}
 		if err != nil {
 			return status.Errorf(codes.Internal, "failed to receive request: %v", err)
 		}
 
+		// Reassemble the value of chunked resources
+		if req.GetSeq() == 0 {
+			if pending != nil {
+				return status.Error(codes.InvalidArgument, "new resource before the last chunk of the previous value")
+			}
+			pending = req
+			value.Reset()
+		} else if pending == nil || req.GetSeq() != pending.GetSeq()+1 {
+			return status.Errorf(codes.InvalidArgument, "unexpected value chunk %d", req.GetSeq())
+		} else {
+			pending.Seq = req.GetSeq()
+		}
+		value.WriteString(req.GetResource().GetValue())
+		if req.GetMore() {
+			continue
+		}
+		req, pending = pending, nil
+
 		// Extract and validate resource
 		resProto := req.GetResource()
 		if resProto == nil {
 			return status.Error(codes.InvalidArgument, "missing resource")
 		}
+		resProto.Value = value.String()
 		res, convErr := domain.ResourceFromProtoDHT(s.node.Space(), resProto)
 		if convErr != nil {
 			return status.Errorf(codes.InvalidArgument, "invalid resource: %v", convErr)
 		}
 
-		// Store locally
-		if serr := s.node.StoreLocal(ctx, *res); serr != nil {
-			return status.Errorf(codes.Internal, "failed to store resource: %v", serr)
+		// Store locally (replica copies skip the ownership check and are
+		// never conditional)
+		var (
+			version uint64
+			serr    error
+		)
+		if req.GetReplica() {
+			version, serr = res.Version, s.node.StoreReplica(ctx, *res)
+		} else {
+			version, serr = s.node.StoreLocalIf(ctx, *res, domain.ConditionFromProtoDHT(req.GetCondition()))
+		}
+		if serr != nil {
+			return status.Errorf(storeCode(serr), "failed to store resource: %v", serr)
 		}
+		versions = append(versions, version)
 	}
 }
 
@@ -281,6 +443,7 @@ func (s *dhtService) Store(stream dhtv1.DHT_StoreServer) error {
 // Errors:
 //   - codes.InvalidArgument if the request is malformed or the key is invalid
 //   - codes.NotFound if the resource does not exist locally
+//   - codes.DataLoss if the stored resource fails its checksum
 //   - codes.Internal if the storage backend fails
 func (s *dhtService) Retrieve(ctx context.Context, req *dhtv1.RetrieveRequest) (*dhtv1.RetrieveResponse, error) {
 	// Validate context
@@ -303,6 +466,9 @@ func (s *dhtService) Retrieve(ctx context.Context, req *dhtv1.RetrieveRequest) (
 		if errors.Is(err, domain.ErrResourceNotFound) {
 			return nil, status.Error(codes.NotFound, "key not found")
 		}
+		if errors.Is(err, domain.ErrResourceCorrupted) {
+			return nil, status.Error(codes.DataLoss, "resource corrupted")
+		}
 		return nil, status.Errorf(codes.Internal, "retrieve failed: %v", err)
 	}
 
@@ -344,6 +510,153 @@ func (s *dhtService) Remove(ctx context.Context, req *dhtv1.RemoveRequest) (*emp
 	return &emptypb.Empty{}, nil
 }
 
+// RemoveBatch deletes a batch of resources from the local storage. Keys
+// that are not stored are reported as not removed rather than failing the
+// whole batch.
+//
+// Errors:
+//   - codes.InvalidArgument if a key is missing or invalid (nothing is removed)
+//   - codes.Internal if the local storage fails
+func (s *dhtService) RemoveBatch(ctx context.Context, req *dhtv1.RemoveBatchRequest) (*dhtv1.RemoveBatchResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate every key before removing any
+	for _, k := range req.GetKeys() {
+		if err := s.node.Space().IsValidID(k); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid key")
+		}
+	}
+
+	resp := &dhtv1.RemoveBatchResponse{Removed: make([]bool, len(req.GetKeys()))}
+	for i, k := range req.GetKeys() {
+		err := s.node.RemoveLocal(domain.ID(k))
+		switch {
+		case err == nil:
+			resp.Removed[i] = true
+		case errors.Is(err, domain.ErrResourceNotFound):
+		default:
+			return nil, status.Errorf(codes.Internal, "remove failed: %v", err)
+		}
+	}
+	return resp, nil
+}
+
+// RemoveRange removes every resource stored locally whose key lies in
+// (from, to], owned or replica copy, and reports how many were removed.
+//
+// Errors:
+//   - codes.InvalidArgument if a bound is missing or invalid.
+func (s *dhtService) RemoveRange(ctx context.Context, req *dhtv1.RemoveRangeRequest) (*dhtv1.RemoveRangeResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if req == nil || len(req.From) == 0 || len(req.To) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing range bounds")
+	}
+	if err := s.node.Space().IsValidID(req.From); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid range lower bound")
+	}
+	if err := s.node.Space().IsValidID(req.To); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid range upper bound")
+	}
+
+	removed := s.node.RemoveRangeLocal(domain.ID(req.From), domain.ID(req.To))
+	return &dhtv1.RemoveRangeResponse{Removed: uint64(removed)}, nil
+}
+
+// RetrieveRange streams a copy of every resource stored locally whose key
+// lies in (from, to]. Resources are not removed from the local storage.
+//
+// Errors:
+//   - codes.InvalidArgument if the request is malformed or a bound is invalid
+//   - codes.Internal if a resource cannot be sent
+func (s *dhtService) RetrieveRange(req *dhtv1.RetrieveRangeRequest, stream dhtv1.DHT_RetrieveRangeServer) error {
+	// Validate context
+	if err := ctxutil.CheckContext(stream.Context()); err != nil {
+		return err
+	}
+
+	// Validate request
+	if req == nil || len(req.From) == 0 || len(req.To) == 0 {
+		return status.Error(codes.InvalidArgument, "missing range bounds")
+	}
+	if err := s.node.Space().IsValidID(req.From); err != nil {
+		return status.Error(codes.InvalidArgument, "invalid range lower bound")
+	}
+	if err := s.node.Space().IsValidID(req.To); err != nil {
+		return status.Error(codes.InvalidArgument, "invalid range upper bound")
+	}
+
+	for _, r := range s.node.RetrieveRangeLocal(domain.ID(req.From), domain.ID(req.To)) {
+		// Check context for cancellation at each step
+		if err := ctxutil.CheckContext(stream.Context()); err != nil {
+			return err
+		}
+		if err := stream.Send(&dhtv1.RetrieveResponse{Resource: r.ToProtoDHT()}); err != nil {
+			return status.Errorf(codes.Internal, "failed to send resource: %v", err)
+		}
+	}
+	return nil
+}
+
+// SyncDigest returns part of the Merkle summary of the resources stored
+// locally with key in (from, to]: the hashes of the requested tree nodes
+// or, if entries is set, the entries of the requested leaves.
+//
+// Errors:
+//   - codes.InvalidArgument if the range, the depth, the level or an index is invalid
+func (s *dhtService) SyncDigest(ctx context.Context, req *dhtv1.SyncDigestRequest) (*dhtv1.SyncDigestResponse, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if req == nil || len(req.From) == 0 || len(req.To) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing range bounds")
+	}
+	if err := s.node.Space().IsValidID(req.From); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid range lower bound")
+	}
+	if err := s.node.Space().IsValidID(req.To); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid range upper bound")
+	}
+	if req.Depth > storage.MaxMerkleDepth {
+		return nil, status.Errorf(codes.InvalidArgument, "depth must be <= %d", storage.MaxMerkleDepth)
+	}
+	level := req.Level
+	if req.Entries {
+		level = req.Depth
+	}
+	if level > req.Depth {
+		return nil, status.Error(codes.InvalidArgument, "level must be <= depth")
+	}
+	for _, i := range req.Indices {
+		if i >= 1<<level {
+			return nil, status.Errorf(codes.InvalidArgument, "index %d out of range at level %d", i, level)
+		}
+	}
+
+	tree := s.node.MerkleTree(domain.ID(req.From), domain.ID(req.To), int(req.Depth))
+	resp := &dhtv1.SyncDigestResponse{}
+	for _, i := range req.Indices {
+		if !req.Entries {
+			resp.Hashes = append(resp.Hashes, tree.Hash(int(level), int(i)))
+			continue
+		}
+		for _, e := range tree.Leaf(int(i)) {
+			resp.Entries = append(resp.Entries, &dhtv1.SyncEntry{Leaf: i, Key: e.Key, Digest: e.Digest})
+		}
+	}
+	return resp, nil
+}
+
 // Leave handles a request from a successor node indicating that it is leaving the network.
 //
 // Behavior:
@@ -381,3 +694,50 @@ func (s *dhtService) Leave(
 
 	return &emptypb.Empty{}, nil
 }
+
+// PredecessorLeaving handles a request from the successor of this node
+// indicating that it is leaving the network: its successors replace it
+// at the head of the successor list.
+//
+// Behavior:
+//   - If the context is canceled or its deadline has expired, the request is aborted.
+//   - If the leaving node or one of its successors is invalid, an InvalidArgument status is returned.
+//   - Otherwise, the node logic is invoked to splice the leaving node out of the successor list
+//     (a leave of a node that is not the successor is ignored).
+//
+// Errors:
+//   - codes.InvalidArgument if the request is malformed
+//   - codes.Internal if internal handling fails
+func (s *dhtService) PredecessorLeaving(
+	ctx context.Context,
+	req *dhtv1.PredecessorLeavingRequest,
+) (*emptypb.Empty, error) {
+	// Validate context
+	if err := ctxutil.CheckContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if req == nil || req.GetLeaving() == nil || len(req.GetLeaving().Id) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid leaving node")
+	}
+	leaving, err := domain.NodeFromProtoDHT(s.node.Space(), req.GetLeaving())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid leaving node: %v", err)
+	}
+	succs := make([]*domain.Node, 0, len(req.GetSuccessors()))
+	for _, pn := range req.GetSuccessors() {
+		nd, err := domain.NodeFromProtoDHT(s.node.Space(), pn)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid successor: %v", err)
+		}
+		succs = append(succs, nd)
+	}
+
+	// Handle successor departure
+	if err = s.node.HandleSuccessorLeave(leaving, succs); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to handle successor leave: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
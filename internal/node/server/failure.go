@@ -0,0 +1,84 @@
+package server
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/logicnode"
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failureStatus builds the gRPC status returned for a failed operation.
+// If err carries a domain.Failure, it is attached as ErrorInfo detail so
+// that callers can tell where the operation failed and whether to retry.
+func failureStatus(code codes.Code, msg string, err error) error {
+	st := status.New(code, msg)
+	var f *domain.Failure
+	if errors.As(err, &f) {
+		if detailed, dErr := st.WithDetails(f.ErrorInfo()); dErr == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
+
+// storeCode returns the gRPC code of a failed store: ResourceExhausted if
+// the owner's storage is full (locally or on a remote owner), so
+// that clients can back off and retry, FailedPrecondition if the condition
+// of a conditional write does not hold, DeadlineExceeded if no time was
+// left to reach the owner (see deadlineExceeded), Unavailable if the owner
+// rejected the write until it learns its predecessor (see
+// logicnode.WithNoPredecessorPolicy), Aborted if the node is not
+// responsible for the key (the write must be routed again with a new
+// lookup), and Internal otherwise.
+func storeCode(err error) codes.Code {
+	if errors.Is(err, domain.ErrStorageFull) || status.Code(err) == codes.ResourceExhausted {
+		return codes.ResourceExhausted
+	}
+	if deadlineExceeded(err) {
+		return codes.DeadlineExceeded
+	}
+	if errors.Is(err, logicnode.ErrNoPredecessor) || status.Code(err) == codes.Unavailable {
+		return codes.Unavailable
+	}
+	if errors.Is(err, domain.ErrPreconditionFailed) || status.Code(err) == codes.FailedPrecondition {
+		return codes.FailedPrecondition
+	}
+	if errors.Is(err, domain.ErrNotResponsible) || status.Code(err) == codes.Aborted {
+		return codes.Aborted
+	}
+	return codes.Internal
+}
+
+// deadlineExceeded reports whether an operation failed because it ran out
+// of time after its lookup, e.g. when forwarding to the owner.
+func deadlineExceeded(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded
+}
+
+// lookupCode returns the gRPC code of an operation that failed to locate
+// the node responsible for its key, and false if err is not such a failure:
+// NotFound if the lookup ended without a responsible node, Internal if a
+// node on its path aborted it for making too many hops (see
+// logicnode.ErrMaxHops), DeadlineExceeded if it ran out of time, and
+// Unavailable otherwise (the routing table is
+// not initialized or a hop failed), since the ring usually repairs itself
+// and the operation may succeed if retried. Operations rejected because the
+// node is shutting down are Unavailable as well, to be retried elsewhere.
+func lookupCode(err error) (codes.Code, bool) {
+	switch {
+	case errors.Is(err, logicnode.ErrStopping):
+		return codes.Unavailable, true
+	case errors.Is(err, logicnode.ErrNoSuccessor):
+		return codes.NotFound, true
+	case logicnode.ExceededHops(err):
+		return codes.Internal, true
+	case !errors.Is(err, logicnode.ErrLookupFailed) && !errors.Is(err, logicnode.ErrRoutingNotInitialized):
+		return codes.Unknown, false
+	case errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded:
+		return codes.DeadlineExceeded, true
+	}
+	return codes.Unavailable, true
+}
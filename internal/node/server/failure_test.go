@@ -0,0 +1,91 @@
+package server_test
+
+import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRoutingFailureDetail(t *testing.T) {
+	// Quando broken è attivo ogni FindSuccessor tra nodi fallisce
+	var broken atomic.Bool
+	r := testring.New(t, 4, testring.WithServerOptions(server.WithUnaryInterceptors(
+		func(ctx context.Context, req any, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+			if broken.Load() && info.FullMethod == "/dht.v1.DHT/FindSuccessor" {
+				return nil, status.Error(codes.Unavailable, "injected routing failure")
+			}
+			return h(ctx, req)
+		})))
+	r.StopStabilizers()
+	origin := r.Members[0]
+
+	// Chiave la cui lookup da origin richiede almeno un inoltro remoto
+	key := ""
+	for i := 0; key == ""; i++ {
+		raw := fmt.Sprintf("key-%d", i)
+		owner := r.Owner(r.Space.NewIdFromString(raw))
+		if owner != r.Members[0] && owner != r.Members[1] {
+			key = raw
+		}
+	}
+
+	api, conn, err := client.Connect(origin.Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	broken.Store(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = api.Get(ctx, &clientv1.GetRequest{Key: key})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("Get: got %v, want Unavailable", err)
+	}
+
+	// Il dettaglio è un google.rpc.ErrorInfo decodificabile
+	var info *errdetails.ErrorInfo
+	for _, d := range status.Convert(err).Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			info = ei
+		}
+	}
+	if info == nil {
+		t.Fatalf("Get: no ErrorInfo detail attached to %v", err)
+	}
+	if info.GetDomain() != domain.FailureDomain || info.GetReason() != "ROUTING_FAILED" {
+		t.Errorf("ErrorInfo domain/reason = %s/%s, want %s/ROUTING_FAILED", info.GetDomain(), info.GetReason(), domain.FailureDomain)
+	}
+
+	f, ok := domain.FailureFromError(err)
+	if !ok {
+		t.Fatalf("FailureFromError(%v): no failure decoded", err)
+	}
+	want := domain.Failure{Stage: domain.StageRouting, LastHop: origin.Addr, Retryable: true}
+	if f.Stage != want.Stage || f.LastHop != want.LastHop || f.Retryable != want.Retryable {
+		t.Errorf("failure = {%s %s %v}, want {%s %s %v}", f.Stage, f.LastHop, f.Retryable, want.Stage, want.LastHop, want.Retryable)
+	}
+
+	// normalizeError conserva sia l'errore comune sia il dettaglio, e
+	// distingue il fallimento della lookup da quello dello storage
+	_, _, err = client.Get(ctx, api, key)
+	if !errors.Is(err, client.ErrUnavailable) || !errors.Is(err, client.ErrLookupFailed) {
+		t.Errorf("client.Get: got %v, want ErrUnavailable and ErrLookupFailed", err)
+	}
+	if !errors.As(err, &f) || f.Stage != domain.StageRouting {
+		t.Errorf("client.Get: %v does not carry the routing failure", err)
+	}
+}
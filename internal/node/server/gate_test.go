@@ -0,0 +1,131 @@
+package server_test
+
+import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	nodeclient "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/storage"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestStartupGate(t *testing.T) {
+	// Nodo singolo avviato come in main: server in ascolto prima della
+	// creazione della DHT e della registrazione
+	sp, err := domain.NewSpace(16, 2, 4)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	self := &domain.Node{ID: sp.NewIdFromString(addr), Addr: addr}
+	cp := nodeclient.New(self.ID, addr, time.Second)
+	n := logicnode.New(routingtable.New(self, sp), cp, storage.NewMemoryStorage(&logger.NopLogger{}))
+	srv, err := server.New(lis, n, nil, server.WithStartupGate())
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+	go func() { _ = srv.Start() }()
+	t.Cleanup(func() {
+		srv.Stop()
+		_ = cp.Close()
+	})
+
+	api, conn, err := client.Connect(addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	dhtConn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer dhtConn.Close()
+	dht := dhtv1.NewDHTClient(dhtConn)
+
+	// RPC unarie e in streaming, del servizio client e di quello DHT
+	calls := map[string]func(ctx context.Context) error{
+		"client Put": func(ctx context.Context) error {
+			_, err := client.Put(ctx, api, "key", "value")
+			return err
+		},
+		"client GetStore": func(ctx context.Context) error {
+			// client.GetStore ignora gli errori di Recv: si legge lo stream direttamente
+			stream, err := api.GetStore(ctx, &emptypb.Empty{})
+			if err != nil {
+				return err
+			}
+			for {
+				if _, err := stream.Recv(); err != nil {
+					if errors.Is(err, io.EOF) {
+						return nil
+					}
+					return err
+				}
+			}
+		},
+		"dht Ping": func(ctx context.Context) error {
+			return nodeclient.Ping(ctx, dht)
+		},
+		"dht Store": func(ctx context.Context) error {
+			_, err := nodeclient.StoreRemote(ctx, dht, []domain.Resource{
+				{Key: sp.NewIdFromString("other"), RawKey: "other", Value: "v"},
+			})
+			return err
+		},
+	}
+	check := func(ready bool) {
+		t.Helper()
+		for name, call := range calls {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			err := call(ctx)
+			cancel()
+			if ready && err != nil {
+				t.Errorf("%s after SetReady: %v", name, err)
+			}
+			if !ready && !rejected(err) {
+				t.Errorf("%s before SetReady: got %v, want Unavailable", name, err)
+			}
+		}
+	}
+
+	n.CreateNewDHT()
+	if srv.Ready() {
+		t.Fatal("gated server ready before SetReady")
+	}
+	check(false)
+
+	srv.SetReady() // registrazione completata
+	if !srv.Ready() {
+		t.Fatal("server not ready after SetReady")
+	}
+	check(true)
+}
+
+// rejected riconosce il rifiuto del gate, sia come status gRPC sia come
+// errore normalizzato dal client esterno.
+func rejected(err error) bool {
+	if errors.Is(err, client.ErrUnavailable) {
+		return true
+	}
+	s, ok := status.FromError(err)
+	return ok && s.Code() == codes.Unavailable
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"strings"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthServices are the services whose status the health service
+// reports: the server as a whole ("") and each registered service.
+var healthServices = []string{"", clientv1.ClientAPI_ServiceDesc.ServiceName, dhtv1.DHT_ServiceDesc.ServiceName}
+
+// isHealthMethod reports whether method is an RPC of the health service,
+// which answers probes even while the startup gate is closed and is never
+// counted against the concurrency limits.
+func isHealthMethod(method string) bool {
+	return strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
+
+// SetServing sets whether the node is part of the DHT (it has joined or
+// created it), as reported by the standard grpc.health.v1.Health service:
+// SERVING once serving is true and the startup gate, if any, is open (see
+// SetReady), NOT_SERVING otherwise. The status starts NOT_SERVING and
+// stays so for good once the server is stopping (Stop, GracefulStop or a
+// Shutdown RPC), so that orchestrators stop routing traffic to it.
+func (s *Server) SetServing(serving bool) {
+	s.serving.Store(serving)
+	s.updateHealth()
+}
+
+// updateHealth publishes the health status of the server on every
+// service of healthServices.
+func (s *Server) updateHealth() {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if s.serving.Load() && s.Ready() {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	for _, svc := range healthServices {
+		s.health.SetServingStatus(svc, status)
+	}
+}
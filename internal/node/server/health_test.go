@@ -0,0 +1,113 @@
+package server_test
+
+import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	nodeclient "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/storage"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthService(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []server.Option
+		gated bool
+	}{
+		{name: "ungated"},
+		// Il servizio di health risponde anche a gate chiuso
+		{name: "gated", opts: []server.Option{server.WithStartupGate(),
+			server.WithConcurrencyLimits(server.Limits{MaxRequests: 1})}, gated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, err := domain.NewSpace(16, 2, 4)
+			if err != nil {
+				t.Fatalf("NewSpace: %v", err)
+			}
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("listen: %v", err)
+			}
+			addr := lis.Addr().String()
+			self := &domain.Node{ID: sp.NewIdFromString(addr), Addr: addr}
+			cp := nodeclient.New(self.ID, addr, time.Second)
+			n := logicnode.New(routingtable.New(self, sp), cp, storage.NewMemoryStorage(&logger.NopLogger{}))
+			srv, err := server.New(lis, n, nil, tt.opts...)
+			if err != nil {
+				t.Fatalf("server.New: %v", err)
+			}
+			go func() { _ = srv.Start() }()
+			t.Cleanup(func() {
+				srv.Stop()
+				_ = cp.Close()
+			})
+
+			conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer conn.Close()
+			hc := healthpb.NewHealthClient(conn)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			check := func(step string, want healthpb.HealthCheckResponse_ServingStatus) {
+				t.Helper()
+				for _, svc := range []string{"", clientv1.ClientAPI_ServiceDesc.ServiceName} {
+					resp, err := hc.Check(ctx, &healthpb.HealthCheckRequest{Service: svc})
+					if err != nil {
+						t.Fatalf("%s: Check(%q): %v", step, svc, err)
+					}
+					if resp.GetStatus() != want {
+						t.Errorf("%s: Check(%q) = %v, want %v", step, svc, resp.GetStatus(), want)
+					}
+				}
+			}
+
+			check("before the DHT is created", healthpb.HealthCheckResponse_NOT_SERVING)
+			n.CreateNewDHT()
+			srv.SetServing(true)
+			if tt.gated {
+				check("gate closed", healthpb.HealthCheckResponse_NOT_SERVING)
+				srv.SetReady()
+			}
+			check("in the DHT", healthpb.HealthCheckResponse_SERVING)
+
+			// GracefulStop notifica NOT_SERVING a chi osserva lo stato
+			wctx, wcancel := context.WithCancel(ctx)
+			defer wcancel()
+			watch, err := hc.Watch(wctx, &healthpb.HealthCheckRequest{})
+			if err != nil {
+				t.Fatalf("Watch: %v", err)
+			}
+			if resp, err := watch.Recv(); err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+				t.Fatalf("Watch: got %v, %v, want SERVING", resp.GetStatus(), err)
+			}
+			stopped := make(chan struct{})
+			go func() {
+				srv.GracefulStop()
+				close(stopped)
+			}()
+			if resp, err := watch.Recv(); err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+				t.Fatalf("Watch during GracefulStop: got %v, %v, want NOT_SERVING", resp.GetStatus(), err)
+			}
+			wcancel()
+			select {
+			case <-stopped:
+			case <-time.After(5 * time.Second):
+				t.Fatal("GracefulStop did not return after the watch was closed")
+			}
+		})
+	}
+}
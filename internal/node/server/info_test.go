@@ -0,0 +1,85 @@
+package server_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestInfo(t *testing.T) {
+	r := testring.New(t, 3)
+	r.WaitStable()
+	r.StopStabilizers() // stato fermo: la risposta si confronta con quello del nodo
+	m := r.Members[0]
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 5 chiavi di cui il nodo è responsabile
+	stored := 0
+	for i := 0; stored < 5 && i < 1<<20; i++ {
+		raw := fmt.Sprintf("info-%d", i)
+		id := r.Space.NewIdFromString(raw)
+		if r.Owner(id) != m {
+			continue
+		}
+		if err := m.Node.StoreLocal(ctx, domain.Resource{Key: id, RawKey: raw, Value: raw}); err != nil {
+			t.Fatalf("StoreLocal %s: %v", raw, err)
+		}
+		stored++
+	}
+
+	api, conn, err := client.Connect(m.Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	info, _, err := client.Info(ctx, api)
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+
+	self, pred := m.Node.Self(), m.Node.Predecessor()
+	if info.GetSelf().GetAddr() != self.Addr || info.GetSelf().GetId() != self.ID.ToHexString(true) {
+		t.Errorf("self = %v, want %s (%s)", info.GetSelf(), self.ID.ToHexString(true), self.Addr)
+	}
+	if pred == nil || info.GetPredecessor().GetAddr() != pred.Addr {
+		t.Errorf("predecessor = %v, want %v", info.GetPredecessor(), pred)
+	}
+	succs, filled := 0, 0
+	for _, s := range m.Node.SuccessorList() {
+		if s != nil {
+			succs++
+		}
+	}
+	for _, d := range m.Node.DeBruijnList() {
+		if d != nil {
+			filled++
+		}
+	}
+
+	tests := []struct {
+		name      string
+		got, want uint64
+	}{
+		{name: "successor count", got: uint64(info.GetSuccessorCount()), want: uint64(succs)},
+		{name: "de Bruijn filled", got: uint64(info.GetDeBruijnFilled()), want: uint64(filled)},
+		{name: "de Bruijn degree", got: uint64(info.GetDeBruijnDegree()), want: uint64(r.Space.GraphGrade)},
+		{name: "stored keys", got: info.GetStoredKeys(), want: uint64(len(m.Node.GetAllResourceStored()))},
+		{name: "pooled connections", got: uint64(info.GetPooledConnections()), want: uint64(m.Node.PooledConnections())},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+	if info.GetStoredKeys() < uint64(stored) {
+		t.Errorf("stored keys = %d, want >= %d", info.GetStoredKeys(), stored)
+	}
+	if info.GetUptimeMs() <= 0 || time.Duration(info.GetUptimeMs())*time.Millisecond > m.Node.Uptime() {
+		t.Errorf("uptime = %dms, node uptime %v", info.GetUptimeMs(), m.Node.Uptime())
+	}
+}
@@ -0,0 +1,156 @@
+package server_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	nodeclient "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+)
+
+// counter conta le RPC per metodo in modo concorrente.
+type counter struct {
+	mu sync.Mutex
+	m  map[string]int
+}
+
+func newCounter() *counter { return &counter{m: make(map[string]int)} }
+
+func (c *counter) inc(method string) {
+	c.mu.Lock()
+	c.m[method]++
+	c.mu.Unlock()
+}
+
+func (c *counter) snapshot() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.m)
+}
+
+// rpcStats è uno stats.Handler di riferimento: vede ogni RPC ricevuta
+// dal server indipendentemente dagli interceptor.
+type rpcStats struct{ c *counter }
+
+type methodKey struct{}
+
+func (h rpcStats) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, methodKey{}, info.FullMethodName)
+}
+
+func (h rpcStats) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if _, ok := s.(*stats.Begin); ok {
+		h.c.inc(ctx.Value(methodKey{}).(string))
+	}
+}
+
+func (h rpcStats) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context { return ctx }
+func (h rpcStats) HandleConn(context.Context, stats.ConnStats)                       {}
+
+func TestUserInterceptorsObserveEveryRPC(t *testing.T) {
+	ref, srvSeen, poolSeen := newCounter(), newCounter(), newCounter()
+
+	r := testring.New(t, 3,
+		testring.WithGRPCOptions(grpc.StatsHandler(rpcStats{ref})),
+		testring.WithServerOptions(
+			server.WithUnaryInterceptors(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+				srvSeen.inc(info.FullMethod)
+				return h(ctx, req)
+			}),
+			server.WithStreamInterceptors(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+				srvSeen.inc(info.FullMethod)
+				return h(srv, ss)
+			}),
+		),
+		testring.WithPoolOptions(
+			nodeclient.WithUnaryInterceptors(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, inv grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				poolSeen.inc(method)
+				return inv(ctx, method, req, reply, cc, opts...)
+			}),
+			nodeclient.WithStreamInterceptors(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, s grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				poolSeen.inc(method)
+				return s(ctx, desc, cc, method, opts...)
+			}),
+		),
+	)
+
+	// Genera traffico su tutti i tipi di RPC: Store (stream), Retrieve,
+	// Remove, RetrieveRange (stream, al join) e le API client.
+	for i := 0; i < 20; i++ {
+		raw := fmt.Sprintf("key-%d", i)
+		res := domain.Resource{Key: r.Space.NewIdFromString(raw), RawKey: raw, Value: raw}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if err := r.Members[0].Node.Put(ctx, res); err != nil {
+			t.Fatalf("Put %s: %v", raw, err)
+		}
+		if _, err := r.Members[1].Node.Get(ctx, res.Key); err != nil {
+			t.Fatalf("Get %s: %v", raw, err)
+		}
+		if err := r.Members[2].Node.Delete(ctx, res.Key); err != nil {
+			t.Fatalf("Delete %s: %v", raw, err)
+		}
+		cancel()
+	}
+	r.Add()
+	r.WaitStable()
+
+	api, conn, err := client.Connect(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, _, err := client.GetRoutingTable(ctx, api); err != nil {
+		t.Fatalf("GetRoutingTable: %v", err)
+	}
+
+	// Ferma la manutenzione e attende che le RPC in volo terminino
+	r.StopStabilizers()
+	var want, gotSrv, gotPool map[string]int
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		time.Sleep(50 * time.Millisecond)
+		want, gotSrv, gotPool = ref.snapshot(), srvSeen.snapshot(), poolSeen.snapshot()
+		if maps.Equal(want, gotSrv) && maps.Equal(dhtOnly(want), gotPool) || time.Now().After(deadline) {
+			break
+		}
+	}
+
+	for _, m := range []string{
+		"/dht.v1.DHT/FindSuccessor", "/dht.v1.DHT/Notify", "/dht.v1.DHT/Store",
+		"/dht.v1.DHT/Retrieve", "/dht.v1.DHT/Remove", "/dht.v1.DHT/RetrieveRange",
+		"/client.v1.ClientAPI/GetRoutingTable",
+	} {
+		if want[m] == 0 {
+			t.Errorf("no %s RPC was generated", m)
+		}
+	}
+	if !maps.Equal(want, gotSrv) {
+		t.Errorf("server interceptors missed RPCs:\n got  %v\n want %v", gotSrv, want)
+	}
+	if !maps.Equal(dhtOnly(want), gotPool) {
+		t.Errorf("pool interceptors missed RPCs:\n got  %v\n want %v", gotPool, dhtOnly(want))
+	}
+}
+
+// dhtOnly filtra le RPC del servizio DHT, le sole originate dal pool.
+func dhtOnly(m map[string]int) map[string]int {
+	out := make(map[string]int)
+	for k, v := range m {
+		if strings.HasPrefix(k, "/dht.v1.DHT/") {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/node/ctxutil"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Limits bounds the RPCs a server serves at once (see
+// WithConcurrencyLimits). A zero limit means unlimited. The health service
+// is never limited.
+type Limits struct {
+	// MaxRequests bounds the RPCs of the clients on both services.
+	MaxRequests int
+	// MaxMaintenance bounds the RPCs between nodes: the stabilization ones
+	// (GetPredecessor, GetSuccessorList, Notify, Ping, Leave,
+	// PredecessorLeaving, SyncDigest) and every DHT RPC flagged as sent by
+	// another node (see ctxutil.WithPeer), such as the lookup steps it
+	// forwards and the replicas it stores, so that a storm of client
+	// requests cannot starve the maintenance of the ring.
+	MaxMaintenance int
+	// Methods gives single methods their own budget instead of the one of
+	// their class, by method name ("Put") or full name
+	// ("/client.v1.ClientAPI/Put"); 0 exempts the method from any limit.
+	Methods map[string]int
+}
+
+// maintenanceMethods are the RPCs counted against Limits.MaxMaintenance
+// whoever sends them.
+var maintenanceMethods = map[string]bool{
+	dhtv1.DHT_GetPredecessor_FullMethodName:     true,
+	dhtv1.DHT_GetSuccessorList_FullMethodName:   true,
+	dhtv1.DHT_Notify_FullMethodName:             true,
+	dhtv1.DHT_Ping_FullMethodName:               true,
+	dhtv1.DHT_Leave_FullMethodName:              true,
+	dhtv1.DHT_PredecessorLeaving_FullMethodName: true,
+	dhtv1.DHT_SyncDigest_FullMethodName:         true,
+}
+
+// overloadedMsg starts the message of the RPCs rejected by the limiter,
+// which clients use to tell them apart from the other ResourceExhausted
+// errors (a full storage).
+const overloadedMsg = "server overloaded"
+
+// semaphore bounds the concurrent holders of a budget; a nil semaphore is
+// unlimited.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+// tryAcquire takes a slot without waiting and reports whether it got one.
+func (s semaphore) tryAcquire() bool {
+	if s == nil {
+		return true
+	}
+	select {
+	case s <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
+
+// limiter rejects the RPCs exceeding their budget with
+// codes.ResourceExhausted, without queueing them: a loaded node answers
+// at once and the client can retry elsewhere or later.
+type limiter struct {
+	requests    semaphore
+	maintenance semaphore
+	methods     map[string]semaphore // own budget by full method name
+}
+
+// newLimiter resolves the method names of l against the services of the
+// server. It fails on a negative limit or a name matching no method.
+func newLimiter(l Limits) (*limiter, error) {
+	if l.MaxRequests < 0 || l.MaxMaintenance < 0 {
+		return nil, fmt.Errorf("server: negative concurrency limit")
+	}
+	lim := &limiter{
+		requests:    newSemaphore(l.MaxRequests),
+		maintenance: newSemaphore(l.MaxMaintenance),
+		methods:     make(map[string]semaphore, len(l.Methods)),
+	}
+	all := serviceMethods()
+	names := make([]string, 0, len(l.Methods))
+	for name := range l.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names) // deterministic error on several bad names
+	for _, name := range names {
+		n := l.Methods[name]
+		if n < 0 {
+			return nil, fmt.Errorf("server: negative concurrency limit for method %s", name)
+		}
+		matched := false
+		for _, full := range all {
+			if full == name || full[strings.LastIndex(full, "/")+1:] == name {
+				lim.methods[full] = newSemaphore(n)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("server: unknown method %q in concurrency limits", name)
+		}
+	}
+	return lim, nil
+}
+
+// serviceMethods returns the full names of the RPCs of both services.
+func serviceMethods() []string {
+	var out []string
+	for _, sd := range []grpc.ServiceDesc{clientv1.ClientAPI_ServiceDesc, dhtv1.DHT_ServiceDesc} {
+		for _, m := range sd.Methods {
+			out = append(out, "/"+sd.ServiceName+"/"+m.MethodName)
+		}
+		for _, st := range sd.Streams {
+			out = append(out, "/"+sd.ServiceName+"/"+st.StreamName)
+		}
+	}
+	return out
+}
+
+// acquire takes a slot of the budget of method, called with ctx, or
+// returns the ResourceExhausted error to reply with.
+func (l *limiter) acquire(ctx context.Context, method string) (semaphore, error) {
+	if isHealthMethod(method) {
+		return nil, nil // nil semaphore: unlimited
+	}
+	sem, class := l.requests, ""
+	if own, ok := l.methods[method]; ok {
+		sem, class = own, method+" "
+	} else if maintenanceMethods[method] || (strings.HasPrefix(method, "/"+dhtv1.DHT_ServiceDesc.ServiceName+"/") && ctxutil.FromPeer(ctx)) {
+		sem, class = l.maintenance, "maintenance "
+	}
+	if !sem.tryAcquire() {
+		return nil, status.Errorf(codes.ResourceExhausted, "%s: %d concurrent %srequests in progress", overloadedMsg, cap(sem), class)
+	}
+	return sem, nil
+}
+
+func (l *limiter) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+	sem, err := l.acquire(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	defer sem.release()
+	return h(ctx, req)
+}
+
+func (l *limiter) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+	sem, err := l.acquire(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	defer sem.release()
+	return h(srv, ss)
+}
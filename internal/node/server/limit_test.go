@@ -0,0 +1,220 @@
+package server_test
+
+import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/ctxutil"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestConcurrencyLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   server.Limits
+		block    string   // metodo che resta in corso occupando il suo budget
+		rejected []string // chiamate rifiutate mentre block è in corso
+		allowed  []string // chiamate servite comunque
+	}{
+		{
+			name:     "requests budget",
+			limits:   server.Limits{MaxRequests: 1},
+			block:    "Get",
+			rejected: []string{"Lookup", "Info"},
+			allowed:  []string{"Ping", "GetSuccessorList"},
+		},
+		{
+			name:     "maintenance budget",
+			limits:   server.Limits{MaxRequests: 1, MaxMaintenance: 1},
+			block:    "GetSuccessorList",
+			rejected: []string{"Ping"},
+			allowed:  []string{"Lookup", "Info"},
+		},
+		{
+			// le RPC inviate da altri nodi non consumano il budget dei client
+			name:     "peer requests",
+			limits:   server.Limits{MaxRequests: 1},
+			block:    "Get",
+			rejected: []string{"Info"},
+			allowed:  []string{"FindSuccessor"},
+		},
+		{
+			name:     "peer requests budget",
+			limits:   server.Limits{MaxRequests: 1, MaxMaintenance: 1},
+			block:    "FindSuccessor",
+			rejected: []string{"Ping"},
+			allowed:  []string{"Lookup", "Info"},
+		},
+		{
+			name:     "method budget",
+			limits:   server.Limits{MaxRequests: 10, Methods: map[string]int{"Get": 1}},
+			block:    "Get",
+			rejected: []string{"Get"},
+			allowed:  []string{"Lookup", "Info"},
+		},
+		{
+			name:     "exempt method",
+			limits:   server.Limits{MaxRequests: 1, Methods: map[string]int{"/client.v1.ClientAPI/Lookup": 0}},
+			block:    "Get",
+			rejected: []string{"Info"},
+			allowed:  []string{"Lookup"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entered, release := make(chan struct{}), make(chan struct{})
+			var once sync.Once
+			blocker := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+				if strings.HasSuffix(info.FullMethod, "/"+tt.block) {
+					once.Do(func() { close(entered) })
+					<-release
+				}
+				return h(ctx, req)
+			}
+			r := testring.New(t, 1, testring.WithServerOptions(
+				server.WithConcurrencyLimits(tt.limits), server.WithUnaryInterceptors(blocker)))
+			api, conn, err := client.Connect(r.Members[0].Addr)
+			if err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			defer conn.Close()
+			dht := dhtv1.NewDHTClient(conn)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			id := r.Space.NewIdFromString("key").ToHexString(true)
+			calls := map[string]func() error{
+				"Get":              func() error { _, _, err := client.Get(ctx, api, "key"); return ignoreNotFound(err) },
+				"Lookup":           func() error { _, _, err := client.Lookup(ctx, api, id); return err },
+				"Info":             func() error { _, _, err := client.Info(ctx, api); return err },
+				"Ping":             func() error { _, err := dht.Ping(ctx, &emptypb.Empty{}); return err },
+				"GetSuccessorList": func() error { _, err := dht.GetSuccessorList(ctx, &emptypb.Empty{}); return err },
+				// passo di lookup inoltrato da un altro nodo
+				"FindSuccessor": func() error {
+					_, err := dht.FindSuccessor(ctxutil.WithPeer(ctx), &dhtv1.FindSuccessorRequest{
+						TargetId: r.Space.NewIdFromString("key"),
+						Mode:     &dhtv1.FindSuccessorRequest_Initial{Initial: &dhtv1.Initial{}},
+					})
+					return err
+				},
+			}
+
+			done := make(chan error, 1)
+			go func() { done <- calls[tt.block]() }()
+			select {
+			case <-entered:
+			case <-ctx.Done():
+				t.Fatalf("%s never reached the server", tt.block)
+			}
+
+			for _, name := range tt.rejected {
+				err := calls[name]()
+				if name == "Ping" || name == "GetSuccessorList" || name == "FindSuccessor" {
+					// RPC interna: l'errore non passa da client.normalizeError
+					if err == nil || !strings.Contains(err.Error(), "server overloaded") {
+						t.Errorf("%s while %s is in progress: got %v, want server overloaded", name, tt.block, err)
+					}
+				} else if !errors.Is(err, client.ErrOverloaded) {
+					t.Errorf("%s while %s is in progress: got %v, want ErrOverloaded", name, tt.block, err)
+				}
+			}
+			for _, name := range tt.allowed {
+				if err := calls[name](); err != nil {
+					t.Errorf("%s while %s is in progress: %v", name, tt.block, err)
+				}
+			}
+
+			// liberato il budget, le richieste rifiutate vengono servite
+			close(release)
+			if err := <-done; err != nil {
+				t.Fatalf("blocked %s: %v", tt.block, err)
+			}
+			for _, name := range tt.rejected {
+				if err := calls[name](); err != nil {
+					t.Errorf("%s after %s completed: %v", name, tt.block, err)
+				}
+			}
+		})
+	}
+}
+
+func TestPeerCallsFlagged(t *testing.T) {
+	// Registra le RPC DHT ricevute senza il flag dei nodi
+	var mu sync.Mutex
+	unflagged := map[string]int{}
+	record := func(ctx context.Context, method string) {
+		if strings.HasPrefix(method, "/dht.v1.DHT/") && !ctxutil.FromPeer(ctx) {
+			mu.Lock()
+			unflagged[method]++
+			mu.Unlock()
+		}
+	}
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+		record(ctx, info.FullMethod)
+		return h(ctx, req)
+	}
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+		record(ss.Context(), info.FullMethod)
+		return h(srv, ss)
+	}
+	r := testring.New(t, 3, testring.WithServerOptions(
+		server.WithUnaryInterceptors(unary), server.WithStreamInterceptors(stream)))
+	r.WaitStable()
+
+	// stabilizzazione, lookup inoltrati e Store verso il responsabile
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := range 8 {
+		raw := fmt.Sprintf("peer-%d", i)
+		if err := r.Members[0].Node.Put(ctx, domain.Resource{Key: r.Space.NewIdFromString(raw), RawKey: raw, Value: raw}); err != nil {
+			t.Fatalf("Put(%s): %v", raw, err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(unflagged) > 0 {
+		t.Errorf("RPCs between nodes without the peer flag: %v", unflagged)
+	}
+}
+
+func ignoreNotFound(err error) error {
+	if errors.Is(err, client.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func TestConcurrencyLimitsInvalid(t *testing.T) {
+	r := testring.New(t, 1)
+	tests := []struct {
+		name   string
+		limits server.Limits
+	}{
+		{name: "negative", limits: server.Limits{MaxRequests: -1}},
+		{name: "negative method", limits: server.Limits{Methods: map[string]int{"Put": -1}}},
+		{name: "unknown method", limits: server.Limits{Methods: map[string]int{"Putt": 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("Listen: %v", err)
+			}
+			defer lis.Close()
+			if _, err := server.New(lis, r.Members[0].Node, nil, server.WithConcurrencyLimits(tt.limits)); err == nil {
+				t.Errorf("New with %+v: got nil error", tt.limits)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package server_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/server"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestListenAdvertisedAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		version  string
+		host     string
+		wantHost string // host pubblicizzato atteso ("" = errore)
+		wantErr  string
+	}{
+		{name: "ipv4", mode: "private", version: "v4", host: "10.0.0.1", wantHost: "10.0.0.1"},
+		{name: "ipv6 ula", mode: "private", version: "v6", host: "fd12:3456::1", wantHost: "fd12:3456::1"},
+		{name: "ipv6 bracketed", mode: "private", version: "v6", host: "[fd12:3456::1]", wantHost: "fd12:3456::1"},
+		{name: "ipv6 link-local is private", mode: "private", version: "v6", host: "fe80::1", wantHost: "fe80::1"},
+		{name: "ipv6 global", mode: "public", version: "v6", host: "2001:db8::1", wantHost: "2001:db8::1"},
+		{name: "auto accepts ipv6", mode: "public", version: "auto", host: "2001:db8::1", wantHost: "2001:db8::1"},
+		{name: "auto accepts ipv4", mode: "private", version: "auto", host: "192.168.1.1", wantHost: "192.168.1.1"},
+		{name: "hostname", mode: "public", version: "v6", host: "node-1.example", wantHost: "node-1.example"},
+		{name: "global ipv6 in private mode", mode: "private", version: "v6", host: "2001:db8::1", wantErr: "not private"},
+		{name: "ula in public mode", mode: "public", version: "v6", host: "fd00::1", wantErr: "is private"},
+		{name: "ipv4 with v6", mode: "private", version: "v6", host: "10.0.0.1", wantErr: "not an IPv6"},
+		{name: "ipv6 with v4", mode: "private", version: "v4", host: "fd00::1", wantErr: "not an IPv4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, advertised, err := server.Listen(tt.mode, tt.version, "127.0.0.1", tt.host, 0)
+			if tt.wantErr != "" {
+				if err == nil {
+					_ = ln.Close()
+					t.Fatalf("Listen: got advertised %s, want error containing %q", advertised, tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Listen: got %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Listen: %v", err)
+			}
+			defer ln.Close()
+
+			// l'indirizzo pubblicizzato è host:porta, con l'IPv6 tra parentesi
+			host, port, err := net.SplitHostPort(advertised)
+			if err != nil {
+				t.Fatalf("SplitHostPort(%s): %v", advertised, err)
+			}
+			if host != tt.wantHost {
+				t.Errorf("advertised host: got %s, want %s", host, tt.wantHost)
+			}
+			if want := strconv.Itoa(ln.Addr().(*net.TCPAddr).Port); port != want {
+				t.Errorf("advertised port: got %s, want %s", port, want)
+			}
+			if strings.Contains(tt.wantHost, ":") && !strings.HasPrefix(advertised, "[") {
+				t.Errorf("IPv6 advertised address %s is not bracketed", advertised)
+			}
+		})
+	}
+}
+
+func TestIPv6AddressIDs(t *testing.T) {
+	space, err := domain.NewSpace(66, 8, 4)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	// indirizzi pubblicizzati che differiscono solo per host o porta
+	addrs := []string{"[2001:db8::1]:4000", "[2001:db8::1]:4001", "[2001:db8::2]:4000", "2001:db8::1:4000"}
+	seen := make(map[string]string)
+	for _, addr := range addrs {
+		id := space.NewIdFromString(addr)
+		if !id.Equal(space.NewIdFromString(addr)) {
+			t.Errorf("NewIdFromString(%s) is not deterministic", addr)
+		}
+		if err := space.IsValidID(id); err != nil {
+			t.Errorf("NewIdFromString(%s): %v", addr, err)
+		}
+		hex := id.ToHexString(true)
+		if other, ok := seen[hex]; ok {
+			t.Errorf("%s and %s map to the same ID %s", addr, other, hex)
+		}
+		seen[hex] = addr
+	}
+}
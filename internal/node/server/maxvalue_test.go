@@ -0,0 +1,76 @@
+package server_test
+
+import (
+	"KoordeDHT/internal/client"
+	nodeclient "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxValueBytes(t *testing.T) {
+	const limit = 6 << 20 // oltre il limite predefinito di 4 MiB dei messaggi gRPC
+	r := testring.New(t, 3,
+		testring.WithServerOptions(server.WithMaxValueBytes(limit)),
+		testring.WithPoolOptions(nodeclient.WithMaxRecvMsgSize(server.MsgSizeFor(limit))))
+
+	// nodo d'ingresso diverso dal responsabile: il valore passa per Store e Retrieve tra nodi
+	const key = "large"
+	entry := r.Members[0]
+	if r.Owner(r.Space.NewIdFromString(key)) == entry {
+		entry = r.Members[1]
+	}
+	api, conn, err := client.Connect(entry.Addr, client.WithMaxRecvMsgSize(server.MsgSizeFor(limit)))
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+
+	tests := []struct {
+		name    string
+		size    int
+		wantErr error
+	}{
+		{name: "at the limit", size: limit},
+		{name: "over the limit", size: limit + 1, wantErr: client.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			value := strings.Repeat("v", tt.size)
+			_, err := client.Put(ctx, api, key, value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Put of %d bytes: got %v, want %v", tt.size, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, _, err := client.Get(ctx, api, key)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if got != value {
+				t.Fatalf("Get returned %d bytes, want %d", len(got), len(value))
+			}
+		})
+	}
+
+	// BatchPut riporta il valore troppo grande senza interrompere lo stream
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stored, failed, _, err := client.BatchPut(ctx, api, []client.PutItem{
+		{Key: "small", Value: "v"},
+		{Key: "huge", Value: strings.Repeat("v", limit+1)},
+	})
+	if err != nil {
+		t.Fatalf("BatchPut: %v", err)
+	}
+	if _, ok := failed["huge"]; stored != 1 || !ok {
+		t.Fatalf("BatchPut: stored %d, failed %v; want 1 stored and huge failed", stored, failed)
+	}
+}
@@ -0,0 +1,74 @@
+package server_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestResourceMetadata(t *testing.T) {
+	r := testring.New(t, 4)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name string
+		key  string
+		meta map[string]string
+	}{
+		{name: "with metadata", key: "doc", meta: map[string]string{"content-type": "text/plain", "owner": "alice"}},
+		// Senza metadati la risorsa si comporta come prima
+		{name: "without metadata", key: "plain"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			put, putConn, err := client.Connect(r.Members[i].Addr)
+			if err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			defer putConn.Close()
+			if _, err := client.PutMeta(ctx, put, tt.key, "value", tt.meta, 0); err != nil {
+				t.Fatalf("PutMeta: %v", err)
+			}
+
+			// La Get da un altro nodo attraversa il trasferimento tra nodi
+			get, getConn, err := client.Connect(r.Members[(i+2)%len(r.Members)].Addr)
+			if err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			defer getConn.Close()
+			v, meta, _, err := client.GetMeta(ctx, get, tt.key)
+			if err != nil || v != "value" {
+				t.Fatalf("GetMeta: got %q, %v", v, err)
+			}
+			if !maps.Equal(meta, tt.meta) {
+				t.Errorf("GetMeta metadata = %v, want %v", meta, tt.meta)
+			}
+
+			owner, ownerConn, err := client.Connect(r.Owner(r.Space.NewIdFromString(tt.key)).Addr)
+			if err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			defer ownerConn.Close()
+			items, _, err := client.GetStore(ctx, owner)
+			if err != nil {
+				t.Fatalf("GetStore: %v", err)
+			}
+			found := false
+			for _, it := range items {
+				if it.Key == tt.key {
+					found = true
+					if !maps.Equal(it.Metadata, tt.meta) {
+						t.Errorf("GetStore metadata = %v, want %v", it.Metadata, tt.meta)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("GetStore of the owner misses %s", tt.key)
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package server
 
-import "KoordeDHT/internal/logger"
+import (
+	"KoordeDHT/internal/logger"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
 
 // Option is a functional option for configuring the Server.
 type Option func(*Server)
@@ -11,3 +17,109 @@ func WithLogger(lgr logger.Logger) Option {
 		s.lgr = lgr
 	}
 }
+
+// WithUnaryInterceptors appends user-supplied unary interceptors
+// (e.g., auth, logging, metrics) to the server chain.
+//
+// Ordering: stats handlers passed in grpcOpts (otelgrpc) observe the
+// RPC first, then any grpc.UnaryInterceptor passed in grpcOpts, then
+// the built-in concurrency limiter (see WithConcurrencyLimits), admin
+// gate (see WithAdmin) and lookuptrace interceptors, and finally the user
+// interceptors in the order given (the first one is the outermost).
+// Multiple calls accumulate.
+func WithUnaryInterceptors(ints ...grpc.UnaryServerInterceptor) Option {
+	return func(s *Server) {
+		s.unaryInts = append(s.unaryInts, ints...)
+	}
+}
+
+// WithStreamInterceptors appends user-supplied stream interceptors to
+// the server chain. Ordering follows the same rules as
+// WithUnaryInterceptors (the only built-in stream interceptors are the
+// startup gate and the concurrency limiter).
+func WithStreamInterceptors(ints ...grpc.StreamServerInterceptor) Option {
+	return func(s *Server) {
+		s.streamInts = append(s.streamInts, ints...)
+	}
+}
+
+// WithAccessLog enables the audit access log of the client Get, Put and
+// Delete RPCs, written to lgr (see AccessLogInterceptor). The access log
+// interceptor runs right after the built-in lookuptrace interceptor, so
+// user interceptors cannot hide requests from it.
+func WithAccessLog(lgr logger.Logger) Option {
+	return func(s *Server) {
+		s.accessLgr = lgr
+	}
+}
+
+// WithConfig sets the configuration fields returned by the GetConfig RPC
+// (see config.Config.Fields, which redacts secrets). Without it GetConfig
+// fails with FailedPrecondition.
+func WithConfig(fields []logger.Field) Option {
+	return func(s *Server) {
+		s.config = fields
+	}
+}
+
+// WithStartupGate makes the server reject every RPC, of both the client
+// and the DHT service, with codes.Unavailable until SetReady is called.
+// The node calls SetReady once it has joined (or created) the DHT and
+// registered with the bootstrap service, so that load balancers and
+// peers never reach a node that is not discoverable yet. The gate is the
+// outermost interceptor: rejected RPCs reach neither the built-in nor the
+// user interceptors. The health service is exempt, and reports
+// NOT_SERVING while the gate is closed (see SetServing).
+func WithStartupGate() Option {
+	return func(s *Server) {
+		s.gated = true
+	}
+}
+
+// WithMaxValueBytes makes the client Put and BatchPut RPCs reject values
+// larger than n bytes with codes.InvalidArgument, and raises the maximum
+// size of the messages the server receives so that a Put carrying a value
+// of n bytes fits in one (gRPC options passed to New take precedence).
+// n <= 0 (default) disables the check: the gRPC limit (4 MiB) applies.
+func WithMaxValueBytes(n int64) Option {
+	return func(s *Server) {
+		s.maxValueBytes = max(n, 0)
+	}
+}
+
+// WithKeepalive makes the server ping a client connection after idle
+// without activity and close it if the ping is not acknowledged within
+// timeout. It also accepts the keepalive pings of clients configured with
+// the same idle time (client.WithKeepalive), sent at most every idle/2 and,
+// with permitWithoutStream, also while no RPC is in flight: gRPC closes
+// the connections of clients pinging more often than that. A non-positive
+// idle keeps the gRPC defaults (default), which reject client pings more
+// frequent than every 5 minutes.
+func WithKeepalive(idle, timeout time.Duration, permitWithoutStream bool) Option {
+	return func(s *Server) {
+		s.keepalive = keepalive.ServerParameters{Time: idle, Timeout: timeout}
+		s.enforce = keepalive.EnforcementPolicy{MinTime: idle / 2, PermitWithoutStream: permitWithoutStream}
+	}
+}
+
+// WithAdmin enables the admin RPCs of the client API (Shutdown), which
+// otherwise fail with codes.PermissionDenied. They let any client make the
+// node leave the ring, so they must not be enabled on nodes reachable by
+// untrusted clients.
+func WithAdmin() Option {
+	return func(s *Server) {
+		s.admin = true
+	}
+}
+
+// WithConcurrencyLimits bounds the RPCs served at once (see Limits): an
+// RPC exceeding its budget is rejected at once with
+// codes.ResourceExhausted, without queueing. Streaming RPCs hold their
+// slot until the stream ends. The limiter runs right after the startup
+// gate, before any other interceptor. New fails if a limit is negative or
+// names an unknown method.
+func WithConcurrencyLimits(l Limits) Option {
+	return func(s *Server) {
+		s.limits = &l
+	}
+}
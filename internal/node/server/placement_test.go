@@ -0,0 +1,77 @@
+package server_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLookupOwnership(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas int
+	}{
+		{name: "no replication", replicas: 1},
+		{name: "three replicas", replicas: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testring.New(t, 5, testring.WithNodeOptions(logicnode.WithReplicas(tt.replicas)))
+			api, conn, err := client.Connect(r.Members[0].Addr)
+			if err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			defer conn.Close()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			for i := 0; i < 20; i++ {
+				id := r.Space.NewIdFromString(fmt.Sprintf("key-%d", i))
+				resp, _, err := client.LookupOwnership(ctx, api, id.ToHexString(true))
+				if err != nil {
+					t.Fatalf("LookupOwnership(%s): %v", id.ToHexString(true), err)
+				}
+				// i membri sono ordinati per ID: predecessore e repliche sono i vicini del responsabile
+				owner := r.Owner(id)
+				idx := 0
+				for j, m := range r.Members {
+					if m == owner {
+						idx = j
+					}
+				}
+				if got := resp.Successor.GetAddr(); got != owner.Addr {
+					t.Fatalf("LookupOwnership(%s): successor %s, want %s", id.ToHexString(true), got, owner.Addr)
+				}
+				pred := r.Members[(idx+len(r.Members)-1)%len(r.Members)]
+				if got := resp.Predecessor.GetAddr(); got != pred.Addr {
+					t.Errorf("LookupOwnership(%s): predecessor %s, want %s", id.ToHexString(true), got, pred.Addr)
+				}
+				if len(resp.Replicas) != tt.replicas-1 {
+					t.Fatalf("LookupOwnership(%s): %d replicas, want %d", id.ToHexString(true), len(resp.Replicas), tt.replicas-1)
+				}
+				for k, nd := range resp.Replicas {
+					want := r.Members[(idx+k+1)%len(r.Members)]
+					if nd.GetAddr() != want.Addr {
+						t.Errorf("LookupOwnership(%s): replica %d is %s, want %s", id.ToHexString(true), k+1, nd.GetAddr(), want.Addr)
+					}
+				}
+			}
+
+			// nessuna risorsa viene scritta
+			for _, m := range r.Members {
+				if n := len(m.Node.GetAllResourceStored()); n != 0 {
+					t.Errorf("node %s stores %d resources after LookupOwnership, want 0", m.Addr, n)
+				}
+			}
+
+			if _, _, err := client.LookupOwnership(ctx, api, "not-hex"); !errors.Is(err, client.ErrInvalidArgument) {
+				t.Errorf("LookupOwnership(not-hex): got %v, want ErrInvalidArgument", err)
+			}
+		})
+	}
+}
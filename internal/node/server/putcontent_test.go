@@ -0,0 +1,63 @@
+package server_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPutContent(t *testing.T) {
+	r := testring.New(t, 4)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr error
+	}{
+		{name: "text", value: "hello"},
+		{name: "same content from another node", value: "hello"},
+		{name: "other content", value: "world"},
+		{name: "empty value", value: "", wantErr: client.ErrInvalidArgument},
+	}
+	keys := make(map[string]string) // valore -> chiave restituita
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, conn, err := client.Connect(r.Members[i%len(r.Members)].Addr)
+			if err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			defer conn.Close()
+
+			key, _, err := client.PutContent(ctx, api, tt.value, 0)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("PutContent: got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PutContent: %v", err)
+			}
+			// la chiave è l'hash del valore, qualunque nodo lo calcoli
+			id := r.Space.NewIdFromString(tt.value)
+			if want := id.ToHexString(true); key != want {
+				t.Fatalf("PutContent key = %s, want %s", key, want)
+			}
+			if prev, ok := keys[tt.value]; ok && prev != key {
+				t.Errorf("same content stored under %s and %s", prev, key)
+			}
+			keys[tt.value] = key
+			if _, err := r.Owner(id).Node.RetrieveLocal(id); err != nil {
+				t.Fatalf("owner of %s does not hold the resource: %v", key, err)
+			}
+			if v, _, err := client.GetID(ctx, api, key); err != nil || v != tt.value {
+				t.Errorf("GetID(%s): got %q, %v, want %q", key, v, err, tt.value)
+			}
+		})
+	}
+}
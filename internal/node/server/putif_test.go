@@ -0,0 +1,63 @@
+package server_test
+
+import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPutIf(t *testing.T) {
+	r := testring.New(t, 4)
+	api, conn, err := client.Connect(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// più chiavi, così che alcune siano gestite dal nodo contattato e altre da nodi remoti
+	for i := range 8 {
+		key := fmt.Sprintf("key-%d", i)
+		steps := []struct {
+			name    string
+			op      func() (time.Duration, error)
+			wantErr error
+			want    string
+		}{
+			{name: "putnx on new key", op: func() (time.Duration, error) { return client.PutIfAbsent(ctx, api, key, "v1", 0) }, want: "v1"},
+			{name: "putnx on existing key", op: func() (time.Duration, error) { return client.PutIfAbsent(ctx, api, key, "v2", 0) }, wantErr: client.ErrPreconditionFailed, want: "v1"},
+			{name: "cas with stale value", op: func() (time.Duration, error) { return client.CompareAndSwap(ctx, api, key, "v0", "v2", 0) }, wantErr: client.ErrPreconditionFailed, want: "v1"},
+			{name: "cas with current value", op: func() (time.Duration, error) { return client.CompareAndSwap(ctx, api, key, "v1", "v2", 0) }, want: "v2"},
+		}
+		for _, st := range steps {
+			if _, err := st.op(); !errors.Is(err, st.wantErr) {
+				t.Fatalf("%s: %s: got %v, want %v", key, st.name, err, st.wantErr)
+			}
+			if v, _, err := client.Get(ctx, api, key); err != nil || v != st.want {
+				t.Fatalf("%s: %s: Get: got %q, %v, want %q", key, st.name, v, err, st.want)
+			}
+		}
+	}
+
+	if _, err := client.CompareAndSwap(ctx, api, "missing", "v0", "v1", 0); !errors.Is(err, client.ErrPreconditionFailed) {
+		t.Errorf("cas on missing key: got %v, want ErrPreconditionFailed", err)
+	}
+	// una sola condizione per richiesta
+	_, err = api.PutIf(ctx, &clientv1.PutIfRequest{
+		Resource: &clientv1.Resource{Key: "key", Value: "v"},
+		IfAbsent: true,
+		IfEqual:  true,
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("PutIf with both conditions: got %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,54 @@
+package server_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPreHashedKeyID(t *testing.T) {
+	r := testring.New(t, 4)
+	api, conn, err := client.Connect(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// digest SHA-256 calcolato dal client, troncato nello spazio dal nodo
+	digest := sha256.Sum256([]byte("content"))
+	idHex := hex.EncodeToString(digest[:])
+	id, err := r.Space.FromBytes(digest[:])
+	if err != nil {
+		t.Fatalf("FromBytes: %v", err)
+	}
+	if _, err := client.PutID(ctx, api, idHex, "value", 0); err != nil {
+		t.Fatalf("PutID: %v", err)
+	}
+	if _, err := r.Owner(id).Node.RetrieveLocal(id); err != nil {
+		t.Fatalf("owner of %s does not hold the resource: %v", id.ToHexString(true), err)
+	}
+	if v, _, err := client.GetID(ctx, api, "0x"+idHex); err != nil || v != "value" {
+		t.Fatalf("GetID: got %q, %v, want value", v, err)
+	}
+	// la stessa stringa usata come chiave normale viene invece hashata
+	if _, _, err := client.Get(ctx, api, idHex); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("Get(%s): got %v, want ErrNotFound", idHex, err)
+	}
+	if _, err := client.DeleteID(ctx, api, idHex); err != nil {
+		t.Fatalf("DeleteID: %v", err)
+	}
+	if _, _, err := client.GetID(ctx, api, idHex); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("GetID after DeleteID: got %v, want ErrNotFound", err)
+	}
+
+	if _, err := client.PutID(ctx, api, "not-hex", "value", 0); !errors.Is(err, client.ErrInvalidArgument) {
+		t.Errorf("PutID(not-hex): got %v, want ErrInvalidArgument", err)
+	}
+}
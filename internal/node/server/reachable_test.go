@@ -0,0 +1,83 @@
+package server_test
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	nodeclient "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/storage"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestVerifySelfReachable riproduce il controllo di main sull'indirizzo
+// pubblicizzato: il Ping deve arrivare al nodo stesso, anche se il gate di
+// avvio è ancora chiuso, e fallire per un indirizzo sbagliato.
+func TestVerifySelfReachable(t *testing.T) {
+	// Porta libera ma senza server: indirizzo pubblicizzato errato
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	wrong := closed.Addr().String()
+	_ = closed.Close()
+
+	tests := []struct {
+		name      string
+		gated     bool
+		wrongAddr bool
+		wantErr   bool
+	}{
+		{name: "reachable"},
+		{name: "reachable behind the startup gate", gated: true},
+		{name: "wrong advertised address", wrongAddr: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp, err := domain.NewSpace(16, 2, 4)
+			if err != nil {
+				t.Fatalf("NewSpace: %v", err)
+			}
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("listen: %v", err)
+			}
+			addr := lis.Addr().String()
+			advertised := addr
+			if tt.wrongAddr {
+				advertised = wrong
+			}
+			self := &domain.Node{ID: sp.NewIdFromString(advertised), Addr: advertised}
+			cp := nodeclient.New(self.ID, addr, time.Second)
+			n := logicnode.New(routingtable.New(self, sp), cp, storage.NewMemoryStorage(&logger.NopLogger{}))
+			var opts []server.Option
+			if tt.gated {
+				opts = append(opts, server.WithStartupGate())
+			}
+			srv, err := server.New(lis, n, nil, opts...)
+			if err != nil {
+				t.Fatalf("server.New: %v", err)
+			}
+			go func() { _ = srv.Start() }()
+			t.Cleanup(func() {
+				srv.Stop()
+				_ = cp.Close()
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			err = cp.Probe(ctx, advertised)
+			reachable := err == nil || server.IsNotReady(err)
+			if tt.gated && !server.IsNotReady(err) {
+				t.Errorf("Probe through the closed gate: got %v, want the not-ready rejection", err)
+			}
+			if reachable == tt.wantErr {
+				t.Fatalf("Probe(%s): reachable=%v (err %v), want %v", advertised, reachable, err, !tt.wantErr)
+			}
+		})
+	}
+}
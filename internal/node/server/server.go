@@ -5,10 +5,21 @@ import (
 	dhtv1 "KoordeDHT/internal/api/dht/v1"
 	"KoordeDHT/internal/logger"
 	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/telemetry/lookuptrace"
+	"context"
+	"errors"
 	"fmt"
 	"net"
+	"sync"
+	"sync/atomic"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	_ "google.golang.org/grpc/encoding/gzip" // accept gzip-compressed requests (see client.WithCompression)
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 )
 
 // Server wraps a gRPC server that exposes both the client-facing
@@ -17,6 +28,45 @@ type Server struct {
 	grpcServer *grpc.Server
 	listener   net.Listener
 	lgr        logger.Logger
+	unaryInts  []grpc.UnaryServerInterceptor  // user interceptors, chained after the built-ins
+	streamInts []grpc.StreamServerInterceptor // user interceptors, chained after the built-ins
+	accessLgr  logger.Logger                  // audit logger for client operations (nil = access log disabled)
+	config     []logger.Field                 // effective configuration exposed by GetConfig (nil = not available)
+	gated      bool                           // reject RPCs until SetReady (see WithStartupGate)
+	ready      atomic.Bool
+	// standard health service, SERVING once the node is in the DHT (see SetServing)
+	health   *health.Server
+	healthMu sync.Mutex // serializes the updates of the health status
+	serving  atomic.Bool
+	// largest value accepted by Put and BatchPut (0 = no limit, see WithMaxValueBytes)
+	maxValueBytes int64
+	// keepalive pings sent to idle clients and accepted from them (Time 0 = gRPC defaults, see WithKeepalive)
+	keepalive keepalive.ServerParameters
+	enforce   keepalive.EnforcementPolicy
+	admin     bool    // serve the admin RPCs (see WithAdmin)
+	limits    *Limits // concurrency limits (nil = unlimited, see WithConcurrencyLimits)
+	// closed once a Shutdown RPC has been served (see ShutdownRequested)
+	shutdownCh   chan struct{}
+	shutdownOnce sync.Once
+}
+
+const (
+	// defaultRecvMsgSize is the gRPC default limit on received messages.
+	defaultRecvMsgSize = 4 << 20
+	// msgOverhead is the room left in a message for the key and the other
+	// fields of a request or response carrying a value.
+	msgOverhead = 1 << 20
+)
+
+// MsgSizeFor returns the limit on received messages that fits a resource
+// value of maxValueBytes (see WithMaxValueBytes), never below the gRPC
+// default, or 0 if maxValueBytes is not positive. Peers and clients can
+// use it for their receive limit as well.
+func MsgSizeFor(maxValueBytes int64) int {
+	if maxValueBytes <= 0 {
+		return 0
+	}
+	return int(max(maxValueBytes+msgOverhead, defaultRecvMsgSize))
 }
 
 // New constructs a new Server bound to the given listener and
@@ -32,9 +82,17 @@ type Server struct {
 //   - grpcOpts: optional gRPC server options (e.g., interceptors, TLS)
 //   - srvOpts: functional options for configuring the Server itself
 //
+// The standard grpc.health.v1.Health service is registered as well,
+// reporting NOT_SERVING until SetServing(true) (see SetServing).
+//
+// The built-in lookuptrace interceptor is always installed; interceptors
+// supplied through WithUnaryInterceptors/WithStreamInterceptors are
+// chained after it (see WithUnaryInterceptors for the full ordering).
+//
 // Returns:
 //   - A pointer to the initialized Server
-//   - An error if required arguments are missing
+//   - An error if required arguments are missing or the concurrency
+//     limits are invalid (see WithConcurrencyLimits)
 func New(lis net.Listener, n *logicnode.Node, grpcOpts []grpc.ServerOption, srvOpts ...Option) (*Server, error) {
 	if lis == nil {
 		return nil, fmt.Errorf("server: listener must not be nil")
@@ -44,23 +102,141 @@ func New(lis net.Listener, n *logicnode.Node, grpcOpts []grpc.ServerOption, srvO
 	}
 
 	s := &Server{
-		grpcServer: grpc.NewServer(grpcOpts...),
 		listener:   lis,
 		lgr:        &logger.NopLogger{}, // default: no logging
+		health:     health.NewServer(),
+		shutdownCh: make(chan struct{}),
 	}
 
-	// Apply functional options (e.g., custom logger)
+	// Apply functional options (e.g., custom logger, interceptors)
 	for _, opt := range srvOpts {
 		opt(s)
 	}
 
+	// Built-in interceptors first, then the user-supplied ones
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	if s.gated {
+		unary = append(unary, s.gateUnary)
+		stream = append(stream, s.gateStream)
+	}
+	if s.limits != nil {
+		lim, err := newLimiter(*s.limits)
+		if err != nil {
+			return nil, err
+		}
+		unary = append(unary, lim.unary)
+		stream = append(stream, lim.stream)
+	}
+	unary = append(unary, s.adminUnary, lookuptrace.ServerInterceptor())
+	if s.accessLgr != nil {
+		unary = append(unary, AccessLogInterceptor(s.accessLgr))
+	}
+	unary = append(unary, s.unaryInts...)
+	stream = append(stream, s.streamInts...)
+	var opts []grpc.ServerOption
+	if size := MsgSizeFor(s.maxValueBytes); size > 0 {
+		// before grpcOpts, so that a limit set by the caller wins
+		opts = append(opts, grpc.MaxRecvMsgSize(size))
+	}
+	if s.keepalive.Time > 0 {
+		opts = append(opts, grpc.KeepaliveParams(s.keepalive), grpc.KeepaliveEnforcementPolicy(s.enforce))
+	}
+	opts = append(append(opts, grpcOpts...), grpc.ChainUnaryInterceptor(unary...))
+	if len(stream) > 0 {
+		opts = append(opts, grpc.ChainStreamInterceptor(stream...))
+	}
+	s.grpcServer = grpc.NewServer(opts...)
+
 	// Register gRPC services bound to the provided node
-	clientv1.RegisterClientAPIServer(s.grpcServer, NewClientService(n))
+	clientv1.RegisterClientAPIServer(s.grpcServer, &clientService{node: n, config: s.config, maxValueBytes: s.maxValueBytes, shutdown: s.requestShutdown})
 	dhtv1.RegisterDHTServer(s.grpcServer, NewDHTService(n))
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
+	s.updateHealth()
 
 	return s, nil
 }
 
+// SetReady opens the startup gate (see WithStartupGate): from now on RPCs
+// are served, and the health status follows SetServing. It is a no-op if
+// the gate is not enabled.
+func (s *Server) SetReady() {
+	if s.gated && !s.ready.Swap(true) {
+		s.lgr.Info("server: startup gate opened, serving RPCs")
+		s.updateHealth()
+	}
+}
+
+// Ready reports whether RPCs are served, i.e. the startup gate is
+// disabled or SetReady was called.
+func (s *Server) Ready() bool {
+	return !s.gated || s.ready.Load()
+}
+
+// errNotReady is returned by every RPC while the startup gate is closed.
+var errNotReady = status.Error(codes.Unavailable, "node not ready: joining the DHT or registering")
+
+// IsNotReady reports whether err is the rejection of a server whose startup
+// gate is still closed. Such a reply proves the server was reached, unlike
+// the Unavailable errors of the transport.
+func IsNotReady(err error) bool {
+	// errors.As rather than status.FromError, which replaces the message of
+	// a wrapped status with the whole error string
+	var se interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &se) {
+		return false
+	}
+	s, want := se.GRPCStatus(), status.Convert(errNotReady)
+	return s.Code() == want.Code() && s.Message() == want.Message()
+}
+
+func (s *Server) gateUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+	if !s.ready.Load() && !isHealthMethod(info.FullMethod) {
+		return nil, errNotReady
+	}
+	return h(ctx, req)
+}
+
+func (s *Server) gateStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+	if !s.ready.Load() && !isHealthMethod(info.FullMethod) {
+		return errNotReady
+	}
+	return h(srv, ss)
+}
+
+// adminMethods are the RPCs served only by a server created with WithAdmin.
+var adminMethods = map[string]bool{
+	clientv1.ClientAPI_Shutdown_FullMethodName: true,
+}
+
+// adminUnary rejects the admin RPCs with codes.PermissionDenied unless the
+// server was created with WithAdmin.
+func (s *Server) adminUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+	if !s.admin && adminMethods[info.FullMethod] {
+		return nil, status.Errorf(codes.PermissionDenied, "admin RPC %s disabled on this node", info.FullMethod)
+	}
+	return h(ctx, req)
+}
+
+// requestShutdown stops the server gracefully once the in-flight RPCs,
+// the Shutdown RPC that requested it included, have completed.
+func (s *Server) requestShutdown() {
+	s.shutdownOnce.Do(func() {
+		s.lgr.Info("server: shutdown requested, stopping gracefully")
+		s.health.Shutdown()
+		close(s.shutdownCh)
+		go s.grpcServer.GracefulStop()
+	})
+}
+
+// ShutdownRequested returns a channel closed once a Shutdown RPC has been
+// served: the node has left the ring (or the handoff timed out) and the
+// server is stopping gracefully, after which Start returns nil. The owner
+// of the server should then stop the node and exit.
+func (s *Server) ShutdownRequested() <-chan struct{} {
+	return s.shutdownCh
+}
+
 // Start launches the gRPC server and blocks until it is stopped.
 // This method should typically be invoked in its own goroutine
 // if the caller needs to perform other tasks concurrently.
@@ -82,6 +258,7 @@ func (s *Server) Start() error {
 // This method should be used only for fast shutdowns
 // (e.g., during process termination).
 func (s *Server) Stop() {
+	s.health.Shutdown()
 	s.grpcServer.Stop()
 }
 
@@ -90,7 +267,10 @@ func (s *Server) Stop() {
 // all in-flight requests to complete before shutting down.
 //
 // This is the recommended way to stop the server during normal
-// operation, as it avoids dropping client requests.
+// operation, as it avoids dropping client requests. The health status
+// turns NOT_SERVING first; open health Watch streams keep the server
+// running until their clients close them.
 func (s *Server) GracefulStop() {
+	s.health.Shutdown()
 	s.grpcServer.GracefulStop()
 }
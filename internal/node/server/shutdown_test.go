@@ -0,0 +1,74 @@
+package server_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestShutdownHandsOffResources(t *testing.T) {
+	r := testring.New(t, 4, testring.WithServerOptions(server.WithAdmin()))
+	entry, leaving := r.Members[0], r.Members[2]
+	api, conn, err := client.Connect(entry.Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	const numKeys = 32
+	for i := range numKeys {
+		key := fmt.Sprintf("key-%d", i)
+		if _, err := client.Put(ctx, api, key, "v-"+key); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+
+	adminAPI, adminConn, err := client.Connect(leaving.Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer adminConn.Close()
+	completed, detail, _, err := client.Shutdown(ctx, adminAPI, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if !completed {
+		t.Fatalf("Shutdown: handoff not completed: %s", detail)
+	}
+	r.Kill(leaving)
+	r.WaitStable()
+
+	// nessuna chiave deve andare persa con l'uscita ordinata del nodo
+	for i := range numKeys {
+		key := fmt.Sprintf("key-%d", i)
+		if v, _, err := client.Get(ctx, api, key); err != nil || v != "v-"+key {
+			t.Errorf("Get(%s) after shutdown: got %q, %v", key, v, err)
+		}
+	}
+}
+
+func TestShutdownRequiresAdmin(t *testing.T) {
+	r := testring.New(t, 2)
+	api, conn, err := client.Connect(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, _, _, err := client.Shutdown(ctx, api, time.Second); !errors.Is(err, client.ErrPermissionDenied) {
+		t.Fatalf("Shutdown without admin: got %v, want ErrPermissionDenied", err)
+	}
+	// il nodo continua a servire
+	if _, err := client.Put(ctx, api, "key", "value"); err != nil {
+		t.Errorf("Put after a rejected Shutdown: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package server_test
+
+import (
+	clientv1 "KoordeDHT/internal/api/client/v1"
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestGetStorePage(t *testing.T) {
+	r := testring.New(t, 1)
+	node := r.Members[0].Node
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 25 chiavi "user-*" e 10 "order-*", con ID distinti
+	seen := make(map[string]bool)
+	want := map[string][]string{}
+	for _, p := range []struct {
+		prefix string
+		count  int
+	}{{"user-", 25}, {"order-", 10}} {
+		for i := 0; len(want[p.prefix]) < p.count; i++ {
+			raw := fmt.Sprintf("%s%d", p.prefix, i)
+			id := r.Space.NewIdFromString(raw)
+			if seen[id.ToHexString(false)] {
+				continue
+			}
+			seen[id.ToHexString(false)] = true
+			if err := node.Put(ctx, domain.Resource{Key: id, RawKey: raw, Value: raw}); err != nil {
+				t.Fatalf("Put %s: %v", raw, err)
+			}
+			want[p.prefix] = append(want[p.prefix], raw)
+		}
+	}
+	want[""] = append(append([]string{}, want["user-"]...), want["order-"]...)
+
+	api, conn, err := client.Connect(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+
+	tests := []struct {
+		name      string
+		prefix    string
+		limit     int
+		wantPages int
+	}{
+		{name: "all keys in pages of 10", limit: 10, wantPages: 4},
+		{name: "prefix filter", prefix: "user-", limit: 10, wantPages: 3},
+		{name: "exact last page", prefix: "order-", limit: 5, wantPages: 2},
+		{name: "default limit", prefix: "user-", wantPages: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []*clientv1.Resource
+			after, pages := "", 0
+			for {
+				page, next, _, err := client.GetStorePage(ctx, api, tt.prefix, after, tt.limit)
+				if err != nil {
+					t.Fatalf("GetStorePage after %q: %v", after, err)
+				}
+				pages++
+				if tt.limit > 0 && len(page) > tt.limit {
+					t.Fatalf("page %d: %d resources, limit %d", pages, len(page), tt.limit)
+				}
+				got = append(got, page...)
+				if next == "" {
+					break
+				}
+				if pages > 100 {
+					t.Fatal("pagination never ends")
+				}
+				after = next
+			}
+			if pages != tt.wantPages {
+				t.Errorf("pages = %d, want %d", pages, tt.wantPages)
+			}
+
+			// Ogni chiave una sola volta, in ordine di id
+			ids := make([]string, len(got))
+			keys := make([]string, len(got))
+			for i, res := range got {
+				ids[i] = r.Space.NewIdFromString(res.Key).ToHexString(false)
+				keys[i] = res.Key
+			}
+			if !sort.StringsAreSorted(ids) {
+				t.Errorf("resources not ordered by id: %v", keys)
+			}
+			sort.Strings(keys)
+			exp := append([]string{}, want[tt.prefix]...)
+			sort.Strings(exp)
+			if fmt.Sprint(keys) != fmt.Sprint(exp) {
+				t.Errorf("keys = %v, want %v", keys, exp)
+			}
+		})
+	}
+}
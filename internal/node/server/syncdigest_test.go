@@ -0,0 +1,82 @@
+package server_test
+
+import (
+	dhtv1 "KoordeDHT/internal/api/dht/v1"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/storage"
+	"KoordeDHT/internal/node/testring"
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestSyncDigest(t *testing.T) {
+	r := testring.New(t, 1)
+	m := r.Members[0]
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var stored []domain.Resource
+	for i := 0; i < 4; i++ {
+		raw := fmt.Sprintf("sync-%d", i)
+		res := domain.Resource{Key: r.Space.NewIdFromString(raw), RawKey: raw, Value: raw}
+		if err := m.Node.StoreLocal(ctx, res); err != nil {
+			t.Fatalf("StoreLocal %s: %v", raw, err)
+		}
+		stored = append(stored, res)
+	}
+
+	conn, err := grpc.NewClient(m.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	dht := dhtv1.NewDHTClient(conn)
+
+	self := m.Node.Self().ID
+	const depth = 3
+	want := storage.NewMerkleTree(stored, self, self, r.Space.Bits, depth)
+
+	tests := []struct {
+		name     string
+		req      *dhtv1.SyncDigestRequest
+		wantCode codes.Code
+	}{
+		{name: "root", req: &dhtv1.SyncDigestRequest{From: self, To: self, Depth: depth, Indices: []uint32{0}}},
+		{name: "leaves", req: &dhtv1.SyncDigestRequest{From: self, To: self, Depth: depth, Level: depth, Indices: []uint32{0, 1, 2, 3, 4, 5, 6, 7}}},
+		{name: "entries", req: &dhtv1.SyncDigestRequest{From: self, To: self, Depth: depth, Indices: []uint32{0, 1, 2, 3, 4, 5, 6, 7}, Entries: true}},
+		{name: "missing bounds", req: &dhtv1.SyncDigestRequest{Depth: depth}, wantCode: codes.InvalidArgument},
+		{name: "depth too large", req: &dhtv1.SyncDigestRequest{From: self, To: self, Depth: storage.MaxMerkleDepth + 1}, wantCode: codes.InvalidArgument},
+		{name: "level beyond depth", req: &dhtv1.SyncDigestRequest{From: self, To: self, Depth: depth, Level: depth + 1}, wantCode: codes.InvalidArgument},
+		{name: "index out of range", req: &dhtv1.SyncDigestRequest{From: self, To: self, Depth: depth, Level: 1, Indices: []uint32{2}}, wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := dht.SyncDigest(ctx, tt.req)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("code = %v (%v), want %v", got, err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if tt.req.Entries {
+				if len(resp.Entries) != len(stored) {
+					t.Errorf("got %d entries, want %d", len(resp.Entries), len(stored))
+				}
+				return
+			}
+			for i, idx := range tt.req.Indices {
+				if !bytes.Equal(resp.Hashes[i], want.Hash(int(tt.req.Level), int(idx))) {
+					t.Errorf("hash of node %d at level %d differs from the local tree", idx, tt.req.Level)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,507 @@
+package storage
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// resourcesBucket is the BoltDB bucket holding the resources, keyed by
+// the raw bytes of their ID (big-endian, so keys are ordered as the ring).
+var resourcesBucket = []byte("resources")
+
+// boltRecord is the value stored for each resource.
+type boltRecord struct {
+	RawKey  string            `json:"rawKey"`
+	Value   string            `json:"value"`
+	Expiry  int64             `json:"expiry,omitempty"`  // unix nanoseconds, 0 = never expires
+	Origin  []byte            `json:"origin,omitempty"`  // ID of the node that accepted the Put
+	Version uint64            `json:"version,omitempty"` // version of the write (see domain.Resource.Version)
+	Meta    map[string]string `json:"meta,omitempty"`    // application metadata (see domain.Resource.Metadata)
+	Sum     *uint32           `json:"sum,omitempty"`     // CRC32 (nil = stored without checksum)
+}
+
+// BoltStorage is a Store persisted to a single BoltDB file, so that a node
+// keeps its resources across restarts. It supports the same options as the
+// in-memory Storage (checksums, quota, maximum number of keys) and
+// resource expiry.
+//
+// Put has no error result (see Store): a failed write is logged at ERROR
+// level and the resource is left as it was.
+type BoltStorage struct {
+	lgr      logger.Logger
+	db       *bolt.DB
+	checksum bool
+	quota    int64
+	now      func() time.Time
+
+	mu   sync.Mutex // serializes writes, so that used and keys match the committed data
+	used int64      // bytes currently stored (see domain.Resource.Size)
+	keys int        // resources currently stored
+
+	maxKeys int          // maximum number of resources (0 = unlimited, see WithMaxKeys)
+	lru     *accessOrder // access order of the keys, by raw ID (nil unless the policy is EvictLRU)
+}
+
+// OpenBolt opens (or creates) the BoltDB file at path and returns a
+// BoltStorage backed by it. The file is locked: opening it from a second
+// process fails after one second. The returned storage must be closed.
+func OpenBolt(path string, lgr logger.Logger, opts ...Option) (*BoltStorage, error) {
+	o := newOptions(opts)
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("storage: open bolt file %s: %w", path, err)
+	}
+	b := &BoltStorage{lgr: lgr, db: db, checksum: o.checksum, quota: o.quota, now: time.Now,
+		maxKeys: o.maxKeys, lru: o.accessOrder()}
+	err = db.Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(resourcesBucket)
+		if err != nil {
+			return err
+		}
+		return bkt.ForEach(func(k, v []byte) error {
+			res, _, err := decodeRecord(k, v)
+			if err != nil {
+				return err
+			}
+			b.used += res.Size()
+			b.keys++
+			b.lru.touch(string(k)) // the order of the previous run is not persisted
+			return nil
+		})
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("storage: load bolt file %s: %w", path, err)
+	}
+	lgr.Info("Storage: bolt file opened", logger.F("path", path), logger.F("bytes", b.used), logger.F("keys", b.keys))
+	return b, nil
+}
+
+// Close closes the BoltDB file.
+func (b *BoltStorage) Close() error {
+	return b.db.Close()
+}
+
+func (b *BoltStorage) encodeRecord(res domain.Resource) ([]byte, error) {
+	rec := boltRecord{RawKey: res.RawKey, Value: res.Value, Origin: res.Origin, Version: res.Version, Meta: res.Metadata}
+	if !res.Expiry.IsZero() {
+		rec.Expiry = res.Expiry.UnixNano()
+	}
+	if b.checksum {
+		sum := checksum(res)
+		rec.Sum = &sum
+	}
+	return json.Marshal(rec)
+}
+
+// decodeRecord rebuilds the resource stored under key k. The returned
+// record carries the stored checksum, if any.
+func decodeRecord(k, v []byte) (domain.Resource, boltRecord, error) {
+	var rec boltRecord
+	if err := json.Unmarshal(v, &rec); err != nil {
+		return domain.Resource{}, rec, fmt.Errorf("decode resource %x: %w", k, err)
+	}
+	res := domain.Resource{Key: domain.ID(bytes.Clone(k)), RawKey: rec.RawKey, Value: rec.Value, Origin: rec.Origin, Version: rec.Version, Metadata: rec.Meta}
+	if rec.Expiry != 0 {
+		res.Expiry = time.Unix(0, rec.Expiry)
+	}
+	return res, rec, nil
+}
+
+// Put inserts or updates the given copy of a resource, unless a newer
+// version of it is stored (see Store.Put).
+func (b *BoltStorage) Put(resource domain.Resource) {
+	if _, err := b.put(resource, false, domain.Condition{}); err != nil {
+		b.lgr.Error("Put: failed to write resource", logger.F("rawKey", resource.RawKey), logger.F("err", err))
+	}
+}
+
+// TryPut is PutConditional with the zero Condition.
+func (b *BoltStorage) TryPut(resource domain.Resource) error {
+	_, err := b.put(resource, true, domain.Condition{})
+	return err
+}
+
+// PutConditional stores the resource only if cond holds for the one
+// currently stored under its key (expired resources count as absent), and
+// returns domain.ErrPreconditionFailed otherwise, or
+// domain.ErrStorageFull if storing it would exceed the quota; overwrites
+// are charged only for the bytes they add. See Store.PutConditional for
+// the version of the stored resource.
+func (b *BoltStorage) PutConditional(resource domain.Resource, cond domain.Condition) (uint64, error) {
+	return b.put(resource, true, cond)
+}
+
+// put stores resource, unless it is a copy older than the stored one or,
+// with a non-zero cond, cond does not hold, and returns the version stored
+// under its key. enforceQuota is set for TryPut and PutConditional, and
+// enforces the EvictReject policy of the maximum number of keys as well;
+// the EvictLRU one applies to every write. Without it, resource is a copy
+// (see Store.Put).
+func (b *BoltStorage) put(resource domain.Resource, enforceQuota bool, cond domain.Condition) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var (
+		delta   int64
+		added   bool             // resource is stored under a new key
+		evicted []string         // keys evicted by the EvictLRU policy
+		dropped []string         // keys tracked by the EvictLRU policy but no longer stored
+		ignored *domain.Resource // newer resource that made Put a no-op
+	)
+	newWrite := enforceQuota && supersedes(resource, cond)
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(resourcesBucket)
+		delta = resource.Size()
+		var cur *domain.Resource
+		old := bkt.Get(resource.Key)
+		added = old == nil
+		if old != nil {
+			if prev, _, err := decodeRecord(resource.Key, old); err == nil {
+				delta -= prev.Size()
+				if !prev.Expired(b.now()) {
+					cur = &prev
+				}
+			}
+		}
+		if !newWrite && stale(resource, cur) {
+			ignored = cur
+			return nil
+		}
+		if !cond.Holds(cur) {
+			return domain.ErrPreconditionFailed
+		}
+		if enforceQuota && b.quota > 0 && delta > 0 && b.used+delta > b.quota {
+			return domain.ErrStorageFull
+		}
+		if added && b.maxKeys > 0 && b.keys >= b.maxKeys {
+			if b.lru == nil {
+				if enforceQuota {
+					return errMaxKeys
+				}
+			} else {
+				var err error
+				if evicted, dropped, err = b.evictLRU(bkt, resource.Key, &delta); err != nil {
+					return err
+				}
+			}
+		}
+		if newWrite {
+			resource.Version = supersede(resource, cur, b.now())
+		}
+		val, err := b.encodeRecord(resource)
+		if err != nil {
+			return err
+		}
+		return bkt.Put(resource.Key, val)
+	})
+	if err != nil {
+		if errors.Is(err, errMaxKeys) {
+			b.lgr.Warn("Put: maximum number of keys reached", logger.F("rawKey", resource.RawKey),
+				logger.F("maxKeys", b.maxKeys))
+			return 0, domain.ErrStorageFull
+		}
+		if errors.Is(err, domain.ErrStorageFull) {
+			b.lgr.Warn("Put: storage quota exceeded", logger.F("rawKey", resource.RawKey),
+				logger.F("size", resource.Size()), logger.F("used", b.used), logger.F("quota", b.quota))
+		}
+		if errors.Is(err, domain.ErrPreconditionFailed) {
+			b.lgr.Debug("Put: precondition not met", logger.F("rawKey", resource.RawKey))
+		}
+		return 0, err
+	}
+	if ignored != nil {
+		b.lgr.Debug("Put: older version ignored", logger.F("rawKey", resource.RawKey),
+			logger.F("version", resource.Version), logger.F("stored", ignored.Version))
+		return ignored.Version, nil
+	}
+	b.used += delta
+	if added {
+		b.keys++
+	}
+	b.keys -= len(evicted)
+	for _, k := range dropped {
+		b.lru.forget(k)
+	}
+	for _, k := range evicted {
+		b.lru.forget(k)
+		b.lgr.Debug("Put: least recently used resource evicted", logger.F("key", domain.ID(k).ToHexString(false)))
+	}
+	b.lru.touch(string(resource.Key))
+	b.lgr.Debug("Put: resource stored", logger.FResource("resource", resource))
+	return resource.Version, nil
+}
+
+// errMaxKeys tells a TryPut rejected by the EvictReject policy from one
+// rejected by the quota, for logging; callers get domain.ErrStorageFull.
+var errMaxKeys = errors.New("maximum number of keys reached")
+
+// evictLRU deletes from bkt the least recently used resources other than
+// keep until a new key fits in the maximum number of keys, subtracting
+// their size from delta. It returns the keys it went through: the evicted
+// ones and those no longer stored (recorded by a Get racing with a
+// delete), which the caller forgets once the transaction is committed. It
+// runs in the write transaction of put, with b.mu held.
+func (b *BoltStorage) evictLRU(bkt *bolt.Bucket, keep []byte, delta *int64) (evicted, dropped []string, err error) {
+	skip := func(k string) bool {
+		return k == string(keep) || slices.Contains(evicted, k) || slices.Contains(dropped, k)
+	}
+	for b.keys-len(evicted) >= b.maxKeys {
+		victim, ok := b.lru.oldest(skip)
+		if !ok {
+			break
+		}
+		v := bkt.Get([]byte(victim))
+		if v == nil {
+			dropped = append(dropped, victim)
+			continue
+		}
+		if res, _, err := decodeRecord([]byte(victim), v); err == nil {
+			*delta -= res.Size()
+		}
+		if err := bkt.Delete([]byte(victim)); err != nil {
+			return nil, nil, err
+		}
+		evicted = append(evicted, victim)
+	}
+	return evicted, dropped, nil
+}
+
+// Get retrieves the resource with the given ID. Missing and expired
+// resources are reported as ErrResourceNotFound (expired ones are deleted
+// on the way), resources failing their checksum as ErrResourceCorrupted.
+func (b *BoltStorage) Get(id domain.ID) (domain.Resource, error) {
+	var (
+		res   domain.Resource
+		rec   boltRecord
+		found bool
+	)
+	err := b.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(resourcesBucket).Get(id)
+		if v == nil {
+			return nil
+		}
+		found = true
+		var err error
+		res, rec, err = decodeRecord(id, v)
+		return err
+	})
+	if err != nil {
+		b.lgr.Error("Get: failed to read resource", logger.F("key", id.ToHexString(false)), logger.F("err", err))
+		return domain.Resource{}, domain.ErrResourceCorrupted
+	}
+	if !found {
+		return domain.Resource{}, domain.ErrResourceNotFound
+	}
+	if res.Expired(b.now()) {
+		b.deleteExpired(id)
+		return domain.Resource{}, domain.ErrResourceNotFound
+	}
+	if rec.Sum != nil && checksum(res) != *rec.Sum {
+		b.lgr.Error("Get: checksum mismatch, resource corrupted",
+			logger.F("key", id.ToHexString(false)), logger.F("rawKey", res.RawKey))
+		return domain.Resource{}, domain.ErrResourceCorrupted
+	}
+	b.lru.touch(string(id))
+	return res, nil
+}
+
+// Delete removes the resource with the given ID, or returns
+// ErrResourceNotFound if it is not present.
+func (b *BoltStorage) Delete(id domain.ID) error {
+	deleted, err := b.remove(id, func(domain.Resource) bool { return true })
+	if err != nil {
+		b.lgr.Error("Delete: failed to delete resource", logger.F("key", id.ToHexString(false)), logger.F("err", err))
+		return err
+	}
+	if !deleted {
+		return domain.ErrResourceNotFound
+	}
+	b.lgr.Debug("Storage: resource deleted", logger.F("key", id.ToHexString(false)))
+	return nil
+}
+
+// deleteExpired removes the resource stored under id if it is (still)
+// expired, so that a concurrent Put of a fresh value is not lost.
+func (b *BoltStorage) deleteExpired(id domain.ID) bool {
+	now := b.now()
+	deleted, err := b.remove(id, func(res domain.Resource) bool { return res.Expired(now) })
+	if err != nil {
+		b.lgr.Error("Storage: failed to evict expired resource", logger.F("key", id.ToHexString(false)), logger.F("err", err))
+	}
+	return deleted
+}
+
+// remove deletes the resource stored under id if cond holds for it, and
+// reports whether it was deleted.
+func (b *BoltStorage) remove(id domain.ID, cond func(domain.Resource) bool) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var size int64
+	deleted := false
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(resourcesBucket)
+		v := bkt.Get(id)
+		if v == nil {
+			return nil
+		}
+		res, _, err := decodeRecord(id, v)
+		if err == nil && !cond(res) {
+			return nil
+		}
+		size = res.Size()
+		deleted = true
+		return bkt.Delete(id)
+	})
+	if err != nil {
+		return false, err
+	}
+	if deleted {
+		b.used -= size
+		b.keys--
+		b.lru.forget(string(id))
+	}
+	return deleted, nil
+}
+
+// scan calls fn on every non-expired resource with key in [from, to]
+// (nil bounds are open), in key order. The expired resources met on the
+// way are evicted once the scan is over.
+func (b *BoltStorage) scan(from, to []byte, fn func(domain.Resource)) {
+	now := b.now()
+	var expired []domain.ID
+	defer func() {
+		for _, id := range expired {
+			b.deleteExpired(id)
+		}
+	}()
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(resourcesBucket).Cursor()
+		k, v := c.First()
+		if from != nil {
+			k, v = c.Seek(from)
+		}
+		for ; k != nil && (to == nil || bytes.Compare(k, to) <= 0); k, v = c.Next() {
+			res, _, err := decodeRecord(k, v)
+			if err != nil {
+				b.lgr.Warn("Storage: skipping undecodable resource", logger.F("err", err))
+				continue
+			}
+			if res.Expired(now) {
+				expired = append(expired, res.Key)
+				continue
+			}
+			fn(res)
+		}
+		return nil
+	})
+	if err != nil {
+		b.lgr.Error("Storage: scan failed", logger.F("err", err))
+	}
+}
+
+// Between returns all resources with IDs k such that k ∈ (from, to] on the
+// ring, expired ones excluded (and evicted). Keys are scanned in order: (from, to] when
+// from < to, otherwise (from, max] followed by [0, to] (the wrap-around
+// case, or the whole ring when from == to). domain.ID.Between is applied
+// on top of the scan, so the semantics match the in-memory Storage.
+func (b *BoltStorage) Between(from, to domain.ID) []domain.Resource {
+	var result []domain.Resource
+	collect := func(res domain.Resource) {
+		if res.Key.Between(from, to) {
+			result = append(result, res)
+		}
+	}
+	switch from.Cmp(to) {
+	case -1:
+		b.scan(from, to, collect)
+		return result
+	case 0:
+		b.scan(nil, nil, collect) // whole ring: a single pass, or from would be listed twice
+		return result
+	}
+	b.scan(from, nil, collect)
+	b.scan(nil, to, collect)
+	return result
+}
+
+// BetweenMulti returns the resources with IDs in each of the intervals,
+// keyed by the index of the interval, with the semantics of the in-memory
+// Storage: the whole bucket is scanned once and every resource is assigned
+// to all the intervals containing it.
+func (b *BoltStorage) BetweenMulti(intervals []Interval) map[int][]domain.Resource {
+	buckets := make(map[int][]domain.Resource, len(intervals))
+	if len(intervals) == 0 {
+		return buckets
+	}
+	b.scan(nil, nil, func(res domain.Resource) { bucket(buckets, intervals, res) })
+	return buckets
+}
+
+// All returns a snapshot of all resources currently stored, expired ones
+// excluded (and evicted).
+func (b *BoltStorage) All() []domain.Resource {
+	var result []domain.Resource
+	b.scan(nil, nil, func(res domain.Resource) { result = append(result, res) })
+	return result
+}
+
+// Len returns the number of resources stored, expired ones excluded.
+func (b *BoltStorage) Len() int {
+	n := 0
+	b.scan(nil, nil, func(domain.Resource) { n++ })
+	return n
+}
+
+// MaxKeys returns the maximum number of resources (see WithMaxKeys), 0 if
+// unlimited.
+func (b *BoltStorage) MaxKeys() int {
+	return b.maxKeys
+}
+
+// Used returns the bytes currently stored (see domain.Resource.Size),
+// expired resources not yet evicted included.
+func (b *BoltStorage) Used() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// Sweep evicts every expired resource and returns how many were removed.
+func (b *BoltStorage) Sweep() int {
+	now := b.now()
+	var expired []domain.ID
+	_ = b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(resourcesBucket).ForEach(func(k, v []byte) error {
+			if res, _, err := decodeRecord(k, v); err == nil && res.Expired(now) {
+				expired = append(expired, res.Key)
+			}
+			return nil
+		})
+	})
+	removed := 0
+	for _, id := range expired {
+		if b.deleteExpired(id) {
+			removed++
+		}
+	}
+	if removed > 0 {
+		b.lgr.Debug("Sweep: expired resources evicted", logger.F("count", removed))
+	}
+	return removed
+}
+
+// StartSweeper runs Sweep every interval in a background goroutine until
+// ctx is canceled. A non-positive interval disables the sweeper.
+func (b *BoltStorage) StartSweeper(ctx context.Context, interval time.Duration) {
+	startSweeper(ctx, interval, b.Sweep)
+}
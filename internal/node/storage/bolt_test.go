@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func openBolt(t *testing.T, path string, opts ...Option) *BoltStorage {
+	t.Helper()
+	b, err := OpenBolt(path, &logger.NopLogger{}, opts...)
+	if err != nil {
+		t.Fatalf("OpenBolt: %v", err)
+	}
+	return b
+}
+
+func TestBoltPersistsAcrossReopen(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "store.db")
+
+	b := openBolt(t, path, WithChecksum(true))
+	kept := domain.Resource{Key: sp.NewIdFromString("kept"), RawKey: "kept", Value: "v1", Origin: sp.NewIdFromString("origin")}
+	gone := domain.Resource{Key: sp.NewIdFromString("gone"), RawKey: "gone", Value: "v2"}
+	ttl := domain.Resource{Key: sp.NewIdFromString("ttl"), RawKey: "ttl", Value: "v3"}.WithTTL(time.Now(), time.Hour)
+	b.Put(kept)
+	b.Put(gone)
+	b.Put(ttl)
+	if err := b.Delete(gone.Key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	used := b.Used()
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Dopo la riapertura il contenuto e i byte occupati sono invariati
+	b = openBolt(t, path, WithChecksum(true))
+	defer b.Close()
+	if got := b.Used(); got != used {
+		t.Fatalf("Used after reopen = %d, want %d", got, used)
+	}
+	tests := []struct {
+		res     domain.Resource
+		wantErr error
+	}{
+		{res: kept},
+		{res: ttl},
+		{res: gone, wantErr: domain.ErrResourceNotFound},
+	}
+	for _, tt := range tests {
+		got, err := b.Get(tt.res.Key)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("Get(%s): got %v, want %v", tt.res.RawKey, err, tt.wantErr)
+		}
+		if err == nil && (got.Value != tt.res.Value || !got.Expiry.Equal(tt.res.Expiry) || !got.Origin.Equal(tt.res.Origin)) {
+			t.Fatalf("Get(%s): got %+v, want %+v", tt.res.RawKey, got, tt.res)
+		}
+	}
+
+	// La scadenza è rispettata anche sui dati persistiti
+	b.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err := b.Get(ttl.Key); !errors.Is(err, domain.ErrResourceNotFound) {
+		t.Fatalf("Get(ttl) after expiry: got %v, want %v", err, domain.ErrResourceNotFound)
+	}
+	if got := b.Len(); got != 1 {
+		t.Fatalf("Len = %d, want 1", got)
+	}
+}
+
+func TestBoltBetweenMatchesMemory(t *testing.T) {
+	sp, err := domain.NewSpace(8, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	b := openBolt(t, filepath.Join(t.TempDir(), "store.db"))
+	defer b.Close()
+	m := NewMemoryStorage(&logger.NopLogger{})
+	for i := 0; i < 64; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		res := domain.Resource{Key: sp.NewIdFromString(key), RawKey: key, Value: key}
+		b.Put(res)
+		m.Put(res)
+	}
+
+	id := func(v uint64) domain.ID { return sp.FromUint64(v) }
+	tests := []struct {
+		name     string
+		from, to domain.ID
+	}{
+		{name: "linear", from: id(10), to: id(200)},
+		{name: "wrap-around", from: id(200), to: id(10)},
+		{name: "whole ring", from: id(77), to: id(77)},
+		{name: "single point", from: id(0), to: id(1)},
+		{name: "up to max", from: id(128), to: id(255)},
+	}
+	keys := func(rs []domain.Resource) []string {
+		out := make([]string, 0, len(rs))
+		for _, r := range rs {
+			out = append(out, r.Key.ToHexString(false))
+		}
+		sort.Strings(out)
+		return out
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, want := keys(b.Between(tt.from, tt.to)), keys(m.Between(tt.from, tt.to))
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Fatalf("Between: got %v, want %v", got, want)
+			}
+		})
+	}
+}
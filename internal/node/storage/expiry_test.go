@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExpiredResources(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	now := time.Unix(1_000_000, 0)
+	newRes := func(raw string, ttl time.Duration) domain.Resource {
+		return domain.Resource{Key: sp.NewIdFromString(raw), RawKey: raw, Value: "v-" + raw}.WithTTL(now, ttl)
+	}
+
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		elapsed time.Duration
+		expired bool
+	}{
+		{name: "no ttl", ttl: 0, elapsed: 24 * time.Hour, expired: false},
+		{name: "ttl not reached", ttl: time.Minute, elapsed: 59 * time.Second, expired: false},
+		{name: "ttl reached", ttl: time.Minute, elapsed: time.Minute, expired: true},
+		{name: "ttl exceeded", ttl: time.Second, elapsed: time.Hour, expired: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := newRes("k", tt.ttl)
+			clock := now
+
+			// Get: le risorse scadute risultano assenti e vengono rimosse subito
+			s := NewMemoryStorage(&logger.NopLogger{})
+			s.now = func() time.Time { return clock }
+			s.Put(res)
+			clock = now.Add(tt.elapsed)
+			_, err := s.Get(res.Key)
+			if got := errors.Is(err, domain.ErrResourceNotFound); got != tt.expired {
+				t.Fatalf("Get: got %v, want expired=%v", err, tt.expired)
+			}
+			if _, stored := s.data[res.Key.ToHexString(false)]; stored == tt.expired {
+				t.Errorf("resource still stored = %v after Get, want %v", stored, !tt.expired)
+			}
+			if got := len(s.All()); got != len(s.data) {
+				t.Errorf("All returned %d resources, want %d", got, len(s.data))
+			}
+
+			// Sweep: rimuove solo le risorse scadute
+			clock = now
+			s = NewMemoryStorage(&logger.NopLogger{})
+			s.now = func() time.Time { return clock }
+			s.Put(res)
+			s.Put(newRes("permanent", 0))
+			clock = now.Add(tt.elapsed)
+			live := 2 - boolToInt(tt.expired)
+			// prima di Between e All, che rimuovono a loro volta le risorse scadute
+			if removed := s.Sweep(); removed != boolToInt(tt.expired) {
+				t.Errorf("Sweep removed %d resources, want %d", removed, boolToInt(tt.expired))
+			}
+			// (k, k] copre l'intero anello
+			if got := len(s.Between(res.Key, res.Key)); got != live {
+				t.Errorf("Between returned %d resources, want %d", got, live)
+			}
+			if got := len(s.All()); got != live {
+				t.Errorf("All returned %d resources, want %d", got, live)
+			}
+			if _, err := s.Get(sp.NewIdFromString("permanent")); err != nil {
+				t.Errorf("permanent resource lost: %v", err)
+			}
+		})
+	}
+}
+
+func TestTransferEvictsExpired(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	now := time.Unix(1_000_000, 0)
+	clock := now
+	tick := func() time.Time { return clock }
+	live := domain.Resource{Key: sp.NewIdFromString("live"), RawKey: "live", Value: "v"}
+	stale := domain.Resource{Key: sp.NewIdFromString("stale"), RawKey: "stale", Value: "v"}.WithTTL(now, time.Second)
+
+	tests := []struct {
+		name string
+		open func(t *testing.T) Store
+	}{
+		{name: "memory", open: func(t *testing.T) Store {
+			s := NewMemoryStorage(&logger.NopLogger{})
+			s.now = tick
+			return s
+		}},
+		{name: "bolt", open: func(t *testing.T) Store {
+			b := openBolt(t, filepath.Join(t.TempDir(), "store.db"))
+			t.Cleanup(func() { _ = b.Close() })
+			b.now = tick
+			return b
+		}},
+	}
+	reads := []struct {
+		name string
+		read func(s Store) []domain.Resource
+	}{
+		// (k, k] copre l'intero anello
+		{name: "Between", read: func(s Store) []domain.Resource { return s.Between(live.Key, live.Key) }},
+		{name: "All", read: func(s Store) []domain.Resource { return s.All() }},
+	}
+	for _, tt := range tests {
+		for _, rd := range reads {
+			t.Run(tt.name+"/"+rd.name, func(t *testing.T) {
+				clock = now
+				s := tt.open(t)
+				s.Put(live)
+				s.Put(stale)
+				clock = now.Add(time.Minute)
+
+				// la risorsa scaduta non viene trasferita e lascia lo store
+				got := rd.read(s)
+				if len(got) != 1 || got[0].RawKey != "live" {
+					t.Fatalf("%s returned %v, want only the live resource", rd.name, got)
+				}
+				if used, want := s.(interface{ Used() int64 }).Used(), live.Size(); used != want {
+					t.Errorf("Used after %s = %d, want %d (expired resource not evicted)", rd.name, used, want)
+				}
+			})
+		}
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"container/list"
+	"sync"
+)
+
+// accessOrder tracks the keys of a store from the most to the least
+// recently read or written, for the EvictLRU policy of WithMaxKeys. It is
+// safe for concurrent use, so that reads holding only a read lock of the
+// store can record their access. The methods of a nil accessOrder are
+// no-ops (policies other than EvictLRU).
+type accessOrder struct {
+	mu    sync.Mutex
+	keys  *list.List               // most recently used first
+	elems map[string]*list.Element // element of each key in keys
+}
+
+func newAccessOrder() *accessOrder {
+	return &accessOrder{keys: list.New(), elems: make(map[string]*list.Element)}
+}
+
+// touch marks key as the most recently used.
+func (a *accessOrder) touch(key string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if e, ok := a.elems[key]; ok {
+		a.keys.MoveToFront(e)
+		return
+	}
+	a.elems[key] = a.keys.PushFront(key)
+}
+
+// forget stops tracking key.
+func (a *accessOrder) forget(key string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if e, ok := a.elems[key]; ok {
+		a.keys.Remove(e)
+		delete(a.elems, key)
+	}
+}
+
+// oldest returns the least recently used key for which skip is false, or
+// false if there is none.
+func (a *accessOrder) oldest(skip func(key string) bool) (string, bool) {
+	if a == nil {
+		return "", false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for e := a.keys.Back(); e != nil; e = e.Prev() {
+		if key := e.Value.(string); !skip(key) {
+			return key, true
+		}
+	}
+	return "", false
+}
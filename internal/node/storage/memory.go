@@ -3,37 +3,205 @@ package storage
 import (
 	"KoordeDHT/internal/domain"
 	"KoordeDHT/internal/logger"
-	"sort"
+	"context"
+	"hash/crc32"
+	"maps"
+	"slices"
 	"sync"
+	"time"
 )
 
-// Storage is an in-memory key-value store that implements the Storage
+// Storage is an in-memory key-value store that implements the Store
 // interface. It is concurrency-safe and intended for local node storage.
 type Storage struct {
 	lgr  logger.Logger
 	mu   sync.RWMutex
 	data map[string]domain.Resource // key is domain.ID.ToHexString(false) (hexadecimal rappresentation of the ID)
+	sums map[string]uint32          // CRC32 of each resource, same keys as data (nil = checksums disabled)
+	now  func() time.Time           // clock used for resource expiry
+
+	quota int64 // maximum bytes accepted by TryPut (0 = unlimited)
+	used  int64 // bytes currently stored (see domain.Resource.Size)
+
+	maxKeys int          // maximum number of resources (0 = unlimited, see WithMaxKeys)
+	lru     *accessOrder // access order of the keys (nil unless the policy is EvictLRU)
 }
 
 // NewMemoryStorage creates and returns a new, empty in-memory storage.
 // This implementation is suitable for unit tests and for nodes that do not
 // require persistence.
-func NewMemoryStorage(lgr logger.Logger) *Storage {
+func NewMemoryStorage(lgr logger.Logger, opts ...Option) *Storage {
+	o := newOptions(opts)
 	s := &Storage{
-		lgr:  lgr,
-		data: make(map[string]domain.Resource),
+		lgr:   lgr,
+		data:  make(map[string]domain.Resource),
+		now:   time.Now,
+		quota: o.quota,
+
+		maxKeys: o.maxKeys,
+		lru:     o.accessOrder(),
+	}
+	if o.checksum {
+		s.sums = make(map[string]uint32)
 	}
 	return s
 }
 
-// Put inserts or updates the given resource in the store.
+// Close is a no-op: the in-memory storage holds no external resources.
+func (s *Storage) Close() error {
+	return nil
+}
+
+// checksum returns the CRC32 of the resource key, raw key, value and
+// metadata (in key order; resources without metadata keep the checksum
+// they had before metadata existed).
+func checksum(r domain.Resource) uint32 {
+	h := crc32.NewIEEE()
+	h.Write(r.Key)
+	h.Write([]byte{0})
+	h.Write([]byte(r.RawKey))
+	h.Write([]byte{0})
+	h.Write([]byte(r.Value))
+	for _, k := range slices.Sorted(maps.Keys(r.Metadata)) {
+		h.Write([]byte{0})
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(r.Metadata[k]))
+	}
+	return h.Sum32()
+}
+
+// Put inserts or updates the given copy of a resource in the store,
+// unless a newer version of it is stored (see Store.Put).
 // The resource is indexed by its ID, serialized as a hexadecimal string.
 func (s *Storage) Put(resource domain.Resource) {
+	s.mu.Lock()
+	if cur := s.current(resource.Key.ToHexString(false)); stale(resource, cur) {
+		s.mu.Unlock()
+		s.lgr.Debug("Put: older version ignored", logger.F("rawKey", resource.RawKey),
+			logger.F("version", resource.Version), logger.F("stored", cur.Version))
+		return
+	}
+	existed := s.put(resource)
+	s.mu.Unlock()
+	s.logPut(resource, existed)
+}
+
+// TryPut is PutConditional with the zero Condition.
+func (s *Storage) TryPut(resource domain.Resource) error {
+	_, err := s.PutConditional(resource, domain.Condition{})
+	return err
+}
+
+// PutConditional stores the resource only if cond holds for the one
+// currently stored under its key (expired resources count as absent), and
+// returns domain.ErrPreconditionFailed otherwise, or
+// domain.ErrStorageFull if storing it would exceed the quota (see
+// WithQuota); overwrites are charged only for the bytes they add. See
+// Store.PutConditional for the version of the stored resource.
+func (s *Storage) PutConditional(resource domain.Resource, cond domain.Condition) (uint64, error) {
 	key := resource.Key.ToHexString(false)
 	s.mu.Lock()
-	_, existed := s.data[key]
-	s.data[key] = resource
+	cur := s.current(key)
+	if !supersedes(resource, cond) && stale(resource, cur) {
+		s.mu.Unlock()
+		s.lgr.Debug("Put: older version ignored", logger.F("rawKey", resource.RawKey),
+			logger.F("version", resource.Version), logger.F("stored", cur.Version))
+		return cur.Version, nil
+	}
+	if !cond.Holds(cur) {
+		s.mu.Unlock()
+		s.lgr.Debug("Put: precondition not met", logger.F("rawKey", resource.RawKey))
+		return 0, domain.ErrPreconditionFailed
+	}
+	if s.quota > 0 {
+		delta := resource.Size()
+		if old, ok := s.data[key]; ok {
+			delta -= old.Size()
+		}
+		if delta > 0 && s.used+delta > s.quota {
+			used := s.used
+			s.mu.Unlock()
+			s.lgr.Warn("Put: storage quota exceeded", logger.F("rawKey", resource.RawKey),
+				logger.F("size", resource.Size()), logger.F("used", used), logger.F("quota", s.quota))
+			return 0, domain.ErrStorageFull
+		}
+	}
+	if s.lru == nil && s.full(key) {
+		s.mu.Unlock()
+		s.lgr.Warn("Put: maximum number of keys reached", logger.F("rawKey", resource.RawKey),
+			logger.F("maxKeys", s.maxKeys))
+		return 0, domain.ErrStorageFull
+	}
+	if supersedes(resource, cond) {
+		resource.Version = supersede(resource, cur, s.now())
+	}
+	existed := s.put(resource)
 	s.mu.Unlock()
+	s.logPut(resource, existed)
+	return resource.Version, nil
+}
+
+// current returns the live resource stored under key, or nil if there is
+// none (expired resources count as absent). It must be called with s.mu
+// held.
+func (s *Storage) current(key string) *domain.Resource {
+	res, ok := s.data[key]
+	if !ok || res.Expired(s.now()) {
+		return nil
+	}
+	return &res
+}
+
+// full reports whether storing a resource under key would exceed the
+// maximum number of keys. It must be called with s.mu held.
+func (s *Storage) full(key string) bool {
+	if s.maxKeys <= 0 {
+		return false
+	}
+	_, ok := s.data[key]
+	return !ok && len(s.data) >= s.maxKeys
+}
+
+// put stores resource and updates the byte usage, evicting the least
+// recently used resources first if the store is full and the policy is
+// EvictLRU. It must be called with s.mu held and reports whether a
+// resource was overwritten.
+func (s *Storage) put(resource domain.Resource) bool {
+	key := resource.Key.ToHexString(false)
+	for s.lru != nil && s.full(key) {
+		victim, ok := s.lru.oldest(func(k string) bool { return k == key })
+		if !ok {
+			break
+		}
+		s.remove(victim)
+		s.lgr.Debug("Put: least recently used resource evicted", logger.F("key", victim))
+	}
+	old, existed := s.data[key]
+	if existed {
+		s.used -= old.Size()
+	}
+	s.data[key] = resource
+	s.used += resource.Size()
+	if s.sums != nil {
+		s.sums[key] = checksum(resource)
+	}
+	s.lru.touch(key)
+	return existed
+}
+
+// remove deletes the resource stored under key and updates the byte usage.
+// It must be called with s.mu held.
+func (s *Storage) remove(key string) {
+	if old, ok := s.data[key]; ok {
+		s.used -= old.Size()
+		delete(s.data, key)
+		delete(s.sums, key)
+	}
+	s.lru.forget(key)
+}
+
+func (s *Storage) logPut(resource domain.Resource, existed bool) {
 	if existed {
 		s.lgr.Debug("Put: resource updated", logger.FResource("resource", resource))
 	} else {
@@ -42,13 +210,30 @@ func (s *Storage) Put(resource domain.Resource) {
 }
 
 // Get retrieves the resource with the given ID.
-// If the key is not present, it returns ErrResourceNotFound.
+// If the key is not present, or the resource has expired, it returns
+// ErrResourceNotFound (expired resources are deleted on the way).
+// If checksums are enabled and the resource does not match its checksum,
+// it returns ErrResourceCorrupted.
 func (s *Storage) Get(id domain.ID) (domain.Resource, error) {
 	key := id.ToHexString(false)
 
 	s.mu.RLock()
-	res, _ := s.data[key]
+	res, ok := s.data[key]
+	sum, hasSum := s.sums[key]
 	s.mu.RUnlock()
+	if !ok {
+		return domain.Resource{}, domain.ErrResourceNotFound
+	}
+	if res.Expired(s.now()) {
+		s.deleteExpired(key)
+		return domain.Resource{}, domain.ErrResourceNotFound
+	}
+	if hasSum && checksum(res) != sum {
+		s.lgr.Error("Get: checksum mismatch, resource corrupted",
+			logger.F("key", key), logger.F("rawKey", res.RawKey))
+		return domain.Resource{}, domain.ErrResourceCorrupted
+	}
+	s.lru.touch(key)
 	return res, nil
 }
 
@@ -58,9 +243,7 @@ func (s *Storage) Delete(id domain.ID) error {
 	key := id.ToHexString(false)
 	s.mu.Lock()
 	_, ok := s.data[key]
-	if ok {
-		delete(s.data, key)
-	}
+	s.remove(key)
 	s.mu.Unlock()
 	if !ok {
 		s.lgr.Debug("Storage: delete failed, resource not found", logger.F("key", key))
@@ -70,32 +253,157 @@ func (s *Storage) Delete(id domain.ID) error {
 	return nil
 }
 
+// deleteExpired removes the resource stored under key if it is (still)
+// expired, so that a concurrent Put of a fresh value is not lost.
+func (s *Storage) deleteExpired(key string) bool {
+	s.mu.Lock()
+	res, ok := s.data[key]
+	expired := ok && res.Expired(s.now())
+	if expired {
+		s.remove(key)
+	}
+	s.mu.Unlock()
+	if expired {
+		s.lgr.Debug("Storage: expired resource evicted", logger.F("key", key), logger.F("rawKey", res.RawKey))
+	}
+	return expired
+}
+
+// Sweep evicts every expired resource and returns how many were removed.
+func (s *Storage) Sweep() int {
+	now := s.now()
+	s.mu.RLock()
+	var expired []string
+	for key, res := range s.data {
+		if res.Expired(now) {
+			expired = append(expired, key)
+		}
+	}
+	s.mu.RUnlock()
+
+	removed := 0
+	for _, key := range expired {
+		if s.deleteExpired(key) {
+			removed++
+		}
+	}
+	if removed > 0 {
+		s.lgr.Debug("Sweep: expired resources evicted", logger.F("count", removed))
+	}
+	return removed
+}
+
+// StartSweeper runs Sweep every interval in a background goroutine until
+// ctx is canceled. A non-positive interval disables the sweeper (expired
+// resources are then only evicted lazily, when read).
+func (s *Storage) StartSweeper(ctx context.Context, interval time.Duration) {
+	startSweeper(ctx, interval, s.Sweep)
+}
+
 // Between returns all resources with IDs k such that k ∈ (from, to] on the ring.
 // The wrap-around case (from > to) is correctly handled by domain.ID.Between.
+// Expired resources are skipped and evicted on the way, so that they are
+// never transferred to another node.
 func (s *Storage) Between(from, to domain.ID) []domain.Resource {
+	now := s.now()
 	s.mu.RLock()
 	var result []domain.Resource
-	for _, res := range s.data {
-		if res.Key.Between(from, to) {
-			result = append(result, res)
+	var expired []string
+	for key, res := range s.data {
+		if !res.Key.Between(from, to) {
+			continue
+		}
+		if res.Expired(now) {
+			expired = append(expired, key)
+			continue
 		}
+		result = append(result, res)
 	}
 	s.mu.RUnlock()
+	s.evict(expired)
 	return result
 }
 
-// All returns a snapshot of all resources currently stored.
-// The slice is a copy and modifications to it do not affect the storage.
+// BetweenMulti returns the resources with IDs in each of the intervals,
+// keyed by the index of the interval; intervals without resources are
+// absent from the map. The storage is scanned once, and a resource in
+// several (overlapping) intervals is listed in each of them. Expired
+// resources are skipped and evicted as in Between.
+func (s *Storage) BetweenMulti(intervals []Interval) map[int][]domain.Resource {
+	now := s.now()
+	buckets := make(map[int][]domain.Resource, len(intervals))
+	if len(intervals) == 0 {
+		return buckets
+	}
+	s.mu.RLock()
+	var expired []string
+	for key, res := range s.data {
+		if res.Expired(now) {
+			expired = append(expired, key)
+			continue
+		}
+		bucket(buckets, intervals, res)
+	}
+	s.mu.RUnlock()
+	s.evict(expired)
+	return buckets
+}
+
+// All returns a snapshot of all resources currently stored, expired ones
+// excluded (and evicted on the way). The slice is a copy and modifications
+// to it do not affect the storage.
 func (s *Storage) All() []domain.Resource {
+	now := s.now()
 	s.mu.RLock()
 	result := make([]domain.Resource, 0, len(s.data))
-	for _, res := range s.data {
+	var expired []string
+	for key, res := range s.data {
+		if res.Expired(now) {
+			expired = append(expired, key)
+			continue
+		}
 		result = append(result, res)
 	}
 	s.mu.RUnlock()
+	s.evict(expired)
 	return result
 }
 
+// evict removes the expired resources found by a read (see deleteExpired).
+func (s *Storage) evict(keys []string) {
+	for _, key := range keys {
+		s.deleteExpired(key)
+	}
+}
+
+// Len returns the number of resources stored, expired ones excluded.
+func (s *Storage) Len() int {
+	now := s.now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, res := range s.data {
+		if !res.Expired(now) {
+			n++
+		}
+	}
+	return n
+}
+
+// MaxKeys returns the maximum number of resources (see WithMaxKeys), 0 if
+// unlimited.
+func (s *Storage) MaxKeys() int {
+	return s.maxKeys
+}
+
+// Used returns the bytes currently stored (see domain.Resource.Size),
+// expired resources not yet evicted included.
+func (s *Storage) Used() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.used
+}
+
 // DebugLog emits a structured DEBUG-level log with the contents of the storage.
 //
 // The log entry includes:
@@ -112,9 +420,7 @@ func (s *Storage) DebugLog() {
 	}
 	s.mu.RUnlock()
 	// Sort by key for deterministic order
-	sort.Slice(snapshot, func(i, j int) bool {
-		return snapshot[i].Key.ToHexString(false) < snapshot[j].Key.ToHexString(false)
-	})
+	sortByKey(snapshot)
 	entries := make([]map[string]any, 0, len(snapshot))
 	for _, res := range snapshot {
 		entries = append(entries, map[string]any{
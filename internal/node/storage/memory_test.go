@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"errors"
+	"testing"
+)
+
+func TestChecksumDetectsCorruption(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	res := domain.Resource{Key: sp.NewIdFromString("k"), RawKey: "k", Value: "original"}
+	key := res.Key.ToHexString(false)
+
+	tests := []struct {
+		name     string
+		checksum bool
+		corrupt  func(s *Storage)
+		wantErr  error
+	}{
+		{name: "intact value", checksum: true, corrupt: func(*Storage) {}},
+		{
+			name: "corrupted value", checksum: true, wantErr: domain.ErrResourceCorrupted,
+			corrupt: func(s *Storage) {
+				r := s.data[key]
+				r.Value = "originaL" // un singolo bit diverso
+				s.data[key] = r
+			},
+		},
+		{
+			name: "corrupted raw key", checksum: true, wantErr: domain.ErrResourceCorrupted,
+			corrupt: func(s *Storage) {
+				r := s.data[key]
+				r.RawKey = "x"
+				s.data[key] = r
+			},
+		},
+		{
+			// Senza checksum la corruzione non è rilevabile
+			name: "checksum disabled", checksum: false,
+			corrupt: func(s *Storage) {
+				r := s.data[key]
+				r.Value = "originaL"
+				s.data[key] = r
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMemoryStorage(&logger.NopLogger{}, WithChecksum(tt.checksum))
+			s.Put(res)
+			tt.corrupt(s)
+
+			_, err := s.Get(res.Key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get: got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("overwrite refreshes checksum", func(t *testing.T) {
+		s := NewMemoryStorage(&logger.NopLogger{}, WithChecksum(true))
+		s.Put(res)
+		updated := res
+		updated.Value = "updated"
+		s.Put(updated)
+		got, err := s.Get(res.Key)
+		if err != nil || got.Value != "updated" {
+			t.Fatalf("Get: got %q, %v; want %q", got.Value, err, "updated")
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"KoordeDHT/internal/domain"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"maps"
+	"math/big"
+	"slices"
+	"sort"
+)
+
+// MaxMerkleDepth bounds the depth of a MerkleTree (2^depth leaves).
+const MaxMerkleDepth = 16
+
+// MerkleEntry is a key of a Merkle leaf with the digest of its resource.
+type MerkleEntry struct {
+	Key    domain.ID
+	Digest []byte
+}
+
+// MerkleTree is a binary hash tree over the resources of a key range
+// (from, to], used by anti-entropy to find the resources on which two
+// nodes differ without exchanging them. The range is split into 2^depth
+// leaves of equal width; a leaf hashes the sorted keys it covers with the
+// digests of their resources, an inner node hashes its two children. Empty
+// subtrees have a nil hash, so that two trees over the same range agree on
+// them whatever the depth.
+type MerkleTree struct {
+	depth  int
+	hashes [][][]byte      // hashes[level][index], level 0 = root, level depth = leaves
+	leaves [][]MerkleEntry // entries of each leaf, sorted by key
+}
+
+// NewMerkleTree builds the tree of depth levels (clamped to
+// [0, MaxMerkleDepth]) over the resources with key in (from, to] in a
+// space of bits-bit identifiers; resources outside the range are ignored.
+func NewMerkleTree(resources []domain.Resource, from, to domain.ID, bits, depth int) *MerkleTree {
+	depth = min(max(depth, 0), MaxMerkleDepth)
+	t := &MerkleTree{
+		depth:  depth,
+		hashes: make([][][]byte, depth+1),
+		leaves: make([][]MerkleEntry, 1<<depth),
+	}
+
+	ring := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	lo := from.ToBigInt()
+	span := new(big.Int).Sub(to.ToBigInt(), lo)
+	span.Mod(span, ring)
+	if span.Sign() == 0 {
+		span.Set(ring) // (x, x] is the whole ring
+	}
+	for _, r := range resources {
+		if !r.Key.Between(from, to) {
+			continue
+		}
+		// offset of the key in [0, span), scaled to [0, 2^depth)
+		off := new(big.Int).Sub(r.Key.ToBigInt(), lo)
+		off.Sub(off, big.NewInt(1))
+		off.Mod(off, ring)
+		off.Lsh(off, uint(depth))
+		off.Quo(off, span)
+		leaf := int(off.Int64())
+		t.leaves[leaf] = append(t.leaves[leaf], MerkleEntry{Key: r.Key, Digest: ResourceDigest(r)})
+	}
+
+	leafHashes := make([][]byte, len(t.leaves))
+	for i, entries := range t.leaves {
+		if len(entries) == 0 {
+			continue
+		}
+		sort.Slice(entries, func(a, b int) bool { return entries[a].Key.Cmp(entries[b].Key) < 0 })
+		h := sha256.New()
+		for _, e := range entries {
+			h.Write(e.Key)
+			h.Write(e.Digest)
+		}
+		leafHashes[i] = h.Sum(nil)
+	}
+	t.hashes[depth] = leafHashes
+	for level := depth - 1; level >= 0; level-- {
+		below := t.hashes[level+1]
+		cur := make([][]byte, 1<<level)
+		for i := range cur {
+			cur[i] = combine(below[2*i], below[2*i+1])
+		}
+		t.hashes[level] = cur
+	}
+	return t
+}
+
+// combine returns the hash of an inner node from those of its children
+// (nil if both subtrees are empty).
+func combine(left, right []byte) []byte {
+	if left == nil && right == nil {
+		return nil
+	}
+	var empty [sha256.Size]byte
+	h := sha256.New()
+	for _, c := range [][]byte{left, right} {
+		if c == nil {
+			c = empty[:]
+		}
+		h.Write(c)
+	}
+	return h.Sum(nil)
+}
+
+// Depth returns the depth of the tree.
+func (t *MerkleTree) Depth() int { return t.depth }
+
+// Hash returns the hash of the node at level with the given index, nil if
+// its subtree is empty or the node does not exist.
+func (t *MerkleTree) Hash(level, index int) []byte {
+	if level < 0 || level > t.depth || index < 0 || index >= 1<<level {
+		return nil
+	}
+	return t.hashes[level][index]
+}
+
+// Leaf returns the entries of the leaf with the given index, sorted by key.
+func (t *MerkleTree) Leaf(index int) []MerkleEntry {
+	if index < 0 || index >= len(t.leaves) {
+		return nil
+	}
+	return t.leaves[index]
+}
+
+// Equal reports whether the hash of the node at level with the given index
+// matches hash.
+func (t *MerkleTree) Equal(level, index int, hash []byte) bool {
+	return bytes.Equal(t.Hash(level, index), hash)
+}
+
+// ResourceDigest returns the digest of the content of r compared by
+// anti-entropy: raw key, value, expiry (with the millisecond precision
+// of the wire format) and application metadata, in key order. The origin
+// is bookkeeping of the node and is left out.
+func ResourceDigest(r domain.Resource) []byte {
+	h := sha256.New()
+	var n [8]byte
+	for _, field := range []string{r.RawKey, r.Value} {
+		binary.BigEndian.PutUint64(n[:], uint64(len(field)))
+		h.Write(n[:])
+		h.Write([]byte(field))
+	}
+	clear(n[:])
+	if !r.Expiry.IsZero() {
+		binary.BigEndian.PutUint64(n[:], uint64(r.Expiry.UnixMilli()))
+	}
+	h.Write(n[:])
+	for _, k := range slices.Sorted(maps.Keys(r.Metadata)) {
+		for _, field := range []string{k, r.Metadata[k]} {
+			binary.BigEndian.PutUint64(n[:], uint64(len(field)))
+			h.Write(n[:])
+			h.Write([]byte(field))
+		}
+	}
+	return h.Sum(nil)
+}
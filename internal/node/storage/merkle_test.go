@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"KoordeDHT/internal/domain"
+	"fmt"
+	"testing"
+)
+
+func TestMerkleTree(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	const depth = 4
+	id := func(x uint64) domain.ID { return sp.FromUint64(x) }
+	res := func(x uint64, value string) domain.Resource {
+		return domain.Resource{Key: id(x), RawKey: fmt.Sprint(x), Value: value}
+	}
+	base := []domain.Resource{res(0x1000, "a"), res(0x4000, "b"), res(0x8000, "c"), res(0xf000, "d")}
+
+	tests := []struct {
+		name     string
+		from, to domain.ID
+		other    []domain.Resource
+		diff     []int // foglie che devono differire
+	}{
+		{name: "same resources", from: id(0), to: id(0xffff), other: base},
+		{name: "missing key", from: id(0), to: id(0xffff), other: base[1:], diff: []int{0}},
+		{name: "changed value", from: id(0), to: id(0xffff),
+			other: []domain.Resource{res(0x1000, "a"), res(0x4000, "b"), res(0x8000, "x"), res(0xf000, "d")}, diff: []int{7}},
+		{name: "extra key", from: id(0), to: id(0xffff), other: append(append([]domain.Resource{}, base...), res(0x4001, "e")), diff: []int{4}},
+		{name: "key outside the range ignored", from: id(0), to: id(0xefff), other: base[:3]},
+		// intervallo a cavallo dello zero: (0xe000, 0x2000] copre 0xf000 e 0x1000,
+		// foglie larghe 0x400
+		{name: "wrap-around range", from: id(0xe000), to: id(0x2000), other: []domain.Resource{res(0x1000, "a")}, diff: []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewMerkleTree(base, tt.from, tt.to, sp.Bits, depth)
+			b := NewMerkleTree(tt.other, tt.from, tt.to, sp.Bits, depth)
+			if got, want := a.Equal(0, 0, b.Hash(0, 0)), len(tt.diff) == 0; got != want {
+				t.Fatalf("roots equal = %v, want %v", got, want)
+			}
+			var diff []int
+			for i := 0; i < 1<<depth; i++ {
+				if !a.Equal(depth, i, b.Hash(depth, i)) {
+					diff = append(diff, i)
+				}
+			}
+			if fmt.Sprint(diff) != fmt.Sprint(tt.diff) {
+				t.Errorf("differing leaves = %v, want %v", diff, tt.diff)
+			}
+		})
+	}
+
+	// albero vuoto: radice nil
+	if h := NewMerkleTree(nil, id(0), id(0), sp.Bits, depth).Hash(0, 0); h != nil {
+		t.Errorf("empty tree root = %x, want nil", h)
+	}
+	// le foglie contengono le chiavi ordinate
+	tree := NewMerkleTree(base, id(0), id(0), sp.Bits, 0)
+	if leaf := tree.Leaf(0); len(leaf) != len(base) || leaf[0].Key.Cmp(leaf[len(leaf)-1].Key) >= 0 {
+		t.Errorf("single leaf = %v, want the %d keys sorted", leaf, len(base))
+	}
+}
@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"KoordeDHT/internal/domain"
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Store is the local key-value storage of a node. Two implementations are
+// available: Storage (in memory, see NewMemoryStorage) and BoltStorage
+// (persisted to a file, see OpenBolt).
+//
+// Implementations must be safe for concurrent use. Resource IDs are
+// compared with domain.ID semantics, so Between honors the wrap-around of
+// the ring.
+type Store interface {
+	// Put inserts or updates a copy of a resource (a replica, transfer or
+	// repair), regardless of the quota. A copy older than the stored one
+	// (see domain.Resource.Version) is ignored, so that a stale copy never
+	// replaces a fresh one.
+	Put(resource domain.Resource)
+	// TryPut is PutConditional with the zero Condition.
+	TryPut(resource domain.Resource) error
+	// PutConditional stores a resource only if cond holds for the one
+	// currently stored under its key, atomically with respect to the other
+	// writes, and fails with domain.ErrPreconditionFailed otherwise. Unlike
+	// Put, it fails with domain.ErrStorageFull if the resource does not fit
+	// in the quota (see WithQuota) or in the maximum number of keys with the
+	// EvictReject policy (see WithMaxKeys).
+	//
+	// A resource without a version is a new write and one stored under a
+	// non-zero condition supersedes the one it was checked against: both
+	// are always applied, with a version raised above the stored one (see
+	// supersede). A versioned resource under the zero Condition is a copy
+	// and is ignored, as by Put, if it is older than the stored one. The
+	// version now stored under the key is returned.
+	PutConditional(resource domain.Resource, cond domain.Condition) (uint64, error)
+	// Get returns the resource with the given ID, or ErrResourceNotFound
+	// (also for expired resources) or ErrResourceCorrupted.
+	Get(id domain.ID) (domain.Resource, error)
+	// Delete removes the resource with the given ID, or returns
+	// ErrResourceNotFound.
+	Delete(id domain.ID) error
+	// Between returns the resources with IDs in (from, to], expired ones
+	// excluded.
+	Between(from, to domain.ID) []domain.Resource
+	// BetweenMulti returns, in a single scan, the resources of every
+	// interval, by index in intervals (see Interval), expired ones excluded.
+	BetweenMulti(intervals []Interval) map[int][]domain.Resource
+	// All returns a snapshot of the stored resources, expired ones excluded.
+	All() []domain.Resource
+	// Len returns the number of stored resources, expired ones excluded.
+	Len() int
+	// MaxKeys returns the maximum number of resources the store holds (see
+	// WithMaxKeys), 0 if unlimited.
+	MaxKeys() int
+	// Sweep evicts the expired resources and returns how many were removed.
+	Sweep() int
+	// StartSweeper runs Sweep every interval until ctx is canceled.
+	StartSweeper(ctx context.Context, interval time.Duration)
+	// Close releases the resources held by the store.
+	Close() error
+}
+
+var (
+	_ Store = (*Storage)(nil)
+	_ Store = (*BoltStorage)(nil)
+)
+
+// Interval is the range (From, To] of the ring, with the wrap-around
+// semantics of domain.ID.Between (From == To is the whole ring).
+type Interval struct {
+	From, To domain.ID
+}
+
+// bucket appends res to the bucket of every interval that contains its
+// key: with overlapping intervals a resource is listed in all of them.
+func bucket(buckets map[int][]domain.Resource, intervals []Interval, res domain.Resource) {
+	for i, iv := range intervals {
+		if res.Key.Between(iv.From, iv.To) {
+			buckets[i] = append(buckets[i], res)
+		}
+	}
+}
+
+// stale reports whether res, a copy, is older than cur, the live resource
+// stored under its key (nil if none), and must not replace it (see
+// Store.Put).
+func stale(res domain.Resource, cur *domain.Resource) bool {
+	return cur != nil && res.Version < cur.Version
+}
+
+// supersedes reports whether res, written under cond, is a new or
+// conditional write rather than a copy (see Store.PutConditional).
+func supersedes(res domain.Resource, cond domain.Condition) bool {
+	return res.Version == 0 || !cond.IsZero()
+}
+
+// supersede returns the version of a new or conditional write res stored
+// over cur at time now: its own, or now if it has none, raised above the
+// one of cur so that the versions of a key never decrease, even if the
+// clock of the writer is behind.
+func supersede(res domain.Resource, cur *domain.Resource, now time.Time) uint64 {
+	v := res.Version
+	if v == 0 {
+		v = uint64(now.UnixNano())
+	}
+	if cur != nil && v <= cur.Version {
+		return cur.Version + 1
+	}
+	return v
+}
+
+// Option configures a Store.
+type Option func(*options)
+
+type options struct {
+	checksum bool
+	quota    int64
+	maxKeys  int
+	eviction string
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithChecksum enables per-resource CRC32 checksums: they are computed on
+// Put and verified on Get, which reports domain.ErrResourceCorrupted if the
+// stored resource no longer matches its checksum.
+func WithChecksum(enabled bool) Option {
+	return func(o *options) {
+		o.checksum = enabled
+	}
+}
+
+// WithQuota limits the bytes (see domain.Resource.Size) that TryPut
+// accepts. A non-positive quota disables the limit. Put ignores the quota,
+// so that transfers of already-accepted resources are never dropped.
+func WithQuota(bytes int64) Option {
+	return func(o *options) {
+		o.quota = max(bytes, 0)
+	}
+}
+
+// Eviction policies of a store holding its maximum number of keys (see
+// WithMaxKeys).
+const (
+	EvictReject = "reject" // new keys are rejected with domain.ErrStorageFull
+	EvictLRU    = "lru"    // the least recently used resources make room for new keys
+)
+
+// WithMaxKeys limits the resources a store holds to maxKeys. Once the store
+// is full, a new key is handled according to policy:
+//
+//   - EvictReject: TryPut and PutConditional fail with
+//     domain.ErrStorageFull. As for the quota, Put ignores the limit, so
+//     that transfers of already-accepted resources are never dropped.
+//   - EvictLRU: every write, Put included, evicts the resources least
+//     recently read (Get) or written to make room for the new one.
+//
+// Overwrites of a stored key are always accepted. Expired resources not yet
+// evicted count towards the limit. A non-positive maxKeys disables the
+// limit; an unknown policy is treated as EvictReject.
+func WithMaxKeys(maxKeys int, policy string) Option {
+	return func(o *options) {
+		o.maxKeys = max(maxKeys, 0)
+		o.eviction = policy
+	}
+}
+
+// accessOrder returns the tracker of the LRU policy, or nil if the keys
+// are not limited or not evicted.
+func (o options) accessOrder() *accessOrder {
+	if o.maxKeys > 0 && o.eviction == EvictLRU {
+		return newAccessOrder()
+	}
+	return nil
+}
+
+// startSweeper runs sweep every interval in a background goroutine until
+// ctx is canceled. A non-positive interval disables the sweeper.
+func startSweeper(ctx context.Context, interval time.Duration, sweep func() int) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sweep()
+			}
+		}
+	}()
+}
+
+// sortByKey orders resources by the hexadecimal form of their ID, the
+// deterministic order of DebugLog and Page.
+func sortByKey(resources []domain.Resource) {
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Key.ToHexString(false) < resources[j].Key.ToHexString(false)
+	})
+}
+
+// Page returns, ordered by ID, up to limit resources of s whose raw key
+// starts with prefix and whose ID (hexadecimal, without 0x) is greater than
+// after, together with the token of the next page: the ID of the last
+// resource returned, or "" if no resource follows. An empty after starts
+// from the first resource; a non-positive limit returns all of them.
+func Page(s Store, prefix, after string, limit int) ([]domain.Resource, string) {
+	all := s.All()
+	sortByKey(all)
+	page := make([]domain.Resource, 0, min(len(all), max(limit, 0)))
+	for _, r := range all {
+		if r.Key.ToHexString(false) <= after || !strings.HasPrefix(r.RawKey, prefix) {
+			continue
+		}
+		if limit > 0 && len(page) == limit {
+			return page, page[len(page)-1].Key.ToHexString(false)
+		}
+		page = append(page, r)
+	}
+	return page, ""
+}
@@ -0,0 +1,436 @@
+package storage
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQuotaAccounting(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	res := func(key, value string) domain.Resource {
+		return domain.Resource{Key: sp.NewIdFromString(key), RawKey: key, Value: value}
+	}
+	type quotaStore interface {
+		Store
+		Used() int64
+	}
+	backends := []struct {
+		name string
+		open func(t *testing.T, opts ...Option) quotaStore
+	}{
+		{name: "memory", open: func(_ *testing.T, opts ...Option) quotaStore {
+			return NewMemoryStorage(&logger.NopLogger{}, opts...)
+		}},
+		{name: "bolt", open: func(t *testing.T, opts ...Option) quotaStore {
+			return openBolt(t, filepath.Join(t.TempDir(), "store.db"), opts...)
+		}},
+	}
+	for _, be := range backends {
+		t.Run(be.name, func(t *testing.T) {
+			// "a" con valore di 7 byte occupa 2 + 1 + 7 = 10 byte
+			s := be.open(t, WithQuota(20))
+			defer s.Close()
+
+			steps := []struct {
+				name     string
+				op       func() error
+				wantErr  error
+				wantUsed int64
+			}{
+				{name: "first", op: func() error { return s.TryPut(res("a", "1234567")) }, wantUsed: 10},
+				{name: "second", op: func() error { return s.TryPut(res("b", "1234567")) }, wantUsed: 20},
+				{name: "over quota", op: func() error { return s.TryPut(res("c", "1234567")) }, wantErr: domain.ErrStorageFull, wantUsed: 20},
+				// Una sovrascrittura più corta non aggiunge byte
+				{name: "shrinking overwrite", op: func() error { return s.TryPut(res("a", "1")) }, wantUsed: 14},
+				{name: "growing overwrite", op: func() error { return s.TryPut(res("a", "1234567890")) }, wantErr: domain.ErrStorageFull, wantUsed: 14},
+				{name: "delete frees space", op: func() error { return s.Delete(res("b", "").Key) }, wantUsed: 4},
+				{name: "fits again", op: func() error { return s.TryPut(res("c", "1234567")) }, wantUsed: 14},
+				// Put ignora la quota (trasferimenti tra nodi)
+				{name: "put bypasses quota", op: func() error { s.Put(res("d", "1234567")); return nil }, wantUsed: 24},
+			}
+			for _, st := range steps {
+				if err := st.op(); !errors.Is(err, st.wantErr) {
+					t.Fatalf("%s: got %v, want %v", st.name, err, st.wantErr)
+				}
+				if got := s.Used(); got != st.wantUsed {
+					t.Fatalf("%s: Used = %d, want %d", st.name, got, st.wantUsed)
+				}
+			}
+		})
+	}
+}
+
+func TestPutConditional(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	res := func(key, value string) domain.Resource {
+		return domain.Resource{Key: sp.NewIdFromString(key), RawKey: key, Value: value}
+	}
+	absent := domain.Condition{IfAbsent: true}
+	equal := func(v string) domain.Condition { return domain.Condition{IfEqual: true, Expected: v} }
+	backends := []struct {
+		name string
+		open func(t *testing.T) Store
+	}{
+		{name: "memory", open: func(*testing.T) Store { return NewMemoryStorage(&logger.NopLogger{}) }},
+		{name: "bolt", open: func(t *testing.T) Store { return openBolt(t, filepath.Join(t.TempDir(), "store.db")) }},
+	}
+	for _, be := range backends {
+		t.Run(be.name, func(t *testing.T) {
+			s := be.open(t)
+			defer s.Close()
+
+			steps := []struct {
+				name      string
+				res       domain.Resource
+				cond      domain.Condition
+				wantErr   error
+				wantValue string
+			}{
+				{name: "absent on empty key", res: res("a", "v1"), cond: absent, wantValue: "v1"},
+				{name: "absent on stored key", res: res("a", "v2"), cond: absent, wantErr: domain.ErrPreconditionFailed, wantValue: "v1"},
+				{name: "equal on other value", res: res("a", "v2"), cond: equal("v0"), wantErr: domain.ErrPreconditionFailed, wantValue: "v1"},
+				{name: "equal on current value", res: res("a", "v2"), cond: equal("v1"), wantValue: "v2"},
+				{name: "equal on missing key", res: res("b", "v1"), cond: equal(""), wantErr: domain.ErrPreconditionFailed},
+				{name: "unconditional", res: res("a", "v3"), wantValue: "v3"},
+			}
+			for _, st := range steps {
+				if _, err := s.PutConditional(st.res, st.cond); !errors.Is(err, st.wantErr) {
+					t.Fatalf("%s: got %v, want %v", st.name, err, st.wantErr)
+				}
+				got, err := s.Get(st.res.Key)
+				if st.wantValue == "" {
+					if !errors.Is(err, domain.ErrResourceNotFound) {
+						t.Fatalf("%s: Get: got %q, %v, want not found", st.name, got.Value, err)
+					}
+					continue
+				}
+				if err != nil || got.Value != st.wantValue {
+					t.Fatalf("%s: Get: got %q, %v, want %q", st.name, got.Value, err, st.wantValue)
+				}
+			}
+
+			// una risorsa scaduta conta come assente
+			expired := res("c", "old")
+			expired.Expiry = time.Now().Add(-time.Second)
+			s.Put(expired)
+			if _, err := s.PutConditional(res("c", "new"), absent); err != nil {
+				t.Fatalf("absent on expired key: %v", err)
+			}
+
+			// scritture concorrenti: una sola PutIfAbsent deve riuscire
+			var (
+				wg sync.WaitGroup
+				ok atomic.Int32
+			)
+			for i := range 16 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := s.PutConditional(res("d", fmt.Sprint(i)), absent); err == nil {
+						ok.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+			if got := ok.Load(); got != 1 {
+				t.Errorf("concurrent PutConditional: %d succeeded, want 1", got)
+			}
+		})
+	}
+}
+
+func TestVersions(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	res := func(value string, version uint64) domain.Resource {
+		return domain.Resource{Key: sp.NewIdFromString("key"), RawKey: "key", Value: value, Version: version}
+	}
+	backends := []struct {
+		name string
+		open func(t *testing.T) Store
+	}{
+		{name: "memory", open: func(*testing.T) Store { return NewMemoryStorage(&logger.NopLogger{}) }},
+		{name: "bolt", open: func(t *testing.T) Store { return openBolt(t, filepath.Join(t.TempDir(), "store.db")) }},
+	}
+	for _, be := range backends {
+		t.Run(be.name, func(t *testing.T) {
+			s := be.open(t)
+			defer s.Close()
+
+			steps := []struct {
+				name        string
+				op          func() error
+				wantValue   string
+				wantVersion uint64
+			}{
+				{name: "first write", op: func() error { s.Put(res("v10", 10)); return nil }, wantValue: "v10", wantVersion: 10},
+				// una replica più vecchia non sovrascrive quella più recente
+				{name: "older put ignored", op: func() error { s.Put(res("v5", 5)); return nil }, wantValue: "v10", wantVersion: 10},
+				{name: "older tryput ignored", op: func() error { return s.TryPut(res("v5", 5)) }, wantValue: "v10", wantVersion: 10},
+				{name: "same version applied", op: func() error { s.Put(res("v10b", 10)); return nil }, wantValue: "v10b", wantVersion: 10},
+				{name: "newer tryput applied", op: func() error { return s.TryPut(res("v20", 20)) }, wantValue: "v20", wantVersion: 20},
+				// una scrittura condizionata supera sempre la versione su cui è stata verificata
+				{name: "conditional raises version", op: func() error {
+					_, err := s.PutConditional(res("v21", 3), domain.Condition{IfEqual: true, Expected: "v20"})
+					return err
+				}, wantValue: "v21", wantVersion: 21},
+				// una nuova scrittura (senza versione) si applica sempre, anche
+				// sopra una copia scritta da un nodo con l'orologio avanti
+				{name: "copy from a clock ahead", op: func() error { s.Put(res("ahead", 1<<62)); return nil }, wantValue: "ahead", wantVersion: 1 << 62},
+				{name: "new write raised above it", op: func() error { return s.TryPut(res("new", 0)) }, wantValue: "new", wantVersion: 1<<62 + 1},
+				{name: "new conditional write raised above it", op: func() error {
+					v, err := s.PutConditional(res("new2", 0), domain.Condition{IfEqual: true, Expected: "new"})
+					if err == nil && v != 1<<62+2 {
+						return fmt.Errorf("PutConditional returned version %d, want %d", v, uint64(1<<62+2))
+					}
+					return err
+				}, wantValue: "new2", wantVersion: 1<<62 + 2},
+			}
+			for _, st := range steps {
+				if err := st.op(); err != nil {
+					t.Fatalf("%s: %v", st.name, err)
+				}
+				got, err := s.Get(sp.NewIdFromString("key"))
+				if err != nil || got.Value != st.wantValue || got.Version != st.wantVersion {
+					t.Fatalf("%s: got %q v%d, %v, want %q v%d", st.name, got.Value, got.Version, err, st.wantValue, st.wantVersion)
+				}
+			}
+		})
+	}
+}
+
+func TestBetweenMulti(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	iv := func(from, to uint64) Interval { return Interval{From: sp.FromUint64(from), To: sp.FromUint64(to)} }
+	tests := []struct {
+		name      string
+		intervals []Interval
+	}{
+		{name: "disjoint", intervals: []Interval{iv(0x0000, 0x4000), iv(0x4000, 0x8000), iv(0x8000, 0xc000)}},
+		{name: "wrap-around", intervals: []Interval{iv(0xc000, 0x2000), iv(0x2000, 0xc000)}},
+		// le chiavi nell'intersezione compaiono in entrambi gli intervalli
+		{name: "overlapping", intervals: []Interval{iv(0x1000, 0x9000), iv(0x6000, 0x3000), iv(0x7000, 0x8000)}},
+		{name: "whole ring", intervals: []Interval{iv(0x5000, 0x5000), iv(0xf000, 0x1000)}},
+		{name: "no intervals"},
+	}
+	backends := []struct {
+		name string
+		open func(t *testing.T) Store
+	}{
+		{name: "memory", open: func(*testing.T) Store { return NewMemoryStorage(&logger.NopLogger{}) }},
+		{name: "bolt", open: func(t *testing.T) Store { return openBolt(t, filepath.Join(t.TempDir(), "store.db")) }},
+	}
+	for _, be := range backends {
+		s := be.open(t)
+		defer s.Close()
+		for i := range 64 {
+			key := fmt.Sprintf("key-%d", i)
+			s.Put(domain.Resource{Key: sp.NewIdFromString(key), RawKey: key, Value: key})
+		}
+		for _, tt := range tests {
+			t.Run(be.name+"/"+tt.name, func(t *testing.T) {
+				got := s.BetweenMulti(tt.intervals)
+				for i, in := range tt.intervals {
+					// ogni bucket coincide con una Between sullo stesso intervallo
+					want := s.Between(in.From, in.To)
+					sortByKey(want)
+					sortByKey(got[i])
+					if !sameKeys(got[i], want) {
+						t.Errorf("interval %d (%s, %s]: got %d resources, want %d",
+							i, in.From.ToHexString(true), in.To.ToHexString(true), len(got[i]), len(want))
+					}
+					if len(want) == 0 {
+						if _, ok := got[i]; ok {
+							t.Errorf("interval %d: empty bucket present in the map", i)
+						}
+					}
+				}
+				if len(got) > len(tt.intervals) {
+					t.Errorf("got %d buckets for %d intervals", len(got), len(tt.intervals))
+				}
+			})
+		}
+	}
+}
+
+func sameKeys(a, b []domain.Resource) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Key.Equal(b[i].Key) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMaxKeys(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	res := func(key string) domain.Resource {
+		return domain.Resource{Key: sp.NewIdFromString(key), RawKey: key, Value: "v"}
+	}
+	type quotaStore interface {
+		Store
+		Used() int64
+	}
+	backends := []struct {
+		name string
+		open func(t *testing.T, opts ...Option) quotaStore
+	}{
+		{name: "memory", open: func(_ *testing.T, opts ...Option) quotaStore {
+			return NewMemoryStorage(&logger.NopLogger{}, opts...)
+		}},
+		{name: "bolt", open: func(t *testing.T, opts ...Option) quotaStore {
+			return openBolt(t, filepath.Join(t.TempDir(), "store.db"), opts...)
+		}},
+	}
+	type step struct {
+		name    string
+		op      func(s Store) error
+		wantErr error
+		want    string // chiavi memorizzate dopo il passo, in ordine
+	}
+	tryPut := func(key string) func(Store) error {
+		return func(s Store) error { return s.TryPut(res(key)) }
+	}
+	policies := []struct {
+		policy string
+		steps  []step
+	}{
+		{policy: EvictReject, steps: []step{
+			{name: "first", op: tryPut("a"), want: "a"},
+			{name: "second", op: tryPut("b"), want: "ab"},
+			{name: "full", op: tryPut("c"), wantErr: domain.ErrStorageFull, want: "ab"},
+			{name: "overwrite", op: tryPut("a"), want: "ab"},
+			{name: "delete frees a key", op: func(s Store) error { return s.Delete(res("b").Key) }, want: "a"},
+			{name: "fits again", op: tryPut("c"), want: "ac"},
+			// Put ignora il limite (trasferimenti tra nodi)
+			{name: "put bypasses the limit", op: func(s Store) error { s.Put(res("d")); return nil }, want: "acd"},
+		}},
+		{policy: EvictLRU, steps: []step{
+			{name: "first", op: tryPut("a"), want: "a"},
+			{name: "second", op: tryPut("b"), want: "ab"},
+			{name: "read a", op: func(s Store) error { _, err := s.Get(res("a").Key); return err }, want: "ab"},
+			{name: "evicts b", op: tryPut("c"), want: "ac"},
+			{name: "put evicts a", op: func(s Store) error { s.Put(res("d")); return nil }, want: "cd"},
+			{name: "overwrite", op: tryPut("c"), want: "cd"},
+			{name: "evicts d", op: tryPut("e"), want: "ce"},
+		}},
+	}
+	for _, be := range backends {
+		for _, p := range policies {
+			t.Run(be.name+"/"+p.policy, func(t *testing.T) {
+				s := be.open(t, WithMaxKeys(2, p.policy))
+				defer s.Close()
+				if s.MaxKeys() != 2 {
+					t.Fatalf("MaxKeys = %d, want 2", s.MaxKeys())
+				}
+				for _, st := range p.steps {
+					if err := st.op(s); !errors.Is(err, st.wantErr) {
+						t.Fatalf("%s: got %v, want %v", st.name, err, st.wantErr)
+					}
+					got := make([]string, 0, len(st.want))
+					for _, r := range s.All() {
+						got = append(got, r.RawKey)
+					}
+					sort.Strings(got)
+					if strings.Join(got, "") != st.want {
+						t.Fatalf("%s: stored %v, want %s", st.name, got, st.want)
+					}
+					// "x" con valore "v" occupa 2 + 1 + 1 = 4 byte
+					if used := s.Used(); used != int64(4*len(st.want)) {
+						t.Fatalf("%s: Used = %d, want %d", st.name, used, 4*len(st.want))
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	sp, err := domain.NewSpace(16, 2, 1)
+	if err != nil {
+		t.Fatalf("NewSpace: %v", err)
+	}
+	meta := map[string]string{"content-type": "application/json", "owner": "bob"}
+	res := domain.Resource{Key: sp.NewIdFromString("a"), RawKey: "a", Value: "{}", Metadata: meta}
+	path := filepath.Join(t.TempDir(), "store.db")
+
+	backends := []struct {
+		name   string
+		open   func(t *testing.T) Store
+		reopen func(t *testing.T) Store // nil = non persistente
+	}{
+		{name: "memory", open: func(*testing.T) Store {
+			return NewMemoryStorage(&logger.NopLogger{}, WithChecksum(true), WithQuota(100))
+		}},
+		{name: "bolt", open: func(t *testing.T) Store {
+			return openBolt(t, path, WithChecksum(true), WithQuota(100))
+		}, reopen: func(t *testing.T) Store {
+			return openBolt(t, path, WithChecksum(true))
+		}},
+	}
+	for _, be := range backends {
+		t.Run(be.name, func(t *testing.T) {
+			s := be.open(t)
+			if err := s.TryPut(res); err != nil {
+				t.Fatalf("TryPut: %v", err)
+			}
+			// La quota conta anche i metadati: 2 + 1 + 2 + 12 + 16 + 5 + 3 = 41 byte
+			if got := s.(interface{ Used() int64 }).Used(); got != 41 {
+				t.Errorf("Used = %d, want 41", got)
+			}
+			check := func(s Store) {
+				t.Helper()
+				got, err := s.Get(res.Key)
+				if err != nil {
+					t.Fatalf("Get: %v", err)
+				}
+				if !maps.Equal(got.Metadata, meta) {
+					t.Errorf("Metadata = %v, want %v", got.Metadata, meta)
+				}
+			}
+			check(s)
+			// I metadati entrano nel digest di anti-entropy
+			plain := res
+			plain.Metadata = nil
+			if bytes.Equal(ResourceDigest(res), ResourceDigest(plain)) {
+				t.Error("ResourceDigest ignores the metadata")
+			}
+			if err := s.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			if be.reopen != nil {
+				s := be.reopen(t)
+				defer s.Close()
+				check(s)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -17,12 +18,28 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
 
-func InitTracer(cfg config.TelemetryConfig, serviceName string, nodeId domain.ID) func(context.Context) error {
-	if !cfg.Tracing.Enabled {
-		log.Println("Tracing disabled")
-		return func(context.Context) error { return nil }
+// ShutdownWithin runs an exporter shutdown function (e.g. the one returned
+// by InitTracer or InitMeter, or the Shutdown of the metrics HTTP server) under a
+// context that expires after timeout, so that pending spans are flushed
+// without blocking the exit of the process indefinitely. A non-positive
+// timeout leaves the context without a deadline. The error reports a
+// flush that did not complete in time.
+func ShutdownWithin(shutdown func(context.Context) error, timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
+	if err := shutdown(ctx); err != nil {
+		return fmt.Errorf("telemetry: shutdown: %w", err)
+	}
+	return nil
+}
 
+// newResource describes the node to the collector: the service name and
+// the node ID, shared by the traces and the metrics.
+func newResource(serviceName string, nodeId domain.ID) (*resource.Resource, error) {
 	attrs := append(
 		[]attribute.KeyValue{
 			semconv.ServiceNameKey.String(serviceName),
@@ -30,12 +47,19 @@ func InitTracer(cfg config.TelemetryConfig, serviceName string, nodeId domain.ID
 		},
 		IdAttributes("dht.node.id", nodeId)...,
 	)
-
-	res, err := resource.New(
+	return resource.New(
 		context.Background(),
 		resource.WithAttributes(attrs...),
 	)
+}
+
+func InitTracer(cfg config.TelemetryConfig, serviceName string, nodeId domain.ID) func(context.Context) error {
+	if !cfg.Tracing.Enabled {
+		log.Println("Tracing disabled")
+		return func(context.Context) error { return nil }
+	}
 
+	res, err := newResource(serviceName, nodeId)
 	if err != nil {
 		log.Fatalf("failed to create resource: %v", err)
 	}
@@ -0,0 +1,75 @@
+package telemetry_test
+
+import (
+	"KoordeDHT/internal/node/telemetry"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// stuckExporter simula un collector irraggiungibile: ogni esportazione
+// resta bloccata finché il contesto non scade.
+type stuckExporter struct{}
+
+func (stuckExporter) ExportSpans(ctx context.Context, _ []sdktrace.ReadOnlySpan) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (stuckExporter) Shutdown(ctx context.Context) error { return nil }
+
+func TestShutdownWithinRespectsDeadline(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		shutdown func() func(context.Context) error
+		wantErr  error
+	}{
+		{
+			name:     "flush completes",
+			shutdown: func() func(context.Context) error { return func(context.Context) error { return nil } },
+		},
+		{
+			name: "exporter hangs",
+			shutdown: func() func(context.Context) error {
+				return func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "tracer provider with pending spans",
+			shutdown: func() func(context.Context) error {
+				tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(stuckExporter{}))
+				_, span := tp.Tracer("test").Start(context.Background(), "pending")
+				span.End()
+				return tp.Shutdown
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown := tt.shutdown()
+			start := time.Now()
+			err := telemetry.ShutdownWithin(shutdown, timeout)
+			elapsed := time.Since(start)
+
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("ShutdownWithin: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ShutdownWithin error = %v, want %v", err, tt.wantErr)
+			}
+			if elapsed > timeout+time.Second {
+				t.Errorf("ShutdownWithin returned after %v, want about %v", elapsed, timeout)
+			}
+		})
+	}
+}
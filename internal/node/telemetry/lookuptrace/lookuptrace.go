@@ -1,8 +1,8 @@
 package lookuptrace
 
 import (
+	"KoordeDHT/internal/node/ctxutil"
 	"context"
-	"strconv"
 	"strings"
 
 	"go.opentelemetry.io/otel"
@@ -38,7 +38,7 @@ func IsLookup(ctx context.Context) bool {
 }
 
 // ServerInterceptor creates spans only for marked Lookup and FindSuccessor
-// and propagates the OTEL context with hop count and lookup flag
+// and propagates the OTEL context and lookup flag
 func ServerInterceptor() grpc.UnaryServerInterceptor {
 	propagator := otel.GetTextMapPropagator()
 
@@ -56,13 +56,9 @@ func ServerInterceptor() grpc.UnaryServerInterceptor {
 		if strings.Contains(method, "Lookup") || (strings.Contains(method, "FindSuccessor") && IsLookup(ctx)) {
 			ctx = WithLookup(ctx)
 
-			// Increment hop count from metadata
-			var hopCount int
+			// Read the hop count set by the sending node (see ctxutil.WithHops)
+			hopCount := ctxutil.Hops(ctx)
 			if md, ok := metadata.FromIncomingContext(ctx); ok {
-				if vals := md.Get("x-koorde-hop"); len(vals) > 0 {
-					hopCount, _ = strconv.Atoi(vals[0])
-				}
-
 				// Extract OTEL context from metadata
 				ctx = propagator.Extract(ctx, metadataCarrier(md))
 			}
@@ -101,21 +97,11 @@ func ClientInterceptor() grpc.UnaryClientInterceptor {
 		if IsLookup(ctx) {
 			ctx = WithLookup(ctx)
 
-			// Increment hop count from metadata
-			var hopCount int
-			if md, ok := metadata.FromOutgoingContext(ctx); ok {
-				if vals := md.Get("x-koorde-hop"); len(vals) > 0 {
-					hopCount, _ = strconv.Atoi(vals[0])
-				}
-			}
-			hopCount++
+			// The forwarded call is one hop past the request being served
+			hopCount := ctxutil.Hops(ctx) + 1
 
 			md, _ := metadata.FromOutgoingContext(ctx)
 			md = md.Copy()
-			md.Set("x-koorde-hop", strconv.Itoa(hopCount))
-
-			// Create new outgoing context with updated metadata
-			ctx = metadata.NewOutgoingContext(ctx, md)
 			ctx, span := tracer.Start(ctx, method, trace.WithSpanKind(trace.SpanKindClient))
 			defer span.End()
 
@@ -0,0 +1,147 @@
+package telemetry
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/config"
+	"KoordeDHT/internal/telemetry/metrics"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const meterName = "KoordeDHT/node"
+
+// Result values of the "result" attribute of the instruments.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// The instruments are created on the global meter provider, which
+// forwards them to the provider installed by InitMeter (a no-op until
+// then), so the node can record them unconditionally.
+var (
+	meter = otel.Meter(meterName)
+
+	lookupDuration = mustHistogram("koorde.lookup.duration",
+		"Duration of the lookups originated by this node.")
+	storeDuration = mustHistogram("koorde.store.duration",
+		"Duration of the Put, Get and Delete operations served by this node, lookup included.")
+	rpcErrors = mustCounter("koorde.rpc.errors",
+		"RPCs served by this node that failed, by method and gRPC status code.")
+)
+
+func mustHistogram(name, desc string) metric.Float64Histogram {
+	h, err := meter.Float64Histogram(name,
+		metric.WithDescription(desc),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(metrics.DefaultLatencyBuckets...),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("telemetry: instrument %s: %v", name, err))
+	}
+	return h
+}
+
+func mustCounter(name, desc string) metric.Int64Counter {
+	c, err := meter.Int64Counter(name, metric.WithDescription(desc))
+	if err != nil {
+		panic(fmt.Sprintf("telemetry: instrument %s: %v", name, err))
+	}
+	return c
+}
+
+func result(err error) attribute.KeyValue {
+	if err != nil {
+		return attribute.String("result", ResultError)
+	}
+	return attribute.String("result", ResultSuccess)
+}
+
+// RecordLookup records the duration of a lookup originated by this node,
+// started at start and ended with err.
+func RecordLookup(ctx context.Context, start time.Time, err error) {
+	lookupDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(result(err)))
+}
+
+// RecordStore records the duration of a storage operation (op is "put",
+// "get" or "delete") started at start and ended with err.
+func RecordStore(ctx context.Context, op string, start time.Time, err error) {
+	storeDuration.Record(ctx, time.Since(start).Seconds(),
+		metric.WithAttributes(attribute.String("op", op), result(err)))
+}
+
+// recordRPCError counts an RPC of method served with a non-nil err.
+func recordRPCError(ctx context.Context, method string, err error) {
+	if err == nil {
+		return
+	}
+	rpcErrors.Add(ctx, 1, metric.WithAttributes(
+		semconv.RPCMethodKey.String(method),
+		semconv.RPCGRPCStatusCodeKey.Int(int(status.Code(err))),
+	))
+}
+
+// UnaryServerInterceptor counts the unary RPCs that fail (see
+// koorde.rpc.errors).
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		recordRPCError(ctx, info.FullMethod, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor counts the streaming RPCs that fail (see
+// koorde.rpc.errors).
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		recordRPCError(ss.Context(), info.FullMethod, err)
+		return err
+	}
+}
+
+// InitMeter installs the global meter provider that pushes the OpenTelemetry
+// instruments of the node (lookup and store durations, RPC errors) over
+// OTLP to the collector of telemetry.tracing.endpoint, every
+// telemetry.metrics.exportInterval. It is a no-op unless both
+// telemetry.metrics.enabled and telemetry.metrics.otlp are set. The
+// returned function flushes the pending measurements and stops the
+// exporter (see ShutdownWithin).
+func InitMeter(cfg config.TelemetryConfig, serviceName string, nodeId domain.ID) func(context.Context) error {
+	if !cfg.Metrics.Enabled || !cfg.Metrics.OTLP {
+		log.Println("OTLP metrics disabled")
+		return func(context.Context) error { return nil }
+	}
+
+	res, err := newResource(serviceName, nodeId)
+	if err != nil {
+		log.Fatalf("failed to create resource: %v", err)
+	}
+	exp, err := otlpmetricgrpc.New(
+		context.Background(),
+		otlpmetricgrpc.WithInsecure(),
+		otlpmetricgrpc.WithEndpoint(cfg.Tracing.Endpoint),
+	)
+	if err != nil {
+		log.Fatalf("failed to initialize OTLP metrics exporter: %v", err)
+	}
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp, sdkmetric.WithInterval(cfg.Metrics.ExportInterval))),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetMeterProvider(mp)
+
+	return mp.Shutdown
+}
@@ -0,0 +1,118 @@
+package telemetry_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/telemetry"
+	"KoordeDHT/internal/node/testring"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMeterInstruments(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(mp)
+	t.Cleanup(func() {
+		otel.SetMeterProvider(prev)
+		_ = mp.Shutdown(context.Background())
+	})
+
+	r := testring.New(t, 3, testring.WithServerOptions(
+		server.WithUnaryInterceptors(telemetry.UnaryServerInterceptor()),
+		server.WithStreamInterceptors(telemetry.StreamServerInterceptor())))
+	api, conn, err := client.Connect(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.Put(ctx, api, "key", "value"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, _, err := client.Get(ctx, api, "key"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := client.Delete(ctx, api, "key"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	// la Get di una chiave assente fallisce con NotFound: è un errore RPC
+	if _, _, err := client.Get(ctx, api, "key"); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("Get after Delete: got %v, want ErrNotFound", err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	got := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			got[m.Name] = m.Data
+		}
+	}
+
+	tests := []struct {
+		name   string
+		metric string
+		attrs  []attribute.KeyValue // attributi di almeno un punto con conteggio > 0
+	}{
+		{name: "lookup duration", metric: "koorde.lookup.duration",
+			attrs: []attribute.KeyValue{attribute.String("result", telemetry.ResultSuccess)}},
+		{name: "put duration", metric: "koorde.store.duration",
+			attrs: []attribute.KeyValue{attribute.String("op", "put"), attribute.String("result", telemetry.ResultSuccess)}},
+		{name: "delete duration", metric: "koorde.store.duration",
+			attrs: []attribute.KeyValue{attribute.String("op", "delete"), attribute.String("result", telemetry.ResultSuccess)}},
+		{name: "failed get", metric: "koorde.store.duration",
+			attrs: []attribute.KeyValue{attribute.String("op", "get"), attribute.String("result", telemetry.ResultError)}},
+		{name: "rpc errors", metric: "koorde.rpc.errors",
+			attrs: []attribute.KeyValue{attribute.String("rpc.method", "/client.v1.ClientAPI/Get"), attribute.Int("rpc.grpc.status_code", 5)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, ok := got[tt.metric]
+			if !ok {
+				t.Fatalf("metric %s not recorded", tt.metric)
+			}
+			var sets []attribute.Set
+			switch d := data.(type) {
+			case metricdata.Histogram[float64]:
+				for _, p := range d.DataPoints {
+					if p.Count > 0 {
+						sets = append(sets, p.Attributes)
+					}
+				}
+			case metricdata.Sum[int64]:
+				for _, p := range d.DataPoints {
+					if p.Value > 0 {
+						sets = append(sets, p.Attributes)
+					}
+				}
+			default:
+				t.Fatalf("metric %s: unexpected aggregation %T", tt.metric, data)
+			}
+			for _, set := range sets {
+				match := true
+				for _, kv := range tt.attrs {
+					if v, ok := set.Value(kv.Key); !ok || v != kv.Value {
+						match = false
+					}
+				}
+				if match {
+					return
+				}
+			}
+			t.Errorf("metric %s: no data point with %v", tt.metric, tt.attrs)
+		})
+	}
+}
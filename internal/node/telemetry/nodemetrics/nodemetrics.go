@@ -0,0 +1,98 @@
+// Package nodemetrics defines the scrapeable metrics of a Koorde node
+// (lookups, storage and connection pool), registered on a
+// metrics.Registry and served on /metrics.
+package nodemetrics
+
+import (
+	"KoordeDHT/internal/node/ctxutil"
+	"KoordeDHT/internal/node/telemetry/lookuptrace"
+	"KoordeDHT/internal/telemetry/metrics"
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// Lookup outcomes, used as values of the "result" label.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// HopBuckets are the upper bounds of the lookup hop histogram.
+var HopBuckets = []float64{0, 1, 2, 3, 4, 5, 6, 8, 10, 12, 16, 20, 24, 32}
+
+// Metrics are the scrapeable metrics of a node. All methods are no-ops on
+// a nil *Metrics, so that the node can call them unconditionally.
+type Metrics struct {
+	Lookups         *metrics.CounterVec // lookups originated by this node, by result
+	LookupHops      *metrics.Histogram  // hop count of the lookup requests served by this node
+	StoredResources *metrics.Gauge      // resources in the local storage
+	PoolConnections *metrics.Gauge      // connections held by the client pool
+	NoPredStores    *metrics.CounterVec // stores received without a predecessor on a multi-node ring, by action
+}
+
+// New registers the node metrics on reg.
+func New(reg *metrics.Registry) *Metrics {
+	return &Metrics{
+		Lookups: reg.NewCounterVec("koorde_lookups_total",
+			"Total number of lookups originated by this node, by result.", "result"),
+		LookupHops: reg.NewHistogram("koorde_lookup_hops",
+			"Hop count (x-koorde-hop) of the lookup FindSuccessor requests served by this node.", HopBuckets),
+		StoredResources: reg.NewGauge("koorde_stored_resources",
+			"Number of resources in the local storage, updated at every stabilization pass."),
+		PoolConnections: reg.NewGauge("koorde_pool_connections",
+			"Number of connections held by the client pool, updated at every stabilization pass."),
+		NoPredStores: reg.NewCounterVec("koorde_stores_without_predecessor_total",
+			"Total number of stores received while the predecessor was unknown on a multi-node ring, by action (repair, reject).", "action"),
+	}
+}
+
+// ObserveLookup counts a lookup originated by this node that ended with err.
+func (m *Metrics) ObserveLookup(err error) {
+	if m == nil {
+		return
+	}
+	if err != nil {
+		m.Lookups.Inc(ResultError)
+		return
+	}
+	m.Lookups.Inc(ResultSuccess)
+}
+
+// SetStoredResources sets the number of resources in the local storage.
+func (m *Metrics) SetStoredResources(n int) {
+	if m == nil {
+		return
+	}
+	m.StoredResources.Set(float64(n))
+}
+
+// SetPoolConnections sets the number of connections held by the pool.
+func (m *Metrics) SetPoolConnections(n int) {
+	if m == nil {
+		return
+	}
+	m.PoolConnections.Set(float64(n))
+}
+
+// ObserveStoreWithoutPredecessor counts a store received while the
+// predecessor was unknown, handled with action (repair or reject).
+func (m *Metrics) ObserveStoreWithoutPredecessor(action string) {
+	if m == nil {
+		return
+	}
+	m.NoPredStores.Inc(action)
+}
+
+// ServerInterceptor observes the hop count of the lookup FindSuccessor
+// requests (see lookuptrace) served by this node. It must be chained after
+// the lookuptrace interceptor, e.g. with server.WithUnaryInterceptors.
+func (m *Metrics) ServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if m != nil && strings.Contains(info.FullMethod, "FindSuccessor") && lookuptrace.IsLookup(ctx) {
+			m.LookupHops.Observe(float64(ctxutil.Hops(ctx)))
+		}
+		return handler(ctx, req)
+	}
+}
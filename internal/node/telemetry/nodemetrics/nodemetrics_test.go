@@ -0,0 +1,99 @@
+package nodemetrics_test
+
+import (
+	"KoordeDHT/internal/client"
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/telemetry/nodemetrics"
+	"KoordeDHT/internal/node/testring"
+	"KoordeDHT/internal/telemetry/metrics"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sample restituisce il valore della riga name dell'output di reg.
+func sample(t *testing.T, reg *metrics.Registry, name string) float64 {
+	t.Helper()
+	var buf bytes.Buffer
+	reg.Write(&buf)
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if v, ok := strings.CutPrefix(line, name+" "); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				t.Fatalf("sample %s: %v", name, err)
+			}
+			return f
+		}
+	}
+	t.Fatalf("sample %s not found in:\n%s", name, buf.String())
+	return 0
+}
+
+func TestLookupMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	m := nodemetrics.New(reg)
+	r := testring.New(t, 6,
+		testring.WithNodeOptions(logicnode.WithMetrics(m)),
+		testring.WithServerOptions(server.WithUnaryInterceptors(m.ServerInterceptor())))
+	before := m.Lookups.Value(nodemetrics.ResultSuccess)
+
+	api, conn, err := client.Connect(r.Members[0].Addr)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	const lookups = 32
+	for i := 0; i < lookups; i++ {
+		id := r.Space.NewIdFromString(fmt.Sprintf("key-%d", i))
+		if _, _, err := client.Lookup(ctx, api, id.ToHexString(true)); err != nil {
+			t.Fatalf("Lookup: %v", err)
+		}
+	}
+
+	if got := m.Lookups.Value(nodemetrics.ResultSuccess) - before; got < lookups {
+		t.Errorf("successful lookups counted = %v, want at least %d", got, lookups)
+	}
+	// Il conteggio degli hop deve propagarsi lungo il percorso: alcune
+	// richieste arrivano oltre il primo hop
+	total := sample(t, reg, "koorde_lookup_hops_count")
+	firstHop := sample(t, reg, `koorde_lookup_hops_bucket{le="1"}`)
+	if total == 0 || firstHop == total {
+		t.Errorf("lookup hops: %v requests, %v within the first hop; want some beyond it", total, firstHop)
+	}
+}
+
+func TestStorageAndPoolGauges(t *testing.T) {
+	reg := metrics.NewRegistry()
+	m := nodemetrics.New(reg)
+	r := testring.New(t, 1, testring.WithNodeOptions(logicnode.WithMetrics(m)))
+	n := r.Members[0].Node
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		k := fmt.Sprintf("key-%d", i)
+		if err := n.Put(ctx, domain.Resource{Key: r.Space.NewIdFromString(k), RawKey: k, Value: k}); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	// I gauge sono aggiornati a ogni passata di stabilizzazione
+	deadline := time.Now().Add(2 * time.Second)
+	for m.StoredResources.Value() != 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := sample(t, reg, "koorde_stored_resources"); got != 3 {
+		t.Errorf("koorde_stored_resources = %v, want 3", got)
+	}
+	if got := sample(t, reg, "koorde_pool_connections"); got != 0 {
+		t.Errorf("koorde_pool_connections = %v, want 0 on a single-node ring", got)
+	}
+}
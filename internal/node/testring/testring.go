@@ -0,0 +1,455 @@
+// Package testring provides an in-process Koorde ring for tests.
+//
+// Every member runs the real logicnode.Node behind a real gRPC server
+// bound to a loopback listener, so tests exercise exactly the code paths
+// used in production (routing, stabilization, storage transfers) without
+// containers or external processes.
+package testring
+
+import (
+	"KoordeDHT/internal/domain"
+	"KoordeDHT/internal/logger"
+	"KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/logicnode"
+	"KoordeDHT/internal/node/routingtable"
+	"KoordeDHT/internal/node/server"
+	"KoordeDHT/internal/node/storage"
+	"context"
+	"net"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Member is a single node of the test ring.
+type Member struct {
+	Node    *logicnode.Node
+	Addr    string
+	RT      *routingtable.RoutingTable // lets tests tamper with the pointers
+	server  *server.Server
+	pool    *client.Pool
+	store   storage.Store
+	stop    context.CancelFunc
+	stopped bool
+	paused  bool
+}
+
+// Ring is a set of Koorde nodes joined into a single overlay.
+type Ring struct {
+	Space     domain.Space
+	Members   []*Member // sorted by node ID once the ring is built
+	Observers []*Member // observer nodes (see AddObserver), not part of Members
+
+	t    testing.TB
+	opts options
+}
+
+type options struct {
+	bits           int
+	degree         int
+	succListSize   int
+	interval       time.Duration
+	failureTimeout time.Duration
+	lgr            logger.Logger
+	nodeOpts       []logicnode.Option
+	rtOpts         []routingtable.Option
+	poolOpts       []client.Option
+	storageOpts    []storage.Option
+	serverOpts     []server.Option
+	grpcOpts       []grpc.ServerOption
+}
+
+// Option customizes the ring built by New.
+type Option func(*options)
+
+// WithSpace overrides the identifier space parameters (default 16 bits,
+// degree 2, successor list of 4).
+func WithSpace(bits, degree, succListSize int) Option {
+	return func(o *options) {
+		o.bits = bits
+		o.degree = degree
+		o.succListSize = succListSize
+	}
+}
+
+// WithInterval sets the period used by all stabilization loops.
+func WithInterval(d time.Duration) Option {
+	return func(o *options) { o.interval = d }
+}
+
+// WithLogger sets the logger used by every member; each member logs
+// through l.WithNode(self). By default nothing is logged.
+func WithLogger(l logger.Logger) Option {
+	return func(o *options) { o.lgr = l }
+}
+
+// WithNodeOptions appends options passed to every logicnode.New call.
+func WithNodeOptions(opts ...logicnode.Option) Option {
+	return func(o *options) { o.nodeOpts = append(o.nodeOpts, opts...) }
+}
+
+// WithRoutingTableOptions appends options passed to every routing table.
+func WithRoutingTableOptions(opts ...routingtable.Option) Option {
+	return func(o *options) { o.rtOpts = append(o.rtOpts, opts...) }
+}
+
+// WithPoolOptions appends options passed to every client pool.
+func WithPoolOptions(opts ...client.Option) Option {
+	return func(o *options) { o.poolOpts = append(o.poolOpts, opts...) }
+}
+
+// WithStorageOptions appends options passed to every member's storage.
+func WithStorageOptions(opts ...storage.Option) Option {
+	return func(o *options) { o.storageOpts = append(o.storageOpts, opts...) }
+}
+
+// WithServerOptions appends options passed to every server.New call.
+func WithServerOptions(opts ...server.Option) Option {
+	return func(o *options) { o.serverOpts = append(o.serverOpts, opts...) }
+}
+
+// WithGRPCOptions appends raw gRPC server options for every member.
+func WithGRPCOptions(opts ...grpc.ServerOption) Option {
+	return func(o *options) { o.grpcOpts = append(o.grpcOpts, opts...) }
+}
+
+// New builds a ring of the given size, waits until the successor and
+// predecessor pointers of every member are consistent, and registers a
+// cleanup that shuts every member down when the test ends.
+func New(t testing.TB, size int, opts ...Option) *Ring {
+	t.Helper()
+	o := options{
+		bits:           16,
+		degree:         2,
+		succListSize:   4,
+		interval:       20 * time.Millisecond,
+		failureTimeout: 500 * time.Millisecond,
+		lgr:            &logger.NopLogger{},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	sp, err := domain.NewSpace(o.bits, o.degree, o.succListSize)
+	if err != nil {
+		t.Fatalf("testring: invalid space: %v", err)
+	}
+	r := &Ring{Space: sp, t: t, opts: o}
+	t.Cleanup(r.Close)
+
+	for i := 0; i < size; i++ {
+		r.Add()
+	}
+	r.WaitStable()
+	return r
+}
+
+// Add starts a new member and joins it to the ring (or creates the ring
+// if it is the first member). The new member is returned; Members is
+// re-sorted by ID.
+func (r *Ring) Add() *Member {
+	r.t.Helper()
+	m := r.start(false, nil, nil)
+	r.Members = append(r.Members, m)
+	r.sortMembers()
+	return m
+}
+
+// AddWithID is like Add, but the new member takes id instead of the ID
+// hashed from its address, so that a test can place it in a chosen arc of
+// the ring.
+func (r *Ring) AddWithID(id domain.ID) *Member {
+	r.t.Helper()
+	m := r.start(false, nil, &restart{id: func(string) domain.ID { return id }})
+	r.Members = append(r.Members, m)
+	r.sortMembers()
+	return m
+}
+
+// AddVNodes starts a physical node running v virtual nodes
+// (logicnode.WithVNodeGroup), each with its own listener, ID and routing
+// table but all sharing one client pool and one storage, and joins them to
+// the ring. The virtual nodes are returned in creation order and are
+// ordinary Members for WaitStable and Owner.
+func (r *Ring) AddVNodes(v int) []*Member {
+	r.t.Helper()
+	ph := &physical{group: logicnode.NewVNodeGroup()}
+	out := make([]*Member, 0, v)
+	for i := 0; i < v; i++ {
+		out = append(out, r.start(false, ph, nil))
+	}
+	r.Members = append(r.Members, out...)
+	r.sortMembers()
+	return out
+}
+
+func (r *Ring) sortMembers() {
+	sort.Slice(r.Members, func(i, j int) bool {
+		return r.Members[i].Node.Self().ID.Cmp(r.Members[j].Node.Self().ID) < 0
+	})
+}
+
+// AddObserver starts a node in observer mode (logicnode.WithObserver) and
+// joins it through the first live member. Observers never own keys, so
+// they are kept in Observers and ignored by WaitStable and Owner.
+func (r *Ring) AddObserver() *Member {
+	r.t.Helper()
+	m := r.start(true, nil, nil)
+	r.Observers = append(r.Observers, m)
+	return m
+}
+
+// physical is the state shared by the virtual nodes of a physical node
+// (see AddVNodes).
+type physical struct {
+	addr  string // address of the first virtual node
+	pool  *client.Pool
+	store storage.Store
+	group *logicnode.VNodeGroup
+	next  int // index of the next virtual node
+}
+
+// Restart simulates the restart of m on a new address (e.g. a reassigned
+// IP): m is killed and a new member is started on another port with the
+// storage of m, under the ID that id returns for the new address, and
+// joined to the ring. The new member replaces m in Members.
+func (r *Ring) Restart(m *Member, id func(addr string) domain.ID) *Member {
+	r.t.Helper()
+	r.Kill(m)
+	_ = m.pool.Close()
+	r.Members = slices.DeleteFunc(r.Members, func(x *Member) bool { return x == m })
+	nm := r.start(false, nil, &restart{store: m.store, id: id})
+	r.Members = append(r.Members, nm)
+	r.sortMembers()
+	return nm
+}
+
+// restart is the state a restarted member keeps (see Restart). AddWithID
+// uses it with a nil store, to choose the ID only.
+type restart struct {
+	store storage.Store
+	id    func(addr string) domain.ID
+}
+
+// start launches a node with its server and maintenance loops and joins it
+// to the ring, or creates the ring if no member is live. With ph the node
+// is the next virtual node of that physical node; with rs it is a restarted
+// member.
+func (r *Ring) start(observer bool, ph *physical, rs *restart) *Member {
+	r.t.Helper()
+	// A port whose address hashes to the ID of a live node is replaced
+	// with another one: the space of the tests is small
+	var (
+		lis  net.Listener
+		self *domain.Node
+	)
+	for attempt := 0; ; attempt++ {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			r.t.Fatalf("testring: listen: %v", err)
+		}
+		addr := l.Addr().String()
+		nd := &domain.Node{ID: r.Space.NewIdFromString(addr), Addr: addr}
+		if ph != nil && ph.next > 0 {
+			nd.ID = logicnode.VNodeID(r.Space, ph.addr, ph.next)
+		}
+		if rs != nil {
+			nd.ID = rs.id(addr)
+		}
+		if !slices.ContainsFunc(slices.Concat(r.Members, r.Observers), func(m *Member) bool {
+			return m.Node.Self().ID.Equal(nd.ID)
+		}) {
+			lis, self = l, nd
+			break
+		}
+		_ = l.Close()
+		if attempt == 10 {
+			r.t.Fatalf("testring: ID collision for %s", addr)
+		}
+	}
+	addr := self.Addr
+
+	lgr := r.opts.lgr.WithNode(*self)
+	rtOpts := append([]routingtable.Option{routingtable.WithLogger(lgr.Named("routingtable"))}, r.opts.rtOpts...)
+	rt := routingtable.New(self, r.Space, rtOpts...)
+	var (
+		cp *client.Pool
+		st storage.Store
+	)
+	nodeOpts := append([]logicnode.Option{logicnode.WithLogger(lgr)}, r.opts.nodeOpts...)
+	if ph != nil && ph.next > 0 {
+		cp, st = ph.pool, ph.store
+	} else {
+		poolAddr := addr
+		if ph != nil {
+			poolAddr = "" // the virtual nodes dial each other
+		}
+		cp = client.New(self.ID, poolAddr, r.opts.failureTimeout,
+			append([]client.Option{client.WithLogger(lgr.Named("clientpool"))}, r.opts.poolOpts...)...)
+		st = storage.NewMemoryStorage(lgr.Named("storage"), r.opts.storageOpts...)
+	}
+	if rs != nil && rs.store != nil {
+		st = rs.store
+	}
+	if ph != nil {
+		if ph.next == 0 {
+			ph.addr, ph.pool, ph.store = addr, cp, st
+		}
+		ph.next++
+		nodeOpts = append(nodeOpts, logicnode.WithVNodeGroup(ph.group))
+	}
+	n := logicnode.New(rt, cp, st, append(nodeOpts, logicnode.WithObserver(observer))...)
+
+	srv, err := server.New(lis, n, r.opts.grpcOpts,
+		append([]server.Option{server.WithLogger(lgr.Named("server"))}, r.opts.serverOpts...)...)
+	if err != nil {
+		r.t.Fatalf("testring: server: %v", err)
+	}
+	go func() { _ = srv.Start() }()
+
+	if len(r.liveMembers()) == 0 {
+		n.CreateNewDHT()
+	} else if err := n.Join([]string{r.liveMembers()[0].Addr}); err != nil {
+		r.t.Fatalf("testring: join %s: %v", addr, err)
+	}
+	srv.SetServing(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n.StartStabilizers(ctx, r.opts.interval, r.opts.interval, r.opts.interval)
+
+	return &Member{Node: n, Addr: addr, RT: rt, server: srv, pool: cp, store: st, stop: cancel}
+}
+
+// Kill abruptly stops a member (no graceful leave), simulating a crash.
+func (r *Ring) Kill(m *Member) {
+	if m.stopped {
+		return
+	}
+	m.stopped = true
+	m.stop()
+	m.server.Stop()
+}
+
+// StopStabilizers halts the maintenance loops of every live member, so
+// tests can tamper with routing state without it being repaired.
+func (r *Ring) StopStabilizers() {
+	for _, m := range r.Members {
+		m.stop()
+	}
+}
+
+// Pause stops the maintenance loops of a single member, which keeps
+// answering RPCs with its (increasingly stale) routing state, simulating a
+// node that was suspended for a while. WaitStable ignores the pointers of
+// paused members.
+func (r *Ring) Pause(m *Member) {
+	m.stop()
+	m.paused = true
+}
+
+// Resume restarts the maintenance loops of a paused member with the given
+// interval (0 = the ring interval), as a node does after a rejoin.
+func (r *Ring) Resume(m *Member, interval time.Duration) {
+	if interval <= 0 {
+		interval = r.opts.interval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stop = cancel
+	m.paused = false
+	m.Node.StartStabilizers(ctx, interval, interval, interval)
+}
+
+// WaitStable blocks until every live member has its ring neighbours as
+// predecessor and successors (as many as its successor list holds, only
+// the first one while a member is paused) and a non-empty de Bruijn window
+// (if de Bruijn routing is enabled), failing the test after a generous
+// timeout.
+func (r *Ring) WaitStable() {
+	r.t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if r.stable() {
+			return
+		}
+		time.Sleep(r.opts.interval)
+	}
+	r.t.Fatalf("testring: ring did not stabilize within timeout")
+}
+
+func (r *Ring) stable() bool {
+	live := r.liveMembers()
+	// the successor lists of the others are rebuilt from the (stale) one of
+	// a paused member, so with a paused member only successors are checked
+	depth := len(live) - 1
+	for _, m := range live {
+		if m.paused {
+			depth = 1
+		}
+	}
+	for i, m := range live {
+		if m.paused {
+			continue
+		}
+		prev := live[(i-1+len(live))%len(live)].Node.Self()
+		succ := m.Node.SuccessorList()
+		if len(succ) == 0 {
+			return false
+		}
+		for j := range min(depth, m.Node.SuccessorListSize()) {
+			next := live[(i+1+j)%len(live)].Node.Self()
+			if j >= len(succ) || !succ[j].ID.Equal(next.ID) {
+				return false
+			}
+		}
+		pred := m.Node.Predecessor()
+		if pred == nil || !pred.ID.Equal(prev.ID) {
+			return false
+		}
+		if m.Node.DeBruijnEnabled() && len(m.Node.DeBruijnList()) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Owner returns the live member responsible for id, i.e. the first member
+// whose ID is >= id on the ring.
+func (r *Ring) Owner(id domain.ID) *Member {
+	live := r.liveMembers()
+	for _, m := range live {
+		if id.Cmp(m.Node.Self().ID) <= 0 {
+			return m
+		}
+	}
+	return live[0]
+}
+
+// Live returns the members that have not been killed, sorted by ID.
+func (r *Ring) Live() []*Member {
+	return r.liveMembers()
+}
+
+func (r *Ring) liveMembers() []*Member {
+	out := make([]*Member, 0, len(r.Members))
+	for _, m := range r.Members {
+		if !m.stopped {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// Close shuts down every member without a graceful leave (there is no
+// point in handing keys over when the whole ring goes away). It is
+// registered as a test cleanup by New.
+func (r *Ring) Close() {
+	all := slices.Concat(r.Members, r.Observers)
+	for _, m := range all {
+		r.Kill(m)
+	}
+	for _, m := range all {
+		_ = m.pool.Close()
+	}
+}
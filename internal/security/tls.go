@@ -0,0 +1,128 @@
+// Package security builds the TLS configurations of the gRPC transport
+// shared by nodes and clients.
+//
+// Three modes are supported:
+//   - none: plaintext connections (the default, for lab deployments).
+//   - tls:  servers present a certificate; clients verify it against the
+//     CA file (or the system roots if no CA file is given).
+//   - mtls: as tls, and in addition servers require a client certificate
+//     signed by the CA, so nodes also present their certificate when
+//     dialing other nodes.
+package security
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// Supported modes.
+const (
+	ModeNone = "none"
+	ModeTLS  = "tls"
+	ModeMTLS = "mtls"
+)
+
+// Config selects the transport security mode and the PEM files it uses.
+type Config struct {
+	Mode     string `yaml:"mode"`
+	CertFile string `yaml:"certFile"` // certificate presented by this process
+	KeyFile  string `yaml:"keyFile"`  // private key of CertFile
+	CAFile   string `yaml:"caFile"`   // CA used to verify the peers
+}
+
+// Enabled reports whether cfg selects TLS (tls or mtls).
+func (cfg Config) Enabled() bool {
+	return cfg.Mode == ModeTLS || cfg.Mode == ModeMTLS
+}
+
+// Validate checks that the mode is supported and that the files it needs
+// are set. With mode tls, a process that only dials (e.g. a client) may
+// leave CertFile and KeyFile empty; set server to require them.
+func (cfg Config) Validate(server bool) error {
+	switch cfg.Mode {
+	case "", ModeNone:
+		return nil
+	case ModeTLS:
+		if server && (cfg.CertFile == "" || cfg.KeyFile == "") {
+			return fmt.Errorf("security: mode tls requires certFile and keyFile")
+		}
+	case ModeMTLS:
+		if cfg.CertFile == "" || cfg.KeyFile == "" || cfg.CAFile == "" {
+			return fmt.Errorf("security: mode mtls requires certFile, keyFile and caFile")
+		}
+	default:
+		return fmt.Errorf("security: invalid mode %q (must be none, tls or mtls)", cfg.Mode)
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return fmt.Errorf("security: certFile and keyFile must be set together")
+	}
+	return nil
+}
+
+// ServerTLS returns the TLS configuration of a gRPC server, or nil if
+// cfg does not enable TLS.
+func ServerTLS(cfg Config) (*tls.Config, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	if err := cfg.Validate(true); err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("security: load server certificate: %w", err)
+	}
+	tc := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if cfg.Mode == ModeMTLS {
+		pool, err := loadCA(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tc.ClientCAs = pool
+		tc.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tc, nil
+}
+
+// ClientTLS returns the TLS configuration used to dial servers, or nil if
+// cfg does not enable TLS. The certificate, if set, is presented to the
+// servers (required by mtls).
+func ClientTLS(cfg Config) (*tls.Config, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	if err := cfg.Validate(false); err != nil {
+		return nil, err
+	}
+	tc := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		pool, err := loadCA(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tc.RootCAs = pool
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("security: load client certificate: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	return tc, nil
+}
+
+// loadCA reads a PEM bundle of CA certificates.
+func loadCA(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("security: read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("security: no certificate found in CA file %s", path)
+	}
+	return pool, nil
+}
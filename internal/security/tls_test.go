@@ -0,0 +1,180 @@
+package security_test
+
+import (
+	"KoordeDHT/internal/client"
+	nodeclient "KoordeDHT/internal/node/client"
+	"KoordeDHT/internal/node/testring"
+	"KoordeDHT/internal/security"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// pki scrive in dir una CA e due certificati firmati da essa (nodo e
+// client), validi per 127.0.0.1, e restituisce i percorsi dei file.
+type pki struct {
+	ca, nodeCert, nodeKey, clientCert, clientKey string
+}
+
+func newPKI(t *testing.T) pki {
+	t.Helper()
+	dir := t.TempDir()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "koorde-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate(ca): %v", err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	p := pki{ca: filepath.Join(dir, "ca.pem")}
+	writePEM(t, p.ca, "CERTIFICATE", caDER)
+	issue := func(name string, serial int64) (string, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("GenerateKey: %v", err)
+		}
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatalf("CreateCertificate(%s): %v", name, err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatalf("MarshalECPrivateKey: %v", err)
+		}
+		cert, keyPath := filepath.Join(dir, name+".pem"), filepath.Join(dir, name+"-key.pem")
+		writePEM(t, cert, "CERTIFICATE", der)
+		writePEM(t, keyPath, "EC PRIVATE KEY", keyDER)
+		return cert, keyPath
+	}
+	p.nodeCert, p.nodeKey = issue("node", 2)
+	p.clientCert, p.clientKey = issue("client", 3)
+	return p
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     security.Config
+		server  bool
+		wantErr bool
+	}{
+		{name: "none", cfg: security.Config{Mode: security.ModeNone}, server: true},
+		{name: "empty mode", cfg: security.Config{}, server: true},
+		{name: "tls server", cfg: security.Config{Mode: security.ModeTLS, CertFile: "c", KeyFile: "k"}, server: true},
+		{name: "tls server without cert", cfg: security.Config{Mode: security.ModeTLS}, server: true, wantErr: true},
+		// Un client TLS può non presentare alcun certificato
+		{name: "tls client without cert", cfg: security.Config{Mode: security.ModeTLS, CAFile: "ca"}},
+		{name: "tls cert without key", cfg: security.Config{Mode: security.ModeTLS, CertFile: "c"}, wantErr: true},
+		{name: "mtls", cfg: security.Config{Mode: security.ModeMTLS, CertFile: "c", KeyFile: "k", CAFile: "ca"}, server: true},
+		{name: "mtls without ca", cfg: security.Config{Mode: security.ModeMTLS, CertFile: "c", KeyFile: "k"}, wantErr: true},
+		{name: "invalid mode", cfg: security.Config{Mode: "ssl"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(tt.server); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate: got %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMutualTLSRing(t *testing.T) {
+	p := newPKI(t)
+	nodeCfg := security.Config{Mode: security.ModeMTLS, CertFile: p.nodeCert, KeyFile: p.nodeKey, CAFile: p.ca}
+	serverTLS, err := security.ServerTLS(nodeCfg)
+	if err != nil {
+		t.Fatalf("ServerTLS: %v", err)
+	}
+	poolTLS, err := security.ClientTLS(nodeCfg)
+	if err != nil {
+		t.Fatalf("ClientTLS: %v", err)
+	}
+
+	// I nodi si parlano solo via mTLS: se il pool non presentasse il
+	// certificato l'anello non si formerebbe
+	r := testring.New(t, 4,
+		testring.WithGRPCOptions(grpc.Creds(credentials.NewTLS(serverTLS))),
+		testring.WithPoolOptions(nodeclient.WithTLS(poolTLS)),
+	)
+	r.WaitStable()
+
+	tests := []struct {
+		name    string
+		cfg     *security.Config // nil = connessione in chiaro
+		wantErr bool
+	}{
+		{name: "client certificate", cfg: &security.Config{Mode: security.ModeMTLS, CertFile: p.clientCert, KeyFile: p.clientKey, CAFile: p.ca}},
+		{name: "no client certificate", cfg: &security.Config{Mode: security.ModeTLS, CAFile: p.ca}, wantErr: true},
+		{name: "plaintext", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []client.ConnectOption
+			if tt.cfg != nil {
+				tc, err := security.ClientTLS(*tt.cfg)
+				if err != nil {
+					t.Fatalf("ClientTLS: %v", err)
+				}
+				opts = append(opts, client.WithTLS(tc))
+			}
+			api, conn, err := client.Connect(r.Members[0].Addr, opts...)
+			if err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			// La lookup attraversa l'anello, quindi verifica anche il pool
+			id := r.Space.NewIdFromString("tls-key")
+			node, _, err := client.Lookup(ctx, api, id.ToHexString(true))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Lookup: got %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && node.GetAddr() != r.Owner(id).Addr {
+				t.Fatalf("Lookup: got %s, want %s", node.GetAddr(), r.Owner(id).Addr)
+			}
+		})
+	}
+}
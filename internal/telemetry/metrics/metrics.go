@@ -0,0 +1,274 @@
+// Package metrics is a minimal metrics registry that exposes counters,
+// gauges and histograms in the Prometheus text exposition format, so that
+// the nodes and the load generators can be scraped (and plotted, e.g. in
+// Grafana) while running, without pulling in a full metrics client library.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds (in seconds) of the latency
+// histograms, from 1ms to 10s.
+var DefaultLatencyBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type collector interface {
+	name() string
+	write(w io.Writer)
+}
+
+// Registry holds a set of metrics and renders them on /metrics.
+type Registry struct {
+	mu         sync.Mutex
+	collectors map[string]collector
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.collectors[c.name()]; dup {
+		panic(fmt.Sprintf("metrics: duplicate metric %q", c.name()))
+	}
+	r.collectors[c.name()] = c
+}
+
+// Write renders every registered metric, sorted by name, in the
+// Prometheus text format.
+func (r *Registry) Write(w io.Writer) {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.collectors))
+	for n := range r.collectors {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	cs := make([]collector, len(names))
+	for i, n := range names {
+		cs[i] = r.collectors[n]
+	}
+	r.mu.Unlock()
+
+	for _, c := range cs {
+		c.write(w)
+	}
+}
+
+// Handler returns an http.Handler that serves the registry.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.Write(w)
+	})
+}
+
+// Serve starts an HTTP server on addr that exposes the registry on
+// /metrics. The listener is opened synchronously, so address errors are
+// reported to the caller; the returned server must be closed on shutdown.
+func Serve(addr string, r *Registry) (*http.Server, net.Addr, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("metrics: listen on %s: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r.Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() { _ = srv.Serve(lis) }()
+	return srv, lis.Addr(), nil
+}
+
+// -------------------------------
+// Counter
+// -------------------------------
+
+// CounterVec is a monotonically increasing counter partitioned by the
+// value of a single label (an empty label name yields a plain counter).
+type CounterVec struct {
+	n, help, label string
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec registers a counter with the given label. Use label ""
+// for a counter without labels.
+func (r *Registry) NewCounterVec(name, help, label string) *CounterVec {
+	c := &CounterVec{n: name, help: help, label: label, values: make(map[string]float64)}
+	r.register(c)
+	return c
+}
+
+// Inc increments by one the counter for the given label value.
+func (c *CounterVec) Inc(value string) {
+	c.Add(value, 1)
+}
+
+// Add increments the counter for the given label value by delta (>= 0).
+func (c *CounterVec) Add(value string, delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.mu.Lock()
+	c.values[value] += delta
+	c.mu.Unlock()
+}
+
+// Value returns the current count for the given label value.
+func (c *CounterVec) Value(value string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[value]
+}
+
+func (c *CounterVec) name() string { return c.n }
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	writeHeader(w, c.n, c.help, "counter")
+	if c.label == "" {
+		fmt.Fprintf(w, "%s %s\n", c.n, formatFloat(c.values[""]))
+		return
+	}
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{%s=%q} %s\n", c.n, c.label, k, formatFloat(c.values[k]))
+	}
+}
+
+// -------------------------------
+// Gauge
+// -------------------------------
+
+// Gauge is a single value that can go up and down.
+type Gauge struct {
+	n, help string
+
+	mu    sync.Mutex
+	value float64
+}
+
+// NewGauge registers a gauge without labels.
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	g := &Gauge{n: name, help: help}
+	r.register(g)
+	return g
+}
+
+// Set sets the gauge to v.
+func (g *Gauge) Set(v float64) {
+	g.mu.Lock()
+	g.value = v
+	g.mu.Unlock()
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.value
+}
+
+func (g *Gauge) name() string { return g.n }
+
+func (g *Gauge) write(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	writeHeader(w, g.n, g.help, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.n, formatFloat(g.value))
+}
+
+// -------------------------------
+// Histogram
+// -------------------------------
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	n, help string
+	bounds  []float64 // sorted upper bounds, +Inf excluded
+
+	mu     sync.Mutex
+	counts []uint64 // per bucket (not cumulative), last entry is +Inf
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram registers a histogram with the given bucket upper bounds
+// (DefaultLatencyBuckets if nil).
+func (r *Registry) NewHistogram(name, help string, buckets []float64) *Histogram {
+	if buckets == nil {
+		buckets = DefaultLatencyBuckets
+	}
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	h := &Histogram{n: name, help: help, bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+	r.register(h)
+	return h
+}
+
+// Observe records a single value.
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v) // first bound >= v
+	h.mu.Lock()
+	h.counts[i]++
+	h.sum += v
+	h.count++
+	h.mu.Unlock()
+}
+
+// ObserveDuration records d in seconds.
+func (h *Histogram) ObserveDuration(d time.Duration) {
+	h.Observe(d.Seconds())
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+func (h *Histogram) name() string { return h.n }
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	writeHeader(w, h.n, h.help, "histogram")
+	var cum uint64
+	for i, b := range h.bounds {
+		cum += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{le=%q} %d\n", h.n, formatFloat(b), cum)
+	}
+	cum += h.counts[len(h.bounds)]
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.n, cum)
+	fmt.Fprintf(w, "%s_sum %s\n", h.n, formatFloat(h.sum))
+	fmt.Fprintf(w, "%s_count %d\n", h.n, h.count)
+}
+
+func writeHeader(w io.Writer, name, help, typ string) {
+	help = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, +1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}